@@ -90,6 +90,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
 		Output: output,
 	}))
+	e.Use(sqleMiddleware.Tracing())
 	e.HideBanner = true
 	e.HidePort = true
 
@@ -177,6 +178,11 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1Router.POST(fmt.Sprintf("%s/connection", dmsV1.InternalDBServiceRouterGroup), v1.CheckInstanceIsConnectable, sqleMiddleware.OpGlobalAllowed())
 		v1Router.GET("/database_driver_options", v1.GetDatabaseDriverOptions)
 		v1Router.GET("/database_driver_logos", v1.GetDatabaseDriverLogos)
+
+		// user delegation
+		v1Router.GET("/user_delegations", v1.GetUserDelegations)
+		v1Router.POST("/user_delegations", v1.CreateUserDelegation)
+		v1Router.DELETE("/user_delegations/:delegation_id/", v1.DeleteUserDelegation)
 	}
 
 	// project admin and global manage router
@@ -203,6 +209,17 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1OpProjectRouter.POST("/:project_name/workflow_templates", v1.CreateWorkflowTemplateV1)
 		v1OpProjectRouter.PATCH("/:project_name/workflow_templates/:workflow_template_id/", v1.UpdateWorkflowTemplateByIdV1)
 		v1OpProjectRouter.DELETE("/:project_name/workflow_templates/:workflow_template_id/", v1.DeleteWorkflowTemplateV1)
+		v1OpProjectRouter.PATCH("/:project_name/workflow_step_slas", v1.UpdateWorkflowStepSLAs)
+		v1OpProjectRouter.PATCH("/:project_name/workflow_duty_policy", v1.UpdateWorkflowDutyPolicy)
+		v1OpProjectRouter.POST("/:project_name/workflow_template_route_rules", v1.CreateWorkflowTemplateRouteRule)
+		v1OpProjectRouter.PATCH("/:project_name/workflow_template_route_rules/:rule_id/", v1.UpdateWorkflowTemplateRouteRule)
+		v1OpProjectRouter.DELETE("/:project_name/workflow_template_route_rules/:rule_id/", v1.DeleteWorkflowTemplateRouteRule)
+		v1OpProjectRouter.PUT("/:project_name/scoring_policy", v1.UpdateScoringPolicy)
+
+		// sql query
+		v1OpProjectRouter.POST("/:project_name/sql_query_masking_rules", v1.CreateSQLQueryMaskingRule)
+		v1OpProjectRouter.PATCH("/:project_name/sql_query_masking_rules/:rule_id/", v1.UpdateSQLQueryMaskingRule)
+		v1OpProjectRouter.DELETE("/:project_name/sql_query_masking_rules/:rule_id/", v1.DeleteSQLQueryMaskingRule)
 
 		// report push
 		v1OpProjectRouter.PUT("/:project_name/report_push_configs/:report_push_config_id/", v1.UpdateReportPushConfig)
@@ -276,6 +293,9 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 
 		// sql manager
 		v1ProjectOpRouter.PATCH("/:project_name/sql_manages/batch", v1.BatchUpdateSqlManage)
+		v1ProjectOpRouter.POST("/:project_name/sql_manages/triage_rules", v1.CreateSqlManageTriageRule)
+		v1ProjectOpRouter.PATCH("/:project_name/sql_manages/triage_rules/:rule_id/", v1.UpdateSqlManageTriageRule)
+		v1ProjectOpRouter.DELETE("/:project_name/sql_manages/triage_rules/:rule_id/", v1.DeleteSqlManageTriageRule)
 
 		// sql audit record
 		v1ProjectOpRouter.POST("/:project_name/sql_audit_records", v1.CreateSQLAuditRecord)
@@ -298,6 +318,15 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1ProjectOpRouter.POST("/:project_name/database_comparison/execute_comparison", v1.ExecuteDatabaseComparison)
 		v1ProjectOpRouter.POST("/:project_name/database_comparison/comparison_statements", v1.GetComparisonStatement)
 		v1ProjectOpRouter.POST("/:project_name/database_comparison/modify_sql_statements", v1.GenDatabaseDiffModifySQLs)
+
+		// schema drift
+		v1ProjectOpRouter.POST("/:project_name/instances/:instance_name/schema_baseline", v1.CreateSchemaBaseline)
+		v1ProjectOpRouter.PATCH("/:project_name/instances/:instance_name/execution_throttle", v1.UpdateExecutionThrottle)
+
+		// team knowledge
+		v1ProjectOpRouter.POST("/:project_name/knowledge_bases", v1.CreateTeamKnowledge)
+		v1ProjectOpRouter.PATCH("/:project_name/knowledge_bases/:knowledge_id/", v1.UpdateTeamKnowledge)
+		v1ProjectOpRouter.DELETE("/:project_name/knowledge_bases/:knowledge_id/", v1.DeleteTeamKnowledge)
 	}
 
 	// project member router
@@ -324,6 +353,12 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1ProjectViewRouter.GET("/:project_name/instances/:instance_name/rules", v1.GetInstanceRules)
 		v1ProjectViewRouter.GET("/:project_name/instances/:instance_name/schemas/:schema_name/tables", v1.ListTableBySchema)
 		v1ProjectViewRouter.GET("/:project_name/instances/:instance_name/schemas/:schema_name/tables/:table_name/metadata", v1.GetTableMetadata)
+		v1ProjectViewRouter.GET("/:project_name/instances/:instance_name/schema_baseline", v1.GetSchemaBaseline)
+		v1ProjectViewRouter.GET("/:project_name/instances/:instance_name/schema_drift_findings", v1.GetSchemaDriftFindings)
+		v1ProjectViewRouter.GET("/:project_name/instances/:instance_name/execution_throttle", v1.GetExecutionThrottle)
+		v1ProjectViewRouter.POST("/:project_name/instances/:instance_name/query", v1.QueryInstance)
+		v1ProjectViewRouter.GET("/:project_name/sql_query_records", v1.GetSQLQueryRecords)
+		v1ProjectViewRouter.GET("/:project_name/sql_query_masking_rules", v1.GetSQLQueryMaskingRules)
 
 		// rule template
 		v1ProjectViewRouter.GET("/:project_name/rule_templates/:rule_template_name/", v1.GetProjectRuleTemplate)
@@ -335,6 +370,12 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1ProjectViewRouter.GET("/:project_name/workflow_templates", v1.GetWorkflowTemplateList)
 		v1ProjectViewRouter.GET("/:project_name/workflow_templates/:workflow_template_id/", v1.GetWorkflowTemplateByIdV1)
 		v1ProjectViewRouter.GET("/:project_name/workflow_template", v1.GetWorkflowTemplate)
+		v1ProjectViewRouter.GET("/:project_name/workflow_step_slas", v1.GetWorkflowStepSLAs)
+		v1ProjectViewRouter.GET("/:project_name/workflow_sla_breaches", v1.GetWorkflowSLABreaches)
+		v1ProjectViewRouter.GET("/:project_name/workflow_duty_policy", v1.GetWorkflowDutyPolicy)
+		v1ProjectViewRouter.GET("/:project_name/workflow_template_route_rules", v1.GetWorkflowTemplateRouteRules)
+		v1ProjectViewRouter.POST("/:project_name/workflow_template_route_rules/evaluate", v1.EvaluateWorkflowTemplateRoute)
+		v1ProjectViewRouter.GET("/:project_name/scoring_policy", v1.GetScoringPolicy)
 		v1ProjectViewRouter.GET("/:project_name/workflows/:workflow_name/", DeprecatedBy(apiV2))
 		v1ProjectViewRouter.GET("/:project_name/workflows", v1.GetWorkflowsV1)
 		v1ProjectViewRouter.GET("/:project_name/workflows/:workflow_name/tasks", DeprecatedBy(apiV2))
@@ -364,8 +405,13 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1ProjectViewRouter.GET("/:project_name/sql_manages", v1.GetSqlManageList)
 		v1ProjectViewRouter.GET("/:project_name/sql_manages/exports", DeprecatedBy(apiV2))
 		v1ProjectViewRouter.GET("/:project_name/sql_manages/rule_tips", v1.GetSqlManageRuleTips)
+		v1ProjectViewRouter.GET("/:project_name/sql_manages/index_advice", v1.GetSqlManageIndexAdvice)
+		v1ProjectViewRouter.GET("/:project_name/sql_manages/triage_rules", v1.GetSqlManageTriageRules)
+		v1ProjectViewRouter.GET("/:project_name/sql_manages/triage_records", v1.GetSqlManageTriageRecords)
 		v1ProjectViewRouter.GET("/:project_name/sql_manages/:sql_manage_id/sql_analysis", v1.GetSqlManageSqlAnalysisV1)
 		v1ProjectViewRouter.GET("/:project_name/sql_manages/:sql_manage_id/sql_analysis_chart", v1.GetSqlManageSqlAnalysisChartV1)
+		v1ProjectViewRouter.GET("/:project_name/sql_manages/:sql_manage_id/execute_plans", v1.GetSqlManageExecutePlans)
+		v1ProjectViewRouter.GET("/:project_name/sql_manages/:sql_manage_id/execute_plans/diff", v1.GetSqlManageExecutePlanDiff)
 		v1ProjectViewRouter.POST("/:project_name/sql_manages/send", v1.SendSqlManage)
 		v1ProjectViewRouter.GET("/:project_name/sql_manages/abnormal_audit_plan_instance", v1.GetAbnormalInstanceAuditPlans)
 
@@ -401,6 +447,11 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1ProjectViewRouter.GET("/:project_name/sql_versions/:sql_version_id/sql_version_stages/:sql_version_stage_id/associate_workflows", v1.GetWorkflowsThatCanBeAssociatedToVersion)
 
 		v1ProjectViewRouter.GET("/:project_name/blacklist", v1.GetBlacklist)
+
+		// knowledge base
+		v1ProjectViewRouter.GET("/:project_name/knowledge_bases", v1.GetProjectKnowledgeBaseList)
+		v1ProjectViewRouter.GET("/:project_name/knowledge_bases/:knowledge_id/", v1.GetProjectKnowledgeBase)
+		v1ProjectViewRouter.GET("/:project_name/knowledge_bases/:knowledge_id/versions", v1.GetProjectKnowledgeBaseVersions)
 	}
 
 	// project member router
@@ -481,6 +532,8 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1Router.GET("/knowledge_bases", v1.GetKnowledgeBaseList)
 		v1Router.GET("/knowledge_bases/tags", v1.GetKnowledgeBaseTagList)
 		v1Router.GET("/knowledge_bases/graph", v1.GetKnowledgeGraph)
+		v1Router.GET("/knowledge_bases/:knowledge_id/", v1.GetKnowledgeBase)
+		v1Router.GET("/knowledge_bases/:knowledge_id/versions", v1.GetKnowledgeBaseVersions)
 
 		// rule
 		v1Router.GET("/rules", v1.GetRules)
@@ -504,6 +557,10 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1Router.GET("/tasks/audits/:task_id/audit_file", v1.DownloadAuditFile)
 		v1Router.GET("/tasks/audits/:task_id/sql_content", v1.GetAuditTaskSQLContent)
 		v1Router.PATCH("/tasks/audits/:task_id/sqls/:number", v1.UpdateAuditTaskSQLs)
+		v1Router.GET("/tasks/audits/:task_id/throttle_events", v1.GetTaskThrottleEvents)
+		v1Router.GET("/tasks/audits/:task_id/sqls/:number/chunked_execution", v1.GetSQLChunkedExecutionV1)
+		v1Router.PATCH("/tasks/audits/:task_id/sqls/:number/chunked_execution", v1.UpdateSQLChunkedExecutionV1)
+		v1Router.POST("/tasks/audits/:task_id/sqls/:number/chunked_execution/pause", v1.PauseSQLChunkedExecutionV1)
 		v1Router.GET("/tasks/audits/:task_id/sqls/:number/analysis", v1.GetTaskAnalysisData)
 		v2Router.GET("/tasks/audits/:task_id/sqls/:number/analysis", v2.GetTaskAnalysisData)
 		v1Router.POST("/projects/:project_name/task_groups", v1.CreateAuditTasksGroupV1)
@@ -524,6 +581,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 
 		// configurations
 		v1Router.GET("/configurations/drivers", v1.GetDrivers)
+		v1Router.GET("/configurations/plugins", v1.GetPluginStatusesV1, sqleMiddleware.ViewGlobalAllowed())
 		v2Router.GET("/configurations/drivers", v2.GetDrivers)
 		v1Router.GET("/configurations/workflows/schedule/default_option", v1.GetScheduledTaskDefaultOptionV1)
 		v1Router.GET("/configurations/ssh_key", v1.GetSSHPublicKey, sqleMiddleware.ViewGlobalAllowed())
@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+
+	dmsV1 "github.com/actiontech/dms/pkg/dms-common/api/dms/v1"
+	"github.com/labstack/echo/v4"
+)
+
+type UpdateSQLChunkedExecutionReqV1 struct {
+	Enabled          bool   `json:"enabled"`
+	ChunkSize        uint64 `json:"chunk_size" example:"1000"`
+	SleepMillisecond uint64 `json:"sleep_millisecond" example:"500"`
+}
+
+// UpdateSQLChunkedExecutionV1
+// @Summary 设置工单中 UPDATE/DELETE 是否按主键范围分批执行
+// @Description set whether the UPDATE/DELETE is executed in primary key range batches, each batch is committed and the execution sleeps between batches
+// @Tags task
+// @Id updateSQLChunkedExecutionV1
+// @Accept json
+// @Security ApiKeyAuth
+// @Param task_id path string true "task id"
+// @Param number path string true "sql number"
+// @Param req body v1.UpdateSQLChunkedExecutionReqV1 true "chunked execution settings"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/tasks/audits/{task_id}/sqls/{number}/chunked_execution [patch]
+func UpdateSQLChunkedExecutionV1(c echo.Context) error {
+	req := new(UpdateSQLChunkedExecutionReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	task, taskSql, err := getTaskSQLForChunkedExecution(c, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeViewOthersWorkflow})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	err = server.SetExecuteSQLChunked(task, taskSql, req.Enabled, req.ChunkSize, req.SleepMillisecond)
+	return controller.JSONBaseErrorReq(c, err)
+}
+
+type GetSQLChunkedExecutionResV1 struct {
+	controller.BaseRes
+	Data *SQLChunkedExecutionResV1 `json:"data"`
+}
+
+type SQLChunkedExecutionResV1 struct {
+	Enabled          bool                          `json:"enabled"`
+	ChunkSize        uint64                        `json:"chunk_size"`
+	SleepMillisecond uint64                        `json:"sleep_millisecond"`
+	Status           string                        `json:"status" enums:"initialized,running,pausing,paused,finished,failed,not_eligible"`
+	Error            string                        `json:"error"`
+	Table            string                        `json:"table"`
+	PKColumn         string                        `json:"pk_column"`
+	MinPK            string                        `json:"min_pk"`
+	MaxPK            string                        `json:"max_pk"`
+	LastPK           string                        `json:"last_pk"`
+	BatchCount       uint64                        `json:"batch_count"`
+	RowAffects       int64                         `json:"row_affects"`
+	StartedAt        *time.Time                    `json:"started_at,omitempty"`
+	FinishedAt       *time.Time                    `json:"finished_at,omitempty"`
+	Batches          []*SQLChunkedExecutionBatchV1 `json:"batches"`
+}
+
+type SQLChunkedExecutionBatchV1 struct {
+	BatchNo    uint64    `json:"batch_no"`
+	StartPK    string    `json:"start_pk"`
+	EndPK      string    `json:"end_pk"`
+	RowAffects int64     `json:"row_affects"`
+	DurationMs int64     `json:"duration_ms"`
+	ExecutedAt time.Time `json:"executed_at"`
+}
+
+// GetSQLChunkedExecutionV1
+// @Summary 获取工单中 SQL 分批执行的进度
+// @Description get the progress of the chunked execution of the SQL, including the completed batches
+// @Tags task
+// @Id getSQLChunkedExecutionV1
+// @Security ApiKeyAuth
+// @Param task_id path string true "task id"
+// @Param number path string true "sql number"
+// @Success 200 {object} v1.GetSQLChunkedExecutionResV1
+// @router /v1/tasks/audits/{task_id}/sqls/{number}/chunked_execution [get]
+func GetSQLChunkedExecutionV1(c echo.Context) error {
+	_, taskSql, err := getTaskSQLForChunkedExecution(c, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeViewOthersWorkflow})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	ce, exist, err := s.GetChunkedExecutionByExecuteSQLId(taskSql.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := &SQLChunkedExecutionResV1{
+		Enabled: taskSql.ExecMode == model.SQLExecModeChunked,
+		Batches: []*SQLChunkedExecutionBatchV1{},
+	}
+	if exist {
+		batches, err := s.GetChunkedExecutionBatches(ce.ID)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		data.ChunkSize = ce.ChunkSize
+		data.SleepMillisecond = ce.SleepMillisecond
+		data.Status = ce.Status
+		data.Error = ce.Error
+		data.Table = ce.Table
+		data.PKColumn = ce.PKColumn
+		data.MinPK = ce.MinPK
+		data.MaxPK = ce.MaxPK
+		data.LastPK = ce.LastPK
+		data.BatchCount = ce.BatchCount
+		data.RowAffects = ce.RowAffects
+		data.StartedAt = ce.StartedAt
+		data.FinishedAt = ce.FinishedAt
+		for _, b := range batches {
+			data.Batches = append(data.Batches, &SQLChunkedExecutionBatchV1{
+				BatchNo:    b.BatchNo,
+				StartPK:    b.StartPK,
+				EndPK:      b.EndPK,
+				RowAffects: b.RowAffects,
+				DurationMs: b.DurationMs,
+				ExecutedAt: b.ExecutedAt,
+			})
+		}
+	}
+	return c.JSON(http.StatusOK, &GetSQLChunkedExecutionResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+// PauseSQLChunkedExecutionV1
+// @Summary 暂停工单中 SQL 的分批执行
+// @Description pause the chunked execution after the current batch, re-execute the SQL to resume from the last completed primary key range
+// @Tags task
+// @Id pauseSQLChunkedExecutionV1
+// @Security ApiKeyAuth
+// @Param task_id path string true "task id"
+// @Param number path string true "sql number"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/tasks/audits/{task_id}/sqls/{number}/chunked_execution/pause [post]
+func PauseSQLChunkedExecutionV1(c echo.Context) error {
+	_, taskSql, err := getTaskSQLForChunkedExecution(c, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeExecuteWorkflow})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, server.PauseChunkedExecution(taskSql))
+}
+
+func getTaskSQLForChunkedExecution(c echo.Context, ops []dmsV1.OpPermissionType) (*model.Task, *model.ExecuteSQL, error) {
+	taskId := c.Param("task_id")
+	task, err := getTaskById(c.Request().Context(), taskId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkCurrentUserCanOpTask(c, task, ops); err != nil {
+		return nil, nil, err
+	}
+	taskSql, exist, err := model.GetStorage().GetTaskSQLByNumber(taskId, c.Param("number"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exist {
+		return nil, nil, errors.New(errors.DataNotExist, fmt.Errorf("sql number not found"))
+	}
+	return task, taskSql, nil
+}
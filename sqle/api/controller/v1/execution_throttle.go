@@ -0,0 +1,217 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver/mysql"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/labstack/echo/v4"
+)
+
+type ExecutionThrottleV1 struct {
+	Enabled              bool   `json:"enabled"`
+	DiscoverReplicas     bool   `json:"discover_replicas"`
+	MaxReplicationLagSec uint64 `json:"max_replication_lag_sec" example:"10"`
+	MaxThreadsRunning    uint64 `json:"max_threads_running" example:"50"`
+	CheckIntervalSecond  uint64 `json:"check_interval_second" example:"5"`
+	TimeoutSecond        uint64 `json:"timeout_second" example:"1800"`
+}
+
+type GetExecutionThrottleResV1 struct {
+	controller.BaseRes
+	Data *ExecutionThrottleResV1 `json:"data"`
+}
+
+type ExecutionThrottleResV1 struct {
+	ExecutionThrottleV1
+	// Replicas are the replicas in the DSNs without password, e.g. "repl@10.10.10.10:3306"
+	Replicas []string `json:"replicas"`
+}
+
+// GetExecutionThrottle
+// @Summary 获取实例的上线限流配置
+// @Description get the execution throttle settings of the instance
+// @Id getExecutionThrottleV1
+// @Tags instance
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param instance_name path string true "instance name"
+// @Success 200 {object} v1.GetExecutionThrottleResV1
+// @router /v1/projects/{project_name}/instances/{instance_name}/execution_throttle [get]
+func GetExecutionThrottle(c echo.Context) error {
+	_, instance, _, err := getSchemaDriftInstance(c, false)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	throttle, exist, err := model.GetStorage().GetExecutionThrottleByInstanceId(instance.GetIDStr())
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := &ExecutionThrottleResV1{
+		ExecutionThrottleV1: ExecutionThrottleV1{
+			CheckIntervalSecond: model.DefaultThrottleCheckIntervalSecond,
+			TimeoutSecond:       model.DefaultThrottleTimeoutSecond,
+		},
+		Replicas: []string{},
+	}
+	if exist {
+		data.ExecutionThrottleV1 = ExecutionThrottleV1{
+			Enabled:              throttle.Enabled,
+			DiscoverReplicas:     throttle.DiscoverReplicas,
+			MaxReplicationLagSec: throttle.MaxReplicationLagSec,
+			MaxThreadsRunning:    throttle.MaxThreadsRunning,
+			CheckIntervalSecond:  throttle.CheckIntervalSecond,
+			TimeoutSecond:        throttle.TimeoutSecond,
+		}
+		for _, dsn := range throttle.ReplicaDSNs {
+			data.Replicas = append(data.Replicas, maskReplicaDSN(dsn))
+		}
+	}
+	return c.JSON(http.StatusOK, &GetExecutionThrottleResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+// maskReplicaDSN turns "user:password@tcp(host:port)/" into "user@host:port".
+func maskReplicaDSN(dsn string) string {
+	idx := strings.LastIndex(dsn, "@")
+	if idx < 0 {
+		return dsn
+	}
+	user := strings.SplitN(dsn[:idx], ":", 2)[0]
+	addr := dsn[idx+1:]
+	if start, end := strings.Index(addr, "("), strings.Index(addr, ")"); start >= 0 && end > start {
+		addr = addr[start+1 : end]
+	}
+	return user + "@" + addr
+}
+
+type UpdateExecutionThrottleReqV1 struct {
+	ExecutionThrottleV1
+	// ReplicaDSNs replaces the replicas if it is not null, e.g. ["repl:password@tcp(10.10.10.10:3306)/"]
+	ReplicaDSNs *[]string `json:"replica_dsns"`
+}
+
+// UpdateExecutionThrottle
+// @Summary 更新实例的上线限流配置
+// @Description update the execution throttle settings of the instance, the execution of the task pauses between statements and batches while the replication lag or Threads_running exceeds the limits
+// @Accept json
+// @Id updateExecutionThrottleV1
+// @Tags instance
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param instance_name path string true "instance name"
+// @Param throttle body v1.UpdateExecutionThrottleReqV1 true "update execution throttle request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/instances/{instance_name}/execution_throttle [patch]
+func UpdateExecutionThrottle(c echo.Context) error {
+	req := new(UpdateExecutionThrottleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, instance, _, err := getSchemaDriftInstance(c, true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if instance.DbType != driverV2.DriverTypeMySQL {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("execution throttle only supports %s", driverV2.DriverTypeMySQL)))
+	}
+
+	s := model.GetStorage()
+	throttle, exist, err := s.GetExecutionThrottleByInstanceId(instance.GetIDStr())
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		throttle = &model.ExecutionThrottle{
+			ProjectId:  projectUid,
+			InstanceId: instance.GetIDStr(),
+		}
+	}
+	if req.ReplicaDSNs != nil {
+		throttle.ReplicaDSNs = []string{}
+		for _, dsn := range *req.ReplicaDSNs {
+			if dsn = strings.TrimSpace(dsn); dsn == "" {
+				continue
+			}
+			if _, err := mysql.ParseReplicaDSN(dsn); err != nil {
+				return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("%v, the dsn should be like \"user:password@tcp(host:port)/\"", err)))
+			}
+			throttle.ReplicaDSNs = append(throttle.ReplicaDSNs, dsn)
+		}
+	}
+	throttle.Enabled = req.Enabled
+	throttle.DiscoverReplicas = req.DiscoverReplicas
+	throttle.MaxReplicationLagSec = req.MaxReplicationLagSec
+	throttle.MaxThreadsRunning = req.MaxThreadsRunning
+	throttle.CheckIntervalSecond = req.CheckIntervalSecond
+	if throttle.CheckIntervalSecond == 0 {
+		throttle.CheckIntervalSecond = model.DefaultThrottleCheckIntervalSecond
+	}
+	throttle.TimeoutSecond = req.TimeoutSecond
+	if throttle.TimeoutSecond == 0 {
+		throttle.TimeoutSecond = model.DefaultThrottleTimeoutSecond
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(throttle))
+}
+
+type GetTaskThrottleEventsResV1 struct {
+	controller.BaseRes
+	Data []*TaskThrottleEventResV1 `json:"data"`
+}
+
+type TaskThrottleEventResV1 struct {
+	ExecuteSQLId      uint      `json:"execute_sql_id"`
+	Event             string    `json:"event" enums:"throttled,resumed,aborted"`
+	Reason            string    `json:"reason"`
+	ThreadsRunning    int64     `json:"threads_running"`
+	ReplicationLagSec int64     `json:"replication_lag_sec"`
+	WaitedSecond      int64     `json:"waited_second"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// GetTaskThrottleEvents
+// @Summary 获取工单任务上线过程中的限流事件
+// @Description get the events that the execution of the task is throttled, resumed or aborted
+// @Tags task
+// @Id getTaskThrottleEventsV1
+// @Security ApiKeyAuth
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetTaskThrottleEventsResV1
+// @router /v1/tasks/audits/{task_id}/throttle_events [get]
+func GetTaskThrottleEvents(c echo.Context) error {
+	task, err := getTaskById(c.Request().Context(), c.Param("task_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := CheckCurrentUserCanViewTask(c, task); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	events, err := model.GetStorage().GetTaskThrottleEvents(task.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*TaskThrottleEventResV1, 0, len(events))
+	for _, event := range events {
+		data = append(data, &TaskThrottleEventResV1{
+			ExecuteSQLId:      event.ExecuteSQLId,
+			Event:             event.Event,
+			Reason:            event.Reason,
+			ThreadsRunning:    event.ThreadsRunning,
+			ReplicationLagSec: event.ReplicationLagSec,
+			WaitedSecond:      event.WaitedSecond,
+			CreatedAt:         event.CreatedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetTaskThrottleEventsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"time"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/labstack/echo/v4"
 )
@@ -19,12 +21,19 @@ type GetKnowledgeBaseListRes struct {
 }
 
 type KnowledgeBase struct {
-	ID          uint   `json:"id"`          // 知识库ID
-	RuleName    string `json:"rule_name"`   // 规则名称
-	Title       string `json:"title"`       // 标题
-	Description string `json:"description"` // 描述
-	Content     string `json:"content"`     // 内容
-	Tags        []*Tag `json:"tags"`        // 标签
+	ID              uint       `json:"id"`                // 知识库ID
+	RuleName        string     `json:"rule_name"`         // 规则名称
+	DBType          string     `json:"db_type"`           // 规则的数据库类型
+	IsCustomRule    bool       `json:"is_custom_rule"`    // 是否为自定义规则的知识
+	IsTeamKnowledge bool       `json:"is_team_knowledge"` // 是否为项目的团队知识
+	Title           string     `json:"title"`             // 标题
+	Description     string     `json:"description"`       // 描述
+	Content         string     `json:"content"`           // 内容
+	Examples        string     `json:"examples"`          // 示例
+	Language        string     `json:"language"`          // 语言，为空时适用于所有语言
+	Version         uint32     `json:"version"`           // 版本
+	UpdatedAt       *time.Time `json:"updated_at"`        // 更新时间
+	Tags            []*Tag     `json:"tags"`              // 标签
 }
 
 type Tag struct {
@@ -35,7 +44,7 @@ type Tag struct {
 
 // GetKnowledgeBaseList
 // @Summary 获取知识库列表
-// @Description get knowledge base list
+// @Description search the global knowledge base by the full-text index, the results are sorted by relevance if keywords are specified
 // @Id getKnowledgeBaseList
 // @Tags knowledge_base
 // @Param keywords query string false "keywords"
@@ -110,3 +119,169 @@ type GraphResponse struct {
 	Nodes []*NodeResponse `json:"nodes"` // 节点集合
 	Edges []*EdgeResponse `json:"edges"` // 边集合
 }
+
+type KnowledgeRelatedRule struct {
+	RuleName     string `json:"rule_name"`
+	DBType       string `json:"db_type"`
+	IsCustomRule bool   `json:"is_custom_rule"`
+	SharedTags   int    `json:"shared_tags"` // 与知识相同的标签数
+}
+
+type KnowledgeBaseDetail struct {
+	KnowledgeBase
+	EditorName   string                  `json:"editor_name"`   // 最后编辑人，为空时由系统生成
+	RelatedRules []*KnowledgeRelatedRule `json:"related_rules"` // 与知识有相同标签的规则
+}
+
+type GetKnowledgeBaseResV1 struct {
+	controller.BaseRes
+	Data *KnowledgeBaseDetail `json:"data"`
+}
+
+// GetKnowledgeBase
+// @Summary 获取知识详情
+// @Description get the detail of global knowledge, including the rules related by tags
+// @Id getKnowledgeBaseV1
+// @Tags knowledge_base
+// @Param knowledge_id path string true "knowledge id"
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetKnowledgeBaseResV1
+// @router /v1/knowledge_bases/{knowledge_id}/ [get]
+func GetKnowledgeBase(c echo.Context) error {
+	return getKnowledgeBase(c)
+}
+
+type KnowledgeBaseVersion struct {
+	Version     uint32     `json:"version"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Content     string     `json:"content"`
+	Examples    string     `json:"examples"`
+	EditorName  string     `json:"editor_name"`
+	CreatedAt   *time.Time `json:"created_at"`
+}
+
+type GetKnowledgeBaseVersionsResV1 struct {
+	controller.BaseRes
+	Data []*KnowledgeBaseVersion `json:"data"`
+}
+
+// GetKnowledgeBaseVersions
+// @Summary 获取知识的历史版本
+// @Description get the versions of global knowledge, the latest version is the first
+// @Id getKnowledgeBaseVersionsV1
+// @Tags knowledge_base
+// @Param knowledge_id path string true "knowledge id"
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetKnowledgeBaseVersionsResV1
+// @router /v1/knowledge_bases/{knowledge_id}/versions [get]
+func GetKnowledgeBaseVersions(c echo.Context) error {
+	return getKnowledgeBaseVersions(c)
+}
+
+// GetProjectKnowledgeBaseList
+// @Summary 获取项目的知识库列表
+// @Description search the global knowledge and the team knowledge of the project by the full-text index
+// @Id getProjectKnowledgeBaseListV1
+// @Tags knowledge_base
+// @Param project_name path string true "project name"
+// @Param keywords query string false "keywords"
+// @Param tags query []string false "tags"
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetKnowledgeBaseListRes
+// @router /v1/projects/{project_name}/knowledge_bases [get]
+func GetProjectKnowledgeBaseList(c echo.Context) error {
+	return getProjectKnowledgeBaseList(c)
+}
+
+// GetProjectKnowledgeBase
+// @Summary 获取项目的知识详情
+// @Description get the detail of global knowledge or the team knowledge of the project
+// @Id getProjectKnowledgeBaseV1
+// @Tags knowledge_base
+// @Param project_name path string true "project name"
+// @Param knowledge_id path string true "knowledge id"
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetKnowledgeBaseResV1
+// @router /v1/projects/{project_name}/knowledge_bases/{knowledge_id}/ [get]
+func GetProjectKnowledgeBase(c echo.Context) error {
+	return getProjectKnowledgeBase(c)
+}
+
+// GetProjectKnowledgeBaseVersions
+// @Summary 获取项目的知识的历史版本
+// @Description get the versions of global knowledge or the team knowledge of the project, the latest version is the first
+// @Id getProjectKnowledgeBaseVersionsV1
+// @Tags knowledge_base
+// @Param project_name path string true "project name"
+// @Param knowledge_id path string true "knowledge id"
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetKnowledgeBaseVersionsResV1
+// @router /v1/projects/{project_name}/knowledge_bases/{knowledge_id}/versions [get]
+func GetProjectKnowledgeBaseVersions(c echo.Context) error {
+	return getProjectKnowledgeBaseVersions(c)
+}
+
+type CreateTeamKnowledgeReqV1 struct {
+	Title       string   `json:"title" valid:"required,max=255"`
+	Description string   `json:"description"`
+	Content     string   `json:"content" valid:"required"`
+	Examples    string   `json:"examples"`
+	Language    string   `json:"language"` // 为空时适用于所有语言
+	Tags        []string `json:"tags"`     // 标签，与规则知识有相同标签时关联到规则
+}
+
+// CreateTeamKnowledge
+// @Summary 添加项目的团队知识
+// @Description create team-specific knowledge of the project, it is related to the rules by tags
+// @Accept json
+// @Id createTeamKnowledgeV1
+// @Tags knowledge_base
+// @Param project_name path string true "project name"
+// @Param knowledge body v1.CreateTeamKnowledgeReqV1 true "create team knowledge request"
+// @Security ApiKeyAuth
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/knowledge_bases [post]
+func CreateTeamKnowledge(c echo.Context) error {
+	return createTeamKnowledge(c)
+}
+
+type UpdateTeamKnowledgeReqV1 struct {
+	Title       *string  `json:"title" valid:"omitempty,max=255"`
+	Description *string  `json:"description"`
+	Content     *string  `json:"content"`
+	Examples    *string  `json:"examples"`
+	Tags        []string `json:"tags"` // 为空时不修改标签
+}
+
+// UpdateTeamKnowledge
+// @Summary 更新项目的团队知识
+// @Description update team knowledge of the project, a new version is recorded
+// @Accept json
+// @Id updateTeamKnowledgeV1
+// @Tags knowledge_base
+// @Param project_name path string true "project name"
+// @Param knowledge_id path string true "knowledge id"
+// @Param knowledge body v1.UpdateTeamKnowledgeReqV1 true "update team knowledge request"
+// @Security ApiKeyAuth
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/knowledge_bases/{knowledge_id}/ [patch]
+func UpdateTeamKnowledge(c echo.Context) error {
+	return updateTeamKnowledge(c)
+}
+
+// DeleteTeamKnowledge
+// @Summary 删除项目的团队知识
+// @Description delete team knowledge of the project
+// @Id deleteTeamKnowledgeV1
+// @Tags knowledge_base
+// @Param project_name path string true "project name"
+// @Param knowledge_id path string true "knowledge id"
+// @Security ApiKeyAuth
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/knowledge_bases/{knowledge_id}/ [delete]
+func DeleteTeamKnowledge(c echo.Context) error {
+	return deleteTeamKnowledge(c)
+}
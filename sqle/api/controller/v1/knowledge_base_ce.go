@@ -4,22 +4,502 @@
 package v1
 
 import (
-	e "errors"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
 
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/knowledge_base"
+
 	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
 )
 
-var ErrCommunityEditionDoesNotSupportKnowledgeBase = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support knowledge base"))
+var errKnowledgeNotExist = errors.New(errors.DataNotExist, fmt.Errorf("knowledge is not exist"))
 
 func getKnowledgeBaseList(c echo.Context) error {
-	return ErrCommunityEditionDoesNotSupportKnowledgeBase
+	return searchKnowledgeBase(c, "")
+}
+
+func getProjectKnowledgeBaseList(c echo.Context) error {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return searchKnowledgeBase(c, model.ProjectUID(projectUid))
+}
+
+func searchKnowledgeBase(c echo.Context, projectUid model.ProjectUID) error {
+	req := new(GetKnowledgeBaseListReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	lang := locale.Bundle.GetLangTagFromCtx(c.Request().Context())
+	knowledge, count, err := model.GetStorage().SearchKnowledge(&model.KnowledgeFilter{
+		Keywords:  req.KeyWords,
+		Tags:      req.Tags,
+		ProjectId: projectUid,
+		Language:  lang.String(),
+		PageIndex: req.PageIndex,
+		PageSize:  req.PageSize,
+	})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*KnowledgeBase, 0, len(knowledge))
+	for _, k := range knowledge {
+		data = append(data, convertKnowledgeToRes(k))
+	}
+	return c.JSON(http.StatusOK, &GetKnowledgeBaseListRes{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: count,
+	})
 }
 
 func getKnowledgeBaseTagList(c echo.Context) error {
-	return ErrCommunityEditionDoesNotSupportKnowledgeBase
+	tags, err := model.GetStorage().GetAllTags()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*Tag, 0, len(tags))
+	for _, tag := range tags {
+		t := &Tag{ID: tag.ID, Name: string(tag.Name)}
+		for _, subTag := range tag.SubTag {
+			t.SubTags = append(t.SubTags, &Tag{ID: subTag.ID, Name: string(subTag.Name)})
+		}
+		data = append(data, t)
+	}
+	return c.JSON(http.StatusOK, &GetKnowledgeBaseTagListRes{
+		BaseRes:   controller.NewBaseReq(nil),
+		TotalNums: uint64(len(data)),
+		Data:      data,
+	})
 }
 
 func getKnowledgeGraph(c echo.Context) error {
-	return ErrCommunityEditionDoesNotSupportKnowledgeBase
-}
\ No newline at end of file
+	req := new(GetKnowledgeGraphReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	refs, err := model.GetStorage().GetKnowledgeTagRefs(req.FilterByRuleName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	graph := knowledge_base.BuildTagGraph(refs)
+	data := &GraphResponse{
+		Nodes: make([]*NodeResponse, 0, len(graph.Nodes)),
+		Edges: make([]*EdgeResponse, 0, len(graph.Edges)),
+	}
+	for _, node := range graph.Nodes {
+		data.Nodes = append(data.Nodes, &NodeResponse{ID: node.ID, Name: node.Name, Weight: node.Weight})
+	}
+	for _, edge := range graph.Edges {
+		data.Edges = append(data.Edges, &EdgeResponse{FromID: edge.FromID, ToID: edge.ToID, Weight: edge.Weight})
+	}
+	return c.JSON(http.StatusOK, &GetKnowledgeGraphResp{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func getKnowledgeBase(c echo.Context) error {
+	k, err := getKnowledgeByParam(c, "")
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return responseKnowledgeDetail(c, k)
+}
+
+func getProjectKnowledgeBase(c echo.Context) error {
+	k, err := getProjectKnowledgeByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return responseKnowledgeDetail(c, k)
+}
+
+func responseKnowledgeDetail(c echo.Context, k *model.Knowledge) error {
+	relatedRules, err := getRelatedRules(k.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := &KnowledgeBaseDetail{
+		KnowledgeBase: *convertKnowledgeToRes(k),
+		RelatedRules:  relatedRules,
+	}
+	if k.EditorId != "" {
+		data.EditorName = dms.GetUserNameWithDelTag(k.EditorId)
+	}
+	return c.JSON(http.StatusOK, &GetKnowledgeBaseResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func getKnowledgeBaseVersions(c echo.Context) error {
+	k, err := getKnowledgeByParam(c, "")
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return responseKnowledgeVersions(c, k)
+}
+
+func getProjectKnowledgeBaseVersions(c echo.Context) error {
+	k, err := getProjectKnowledgeByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return responseKnowledgeVersions(c, k)
+}
+
+func responseKnowledgeVersions(c echo.Context, k *model.Knowledge) error {
+	versions, err := model.GetStorage().GetKnowledgeVersions(k.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	editorNames := map[string]string{}
+	data := make([]*KnowledgeBaseVersion, 0, len(versions))
+	for _, v := range versions {
+		if _, ok := editorNames[v.EditorId]; !ok && v.EditorId != "" {
+			editorNames[v.EditorId] = dms.GetUserNameWithDelTag(v.EditorId)
+		}
+		createdAt := v.CreatedAt
+		data = append(data, &KnowledgeBaseVersion{
+			Version:     v.Version,
+			Title:       v.Title,
+			Description: v.Description,
+			Content:     v.Content,
+			Examples:    v.Examples,
+			EditorName:  editorNames[v.EditorId],
+			CreatedAt:   &createdAt,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetKnowledgeBaseVersionsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func createTeamKnowledge(c echo.Context) error {
+	req := new(CreateTeamKnowledgeReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.Language != "" {
+		tag, err := language.Parse(req.Language)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("language %s is invalid", req.Language)))
+		}
+		req.Language = tag.String()
+	}
+	s := model.GetStorage()
+	tags, err := getKnowledgeTags(s, req.Tags)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	k := &model.Knowledge{
+		Title:       req.Title,
+		Description: req.Description,
+		Content:     req.Content,
+		Examples:    req.Examples,
+		Language:    req.Language,
+		ProjectId:   model.ProjectUID(projectUid),
+		EditorId:    controller.GetUserID(c),
+	}
+	if tags == nil {
+		tags = []*model.Tag{}
+	}
+	return controller.JSONBaseErrorReq(c, s.SaveKnowledge(k, tags))
+}
+
+func updateTeamKnowledge(c echo.Context) error {
+	req := new(UpdateTeamKnowledgeReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	k, err := getTeamKnowledgeByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.Title != nil {
+		if *req.Title == "" {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("title is empty")))
+		}
+		k.Title = *req.Title
+	}
+	if req.Description != nil {
+		k.Description = *req.Description
+	}
+	if req.Content != nil {
+		k.Content = *req.Content
+	}
+	if req.Examples != nil {
+		k.Examples = *req.Examples
+	}
+	s := model.GetStorage()
+	tags, err := getKnowledgeTags(s, req.Tags)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	k.EditorId = controller.GetUserID(c)
+	return controller.JSONBaseErrorReq(c, s.SaveKnowledge(k, tags))
+}
+
+func deleteTeamKnowledge(c echo.Context) error {
+	k, err := getTeamKnowledgeByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().DeleteKnowledge(k))
+}
+
+func getRuleKnowledge(c echo.Context) error {
+	rule, exist, err := model.GetStorage().GetRule(c.Param("rule_name"), c.Param("db_type"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("rule is not exist")))
+	}
+	knowledge, err := model.GetStorage().GetKnowledgeOfRule(rule.Name, rule.DBType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	lang := locale.Bundle.GetLangTagFromCtx(c.Request().Context())
+	ruleInfo := rule.I18nRuleInfo.GetRuleInfoByLangTag(lang)
+	return responseRuleKnowledge(c, RuleInfo{Desc: ruleInfo.Desc, Annotation: ruleInfo.Annotation}, knowledge.GetByLangTag(lang))
+}
+
+func getCustomRuleKnowledge(c echo.Context) error {
+	rule, err := getCustomRuleByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	knowledge, err := model.GetStorage().GetKnowledgeOfCustomRule(rule.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	lang := locale.Bundle.GetLangTagFromCtx(c.Request().Context())
+	return responseRuleKnowledge(c, RuleInfo{Desc: rule.Desc, Annotation: rule.Annotation}, knowledge.GetByLangTag(lang))
+}
+
+func responseRuleKnowledge(c echo.Context, ruleInfo RuleInfo, k *model.Knowledge) error {
+	data := RuleKnowledgeResV1{Rule: ruleInfo, Tags: []string{}, RelatedRules: []*KnowledgeRelatedRule{}}
+	if k != nil {
+		relatedRules, err := getRelatedRules(k.ID)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		data.KnowledgeContent = k.Content
+		data.KnowledgeId = k.ID
+		data.KnowledgeExamples = k.Examples
+		data.Version = k.Version
+		data.Tags = k.TagNames()
+		data.RelatedRules = relatedRules
+	}
+	return c.JSON(http.StatusOK, &GetRuleKnowledgeResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func updateRuleKnowledge(c echo.Context) error {
+	req := new(UpdateRuleKnowledgeReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	rule, exist, err := s.GetRule(c.Param("rule_name"), c.Param("db_type"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("rule is not exist")))
+	}
+	knowledge, err := s.GetKnowledgeOfRule(rule.Name, rule.DBType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	tags, err := getKnowledgeTags(s, req.Tags)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	lang := locale.Bundle.GetLangTagFromCtx(c.Request().Context())
+	k, created := getOrNewRuleKnowledge(knowledge, lang)
+	if created {
+		ruleInfo := rule.I18nRuleInfo.GetRuleInfoByLangTag(lang)
+		k.Title = ruleInfo.Desc
+		k.Description = ruleInfo.Annotation
+	}
+	setRuleKnowledge(c, k, req)
+	if created {
+		return controller.JSONBaseErrorReq(c, s.CreateRuleKnowledge(rule, k, tags))
+	}
+	return controller.JSONBaseErrorReq(c, s.SaveKnowledge(k, tags))
+}
+
+func updateCustomRuleKnowledge(c echo.Context) error {
+	req := new(UpdateRuleKnowledgeReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule, err := getCustomRuleByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	knowledge, err := s.GetKnowledgeOfCustomRule(rule.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	tags, err := getKnowledgeTags(s, req.Tags)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	k, created := getOrNewRuleKnowledge(knowledge, locale.Bundle.GetLangTagFromCtx(c.Request().Context()))
+	if created {
+		k.Title = rule.Desc
+		k.Description = rule.Annotation
+	}
+	setRuleKnowledge(c, k, req)
+	if created {
+		return controller.JSONBaseErrorReq(c, s.CreateCustomRuleKnowledge(rule, k, tags))
+	}
+	return controller.JSONBaseErrorReq(c, s.SaveKnowledge(k, tags))
+}
+
+// getOrNewRuleKnowledge 规则的知识按语言编辑，当前语言的知识不存在时新建
+func getOrNewRuleKnowledge(knowledge model.MultiLanguageKnowledge, lang language.Tag) (*model.Knowledge, bool) {
+	for _, k := range knowledge {
+		if k.Language == lang.String() {
+			return k, false
+		}
+	}
+	return &model.Knowledge{Language: lang.String()}, true
+}
+
+func setRuleKnowledge(c echo.Context, k *model.Knowledge, req *UpdateRuleKnowledgeReq) {
+	if req.KnowledgeContent != nil {
+		k.Content = *req.KnowledgeContent
+	}
+	if req.KnowledgeExamples != nil {
+		k.Examples = *req.KnowledgeExamples
+	}
+	k.EditorId = controller.GetUserID(c)
+}
+
+func getCustomRuleByParam(c echo.Context) (*model.CustomRule, error) {
+	rule, exist, err := model.GetStorage().GetCustomRuleByRuleId(c.Param("rule_name"))
+	if err != nil {
+		return nil, err
+	}
+	if !exist || rule.DBType != c.Param("db_type") {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("custom rule is not exist"))
+	}
+	return rule, nil
+}
+
+// getKnowledgeTags 获取或创建标签，names 为 nil 时返回 nil 表示不修改标签
+func getKnowledgeTags(s *model.Storage, names []string) ([]*model.Tag, error) {
+	if names == nil {
+		return nil, nil
+	}
+	if err := knowledge_base.CheckTagNames(names); err != nil {
+		return nil, errors.New(errors.DataInvalid, err)
+	}
+	return knowledge_base.GetOrCreateTags(s, map[string]*model.Tag{}, names)
+}
+
+func getKnowledgeByParam(c echo.Context, projectUid model.ProjectUID) (*model.Knowledge, error) {
+	id, err := strconv.ParseUint(c.Param("knowledge_id"), 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("knowledge id %s is invalid", c.Param("knowledge_id")))
+	}
+	k, exist, err := model.GetStorage().GetKnowledgeById(projectUid, uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errKnowledgeNotExist
+	}
+	return k, nil
+}
+
+func getProjectKnowledgeByParam(c echo.Context) (*model.Knowledge, error) {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return nil, err
+	}
+	return getKnowledgeByParam(c, model.ProjectUID(projectUid))
+}
+
+// getTeamKnowledgeByParam 获取项目的团队知识，全局知识只能通过规则知识的接口修改
+func getTeamKnowledgeByParam(c echo.Context) (*model.Knowledge, error) {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return nil, err
+	}
+	k, err := getKnowledgeByParam(c, model.ProjectUID(projectUid))
+	if err != nil {
+		return nil, err
+	}
+	if k.ProjectId == "" {
+		return nil, errKnowledgeNotExist
+	}
+	return k, nil
+}
+
+func getRelatedRules(knowledgeId uint) ([]*KnowledgeRelatedRule, error) {
+	rules, err := model.GetStorage().GetRelatedRulesOfKnowledge(knowledgeId, knowledge_base.MaxRelatedRules)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]*KnowledgeRelatedRule, 0, len(rules))
+	for _, r := range rules {
+		data = append(data, &KnowledgeRelatedRule{
+			RuleName:     r.RuleName,
+			DBType:       r.DBType,
+			IsCustomRule: r.IsCustomRule,
+			SharedTags:   r.SharedTags,
+		})
+	}
+	return data, nil
+}
+
+func convertKnowledgeToRes(k *model.Knowledge) *KnowledgeBase {
+	updatedAt := k.UpdatedAt
+	res := &KnowledgeBase{
+		ID:              k.ID,
+		IsTeamKnowledge: k.ProjectId != "",
+		Title:           k.Title,
+		Description:     k.Description,
+		Content:         k.Content,
+		Examples:        k.Examples,
+		Language:        k.Language,
+		Version:         k.Version,
+		UpdatedAt:       &updatedAt,
+		Tags:            make([]*Tag, 0, len(k.Tags)),
+	}
+	if len(k.Rules) > 0 {
+		res.RuleName = k.Rules[0].Name
+		res.DBType = k.Rules[0].DBType
+	} else if len(k.CustomRules) > 0 {
+		res.RuleName = k.CustomRules[0].RuleId
+		res.DBType = k.CustomRules[0].DBType
+		res.IsCustomRule = true
+	}
+	for _, tag := range k.Tags {
+		res.Tags = append(res.Tags, &Tag{ID: tag.ID, Name: string(tag.Name)})
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
+
+	"github.com/labstack/echo/v4"
+)
+
+type GetPluginStatusesResV1 struct {
+	controller.BaseRes
+	Data []*PluginStatusResV1 `json:"data"`
+}
+
+type PluginStatusResV1 struct {
+	PluginName       string                   `json:"plugin_name"`
+	FileName         string                   `json:"file_name"`
+	BuiltIn          bool                     `json:"built_in"`
+	ProtocolVersion  int                      `json:"protocol_version"`
+	Status           string                   `json:"status" enums:"running,unhealthy,crashed,draining"`
+	Pid              int                      `json:"pid"`
+	StartedAt        *time.Time               `json:"started_at,omitempty"`
+	LastCheckedAt    *time.Time               `json:"last_checked_at,omitempty"`
+	LastError        string                   `json:"last_error"`
+	InFlightSessions int                      `json:"in_flight_sessions"`
+	RestartCount     int                      `json:"restart_count"`
+	RestartHistory   []*PluginRestartRecordV1 `json:"restart_history"`
+}
+
+type PluginRestartRecordV1 struct {
+	Time    time.Time `json:"time"`
+	Reason  string    `json:"reason" enums:"exited,unhealthy"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error"`
+}
+
+// GetPluginStatusesV1
+// @Summary 获取插件运行状态及重启记录
+// @Description get the status and restart history of the plugins on this sqled node
+// @Id getPluginStatusesV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetPluginStatusesResV1
+// @router /v1/configurations/plugins [get]
+func GetPluginStatusesV1(c echo.Context) error {
+	statuses := driver.GetPluginManager().PluginStatuses()
+	data := make([]*PluginStatusResV1, 0, len(statuses))
+	for _, s := range statuses {
+		res := &PluginStatusResV1{
+			PluginName:       s.PluginName,
+			FileName:         s.FileName,
+			BuiltIn:          s.BuiltIn,
+			ProtocolVersion:  s.ProtocolVersion,
+			Status:           s.Status,
+			Pid:              s.Pid,
+			StartedAt:        timePtrOrNil(s.StartedAt),
+			LastCheckedAt:    timePtrOrNil(s.LastCheckedAt),
+			LastError:        s.LastError,
+			InFlightSessions: s.InFlightSessions,
+			RestartCount:     s.RestartCount,
+			RestartHistory:   make([]*PluginRestartRecordV1, 0, len(s.RestartHistory)),
+		}
+		for _, r := range s.RestartHistory {
+			res.RestartHistory = append(res.RestartHistory, &PluginRestartRecordV1{
+				Time:    r.Time,
+				Reason:  r.Reason,
+				Success: r.Success,
+				Error:   r.Error,
+			})
+		}
+		data = append(data, res)
+	}
+	return c.JSON(http.StatusOK, &GetPluginStatusesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func timePtrOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
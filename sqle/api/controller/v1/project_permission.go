@@ -12,6 +12,7 @@ import (
 	"github.com/actiontech/sqle/sqle/dms"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
 
 	"github.com/labstack/echo/v4"
 )
@@ -34,6 +35,13 @@ func CheckCurrentUserCanOperateWorkflow(c echo.Context, projectUid string, workf
 	if access {
 		return nil
 	}
+	delegator, err := server.GetStepDelegator(userId, workflow)
+	if err != nil {
+		return err
+	}
+	if delegator != "" {
+		return nil
+	}
 
 	if len(ops) > 0 {
 		for _, item := range workflow.Record.InstanceRecords {
@@ -64,6 +72,13 @@ func CheckCurrentUserCanViewWorkflow(c echo.Context, projectUid string, workflow
 	if access {
 		return nil
 	}
+	delegator, err := server.GetStepDelegator(userId, workflow)
+	if err != nil {
+		return err
+	}
+	if delegator != "" {
+		return nil
+	}
 
 	if len(ops) > 0 {
 		for _, item := range workflow.Record.InstanceRecords {
@@ -473,6 +488,17 @@ func CheckCurrentUserCanOpInstances(ctx context.Context, projectUID string, user
 	return true, nil
 }
 
+func CheckCurrentUserCanQueryInstance(ctx context.Context, projectUID string, userId string, instance *model.Instance) (bool, error) {
+	up, err := dms.NewUserPermission(userId, projectUID)
+	if err != nil {
+		return false, fmt.Errorf("get user op permission from dms error: %v", err)
+	}
+	if up.CanOpProject() {
+		return true, nil
+	}
+	return up.CanOpInstanceNoAdmin(instance.GetIDStr(), dmsV1.OpPermissionTypeSQLQuery), nil
+}
+
 func CheckCurrentUserCanCreateWorkflow(ctx context.Context, projectUID string, user *model.User, tasks []*model.Task) (bool, error) {
 	up, err := dms.NewUserPermission(user.GetIDStr(), projectUID)
 	if err != nil {
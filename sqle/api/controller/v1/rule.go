@@ -2165,8 +2165,13 @@ type RuleInfo struct {
 }
 
 type RuleKnowledgeResV1 struct {
-	Rule             RuleInfo `json:"rule"`
-	KnowledgeContent string   `json:"knowledge_content"`
+	Rule              RuleInfo                `json:"rule"`
+	KnowledgeContent  string                  `json:"knowledge_content"`
+	KnowledgeId       uint                    `json:"knowledge_id"`       // 为0时规则还没有知识
+	KnowledgeExamples string                  `json:"knowledge_examples"` // 示例
+	Version           uint32                  `json:"version"`
+	Tags              []string                `json:"tags"`
+	RelatedRules      []*KnowledgeRelatedRule `json:"related_rules"` // 与知识有相同标签的规则
 }
 
 type GetRuleKnowledgeResV1 struct {
@@ -2189,12 +2194,14 @@ func GetRuleKnowledge(c echo.Context) error {
 }
 
 type UpdateRuleKnowledgeReq struct {
-	KnowledgeContent *string `json:"knowledge_content" form:"knowledge_content"`
+	KnowledgeContent  *string  `json:"knowledge_content" form:"knowledge_content"`
+	KnowledgeExamples *string  `json:"knowledge_examples" form:"knowledge_examples"`
+	Tags              []string `json:"tags" form:"tags"` // 为空时不修改标签
 }
 
 // UpdateRuleKnowledgeV1
 // @Summary 更新规则知识库
-// @Description update rule knowledge
+// @Description update the knowledge of rule in the language of the user, a new version is recorded
 // @Id updateRuleKnowledge
 // @Tags rule_template
 // @Security ApiKeyAuth
@@ -2223,7 +2230,7 @@ func GetCustomRuleKnowledge(c echo.Context) error {
 
 // UpdateCustomRuleKnowledgeV1
 // @Summary 更新自定义规则知识库
-// @Description update custom rule knowledge
+// @Description update the knowledge of custom rule in the language of the user, a new version is recorded
 // @Id updateCustomRuleKnowledge
 // @Tags rule_template
 // @Security ApiKeyAuth
@@ -11,7 +11,6 @@ import (
 )
 
 var errCommunityEditionNotSupportCustomRule = errors.New(errors.CustomRuleEditionNotSupported, e.New("custom rule community not supported"))
-var errCommunityEditionNotSupportRuleKnowledge = errors.New(errors.CustomRuleEditionNotSupported, e.New("community do not support rule knowledge"))
 
 func getCustomRules(c echo.Context) error {
 	return errCommunityEditionNotSupportCustomRule
@@ -36,19 +35,3 @@ func getCustomRule(c echo.Context) error {
 func getRuleTypeByDBType(c echo.Context) error {
 	return errCommunityEditionNotSupportCustomRule
 }
-
-func getRuleKnowledge(c echo.Context) error {
-	return errCommunityEditionNotSupportRuleKnowledge
-}
-
-func updateRuleKnowledge(c echo.Context) error {
-	return errCommunityEditionNotSupportRuleKnowledge
-}
-
-func getCustomRuleKnowledge(c echo.Context) error {
-	return errCommunityEditionNotSupportRuleKnowledge
-}
-
-func updateCustomRuleKnowledge(c echo.Context) error {
-	return errCommunityEditionNotSupportRuleKnowledge
-}
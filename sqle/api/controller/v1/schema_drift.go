@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+	"github.com/labstack/echo/v4"
+)
+
+// getSchemaDriftInstance returns the instance in path if the current user can access it.
+func getSchemaDriftInstance(c echo.Context, needOp bool) (projectUid string, instance *model.Instance, userId string, err error) {
+	projectUid, err = dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return "", nil, "", err
+	}
+	instance, exist, err := dms.GetInstanceInProjectByName(c.Request().Context(), projectUid, c.Param("instance_name"))
+	if err != nil {
+		return "", nil, "", err
+	}
+	if !exist {
+		return "", nil, "", ErrInstanceNoAccess
+	}
+	userId = controller.GetUserID(c)
+	checker := CheckCurrentUserCanViewInstances
+	if needOp {
+		checker = CheckCurrentUserCanOpInstances
+	}
+	can, err := checker(c.Request().Context(), projectUid, userId, []*model.Instance{instance})
+	if err != nil {
+		return "", nil, "", err
+	}
+	if !can {
+		return "", nil, "", ErrInstanceNoAccess
+	}
+	return projectUid, instance, userId, nil
+}
+
+type CreateSchemaBaselineReqV1 struct {
+	Source string `json:"source" enums:"snapshot,workflow" valid:"required,oneof=snapshot workflow"`
+	Desc   string `json:"desc" example:"baseline after release v1.2"`
+}
+
+// CreateSchemaBaseline
+// @Summary 设置实例的表结构基线
+// @Description pin the approved schema of the instance, "snapshot" uses the current schema and "workflow" replays the DDL executed by the finished workflows
+// @Accept json
+// @Id createSchemaBaselineV1
+// @Tags schema_drift
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param instance_name path string true "instance name"
+// @Param baseline body v1.CreateSchemaBaselineReqV1 true "create schema baseline request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/instances/{instance_name}/schema_baseline [post]
+func CreateSchemaBaseline(c echo.Context) error {
+	req := new(CreateSchemaBaselineReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, instance, userId, err := getSchemaDriftInstance(c, true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if instance.DbType != driverV2.DriverTypeMySQL {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("schema baseline only supports %s", driverV2.DriverTypeMySQL)))
+	}
+
+	l := log.NewEntry().WithField("action", "create_schema_baseline")
+	switch req.Source {
+	case model.SchemaBaselineSourceSnapshot:
+		_, err = auditplan.CreateSchemaBaselineBySnapshot(l, projectUid, instance.GetIDStr(), userId, req.Desc)
+	case model.SchemaBaselineSourceWorkflow:
+		_, err = auditplan.CreateSchemaBaselineByWorkflow(l, projectUid, instance.GetIDStr(), userId, req.Desc)
+	}
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
+type SchemaBaselineTableResV1 struct {
+	SchemaName     string `json:"schema_name"`
+	TableName      string `json:"table_name"`
+	CreateTableSQL string `json:"create_table_sql"`
+	WorkflowId     string `json:"workflow_id"`
+}
+
+type SchemaBaselineResV1 struct {
+	Id           uint                        `json:"id"`
+	Source       string                      `json:"source" enums:"snapshot,workflow"`
+	Desc         string                      `json:"desc"`
+	CreateUserId string                      `json:"create_user_id"`
+	CreatedAt    time.Time                   `json:"created_at"`
+	Tables       []*SchemaBaselineTableResV1 `json:"tables"`
+}
+
+type GetSchemaBaselineResV1 struct {
+	controller.BaseRes
+	Data *SchemaBaselineResV1 `json:"data"`
+}
+
+// GetSchemaBaseline
+// @Summary 获取实例的表结构基线
+// @Description get the schema baseline of the instance
+// @Id getSchemaBaselineV1
+// @Tags schema_drift
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param instance_name path string true "instance name"
+// @Success 200 {object} v1.GetSchemaBaselineResV1
+// @router /v1/projects/{project_name}/instances/{instance_name}/schema_baseline [get]
+func GetSchemaBaseline(c echo.Context) error {
+	_, instance, _, err := getSchemaDriftInstance(c, false)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	baseline, exist, err := model.GetStorage().GetSchemaBaselineByInstanceId(instance.GetIDStr())
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("schema baseline is not exist")))
+	}
+
+	data := &SchemaBaselineResV1{
+		Id:           baseline.ID,
+		Source:       baseline.Source,
+		Desc:         baseline.Desc,
+		CreateUserId: baseline.CreateUserId,
+		CreatedAt:    baseline.CreatedAt,
+		Tables:       make([]*SchemaBaselineTableResV1, 0, len(baseline.Objects)),
+	}
+	for _, object := range baseline.Objects {
+		data.Tables = append(data.Tables, &SchemaBaselineTableResV1{
+			SchemaName:     object.SchemaName,
+			TableName:      object.TableName,
+			CreateTableSQL: object.CreateTableSQL,
+			WorkflowId:     object.WorkflowId,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSchemaBaselineResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type GetSchemaDriftFindingsReqV1 struct {
+	FilterStatus    string `json:"filter_status" query:"filter_status" enums:"open,resolved" valid:"omitempty,oneof=open resolved"`
+	FilterDriftType string `json:"filter_drift_type" query:"filter_drift_type" enums:"table_added,table_removed,column_added,column_removed,column_changed,index_added,index_removed,index_changed"`
+	PageIndex       uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize        uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type SchemaDriftFindingResV1 struct {
+	Id                 uint       `json:"id"`
+	SchemaName         string     `json:"schema_name"`
+	TableName          string     `json:"table_name"`
+	DriftType          string     `json:"drift_type" enums:"table_added,table_removed,column_added,column_removed,column_changed,index_added,index_removed,index_changed"`
+	ObjectName         string     `json:"object_name"`
+	BaselineDefinition string     `json:"baseline_definition"`
+	CurrentDefinition  string     `json:"current_definition"`
+	Diff               string     `json:"diff"`
+	WorkflowId         string     `json:"workflow_id"`
+	Status             string     `json:"status" enums:"open,resolved"`
+	FirstDetectedAt    *time.Time `json:"first_detected_at"`
+	LastDetectedAt     *time.Time `json:"last_detected_at"`
+	ResolvedAt         *time.Time `json:"resolved_at"`
+}
+
+type GetSchemaDriftFindingsResV1 struct {
+	controller.BaseRes
+	Data      []*SchemaDriftFindingResV1 `json:"data"`
+	TotalNums uint64                     `json:"total_nums"`
+}
+
+// GetSchemaDriftFindings
+// @Summary 获取实例的表结构漂移列表
+// @Description get the schema drift found by the schema meta audit plan against the baseline of the instance
+// @Id getSchemaDriftFindingsV1
+// @Tags schema_drift
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param instance_name path string true "instance name"
+// @Param filter_status query string false "filter status" Enums(open,resolved)
+// @Param filter_drift_type query string false "filter drift type" Enums(table_added,table_removed,column_added,column_removed,column_changed,index_added,index_removed,index_changed)
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetSchemaDriftFindingsResV1
+// @router /v1/projects/{project_name}/instances/{instance_name}/schema_drift_findings [get]
+func GetSchemaDriftFindings(c echo.Context) error {
+	req := new(GetSchemaDriftFindingsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, instance, _, err := getSchemaDriftInstance(c, false)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	findings, count, err := model.GetStorage().GetSchemaDriftFindingList(projectUid, instance.GetIDStr(), req.FilterStatus, req.FilterDriftType, req.PageIndex, req.PageSize)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]*SchemaDriftFindingResV1, 0, len(findings))
+	for _, f := range findings {
+		data = append(data, &SchemaDriftFindingResV1{
+			Id:                 f.ID,
+			SchemaName:         f.SchemaName,
+			TableName:          f.TableName,
+			DriftType:          f.DriftType,
+			ObjectName:         f.ObjectName,
+			BaselineDefinition: f.BaselineDefinition,
+			CurrentDefinition:  f.CurrentDefinition,
+			Diff:               f.Diff,
+			WorkflowId:         f.WorkflowId,
+			Status:             f.Status,
+			FirstDetectedAt:    f.FirstDetectedAt,
+			LastDetectedAt:     f.LastDetectedAt,
+			ResolvedAt:         f.ResolvedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSchemaDriftFindingsResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: count,
+	})
+}
@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/labstack/echo/v4"
+)
+
+type ScoringPolicyV1 struct {
+	Enabled        bool               `json:"enabled"`
+	BaseScore      int32              `json:"base_score" example:"100"`
+	LevelPenalties map[string]float64 `json:"level_penalties"`
+	RulePenalties  map[string]float64 `json:"rule_penalties"`
+	CategoryCaps   map[string]float64 `json:"category_caps"`
+	HardFailRules  []string           `json:"hard_fail_rules"`
+	MinSubmitScore int32              `json:"min_submit_score" example:"60"`
+}
+
+type GetScoringPolicyResV1 struct {
+	controller.BaseRes
+	Data *ScoringPolicyV1 `json:"data"`
+}
+
+// GetScoringPolicy
+// @Summary 获取项目的审核评分策略
+// @Description get the audit scoring policy of the project, the default scoring is used if it is not enabled
+// @Id getScoringPolicyV1
+// @Tags scoring_policy
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Success 200 {object} v1.GetScoringPolicyResV1
+// @router /v1/projects/{project_name}/scoring_policy [get]
+func GetScoringPolicy(c echo.Context) error {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	policy, exist, err := model.GetStorage().GetScoringPolicyByProjectId(projectUid)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := &ScoringPolicyV1{
+		BaseScore: 100,
+		LevelPenalties: map[string]float64{
+			string(driverV2.RuleLevelError):  0,
+			string(driverV2.RuleLevelWarn):   0,
+			string(driverV2.RuleLevelNotice): 0,
+		},
+		RulePenalties: map[string]float64{},
+		CategoryCaps:  map[string]float64{},
+		HardFailRules: []string{},
+	}
+	if exist {
+		data.Enabled = policy.Enabled
+		data.BaseScore = policy.BaseScore
+		for level, penalty := range policy.LevelPenalties {
+			data.LevelPenalties[level] = penalty
+		}
+		for rule, penalty := range policy.RulePenalties {
+			data.RulePenalties[rule] = penalty
+		}
+		for category, limit := range policy.CategoryCaps {
+			data.CategoryCaps[category] = limit
+		}
+		data.HardFailRules = append(data.HardFailRules, policy.HardFailRules...)
+		data.MinSubmitScore = policy.MinSubmitScore
+	}
+	return c.JSON(http.StatusOK, &GetScoringPolicyResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type UpdateScoringPolicyReqV1 struct {
+	ScoringPolicyV1
+}
+
+// UpdateScoringPolicy
+// @Summary 更新项目的审核评分策略
+// @Description update the audit scoring policy of the project, it takes effect on the tasks audited after the update
+// @Accept json
+// @Id updateScoringPolicyV1
+// @Tags scoring_policy
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param policy body v1.UpdateScoringPolicyReqV1 true "update scoring policy request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/scoring_policy [put]
+func UpdateScoringPolicy(c echo.Context) error {
+	req := new(UpdateScoringPolicyReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := checkScoringPolicy(&req.ScoringPolicyV1); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+
+	s := model.GetStorage()
+	policy, exist, err := s.GetScoringPolicyByProjectId(projectUid)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		policy = &model.ScoringPolicy{ProjectId: projectUid}
+	}
+	policy.Enabled = req.Enabled
+	policy.BaseScore = req.BaseScore
+	policy.LevelPenalties = req.LevelPenalties
+	policy.RulePenalties = req.RulePenalties
+	policy.CategoryCaps = req.CategoryCaps
+	policy.HardFailRules = req.HardFailRules
+	policy.MinSubmitScore = req.MinSubmitScore
+	return controller.JSONBaseErrorReq(c, s.Save(policy))
+}
+
+func checkScoringPolicy(policy *ScoringPolicyV1) error {
+	if policy.BaseScore <= 0 {
+		return fmt.Errorf("base score must be greater than 0")
+	}
+	if policy.MinSubmitScore < 0 || policy.MinSubmitScore > policy.BaseScore {
+		return fmt.Errorf("min submit score must be between 0 and the base score")
+	}
+	for level, penalty := range policy.LevelPenalties {
+		switch driverV2.RuleLevel(level) {
+		case driverV2.RuleLevelError, driverV2.RuleLevelWarn, driverV2.RuleLevelNotice:
+		default:
+			return fmt.Errorf("level %s is invalid, it must be one of error, warn and notice", level)
+		}
+		if penalty < 0 {
+			return fmt.Errorf("penalty of level %s must not be negative", level)
+		}
+	}
+	for rule, penalty := range policy.RulePenalties {
+		if penalty < 0 {
+			return fmt.Errorf("penalty of rule %s must not be negative", rule)
+		}
+	}
+	for category, limit := range policy.CategoryCaps {
+		if limit < 0 {
+			return fmt.Errorf("cap of category %s must not be negative", category)
+		}
+	}
+	return nil
+}
@@ -96,7 +96,7 @@ func DirectAudit(c echo.Context) error {
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
-	l := log.NewEntry().WithField("/v1/sql_audit", "direct audit failed")
+	l := log.NewEntry().WithField("/v1/sql_audit", "direct audit failed").WithContext(c.Request().Context())
 
 	ctx := c.Request().Context()
 	var instance *model.Instance
@@ -204,7 +204,7 @@ func DirectAuditFiles(c echo.Context) error {
 		sqls = req.FileContents[0]
 	}
 
-	l := log.NewEntry().WithField("api", "[post]/v1/audit_files")
+	l := log.NewEntry().WithField("api", "[post]/v1/audit_files").WithContext(c.Request().Context())
 
 	ctx := c.Request().Context()
 	var instance *model.Instance
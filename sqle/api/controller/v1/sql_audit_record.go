@@ -166,7 +166,7 @@ func CreateSQLAuditRecord(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, fmt.Errorf("save sql audit record failed: %v", err))
 	}
 
-	task, err = server.GetSqled().AddTaskWaitResult(projectUid, fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
+	task, err = server.GetSqled().AddTaskWaitResult(c.Request().Context(), projectUid, fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
 	if err != nil {
 		if txrr := s.HandleSQLAuditFailure(&record); txrr != nil {
 			return controller.JSONBaseErrorReq(c, fmt.Errorf("audit task execute failed %v, rollback sql audit record failed: %v", err, txrr))
@@ -0,0 +1,260 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+	"github.com/labstack/echo/v4"
+)
+
+// getSqlManageInProject returns the SQL in SQL management if it belongs to the project and the current user can view its instance.
+func getSqlManageInProject(c echo.Context) (*model.SQLManageRecord, error) {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return nil, err
+	}
+	sql, exist, err := model.GetStorage().GetManageSQLById(c.Param("sql_manage_id"))
+	if err != nil {
+		return nil, err
+	}
+	if !exist || sql.ProjectId != projectUid {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("sql manage is not exist"))
+	}
+	instance, exist, err := dms.GetInstancesById(c.Request().Context(), sql.InstanceID)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrInstanceNoAccess
+	}
+	can, err := CheckCurrentUserCanViewInstances(c.Request().Context(), projectUid, controller.GetUserID(c), []*model.Instance{instance})
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, ErrInstanceNoAccess
+	}
+	return sql, nil
+}
+
+type SqlManageExecutePlanResV1 struct {
+	Id          uint      `json:"id"`
+	PlanHash    string    `json:"plan_hash"`
+	PlanSummary string    `json:"plan_summary" example:"t1(ALL) -> t2(ref, idx_a)"`
+	SeenCount   int       `json:"seen_count"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+type SqlManageExecutePlanChangeResV1 struct {
+	Id           uint      `json:"id"`
+	FromPlanId   uint      `json:"from_plan_id"`
+	ToPlanId     uint      `json:"to_plan_id"`
+	ChangeTypes  []string  `json:"change_types" enums:"index_to_full_scan,full_scan_to_index,index_changed,access_type_changed,join_order_changed"`
+	IsRegression bool      `json:"is_regression"`
+	DetectedAt   time.Time `json:"detected_at"`
+}
+
+type SqlManageExecutePlanHistoryResV1 struct {
+	Plans   []*SqlManageExecutePlanResV1       `json:"plans"`
+	Changes []*SqlManageExecutePlanChangeResV1 `json:"changes"`
+}
+
+type GetSqlManageExecutePlansResV1 struct {
+	controller.BaseRes
+	Data *SqlManageExecutePlanHistoryResV1 `json:"data"`
+}
+
+func convertSqlManageExecutePlanToRes(plan *model.SqlManageExecutePlan) *SqlManageExecutePlanResV1 {
+	res := &SqlManageExecutePlanResV1{
+		Id:          plan.ID,
+		PlanHash:    plan.PlanHash,
+		SeenCount:   plan.SeenCount,
+		FirstSeenAt: plan.FirstSeenAt,
+		LastSeenAt:  plan.LastSeenAt,
+	}
+	if nodes, err := auditplan.ParseExecutePlanSummary(plan.PlanSummary); err == nil {
+		res.PlanSummary = auditplan.FormatExecutePlan(nodes)
+	}
+	return res
+}
+
+// GetSqlManageExecutePlans
+// @Summary 获取SQL管控SQL的执行计划历史
+// @Description get the distinct execute plans of the sql and the plan changes
+// @Id getSqlManageExecutePlansV1
+// @Tags SqlManage
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param sql_manage_id path string true "sql manage id"
+// @Success 200 {object} v1.GetSqlManageExecutePlansResV1
+// @router /v1/projects/{project_name}/sql_manages/{sql_manage_id}/execute_plans [get]
+func GetSqlManageExecutePlans(c echo.Context) error {
+	sql, err := getSqlManageInProject(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	plans, err := s.GetSqlManageExecutePlans(sql.SQLID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	changes, err := s.GetSqlManageExecutePlanChanges(sql.SQLID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := &SqlManageExecutePlanHistoryResV1{
+		Plans:   make([]*SqlManageExecutePlanResV1, 0, len(plans)),
+		Changes: make([]*SqlManageExecutePlanChangeResV1, 0, len(changes)),
+	}
+	for _, plan := range plans {
+		data.Plans = append(data.Plans, convertSqlManageExecutePlanToRes(plan))
+	}
+	for _, change := range changes {
+		data.Changes = append(data.Changes, &SqlManageExecutePlanChangeResV1{
+			Id:           change.ID,
+			FromPlanId:   change.FromPlanID,
+			ToPlanId:     change.ToPlanID,
+			ChangeTypes:  change.ChangeTypes,
+			IsRegression: change.IsRegression,
+			DetectedAt:   change.DetectedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSqlManageExecutePlansResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type GetSqlManageExecutePlanDiffReqV1 struct {
+	FromPlanId uint `query:"from_plan_id" json:"from_plan_id" valid:"required"`
+	ToPlanId   uint `query:"to_plan_id" json:"to_plan_id" valid:"required"`
+}
+
+type ExecutePlanRowResV1 struct {
+	SelectType   string  `json:"select_type"`
+	Type         string  `json:"type"`
+	PossibleKeys string  `json:"possible_keys"`
+	Key          string  `json:"key"`
+	KeyLen       int     `json:"key_len"`
+	Ref          string  `json:"ref"`
+	Rows         int     `json:"rows"`
+	Filtered     float64 `json:"filtered"`
+	Extra        string  `json:"extra"`
+}
+
+type ExecutePlanDiffRowResV1 struct {
+	SelectId int                  `json:"select_id"`
+	Table    string               `json:"table"`
+	From     *ExecutePlanRowResV1 `json:"from"`
+	To       *ExecutePlanRowResV1 `json:"to"`
+	Changed  bool                 `json:"changed"`
+}
+
+type SqlManageExecutePlanDiffResV1 struct {
+	FromPlan     *SqlManageExecutePlanResV1 `json:"from_plan"`
+	ToPlan       *SqlManageExecutePlanResV1 `json:"to_plan"`
+	ChangeTypes  []string                   `json:"change_types" enums:"index_to_full_scan,full_scan_to_index,index_changed,access_type_changed,join_order_changed"`
+	IsRegression bool                       `json:"is_regression"`
+	Rows         []*ExecutePlanDiffRowResV1 `json:"rows"`
+}
+
+type GetSqlManageExecutePlanDiffResV1 struct {
+	controller.BaseRes
+	Data *SqlManageExecutePlanDiffResV1 `json:"data"`
+}
+
+func convertExecutePlanRecordToRes(record *model.SqlManageMetricExecutePlanRecord) *ExecutePlanRowResV1 {
+	if record == nil {
+		return nil
+	}
+	return &ExecutePlanRowResV1{
+		SelectType:   record.SelectType,
+		Type:         record.Type,
+		PossibleKeys: record.PossibleKeys,
+		Key:          record.Key,
+		KeyLen:       record.KeyLen,
+		Ref:          record.Ref,
+		Rows:         record.Rows,
+		Filtered:     record.Filtered,
+		Extra:        record.Extra,
+	}
+}
+
+// GetSqlManageExecutePlanDiff
+// @Summary 对比SQL管控SQL的两个执行计划
+// @Description compare two execute plans of the sql side by side
+// @Id getSqlManageExecutePlanDiffV1
+// @Tags SqlManage
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param sql_manage_id path string true "sql manage id"
+// @Param from_plan_id query uint true "from plan id"
+// @Param to_plan_id query uint true "to plan id"
+// @Success 200 {object} v1.GetSqlManageExecutePlanDiffResV1
+// @router /v1/projects/{project_name}/sql_manages/{sql_manage_id}/execute_plans/diff [get]
+func GetSqlManageExecutePlanDiff(c echo.Context) error {
+	req := new(GetSqlManageExecutePlanDiffReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	sql, err := getSqlManageInProject(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	s := model.GetStorage()
+	plans := make([]*model.SqlManageExecutePlan, 0, 2)
+	records := make([][]*model.SqlManageMetricExecutePlanRecord, 0, 2)
+	nodes := make([][]auditplan.ExecutePlanNode, 0, 2)
+	for _, planId := range []uint{req.FromPlanId, req.ToPlanId} {
+		plan, exist, err := s.GetSqlManageExecutePlanById(sql.SQLID, planId)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("execute plan %d is not exist", planId)))
+		}
+		planRecords, err := s.GetSqlManageMetricExecutePlanRecords(plan.LastMetricRecordID)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		planNodes, err := auditplan.ParseExecutePlanSummary(plan.PlanSummary)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		plans = append(plans, plan)
+		records = append(records, planRecords)
+		nodes = append(nodes, planNodes)
+	}
+
+	changeTypes, isRegression := auditplan.CompareExecutePlans(nodes[0], nodes[1])
+	data := &SqlManageExecutePlanDiffResV1{
+		FromPlan:     convertSqlManageExecutePlanToRes(plans[0]),
+		ToPlan:       convertSqlManageExecutePlanToRes(plans[1]),
+		ChangeTypes:  changeTypes,
+		IsRegression: isRegression,
+		Rows:         []*ExecutePlanDiffRowResV1{},
+	}
+	for _, pair := range auditplan.AlignExecutePlanRecords(records[0], records[1]) {
+		data.Rows = append(data.Rows, &ExecutePlanDiffRowResV1{
+			SelectId: pair.SelectId,
+			Table:    pair.Table,
+			From:     convertExecutePlanRecordToRes(pair.From),
+			To:       convertExecutePlanRecordToRes(pair.To),
+			Changed:  pair.Changed,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSqlManageExecutePlanDiffResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
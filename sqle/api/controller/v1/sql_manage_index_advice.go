@@ -0,0 +1,159 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/driver/mysql"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+// the default days of the metrics weighting the workload
+const defaultSqlManageIndexAdviceDays = 7
+
+type GetSqlManageIndexAdviceReqV1 struct {
+	InstanceId string `query:"instance_id" json:"instance_id" valid:"required"`
+	SchemaName string `query:"schema_name" json:"schema_name" valid:"required"`
+	Days       uint   `query:"days" json:"days" example:"7"`
+}
+
+type WorkloadIndexQueryResV1 struct {
+	SqlManageId    uint    `json:"sql_manage_id"`
+	SqlFingerprint string  `json:"sql_fingerprint"`
+	ExecutionCount int64   `json:"execution_count"`
+	QueryTimeAvg   float64 `json:"query_time_avg"`
+	// QueryTimeKnown is false if the query time is assumed to be the median of the workload
+	QueryTimeKnown bool    `json:"query_time_known"`
+	Weight         float64 `json:"weight"`
+}
+
+type WorkloadIndexResV1 struct {
+	TableName          string                     `json:"table_name"`
+	IndexName          string                     `json:"index_name"`
+	Columns            []string                   `json:"columns"`
+	CreateSQL          string                     `json:"create_sql"`
+	Benefit            float64                    `json:"benefit"`
+	WriteWeight        float64                    `json:"write_weight"`
+	WriteAmplification float64                    `json:"write_amplification"`
+	StorageBytes       int64                      `json:"storage_bytes"`
+	Queries            []*WorkloadIndexQueryResV1 `json:"queries"`
+	Reason             string                     `json:"reason,omitempty"`
+}
+
+type SqlManageIndexAdviceResV1 struct {
+	MaxIndexCount int                   `json:"max_index_count"`
+	Indexes       []*WorkloadIndexResV1 `json:"indexes"`
+	Rejected      []*WorkloadIndexResV1 `json:"rejected"`
+}
+
+type GetSqlManageIndexAdviceResV1 struct {
+	controller.BaseRes
+	Data *SqlManageIndexAdviceResV1 `json:"data"`
+}
+
+// GetSqlManageIndexAdvice
+// @Summary 获取SQL管控中数据源的工作负载索引建议
+// @Description advise the indexes for the workload of the schema in sql management, the sql are weighted by their execution count and query time
+// @Id getSqlManageIndexAdviceV1
+// @Tags SqlManage
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param instance_id query string true "instance id"
+// @Param schema_name query string true "schema name"
+// @Param days query uint false "days of the metrics weighting the workload, default 7"
+// @Success 200 {object} v1.GetSqlManageIndexAdviceResV1
+// @router /v1/projects/{project_name}/sql_manages/index_advice [get]
+func GetSqlManageIndexAdvice(c echo.Context) error {
+	req := new(GetSqlManageIndexAdviceReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	projectUid, err := dms.GetProjectUIDByName(c.Request().Context(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	instanceId, err := strconv.ParseUint(req.InstanceId, 10, 64)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.NewDataInvalidErr("instance id %s is invalid", req.InstanceId))
+	}
+	instance, exist, err := dms.GetInstanceInProjectById(c.Request().Context(), projectUid, instanceId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrInstanceNotExist)
+	}
+	can, err := CheckCurrentUserCanViewInstances(c.Request().Context(), projectUid, controller.GetUserID(c), []*model.Instance{instance})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !can {
+		return controller.JSONBaseErrorReq(c, ErrInstanceNoAccess)
+	}
+
+	days := req.Days
+	if days == 0 {
+		days = defaultSqlManageIndexAdviceDays
+	}
+	since := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	advice, err := auditplan.AdviseSqlManageIndexes(log.NewEntry(), instance, req.SchemaName, since)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	lang := locale.Bundle.GetLangTagFromCtx(c.Request().Context())
+	data := &SqlManageIndexAdviceResV1{
+		MaxIndexCount: advice.MaxIndexCount,
+		Indexes:       make([]*WorkloadIndexResV1, 0, len(advice.Plan.Indexes)),
+		Rejected:      make([]*WorkloadIndexResV1, 0, len(advice.Plan.Rejected)),
+	}
+	for _, index := range advice.Plan.Indexes {
+		data.Indexes = append(data.Indexes, convertWorkloadIndexToRes(index, advice, lang))
+	}
+	for _, index := range advice.Plan.Rejected {
+		data.Rejected = append(data.Rejected, convertWorkloadIndexToRes(index, advice, lang))
+	}
+	return c.JSON(http.StatusOK, &GetSqlManageIndexAdviceResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func convertWorkloadIndexToRes(index *mysql.WorkloadIndex, advice *auditplan.SqlManageIndexAdvice, lang language.Tag) *WorkloadIndexResV1 {
+	res := &WorkloadIndexResV1{
+		TableName:          index.TableName,
+		IndexName:          index.IndexName,
+		Columns:            index.Columns,
+		CreateSQL:          index.CreateSQL,
+		Benefit:            index.Benefit,
+		WriteWeight:        index.WriteWeight,
+		WriteAmplification: index.WriteAmplification,
+		StorageBytes:       index.StorageBytes,
+		Queries:            make([]*WorkloadIndexQueryResV1, 0, len(index.SQLIDs)),
+		Reason:             index.Reason.GetStrInLang(lang),
+	}
+	for _, sqlId := range index.SQLIDs {
+		query, ok := advice.Queries[sqlId]
+		if !ok {
+			continue
+		}
+		res.Queries = append(res.Queries, &WorkloadIndexQueryResV1{
+			SqlManageId:    query.Record.ID,
+			SqlFingerprint: query.Record.SqlFingerprint,
+			ExecutionCount: query.ExecutionCount,
+			QueryTimeAvg:   query.QueryTimeAvg,
+			QueryTimeKnown: query.QueryTimeKnown,
+			Weight:         query.Weight,
+		})
+	}
+	return res
+}
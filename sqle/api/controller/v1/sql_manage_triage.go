@@ -0,0 +1,322 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+	"github.com/labstack/echo/v4"
+)
+
+type SqlManageMetricConditionV1 struct {
+	// Metric is a numeric metric collected by the audit plan, e.g. query_time_avg
+	Metric   string  `json:"metric" valid:"required"`
+	Operator string  `json:"operator" enums:">,>=,<,<=,=" valid:"required,oneof=> >= < <= ="`
+	Value    float64 `json:"value"`
+}
+
+type SqlManageTriageRuleV1 struct {
+	Name     string `json:"name" valid:"required,max=255"`
+	Type     string `json:"type" enums:"match,auto_close,auto_reopen" valid:"required,oneof=match auto_close auto_reopen"`
+	Priority int    `json:"priority"`
+	Enabled  bool   `json:"enabled"`
+	// Sources are the audit plan types collecting the SQL
+	Sources          []string                      `json:"sources"`
+	InstanceIds      []string                      `json:"instance_ids"`
+	SchemaNames      []string                      `json:"schema_names"`
+	MinAuditLevel    string                        `json:"min_audit_level" enums:"normal,notice,warn,error" valid:"omitempty,oneof=normal notice warn error"`
+	MetricConditions []*SqlManageMetricConditionV1 `json:"metric_conditions" valid:"dive"`
+	// InactiveDays is the days the SQL has not been seen before it is closed by the auto_close rule
+	InactiveDays uint `json:"inactive_days"`
+	// Status is only set by the match rule, the auto_close rule solves the SQL and the auto_reopen rule reopens it
+	Status string `json:"status" enums:"unhandled,solved,ignored,manual_audited"`
+	// Assignees are the user ids the SQL is assigned to
+	Assignees []string `json:"assignees"`
+	// SQLPriority is not changed if it is null
+	SQLPriority *string `json:"sql_priority" enums:",high"`
+	// Comment is appended to the remark of the SQL
+	Comment string `json:"comment" valid:"max=1000"`
+}
+
+type SqlManageTriageRuleResV1 struct {
+	Id uint `json:"id"`
+	SqlManageTriageRuleV1
+}
+
+type GetSqlManageTriageRulesResV1 struct {
+	controller.BaseRes
+	Data []*SqlManageTriageRuleResV1 `json:"data"`
+}
+
+// GetSqlManageTriageRules
+// @Summary 获取SQL管控分诊规则列表
+// @Description get the rules automating the triage of the SQL in sql management, the enabled rules are evaluated by priority in ascending order
+// @Id getSqlManageTriageRulesV1
+// @Tags SqlManage
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Success 200 {object} v1.GetSqlManageTriageRulesResV1
+// @router /v1/projects/{project_name}/sql_manages/triage_rules [get]
+func GetSqlManageTriageRules(c echo.Context) error {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rules, err := model.GetStorage().GetSqlManageTriageRules(model.ProjectUID(projectUid))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*SqlManageTriageRuleResV1, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, convertSqlManageTriageRuleToRes(rule))
+	}
+	return c.JSON(http.StatusOK, &GetSqlManageTriageRulesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func convertSqlManageTriageRuleToRes(rule *model.SqlManageTriageRule) *SqlManageTriageRuleResV1 {
+	conditions := make([]*SqlManageMetricConditionV1, 0, len(rule.MetricConditions))
+	for _, condition := range rule.MetricConditions {
+		conditions = append(conditions, &SqlManageMetricConditionV1{
+			Metric:   condition.Metric,
+			Operator: condition.Operator,
+			Value:    condition.Value,
+		})
+	}
+	return &SqlManageTriageRuleResV1{
+		Id: rule.ID,
+		SqlManageTriageRuleV1: SqlManageTriageRuleV1{
+			Name:             rule.Name,
+			Type:             rule.Type,
+			Priority:         rule.Priority,
+			Enabled:          rule.Enabled,
+			Sources:          rule.Sources,
+			InstanceIds:      rule.InstanceIds,
+			SchemaNames:      rule.SchemaNames,
+			MinAuditLevel:    rule.MinAuditLevel,
+			MetricConditions: conditions,
+			InactiveDays:     rule.InactiveDays,
+			Status:           rule.Status,
+			Assignees:        rule.Assignees,
+			SQLPriority:      rule.SQLPriority,
+			Comment:          rule.Comment,
+		},
+	}
+}
+
+type CreateSqlManageTriageRuleReqV1 struct {
+	SqlManageTriageRuleV1
+}
+
+// CreateSqlManageTriageRule
+// @Summary 添加SQL管控分诊规则
+// @Description create a rule automating the triage of the SQL in sql management, the SQL matching all its conditions get its actions and an empty condition always matches
+// @Accept json
+// @Id createSqlManageTriageRuleV1
+// @Tags SqlManage
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule body v1.CreateSqlManageTriageRuleReqV1 true "create sql manage triage rule request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/sql_manages/triage_rules [post]
+func CreateSqlManageTriageRule(c echo.Context) error {
+	req := new(CreateSqlManageTriageRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule := &model.SqlManageTriageRule{ProjectId: model.ProjectUID(projectUid)}
+	if err := setSqlManageTriageRule(rule, &req.SqlManageTriageRuleV1); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Save(rule))
+}
+
+type UpdateSqlManageTriageRuleReqV1 struct {
+	SqlManageTriageRuleV1
+}
+
+// UpdateSqlManageTriageRule
+// @Summary 更新SQL管控分诊规则
+// @Description update a triage rule of sql management, the changes made by the rule before are kept
+// @Accept json
+// @Id updateSqlManageTriageRuleV1
+// @Tags SqlManage
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule_id path string true "triage rule id"
+// @Param rule body v1.UpdateSqlManageTriageRuleReqV1 true "update sql manage triage rule request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/sql_manages/triage_rules/{rule_id}/ [patch]
+func UpdateSqlManageTriageRule(c echo.Context) error {
+	req := new(UpdateSqlManageTriageRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule, err := getSqlManageTriageRule(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := setSqlManageTriageRule(rule, &req.SqlManageTriageRuleV1); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Save(rule))
+}
+
+// DeleteSqlManageTriageRule
+// @Summary 删除SQL管控分诊规则
+// @Description delete a triage rule of sql management, the records of the changes made by the rule are kept
+// @Id deleteSqlManageTriageRuleV1
+// @Tags SqlManage
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule_id path string true "triage rule id"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/sql_manages/triage_rules/{rule_id}/ [delete]
+func DeleteSqlManageTriageRule(c echo.Context) error {
+	rule, err := getSqlManageTriageRule(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Delete(rule))
+}
+
+func getSqlManageTriageRule(c echo.Context) (*model.SqlManageTriageRule, error) {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(c.Param("rule_id"), 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("rule id %s is invalid", c.Param("rule_id")))
+	}
+	rule, exist, err := model.GetStorage().GetSqlManageTriageRuleById(model.ProjectUID(projectUid), uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("triage rule is not exist"))
+	}
+	return rule, nil
+}
+
+func setSqlManageTriageRule(rule *model.SqlManageTriageRule, req *SqlManageTriageRuleV1) error {
+	conditions := make(model.SqlManageMetricConditions, 0, len(req.MetricConditions))
+	for _, condition := range req.MetricConditions {
+		conditions = append(conditions, &model.SqlManageMetricCondition{
+			Metric:   condition.Metric,
+			Operator: condition.Operator,
+			Value:    condition.Value,
+		})
+	}
+	rule.Name = req.Name
+	rule.Type = req.Type
+	rule.Priority = req.Priority
+	rule.Enabled = req.Enabled
+	rule.Sources = req.Sources
+	rule.InstanceIds = req.InstanceIds
+	rule.SchemaNames = req.SchemaNames
+	rule.MinAuditLevel = req.MinAuditLevel
+	rule.MetricConditions = conditions
+	rule.InactiveDays = req.InactiveDays
+	rule.Status = req.Status
+	rule.Assignees = req.Assignees
+	rule.SQLPriority = req.SQLPriority
+	rule.Comment = req.Comment
+	if err := auditplan.ValidateSqlManageTriageRule(rule); err != nil {
+		return errors.New(errors.DataInvalid, err)
+	}
+	return nil
+}
+
+type GetSqlManageTriageRecordsReqV1 struct {
+	FilterSqlManageId uint   `json:"filter_sql_manage_id" query:"filter_sql_manage_id"`
+	FilterRuleId      uint   `json:"filter_rule_id" query:"filter_rule_id"`
+	PageIndex         uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize          uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type SqlManageTriageRecordResV1 struct {
+	Id          uint      `json:"id"`
+	RuleId      uint      `json:"rule_id"`
+	RuleName    string    `json:"rule_name"`
+	RuleType    string    `json:"rule_type" enums:"match,auto_close,auto_reopen"`
+	SqlManageId uint      `json:"sql_manage_id"`
+	Field       string    `json:"field" enums:"status,assignees,priority,remark"`
+	OldValue    string    `json:"old_value"`
+	NewValue    string    `json:"new_value"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GetSqlManageTriageRecordsResV1 struct {
+	controller.BaseRes
+	Data      []*SqlManageTriageRecordResV1 `json:"data"`
+	TotalNums uint64                        `json:"total_nums"`
+}
+
+// GetSqlManageTriageRecords
+// @Summary 获取SQL管控分诊规则的变更记录
+// @Description get the records of the fields of the SQL changed by the triage rules of sql management
+// @Id getSqlManageTriageRecordsV1
+// @Tags SqlManage
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param filter_sql_manage_id query uint false "sql manage id"
+// @Param filter_rule_id query uint false "triage rule id"
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetSqlManageTriageRecordsResV1
+// @router /v1/projects/{project_name}/sql_manages/triage_records [get]
+func GetSqlManageTriageRecords(c echo.Context) error {
+	req := new(GetSqlManageTriageRecordsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	records, count, err := model.GetStorage().GetSqlManageTriageRecords(&model.SqlManageTriageRecordFilter{
+		ProjectId:         model.ProjectUID(projectUid),
+		SQLManageRecordId: req.FilterSqlManageId,
+		RuleId:            req.FilterRuleId,
+		Limit:             req.PageSize,
+		Offset:            (req.PageIndex - 1) * req.PageSize,
+	})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*SqlManageTriageRecordResV1, 0, len(records))
+	for _, record := range records {
+		data = append(data, &SqlManageTriageRecordResV1{
+			Id:          record.ID,
+			RuleId:      record.RuleId,
+			RuleName:    record.RuleName,
+			RuleType:    record.RuleType,
+			SqlManageId: record.SQLManageRecordId,
+			Field:       record.Field,
+			OldValue:    record.OldValue,
+			NewValue:    record.NewValue,
+			Reason:      record.Reason,
+			CreatedAt:   record.CreatedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSqlManageTriageRecordsResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: uint64(count),
+	})
+}
@@ -0,0 +1,365 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/labstack/echo/v4"
+)
+
+type QueryInstanceReqV1 struct {
+	SchemaName string `json:"schema_name" example:"db1"`
+	SQL        string `json:"sql" example:"select * from t1 where id = 1" valid:"required"`
+}
+
+type QueryInstanceResV1 struct {
+	controller.BaseRes
+	Data *QueryInstanceResDataV1 `json:"data"`
+}
+
+type QueryInstanceResDataV1 struct {
+	AuditResult *AuditResDataV1 `json:"audit_result"`
+	Columns     []string        `json:"columns"`
+	Rows        [][]string      `json:"rows"`
+	// Truncated is true if the result has more rows than the max_pre_query_rows of the instance
+	Truncated     bool     `json:"truncated"`
+	MaskedColumns []string `json:"masked_columns"`
+	DurationMs    int64    `json:"duration_ms"`
+}
+
+// QueryInstance
+// @Summary 在实例上执行只读查询
+// @Description audit the statement with the rule template of the instance and run it if it is a read-only query, the result is limited by the sql query config of the instance and masked by the masking rules of the project
+// @Accept json
+// @Id queryInstanceV1
+// @Tags sql_query
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param instance_name path string true "instance name"
+// @Param query body v1.QueryInstanceReqV1 true "query request"
+// @Success 200 {object} v1.QueryInstanceResV1
+// @router /v1/projects/{project_name}/instances/{instance_name}/query [post]
+func QueryInstance(c echo.Context) error {
+	req := new(QueryInstanceReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	instance, exist, err := dms.GetInstanceInProjectByName(c.Request().Context(), projectUid, c.Param("instance_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrInstanceNoAccess)
+	}
+	userId := controller.GetUserID(c)
+	can, err := CheckCurrentUserCanQueryInstance(c.Request().Context(), projectUid, userId, instance)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !can {
+		return controller.JSONBaseErrorReq(c, ErrInstanceNoAccess)
+	}
+
+	res, err := server.QueryReadOnly(log.NewEntry(), instance, req.SchemaName, req.SQL, userId)
+	if res == nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	// the audit result is returned with the error if the query is rejected
+	data := &QueryInstanceResDataV1{
+		AuditResult:   convertTaskResultToAuditResV1(c.Request().Context(), res.Task),
+		Columns:       res.Columns,
+		Rows:          res.Rows,
+		Truncated:     res.Truncated,
+		MaskedColumns: res.MaskedColumns,
+	}
+	if res.Record != nil {
+		data.DurationMs = res.Record.DurationMs
+	}
+	return c.JSON(http.StatusOK, &QueryInstanceResV1{
+		BaseRes: controller.NewBaseReq(err),
+		Data:    data,
+	})
+}
+
+type GetSQLQueryRecordsReqV1 struct {
+	FilterInstanceName string `json:"filter_instance_name" query:"filter_instance_name"`
+	FilterUserId       string `json:"filter_user_id" query:"filter_user_id"`
+	FilterStatus       string `json:"filter_status" query:"filter_status" enums:"succeeded,rejected,failed" valid:"omitempty,oneof=succeeded rejected failed"`
+	PageIndex          uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize           uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type SQLQueryRecordResV1 struct {
+	Id            uint       `json:"id"`
+	InstanceId    string     `json:"instance_id"`
+	UserId        string     `json:"user_id"`
+	SchemaName    string     `json:"schema_name"`
+	SQL           string     `json:"sql"`
+	AuditLevel    string     `json:"audit_level"`
+	Status        string     `json:"status" enums:"succeeded,rejected,failed"`
+	Error         string     `json:"error"`
+	RowCount      int        `json:"row_count"`
+	Truncated     bool       `json:"truncated"`
+	MaskedColumns []string   `json:"masked_columns"`
+	StartAt       *time.Time `json:"start_at"`
+	DurationMs    int64      `json:"duration_ms"`
+}
+
+type GetSQLQueryRecordsResV1 struct {
+	controller.BaseRes
+	Data      []*SQLQueryRecordResV1 `json:"data"`
+	TotalNums uint64                 `json:"total_nums"`
+}
+
+// GetSQLQueryRecords
+// @Summary 获取查询控制台的查询记录
+// @Description get the records of the query console, the members who are not project admin only get their own records
+// @Id getSQLQueryRecordsV1
+// @Tags sql_query
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param filter_instance_name query string false "filter instance name"
+// @Param filter_user_id query string false "filter user id"
+// @Param filter_status query string false "filter status" Enums(succeeded,rejected,failed)
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetSQLQueryRecordsResV1
+// @router /v1/projects/{project_name}/sql_query_records [get]
+func GetSQLQueryRecords(c echo.Context) error {
+	req := new(GetSQLQueryRecordsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	userId := controller.GetUserID(c)
+	up, err := dms.NewUserPermission(userId, projectUid)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	filterUserId := req.FilterUserId
+	if !up.CanViewProject() {
+		filterUserId = userId
+	}
+	instanceId := ""
+	if req.FilterInstanceName != "" {
+		instance, exist, err := dms.GetInstanceInProjectByName(c.Request().Context(), projectUid, req.FilterInstanceName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			return controller.JSONBaseErrorReq(c, ErrInstanceNotExist)
+		}
+		instanceId = instance.GetIDStr()
+	}
+
+	records, count, err := model.GetStorage().GetSQLQueryRecordList(projectUid, instanceId, filterUserId, req.FilterStatus, req.PageIndex, req.PageSize)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*SQLQueryRecordResV1, 0, len(records))
+	for _, r := range records {
+		data = append(data, &SQLQueryRecordResV1{
+			Id:            r.ID,
+			InstanceId:    r.InstanceId,
+			UserId:        r.UserId,
+			SchemaName:    r.Schema,
+			SQL:           r.SQL,
+			AuditLevel:    r.AuditLevel,
+			Status:        r.Status,
+			Error:         r.Error,
+			RowCount:      r.RowCount,
+			Truncated:     r.Truncated,
+			MaskedColumns: r.MaskedColumns,
+			StartAt:       r.StartAt,
+			DurationMs:    r.DurationMs,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSQLQueryRecordsResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: count,
+	})
+}
+
+type SQLQueryMaskingRuleV1 struct {
+	Name          string `json:"name" valid:"required"`
+	Enabled       bool   `json:"enabled"`
+	ColumnPattern string `json:"column_pattern" example:"^(phone|mobile)$" valid:"required"`
+	MaskingType   string `json:"masking_type" enums:"phone,id_card,email,regex" valid:"required,oneof=phone id_card email regex"`
+	ValuePattern  string `json:"value_pattern" example:"\\d{16}"`
+	Replacement   string `json:"replacement" example:"***"`
+}
+
+type SQLQueryMaskingRuleResV1 struct {
+	Id uint `json:"id"`
+	SQLQueryMaskingRuleV1
+}
+
+type GetSQLQueryMaskingRulesResV1 struct {
+	controller.BaseRes
+	Data []*SQLQueryMaskingRuleResV1 `json:"data"`
+}
+
+// GetSQLQueryMaskingRules
+// @Summary 获取查询控制台的脱敏规则
+// @Description get the masking rules of the query console, a column is masked by the first enabled rule matching its name
+// @Id getSQLQueryMaskingRulesV1
+// @Tags sql_query
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Success 200 {object} v1.GetSQLQueryMaskingRulesResV1
+// @router /v1/projects/{project_name}/sql_query_masking_rules [get]
+func GetSQLQueryMaskingRules(c echo.Context) error {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rules, err := model.GetStorage().GetSQLQueryMaskingRules(projectUid)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*SQLQueryMaskingRuleResV1, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, &SQLQueryMaskingRuleResV1{
+			Id: rule.ID,
+			SQLQueryMaskingRuleV1: SQLQueryMaskingRuleV1{
+				Name:          rule.Name,
+				Enabled:       rule.Enabled,
+				ColumnPattern: rule.ColumnPattern,
+				MaskingType:   rule.MaskingType,
+				ValuePattern:  rule.ValuePattern,
+				Replacement:   rule.Replacement,
+			},
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSQLQueryMaskingRulesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type CreateSQLQueryMaskingRuleReqV1 struct {
+	SQLQueryMaskingRuleV1
+}
+
+// CreateSQLQueryMaskingRule
+// @Summary 添加查询控制台的脱敏规则
+// @Description create a masking rule of the query console
+// @Accept json
+// @Id createSQLQueryMaskingRuleV1
+// @Tags sql_query
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule body v1.CreateSQLQueryMaskingRuleReqV1 true "create masking rule request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/sql_query_masking_rules [post]
+func CreateSQLQueryMaskingRule(c echo.Context) error {
+	req := new(CreateSQLQueryMaskingRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule := &model.SQLQueryMaskingRule{ProjectId: projectUid}
+	setSQLQueryMaskingRule(rule, &req.SQLQueryMaskingRuleV1)
+	if err := server.ValidateSQLQueryMaskingRule(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Save(rule))
+}
+
+type UpdateSQLQueryMaskingRuleReqV1 struct {
+	SQLQueryMaskingRuleV1
+}
+
+// UpdateSQLQueryMaskingRule
+// @Summary 更新查询控制台的脱敏规则
+// @Description update a masking rule of the query console
+// @Accept json
+// @Id updateSQLQueryMaskingRuleV1
+// @Tags sql_query
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule_id path string true "masking rule id"
+// @Param rule body v1.UpdateSQLQueryMaskingRuleReqV1 true "update masking rule request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/sql_query_masking_rules/{rule_id}/ [patch]
+func UpdateSQLQueryMaskingRule(c echo.Context) error {
+	req := new(UpdateSQLQueryMaskingRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule, err := getSQLQueryMaskingRule(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	setSQLQueryMaskingRule(rule, &req.SQLQueryMaskingRuleV1)
+	if err := server.ValidateSQLQueryMaskingRule(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Save(rule))
+}
+
+// DeleteSQLQueryMaskingRule
+// @Summary 删除查询控制台的脱敏规则
+// @Description delete a masking rule of the query console
+// @Id deleteSQLQueryMaskingRuleV1
+// @Tags sql_query
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule_id path string true "masking rule id"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/sql_query_masking_rules/{rule_id}/ [delete]
+func DeleteSQLQueryMaskingRule(c echo.Context) error {
+	rule, err := getSQLQueryMaskingRule(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Delete(rule))
+}
+
+func getSQLQueryMaskingRule(c echo.Context) (*model.SQLQueryMaskingRule, error) {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(c.Param("rule_id"), 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("rule id %s is invalid", c.Param("rule_id")))
+	}
+	rule, exist, err := model.GetStorage().GetSQLQueryMaskingRuleById(projectUid, uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("masking rule is not exist"))
+	}
+	return rule, nil
+}
+
+func setSQLQueryMaskingRule(rule *model.SQLQueryMaskingRule, req *SQLQueryMaskingRuleV1) {
+	rule.Name = req.Name
+	rule.Enabled = req.Enabled
+	rule.ColumnPattern = req.ColumnPattern
+	rule.MaskingType = req.MaskingType
+	rule.ValuePattern = req.ValuePattern
+	rule.Replacement = req.Replacement
+}
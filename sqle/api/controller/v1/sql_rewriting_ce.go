@@ -4,17 +4,74 @@
 package v1
 
 import (
-	e "errors"
+	"net/http"
+	"time"
 
-	"github.com/actiontech/sqle/sqle/errors"
+	dmsV1 "github.com/actiontech/dms/pkg/dms-common/api/dms/v1"
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/server"
 	"github.com/labstack/echo/v4"
 )
 
+// getRewriteSQLData rewrites the statement by the built-in rewriter in process, the rewriting is fast enough to be
+// done in the request, so the task is completed once it is started.
 func getRewriteSQLData(c echo.Context) error {
-	return errors.New(errors.EnterpriseEditionFeatures, e.New("sql rewriting is enterprise version feature"))
+	req := new(RewriteSQLReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return rewriteTaskSQL(c)
 }
 
 // getAsyncRewriteTaskStatus 获取异步重写任务状态
 func getAsyncRewriteTaskStatus(c echo.Context) error {
-	return errors.New(errors.EnterpriseEditionFeatures, e.New("sql rewriting is enterprise version feature"))
+	return rewriteTaskSQL(c)
+}
+
+func rewriteTaskSQL(c echo.Context) error {
+	startTime := time.Now()
+	task, taskSql, err := getTaskSQLForChunkedExecution(c, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeViewOthersWorkflow})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	result, err := server.RewriteTaskSQL(log.NewEntry(), task, taskSql)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	lang := locale.Bundle.GetLangTagFromCtx(c.Request().Context())
+	levels := map[string]string{}
+	for _, auditResult := range taskSql.AuditResults {
+		levels[auditResult.RuleName] = auditResult.Level
+	}
+	data := &RewriteSQLData{
+		Suggestions:               make([]*RewriteSuggestion, 0, len(result.Suggestions)),
+		RewrittenSQL:              result.RewrittenSQL,
+		BusinessNonEquivalentDesc: result.NonEquivalentDesc.GetStrInLang(lang),
+	}
+	for _, suggestion := range result.Suggestions {
+		data.Suggestions = append(data.Suggestions, &RewriteSuggestion{
+			RuleName:     suggestion.RuleName,
+			AuditLevel:   levels[suggestion.RuleName],
+			Type:         "statement",
+			Desc:         suggestion.Desc.GetStrInLang(lang),
+			Status:       "initial",
+			RewrittenSQL: suggestion.RewrittenSQL,
+		})
+	}
+
+	endTime := time.Now().Format(time.RFC3339)
+	return c.JSON(http.StatusOK, &AsyncRewriteTaskStatusRes{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &AsyncRewriteTask{
+			TaskID:    c.Param("task_id"),
+			SQLNumber: c.Param("number"),
+			Status:    "completed",
+			StartTime: startTime.Format(time.RFC3339),
+			EndTime:   &endTime,
+			Result:    data,
+		},
+	})
 }
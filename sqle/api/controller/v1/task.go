@@ -50,28 +50,29 @@ type GetAuditTaskResV1 struct {
 }
 
 type AuditTaskResV1 struct {
-	Id                         uint            `json:"task_id"`
-	InstanceName               string          `json:"instance_name"`
-	InstanceDbType             string          `json:"instance_db_type"`
-	InstanceSchema             string          `json:"instance_schema" example:"db1"`
-	AuditLevel                 string          `json:"audit_level" enums:"normal,notice,warn,error,"`
-	Score                      int32           `json:"score"`
-	PassRate                   float64         `json:"pass_rate"`
-	Status                     string          `json:"status" enums:"initialized,audited,executing,exec_success,exec_failed,manually_executed"`
-	SQLSource                  string          `json:"sql_source" enums:"form_data,sql_file,mybatis_xml_file,audit_plan,zip_file,git_repository"`
-	ExecStartTime              *time.Time      `json:"exec_start_time,omitempty"`
-	ExecEndTime                *time.Time      `json:"exec_end_time,omitempty"`
-	FileOrderMethod            string          `json:"file_order_method,omitempty"`
-	ExecMode                   string          `json:"exec_mode,omitempty"`
-	EnableBackup               bool            `json:"enable_backup"`
-	BackupMaxRows              uint64          `json:"backup_max_rows,omitempty"`
-	BackupConflictWithInstance bool            `json:"backup_conflict_with_instance"` // 当数据源备份开启，工单备份关闭，则需要提示审核人工单备份策略与数据源备份策略不一致
-	ExecFailStage              string          `json:"exec_fail_stage,omitempty"`
-	ExecFailReason             string          `json:"exec_fail_reason,omitempty"`
-	ExecFailSQLCount           int             `json:"exec_fail_sql_count,omitempty"`
-	ExecFailSQLNumber          uint            `json:"exec_fail_sql_number,omitempty"`
-	ExecFailSQLID              uint            `json:"exec_fail_sql_id,omitempty"`
-	AuditFiles                 []AuditFileResp `json:"audit_files,omitempty"`
+	Id                         uint                  `json:"task_id"`
+	InstanceName               string                `json:"instance_name"`
+	InstanceDbType             string                `json:"instance_db_type"`
+	InstanceSchema             string                `json:"instance_schema" example:"db1"`
+	AuditLevel                 string                `json:"audit_level" enums:"normal,notice,warn,error,"`
+	Score                      int32                 `json:"score"`
+	ScoreBreakdown             *model.ScoreBreakdown `json:"score_breakdown,omitempty"`
+	PassRate                   float64               `json:"pass_rate"`
+	Status                     string                `json:"status" enums:"initialized,audited,executing,exec_success,exec_failed,manually_executed"`
+	SQLSource                  string                `json:"sql_source" enums:"form_data,sql_file,mybatis_xml_file,audit_plan,zip_file,git_repository"`
+	ExecStartTime              *time.Time            `json:"exec_start_time,omitempty"`
+	ExecEndTime                *time.Time            `json:"exec_end_time,omitempty"`
+	FileOrderMethod            string                `json:"file_order_method,omitempty"`
+	ExecMode                   string                `json:"exec_mode,omitempty"`
+	EnableBackup               bool                  `json:"enable_backup"`
+	BackupMaxRows              uint64                `json:"backup_max_rows,omitempty"`
+	BackupConflictWithInstance bool                  `json:"backup_conflict_with_instance"` // 当数据源备份开启，工单备份关闭，则需要提示审核人工单备份策略与数据源备份策略不一致
+	ExecFailStage              string                `json:"exec_fail_stage,omitempty"`
+	ExecFailReason             string                `json:"exec_fail_reason,omitempty"`
+	ExecFailSQLCount           int                   `json:"exec_fail_sql_count,omitempty"`
+	ExecFailSQLNumber          uint                  `json:"exec_fail_sql_number,omitempty"`
+	ExecFailSQLID              uint                  `json:"exec_fail_sql_id,omitempty"`
+	AuditFiles                 []AuditFileResp       `json:"audit_files,omitempty"`
 }
 
 type AuditFileResp struct {
@@ -86,6 +87,7 @@ func convertTaskToRes(ctx context.Context, task *model.Task) *AuditTaskResV1 {
 		InstanceSchema:             task.Schema,
 		AuditLevel:                 task.AuditLevel,
 		Score:                      task.Score,
+		ScoreBreakdown:             task.ScoreBreakdown,
 		PassRate:                   task.PassRate,
 		Status:                     task.Status,
 		SQLSource:                  task.SQLSource,
@@ -414,7 +416,7 @@ func CreateAndAuditTask(c echo.Context) error {
 		}
 	}
 	task.Instance = &tmpInst
-	task, err = server.GetSqled().AddTaskWaitResult(projectUid, fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
+	task, err = server.GetSqled().AddTaskWaitResult(c.Request().Context(), projectUid, fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
@@ -1132,7 +1134,7 @@ func AuditTaskGroupV1(c echo.Context) error {
 			continue
 		}
 
-		tasks[i], err = server.GetSqled().AddTaskWaitResult(projectId, fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
+		tasks[i], err = server.GetSqled().AddTaskWaitResult(c.Request().Context(), projectId, fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
 		if err != nil {
 			return controller.JSONBaseErrorReq(c, err)
 		}
@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/labstack/echo/v4"
+)
+
+type CreateUserDelegationReqV1 struct {
+	DelegateUserId string `json:"delegate_user_id" valid:"required"`
+	// ProjectName limits the delegation to the project, empty means all projects
+	ProjectName string `json:"project_name"`
+	// InstanceName limits the delegation to the workflows only on the instance, project_name is required with it
+	InstanceName string     `json:"instance_name"`
+	StartTime    *time.Time `json:"start_time" valid:"required"`
+	EndTime      *time.Time `json:"end_time" valid:"required"`
+	Reason       string     `json:"reason" valid:"max=255"`
+}
+
+// CreateUserDelegation
+// @Summary 添加审批委托
+// @Description delegate the workflow steps assigned to the current user to another user during a period, e.g. when the current user is out of office. The delegate can operate the steps on behalf of the current user and receives the notifications of the current user during the period.
+// @Accept json
+// @Id createUserDelegationV1
+// @Tags user_delegation
+// @Security ApiKeyAuth
+// @Param delegation body v1.CreateUserDelegationReqV1 true "create user delegation request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/user_delegations [post]
+func CreateUserDelegation(c echo.Context) error {
+	req := new(CreateUserDelegationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	userId := controller.GetUserID(c)
+	if req.DelegateUserId == userId {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("can not delegate to yourself")))
+	}
+	if !req.EndTime.After(*req.StartTime) {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("end time should be after start time")))
+	}
+	if !req.EndTime.After(time.Now()) {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("end time should be in the future")))
+	}
+	if _, err := dms.GetUser(c.Request().Context(), req.DelegateUserId, controller.GetDMSServerAddress()); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("delegate user is not exist: %v", err)))
+	}
+
+	delegation := &model.UserDelegation{
+		DelegatorId: userId,
+		DelegateId:  req.DelegateUserId,
+		StartAt:     *req.StartTime,
+		EndAt:       *req.EndTime,
+		Reason:      req.Reason,
+	}
+	if req.InstanceName != "" && req.ProjectName == "" {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("project name is required to limit the delegation to an instance")))
+	}
+	if req.ProjectName != "" {
+		projectUid, err := dms.GetProjectUIDByName(context.TODO(), req.ProjectName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		delegation.ProjectId = projectUid
+		if req.InstanceName != "" {
+			instance, exist, err := dms.GetInstanceInProjectByName(c.Request().Context(), projectUid, req.InstanceName)
+			if err != nil {
+				return controller.JSONBaseErrorReq(c, err)
+			}
+			if !exist {
+				return controller.JSONBaseErrorReq(c, ErrInstanceNotExist)
+			}
+			delegation.InstanceId = instance.GetIDStr()
+		}
+		// the delegation for all projects is checked when the delegate operates the workflow
+		if err := server.CheckDelegatePermissions(userId, req.DelegateUserId, projectUid, delegation.InstanceId); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+
+	s := model.GetStorage()
+	overlapped, err := s.IsUserDelegationOverlapped(delegation)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if overlapped {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist, fmt.Errorf("there is already a delegation of the same scope during the period")))
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(delegation))
+}
+
+type GetUserDelegationsReqV1 struct {
+	WithExpired bool   `json:"with_expired" query:"with_expired"`
+	PageIndex   uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize    uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type UserDelegationResV1 struct {
+	Id            uint      `json:"id"`
+	DelegatorName string    `json:"delegator_name"`
+	DelegateName  string    `json:"delegate_name"`
+	ProjectName   string    `json:"project_name"`
+	InstanceName  string    `json:"instance_name"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Reason        string    `json:"reason"`
+	IsActive      bool      `json:"is_active"`
+}
+
+type GetUserDelegationsResV1 struct {
+	controller.BaseRes
+	Data      []*UserDelegationResV1 `json:"data"`
+	TotalNums uint64                 `json:"total_nums"`
+}
+
+// GetUserDelegations
+// @Summary 获取审批委托列表
+// @Description get the delegations the current user delegates or is delegated
+// @Id getUserDelegationsV1
+// @Tags user_delegation
+// @Security ApiKeyAuth
+// @Param with_expired query bool false "include the expired delegations"
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetUserDelegationsResV1
+// @router /v1/user_delegations [get]
+func GetUserDelegations(c echo.Context) error {
+	req := new(GetUserDelegationsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	delegations, count, err := model.GetStorage().GetUserDelegationList(controller.GetUserID(c), req.WithExpired, req.PageIndex, req.PageSize)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	now := time.Now()
+	data := make([]*UserDelegationResV1, 0, len(delegations))
+	for _, d := range delegations {
+		res := &UserDelegationResV1{
+			Id:            d.ID,
+			DelegatorName: dms.GetUserNameWithDelTag(d.DelegatorId),
+			DelegateName:  dms.GetUserNameWithDelTag(d.DelegateId),
+			StartTime:     d.StartAt,
+			EndTime:       d.EndAt,
+			Reason:        d.Reason,
+			IsActive:      d.IsActive(now),
+		}
+		if d.ProjectId != "" {
+			project, err := dms.GetProjectByID(d.ProjectId)
+			if err != nil {
+				return controller.JSONBaseErrorReq(c, err)
+			}
+			res.ProjectName = project.Name
+		}
+		if d.InstanceId != "" {
+			res.InstanceName = dms.GetInstancesByIdWithoutError(d.InstanceId).Name
+		}
+		data = append(data, res)
+	}
+	return c.JSON(http.StatusOK, &GetUserDelegationsResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: count,
+	})
+}
+
+// DeleteUserDelegation
+// @Summary 删除审批委托
+// @Description delete a delegation of the current user, the delegate can not operate the steps of the current user any more
+// @Id deleteUserDelegationV1
+// @Tags user_delegation
+// @Security ApiKeyAuth
+// @Param delegation_id path string true "delegation id"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/user_delegations/{delegation_id}/ [delete]
+func DeleteUserDelegation(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("delegation_id"), 10, 64)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("delegation id %s is invalid", c.Param("delegation_id"))))
+	}
+	s := model.GetStorage()
+	delegation, exist, err := s.GetUserDelegationById(uint(id))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist || delegation.DelegatorId != controller.GetUserID(c) {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("delegation is not exist")))
+	}
+	return controller.JSONBaseErrorReq(c, s.Delete(delegation))
+}
@@ -479,7 +479,7 @@ func PrepareForTaskExecution(c echo.Context, projectID string, workflow *model.W
 
 		for _, u := range strings.Split(record.ExecutionAssignees, ",") {
 			if u == user.GetIDStr() {
-				return nil
+				return server.CheckWorkflowExecuteDuty(workflow, user)
 			}
 		}
 	}
@@ -658,11 +658,6 @@ func CheckWorkflowCreationPrerequisites(c echo.Context, projectName string, task
 	for _, instance := range instancesOfWorkflowInProject {
 		projectInstanceMap[instance.ID] = instance
 	}
-	workflowTemplate, err := s.ResolveWorkflowTemplateForCreate(model.ProjectUID(projectUid), workflowTemplateId)
-	if err != nil {
-		return nil, err
-	}
-	templateID := workflowTemplate.ID
 	// check tasks instance
 	for _, task := range tasks {
 		if instance, ok := projectInstanceMap[task.InstanceId]; ok {
@@ -716,6 +711,28 @@ func CheckWorkflowCreationPrerequisites(c echo.Context, projectName string, task
 		return nil, errors.New(errors.DataConflict, fmt.Errorf("task has been used in other workflow"))
 	}
 
+	// the workflow template is selected by the route rules of the project if it is not specified
+	var workflowTemplate *model.WorkflowTemplate
+	if workflowTemplateId != nil && *workflowTemplateId != 0 {
+		workflowTemplate, err = s.ResolveWorkflowTemplateForCreate(model.ProjectUID(projectUid), workflowTemplateId)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		route, err := server.RouteWorkflowTemplate(log.NewEntry(), model.ProjectUID(projectUid), tasks, opsTypeUID)
+		if err != nil {
+			return nil, err
+		}
+		if route.Rule != nil {
+			log.NewEntry().Infof("workflow template %s is selected by route rule %s, reasons: %v", route.Template.Name, route.Rule.Name, route.Evaluations[len(route.Evaluations)-1].Reasons)
+		}
+		workflowTemplate = route.Template
+	}
+	if err := CheckTasksScoreCanCommit(projectUid, workflowTemplate, tasks); err != nil {
+		return nil, err
+	}
+	templateID := workflowTemplate.ID
+
 	stepTemplates, err := s.GetWorkflowStepsByTemplateId(workflowTemplate.ID)
 	if err != nil {
 		return nil, err
@@ -769,6 +786,38 @@ func CheckWorkflowCanCommit(template *model.WorkflowTemplate, tasks []*model.Tas
 	return nil
 }
 
+// CheckTasksScoreCanCommit gates the submission of the workflow by the scoring policy of the project. The
+// workflows of the projects without an enabled scoring policy are not gated.
+func CheckTasksScoreCanCommit(projectUid string, template *model.WorkflowTemplate, tasks []*model.Task) error {
+	policy, exist, err := model.GetStorage().GetScoringPolicyByProjectId(projectUid)
+	if err != nil {
+		return err
+	}
+	if !exist || !policy.Enabled {
+		return nil
+	}
+	return checkTasksScoreCanCommit(policy, template, tasks)
+}
+
+// checkTasksScoreCanCommit forbids submitting if the audit level of a task is higher than the allowable
+// submission level of the template, a hard-fail rule is hit or the score is lower than the min submit score.
+func checkTasksScoreCanCommit(policy *model.ScoringPolicy, template *model.WorkflowTemplate, tasks []*model.Task) error {
+	if err := CheckWorkflowCanCommit(template, tasks); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if task.ScoreBreakdown.IsHardFailed() {
+			return errors.New(errors.DataInvalid,
+				fmt.Errorf("the audit result hits the hard-fail rules %v of the scoring policy, please modify it before submitting. taskId=%v", task.ScoreBreakdown.HardFailRules, task.ID))
+		}
+		if policy.MinSubmitScore > 0 && task.Score < policy.MinSubmitScore {
+			return errors.New(errors.DataInvalid,
+				fmt.Errorf("the audit score %v is lower than the allowable submission score(%v), please modify it before submitting. taskId=%v", task.Score, policy.MinSubmitScore, task.ID))
+		}
+	}
+	return nil
+}
+
 type GetWorkflowsReqV1 struct {
 	FilterSubject                   string                `json:"filter_subject" query:"filter_subject"`
 	FilterWorkflowID                string                `json:"filter_workflow_id" query:"filter_workflow_id"`
@@ -1640,8 +1689,9 @@ CheckReExecSqlIds:
 			return errors.New(errors.DataInvalid, fmt.Errorf("execute sql id %d not found in task", sqlId))
 		}
 
-		if execSql.ExecStatus != model.SQLExecuteStatusFailed && execSql.ExecStatus != model.SQLExecuteStatusInitialized {
-			return errors.New(errors.DataInvalid, fmt.Errorf("execute sql id %d status is %s, only failed or initialized sql can be re-executed", sqlId, execSql.ExecStatus))
+		// 暂停的分批执行 SQL 重新上线时从最后完成的主键范围继续
+		if execSql.ExecStatus != model.SQLExecuteStatusFailed && execSql.ExecStatus != model.SQLExecuteStatusInitialized && execSql.ExecStatus != model.SQLExecuteStatusPaused {
+			return errors.New(errors.DataInvalid, fmt.Errorf("execute sql id %d status is %s, only failed, paused or initialized sql can be re-executed", sqlId, execSql.ExecStatus))
 		}
 	}
 
@@ -2362,7 +2412,7 @@ func auditTaskGroup(c echo.Context, s *model.Storage, tasks []*model.Task, req *
 			continue
 		}
 
-		tasks[i], err = server.GetSqled().AddTaskWaitResult(projectId, fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
+		tasks[i], err = server.GetSqled().AddTaskWaitResult(c.Request().Context(), projectId, fmt.Sprintf("%d", task.ID), server.ActionTypeAudit)
 		if err != nil {
 			return err
 		}
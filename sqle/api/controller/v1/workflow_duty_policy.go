@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/labstack/echo/v4"
+)
+
+type WorkflowDutyPolicyV1 struct {
+	// ForbidCreatorApprove forbids the creator of a workflow to approve it
+	ForbidCreatorApprove bool `json:"forbid_creator_approve"`
+	// ForbidApproverExecute forbids the approvers of a workflow to execute it
+	ForbidApproverExecute bool `json:"forbid_approver_execute"`
+	// MinDistinctParticipants is the least number of the distinct creator, approvers and executor, 0 means disabled
+	MinDistinctParticipants uint `json:"min_distinct_participants"`
+	// MinParticipantsAuditLevel limits min_distinct_participants to the workflows whose audit level is not lower than it, empty means all workflows
+	MinParticipantsAuditLevel string `json:"min_participants_audit_level" enums:"normal,notice,warn,error" valid:"omitempty,oneof=normal notice warn error"`
+}
+
+type GetWorkflowDutyPolicyResV1 struct {
+	controller.BaseRes
+	Data *WorkflowDutyPolicyResV1 `json:"data"`
+}
+
+type WorkflowDutyPolicyResV1 struct {
+	WorkflowTemplateId   uint   `json:"workflow_template_id"`
+	WorkflowTemplateName string `json:"workflow_template_name"`
+	WorkflowDutyPolicyV1
+}
+
+// GetWorkflowDutyPolicy
+// @Summary 获取审批流程模板的职责分离策略
+// @Description get the separation of duties policy of a workflow template, the default workflow template of the project is used if workflow_template_id is empty
+// @Id getWorkflowDutyPolicyV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param workflow_template_id query string false "workflow template id"
+// @Success 200 {object} v1.GetWorkflowDutyPolicyResV1
+// @router /v1/projects/{project_name}/workflow_duty_policy [get]
+func GetWorkflowDutyPolicy(c echo.Context) error {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	template, err := getWorkflowTemplateForSLA(projectUid, c.QueryParam("workflow_template_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowDutyPolicyResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &WorkflowDutyPolicyResV1{
+			WorkflowTemplateId:   template.ID,
+			WorkflowTemplateName: template.Name,
+			WorkflowDutyPolicyV1: WorkflowDutyPolicyV1{
+				ForbidCreatorApprove:      template.ForbidCreatorApprove,
+				ForbidApproverExecute:     template.ForbidApproverExecute,
+				MinDistinctParticipants:   template.MinDistinctParticipants,
+				MinParticipantsAuditLevel: template.MinParticipantsAuditLevel,
+			},
+		},
+	})
+}
+
+type UpdateWorkflowDutyPolicyReqV1 struct {
+	WorkflowTemplateId uint `json:"workflow_template_id"`
+	WorkflowDutyPolicyV1
+}
+
+// UpdateWorkflowDutyPolicy
+// @Summary 更新审批流程模板的职责分离策略
+// @Description update the separation of duties policy of a workflow template, the default workflow template of the project is used if workflow_template_id is empty. The policy is checked when the workflows are approved or executed.
+// @Accept json
+// @Id updateWorkflowDutyPolicyV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param policy body v1.UpdateWorkflowDutyPolicyReqV1 true "update workflow duty policy request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflow_duty_policy [patch]
+func UpdateWorkflowDutyPolicy(c echo.Context) error {
+	req := new(UpdateWorkflowDutyPolicyReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	templateId := ""
+	if req.WorkflowTemplateId != 0 {
+		templateId = strconv.FormatUint(uint64(req.WorkflowTemplateId), 10)
+	}
+	template, err := getWorkflowTemplateForSLA(projectUid, templateId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if template.WorkflowType != model.WorkflowTemplateTypeWorkflow {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("the duty policy only applies to the sql workflow templates")))
+	}
+	template.ForbidCreatorApprove = req.ForbidCreatorApprove
+	template.ForbidApproverExecute = req.ForbidApproverExecute
+	template.MinDistinctParticipants = req.MinDistinctParticipants
+	template.MinParticipantsAuditLevel = req.MinParticipantsAuditLevel
+	return controller.JSONBaseErrorReq(c, model.GetStorage().UpdateWorkflowTemplateDutyPolicy(template))
+}
@@ -0,0 +1,309 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/labstack/echo/v4"
+)
+
+type WorkflowStepSLAV1 struct {
+	Number uint `json:"number" valid:"required"`
+	// RemindIntervalMinute reminds the assignees every interval while the step is pending, 0 means disabled
+	RemindIntervalMinute uint `json:"remind_interval_minute"`
+	// EscalateAfterMinute escalates the step once it is pending longer than the duration, 0 means disabled
+	EscalateAfterMinute    uint     `json:"escalate_after_minute"`
+	EscalateUserIdList     []string `json:"escalate_user_id_list"`
+	EscalateMemberGroupUid string   `json:"escalate_member_group_uid"`
+	EscalateMode           string   `json:"escalate_mode" enums:"add,reassign" valid:"omitempty,oneof=add reassign"`
+}
+
+type WorkflowStepSLAResV1 struct {
+	WorkflowStepSLAV1
+	Type string `json:"type" enums:"sql_review,sql_execute"`
+	Desc string `json:"desc"`
+}
+
+type GetWorkflowStepSLAsResV1 struct {
+	controller.BaseRes
+	Data *WorkflowStepSLAsResDataV1 `json:"data"`
+}
+
+type WorkflowStepSLAsResDataV1 struct {
+	WorkflowTemplateId   uint                    `json:"workflow_template_id"`
+	WorkflowTemplateName string                  `json:"workflow_template_name"`
+	Steps                []*WorkflowStepSLAResV1 `json:"steps"`
+}
+
+// GetWorkflowStepSLAs
+// @Summary 获取审批流程模板各步骤的SLA配置
+// @Description get the sla of the steps of a workflow template, the default workflow template of the project is used if workflow_template_id is empty
+// @Id getWorkflowStepSLAsV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param workflow_template_id query string false "workflow template id"
+// @Success 200 {object} v1.GetWorkflowStepSLAsResV1
+// @router /v1/projects/{project_name}/workflow_step_slas [get]
+func GetWorkflowStepSLAs(c echo.Context) error {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	template, err := getWorkflowTemplateForSLA(projectUid, c.QueryParam("workflow_template_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	steps, err := model.GetStorage().GetWorkflowStepsByTemplateId(template.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := &WorkflowStepSLAsResDataV1{
+		WorkflowTemplateId:   template.ID,
+		WorkflowTemplateName: template.Name,
+		Steps:                make([]*WorkflowStepSLAResV1, 0, len(steps)),
+	}
+	for _, step := range steps {
+		userIds := []string{}
+		if step.SLAEscalateUsers != "" {
+			userIds = strings.Split(step.SLAEscalateUsers, ",")
+		}
+		data.Steps = append(data.Steps, &WorkflowStepSLAResV1{
+			WorkflowStepSLAV1: WorkflowStepSLAV1{
+				Number:                 step.Number,
+				RemindIntervalMinute:   step.SLARemindIntervalMinute,
+				EscalateAfterMinute:    step.SLAEscalateAfterMinute,
+				EscalateUserIdList:     userIds,
+				EscalateMemberGroupUid: step.SLAEscalateMemberGroupUid,
+				EscalateMode:           step.SLAEscalateMode,
+			},
+			Type: step.Typ,
+			Desc: step.Desc,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowStepSLAsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type UpdateWorkflowStepSLAsReqV1 struct {
+	WorkflowTemplateId uint                 `json:"workflow_template_id"`
+	Steps              []*WorkflowStepSLAV1 `json:"steps" valid:"required,dive,required"`
+}
+
+// UpdateWorkflowStepSLAs
+// @Summary 更新审批流程模板各步骤的SLA配置
+// @Description update the sla of the steps of a workflow template, the default workflow template of the project is used if workflow_template_id is empty. The steps not in the request are unchanged.
+// @Accept json
+// @Id updateWorkflowStepSLAsV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param steps body v1.UpdateWorkflowStepSLAsReqV1 true "update workflow step sla request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflow_step_slas [patch]
+func UpdateWorkflowStepSLAs(c echo.Context) error {
+	req := new(UpdateWorkflowStepSLAsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	templateId := ""
+	if req.WorkflowTemplateId != 0 {
+		templateId = strconv.FormatUint(uint64(req.WorkflowTemplateId), 10)
+	}
+	template, err := getWorkflowTemplateForSLA(projectUid, templateId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	steps, err := s.GetWorkflowStepsByTemplateId(template.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	stepMap := make(map[uint]*model.WorkflowStepTemplate, len(steps))
+	for _, step := range steps {
+		stepMap[step.Number] = step
+	}
+
+	updated := make([]*model.WorkflowStepTemplate, 0, len(req.Steps))
+	for _, reqStep := range req.Steps {
+		step, ok := stepMap[reqStep.Number]
+		if !ok {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("step %d is not exist in workflow template %s", reqStep.Number, template.Name)))
+		}
+		hasTarget := len(reqStep.EscalateUserIdList) > 0 || reqStep.EscalateMemberGroupUid != ""
+		if reqStep.EscalateMode == model.SLAEscalateModeReassign && step.Typ != model.WorkflowStepTypeSQLReview {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("step %d can not be reassigned, only the review steps can be reassigned", step.Number)))
+		}
+		if reqStep.EscalateAfterMinute > 0 && !hasTarget {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("the escalation target of step %d is required", step.Number)))
+		}
+		if reqStep.RemindIntervalMinute > 0 && reqStep.EscalateAfterMinute > 0 && reqStep.RemindIntervalMinute >= reqStep.EscalateAfterMinute {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("the remind interval of step %d should be less than its escalation duration", step.Number)))
+		}
+		step.SLARemindIntervalMinute = reqStep.RemindIntervalMinute
+		step.SLAEscalateAfterMinute = reqStep.EscalateAfterMinute
+		step.SLAEscalateUsers = strings.Join(reqStep.EscalateUserIdList, ",")
+		step.SLAEscalateMemberGroupUid = reqStep.EscalateMemberGroupUid
+		step.SLAEscalateMode = reqStep.EscalateMode
+		if step.SLAEscalateAfterMinute > 0 && step.SLAEscalateMode == "" {
+			step.SLAEscalateMode = model.SLAEscalateModeAdd
+		}
+		updated = append(updated, step)
+	}
+	for _, step := range updated {
+		if err := s.UpdateWorkflowStepTemplateSLA(step); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
+// getWorkflowTemplateForSLA gets the workflow template by id, or the default workflow template of the project if
+// the id is empty.
+func getWorkflowTemplateForSLA(projectUid, templateId string) (*model.WorkflowTemplate, error) {
+	s := model.GetStorage()
+	var template *model.WorkflowTemplate
+	var exist bool
+	var err error
+	if templateId == "" {
+		template, exist, err = s.GetWorkflowTemplateByProjectIdAndType(model.ProjectUID(projectUid), model.WorkflowTemplateTypeWorkflow)
+	} else {
+		id, parseErr := strconv.ParseUint(templateId, 10, 64)
+		if parseErr != nil {
+			return nil, errors.New(errors.DataInvalid, fmt.Errorf("workflow template id %s is invalid", templateId))
+		}
+		template, exist, err = s.GetWorkflowTemplateByProjectIdAndId(model.ProjectUID(projectUid), uint(id))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("workflow template is not exist"))
+	}
+	return template, nil
+}
+
+type GetWorkflowSLABreachesReqV1 struct {
+	FilterWorkflowId     string `json:"filter_workflow_id" query:"filter_workflow_id"`
+	FilterBreachedAtFrom string `json:"filter_breached_at_from" query:"filter_breached_at_from"`
+	FilterBreachedAtTo   string `json:"filter_breached_at_to" query:"filter_breached_at_to"`
+	PageIndex            uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize             uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type WorkflowSLABreachResV1 struct {
+	WorkflowId        string     `json:"workflow_id"`
+	WorkflowSubject   string     `json:"workflow_subject"`
+	StepNumber        uint       `json:"step_number"`
+	StepStartAt       *time.Time `json:"step_start_at"`
+	BreachedAt        *time.Time `json:"breached_at"`
+	RemindCount       uint       `json:"remind_count"`
+	OriginalAssignees []string   `json:"original_assignees"`
+	EscalatedTo       []string   `json:"escalated_to"`
+	EscalateMode      string     `json:"escalate_mode" enums:"add,reassign"`
+	// OperateAt and OperationUser are empty if the step is still pending
+	OperateAt     *time.Time `json:"operate_at"`
+	OperationUser string     `json:"operation_user"`
+}
+
+type GetWorkflowSLABreachesResV1 struct {
+	controller.BaseRes
+	Data      []*WorkflowSLABreachResV1 `json:"data"`
+	TotalNums uint64                    `json:"total_nums"`
+}
+
+// GetWorkflowSLABreaches
+// @Summary 获取审批SLA超时记录
+// @Description get the workflow steps escalated for breaching their sla
+// @Id getWorkflowSLABreachesV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param filter_workflow_id query string false "filter workflow id"
+// @Param filter_breached_at_from query string false "filter breached at from, in RFC3339 format"
+// @Param filter_breached_at_to query string false "filter breached at to, in RFC3339 format"
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetWorkflowSLABreachesResV1
+// @router /v1/projects/{project_name}/workflow_sla_breaches [get]
+func GetWorkflowSLABreaches(c echo.Context) error {
+	req := new(GetWorkflowSLABreachesReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	breachedFrom, err := parseSLAFilterTime(req.FilterBreachedAtFrom)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	breachedTo, err := parseSLAFilterTime(req.FilterBreachedAtTo)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	breaches, count, err := model.GetStorage().GetWorkflowSLABreachList(projectUid, req.FilterWorkflowId, breachedFrom, breachedTo, req.PageIndex, req.PageSize)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*WorkflowSLABreachResV1, 0, len(breaches))
+	for _, b := range breaches {
+		res := &WorkflowSLABreachResV1{
+			WorkflowId:        b.WorkflowId,
+			WorkflowSubject:   b.Subject,
+			StepNumber:        b.StepNumber,
+			StepStartAt:       b.StepStartAt,
+			BreachedAt:        b.BreachedAt,
+			RemindCount:       b.RemindCount,
+			OriginalAssignees: convertUserIdsToNames(b.OriginalAssignees),
+			EscalatedTo:       convertUserIdsToNames(b.EscalatedTo),
+			EscalateMode:      b.EscalateMode,
+			OperateAt:         b.OperateAt,
+		}
+		if b.OperationUserId != "" {
+			res.OperationUser = dms.GetUserNameWithDelTag(b.OperationUserId)
+		}
+		data = append(data, res)
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowSLABreachesResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: count,
+	})
+}
+
+func parseSLAFilterTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("time %s is invalid, it should be in RFC3339 format", value))
+	}
+	return &t, nil
+}
+
+func convertUserIdsToNames(ids string) []string {
+	names := []string{}
+	for _, id := range strings.Split(ids, ",") {
+		if id != "" {
+			names = append(names, dms.GetUserNameWithDelTag(id))
+		}
+	}
+	return names
+}
@@ -0,0 +1,344 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/actiontech/sqle/sqle/utils"
+	"github.com/labstack/echo/v4"
+)
+
+type WorkflowTemplateRouteRuleV1 struct {
+	Name               string `json:"name" valid:"required,max=255"`
+	Priority           int    `json:"priority"`
+	Enabled            bool   `json:"enabled"`
+	WorkflowTemplateId uint   `json:"workflow_template_id" valid:"required"`
+	// EnvironmentTags matches if the environment tags of all the instances of the workflow are in it
+	EnvironmentTags []string `json:"environment_tags"`
+	// AnySQLTypes matches if the workflow has any statement of the types
+	AnySQLTypes []string `json:"any_sql_types" enums:"dml,ddl,dql,dcl"`
+	// OnlySQLTypes matches if the workflow only has the statements of the types
+	OnlySQLTypes  []string `json:"only_sql_types" enums:"dml,ddl,dql,dcl"`
+	MinAuditLevel string   `json:"min_audit_level" enums:"normal,notice,warn,error" valid:"omitempty,oneof=normal notice warn error"`
+	MaxAuditLevel string   `json:"max_audit_level" enums:"normal,notice,warn,error" valid:"omitempty,oneof=normal notice warn error"`
+	// MinAffectedRows and MaxAffectedRows limit the total estimated affected rows of the DML statements of the workflow
+	MinAffectedRows *int64   `json:"min_affected_rows"`
+	MaxAffectedRows *int64   `json:"max_affected_rows"`
+	OpsTypeUIDs     []string `json:"ops_type_uids"`
+}
+
+type WorkflowTemplateRouteRuleResV1 struct {
+	Id                   uint   `json:"id"`
+	WorkflowTemplateName string `json:"workflow_template_name"`
+	WorkflowTemplateRouteRuleV1
+}
+
+type GetWorkflowTemplateRouteRulesResV1 struct {
+	controller.BaseRes
+	Data []*WorkflowTemplateRouteRuleResV1 `json:"data"`
+}
+
+// GetWorkflowTemplateRouteRules
+// @Summary 获取审批流程模板路由规则列表
+// @Description get the route rules to select the workflow template of the new workflows, the enabled rules are evaluated by priority in ascending order and the default workflow template is used if none matches
+// @Id getWorkflowTemplateRouteRulesV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Success 200 {object} v1.GetWorkflowTemplateRouteRulesResV1
+// @router /v1/projects/{project_name}/workflow_template_route_rules [get]
+func GetWorkflowTemplateRouteRules(c echo.Context) error {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	rules, err := s.GetWorkflowTemplateRouteRules(model.ProjectUID(projectUid))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*WorkflowTemplateRouteRuleResV1, 0, len(rules))
+	for _, rule := range rules {
+		templateName := ""
+		template, exist, err := s.GetWorkflowTemplateByProjectIdAndId(model.ProjectUID(projectUid), rule.WorkflowTemplateId)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if exist {
+			templateName = template.Name
+		}
+		data = append(data, convertWorkflowTemplateRouteRuleToRes(rule, templateName))
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowTemplateRouteRulesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func convertWorkflowTemplateRouteRuleToRes(rule *model.WorkflowTemplateRouteRule, templateName string) *WorkflowTemplateRouteRuleResV1 {
+	return &WorkflowTemplateRouteRuleResV1{
+		Id:                   rule.ID,
+		WorkflowTemplateName: templateName,
+		WorkflowTemplateRouteRuleV1: WorkflowTemplateRouteRuleV1{
+			Name:               rule.Name,
+			Priority:           rule.Priority,
+			Enabled:            rule.Enabled,
+			WorkflowTemplateId: rule.WorkflowTemplateId,
+			EnvironmentTags:    rule.EnvironmentTags,
+			AnySQLTypes:        rule.AnySQLTypes,
+			OnlySQLTypes:       rule.OnlySQLTypes,
+			MinAuditLevel:      rule.MinAuditLevel,
+			MaxAuditLevel:      rule.MaxAuditLevel,
+			MinAffectedRows:    rule.MinAffectedRows,
+			MaxAffectedRows:    rule.MaxAffectedRows,
+			OpsTypeUIDs:        rule.OpsTypeUIDs,
+		},
+	}
+}
+
+type CreateWorkflowTemplateRouteRuleReqV1 struct {
+	WorkflowTemplateRouteRuleV1
+}
+
+// CreateWorkflowTemplateRouteRule
+// @Summary 添加审批流程模板路由规则
+// @Description create a route rule to select the workflow template of the new workflows whose attributes match all its conditions, an empty condition always matches
+// @Accept json
+// @Id createWorkflowTemplateRouteRuleV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule body v1.CreateWorkflowTemplateRouteRuleReqV1 true "create workflow template route rule request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflow_template_route_rules [post]
+func CreateWorkflowTemplateRouteRule(c echo.Context) error {
+	req := new(CreateWorkflowTemplateRouteRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule := &model.WorkflowTemplateRouteRule{ProjectId: model.ProjectUID(projectUid)}
+	if err := setWorkflowTemplateRouteRule(rule, &req.WorkflowTemplateRouteRuleV1); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Save(rule))
+}
+
+type UpdateWorkflowTemplateRouteRuleReqV1 struct {
+	WorkflowTemplateRouteRuleV1
+}
+
+// UpdateWorkflowTemplateRouteRule
+// @Summary 更新审批流程模板路由规则
+// @Description update a route rule of the workflow templates, the workflows created are not affected
+// @Accept json
+// @Id updateWorkflowTemplateRouteRuleV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule_id path string true "route rule id"
+// @Param rule body v1.UpdateWorkflowTemplateRouteRuleReqV1 true "update workflow template route rule request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflow_template_route_rules/{rule_id}/ [patch]
+func UpdateWorkflowTemplateRouteRule(c echo.Context) error {
+	req := new(UpdateWorkflowTemplateRouteRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule, err := getWorkflowTemplateRouteRule(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := setWorkflowTemplateRouteRule(rule, &req.WorkflowTemplateRouteRuleV1); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Save(rule))
+}
+
+// DeleteWorkflowTemplateRouteRule
+// @Summary 删除审批流程模板路由规则
+// @Description delete a route rule of the workflow templates
+// @Id deleteWorkflowTemplateRouteRuleV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param rule_id path string true "route rule id"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflow_template_route_rules/{rule_id}/ [delete]
+func DeleteWorkflowTemplateRouteRule(c echo.Context) error {
+	rule, err := getWorkflowTemplateRouteRule(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Delete(rule))
+}
+
+func getWorkflowTemplateRouteRule(c echo.Context) (*model.WorkflowTemplateRouteRule, error) {
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(c.Param("rule_id"), 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("rule id %s is invalid", c.Param("rule_id")))
+	}
+	rule, exist, err := model.GetStorage().GetWorkflowTemplateRouteRuleById(model.ProjectUID(projectUid), uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("route rule is not exist"))
+	}
+	return rule, nil
+}
+
+func setWorkflowTemplateRouteRule(rule *model.WorkflowTemplateRouteRule, req *WorkflowTemplateRouteRuleV1) error {
+	template, exist, err := model.GetStorage().GetWorkflowTemplateByProjectIdAndId(rule.ProjectId, req.WorkflowTemplateId)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		template = nil
+	}
+	if err := model.ValidateWorkflowTemplateForCreate(template, rule.ProjectId); err != nil {
+		return err
+	}
+	rule.Name = req.Name
+	rule.Priority = req.Priority
+	rule.Enabled = req.Enabled
+	rule.WorkflowTemplateId = req.WorkflowTemplateId
+	rule.EnvironmentTags = req.EnvironmentTags
+	rule.AnySQLTypes = req.AnySQLTypes
+	rule.OnlySQLTypes = req.OnlySQLTypes
+	rule.MinAuditLevel = req.MinAuditLevel
+	rule.MaxAuditLevel = req.MaxAuditLevel
+	rule.MinAffectedRows = req.MinAffectedRows
+	rule.MaxAffectedRows = req.MaxAffectedRows
+	rule.OpsTypeUIDs = req.OpsTypeUIDs
+	if err := server.ValidateWorkflowTemplateRouteRule(rule); err != nil {
+		return errors.New(errors.DataInvalid, err)
+	}
+	return nil
+}
+
+type EvaluateWorkflowTemplateRouteReqV1 struct {
+	TaskIds    []uint `json:"task_ids" form:"task_ids" valid:"required"`
+	OpsTypeUID string `json:"ops_type_uid"`
+}
+
+type WorkflowTemplateRouteRuleEvaluationV1 struct {
+	RuleId   uint     `json:"rule_id"`
+	RuleName string   `json:"rule_name"`
+	Matched  bool     `json:"matched"`
+	Reasons  []string `json:"reasons"`
+}
+
+type WorkflowTemplateRouteResV1 struct {
+	WorkflowTemplateId   uint   `json:"workflow_template_id"`
+	WorkflowTemplateName string `json:"workflow_template_name"`
+	// MatchedRuleName is empty if the default workflow template is selected
+	MatchedRuleName string                                   `json:"matched_rule_name"`
+	EnvironmentTags []string                                 `json:"environment_tags"`
+	SQLTypes        []string                                 `json:"sql_types"`
+	AuditLevel      string                                   `json:"audit_level"`
+	AffectedRows    *int64                                   `json:"affected_rows"`
+	AffectedRowsErr string                                   `json:"affected_rows_err"`
+	Evaluations     []*WorkflowTemplateRouteRuleEvaluationV1 `json:"evaluations"`
+}
+
+type EvaluateWorkflowTemplateRouteResV1 struct {
+	controller.BaseRes
+	Data *WorkflowTemplateRouteResV1 `json:"data"`
+}
+
+// EvaluateWorkflowTemplateRoute
+// @Summary 预览审批流程模板路由结果
+// @Description explain which workflow template would be selected for a workflow of the audited tasks and why, nothing is created
+// @Accept json
+// @Id evaluateWorkflowTemplateRouteV1
+// @Tags workflow
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param req body v1.EvaluateWorkflowTemplateRouteReqV1 true "evaluate workflow template route request"
+// @Success 200 {object} v1.EvaluateWorkflowTemplateRouteResV1
+// @router /v1/projects/{project_name}/workflow_template_route_rules/evaluate [post]
+func EvaluateWorkflowTemplateRoute(c echo.Context) error {
+	req := new(EvaluateWorkflowTemplateRouteReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetProjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	tasks, foundAllTasks, err := model.GetStorage().GetTasksByIds(utils.RemoveDuplicateUint(req.TaskIds))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !foundAllTasks {
+		return controller.JSONBaseErrorReq(c, errors.NewTaskNoExistOrNoAccessErr())
+	}
+	instanceIds := make([]uint64, 0, len(tasks))
+	for _, task := range tasks {
+		if strconv.FormatUint(task.CreateUserId, 10) != controller.GetUserID(c) {
+			return controller.JSONBaseErrorReq(c, errors.NewTaskNoExistOrNoAccessErr())
+		}
+		instanceIds = append(instanceIds, task.InstanceId)
+	}
+	instances, err := dms.GetInstancesInProjectByIds(c.Request().Context(), projectUid, instanceIds)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	instanceMap := map[uint64]*model.Instance{}
+	for _, instance := range instances {
+		instanceMap[instance.ID] = instance
+	}
+	for _, task := range tasks {
+		instance, ok := instanceMap[task.InstanceId]
+		if !ok {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("instance is not in project. taskId=%v", task.ID)))
+		}
+		task.Instance = instance
+	}
+
+	route, err := server.RouteWorkflowTemplate(log.NewEntry(), model.ProjectUID(projectUid), tasks, req.OpsTypeUID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := &WorkflowTemplateRouteResV1{
+		WorkflowTemplateId:   route.Template.ID,
+		WorkflowTemplateName: route.Template.Name,
+		EnvironmentTags:      route.Attributes.EnvironmentTags,
+		SQLTypes:             route.Attributes.SQLTypes,
+		AuditLevel:           route.Attributes.AuditLevel,
+		AffectedRows:         route.Attributes.AffectedRows,
+		AffectedRowsErr:      route.Attributes.AffectedRowsErr,
+		Evaluations:          make([]*WorkflowTemplateRouteRuleEvaluationV1, 0, len(route.Evaluations)),
+	}
+	if route.Rule != nil {
+		data.MatchedRuleName = route.Rule.Name
+	}
+	for _, evaluation := range route.Evaluations {
+		data.Evaluations = append(data.Evaluations, &WorkflowTemplateRouteRuleEvaluationV1{
+			RuleId:   evaluation.Rule.ID,
+			RuleName: evaluation.Rule.Name,
+			Matched:  evaluation.Matched,
+			Reasons:  evaluation.Reasons,
+		})
+	}
+	return c.JSON(http.StatusOK, &EvaluateWorkflowTemplateRouteResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckWorkflowCanCommit(t *testing.T) {
+	template := &model.WorkflowTemplate{AllowSubmitWhenLessAuditLevel: string(driverV2.RuleLevelWarn)}
+	assert.NoError(t, CheckWorkflowCanCommit(template, []*model.Task{
+		{AuditLevel: string(driverV2.RuleLevelWarn)},
+	}))
+	assert.Error(t, CheckWorkflowCanCommit(template, []*model.Task{
+		{AuditLevel: string(driverV2.RuleLevelError)},
+	}))
+}
+
+func TestCheckTasksScoreCanCommit(t *testing.T) {
+	policy := &model.ScoringPolicy{Enabled: true, BaseScore: 100, MinSubmitScore: 60}
+	template := &model.WorkflowTemplate{AllowSubmitWhenLessAuditLevel: string(driverV2.RuleLevelWarn)}
+	assert.NoError(t, checkTasksScoreCanCommit(policy, template, []*model.Task{
+		{AuditLevel: string(driverV2.RuleLevelWarn), Score: 80, ScoreBreakdown: &model.ScoreBreakdown{Policy: model.ScorePolicyProject}},
+	}))
+	// the audit level is higher than the allowable submission level of the template
+	assert.Error(t, checkTasksScoreCanCommit(policy, template, []*model.Task{
+		{AuditLevel: string(driverV2.RuleLevelError), Score: 80},
+	}))
+	// the score is lower than the min submit score
+	assert.Error(t, checkTasksScoreCanCommit(policy, template, []*model.Task{
+		{AuditLevel: string(driverV2.RuleLevelNotice), Score: 59},
+	}))
+
+	// the hard-fail rules forbid submitting even if the audit level is allowed
+	template.AllowSubmitWhenLessAuditLevel = string(driverV2.RuleLevelError)
+	assert.Error(t, checkTasksScoreCanCommit(policy, template, []*model.Task{
+		{AuditLevel: string(driverV2.RuleLevelNotice), Score: 80, ScoreBreakdown: &model.ScoreBreakdown{HardFailRules: []string{"ddl_check_pk_not_exist"}}},
+	}))
+}
+
+func TestCheckTasksScoreCanCommitWithoutPolicy(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	model.InitMockStorage(mockDB)
+	mock.ExpectQuery("SELECT \\* FROM `scoring_policies`").WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// the projects without an enabled scoring policy are not gated
+	template := &model.WorkflowTemplate{AllowSubmitWhenLessAuditLevel: string(driverV2.RuleLevelWarn)}
+	assert.NoError(t, CheckTasksScoreCanCommit("1", template, []*model.Task{
+		{AuditLevel: string(driverV2.RuleLevelError), Score: 10},
+	}))
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -72,7 +72,7 @@ func DirectAudit(c echo.Context) error {
 		}
 	}
 
-	l := log.NewEntry().WithField(c.Path(), "direct audit failed")
+	l := log.NewEntry().WithField(c.Path(), "direct audit failed").WithContext(c.Request().Context())
 
 	var instance *model.Instance
 	if req.ProjectId != "" && req.InstanceName != nil {
@@ -177,7 +177,7 @@ func DirectAuditFiles(c echo.Context) error {
 		sqls = req.FileContents[0]
 	}
 
-	l := log.NewEntry().WithField("api", "[post]/v2/audit_files")
+	l := log.NewEntry().WithField("api", "[post]/v2/audit_files").WithContext(c.Request().Context())
 
 	ctx := c.Request().Context()
 	var instance *model.Instance
@@ -8,6 +8,7 @@ import (
 	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/server"
+	"github.com/actiontech/sqle/sqle/server/knowledge_base"
 
 	"github.com/labstack/echo/v4"
 )
@@ -65,6 +66,7 @@ type AuditResult struct {
 	RuleName            string                    `json:"rule_name"`
 	DbType              string                    `json:"db_type"`
 	I18nAuditResultInfo model.I18nAuditResultInfo `json:"i18n_audit_result_info"`
+	KnowledgeId         uint                      `json:"knowledge_id,omitempty"` // 规则的知识ID，用于跳转到规则知识
 }
 
 // @Summary 获取指定扫描任务的SQLs信息
@@ -141,6 +143,20 @@ func GetTaskSQLs(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	lang := locale.Bundle.GetLangTagFromCtx(c.Request().Context())
+	ruleNames := []string{}
+	for _, taskSQL := range taskSQLs {
+		for _, ar := range taskSQL.AuditResults {
+			if ar.RuleName != "" {
+				ruleNames = append(ruleNames, ar.RuleName)
+			}
+		}
+	}
+	knowledgeIds, err := knowledge_base.GetKnowledgeIdsOfRules(task.DBType, ruleNames, lang)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
 	for _, taskSQL := range taskSQLs {
 		backupStatus := backupTaskMap.GetBackupStatus(taskSQL.Id)
 		backupResult := backupTaskMap.GetBackupResult(taskSQL.Id)
@@ -195,11 +211,12 @@ func GetTaskSQLs(c echo.Context) error {
 			taskSQLRes.AuditResult = append(taskSQLRes.AuditResult, &AuditResult{
 				Level:               ar.Level,
 				ExecutionFailed:     ar.ExecutionFailed,
-				ErrorInfo:           ar.GetAuditErrorMsgByLangTag(locale.Bundle.GetLangTagFromCtx(c.Request().Context())),
-				Message:             ar.GetAuditMsgByLangTag(locale.Bundle.GetLangTagFromCtx(c.Request().Context())),
+				ErrorInfo:           ar.GetAuditErrorMsgByLangTag(lang),
+				Message:             ar.GetAuditMsgByLangTag(lang),
 				RuleName:            ar.RuleName,
 				DbType:              task.DBType,
 				I18nAuditResultInfo: ar.I18nAuditResultInfo,
+				KnowledgeId:         knowledgeIds[ar.RuleName],
 			})
 		}
 
@@ -29,15 +29,16 @@ import (
 var errTaskHasBeenUsed = errors.New(errors.DataConflict, fmt.Errorf("task has been used in other workflow"))
 
 type WorkflowStepResV2 struct {
-	Id            uint       `json:"workflow_step_id,omitempty"`
-	Number        uint       `json:"number"`
-	Type          string     `json:"type" enums:"create_workflow,update_workflow,sql_review,sql_execute"`
-	Desc          string     `json:"desc,omitempty"`
-	Users         []string   `json:"assignee_user_name_list,omitempty"`
-	OperationUser string     `json:"operation_user_name,omitempty"`
-	OperationTime *time.Time `json:"operation_time,omitempty"`
-	State         string     `json:"state,omitempty" enums:"initialized,approved,rejected"`
-	Reason        string     `json:"reason,omitempty"`
+	Id             uint       `json:"workflow_step_id,omitempty"`
+	Number         uint       `json:"number"`
+	Type           string     `json:"type" enums:"create_workflow,update_workflow,sql_review,sql_execute"`
+	Desc           string     `json:"desc,omitempty"`
+	Users          []string   `json:"assignee_user_name_list,omitempty"`
+	OperationUser  string     `json:"operation_user_name,omitempty"`
+	OnBehalfOfUser string     `json:"on_behalf_of_user_name,omitempty"`
+	OperationTime  *time.Time `json:"operation_time,omitempty"`
+	State          string     `json:"state,omitempty" enums:"initialized,approved,rejected"`
+	Reason         string     `json:"reason,omitempty"`
 }
 
 type ApproveWorkflowReqV2 struct {
@@ -799,6 +800,14 @@ func UpdateWorkflowV2(c echo.Context) error {
 		}
 	}
 
+	workflowTemplate, err := s.ResolveWorkflowTemplateForCreate(workflow.ProjectId, workflow.WorkflowTemplateId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := v1.CheckTasksScoreCanCommit(string(workflow.ProjectId), workflowTemplate, tasks); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
 	count, err := s.GetWorkflowRecordCountByTaskIds(taskIds)
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
@@ -1270,6 +1279,10 @@ func convertWorkflowStepToRes(step *model.WorkflowStep) *WorkflowStepResV2 {
 		Users:         []string{},
 		OperationUser: dms.GetUserNameWithDelTag(step.OperationUserId),
 	}
+	// the step is operated by a delegate of the assignee
+	if step.OnBehalfOfUserId != "" {
+		stepRes.OnBehalfOfUser = dms.GetUserNameWithDelTag(step.OnBehalfOfUserId)
+	}
 	// 处理 Template 可能为 nil 的情况（例如使用临时模板创建的工单）
 	if step.Template != nil {
 		stepRes.Type = step.Template.Typ
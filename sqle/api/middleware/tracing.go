@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/actiontech/sqle/sqle/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Tracing starts a server span for each request, the span is the child of the `traceparent` header if the caller sends it.
+func Tracing() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := tracing.Extract(req.Context(), req.Header.Get)
+			ctx, span := tracing.Start(ctx, fmt.Sprintf("%s %s", req.Method, c.Path()),
+				tracing.WithSpanKind(tracing.SpanKindServer),
+				tracing.WithAttributes(
+					tracing.Attr("http.method", req.Method),
+					tracing.Attr("http.route", c.Path()),
+					tracing.Attr("http.target", req.URL.Path),
+				))
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			span.SetAttributes(tracing.Attr("http.status_code", c.Response().Status))
+			if err != nil {
+				span.RecordError(err)
+			} else if c.Response().Status >= http.StatusInternalServerError {
+				span.RecordError(errors.New(http.StatusText(c.Response().Status)))
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/conformance"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pluginPath string
+	pluginArgs []string
+	host       string
+	port       string
+	user       string
+	password   string
+	database   string
+	sqls       []string
+	querySQL   string
+	schema     string
+	table      string
+	format     string
+	timeout    time.Duration
+)
+
+func main() {
+	var rootCmd = &cobra.Command{
+		Use:   "plugin-conformance",
+		Short: "Check whether a driver plugin conforms to the plugin protocol",
+		Long: "Check whether a driver plugin conforms to the plugin protocol.\n\n" +
+			"The plugin is launched like sqled does and every RPC is called. The RPCs need a database\n" +
+			"are skipped if --host is not given. It exits with 1 if any check fails.",
+		SilenceUsage: true,
+		RunE:         run,
+	}
+	rootCmd.Flags().StringVarP(&pluginPath, "plugin", "", "", "plugin binary path")
+	rootCmd.Flags().StringArrayVarP(&pluginArgs, "arg", "", nil, "argument passed to the plugin, can be repeated")
+	rootCmd.Flags().StringVarP(&host, "host", "", "", "database host")
+	rootCmd.Flags().StringVarP(&port, "port", "", "", "database port")
+	rootCmd.Flags().StringVarP(&user, "user", "", "", "database user")
+	rootCmd.Flags().StringVarP(&password, "password", "", "", "database password")
+	rootCmd.Flags().StringVarP(&database, "database", "", "", "database name")
+	rootCmd.Flags().StringArrayVarP(&sqls, "sql", "", nil, "sample SQL in the dialect of the plugin, can be repeated")
+	rootCmd.Flags().StringVarP(&querySQL, "query", "", "", "read-only query for the Query and Explain RPC")
+	rootCmd.Flags().StringVarP(&schema, "schema", "", "", "schema of the sample table")
+	rootCmd.Flags().StringVarP(&table, "table", "", "", "sample table for GetTableMeta, GetDatabaseObjectDDL and GetDatabaseDiffModifySQL")
+	rootCmd.Flags().StringVarP(&format, "format", "", "text", "report format, text or json")
+	rootCmd.Flags().DurationVarP(&timeout, "timeout", "", 30*time.Second, "timeout of each RPC")
+	_ = rootCmd.MarkFlagRequired("plugin")
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format %s", format)
+	}
+	cfg := &conformance.Config{
+		PluginPath: pluginPath,
+		PluginArgs: pluginArgs,
+		SQLs:       sqls,
+		QuerySQL:   querySQL,
+		Timeout:    timeout,
+	}
+	if host != "" {
+		cfg.DSN = &driverV2.DSN{
+			Host:         host,
+			Port:         port,
+			User:         user,
+			Password:     password,
+			DatabaseName: database,
+		}
+	}
+	if table != "" {
+		cfg.Table = &driverV2.Table{Name: table, Schema: schema}
+	}
+
+	report, err := conformance.Run(cfg)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if !report.Passed() {
+		os.Exit(1)
+	}
+	return nil
+}
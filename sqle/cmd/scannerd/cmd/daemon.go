@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	scannerCmd "github.com/actiontech/sqle/sqle/cmd/scannerd/command"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/daemon"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonConfigFile string
+
+	daemonCmd = &cobra.Command{
+		Use:   scannerCmd.TypeDaemon,
+		Short: "Run the scanners of multiple sources by the config file",
+		// the sqle server is configured in the config file, the required flags of root are not required
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			log := logrus.WithField("scanner", "daemon")
+			d, err := daemon.New(daemonConfigFile, log)
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			// reload the config file on SIGHUP
+			hup := make(chan os.Signal, 1)
+			signal.Notify(hup, syscall.SIGHUP)
+			reload := make(chan struct{})
+			go func() {
+				for {
+					select {
+					case <-hup:
+						select {
+						case reload <- struct{}{}:
+						case <-ctx.Done():
+							return
+						}
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			if err := d.Run(ctx, reload); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	daemonScanner, err := scannerCmd.GetScannerdCmd(scannerCmd.TypeDaemon)
+	if err != nil {
+		panic(err)
+	}
+	daemonCmd.Flags().StringVarP(daemonScanner.StringFlagFn[scannerCmd.FlagConfig](&daemonConfigFile))
+
+	for _, requiredFlag := range daemonScanner.RequiredFlags {
+		_ = daemonCmd.MarkFlagRequired(requiredFlag)
+	}
+
+	rootCmd.AddCommand(daemonCmd)
+}
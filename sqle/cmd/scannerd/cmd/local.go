@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/local"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	scannerCmd "github.com/actiontech/sqle/sqle/cmd/scannerd/command"
+	"github.com/sirupsen/logrus"
+)
+
+var localFlags struct {
+	local            bool
+	ruleTemplateFile string
+	schemaFile       string
+	outputFormat     string
+	failLevel        string
+}
+
+// newDirectAuditClient returns the local auditor in the local mode, otherwise the SQLE client.
+func newDirectAuditClient() (common.DirectAuditClient, error) {
+	if !localFlags.local {
+		return scanner.NewSQLEClient(time.Second*time.Duration(rootCmdFlags.timeout), rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token).WithProject(rootCmdFlags.project), nil
+	}
+	if localFlags.ruleTemplateFile == "" {
+		return nil, fmt.Errorf("flag \"%s\" is required in the local mode", scannerCmd.FlagRuleTemplateFile)
+	}
+	return local.New(&local.Params{
+		RuleTemplateFile: localFlags.ruleTemplateFile,
+		SchemaFile:       localFlags.schemaFile,
+		OutputFormat:     localFlags.outputFormat,
+		FailLevel:        localFlags.failLevel,
+	}, logrus.WithField("auditor", "local"))
+}
@@ -4,11 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/mybatis"
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
-	"github.com/actiontech/sqle/sqle/pkg/scanner"
 
 	scannerCmd "github.com/actiontech/sqle/sqle/cmd/scannerd/command"
 	"github.com/fatih/color"
@@ -24,6 +22,7 @@ var (
 	instNameXml     string
 	schemaNameXml   string
 	ShowFileContent bool
+	maxVariants     int
 
 	mybatisCmd = &cobra.Command{
 		Use:   scannerCmd.TypeMySQLMybatis,
@@ -37,9 +36,14 @@ var (
 				InstName:        instNameXml,
 				SchemaName:      schemaNameXml,
 				ShowFileContent: ShowFileContent,
+				MaxVariants:     maxVariants,
 			}
 			log := logrus.WithField("scanner", "mybatis")
-			client := scanner.NewSQLEClient(time.Second*time.Duration(rootCmdFlags.timeout), rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token).WithProject(rootCmdFlags.project)
+			client, err := newDirectAuditClient()
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
 			scanner, err := mybatis.New(param, log, client)
 			if err != nil {
 				fmt.Println(color.RedString(err.Error()))
@@ -68,6 +72,12 @@ func init() {
 	mybatisCmd.Flags().StringVarP(mybatis.StringFlagFn[scannerCmd.FlagInstanceName](&instNameXml))
 	mybatisCmd.Flags().StringVarP(mybatis.StringFlagFn[scannerCmd.FlagSchemaName](&schemaNameXml))
 	mybatisCmd.Flags().BoolVarP(mybatis.BoolFlagFn[scannerCmd.FlagShowFileContent](&ShowFileContent))
+	mybatisCmd.Flags().IntVarP(mybatis.IntFlagFn[scannerCmd.FlagMaxVariants](&maxVariants))
+	mybatisCmd.Flags().BoolVarP(mybatis.BoolFlagFn[scannerCmd.FlagLocal](&localFlags.local))
+	mybatisCmd.Flags().StringVarP(mybatis.StringFlagFn[scannerCmd.FlagRuleTemplateFile](&localFlags.ruleTemplateFile))
+	mybatisCmd.Flags().StringVarP(mybatis.StringFlagFn[scannerCmd.FlagSchemaFile](&localFlags.schemaFile))
+	mybatisCmd.Flags().StringVarP(mybatis.StringFlagFn[scannerCmd.FlagOutputFormat](&localFlags.outputFormat))
+	mybatisCmd.Flags().StringVarP(mybatis.StringFlagFn[scannerCmd.FlagFailLevel](&localFlags.failLevel))
 
 	for _, requiredFlag := range mybatis.RequiredFlags {
 		_ = mybatisCmd.MarkFlagRequired(requiredFlag)
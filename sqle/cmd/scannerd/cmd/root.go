@@ -38,8 +38,17 @@ func init() {
 	rootCmd.PersistentFlags().IntVarP(root.IntFlagFn[scannerCmd.FlagTimeout](&rootCmdFlags.timeout))
 	rootCmd.PersistentFlags().StringVarP(root.StringFlagFn[scannerCmd.FlagProject](&rootCmdFlags.project))
 
-	for _, requiredFlag := range root.RequiredFlags {
-		_ = rootCmd.MarkPersistentFlagRequired(requiredFlag)
+	// the required flags of root are used to connect SQLE server, they are not required in the local mode
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if local, err := cmd.Flags().GetBool(scannerCmd.FlagLocal); err == nil && local {
+			return nil
+		}
+		for _, requiredFlag := range root.RequiredFlags {
+			if !cmd.Flags().Changed(requiredFlag) {
+				return fmt.Errorf("required flag(s) \"%s\" not set", requiredFlag)
+			}
+		}
+		return nil
 	}
 }
 
@@ -4,12 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
 
 	scannerCmd "github.com/actiontech/sqle/sqle/cmd/scannerd/command"
 	sqlFile "github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/sql_file"
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
-	"github.com/actiontech/sqle/sqle/pkg/scanner"
 
 	"github.com/fatih/color"
 	"github.com/sirupsen/logrus"
@@ -36,7 +34,11 @@ var (
 				ShowFileContent:  ShowFileContent,
 			}
 			log := logrus.WithField("scanner", "sqlFile")
-			client := scanner.NewSQLEClient(time.Second*time.Duration(rootCmdFlags.timeout), rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token).WithProject(rootCmdFlags.project)
+			client, err := newDirectAuditClient()
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
 			scanner, err := sqlFile.New(param, log, client)
 			if err != nil {
 				fmt.Println(color.RedString(err.Error()))
@@ -64,6 +66,11 @@ func init() {
 	sqlFileCmd.Flags().StringVarP(sqlfile.StringFlagFn[scannerCmd.FlagInstanceName](&instNameSqlFile))
 	sqlFileCmd.Flags().StringVarP(sqlfile.StringFlagFn[scannerCmd.FlagSchemaName](&schemaNameSqlFile))
 	sqlFileCmd.Flags().BoolVarP(sqlfile.BoolFlagFn[scannerCmd.FlagShowFileContent](&ShowFileContent))
+	sqlFileCmd.Flags().BoolVarP(sqlfile.BoolFlagFn[scannerCmd.FlagLocal](&localFlags.local))
+	sqlFileCmd.Flags().StringVarP(sqlfile.StringFlagFn[scannerCmd.FlagRuleTemplateFile](&localFlags.ruleTemplateFile))
+	sqlFileCmd.Flags().StringVarP(sqlfile.StringFlagFn[scannerCmd.FlagSchemaFile](&localFlags.schemaFile))
+	sqlFileCmd.Flags().StringVarP(sqlfile.StringFlagFn[scannerCmd.FlagOutputFormat](&localFlags.outputFormat))
+	sqlFileCmd.Flags().StringVarP(sqlfile.StringFlagFn[scannerCmd.FlagFailLevel](&localFlags.failLevel))
 
 	for _, requiredFlag := range sqlfile.RequiredFlags {
 		_ = sqlFileCmd.MarkFlagRequired(requiredFlag)
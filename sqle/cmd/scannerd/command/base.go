@@ -38,6 +38,7 @@ const (
 	FlagSkipErrorXmlSort    string = "X"
 	FlagShowFileContent     string = "show-file-content"
 	FlagShowFileContentSort string = "L"
+	FlagMaxVariants         string = "max-variants"
 	// sqlfile
 	FlagSkipErrorSqlFile     string = "skip-error-sql-file"
 	FlagSkipErrorSqlFileSort string = "S"
@@ -47,6 +48,15 @@ const (
 	FlagExcludeUserList   string = "exclude-user-list"
 	FlagIncludeSchemaList string = "include-schema-list"
 	FlagExcludeSchemaList string = "exclude-schema-list"
+	// local
+	FlagLocal            string = "local"
+	FlagRuleTemplateFile string = "rule-template-file"
+	FlagSchemaFile       string = "schema-file"
+	FlagOutputFormat     string = "output-format"
+	FlagFailLevel        string = "fail-level"
+	// daemon
+	FlagConfig     string = "config"
+	FlagConfigSort string = "c"
 	// tbase
 	FlagFileFormat     string = "format"
 	FlagFileFormatSort string = "F"
@@ -85,6 +95,8 @@ func GetScannerdCmd(scannerType string) (*scannerCmd, error) {
 		return &sqlFile, nil
 	case TypeTBaseSlowLog:
 		return &tbaseLog, nil
+	case TypeDaemon:
+		return &daemon, nil
 	default:
 		return nil, fmt.Errorf("unsupport scannerd type %s", scannerType)
 	}
@@ -112,6 +124,15 @@ func (cmd *scannerCmd) addBoolFlag(name string, shorthand string, value bool, us
 	}
 }
 
+// addLocalFlags adds the flags of the local mode, which audits the SQL by the built-in MySQL driver without SQLE server.
+func (cmd *scannerCmd) addLocalFlags() {
+	cmd.addBoolFlag(FlagLocal, EmptyFlagSort, false, "audit the sql locally by the built-in mysql driver without sqle server")
+	cmd.addStringFlag(FlagRuleTemplateFile, EmptyFlagSort, EmptyDefaultValue, "rule template file exported by sqle in the json format, required in the local mode")
+	cmd.addStringFlag(FlagSchemaFile, EmptyFlagSort, EmptyDefaultValue, "schema snapshot file of the create table statements, such as the output of mysqldump --no-data, used in the local mode")
+	cmd.addStringFlag(FlagOutputFormat, EmptyFlagSort, "text", "output format of the audit result in the local mode, text or json")
+	cmd.addStringFlag(FlagFailLevel, EmptyFlagSort, "error", "exit with non-zero code if the audit level of any sql reaches the level in the local mode, normal, notice, warn or error")
+}
+
 func (cmd *scannerCmd) addRequiredFlag(name string) {
 	cmd.RequiredFlags = append(cmd.RequiredFlags, name)
 }
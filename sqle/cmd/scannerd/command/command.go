@@ -8,6 +8,7 @@ const (
 	TypeTBaseSlowLog       = "TBase_slow_log"
 	TypeTiDBAuditLog       = "tidb_audit_log"
 	TypeRootScannerd       = "root"
+	TypeDaemon             = "daemon"
 )
 
 var (
@@ -17,6 +18,7 @@ var (
 	sqlFile      scannerCmd = newScannerCmd(TypeSQLFile)
 	tbaseLog     scannerCmd = newScannerCmd(TypeTBaseSlowLog)
 	tidbAuditLog scannerCmd = newScannerCmd(TypeTiDBAuditLog)
+	daemon       scannerCmd = newScannerCmd(TypeDaemon)
 )
 
 func init() {
@@ -39,6 +41,9 @@ func init() {
 		"skip the statement that the scanner failed to parse from within the xml file")
 	myBatis.addBoolFlag(FlagSkipErrorXml, FlagSkipErrorXmlSort, false, "skip the xml file that failed to parse")
 	myBatis.addBoolFlag(FlagShowFileContent, FlagShowFileContentSort, false, "show xml file")
+	myBatis.addIntFlag(FlagMaxVariants, EmptyFlagSort, 0,
+		"max variants of the dynamic sql of each statement to audit, 0 (default) means flattening each statement into one sql")
+	myBatis.addLocalFlags()
 	myBatis.addRequiredFlag(FlagDirectory)
 }
 
@@ -60,5 +65,11 @@ func init() {
 	sqlFile.addStringFlag(FlagInstanceName, FlagInstanceNameSort, EmptyDefaultValue, "instance name")
 	sqlFile.addStringFlag(FlagSchemaName, FlagSchemaNameSort, EmptyDefaultValue, "schema name")
 	sqlFile.addBoolFlag(FlagShowFileContent, FlagShowFileContentSort, false, "show sql file")
+	sqlFile.addLocalFlags()
 	sqlFile.addRequiredFlag(FlagDirectory)
 }
+
+func init() {
+	daemon.addStringFlag(FlagConfig, FlagConfigSort, EmptyDefaultValue, "config file of the daemon in the yaml format")
+	daemon.addRequiredFlag(FlagConfig)
+}
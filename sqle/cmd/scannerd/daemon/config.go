@@ -0,0 +1,153 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	scannerCmd "github.com/actiontech/sqle/sqle/cmd/scannerd/command"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultProject        = "default"
+	defaultTimeoutSecond  = 10
+	defaultPollInterval   = 5 * time.Minute
+	defaultMaxBufferedSQL = 100000
+)
+
+// the name of the source is a part of the state file name
+var sourceNamePattern = regexp.MustCompile(`^[\w.-]+$`)
+
+// logScannerSupported is false in the builds without the slow log scanners, the slow log sources are rejected then.
+var logScannerSupported = true
+
+type Config struct {
+	SQLE SQLEConfig `yaml:"sqle"`
+	// StateDir keeps the state and the buffered SQLs of the sources, it is the "state" directory beside the config
+	// file by default.
+	StateDir string `yaml:"state_dir"`
+	// StatusAddr is the listen address of the status endpoint, such as "127.0.0.1:10090", the endpoint is disabled if
+	// it is empty.
+	StatusAddr string          `yaml:"status_addr"`
+	Sources    []*SourceConfig `yaml:"sources"`
+}
+
+type SQLEConfig struct {
+	Host          string `yaml:"host"`
+	Port          string `yaml:"port"`
+	Token         string `yaml:"token"`
+	TimeoutSecond int    `yaml:"timeout"`
+}
+
+type SourceConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+
+	Project      string `yaml:"project"`
+	AuditPlanID  string `yaml:"audit_plan_id"`
+	DbType       string `yaml:"db_type"`
+	InstanceName string `yaml:"instance_name"`
+	SchemaName   string `yaml:"schema_name"`
+
+	// the directory of the SQL files or the MyBatis XML files
+	Dir            string `yaml:"dir"`
+	SkipErrorQuery bool   `yaml:"skip_error_query"`
+	SkipErrorFile  bool   `yaml:"skip_error_file"`
+	MaxVariants    int    `yaml:"max_variants"`
+	// PollInterval is the interval checking the changed files in the directory, such as "10m"
+	PollInterval string `yaml:"poll_interval"`
+
+	// the slow log file. The daemon does not persist the offset read in the file, a restarted source reads the
+	// file from where the slow log scanner starts.
+	LogFile        string `yaml:"log_file"`
+	IncludeUsers   string `yaml:"include_users"`
+	ExcludeUsers   string `yaml:"exclude_users"`
+	IncludeSchemas string `yaml:"include_schemas"`
+	ExcludeSchemas string `yaml:"exclude_schemas"`
+	// MaxBufferedSQL limits the SQLs buffered while sqled is unreachable, the oldest SQLs are dropped beyond it
+	MaxBufferedSQL int `yaml:"max_buffered_sql"`
+}
+
+func (s *SourceConfig) isDirectory() bool {
+	return s.Type == scannerCmd.TypeMySQLMybatis || s.Type == scannerCmd.TypeSQLFile
+}
+
+func (s *SourceConfig) pollInterval() time.Duration {
+	interval, err := time.ParseDuration(s.PollInterval)
+	if err != nil || interval <= 0 {
+		return defaultPollInterval
+	}
+	return interval
+}
+
+// LoadConfig loads the config file of the daemon and fills the default values.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("load config file %s failed: %v", path, err)
+	}
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(b, cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal config file %s failed: %v", path, err)
+	}
+
+	if cfg.SQLE.Host == "" {
+		cfg.SQLE.Host = "127.0.0.1"
+	}
+	if cfg.SQLE.Port == "" {
+		cfg.SQLE.Port = "10000"
+	}
+	if cfg.SQLE.TimeoutSecond <= 0 {
+		cfg.SQLE.TimeoutSecond = defaultTimeoutSecond
+	}
+	if cfg.SQLE.Token == "" {
+		return nil, fmt.Errorf("sqle token is required")
+	}
+	if cfg.StateDir == "" {
+		cfg.StateDir = filepath.Join(filepath.Dir(path), "state")
+	}
+
+	names := map[string]struct{}{}
+	for i, source := range cfg.Sources {
+		if source.Name == "" {
+			return nil, fmt.Errorf("the name of source %d is required", i+1)
+		}
+		if !sourceNamePattern.MatchString(source.Name) {
+			return nil, fmt.Errorf("the name of source %s should only contain letters, digits, '_', '.' and '-'", source.Name)
+		}
+		if _, ok := names[source.Name]; ok {
+			return nil, fmt.Errorf("the name of source %s is duplicated", source.Name)
+		}
+		names[source.Name] = struct{}{}
+		if source.Project == "" {
+			source.Project = defaultProject
+		}
+		if source.MaxBufferedSQL <= 0 {
+			source.MaxBufferedSQL = defaultMaxBufferedSQL
+		}
+
+		switch source.Type {
+		case scannerCmd.TypeMySQLMybatis, scannerCmd.TypeSQLFile:
+			if source.Dir == "" {
+				return nil, fmt.Errorf("the dir of source %s is required", source.Name)
+			}
+		case scannerCmd.TypeMySQLSlowLog, scannerCmd.TypeTDSQLInnodbSlowLog:
+			if !logScannerSupported {
+				return nil, fmt.Errorf("the type %s of source %s is not supported in this edition", source.Type, source.Name)
+			}
+			if source.LogFile == "" {
+				return nil, fmt.Errorf("the log file of source %s is required", source.Name)
+			}
+			if source.AuditPlanID == "" {
+				return nil, fmt.Errorf("the audit plan id of source %s is required", source.Name)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported type %s of source %s", source.Type, source.Name)
+		}
+	}
+	return cfg, nil
+}
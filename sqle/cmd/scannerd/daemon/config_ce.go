@@ -0,0 +1,9 @@
+//go:build !enterprise
+// +build !enterprise
+
+package daemon
+
+func init() {
+	// the slow log scanner is not implemented in the community edition, see slowquery.New
+	logScannerSupported = false
+}
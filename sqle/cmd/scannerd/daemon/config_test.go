@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "scannerd.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func setLogScannerSupported(t *testing.T, supported bool) {
+	old := logScannerSupported
+	logScannerSupported = supported
+	t.Cleanup(func() { logScannerSupported = old })
+}
+
+func TestLoadConfig(t *testing.T) {
+	setLogScannerSupported(t, true)
+	path := writeTestConfig(t, `
+sqle:
+  token: test_token
+status_addr: 127.0.0.1:10090
+sources:
+  - name: order-mybatis
+    type: mysql_mybatis
+    project: order
+    dir: /data/order
+    poll_interval: 1m
+  - name: order.slow_log
+    type: mysql_slow_log
+    audit_plan_id: "3"
+    log_file: /var/log/mysql/slow.log
+`)
+	cfg, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", cfg.SQLE.Host)
+	assert.Equal(t, "10000", cfg.SQLE.Port)
+	assert.Equal(t, defaultTimeoutSecond, cfg.SQLE.TimeoutSecond)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "state"), cfg.StateDir)
+	assert.Len(t, cfg.Sources, 2)
+
+	assert.Equal(t, "order", cfg.Sources[0].Project)
+	assert.True(t, cfg.Sources[0].isDirectory())
+	assert.Equal(t, "1m0s", cfg.Sources[0].pollInterval().String())
+	assert.Equal(t, defaultProject, cfg.Sources[1].Project)
+	assert.False(t, cfg.Sources[1].isDirectory())
+	assert.Equal(t, defaultPollInterval, cfg.Sources[1].pollInterval())
+	assert.Equal(t, defaultMaxBufferedSQL, cfg.Sources[1].MaxBufferedSQL)
+}
+
+func TestLoadInvalidConfig(t *testing.T) {
+	cases := map[string]string{
+		"without token": `
+sources:
+  - {name: s1, type: sql_file, dir: /data}`,
+		"unknown field": `
+sqle: {token: t}
+sources:
+  - {name: s1, type: sql_file, directory: /data}`,
+		"invalid name": `
+sqle: {token: t}
+sources:
+  - {name: "../s1", type: sql_file, dir: /data}`,
+		"duplicated name": `
+sqle: {token: t}
+sources:
+  - {name: s1, type: sql_file, dir: /data}
+  - {name: s1, type: mysql_mybatis, dir: /data}`,
+		"without dir": `
+sqle: {token: t}
+sources:
+  - {name: s1, type: mysql_mybatis}`,
+		"without audit plan": `
+sqle: {token: t}
+sources:
+  - {name: s1, type: mysql_slow_log, log_file: /var/log/slow.log}`,
+		"unsupported type": `
+sqle: {token: t}
+sources:
+  - {name: s1, type: TBase_slow_log, log_file: /var/log/slow.log, audit_plan_id: "1"}`,
+	}
+	for name, content := range cases {
+		_, err := LoadConfig(writeTestConfig(t, content))
+		assert.Error(t, err, name)
+	}
+
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "not_exist.yaml"))
+	assert.Error(t, err)
+}
+
+func TestLoadConfigWithoutLogScanner(t *testing.T) {
+	setLogScannerSupported(t, false)
+	_, err := LoadConfig(writeTestConfig(t, `
+sqle: {token: t}
+sources:
+  - {name: s1, type: mysql_slow_log, log_file: /var/log/slow.log, audit_plan_id: "1"}`))
+	assert.EqualError(t, err, "the type mysql_slow_log of source s1 is not supported in this edition")
+
+	_, err = LoadConfig(writeTestConfig(t, `
+sqle: {token: t}
+sources:
+  - {name: s1, type: sql_file, dir: /data}`))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,149 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// the interval checking whether the config file is modified
+const configCheckInterval = 10 * time.Second
+
+// Daemon runs the scanners of the sources in the config file, the sources are restarted, started or stopped when the
+// config file is modified.
+type Daemon struct {
+	configPath string
+	l          *logrus.Entry
+
+	mu            sync.Mutex
+	cfg           *Config
+	configModTime time.Time
+	loadedAt      time.Time
+	workers       map[string] /*source name*/ *worker
+}
+
+func New(configPath string, l *logrus.Entry) (*Daemon, error) {
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	d := &Daemon{
+		configPath: configPath,
+		l:          l,
+		cfg:        cfg,
+		loadedAt:   time.Now(),
+		workers:    map[string]*worker{},
+	}
+	if info, err := os.Stat(configPath); err == nil {
+		d.configModTime = info.ModTime()
+	}
+	return d, nil
+}
+
+// Run runs the sources until ctx is canceled, the config file is reloaded when it is modified or reload is triggered.
+func (d *Daemon) Run(ctx context.Context, reload <-chan struct{}) error {
+	var server *http.Server
+	if d.cfg.StatusAddr != "" {
+		server = &http.Server{Addr: d.cfg.StatusAddr, Handler: d.statusHandler(), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				d.l.Errorf("status endpoint stopped: %v", err)
+			}
+		}()
+		d.l.Infof("status endpoint listens on %s", d.cfg.StatusAddr)
+	}
+
+	d.apply(ctx, d.cfg)
+	ticker := time.NewTicker(configCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			d.stopAll()
+			if server != nil {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = server.Shutdown(shutdownCtx)
+			}
+			return nil
+		case <-reload:
+			d.reload(ctx)
+		case <-ticker.C:
+			info, err := os.Stat(d.configPath)
+			if err != nil {
+				d.l.Warnf("stat config file failed: %v", err)
+				continue
+			}
+			if !info.ModTime().Equal(d.configModTime) {
+				d.reload(ctx)
+			}
+		}
+	}
+}
+
+// reload keeps the running sources if the config file is invalid.
+func (d *Daemon) reload(ctx context.Context) {
+	if info, err := os.Stat(d.configPath); err == nil {
+		d.configModTime = info.ModTime()
+	}
+	cfg, err := LoadConfig(d.configPath)
+	if err != nil {
+		d.l.Errorf("reload config failed, keep the running sources: %v", err)
+		return
+	}
+	if cfg.StatusAddr != d.cfg.StatusAddr {
+		d.l.Warnf("the status address is changed, it takes effect after restarting")
+	}
+	d.l.Infof("reload config file %s", d.configPath)
+	d.apply(ctx, cfg)
+}
+
+// apply stops the sources removed or modified, and starts the sources added or modified.
+func (d *Daemon) apply(ctx context.Context, cfg *Config) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	globalChanged := !reflect.DeepEqual(cfg.SQLE, d.cfg.SQLE) || cfg.StateDir != d.cfg.StateDir
+	sources := map[string]*SourceConfig{}
+	for _, source := range cfg.Sources {
+		sources[source.Name] = source
+	}
+	for name, w := range d.workers {
+		if source, ok := sources[name]; ok && !globalChanged && reflect.DeepEqual(source, w.cfg) {
+			continue
+		}
+		d.l.Infof("stop source %s", name)
+		w.stop()
+		delete(d.workers, name)
+	}
+	for _, source := range cfg.Sources {
+		if _, ok := d.workers[source.Name]; ok {
+			continue
+		}
+		w, err := newWorker(d.l, cfg, source)
+		if err != nil {
+			d.l.Errorf("start source %s failed: %v", source.Name, err)
+			continue
+		}
+		d.l.Infof("start source %s", source.Name)
+		w.start(ctx)
+		d.workers[source.Name] = w
+	}
+	d.cfg = cfg
+	d.loadedAt = time.Now()
+}
+
+func (d *Daemon) stopAll() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name, w := range d.workers {
+		w.stop()
+		delete(d.workers, name)
+	}
+}
@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+)
+
+// SourceState is the state of the source persisted between the restarts of the daemon.
+type SourceState struct {
+	// Files are the sha256 digests of the audited files of the directory source, keyed by the file path
+	Files map[string]string `json:"files,omitempty"`
+}
+
+type stateStore struct {
+	dir string
+}
+
+func (s *stateStore) statePath(name string) string {
+	return filepath.Join(s.dir, name+".state.json")
+}
+
+func (s *stateStore) bufferPath(name string) string {
+	return filepath.Join(s.dir, name+".buffer.json")
+}
+
+func (s *stateStore) load(name string) (*SourceState, error) {
+	state := &SourceState{Files: map[string]string{}}
+	if err := readJSONFile(s.statePath(name), state); err != nil {
+		return nil, fmt.Errorf("load state of source %s failed: %v", name, err)
+	}
+	if state.Files == nil {
+		state.Files = map[string]string{}
+	}
+	return state, nil
+}
+
+func (s *stateStore) save(name string, state *SourceState) error {
+	if err := writeJSONFile(s.statePath(name), state); err != nil {
+		return fmt.Errorf("save state of source %s failed: %v", name, err)
+	}
+	return nil
+}
+
+// sqlBuffer keeps the SQLs failed to upload while sqled is unreachable, they are uploaded before the new SQLs after
+// sqled is reachable again. The oldest SQLs are dropped if the buffer is full.
+type sqlBuffer struct {
+	path   string
+	maxLen int
+}
+
+func (b *sqlBuffer) load() ([]scanners.SQL, error) {
+	sqls := []scanners.SQL{}
+	if err := readJSONFile(b.path, &sqls); err != nil {
+		return nil, fmt.Errorf("load buffered sqls failed: %v", err)
+	}
+	return sqls, nil
+}
+
+// save overwrites the buffer with the SQLs, it returns the count of the SQLs dropped.
+func (b *sqlBuffer) save(sqls []scanners.SQL) (dropped int, err error) {
+	if len(sqls) == 0 {
+		if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		return 0, nil
+	}
+	if len(sqls) > b.maxLen {
+		dropped = len(sqls) - b.maxLen
+		sqls = sqls[dropped:]
+	}
+	if err := writeJSONFile(b.path, sqls); err != nil {
+		return 0, fmt.Errorf("save buffered sqls failed: %v", err)
+	}
+	return dropped, nil
+}
+
+// readJSONFile leaves v unchanged if the file does not exist.
+func readJSONFile(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// writeJSONFile writes a temporary file and renames it, so the file is not broken if the daemon exits while writing.
+func writeJSONFile(path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
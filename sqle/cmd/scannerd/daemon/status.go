@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type Status struct {
+	ConfigFile string         `json:"config_file"`
+	LoadedAt   time.Time      `json:"loaded_at"`
+	Sources    []SourceStatus `json:"sources"`
+}
+
+func (d *Daemon) Status() *Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := &Status{
+		ConfigFile: d.configPath,
+		LoadedAt:   d.loadedAt,
+		Sources:    make([]SourceStatus, 0, len(d.workers)),
+	}
+	for _, w := range d.workers {
+		status.Sources = append(status.Sources, w.getStatus())
+	}
+	sort.Slice(status.Sources, func(i, j int) bool {
+		return status.Sources[i].Name < status.Sources[j].Name
+	})
+	return status
+}
+
+func (d *Daemon) statusHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(d.Status())
+	})
+	return mux
+}
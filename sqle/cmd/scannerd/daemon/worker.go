@@ -0,0 +1,325 @@
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	scannerCmd "github.com/actiontech/sqle/sqle/cmd/scannerd/command"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/slowquery"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+	"github.com/actiontech/sqle/sqle/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	SourceStateRunning = "running"
+	SourceStateError   = "error"
+	SourceStateStopped = "stopped"
+
+	minRestartBackoff = 10 * time.Second
+	maxRestartBackoff = 5 * time.Minute
+
+	// the same as the scanner command
+	leastPushSecond = 30
+	pushBufferSize  = 1024
+)
+
+type SourceStatus struct {
+	Name         string     `json:"name"`
+	Type         string     `json:"type"`
+	State        string     `json:"state"`
+	StartedAt    time.Time  `json:"started_at"`
+	LastSyncAt   *time.Time `json:"last_sync_at,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	Restarts     int        `json:"restarts"`
+	AuditedFiles int        `json:"audited_files"`
+	UploadedSQLs int        `json:"uploaded_sqls"`
+	BufferedSQLs int        `json:"buffered_sqls"`
+	DroppedSQLs  int        `json:"dropped_sqls"`
+}
+
+// worker runs a source until it is stopped, the source is restarted with backoff if it fails.
+type worker struct {
+	l      *logrus.Entry
+	cfg    *SourceConfig
+	client *scanner.Client
+	// auditClient audits the SQLs of the directory source, it is the client by default
+	auditClient common.DirectAuditClient
+	store       *stateStore
+	state       *SourceState
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	status SourceStatus
+}
+
+func newWorker(l *logrus.Entry, cfg *Config, source *SourceConfig) (*worker, error) {
+	store := &stateStore{dir: cfg.StateDir}
+	state, err := store.load(source.Name)
+	if err != nil {
+		return nil, err
+	}
+	client := scanner.NewSQLEClient(time.Second*time.Duration(cfg.SQLE.TimeoutSecond), cfg.SQLE.Host, cfg.SQLE.Port).
+		WithToken(cfg.SQLE.Token).WithProject(source.Project)
+	return &worker{
+		l:           l.WithField("source", source.Name),
+		cfg:         source,
+		client:      client,
+		auditClient: client,
+		store:       store,
+		state:       state,
+		done:        make(chan struct{}),
+		status: SourceStatus{
+			Name:      source.Name,
+			Type:      source.Type,
+			State:     SourceStateRunning,
+			StartedAt: time.Now(),
+		},
+	}, nil
+}
+
+func (w *worker) start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	go w.run(ctx)
+}
+
+func (w *worker) stop() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *worker) getStatus() SourceStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *worker) updateStatus(fn func(status *SourceStatus)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fn(&w.status)
+}
+
+func (w *worker) run(ctx context.Context) {
+	defer close(w.done)
+	backoff := minRestartBackoff
+	for {
+		var err error
+		if w.cfg.isDirectory() {
+			err = w.runDirectory(ctx)
+		} else {
+			err = w.runLog(ctx)
+		}
+		if ctx.Err() != nil {
+			w.updateStatus(func(status *SourceStatus) { status.State = SourceStateStopped })
+			return
+		}
+		if err == nil {
+			err = errors.New("the scanner stopped unexpectedly")
+		}
+		w.l.Errorf("source failed, restart after %v: %v", backoff, err)
+		w.updateStatus(func(status *SourceStatus) {
+			status.State = SourceStateError
+			status.LastError = err.Error()
+			status.Restarts++
+		})
+
+		select {
+		case <-ctx.Done():
+			w.updateStatus(func(status *SourceStatus) { status.State = SourceStateStopped })
+			return
+		case <-time.After(backoff):
+		}
+		w.updateStatus(func(status *SourceStatus) { status.State = SourceStateRunning })
+		backoff *= 2
+		if backoff > maxRestartBackoff {
+			backoff = maxRestartBackoff
+		}
+	}
+}
+
+// runDirectory audits the changed files in the directory every poll interval until ctx is canceled.
+func (w *worker) runDirectory(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.pollInterval())
+	defer ticker.Stop()
+	for {
+		if err := w.syncDirectory(ctx); err != nil {
+			w.l.Errorf("sync directory failed: %v", err)
+			w.updateStatus(func(status *SourceStatus) { status.LastError = err.Error() })
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncDirectory audits the files changed since the last sync. The digests of the files are saved only if the SQLs
+// are audited, so the files are audited again in the next sync if sqled is unreachable.
+func (w *worker) syncDirectory(ctx context.Context) error {
+	fileSuffix, maxVariants := utils.SQLFileSuffix, 0
+	if w.cfg.Type == scannerCmd.TypeMySQLMybatis {
+		fileSuffix, maxVariants = utils.MybatisFileSuffix, w.cfg.MaxVariants
+	}
+	digests, err := digestFiles(w.cfg.Dir, fileSuffix)
+	if err != nil {
+		return err
+	}
+	changed := []string{}
+	for file, digest := range digests {
+		if w.state.Files[file] != digest {
+			changed = append(changed, file)
+		}
+	}
+	sort.Strings(changed)
+
+	sqls := []driverV2.Node{}
+	for _, file := range changed {
+		nodes, err := common.GetSQLFromFile(file, w.cfg.SkipErrorQuery, fileSuffix, false, maxVariants)
+		if err != nil {
+			if !w.cfg.SkipErrorFile {
+				return fmt.Errorf("parse file %s failed: %v", file, err)
+			}
+			// the file is skipped until it is changed
+			w.l.Warnf("skip file %s failed to parse: %v", file, err)
+			continue
+		}
+		sqls = append(sqls, nodes...)
+	}
+	if len(sqls) > 0 {
+		err := common.DirectAudit(ctx, w.auditClient, sqls, w.cfg.DbType, w.cfg.InstanceName, w.cfg.SchemaName)
+		if err != nil && !errors.Is(err, scanner.ErrAuditResult) {
+			return fmt.Errorf("audit %d changed files failed: %v", len(changed), err)
+		}
+	}
+
+	w.state.Files = digests
+	if err := w.store.save(w.cfg.Name, w.state); err != nil {
+		return err
+	}
+	now := time.Now()
+	w.updateStatus(func(status *SourceStatus) {
+		status.AuditedFiles += len(changed)
+		status.UploadedSQLs += len(sqls)
+		status.LastSyncAt = &now
+		status.LastError = ""
+	})
+	return nil
+}
+
+// digestFiles returns the sha256 of the files with the suffix in the directory.
+func digestFiles(dir, fileSuffix string) (map[string]string, error) {
+	digests := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), fileSuffix) {
+			return nil
+		}
+		f, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		digests[path] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	return digests, err
+}
+
+// runLog runs the log scanner until ctx is canceled or the scanner fails, the SQLs are buffered if they are failed
+// to upload.
+func (w *worker) runLog(ctx context.Context) error {
+	s, err := slowquery.New(&slowquery.Params{
+		LogFilePath:    w.cfg.LogFile,
+		AuditPlanID:    w.cfg.AuditPlanID,
+		IncludeUsers:   w.cfg.IncludeUsers,
+		ExcludeUsers:   w.cfg.ExcludeUsers,
+		IncludeSchemas: w.cfg.IncludeSchemas,
+		ExcludeSchemas: w.cfg.ExcludeSchemas,
+	}, w.l, w.client)
+	if err != nil {
+		return err
+	}
+	return w.runScanner(ctx, s)
+}
+
+func (w *worker) runScanner(ctx context.Context, s scanners.Scanner) error {
+	return supervisor.Start(ctx, &bufferedScanner{
+		Scanner: s,
+		w:       w,
+		buffer:  &sqlBuffer{path: w.store.bufferPath(w.cfg.Name), maxLen: w.cfg.MaxBufferedSQL},
+	}, leastPushSecond, pushBufferSize)
+}
+
+// bufferedScanner buffers the SQLs failed to upload, they are uploaded before the new SQLs in the next upload.
+type bufferedScanner struct {
+	scanners.Scanner
+	w      *worker
+	buffer *sqlBuffer
+}
+
+func (s *bufferedScanner) Upload(ctx context.Context, sqls []scanners.SQL, errorMessage string) error {
+	if errorMessage != "" {
+		return s.Scanner.Upload(ctx, sqls, errorMessage)
+	}
+	buffered, err := s.buffer.load()
+	if err != nil {
+		return err
+	}
+	pending := append(buffered, sqls...)
+
+	uploaded := 0
+	for uploaded < len(pending) {
+		end := uploaded + pushBufferSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		if err = s.Scanner.Upload(ctx, pending[uploaded:end], ""); err != nil {
+			s.w.l.Warnf("upload sqls failed, buffer %d sqls: %v", len(pending)-uploaded, err)
+			break
+		}
+		uploaded = end
+	}
+	dropped, saveErr := s.buffer.save(pending[uploaded:])
+	if saveErr != nil {
+		return saveErr
+	}
+
+	now := time.Now()
+	s.w.updateStatus(func(status *SourceStatus) {
+		status.UploadedSQLs += uploaded
+		status.BufferedSQLs = len(pending) - uploaded - dropped
+		status.DroppedSQLs += dropped
+		status.LastSyncAt = &now
+		if err != nil {
+			status.LastError = err.Error()
+		} else {
+			status.LastError = ""
+		}
+	})
+	return nil
+}
@@ -0,0 +1,169 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	scannerCmd "github.com/actiontech/sqle/sqle/cmd/scannerd/command"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAuditClient struct {
+	reqs []*scanner.CreateSqlAuditReq
+	err  error
+}
+
+func (c *mockAuditClient) DirectAudit(ctx context.Context, req *scanner.CreateSqlAuditReq) error {
+	c.reqs = append(c.reqs, req)
+	return c.err
+}
+
+type mockScanner struct {
+	uploaded [][]scanners.SQL
+	err      error
+}
+
+func (s *mockScanner) Run(ctx context.Context) error { return nil }
+
+func (s *mockScanner) SQLs() <-chan scanners.SQL { return nil }
+
+func (s *mockScanner) Upload(ctx context.Context, sqls []scanners.SQL, errorMessage string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.uploaded = append(s.uploaded, sqls)
+	return nil
+}
+
+func newTestWorker(t *testing.T, source *SourceConfig) *worker {
+	cfg := &Config{SQLE: SQLEConfig{Token: "t"}, StateDir: t.TempDir()}
+	w, err := newWorker(logrus.WithField("test", "test"), cfg, source)
+	assert.NoError(t, err)
+	return w
+}
+
+func TestStateStore(t *testing.T) {
+	store := &stateStore{dir: filepath.Join(t.TempDir(), "state")}
+	state, err := store.load("s1")
+	assert.NoError(t, err)
+	assert.Empty(t, state.Files)
+
+	state.Files["/data/a.sql"] = "digest"
+	assert.NoError(t, store.save("s1", state))
+	state, err = store.load("s1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"/data/a.sql": "digest"}, state.Files)
+}
+
+func TestSQLBuffer(t *testing.T) {
+	buffer := &sqlBuffer{path: filepath.Join(t.TempDir(), "s1.buffer.json"), maxLen: 2}
+	sqls, err := buffer.load()
+	assert.NoError(t, err)
+	assert.Empty(t, sqls)
+
+	dropped, err := buffer.save([]scanners.SQL{{RawText: "select 1"}, {RawText: "select 2"}, {RawText: "select 3"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+	sqls, err = buffer.load()
+	assert.NoError(t, err)
+	assert.Equal(t, []scanners.SQL{{RawText: "select 2"}, {RawText: "select 3"}}, sqls)
+
+	_, err = buffer.save(nil)
+	assert.NoError(t, err)
+	_, err = os.Stat(buffer.path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSyncDirectory(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.sql"), []byte("select 1;"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.sql"), []byte("select 2;"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("select 3;"), 0644))
+
+	w := newTestWorker(t, &SourceConfig{Name: "s1", Type: scannerCmd.TypeSQLFile, Dir: dir, DbType: "MySQL"})
+	client := &mockAuditClient{}
+	w.auditClient = client
+
+	// all files are audited in the first sync
+	assert.NoError(t, w.syncDirectory(context.TODO()))
+	assert.Len(t, client.reqs, 1)
+	assert.Equal(t, "select 1;select 2;", client.reqs[0].Sqls)
+	assert.Equal(t, 2, w.getStatus().AuditedFiles)
+
+	// the files not changed are not audited again
+	assert.NoError(t, w.syncDirectory(context.TODO()))
+	assert.Len(t, client.reqs, 1)
+
+	// only the changed file is audited, the sql audited with error level is audited successfully
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.sql"), []byte("delete from t1;"), 0644))
+	client.err = fmt.Errorf("audit failed: %w", scanner.ErrAuditResult)
+	assert.NoError(t, w.syncDirectory(context.TODO()))
+	assert.Len(t, client.reqs, 2)
+	assert.Equal(t, "delete from t1;", client.reqs[1].Sqls)
+
+	// the changed file is audited again in the next sync if sqled is unreachable
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.sql"), []byte("select 4;"), 0644))
+	client.err = errors.New("connection refused")
+	assert.Error(t, w.syncDirectory(context.TODO()))
+	client.err = nil
+	assert.NoError(t, w.syncDirectory(context.TODO()))
+	assert.Len(t, client.reqs, 4)
+	assert.Equal(t, "select 4;", client.reqs[3].Sqls)
+
+	// the state is persisted
+	state, err := w.store.load("s1")
+	assert.NoError(t, err)
+	assert.Len(t, state.Files, 2)
+}
+
+func TestBufferedScannerUpload(t *testing.T) {
+	w := newTestWorker(t, &SourceConfig{Name: "s1", Type: scannerCmd.TypeMySQLSlowLog, MaxBufferedSQL: 100})
+	s := &mockScanner{err: errors.New("connection refused")}
+	bs := &bufferedScanner{Scanner: s, w: w, buffer: &sqlBuffer{path: w.store.bufferPath("s1"), maxLen: 100}}
+
+	// the sqls are buffered if sqled is unreachable
+	assert.NoError(t, bs.Upload(context.TODO(), []scanners.SQL{{RawText: "select 1"}}, ""))
+	assert.Equal(t, 1, w.getStatus().BufferedSQLs)
+	assert.Equal(t, "connection refused", w.getStatus().LastError)
+
+	// the buffered sqls are uploaded before the new sqls
+	s.err = nil
+	assert.NoError(t, bs.Upload(context.TODO(), []scanners.SQL{{RawText: "select 2"}}, ""))
+	assert.Len(t, s.uploaded, 1)
+	assert.Equal(t, []scanners.SQL{{RawText: "select 1"}, {RawText: "select 2"}}, s.uploaded[0])
+	status := w.getStatus()
+	assert.Equal(t, 0, status.BufferedSQLs)
+	assert.Equal(t, 2, status.UploadedSQLs)
+	assert.Empty(t, status.LastError)
+}
+
+func TestStatusHandler(t *testing.T) {
+	d := &Daemon{configPath: "scannerd.yaml", workers: map[string]*worker{}}
+	d.workers["s2"] = newTestWorker(t, &SourceConfig{Name: "s2", Type: scannerCmd.TypeSQLFile})
+	d.workers["s1"] = newTestWorker(t, &SourceConfig{Name: "s1", Type: scannerCmd.TypeMySQLMybatis})
+
+	rec := httptest.NewRecorder()
+	d.statusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	status := &Status{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), status))
+	assert.Equal(t, "scannerd.yaml", status.ConfigFile)
+	assert.Len(t, status.Sources, 2)
+	assert.Equal(t, "s1", status.Sources[0].Name)
+	assert.Equal(t, SourceStateRunning, status.Sources[0].State)
+
+	rec = httptest.NewRecorder()
+	d.statusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/status", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/actiontech/sqle/sqle/utils"
 )
 
-func GetSQLFromPath(pathName string, skipErrorQuery, skipErrorFile bool, fileSuffix string, showFileContent bool) (allSQL []driverV2.Node, err error) {
+// GetSQLFromPath gets the SQL from the files in the path, the MyBatis statement is expanded to at most `maxVariants`
+// variants of its dynamic SQL, or flattened into one SQL if `maxVariants` is 0.
+func GetSQLFromPath(pathName string, skipErrorQuery, skipErrorFile bool, fileSuffix string, showFileContent bool, maxVariants int) (allSQL []driverV2.Node, err error) {
 	if !path.IsAbs(pathName) {
 		pwd, err := os.Getwd()
 		if err != nil {
@@ -32,9 +34,9 @@ func GetSQLFromPath(pathName string, skipErrorQuery, skipErrorFile bool, fileSuf
 		pathJoin := path.Join(pathName, fi.Name())
 
 		if fi.IsDir() {
-			sqlList, err = GetSQLFromPath(pathJoin, skipErrorQuery, skipErrorFile, fileSuffix, showFileContent)
+			sqlList, err = GetSQLFromPath(pathJoin, skipErrorQuery, skipErrorFile, fileSuffix, showFileContent, maxVariants)
 		} else if strings.HasSuffix(fi.Name(), fileSuffix) {
-			sqlList, err = GetSQLFromFile(pathJoin, skipErrorQuery, fileSuffix, showFileContent)
+			sqlList, err = GetSQLFromFile(pathJoin, skipErrorQuery, fileSuffix, showFileContent, maxVariants)
 		}
 
 		if err != nil {
@@ -49,13 +51,26 @@ func GetSQLFromPath(pathName string, skipErrorQuery, skipErrorFile bool, fileSuf
 	return allSQL, err
 }
 
-func GetSQLFromFile(file string, skipErrorQuery bool, fileSuffix string, showFileContent bool) (r []driverV2.Node, err error) {
+func GetSQLFromFile(file string, skipErrorQuery bool, fileSuffix string, showFileContent bool, maxVariants int) (r []driverV2.Node, err error) {
 	content, err := ReadFileContent(file)
 	if err != nil {
 		return nil, err
 	}
 	switch fileSuffix {
 	case utils.MybatisFileSuffix:
+		if maxVariants > 0 {
+			variants, err := ParseMybatisVariants(content, maxVariants, skipErrorQuery)
+			if err != nil {
+				if showFileContent {
+					fmt.Printf("failed to parse xml file content: %s", content)
+				}
+				return nil, err
+			}
+			// the iBatis sqlMap is not expanded
+			if variants != nil {
+				return getSQLFromMybatisVariants(variants, skipErrorQuery)
+			}
+		}
 		var sqls []string
 		var err error
 		if skipErrorQuery {
@@ -92,6 +107,27 @@ func GetSQLFromFile(file string, skipErrorQuery bool, fileSuffix string, showFil
 	return r, nil
 }
 
+// getSQLFromMybatisVariants parses the variants, the statement and the branch conditions of the variant are kept as
+// a comment ahead of the SQL, so that the audit result can be traced back to the branches. The variants failed to
+// parse are skipped if `skipErrorQuery` is true.
+func getSQLFromMybatisVariants(variants []*MybatisVariant, skipErrorQuery bool) (r []driverV2.Node, err error) {
+	for _, variant := range variants {
+		n, err := Parse(context.TODO(), variant.SQL)
+		if err != nil {
+			if skipErrorQuery {
+				continue
+			}
+			return nil, fmt.Errorf("parse the variant of mybatis statement %s failed: %v", variant.StatementId, err)
+		}
+		for i := range n {
+			n[i].Text = fmt.Sprintf("%s %s", variant.Comment(), n[i].Text)
+			n[i].StartLine = variant.StartLine
+		}
+		r = append(r, n...)
+	}
+	return r, nil
+}
+
 func ReadFileContent(file string) (content string, err error) {
 	data, err := ioutil.ReadFile(filepath.Clean(file))
 	if err != nil {
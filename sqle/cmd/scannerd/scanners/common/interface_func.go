@@ -46,7 +46,12 @@ func Audit(c *scanner.Client, apName string) error {
 	return c.GetAuditReportReq(apName, reportID)
 }
 
-func DirectAudit(ctx context.Context, c *scanner.Client, sqlList []driverV2.Node, dbType, instName, schemaName string) error {
+// DirectAuditClient audits the SQL directly, it is the SQLE client, or the local auditor in the local mode.
+type DirectAuditClient interface {
+	DirectAudit(ctx context.Context, sqlAuditReq *scanner.CreateSqlAuditReq) error
+}
+
+func DirectAudit(ctx context.Context, c DirectAuditClient, sqlList []driverV2.Node, dbType, instName, schemaName string) error {
 	sqlAuditReq := new(scanner.CreateSqlAuditReq)
 	sqlAuditReq.DbType = dbType
 	sqlAuditReq.InstanceName = instName
@@ -0,0 +1,454 @@
+package common
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	mybatisAST "github.com/actiontech/mybatis-mapper-2-sql/ast"
+	"github.com/pingcap/parser/ast"
+)
+
+// the max depth of the nested <include>, it avoids the infinite recursion of the cyclic references
+const maxMybatisIncludeDepth = 10
+
+// MybatisVariant is a SQL of the MyBatis statement with the dynamic branches resolved, Conditions are the test
+// expressions of the branches producing it.
+type MybatisVariant struct {
+	StatementId string
+	StartLine   uint64
+	SQL         string
+	Conditions  []string
+}
+
+// Comment describes the statement and the branch conditions of the variant as a SQL comment.
+func (v *MybatisVariant) Comment() string {
+	desc := fmt.Sprintf("mybatis statement: %s", v.StatementId)
+	if len(v.Conditions) > 0 {
+		conditions := make([]string, 0, len(v.Conditions))
+		for _, condition := range v.Conditions {
+			if !strings.HasPrefix(condition, "NOT (") {
+				condition = fmt.Sprintf("(%s)", condition)
+			}
+			conditions = append(conditions, condition)
+		}
+		desc = fmt.Sprintf("%s, conditions: %s", desc, strings.Join(conditions, " AND "))
+	}
+	return fmt.Sprintf("/* %s */", strings.ReplaceAll(desc, "*/", "* /"))
+}
+
+// ParseMybatisVariants parses the MyBatis mapper XML, and enumerates at most `maxVariants` variants of each statement
+// by turning the <if> on and off and choosing each branch of the <choose>. It returns nil if the XML is not a MyBatis
+// mapper, such as the iBatis sqlMap.
+//
+// All the statements are enumerated if the count of the combinations of the branches does not exceed `maxVariants`,
+// otherwise the variants with all branches on, all branches off and only one branch changed are enumerated in order.
+// The variants producing the same SQL are merged, and the variants failed to be parsed are dropped except the first
+// one with all branches on.
+func ParseMybatisVariants(content string, maxVariants int, skipErrorQuery bool) ([]*MybatisVariant, error) {
+	mapper, err := parseMybatisMapper(content)
+	if err != nil {
+		return nil, err
+	}
+	if mapper == nil {
+		return nil, nil
+	}
+	ctx := mybatisAST.NewContext(&mybatisAST.Config{SkipErrorQuery: skipErrorQuery, RestoreOriginSql: true})
+	ctx.Sqls = mapper.SqlNodes
+	ctx.DefaultNamespace = mapper.NameSpace
+
+	variants := []*MybatisVariant{}
+	for _, query := range mapper.QueryNodes {
+		queryVariants, err := expandMybatisQuery(ctx, query, maxVariants)
+		if err != nil {
+			if skipErrorQuery {
+				continue
+			}
+			return nil, fmt.Errorf("expand %s %s failed: %v", query.Type, query.Id, err)
+		}
+		variants = append(variants, queryVariants...)
+	}
+	return variants, nil
+}
+
+func expandMybatisQuery(ctx *mybatisAST.Context, query *mybatisAST.QueryNode, maxVariants int) ([]*MybatisVariant, error) {
+	ctx.QueryType = query.Type
+	collector := &mybatisVariantRenderer{ctx: ctx}
+	if _, err := collector.render(query.Children, true, 0); err != nil {
+		return nil, err
+	}
+
+	variants := []*MybatisVariant{}
+	sqlSet := map[string]struct{}{}
+	for i, choices := range enumerateBranchChoices(collector.optionCounts, maxVariants) {
+		r := &mybatisVariantRenderer{ctx: ctx, choices: choices}
+		sql, err := r.render(query.Children, true, 0)
+		if err != nil {
+			return nil, err
+		}
+		sql = strings.TrimSpace(sql)
+		key := strings.Join(strings.Fields(sql), " ")
+		if _, ok := sqlSet[key]; ok {
+			continue
+		}
+		sqlSet[key] = struct{}{}
+		if i > 0 && !isParsableSQL(sql) {
+			continue
+		}
+		variants = append(variants, &MybatisVariant{
+			StatementId: query.Id,
+			StartLine:   query.StartLine,
+			SQL:         sql,
+			Conditions:  r.conditions,
+		})
+	}
+	return variants, nil
+}
+
+func isParsableSQL(sql string) bool {
+	nodes, err := ParseSql(sql)
+	if err != nil {
+		return false
+	}
+	for _, node := range nodes {
+		if _, ok := node.(*ast.UnparsedStmt); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// enumerateBranchChoices returns the chosen options of the branches of each variant, option 0 of every branch is
+// "on", and the last option is "off".
+func enumerateBranchChoices(optionCounts []int, maxVariants int) [][]int {
+	if maxVariants < 1 {
+		maxVariants = 1
+	}
+	total := 1
+	for _, count := range optionCounts {
+		total *= count
+		if total > maxVariants {
+			break
+		}
+	}
+
+	result := [][]int{}
+	if total <= maxVariants {
+		choices := make([]int, len(optionCounts))
+		for {
+			result = append(result, append([]int{}, choices...))
+			// increase the choices as a mixed radix number
+			i := len(choices) - 1
+			for ; i >= 0; i-- {
+				choices[i]++
+				if choices[i] < optionCounts[i] {
+					break
+				}
+				choices[i] = 0
+			}
+			if i < 0 {
+				return result
+			}
+		}
+	}
+
+	add := func(choices []int) bool {
+		if len(result) >= maxVariants {
+			return false
+		}
+		result = append(result, choices)
+		return true
+	}
+	add(make([]int, len(optionCounts)))
+	allOff := make([]int, len(optionCounts))
+	for i, count := range optionCounts {
+		allOff[i] = count - 1
+	}
+	add(allOff)
+	for i, count := range optionCounts {
+		for option := 1; option < count; option++ {
+			choices := make([]int, len(optionCounts))
+			choices[i] = option
+			if !add(choices) {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// mybatisVariantRenderer renders the SQL of the statement by the chosen options of the branches. The branches are
+// visited in the same order in every rendering, even if they are in an inactive branch, so that the choices are
+// matched with the branches by the order. It collects the option counts of the branches if `choices` is nil.
+type mybatisVariantRenderer struct {
+	ctx *mybatisAST.Context
+
+	choices      []int
+	next         int
+	optionCounts []int
+	conditions   []string
+}
+
+func (r *mybatisVariantRenderer) choose(optionCount int) int {
+	if r.choices == nil {
+		r.optionCounts = append(r.optionCounts, optionCount)
+		return 0
+	}
+	choice := r.choices[r.next]
+	r.next++
+	return choice
+}
+
+func (r *mybatisVariantRenderer) render(nodes []mybatisAST.Node, active bool, depth int) (string, error) {
+	buff := strings.Builder{}
+	for _, node := range nodes {
+		data, err := r.renderNode(node, active, depth)
+		if err != nil {
+			return "", err
+		}
+		// the text is trimmed in the XML, so the children are separated by the whitespace
+		buff.WriteString(data)
+		buff.WriteString(" ")
+	}
+	return buff.String(), nil
+}
+
+func (r *mybatisVariantRenderer) renderNode(node mybatisAST.Node, active bool, depth int) (string, error) {
+	switch n := node.(type) {
+	case *mybatisAST.IfNode:
+		on := r.choose(2) == 0
+		if active {
+			if on {
+				r.conditions = append(r.conditions, n.Expression)
+			} else {
+				r.conditions = append(r.conditions, negateCondition(n.Expression))
+			}
+		}
+		body, err := r.render(n.Children, active && on, depth)
+		if err != nil || !on {
+			return "", err
+		}
+		return body, nil
+	case *mybatisAST.ChooseNode:
+		// the option after the <when> is the <otherwise>, or nothing if it is not defined
+		choice := r.choose(len(n.When) + 1)
+		var result string
+		for i, when := range n.When {
+			body, err := r.render(when.Children, active && choice == i, depth)
+			if err != nil {
+				return "", err
+			}
+			if choice == i {
+				result = body
+			}
+		}
+		if n.Otherwise != nil {
+			body, err := r.render(n.Otherwise.Children, active && choice == len(n.When), depth)
+			if err != nil {
+				return "", err
+			}
+			if choice == len(n.When) {
+				result = body
+			}
+		}
+		if active {
+			// the first <when> whose test is true is chosen
+			for i := 0; i < choice && i < len(n.When); i++ {
+				r.conditions = append(r.conditions, negateCondition(n.When[i].Expression))
+			}
+			if choice < len(n.When) {
+				r.conditions = append(r.conditions, n.When[choice].Expression)
+			}
+		}
+		return result, nil
+	case *mybatisAST.TrimNode:
+		body, err := r.render(n.Children, active, depth)
+		if err != nil {
+			return "", err
+		}
+		return trimMybatisBody(n, body), nil
+	case *mybatisAST.ForeachNode:
+		item, err := r.render(n.Children, active, depth)
+		if err != nil {
+			return "", err
+		}
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return "", nil
+		}
+		// render the collection as two items, the same as the flattened statement
+		return fmt.Sprintf(" %s%s%s%s%s ", n.Open, item, n.Separator, item, n.Close), nil
+	case *mybatisAST.IncludeNode:
+		if depth >= maxMybatisIncludeDepth {
+			return "", fmt.Errorf("the <include> is nested more than %d levels", maxMybatisIncludeDepth)
+		}
+		for _, p := range n.Properties {
+			r.ctx.SetVariable(p.Name, p.Value)
+		}
+		var refId string
+		switch it := n.RefId.(type) {
+		case mybatisAST.Value:
+			refId = string(it)
+		case *mybatisAST.Variable:
+			variable, ok := r.ctx.GetVariable(it.Name)
+			if !ok {
+				return "", fmt.Errorf("variable %s is undefined", it.Name)
+			}
+			refId = variable
+		}
+		sql, ok := r.ctx.GetSql(refId)
+		if !ok {
+			return "", fmt.Errorf("sql %s is not exist", refId)
+		}
+		return r.render(sql.Children, active, depth+1)
+	default:
+		return node.GetStmt(r.ctx)
+	}
+}
+
+func negateCondition(expression string) string {
+	return fmt.Sprintf("NOT (%s)", expression)
+}
+
+// trimMybatisBody renders the <where>, <set> and <trim>, the prefix and the suffix are omitted if the body is empty
+// like MyBatis does.
+func trimMybatisBody(n *mybatisAST.TrimNode, body string) string {
+	body = strings.TrimSpace(body)
+	for _, override := range n.PrefixOverrides {
+		token := strings.TrimSpace(override)
+		if token == "" || len(body) < len(token) || !strings.EqualFold(body[:len(token)], token) {
+			continue
+		}
+		// the override "AND " matches "AND\n" but not "ANDROID"
+		if token != override && len(body) > len(token) && !unicode.IsSpace(rune(body[len(token)])) {
+			continue
+		}
+		body = strings.TrimSpace(body[len(token):])
+		break
+	}
+	for _, override := range n.SuffixOverrides {
+		token := strings.TrimSpace(override)
+		if token != "" && len(body) >= len(token) && strings.EqualFold(body[len(body)-len(token):], token) {
+			body = strings.TrimSpace(body[:len(body)-len(token)])
+			break
+		}
+	}
+	if body == "" {
+		return " "
+	}
+	return fmt.Sprintf(" %s %s %s ", n.Prefix, body, n.Suffix)
+}
+
+// parseMybatisMapper parses the MyBatis mapper XML to the AST, it returns nil if the root element is not <mapper>.
+func parseMybatisMapper(content string) (*mybatisAST.Mapper, error) {
+	d := xml.NewDecoder(strings.NewReader(content))
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		st, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if st.Name.Local != "mapper" {
+			return nil, nil
+		}
+		node, err := parseMybatisNode(d, &st)
+		if err != nil {
+			return nil, err
+		}
+		mapper, ok := node.(*mybatisAST.Mapper)
+		if !ok {
+			return nil, fmt.Errorf("the mapper is not found")
+		}
+		return mapper, nil
+	}
+}
+
+func parseMybatisNode(d *xml.Decoder, start *xml.StartElement) (mybatisAST.Node, error) {
+	node, err := newMybatisNode(d, start)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return node, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tt := t.(type) {
+		case xml.StartElement:
+			child, err := parseMybatisNode(d, &tt)
+			if err != nil {
+				return nil, err
+			}
+			if child == nil {
+				continue
+			}
+			if node == nil {
+				node = child
+			} else if err := node.AddChildren(child); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if tt.Name == start.Name {
+				return node, nil
+			}
+		case xml.CharData:
+			if strings.TrimSpace(string(tt)) == "" || node == nil {
+				continue
+			}
+			data := mybatisAST.NewMyBatisData(tt.Copy())
+			if err := data.ScanData(); err != nil {
+				return nil, err
+			}
+			if err := node.AddChildren(data); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func newMybatisNode(d *xml.Decoder, start *xml.StartElement) (mybatisAST.Node, error) {
+	var node mybatisAST.Node
+	switch start.Name.Local {
+	case "mapper":
+		node = mybatisAST.NewMapper()
+	case "sql":
+		node = mybatisAST.NewSqlNode()
+	case "include":
+		node = mybatisAST.NewIncludeNode()
+	case "property":
+		node = mybatisAST.NewPropertyNode()
+	case "select", "update", "delete", "insert":
+		startLine, _ := d.InputPos()
+		node = mybatisAST.NewQueryNode(uint64(startLine))
+	case "if":
+		node = mybatisAST.NewIfNode()
+	case "choose":
+		node = mybatisAST.NewChooseNode()
+	case "when":
+		node = mybatisAST.NewWhenNode()
+	case "otherwise":
+		node = mybatisAST.NewOtherwiseNode()
+	case "where", "set", "trim":
+		node = mybatisAST.NewTrimNode()
+	case "foreach":
+		node = mybatisAST.NewForeachNode()
+	default:
+		return nil, nil
+	}
+	if err := node.Scan(start); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMybatisMapper = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="test.UserMapper">
+    <sql id="userFilter">
+        <if test="name != null">AND name = #{name}</if>
+    </sql>
+    <delete id="deleteUser">
+        DELETE FROM users
+        <where>
+            <if test="id != null">id = #{id}</if>
+        </where>
+    </delete>
+    <select id="listUsers">
+        SELECT * FROM users
+        <where>
+            <choose>
+                <when test="id != null">id = #{id}</when>
+                <when test="email != null">email = #{email}</when>
+                <otherwise>status = 1</otherwise>
+            </choose>
+            <include refid="userFilter"/>
+        </where>
+    </select>
+    <update id="updateUsers">
+        UPDATE users
+        <set>
+            <if test="name != null">name = #{name},</if>
+            <if test="email != null">email = #{email},</if>
+        </set>
+        WHERE id IN
+        <foreach collection="ids" item="id" open="(" separator="," close=")">#{id}</foreach>
+    </update>
+</mapper>`
+
+func normalizeVariants(variants []*MybatisVariant, statementId string) map[string][]string {
+	result := map[string][]string{}
+	for _, v := range variants {
+		if v.StatementId == statementId {
+			result[strings.Join(strings.Fields(v.SQL), " ")] = v.Conditions
+		}
+	}
+	return result
+}
+
+func TestParseMybatisVariants(t *testing.T) {
+	variants, err := ParseMybatisVariants(testMybatisMapper, 16, false)
+	assert.NoError(t, err)
+
+	// the unfiltered DELETE is produced if the test is false
+	assert.Equal(t, map[string][]string{
+		"DELETE FROM users WHERE id = ?": {"id != null"},
+		"DELETE FROM users":              {"NOT (id != null)"},
+	}, normalizeVariants(variants, "deleteUser"))
+
+	assert.Equal(t, map[string][]string{
+		"SELECT * FROM users WHERE id = ? AND name = ?":     {"id != null", "name != null"},
+		"SELECT * FROM users WHERE id = ?":                  {"id != null", "NOT (name != null)"},
+		"SELECT * FROM users WHERE email = ? AND name = ?":  {"NOT (id != null)", "email != null", "name != null"},
+		"SELECT * FROM users WHERE email = ?":               {"NOT (id != null)", "email != null", "NOT (name != null)"},
+		"SELECT * FROM users WHERE status = 1 AND name = ?": {"NOT (id != null)", "NOT (email != null)", "name != null"},
+		"SELECT * FROM users WHERE status = 1":              {"NOT (id != null)", "NOT (email != null)", "NOT (name != null)"},
+	}, normalizeVariants(variants, "listUsers"))
+
+	// the UPDATE without the SET clause can not be parsed, so it is dropped
+	assert.Equal(t, map[string][]string{
+		"UPDATE users SET name = ?, email = ? WHERE id IN (?,?)": {"name != null", "email != null"},
+		"UPDATE users SET name = ? WHERE id IN (?,?)":            {"name != null", "NOT (email != null)"},
+		"UPDATE users SET email = ? WHERE id IN (?,?)":           {"NOT (name != null)", "email != null"},
+	}, normalizeVariants(variants, "updateUsers"))
+}
+
+func TestParseMybatisVariantsWithLimit(t *testing.T) {
+	variants, err := ParseMybatisVariants(testMybatisMapper, 2, false)
+	assert.NoError(t, err)
+
+	// the variants with all branches on and all branches off are enumerated first
+	assert.Equal(t, map[string][]string{
+		"SELECT * FROM users WHERE id = ? AND name = ?": {"id != null", "name != null"},
+		"SELECT * FROM users WHERE status = 1":          {"NOT (id != null)", "NOT (email != null)", "NOT (name != null)"},
+	}, normalizeVariants(variants, "listUsers"))
+}
+
+func TestParseMybatisVariantsNotMapper(t *testing.T) {
+	variants, err := ParseMybatisVariants(`<sqlMap namespace="test"><select id="q">SELECT 1</select></sqlMap>`, 16, false)
+	assert.NoError(t, err)
+	assert.Nil(t, variants)
+}
+
+func TestMybatisVariantComment(t *testing.T) {
+	v := &MybatisVariant{StatementId: "deleteUser", Conditions: []string{"id != null", "ids.size() > 0 */"}}
+	assert.Equal(t, "/* mybatis statement: deleteUser, conditions: (id != null) AND (ids.size() > 0 * /) */", v.Comment())
+
+	v = &MybatisVariant{StatementId: "deleteUser"}
+	assert.Equal(t, "/* mybatis statement: deleteUser */", v.Comment())
+}
+
+func TestGetSQLFromFileWithVariants(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "user.xml")
+	assert.NoError(t, os.WriteFile(file, []byte(testMybatisMapper), 0644))
+
+	// each statement is flattened into one SQL by default
+	nodes, err := GetSQLFromFile(file, false, utils.MybatisFileSuffix, false, 0)
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 3)
+
+	nodes, err = GetSQLFromFile(file, true, utils.MybatisFileSuffix, false, 16)
+	assert.NoError(t, err)
+	assert.Greater(t, len(nodes), 3)
+	for _, n := range nodes {
+		assert.True(t, strings.HasPrefix(n.Text, "/* mybatis statement: "), n.Text)
+	}
+}
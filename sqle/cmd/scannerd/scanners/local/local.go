@@ -0,0 +1,262 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
+	"github.com/actiontech/sqle/sqle/driver/mysql"
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	OutputFormatText = "text"
+	OutputFormatJson = "json"
+)
+
+// the rules require the connection to the instance, they are ignored in the local mode
+var instanceRequiredRules = map[string]struct{}{
+	rulepkg.ConfigDDLOSCMinSize:            {},
+	rulepkg.ConfigDDLGhostMinSize:          {},
+	rulepkg.ConfigDMLExplainPreCheckEnable: {},
+}
+
+// ruleTemplateFile is the rule template file exported by SQLE in the json format.
+type ruleTemplateFile struct {
+	Name     string
+	DBType   string `json:"db_type"`
+	RuleList []struct {
+		Name   string
+		Level  string
+		Params []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}
+	}
+}
+
+type Params struct {
+	RuleTemplateFile string
+	SchemaFile       string
+	OutputFormat     string
+	FailLevel        string
+}
+
+// Auditor audits the SQL by the built-in MySQL driver without SQLE server, it replaces the SQLE client in the local
+// mode of the scanner.
+type Auditor struct {
+	l   *logrus.Entry
+	out io.Writer
+
+	rules        []*driverV2.Rule
+	schemaFile   string
+	outputFormat string
+	failLevel    driverV2.RuleLevel
+}
+
+var _ common.DirectAuditClient = (*Auditor)(nil)
+
+func New(params *Params, l *logrus.Entry) (*Auditor, error) {
+	switch params.OutputFormat {
+	case "", OutputFormatText, OutputFormatJson:
+	default:
+		return nil, fmt.Errorf("unsupported output format %s, it should be %s or %s", params.OutputFormat, OutputFormatText, OutputFormatJson)
+	}
+	failLevel := driverV2.RuleLevel(params.FailLevel)
+	switch failLevel {
+	case driverV2.RuleLevelNormal, driverV2.RuleLevelNotice, driverV2.RuleLevelWarn, driverV2.RuleLevelError:
+	default:
+		return nil, fmt.Errorf("unsupported fail level %s", params.FailLevel)
+	}
+	rules, err := LoadRuleTemplateFile(params.RuleTemplateFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Auditor{
+		l:            l,
+		out:          os.Stdout,
+		rules:        rules,
+		schemaFile:   params.SchemaFile,
+		outputFormat: params.OutputFormat,
+		failLevel:    failLevel,
+	}, nil
+}
+
+// LoadRuleTemplateFile loads the rules of the rule template file exported by SQLE in the json format, the level and
+// the params of the rules are overwritten by the file.
+func LoadRuleTemplateFile(file string) ([]*driverV2.Rule, error) {
+	content, err := common.ReadFileContent(file)
+	if err != nil {
+		return nil, err
+	}
+	template := &ruleTemplateFile{}
+	if err := json.Unmarshal([]byte(content), template); err != nil {
+		return nil, fmt.Errorf("the rule template file should be exported in the json format, error: %v", err)
+	}
+	if template.DBType != "" && template.DBType != driverV2.DriverTypeMySQL {
+		return nil, fmt.Errorf("unsupported db type %s of rule template %s, only %s is supported in the local mode",
+			template.DBType, template.Name, driverV2.DriverTypeMySQL)
+	}
+
+	rules := make([]*driverV2.Rule, 0, len(template.RuleList))
+	for _, r := range template.RuleList {
+		if _, ok := instanceRequiredRules[r.Name]; ok {
+			continue
+		}
+		handler, ok := rulepkg.GetRuleHandlerFromAllRules(r.Name)
+		if !ok {
+			logrus.StandardLogger().Warnf("rule %s is not supported by the built-in driver, skip it", r.Name)
+			continue
+		}
+		rule := handler.Rule
+		rule.Level = driverV2.RuleLevel(r.Level)
+		rule.Params = handler.Rule.Params.Copy()
+		for _, p := range r.Params {
+			if err := rule.Params.SetParamValue(p.Key, p.Value); err != nil {
+				return nil, fmt.Errorf("set param of rule %s failed: %v", r.Name, err)
+			}
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, nil
+}
+
+type auditResult struct {
+	Level    string `json:"level"`
+	RuleName string `json:"rule_name"`
+	Message  string `json:"message"`
+}
+
+type auditSQL struct {
+	Number       int            `json:"number"`
+	SQL          string         `json:"sql"`
+	AuditLevel   string         `json:"audit_level"`
+	AuditResults []*auditResult `json:"audit_results"`
+}
+
+type auditReport struct {
+	TotalCount   int         `json:"total_count"`
+	ErrorCount   int         `json:"error_count"`
+	WarningCount int         `json:"warning_count"`
+	FailLevel    string      `json:"fail_level"`
+	FailCount    int         `json:"fail_count"`
+	SQLs         []*auditSQL `json:"sqls"`
+}
+
+// DirectAudit audits the SQL of the request and prints the report, it returns an error if the audit level of any SQL
+// reaches the fail level.
+func (a *Auditor) DirectAudit(ctx context.Context, sqlAuditReq *scanner.CreateSqlAuditReq) error {
+	if sqlAuditReq.DbType != "" && sqlAuditReq.DbType != driverV2.DriverTypeMySQL {
+		return fmt.Errorf("unsupported db type %s, only %s is supported in the local mode", sqlAuditReq.DbType, driverV2.DriverTypeMySQL)
+	}
+	inspect, err := a.newInspect(sqlAuditReq.InstanceSchema)
+	if err != nil {
+		return err
+	}
+	defer inspect.Close(ctx)
+
+	nodes, err := inspect.Parse(ctx, sqlAuditReq.Sqls)
+	if err != nil {
+		return err
+	}
+	report := &auditReport{FailLevel: string(a.failLevel), SQLs: make([]*auditSQL, 0, len(nodes))}
+	for i, node := range nodes {
+		results, err := inspect.Audit(ctx, []string{node.Text})
+		if err != nil {
+			return fmt.Errorf("audit sql %s failed: %v", node.Text, err)
+		}
+		sql := &auditSQL{
+			Number:       i + 1,
+			SQL:          node.Text,
+			AuditLevel:   string(results[0].Level()),
+			AuditResults: make([]*auditResult, 0, len(results[0].Results)),
+		}
+		for _, result := range results[0].Results {
+			sql.AuditResults = append(sql.AuditResults, &auditResult{
+				Level:    string(result.Level),
+				RuleName: result.RuleName,
+				Message:  result.I18nAuditResultInfo[i18nPkg.DefaultLang].Message,
+			})
+		}
+		report.SQLs = append(report.SQLs, sql)
+
+		report.TotalCount++
+		switch results[0].Level() {
+		case driverV2.RuleLevelError:
+			report.ErrorCount++
+		case driverV2.RuleLevelWarn:
+			report.WarningCount++
+		}
+		if results[0].Level().MoreOrEqual(a.failLevel) {
+			report.FailCount++
+		}
+	}
+
+	if err := a.print(report); err != nil {
+		return err
+	}
+	if report.FailCount > 0 {
+		return fmt.Errorf("the audit level of %d sqls reaches %s", report.FailCount, a.failLevel)
+	}
+	return nil
+}
+
+// newInspect creates the offline inspector, or the inspector auditing with the schema snapshot if it is provided.
+func (a *Auditor) newInspect(schemaName string) (*mysql.MysqlDriverImpl, error) {
+	cfg := &driverV2.Config{Rules: a.rules}
+	if a.schemaFile == "" {
+		inspect, err := mysql.NewInspect(a.l, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if schemaName != "" {
+			inspect.Ctx.SetCurrentSchema(schemaName)
+		}
+		return inspect, nil
+	}
+
+	content, err := common.ReadFileContent(a.schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := common.ParseSql(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema file %s failed: %v", a.schemaFile, err)
+	}
+	ctx := session.NewContext(nil)
+	ctx.AddSystemVariable(session.SysVarLowerCaseTableNames, "0")
+	ctx.LoadSchemaSnapshot(nodes)
+	if schemaName != "" {
+		ctx.SetCurrentSchema(schemaName)
+	}
+	return mysql.NewInspectWithContext(a.l, cfg, ctx), nil
+}
+
+func (a *Auditor) print(report *auditReport) error {
+	if a.outputFormat == OutputFormatJson {
+		encoder := json.NewEncoder(a.out)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	fmt.Fprintln(a.out, "---------------------------------------------------------")
+	for _, sql := range report.SQLs {
+		fmt.Fprintln(a.out, sql.SQL)
+		for _, result := range sql.AuditResults {
+			fmt.Fprintf(a.out, "[%s]%s\n", result.Level, result.Message)
+		}
+		fmt.Fprintln(a.out, "---------------------------------------------------------")
+	}
+	fmt.Fprintf(a.out, "total sqls: %d, error sqls: %d, warning sqls: %d\n", report.TotalCount, report.ErrorCount, report.WarningCount)
+	return nil
+}
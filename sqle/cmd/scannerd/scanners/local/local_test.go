@@ -0,0 +1,137 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRuleTemplate = `{
+	"Name": "test_template",
+	"RuleVersion": 2,
+	"db_type": "MySQL",
+	"RuleList": [
+		{"Name": "all_check_where_is_invalid", "Level": "error", "Params": []},
+		{"Name": "ddl_check_index_count", "Level": "warn", "Params": [{"key": "first_key", "value": "8", "type": "int"}]},
+		{"Name": "dml_enable_explain_pre_check", "Level": "warn", "Params": []},
+		{"Name": "not_exist_rule", "Level": "warn", "Params": []}
+	]
+}`
+
+func newTestAuditor(t *testing.T, schema string, outputFormat, failLevel string) (*Auditor, *bytes.Buffer) {
+	dir := t.TempDir()
+	templateFile := filepath.Join(dir, "template.json")
+	assert.NoError(t, os.WriteFile(templateFile, []byte(testRuleTemplate), 0644))
+	params := &Params{RuleTemplateFile: templateFile, OutputFormat: outputFormat, FailLevel: failLevel}
+	if schema != "" {
+		params.SchemaFile = filepath.Join(dir, "schema.sql")
+		assert.NoError(t, os.WriteFile(params.SchemaFile, []byte(schema), 0644))
+	}
+	auditor, err := New(params, logrus.WithField("test", "test"))
+	assert.NoError(t, err)
+	out := &bytes.Buffer{}
+	auditor.out = out
+	return auditor, out
+}
+
+func TestLoadRuleTemplateFile(t *testing.T) {
+	auditor, _ := newTestAuditor(t, "", OutputFormatText, "error")
+	// the rule requiring the instance and the unknown rule are skipped
+	assert.Len(t, auditor.rules, 2)
+	assert.Equal(t, rulepkg.DMLCheckWhereIsInvalid, auditor.rules[0].Name)
+	assert.Equal(t, driverV2.RuleLevelError, auditor.rules[0].Level)
+	assert.Equal(t, 8, auditor.rules[1].Params.GetParam(rulepkg.DefaultSingleParamKeyName).Int())
+	// the params of the built-in rule are not changed
+	builtinRule := rulepkg.RuleHandlerMap[rulepkg.DDLCheckIndexCount].Rule
+	assert.NotEqual(t, 8, builtinRule.Params.GetParam(rulepkg.DefaultSingleParamKeyName).Int())
+}
+
+func TestNewWithInvalidParams(t *testing.T) {
+	_, err := New(&Params{OutputFormat: "xml", FailLevel: "error"}, logrus.WithField("test", "test"))
+	assert.Error(t, err)
+	_, err = New(&Params{OutputFormat: OutputFormatText, FailLevel: "fatal"}, logrus.WithField("test", "test"))
+	assert.Error(t, err)
+}
+
+func TestDirectAudit(t *testing.T) {
+	auditor, out := newTestAuditor(t, "", OutputFormatJson, "error")
+	err := auditor.DirectAudit(context.TODO(), &scanner.CreateSqlAuditReq{
+		DbType: driverV2.DriverTypeMySQL,
+		Sqls:   "delete from t1 where id = 1;delete from t1;",
+	})
+	assert.Error(t, err)
+
+	report := &auditReport{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), report))
+	assert.Equal(t, 2, report.TotalCount)
+	assert.Equal(t, 1, report.ErrorCount)
+	assert.Equal(t, 1, report.FailCount)
+	assert.Equal(t, "delete from t1;", report.SQLs[1].SQL)
+	assert.Equal(t, rulepkg.DMLCheckWhereIsInvalid, report.SQLs[1].AuditResults[0].RuleName)
+
+	auditor, out = newTestAuditor(t, "", OutputFormatText, "error")
+	assert.NoError(t, auditor.DirectAudit(context.TODO(), &scanner.CreateSqlAuditReq{Sqls: "delete from t1 where id = 1;"}))
+	assert.Contains(t, out.String(), "total sqls: 1, error sqls: 0, warning sqls: 0")
+
+	err = auditor.DirectAudit(context.TODO(), &scanner.CreateSqlAuditReq{DbType: "PostgreSQL", Sqls: "select 1;"})
+	assert.Error(t, err)
+}
+
+func TestDirectAuditWithSchemaFile(t *testing.T) {
+	schema := "CREATE DATABASE db1;\nUSE db1;\nCREATE TABLE t1 (id int primary key);"
+	auditor, out := newTestAuditor(t, schema, OutputFormatJson, "error")
+	err := auditor.DirectAudit(context.TODO(), &scanner.CreateSqlAuditReq{
+		InstanceSchema: "db1",
+		Sqls:           "delete from t1 where id = 1;delete from t2 where id = 1;",
+	})
+	assert.Error(t, err)
+
+	report := &auditReport{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), report))
+	assert.Equal(t, 2, report.TotalCount)
+	assert.Empty(t, report.SQLs[0].AuditResults)
+	// the table not in the snapshot is reported
+	assert.Equal(t, string(driverV2.RuleLevelError), report.SQLs[1].AuditLevel)
+	assert.Contains(t, report.SQLs[1].AuditResults[0].Message, "t2")
+}
+
+func TestDirectAuditWithSchemaFileAllRules(t *testing.T) {
+	schema := "CREATE DATABASE db1;\nUSE db1;\nCREATE TABLE t1 (id int primary key, v1 varchar(10), v2 int, KEY idx_v1(v1));"
+	auditor, out := newTestAuditor(t, schema, OutputFormatJson, "error")
+	auditor.rules = nil
+	for _, handlers := range []map[string]rulepkg.RuleHandler{rulepkg.RuleHandlerMap, rulepkg.AIRuleHandlerMap} {
+		for name, handler := range handlers {
+			if _, ok := instanceRequiredRules[name]; ok {
+				continue
+			}
+			rule := handler.Rule
+			auditor.rules = append(auditor.rules, &rule)
+		}
+	}
+	// the rules requiring the connection do not panic without it
+	err := auditor.DirectAudit(context.TODO(), &scanner.CreateSqlAuditReq{
+		InstanceSchema: "db1",
+		Sqls: "use db1;" +
+			"select * from t1 where v1 = 1 order by v2 limit 10;" +
+			"update t1 set v2 = 1 where v1 = 'a';" +
+			"delete from t1 where v2 + 1 > 3 order by id limit 10;" +
+			"insert into t1 select * from t1 where v2 > 1;" +
+			"alter table t1 add column v3 int, add index idx_v2(v2);" +
+			"create index idx_x on t1(v2);" +
+			"select count(*) from t1 join t1 as b on t1.id = b.v2 group by t1.v1;",
+	})
+	assert.Error(t, err)
+	report := &auditReport{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), report))
+	assert.Equal(t, 8, report.TotalCount)
+}
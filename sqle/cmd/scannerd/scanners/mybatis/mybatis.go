@@ -7,13 +7,12 @@ import (
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
-	"github.com/actiontech/sqle/sqle/pkg/scanner"
 	"github.com/sirupsen/logrus"
 )
 
 type MyBatis struct {
 	l *logrus.Entry
-	c *scanner.Client
+	c common.DirectAuditClient
 
 	xmlDir          string
 	skipErrorQuery  bool
@@ -22,6 +21,7 @@ type MyBatis struct {
 	instName        string
 	schemaName      string
 	showFileContent bool
+	maxVariants     int
 }
 
 type Params struct {
@@ -32,9 +32,10 @@ type Params struct {
 	InstName        string
 	SchemaName      string
 	ShowFileContent bool
+	MaxVariants     int
 }
 
-func New(params *Params, l *logrus.Entry, c *scanner.Client) (*MyBatis, error) {
+func New(params *Params, l *logrus.Entry, c common.DirectAuditClient) (*MyBatis, error) {
 	return &MyBatis{
 		xmlDir:          params.XMLDir,
 		skipErrorQuery:  params.SkipErrorQuery,
@@ -43,13 +44,14 @@ func New(params *Params, l *logrus.Entry, c *scanner.Client) (*MyBatis, error) {
 		instName:        params.InstName,
 		schemaName:      params.SchemaName,
 		showFileContent: params.ShowFileContent,
+		maxVariants:     params.MaxVariants,
 		l:               l,
 		c:               c,
 	}, nil
 }
 
 func (mb *MyBatis) Run(ctx context.Context) error {
-	sqls, err := common.GetSQLFromPath(mb.xmlDir, mb.skipErrorQuery, mb.skipErrorXml, utils.MybatisFileSuffix, mb.showFileContent)
+	sqls, err := common.GetSQLFromPath(mb.xmlDir, mb.skipErrorQuery, mb.skipErrorXml, utils.MybatisFileSuffix, mb.showFileContent, mb.maxVariants)
 	if err != nil {
 		return err
 	}
@@ -8,14 +8,13 @@ import (
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
-	"github.com/actiontech/sqle/sqle/pkg/scanner"
 
 	"github.com/sirupsen/logrus"
 )
 
 type SQLFile struct {
 	l *logrus.Entry
-	c *scanner.Client
+	c common.DirectAuditClient
 
 	sqlDir           string
 	skipErrorSqlFile bool
@@ -35,7 +34,7 @@ type Params struct {
 	ShowFileContent  bool
 }
 
-func New(params *Params, l *logrus.Entry, c *scanner.Client) (*SQLFile, error) {
+func New(params *Params, l *logrus.Entry, c common.DirectAuditClient) (*SQLFile, error) {
 	return &SQLFile{
 		sqlDir:           params.SQLDir,
 		skipErrorSqlFile: params.SkipErrorSqlFile,
@@ -49,7 +48,7 @@ func New(params *Params, l *logrus.Entry, c *scanner.Client) (*SQLFile, error) {
 }
 
 func (sf *SQLFile) Run(ctx context.Context) error {
-	sqls, err := common.GetSQLFromPath(sf.sqlDir, false, sf.skipErrorSqlFile, utils.SQLFileSuffix, sf.showFileContent)
+	sqls, err := common.GetSQLFromPath(sf.sqlDir, false, sf.skipErrorSqlFile, utils.SQLFileSuffix, sf.showFileContent, 0)
 	if err != nil {
 		return fmt.Errorf("failed to get sql from path: %v", err)
 	}
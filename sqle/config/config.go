@@ -53,7 +53,17 @@ type SeviceOpts struct {
 	PluginPath         string         `yaml:"plugin_path"`
 	Database           Database       `yaml:"database"`
 	PluginConfig       []PluginConfig `yaml:"plugin_config"`
-	PprofPort          int            `yaml:"pprof_port"` // pprof 独立服务器端口，0 表示禁用
+	PprofPort          int            `yaml:"pprof_port"`   // pprof 独立服务器端口，0 表示禁用
+	MetricsPort        int            `yaml:"metrics_port"` // Prometheus 指标独立服务器端口，0 表示禁用
+	Tracing            Tracing        `yaml:"tracing"`
+	LocalePath         string         `yaml:"locale_path"` // 额外语言包目录，启动时加载其中的 active.<lang>.toml
+}
+
+type Tracing struct {
+	// OTLP/HTTP 地址，如 http://127.0.0.1:4318，为空表示不开启链路追踪
+	OtlpEndpoint string `yaml:"otlp_endpoint"`
+	// 采样比例，范围 0-1，为空时全部采样
+	SampleRatio string `yaml:"sample_ratio"`
 }
 
 type Database struct {
@@ -247,3 +247,15 @@ func ListProjectUserTips(ctx context.Context, projectUid string) (users []*model
 	}
 	return users, nil
 }
+
+func GetMemberGroupUserIds(ctx context.Context, projectUid, memberGroupUid string) ([]string, error) {
+	group, err := dmsobject.GetMemberGroup(ctx, memberGroupUid, projectUid, controller.GetDMSServerAddress())
+	if err != nil {
+		return nil, fmt.Errorf("get member group from dms error: %v", err)
+	}
+	userIds := make([]string, 0, len(group.Users))
+	for _, user := range group.Users {
+		userIds = append(userIds, user.Uid)
+	}
+	return userIds, nil
+}
@@ -732,6 +732,29 @@ var doc = `{
                 }
             }
         },
+        "/v1/configurations/plugins": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the status and restart history of the plugins on this sqled node",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取插件运行状态及重启记录",
+                "operationId": "getPluginStatusesV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetPluginStatusesResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/configurations/ssh_key": {
             "get": {
                 "security": [
@@ -1694,7 +1717,7 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get knowledge base list",
+                "description": "search the global knowledge base by the full-text index, the results are sorted by relevance if keywords are specified",
                 "tags": [
                     "knowledge_base"
                 ],
@@ -1795,6 +1818,70 @@ var doc = `{
                 }
             }
         },
+        "/v1/knowledge_bases/{knowledge_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the detail of global knowledge, including the rules related by tags",
+                "tags": [
+                    "knowledge_base"
+                ],
+                "summary": "获取知识详情",
+                "operationId": "getKnowledgeBaseV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "knowledge id",
+                        "name": "knowledge_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetKnowledgeBaseResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/knowledge_bases/{knowledge_id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the versions of global knowledge, the latest version is the first",
+                "tags": [
+                    "knowledge_base"
+                ],
+                "summary": "获取知识的历史版本",
+                "operationId": "getKnowledgeBaseVersionsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "knowledge id",
+                        "name": "knowledge_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetKnowledgeBaseVersionsResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/operations": {
             "get": {
                 "security": [
@@ -4085,19 +4172,19 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/instances/{instance_name}/rules": {
+        "/v1/projects/{project_name}/instances/{instance_name}/execution_throttle": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get instance all rule",
+                "description": "get the execution throttle settings of the instance",
                 "tags": [
                     "instance"
                 ],
-                "summary": "获取实例应用的规则列表",
-                "operationId": "getInstanceRuleListV1",
+                "summary": "获取实例的上线限流配置",
+                "operationId": "getExecutionThrottleV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4118,25 +4205,26 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRulesResV1"
+                            "$ref": "#/definitions/v1.GetExecutionThrottleResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/instances/{instance_name}/schemas": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "instance schema list",
+                "description": "update the execution throttle settings of the instance, the execution of the task pauses between statements and batches while the replication lag or Threads_running exceeds the limits",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
                     "instance"
                 ],
-                "summary": "实例 Schema 列表",
-                "operationId": "getInstanceSchemasV1",
+                "summary": "更新实例的上线限流配置",
+                "operationId": "updateExecutionThrottleV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4151,31 +4239,43 @@ var doc = `{
                         "name": "instance_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "update execution throttle request",
+                        "name": "throttle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateExecutionThrottleReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetInstanceSchemaResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/instances/{instance_name}/schemas/{schema_name}/tables": {
-            "get": {
+        "/v1/projects/{project_name}/instances/{instance_name}/query": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "list table by schema",
+                "description": "audit the statement with the rule template of the instance and run it if it is a read-only query, the result is limited by the sql query config of the instance and masked by the masking rules of the project",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "instance"
+                    "sql_query"
                 ],
-                "summary": "获取数据库下的所有表",
-                "operationId": "listTableBySchema",
+                "summary": "在实例上执行只读查询",
+                "operationId": "queryInstanceV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4192,36 +4292,38 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "schema name",
-                        "name": "schema_name",
-                        "in": "path",
-                        "required": true
+                        "description": "query request",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueryInstanceReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.ListTableBySchemaResV1"
+                            "$ref": "#/definitions/v1.QueryInstanceResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/instances/{instance_name}/schemas/{schema_name}/tables/{table_name}/metadata": {
+        "/v1/projects/{project_name}/instances/{instance_name}/rules": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get table metadata",
+                "description": "get instance all rule",
                 "tags": [
                     "instance"
                 ],
-                "summary": "获取表元数据",
-                "operationId": "getTableMetadata",
+                "summary": "获取实例应用的规则列表",
+                "operationId": "getInstanceRuleListV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4236,45 +4338,31 @@ var doc = `{
                         "name": "instance_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "schema name",
-                        "name": "schema_name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "table name",
-                        "name": "table_name",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetTableMetadataResV1"
+                            "$ref": "#/definitions/v1.GetRulesResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/pipelines": {
+        "/v1/projects/{project_name}/instances/{instance_name}/schema_baseline": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get pipeline list",
+                "description": "get the schema baseline of the instance",
                 "tags": [
-                    "pipeline"
+                    "schema_drift"
                 ],
-                "summary": "获取流水线列表",
-                "operationId": "getPipelinesV1",
+                "summary": "获取实例的表结构基线",
+                "operationId": "getSchemaBaselineV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4285,22 +4373,9 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "fuzzy search pipeline name and description",
-                        "name": "fuzzy_search_name_desc",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query",
+                        "description": "instance name",
+                        "name": "instance_name",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -4308,7 +4383,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetPipelinesResV1"
+                            "$ref": "#/definitions/v1.GetSchemaBaselineResV1"
                         }
                     }
                 }
@@ -4319,15 +4394,15 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create pipeline",
+                "description": "pin the approved schema of the instance, \"snapshot\" uses the current schema and \"workflow\" replays the DDL executed by the finished workflows",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "pipeline"
+                    "schema_drift"
                 ],
-                "summary": "创建流水线",
-                "operationId": "createPipelineV1",
+                "summary": "设置实例的表结构基线",
+                "operationId": "createSchemaBaselineV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4337,12 +4412,19 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "create pipeline",
-                        "name": "pipeline",
+                        "type": "string",
+                        "description": "instance name",
+                        "name": "instance_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "create schema baseline request",
+                        "name": "baseline",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreatePipelineReqV1"
+                            "$ref": "#/definitions/v1.CreateSchemaBaselineReqV1"
                         }
                     }
                 ],
@@ -4350,25 +4432,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.CreatePipelineResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/pipelines/{pipeline_id}/": {
+        "/v1/projects/{project_name}/instances/{instance_name}/schema_drift_findings": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get pipeline detail",
+                "description": "get the schema drift found by the schema meta audit plan against the baseline of the instance",
                 "tags": [
-                    "pipeline"
+                    "schema_drift"
                 ],
-                "summary": "获取流水线详情",
-                "operationId": "getPipelineDetailV1",
+                "summary": "获取实例的表结构漂移列表",
+                "operationId": "getSchemaDriftFindingsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4379,33 +4461,75 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "pipeline id",
-                        "name": "pipeline_id",
+                        "description": "instance name",
+                        "name": "instance_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "resolved"
+                        ],
+                        "type": "string",
+                        "description": "filter status",
+                        "name": "filter_status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "table_added",
+                            "table_removed",
+                            "column_added",
+                            "column_removed",
+                            "column_changed",
+                            "index_added",
+                            "index_removed",
+                            "index_changed"
+                        ],
+                        "type": "string",
+                        "description": "filter drift type",
+                        "name": "filter_drift_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetPipelineDetailResV1"
+                            "$ref": "#/definitions/v1.GetSchemaDriftFindingsResV1"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/projects/{project_name}/instances/{instance_name}/schemas": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "delete pipeline",
+                "description": "instance schema list",
                 "tags": [
-                    "pipeline"
+                    "instance"
                 ],
-                "summary": "删除流水线",
-                "operationId": "deletePipelineV1",
+                "summary": "实例 Schema 列表",
+                "operationId": "getInstanceSchemasV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4416,8 +4540,8 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "pipeline id",
-                        "name": "pipeline_id",
+                        "description": "instance name",
+                        "name": "instance_name",
                         "in": "path",
                         "required": true
                     }
@@ -4426,23 +4550,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetInstanceSchemaResV1"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/projects/{project_name}/instances/{instance_name}/schemas/{schema_name}/tables": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update pipeline",
+                "description": "list table by schema",
                 "tags": [
-                    "pipeline"
+                    "instance"
                 ],
-                "summary": "更新流水线",
-                "operationId": "updatePipelineV1",
+                "summary": "获取数据库下的所有表",
+                "operationId": "listTableBySchema",
                 "parameters": [
                     {
                         "type": "string",
@@ -4453,44 +4579,42 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "pipeline id",
-                        "name": "pipeline_id",
+                        "description": "instance name",
+                        "name": "instance_name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "update pipeline",
-                        "name": "pipeline",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpdatePipelineReqV1"
-                        }
+                        "type": "string",
+                        "description": "schema name",
+                        "name": "schema_name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.ListTableBySchemaResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/pipelines/{pipeline_id}/token/{node_id}/": {
-            "patch": {
+        "/v1/projects/{project_name}/instances/{instance_name}/schemas/{schema_name}/tables/{table_name}/metadata": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "refresh pipeline token",
+                "description": "get table metadata",
                 "tags": [
-                    "pipeline"
+                    "instance"
                 ],
-                "summary": "刷新流水线节点token",
-                "operationId": "refreshPipelineNodeTokenV1",
+                "summary": "获取表元数据",
+                "operationId": "getTableMetadata",
                 "parameters": [
                     {
                         "type": "string",
@@ -4501,15 +4625,22 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "pipeline id",
-                        "name": "pipeline_id",
+                        "description": "instance name",
+                        "name": "instance_name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "node id",
-                        "name": "node_id",
+                        "description": "schema name",
+                        "name": "schema_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "table name",
+                        "name": "table_name",
                         "in": "path",
                         "required": true
                     }
@@ -4518,25 +4649,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetTableMetadataResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/report_push_configs": {
+        "/v1/projects/{project_name}/knowledge_bases": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get report push config list",
+                "description": "search the global knowledge and the team knowledge of the project by the full-text index",
                 "tags": [
-                    "ReportPushConfig"
+                    "knowledge_base"
                 ],
-                "summary": "获取消息推送配置列表",
-                "operationId": "GetReportPushConfigList",
+                "summary": "获取项目的知识库列表",
+                "operationId": "getProjectKnowledgeBaseListV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4544,31 +4675,61 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "keywords",
+                        "name": "keywords",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "tags",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetReportPushConfigsListResV1"
+                            "$ref": "#/definitions/v1.GetKnowledgeBaseListRes"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/report_push_configs/{report_push_config_id}/": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update report push config",
+                "description": "create team-specific knowledge of the project, it is related to the rules by tags",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "ReportPushConfig"
+                    "knowledge_base"
                 ],
-                "summary": "更新消息推送配置",
-                "operationId": "UpdateReportPushConfig",
+                "summary": "添加项目的团队知识",
+                "operationId": "createTeamKnowledgeV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4578,19 +4739,12 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "report push config id",
-                        "name": "report_push_config_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "update report push config request",
-                        "name": "req",
+                        "description": "create team knowledge request",
+                        "name": "knowledge",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateReportPushConfigReqV1"
+                            "$ref": "#/definitions/v1.CreateTeamKnowledgeReqV1"
                         }
                     }
                 ],
@@ -4604,19 +4758,19 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/rule_template_tips": {
+        "/v1/projects/{project_name}/knowledge_bases/{knowledge_id}/": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get rule template tips in project",
+                "description": "get the detail of global knowledge or the team knowledge of the project",
                 "tags": [
-                    "rule_template"
+                    "knowledge_base"
                 ],
-                "summary": "获取项目规则模板提示",
-                "operationId": "getProjectRuleTemplateTipsV1",
+                "summary": "获取项目的知识详情",
+                "operationId": "getProjectKnowledgeBaseV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4627,34 +4781,33 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "filter db type",
-                        "name": "filter_db_type",
-                        "in": "query"
+                        "description": "knowledge id",
+                        "name": "knowledge_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRuleTemplateTipsResV1"
+                            "$ref": "#/definitions/v1.GetKnowledgeBaseResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/rule_templates": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get all rule template in a project",
+                "description": "delete team knowledge of the project",
                 "tags": [
-                    "rule_template"
+                    "knowledge_base"
                 ],
-                "summary": "项目规则模板列表",
-                "operationId": "getProjectRuleTemplateListV1",
+                "summary": "删除项目的团队知识",
+                "operationId": "deleteTeamKnowledgeV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4664,17 +4817,10 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query",
+                        "type": "string",
+                        "description": "knowledge id",
+                        "name": "knowledge_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -4682,26 +4828,26 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetProjectRuleTemplatesResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create a rule template in project",
+                "description": "update team knowledge of the project, a new version is recorded",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "rule_template"
+                    "knowledge_base"
                 ],
-                "summary": "添加项目规则模板",
-                "operationId": "createProjectRuleTemplateV1",
+                "summary": "更新项目的团队知识",
+                "operationId": "updateTeamKnowledgeV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4711,12 +4857,19 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "add rule template request",
-                        "name": "req",
+                        "type": "string",
+                        "description": "knowledge id",
+                        "name": "knowledge_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update team knowledge request",
+                        "name": "knowledge",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateProjectRuleTemplateReqV1"
+                            "$ref": "#/definitions/v1.UpdateTeamKnowledgeReqV1"
                         }
                     }
                 ],
@@ -4730,19 +4883,19 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/rule_templates/{rule_template_name}/": {
+        "/v1/projects/{project_name}/knowledge_bases/{knowledge_id}/versions": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get rule template detail in project",
+                "description": "get the versions of global knowledge or the team knowledge of the project, the latest version is the first",
                 "tags": [
-                    "rule_template"
+                    "knowledge_base"
                 ],
-                "summary": "获取项目规则模板信息",
-                "operationId": "getProjectRuleTemplateV1",
+                "summary": "获取项目的知识的历史版本",
+                "operationId": "getProjectKnowledgeBaseVersionsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4753,45 +4906,35 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
+                        "description": "knowledge id",
+                        "name": "knowledge_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "fuzzy rule,keyword for desc and annotation",
-                        "name": "fuzzy_keyword_rule",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "tags for rule",
-                        "name": "tags",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetProjectRuleTemplateResV1"
+                            "$ref": "#/definitions/v1.GetKnowledgeBaseVersionsResV1"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/projects/{project_name}/pipelines": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "delete rule template in project",
+                "description": "get pipeline list",
                 "tags": [
-                    "rule_template"
+                    "pipeline"
                 ],
-                "summary": "删除项目规则模板",
-                "operationId": "deleteProjectRuleTemplateV1",
+                "summary": "获取流水线列表",
+                "operationId": "getPipelinesV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4802,9 +4945,22 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
-                        "in": "path",
+                        "description": "fuzzy search pipeline name and description",
+                        "name": "fuzzy_search_name_desc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -4812,23 +4968,26 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetPipelinesResV1"
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update rule template in project",
+                "description": "create pipeline",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "rule_template"
+                    "pipeline"
                 ],
-                "summary": "更新项目规则模板",
-                "operationId": "updateProjectRuleTemplateV1",
+                "summary": "创建流水线",
+                "operationId": "createPipelineV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4838,19 +4997,12 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "update rule template request",
-                        "name": "req",
+                        "description": "create pipeline",
+                        "name": "pipeline",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateProjectRuleTemplateReqV1"
+                            "$ref": "#/definitions/v1.CreatePipelineReqV1"
                         }
                     }
                 ],
@@ -4858,28 +5010,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.CreatePipelineResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/rule_templates/{rule_template_name}/clone": {
-            "post": {
+        "/v1/projects/{project_name}/pipelines/{pipeline_id}/": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "clone a rule template in project",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "get pipeline detail",
                 "tags": [
-                    "rule_template"
+                    "pipeline"
                 ],
-                "summary": "克隆项目规则模板",
-                "operationId": "cloneProjectRuleTemplateV1",
+                "summary": "获取流水线详情",
+                "operationId": "getPipelineDetailV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4890,44 +5039,33 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
+                        "description": "pipeline id",
+                        "name": "pipeline_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "clone rule template request",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.CloneProjectRuleTemplateReqV1"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetPipelineDetailResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/rule_templates/{rule_template_name}/export": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "export rule template in a project",
+                "description": "delete pipeline",
                 "tags": [
-                    "rule_template"
+                    "pipeline"
                 ],
-                "summary": "导出项目规则模板",
-                "operationId": "exportProjectRuleTemplateV1",
+                "summary": "删除流水线",
+                "operationId": "deletePipelineV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4938,136 +5076,81 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
+                        "description": "pipeline id",
+                        "name": "pipeline_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "enum": [
-                            "csv",
-                            "json"
-                        ],
-                        "type": "string",
-                        "description": "export type",
-                        "name": "export_type",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "sqle rule template file",
+                        "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/sql_audit_records": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql audit records",
+                "description": "update pipeline",
                 "tags": [
-                    "sql_audit_record"
+                    "pipeline"
                 ],
-                "summary": "获取SQL审核记录列表",
-                "operationId": "getSQLAuditRecordsV1",
+                "summary": "更新流水线",
+                "operationId": "updatePipelineV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "fuzzy search tags",
-                        "name": "fuzzy_search_tags",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "auditing",
-                            "successfully"
-                        ],
-                        "type": "string",
-                        "description": "filter sql audit status",
-                        "name": "filter_sql_audit_status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "filter instance id",
-                        "name": "filter_instance_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter create time from",
-                        "name": "filter_create_time_from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter create time to",
-                        "name": "filter_create_time_to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter sql audit record ids",
-                        "name": "filter_sql_audit_record_ids",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "pipeline id",
+                        "name": "pipeline_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "update pipeline",
+                        "name": "pipeline",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdatePipelineReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSQLAuditRecordsResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/projects/{project_name}/pipelines/{pipeline_id}/token/{node_id}/": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "SQL audit\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.\n4. file[input_zip_file]: it is ZIP file that sql will be parsed from xml or sql file inside it.\n5. formData[git_http_url]:the url which scheme is http(s) and end with .git.\n6. formData[git_user_name]:The name of the user who owns the repository read access.\n7. formData[git_branch_name]:The name of the repository branch.\n8. formData[git_user_password]:The password corresponding to git_user_name.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "refresh pipeline token",
                 "tags": [
-                    "sql_audit_record"
+                    "pipeline"
                 ],
-                "summary": "SQL审核",
-                "operationId": "CreateSQLAuditRecordV1",
+                "summary": "刷新流水线节点token",
+                "operationId": "refreshPipelineNodeTokenV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5078,100 +5161,42 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "instance name",
-                        "name": "instance_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "schema of instance",
-                        "name": "instance_schema",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "db type of instance",
-                        "name": "db_type",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "sqls for audit",
-                        "name": "sqls",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input SQL file",
-                        "name": "input_sql_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input mybatis XML file",
-                        "name": "input_mybatis_xml_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input ZIP file",
-                        "name": "input_zip_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "git repository url",
-                        "name": "git_http_url",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "the name of user to clone the repository",
-                        "name": "git_user_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "the name of repository branch",
-                        "name": "git_branch_name",
-                        "in": "formData"
+                        "description": "pipeline id",
+                        "name": "pipeline_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "the password corresponding to git_user_name",
-                        "name": "git_user_password",
-                        "in": "formData"
+                        "description": "node id",
+                        "name": "node_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateSQLAuditRecordResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_audit_records/tag_tips": {
+        "/v1/projects/{project_name}/report_push_configs": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql audit record tag tips",
+                "description": "Get report push config list",
                 "tags": [
-                    "sql_audit_record"
+                    "ReportPushConfig"
                 ],
-                "summary": "获取SQL审核记录标签列表",
-                "operationId": "GetSQLAuditRecordTagTipsV1",
+                "summary": "获取消息推送配置列表",
+                "operationId": "GetReportPushConfigList",
                 "parameters": [
                     {
                         "type": "string",
@@ -5185,25 +5210,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSQLAuditRecordTagTipsResV1"
+                            "$ref": "#/definitions/v1.GetReportPushConfigsListResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_audit_records/{sql_audit_record_id}/": {
-            "get": {
+        "/v1/projects/{project_name}/report_push_configs/{report_push_config_id}/": {
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql audit record info",
+                "description": "update report push config",
                 "tags": [
-                    "sql_audit_record"
+                    "ReportPushConfig"
                 ],
-                "summary": "获取SQL审核记录信息",
-                "operationId": "getSQLAuditRecordV1",
+                "summary": "更新消息推送配置",
+                "operationId": "UpdateReportPushConfig",
                 "parameters": [
                     {
                         "type": "string",
@@ -5214,36 +5239,44 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "sql audit record id",
-                        "name": "sql_audit_record_id",
+                        "description": "report push config id",
+                        "name": "report_push_config_id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
+                    },
+                    {
+                        "description": "update report push config request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateReportPushConfigReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSQLAuditRecordResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/projects/{project_name}/rule_template_tips": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update SQL audit record",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "get rule template tips in project",
                 "tags": [
-                    "sql_audit_record"
+                    "rule_template"
                 ],
-                "summary": "更新SQL审核记录",
-                "operationId": "updateSQLAuditRecordV1",
+                "summary": "获取项目规则模板提示",
+                "operationId": "getProjectRuleTemplateTipsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5254,44 +5287,34 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "sql audit record id",
-                        "name": "sql_audit_record_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "update SQL audit record",
-                        "name": "param",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpdateSQLAuditRecordReqV1"
-                        }
+                        "description": "filter db type",
+                        "name": "filter_db_type",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetRuleTemplateTipsResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_dev_records": {
+        "/v1/projects/{project_name}/rule_templates": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql dev record list",
+                "description": "get all rule template in a project",
                 "tags": [
-                    "SqlDEVRecord"
+                    "rule_template"
                 ],
-                "summary": "获取开发sql记录",
-                "operationId": "GetSqlDEVRecordList",
+                "summary": "项目规则模板列表",
+                "operationId": "getProjectRuleTemplateListV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5300,66 +5323,6 @@ var doc = `{
                         "in": "path",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "fuzzy search sql fingerprint",
-                        "name": "fuzzy_search_sql_fingerprint",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "instance name",
-                        "name": "filter_instance_name",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "ide_plugin"
-                        ],
-                        "type": "string",
-                        "description": "source",
-                        "name": "filter_source",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "creator name",
-                        "name": "filter_creator",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "last receive time from",
-                        "name": "filter_last_receive_time_from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "last receive time to",
-                        "name": "filter_last_receive_time_to",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "first_appear_timestamp",
-                            "last_receive_timestamp",
-                            "fp_count"
-                        ],
-                        "type": "string",
-                        "description": "sort field",
-                        "name": "sort_field",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "description": "sort order",
-                        "name": "sort_order",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
                         "description": "page index",
@@ -5379,26 +5342,26 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlDEVRecordListResp"
+                            "$ref": "#/definitions/v1.GetProjectRuleTemplatesResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/sql_manages": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql manage list",
+                "description": "create a rule template in project",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "SqlManage"
+                    "rule_template"
                 ],
-                "summary": "获取管控sql列表",
-                "operationId": "GetSqlManageList",
-                "deprecated": true,
+                "summary": "添加项目规则模板",
+                "operationId": "createProjectRuleTemplateV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5408,68 +5371,1090 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "fuzzy search sql fingerprint",
-                        "name": "fuzzy_search_sql_fingerprint",
-                        "in": "query"
-                    },
+                        "description": "add rule template request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateProjectRuleTemplateReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/rule_templates/{rule_template_name}/": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "assignee",
-                        "name": "filter_assignee",
-                        "in": "query"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get rule template detail in project",
+                "tags": [
+                    "rule_template"
+                ],
+                "summary": "获取项目规则模板信息",
+                "operationId": "getProjectRuleTemplateV1",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "instance name",
-                        "name": "filter_instance_name",
-                        "in": "query"
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "audit_plan",
-                            "sql_audit_record"
-                        ],
                         "type": "string",
-                        "description": "source",
-                        "name": "filter_source",
-                        "in": "query"
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "normal",
-                            "notice",
-                            "warn",
-                            "error"
-                        ],
                         "type": "string",
-                        "description": "audit level",
-                        "name": "filter_audit_level",
+                        "description": "fuzzy rule,keyword for desc and annotation",
+                        "name": "fuzzy_keyword_rule",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "last audit start time from",
-                        "name": "filter_last_audit_start_time_from",
+                        "description": "tags for rule",
+                        "name": "tags",
                         "in": "query"
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetProjectRuleTemplateResV1"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "last audit start time to",
-                        "name": "filter_last_audit_start_time_to",
-                        "in": "query"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete rule template in project",
+                "tags": [
+                    "rule_template"
+                ],
+                "summary": "删除项目规则模板",
+                "operationId": "deleteProjectRuleTemplateV1",
+                "parameters": [
                     {
-                        "enum": [
-                            "unhandled",
-                            "solved",
-                            "ignored",
-                            "manual_audited"
-                        ],
                         "type": "string",
-                        "description": "status",
-                        "name": "filter_status",
-                        "in": "query"
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update rule template in project",
+                "tags": [
+                    "rule_template"
+                ],
+                "summary": "更新项目规则模板",
+                "operationId": "updateProjectRuleTemplateV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update rule template request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateProjectRuleTemplateReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/rule_templates/{rule_template_name}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "clone a rule template in project",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule_template"
+                ],
+                "summary": "克隆项目规则模板",
+                "operationId": "cloneProjectRuleTemplateV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "clone rule template request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CloneProjectRuleTemplateReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/rule_templates/{rule_template_name}/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "export rule template in a project",
+                "tags": [
+                    "rule_template"
+                ],
+                "summary": "导出项目规则模板",
+                "operationId": "exportProjectRuleTemplateV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "export type",
+                        "name": "export_type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "sqle rule template file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/scoring_policy": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the audit scoring policy of the project, the default scoring is used if it is not enabled",
+                "tags": [
+                    "scoring_policy"
+                ],
+                "summary": "获取项目的审核评分策略",
+                "operationId": "getScoringPolicyV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetScoringPolicyResV1"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update the audit scoring policy of the project, it takes effect on the tasks audited after the update",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scoring_policy"
+                ],
+                "summary": "更新项目的审核评分策略",
+                "operationId": "updateScoringPolicyV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update scoring policy request",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateScoringPolicyReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_audit_records": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql audit records",
+                "tags": [
+                    "sql_audit_record"
+                ],
+                "summary": "获取SQL审核记录列表",
+                "operationId": "getSQLAuditRecordsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "fuzzy search tags",
+                        "name": "fuzzy_search_tags",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "auditing",
+                            "successfully"
+                        ],
+                        "type": "string",
+                        "description": "filter sql audit status",
+                        "name": "filter_sql_audit_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "filter instance id",
+                        "name": "filter_instance_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create time from",
+                        "name": "filter_create_time_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create time to",
+                        "name": "filter_create_time_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter sql audit record ids",
+                        "name": "filter_sql_audit_record_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSQLAuditRecordsResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "SQL audit\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.\n4. file[input_zip_file]: it is ZIP file that sql will be parsed from xml or sql file inside it.\n5. formData[git_http_url]:the url which scheme is http(s) and end with .git.\n6. formData[git_user_name]:The name of the user who owns the repository read access.\n7. formData[git_branch_name]:The name of the repository branch.\n8. formData[git_user_password]:The password corresponding to git_user_name.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sql_audit_record"
+                ],
+                "summary": "SQL审核",
+                "operationId": "CreateSQLAuditRecordV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "instance name",
+                        "name": "instance_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "schema of instance",
+                        "name": "instance_schema",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "db type of instance",
+                        "name": "db_type",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sqls for audit",
+                        "name": "sqls",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input SQL file",
+                        "name": "input_sql_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input mybatis XML file",
+                        "name": "input_mybatis_xml_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input ZIP file",
+                        "name": "input_zip_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "git repository url",
+                        "name": "git_http_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the name of user to clone the repository",
+                        "name": "git_user_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the name of repository branch",
+                        "name": "git_branch_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the password corresponding to git_user_name",
+                        "name": "git_user_password",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateSQLAuditRecordResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_audit_records/tag_tips": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql audit record tag tips",
+                "tags": [
+                    "sql_audit_record"
+                ],
+                "summary": "获取SQL审核记录标签列表",
+                "operationId": "GetSQLAuditRecordTagTipsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSQLAuditRecordTagTipsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_audit_records/{sql_audit_record_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql audit record info",
+                "tags": [
+                    "sql_audit_record"
+                ],
+                "summary": "获取SQL审核记录信息",
+                "operationId": "getSQLAuditRecordV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql audit record id",
+                        "name": "sql_audit_record_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSQLAuditRecordResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update SQL audit record",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sql_audit_record"
+                ],
+                "summary": "更新SQL审核记录",
+                "operationId": "updateSQLAuditRecordV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql audit record id",
+                        "name": "sql_audit_record_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update SQL audit record",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSQLAuditRecordReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_dev_records": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql dev record list",
+                "tags": [
+                    "SqlDEVRecord"
+                ],
+                "summary": "获取开发sql记录",
+                "operationId": "GetSqlDEVRecordList",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "fuzzy search sql fingerprint",
+                        "name": "fuzzy_search_sql_fingerprint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "instance name",
+                        "name": "filter_instance_name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ide_plugin"
+                        ],
+                        "type": "string",
+                        "description": "source",
+                        "name": "filter_source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "creator name",
+                        "name": "filter_creator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "last receive time from",
+                        "name": "filter_last_receive_time_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "last receive time to",
+                        "name": "filter_last_receive_time_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "first_appear_timestamp",
+                            "last_receive_timestamp",
+                            "fp_count"
+                        ],
+                        "type": "string",
+                        "description": "sort field",
+                        "name": "sort_field",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "sort order",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlDEVRecordListResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql manage list",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取管控sql列表",
+                "operationId": "GetSqlManageList",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "fuzzy search sql fingerprint",
+                        "name": "fuzzy_search_sql_fingerprint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "assignee",
+                        "name": "filter_assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "instance name",
+                        "name": "filter_instance_name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "audit_plan",
+                            "sql_audit_record"
+                        ],
+                        "type": "string",
+                        "description": "source",
+                        "name": "filter_source",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "normal",
+                            "notice",
+                            "warn",
+                            "error"
+                        ],
+                        "type": "string",
+                        "description": "audit level",
+                        "name": "filter_audit_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "last audit start time from",
+                        "name": "filter_last_audit_start_time_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "last audit start time to",
+                        "name": "filter_last_audit_start_time_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "unhandled",
+                            "solved",
+                            "ignored",
+                            "manual_audited"
+                        ],
+                        "type": "string",
+                        "description": "status",
+                        "name": "filter_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "rule name",
+                        "name": "filter_rule_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "db type",
+                        "name": "filter_db_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fuzzy search endpoint",
+                        "name": "fuzzy_search_endpoint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fuzzy search schema name",
+                        "name": "fuzzy_search_schema_name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "first_appear_timestamp",
+                            "last_receive_timestamp",
+                            "fp_count"
+                        ],
+                        "type": "string",
+                        "description": "sort field",
+                        "name": "sort_field",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "sort order",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlManageListResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/abnormal_audit_plan_instance": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the instance of audit plan execution abnormal",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取执行异常的扫描任务实例",
+                "operationId": "getAbnormalInstanceAuditPlansV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetAbnormalAuditPlanInstancesResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/batch": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "batch update sql manage",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "批量更新SQL管控",
+                "operationId": "BatchUpdateSqlManage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "batch update sql manage request",
+                        "name": "BatchUpdateSqlManageReq",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchUpdateSqlManageReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/exports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "export sql manage",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "导出SQL管控",
+                "operationId": "exportSqlManageV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "fuzzy search sql fingerprint",
+                        "name": "fuzzy_search_sql_fingerprint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "assignee",
+                        "name": "filter_assignee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by business",
+                        "name": "filter_business",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "high",
+                            "low"
+                        ],
+                        "type": "string",
+                        "description": "priority",
+                        "name": "filter_priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "instance id",
+                        "name": "filter_instance_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "audit_plan",
+                            "sql_audit_record"
+                        ],
+                        "type": "string",
+                        "description": "source",
+                        "name": "filter_source",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "normal",
+                            "notice",
+                            "warn",
+                            "error"
+                        ],
+                        "type": "string",
+                        "description": "audit level",
+                        "name": "filter_audit_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "last audit start time from",
+                        "name": "filter_last_audit_start_time_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "last audit start time to",
+                        "name": "filter_last_audit_start_time_to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "unhandled",
+                            "solved",
+                            "ignored",
+                            "manual_audited"
+                        ],
+                        "type": "string",
+                        "description": "status",
+                        "name": "filter_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "db type",
+                        "name": "filter_db_type",
+                        "in": "query"
                     },
                     {
                         "type": "string",
@@ -5479,43 +6464,838 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "db type",
-                        "name": "filter_db_type",
-                        "in": "query"
+                        "description": "fuzzy search endpoint",
+                        "name": "fuzzy_search_endpoint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fuzzy search schema name",
+                        "name": "fuzzy_search_schema_name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "first_appear_timestamp",
+                            "last_receive_timestamp",
+                            "fp_count"
+                        ],
+                        "type": "string",
+                        "description": "sort field",
+                        "name": "sort_field",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "sort order",
+                        "name": "sort_order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "export sql manage",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/index_advice": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "advise the indexes for the workload of the schema in sql management, the sql are weighted by their execution count and query time",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取SQL管控中数据源的工作负载索引建议",
+                "operationId": "getSqlManageIndexAdviceV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "instance id",
+                        "name": "instance_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "schema name",
+                        "name": "schema_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "days of the metrics weighting the workload, default 7",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlManageIndexAdviceResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/rule_tips": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql manage rule tips",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取管控规则tips",
+                "operationId": "GetSqlManageRuleTips",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlManageRuleTipsResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/send": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql manage analysis",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "推送SQL管控结果到外部系统",
+                "operationId": "SendSqlManage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "batch update sql manage request",
+                        "name": "SqlManageCodingReq",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SqlManageCodingReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PostSqlManageCodingResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/triage_records": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the records of the fields of the SQL changed by the triage rules of sql management",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取SQL管控分诊规则的变更记录",
+                "operationId": "getSqlManageTriageRecordsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "sql manage id",
+                        "name": "filter_sql_manage_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "triage rule id",
+                        "name": "filter_rule_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlManageTriageRecordsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/triage_rules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the rules automating the triage of the SQL in sql management, the enabled rules are evaluated by priority in ascending order",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取SQL管控分诊规则列表",
+                "operationId": "getSqlManageTriageRulesV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlManageTriageRulesResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "create a rule automating the triage of the SQL in sql management, the SQL matching all its conditions get its actions and an empty condition always matches",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "添加SQL管控分诊规则",
+                "operationId": "createSqlManageTriageRuleV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "create sql manage triage rule request",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateSqlManageTriageRuleReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/triage_rules/{rule_id}/": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete a triage rule of sql management, the records of the changes made by the rule are kept",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "删除SQL管控分诊规则",
+                "operationId": "deleteSqlManageTriageRuleV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "triage rule id",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update a triage rule of sql management, the changes made by the rule before are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "更新SQL管控分诊规则",
+                "operationId": "updateSqlManageTriageRuleV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "triage rule id",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update sql manage triage rule request",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSqlManageTriageRuleReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/{sql_manage_id}/execute_plans": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the distinct execute plans of the sql and the plan changes",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取SQL管控SQL的执行计划历史",
+                "operationId": "getSqlManageExecutePlansV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql manage id",
+                        "name": "sql_manage_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlManageExecutePlansResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/{sql_manage_id}/execute_plans/diff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "compare two execute plans of the sql side by side",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "对比SQL管控SQL的两个执行计划",
+                "operationId": "getSqlManageExecutePlanDiffV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql manage id",
+                        "name": "sql_manage_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "from plan id",
+                        "name": "from_plan_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "to plan id",
+                        "name": "to_plan_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlManageExecutePlanDiffResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/{sql_manage_id}/sql_analysis": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql manage analysis",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取SQL管控SQL分析",
+                "operationId": "GetSqlManageSqlAnalysisV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql manage id",
+                        "name": "sql_manage_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "whether to calculate and return affected rows, default is true",
+                        "name": "affectRowsEnabled",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlManageSqlAnalysisResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_manages/{sql_manage_id}/sql_analysis_chart": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql manage analysis",
+                "tags": [
+                    "SqlManage"
+                ],
+                "summary": "获取SQL管控SQL执行计划Cost趋势图表",
+                "operationId": "GetSqlManageSqlAnalysisChartV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql manage id",
+                        "name": "sql_manage_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "latest_point_enabled",
+                        "name": "latest_point_enabled",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "end time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "metric_name",
+                        "name": "metric_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SqlManageAnalysisChartResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_optimization_records": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql optimization records",
+                "tags": [
+                    "sql_optimization"
+                ],
+                "summary": "获取SQL优化记录列表",
+                "operationId": "getOptimizationRecords",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "fuzzy search for optimization_id or create_username",
+                        "name": "fuzzy_search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter instance name",
+                        "name": "filter_instance_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create time from",
+                        "name": "filter_create_time_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create time to",
+                        "name": "filter_create_time_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetOptimizationRecordsRes"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "optimize sql\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.\n4. file[input_zip_file]: it is ZIP file that sql will be parsed from xml or sql file inside it.\n5. formData[git_http_url]:the url which scheme is http(s) and end with .git.\n6. formData[git_user_name]:The name of the user who owns the repository read access.\n7. formData[git_user_password]:The password corresponding to git_user_name.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sql_optimization"
+                ],
+                "summary": "优化SQL",
+                "operationId": "OptimizeSQLReq",
+                "parameters": [
+                    {
+                        "description": "sqls that should be optimization",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.OptimizeSQLReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "fuzzy search endpoint",
-                        "name": "fuzzy_search_endpoint",
-                        "in": "query"
+                        "description": "instance name",
+                        "name": "instance_name",
+                        "in": "formData"
                     },
                     {
                         "type": "string",
-                        "description": "fuzzy search schema name",
-                        "name": "fuzzy_search_schema_name",
-                        "in": "query"
+                        "description": "schema of instance",
+                        "name": "schema_name",
+                        "in": "formData"
                     },
                     {
-                        "enum": [
-                            "first_appear_timestamp",
-                            "last_receive_timestamp",
-                            "fp_count"
-                        ],
                         "type": "string",
-                        "description": "sort field",
-                        "name": "sort_field",
-                        "in": "query"
+                        "description": "db type of instance",
+                        "name": "db_type",
+                        "in": "formData"
                     },
                     {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
                         "type": "string",
-                        "description": "sort order",
-                        "name": "sort_order",
-                        "in": "query"
+                        "description": "sqls for audit",
+                        "name": "sql_content",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "optimization name",
+                        "name": "optimization_name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "input SQL file",
+                        "name": "input_sql_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input mybatis XML file",
+                        "name": "input_mybatis_xml_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input ZIP file",
+                        "name": "input_zip_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "git repository url",
+                        "name": "git_http_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the name of user to clone the repository",
+                        "name": "git_user_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the password corresponding to git_user_name",
+                        "name": "git_user_password",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.OptimizeSQLRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_optimization_records/{optimization_record_id}/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql optimization record",
+                "tags": [
+                    "sql_optimization"
+                ],
+                "summary": "获取SQL优化记录",
+                "operationId": "GetOptimizationRecordReq",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql optimization record id",
+                        "name": "optimization_record_id",
+                        "in": "path",
+                        "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "sql",
+                        "name": "sql",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetOptimizationRecordRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_optimization_records/{optimization_record_id}/sqls": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql optimization sqls",
+                "tags": [
+                    "sql_optimization"
+                ],
+                "summary": "获取SQL优化语句列表",
+                "operationId": "getOptimizationSQLs",
+                "parameters": [
                     {
                         "type": "integer",
                         "description": "page index",
@@ -5529,31 +7309,45 @@ var doc = `{
                         "name": "page_size",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "optimization record id",
+                        "name": "optimization_record_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlManageListResp"
+                            "$ref": "#/definitions/v1.GetOptimizationSQLsRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_manages/abnormal_audit_plan_instance": {
+        "/v1/projects/{project_name}/sql_optimization_records/{optimization_record_id}/sqls/{number}/": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get the instance of audit plan execution abnormal",
+                "description": "get sql optimization record",
                 "tags": [
-                    "SqlManage"
+                    "sql_optimization"
                 ],
-                "summary": "获取执行异常的扫描任务实例",
-                "operationId": "getAbnormalInstanceAuditPlansV1",
+                "summary": "获取SQL优化语句详情",
+                "operationId": "GetOptimizationReq",
                 "parameters": [
                     {
                         "type": "string",
@@ -5561,73 +7355,111 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql optimization record id",
+                        "name": "optimization_record_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "optimization record sql  number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetAbnormalAuditPlanInstancesResp"
+                            "$ref": "#/definitions/v1.GetOptimizationSQLRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_manages/batch": {
-            "patch": {
+        "/v1/projects/{project_name}/sql_performance_insights": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "batch update sql manage",
+                "description": "get sql manage sql performance insights",
                 "tags": [
-                    "SqlManage"
+                    "SqlInsight"
                 ],
-                "summary": "批量更新SQL管控",
-                "operationId": "BatchUpdateSqlManage",
+                "summary": "获取SQL管控SQL性能洞察图表数据",
+                "operationId": "GetSqlPerformanceInsights",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
-                        "in": "path",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "comprehensive_trend",
+                            "slow_sql_trend",
+                            "top_sql_trend",
+                            "active_session_trend"
+                        ],
+                        "type": "string",
+                        "description": "metric name",
+                        "name": "metric_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start time",
+                        "name": "start_time",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "batch update sql manage request",
-                        "name": "BatchUpdateSqlManageReq",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.BatchUpdateSqlManageReq"
-                        }
+                        "type": "string",
+                        "description": "end time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "instance id",
+                        "name": "instance_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetSqlPerformanceInsightsResp"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_manages/exports": {
+        "/v1/projects/{project_name}/sql_performance_insights/related_sql": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "export sql manage",
+                "description": "Get related SQL for the selected time range in SQL performance insights",
                 "tags": [
-                    "SqlManage"
+                    "SqlInsight"
                 ],
-                "summary": "导出SQL管控",
-                "operationId": "exportSqlManageV1",
-                "deprecated": true,
+                "summary": "获取sql洞察 时间选区 的关联SQL",
+                "operationId": "GetSqlPerformanceInsightsRelatedSQL",
                 "parameters": [
                     {
                         "type": "string",
@@ -5638,153 +7470,133 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "fuzzy search sql fingerprint",
-                        "name": "fuzzy_search_sql_fingerprint",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "assignee",
-                        "name": "filter_assignee",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter by business",
-                        "name": "filter_business",
-                        "in": "query"
+                        "description": "instance id",
+                        "name": "instance_id",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "high",
-                            "low"
-                        ],
                         "type": "string",
-                        "description": "priority",
-                        "name": "filter_priority",
-                        "in": "query"
+                        "description": "start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "instance id",
-                        "name": "filter_instance_id",
-                        "in": "query"
+                        "description": "end time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "enum": [
-                            "audit_plan",
-                            "sql_audit_record"
+                            "workflow",
+                            "sql_manage",
+                            "workbench"
                         ],
                         "type": "string",
-                        "description": "source",
+                        "description": "filter by SQL source",
                         "name": "filter_source",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "normal",
-                            "notice",
-                            "warn",
-                            "error"
-                        ],
-                        "type": "string",
-                        "description": "audit level",
-                        "name": "filter_audit_level",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "last audit start time from",
-                        "name": "filter_last_audit_start_time_from",
-                        "in": "query"
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "last audit start time to",
-                        "name": "filter_last_audit_start_time_to",
+                        "description": "order by field",
+                        "name": "order_by",
                         "in": "query"
                     },
                     {
-                        "enum": [
-                            "unhandled",
-                            "solved",
-                            "ignored",
-                            "manual_audited"
-                        ],
-                        "type": "string",
-                        "description": "status",
-                        "name": "filter_status",
+                        "type": "boolean",
+                        "description": "is ascending order",
+                        "name": "is_asc",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "db type",
-                        "name": "filter_db_type",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "rule name",
-                        "name": "filter_rule_name",
-                        "in": "query"
-                    },
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlPerformanceInsightsRelatedSQLResp"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_performance_insights/related_sql/related_transaction": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "fuzzy search endpoint",
-                        "name": "fuzzy_search_endpoint",
-                        "in": "query"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get related transaction for the selected SQL in SQL performance insights",
+                "tags": [
+                    "SqlInsight"
+                ],
+                "summary": "获取sql洞察 相关SQL中具体一条SQL 的关联事务",
+                "operationId": "GetSqlPerformanceInsightsRelatedTransaction",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "fuzzy search schema name",
-                        "name": "fuzzy_search_schema_name",
-                        "in": "query"
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "first_appear_timestamp",
-                            "last_receive_timestamp",
-                            "fp_count"
-                        ],
                         "type": "string",
-                        "description": "sort field",
-                        "name": "sort_field",
-                        "in": "query"
+                        "description": "instance id",
+                        "name": "instance_id",
+                        "in": "query",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
                         "type": "string",
-                        "description": "sort order",
-                        "name": "sort_order",
-                        "in": "query"
+                        "description": "sql id",
+                        "name": "sql_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "export sql manage",
+                        "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/v1.GetSqlPerformanceInsightsRelatedTransactionResp"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_manages/rule_tips": {
+        "/v1/projects/{project_name}/sql_query_masking_rules": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql manage rule tips",
+                "description": "get the masking rules of the query console, a column is masked by the first enabled rule matching its name",
                 "tags": [
-                    "SqlManage"
+                    "sql_query"
                 ],
-                "summary": "获取管控规则tips",
-                "operationId": "GetSqlManageRuleTips",
+                "summary": "获取查询控制台的脱敏规则",
+                "operationId": "getSQLQueryMaskingRulesV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5798,25 +7610,26 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlManageRuleTipsResp"
+                            "$ref": "#/definitions/v1.GetSQLQueryMaskingRulesResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/sql_manages/send": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql manage analysis",
+                "description": "create a masking rule of the query console",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "SqlManage"
+                    "sql_query"
                 ],
-                "summary": "推送SQL管控结果到外部系统",
-                "operationId": "SendSqlManage",
+                "summary": "添加查询控制台的脱敏规则",
+                "operationId": "createSQLQueryMaskingRuleV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5826,12 +7639,12 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "batch update sql manage request",
-                        "name": "SqlManageCodingReq",
+                        "description": "create masking rule request",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.SqlManageCodingReq"
+                            "$ref": "#/definitions/v1.CreateSQLQueryMaskingRuleReqV1"
                         }
                     }
                 ],
@@ -5839,25 +7652,65 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.PostSqlManageCodingResp"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_manages/{sql_manage_id}/sql_analysis": {
-            "get": {
+        "/v1/projects/{project_name}/sql_query_masking_rules/{rule_id}/": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete a masking rule of the query console",
+                "tags": [
+                    "sql_query"
+                ],
+                "summary": "删除查询控制台的脱敏规则",
+                "operationId": "deleteSQLQueryMaskingRuleV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "masking rule id",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql manage analysis",
+                "description": "update a masking rule of the query console",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "SqlManage"
+                    "sql_query"
                 ],
-                "summary": "获取SQL管控SQL分析",
-                "operationId": "GetSqlManageSqlAnalysisV1",
+                "summary": "更新查询控制台的脱敏规则",
+                "operationId": "updateSQLQueryMaskingRuleV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5868,41 +7721,44 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "sql manage id",
-                        "name": "sql_manage_id",
+                        "description": "masking rule id",
+                        "name": "rule_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "whether to calculate and return affected rows, default is true",
-                        "name": "affectRowsEnabled",
-                        "in": "query"
+                        "description": "update masking rule request",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSQLQueryMaskingRuleReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlManageSqlAnalysisResp"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_manages/{sql_manage_id}/sql_analysis_chart": {
+        "/v1/projects/{project_name}/sql_query_records": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql manage analysis",
+                "description": "get the records of the query console, the members who are not project admin only get their own records",
                 "tags": [
-                    "SqlManage"
+                    "sql_query"
                 ],
-                "summary": "获取SQL管控SQL执行计划Cost趋势图表",
-                "operationId": "GetSqlManageSqlAnalysisChartV1",
+                "summary": "获取查询控制台的查询记录",
+                "operationId": "getSQLQueryRecordsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5913,36 +7769,38 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "sql manage id",
-                        "name": "sql_manage_id",
-                        "in": "path",
-                        "required": true
+                        "description": "filter instance name",
+                        "name": "filter_instance_name",
+                        "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "latest_point_enabled",
-                        "name": "latest_point_enabled",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "filter user id",
+                        "name": "filter_user_id",
+                        "in": "query"
                     },
                     {
+                        "enum": [
+                            "succeeded",
+                            "rejected",
+                            "failed"
+                        ],
                         "type": "string",
-                        "description": "start time",
-                        "name": "start_time",
-                        "in": "query",
-                        "required": true
+                        "description": "filter status",
+                        "name": "filter_status",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "end time",
-                        "name": "end_time",
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "metric_name",
-                        "name": "metric_name",
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
                         "in": "query",
                         "required": true
                     }
@@ -5951,48 +7809,70 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.SqlManageAnalysisChartResp"
+                            "$ref": "#/definitions/v1.GetSQLQueryRecordsResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_optimization_records": {
+        "/v1/projects/{project_name}/sql_versions": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql optimization records",
+                "description": "sql version list",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "sql_optimization"
+                    "sql_version"
                 ],
-                "summary": "获取SQL优化记录列表",
-                "operationId": "getOptimizationRecords",
+                "summary": "获取SQL版本列表",
+                "operationId": "getSqlVersionListV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "fuzzy search for optimization_id or create_username",
-                        "name": "fuzzy_search",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by created at from",
+                        "name": "filter_by_created_at_from",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "filter instance name",
-                        "name": "filter_instance_name",
+                        "description": "filter by created at to",
+                        "name": "filter_by_created_at_to",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "filter create time from",
-                        "name": "filter_create_time_from",
+                        "description": "filter by lock time from",
+                        "name": "filter_by_lock_time_from",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "filter create time to",
-                        "name": "filter_create_time_to",
+                        "description": "filter by lock time to",
+                        "name": "filter_by_lock_time_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by version status",
+                        "name": "filter_by_version_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fuzzy search",
+                        "name": "fuzzy_search",
                         "in": "query"
                     },
                     {
@@ -6008,20 +7888,13 @@ var doc = `{
                         "name": "page_size",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetOptimizationRecordsRes"
+                            "$ref": "#/definitions/v1.GetSqlVersionListResV1"
                         }
                     }
                 }
@@ -6032,28 +7905,16 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "optimize sql\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.\n4. file[input_zip_file]: it is ZIP file that sql will be parsed from xml or sql file inside it.\n5. formData[git_http_url]:the url which scheme is http(s) and end with .git.\n6. formData[git_user_name]:The name of the user who owns the repository read access.\n7. formData[git_user_password]:The password corresponding to git_user_name.",
+                "description": "create sql version",
                 "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sql_optimization"
+                    "sql_version"
                 ],
-                "summary": "优化SQL",
-                "operationId": "OptimizeSQLReq",
+                "summary": "创建SQL版本记录",
+                "operationId": "createSqlVersionV1",
                 "parameters": [
-                    {
-                        "description": "sqls that should be optimization",
-                        "name": "req",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.OptimizeSQLReq"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "project name",
@@ -6062,96 +7923,112 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "instance name",
-                        "name": "instance_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "schema of instance",
-                        "name": "schema_name",
-                        "in": "formData"
-                    },
+                        "description": "create sql version request",
+                        "name": "sql_version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateSqlVersionReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateSqlVersionResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "db type of instance",
-                        "name": "db_type",
-                        "in": "formData"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get sql version detail",
+                "tags": [
+                    "sql_version"
+                ],
+                "summary": "获取SQL版本详情",
+                "operationId": "getSqlVersionDetailV1",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "sqls for audit",
-                        "name": "sql_content",
-                        "in": "formData"
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "optimization name",
-                        "name": "optimization_name",
-                        "in": "formData",
+                        "description": "sql version id",
+                        "name": "sql_version_id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "input SQL file",
-                        "name": "input_sql_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input mybatis XML file",
-                        "name": "input_mybatis_xml_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input ZIP file",
-                        "name": "input_zip_file",
-                        "in": "formData"
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSqlVersionDetailResV1"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "git repository url",
-                        "name": "git_http_url",
-                        "in": "formData"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete sql version",
+                "tags": [
+                    "sql_version"
+                ],
+                "summary": "删除SQL版本",
+                "operationId": "deleteSqlVersionV1",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "the name of user to clone the repository",
-                        "name": "git_user_name",
-                        "in": "formData"
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "the password corresponding to git_user_name",
-                        "name": "git_user_password",
-                        "in": "formData"
+                        "description": "sql version id",
+                        "name": "sql_version_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.OptimizeSQLRes"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/sql_optimization_records/{optimization_record_id}/": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql optimization record",
+                "description": "update sql version",
                 "tags": [
-                    "sql_optimization"
+                    "sql_version"
                 ],
-                "summary": "获取SQL优化记录",
-                "operationId": "GetOptimizationRecordReq",
+                "summary": "更新SQL版本信息",
+                "operationId": "updateSqlVersionV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6162,56 +8039,44 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "sql optimization record id",
-                        "name": "optimization_record_id",
+                        "description": "sql version id",
+                        "name": "sql_version_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "sql",
-                        "name": "sql",
-                        "in": "query"
+                        "description": "update sql version request",
+                        "name": "sql_version",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSqlVersionReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetOptimizationRecordRes"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_optimization_records/{optimization_record_id}/sqls": {
-            "get": {
+        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/batch_execute_workflows": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql optimization sqls",
+                "description": "batch execute workflows",
                 "tags": [
-                    "sql_optimization"
+                    "sql_version"
                 ],
-                "summary": "获取SQL优化语句列表",
-                "operationId": "getOptimizationSQLs",
+                "summary": "工单批量上线",
+                "operationId": "batchExecuteWorkflowsV1",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "project name",
@@ -6221,35 +8086,44 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "optimization record id",
-                        "name": "optimization_record_id",
+                        "description": "sql version id",
+                        "name": "sql_version_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "batch execute workflows request",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchExecuteWorkflowsReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetOptimizationSQLsRes"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_optimization_records/{optimization_record_id}/sqls/{number}/": {
-            "get": {
+        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/batch_release_workflows": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql optimization record",
+                "description": "batch release workflow",
                 "tags": [
-                    "sql_optimization"
+                    "sql_version"
                 ],
-                "summary": "获取SQL优化语句详情",
-                "operationId": "GetOptimizationReq",
+                "summary": "批量发布工单（在版本的下一阶段创建工单）",
+                "operationId": "batchReleaseWorkflowsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6260,42 +8134,44 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "sql optimization record id",
-                        "name": "optimization_record_id",
+                        "description": "sql version id",
+                        "name": "sql_version_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "optimization record sql  number",
-                        "name": "number",
-                        "in": "path",
-                        "required": true
+                        "description": "batch release workflow request",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchReleaseWorkflowReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetOptimizationSQLRes"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_performance_insights": {
-            "get": {
+        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/lock": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql manage sql performance insights",
+                "description": "lock sql version",
                 "tags": [
-                    "SqlInsight"
+                    "sql_version"
                 ],
-                "summary": "获取SQL管控SQL性能洞察图表数据",
-                "operationId": "GetSqlPerformanceInsights",
+                "summary": "锁定SQL版本",
+                "operationId": "lockSqlVersionV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6304,64 +8180,46 @@ var doc = `{
                         "in": "path",
                         "required": true
                     },
-                    {
-                        "enum": [
-                            "comprehensive_trend",
-                            "slow_sql_trend",
-                            "top_sql_trend",
-                            "active_session_trend"
-                        ],
-                        "type": "string",
-                        "description": "metric name",
-                        "name": "metric_name",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "start time",
-                        "name": "start_time",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "end time",
-                        "name": "end_time",
-                        "in": "query",
+                        "description": "sql version id",
+                        "name": "sql_version_id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "instance id",
-                        "name": "instance_id",
-                        "in": "query",
-                        "required": true
+                        "description": "lock sql version request",
+                        "name": "sql_version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.LockSqlVersionReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlPerformanceInsightsResp"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_performance_insights/related_sql": {
+        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/sql_version_stages/{sql_version_stage_id}/associate_workflows": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get related SQL for the selected time range in SQL performance insights",
+                "description": "get workflows that can be associated to version",
                 "tags": [
-                    "SqlInsight"
+                    "sql_version"
                 ],
-                "summary": "获取sql洞察 时间选区 的关联SQL",
-                "operationId": "GetSqlPerformanceInsightsRelatedSQL",
+                "summary": "获取可与版本关联的工单",
+                "operationId": "GetWorkflowsThatCanBeAssociatedToVersionV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6372,87 +8230,95 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "instance id",
-                        "name": "instance_id",
-                        "in": "query",
+                        "description": "sql version id",
+                        "name": "sql_version_id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "start time",
-                        "name": "start_time",
-                        "in": "query",
+                        "description": "sql version stage id",
+                        "name": "sql_version_stage_id",
+                        "in": "path",
                         "required": true
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetWorkflowsThatCanBeAssociatedToVersionResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "end time",
-                        "name": "end_time",
-                        "in": "query",
-                        "required": true
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "batch associate workflows with version",
+                "tags": [
+                    "sql_version"
+                ],
+                "summary": "批量关联工单到版本",
+                "operationId": "batchAssociateWorkflowsWithVersionV1",
+                "parameters": [
                     {
-                        "enum": [
-                            "workflow",
-                            "sql_manage",
-                            "workbench"
-                        ],
                         "type": "string",
-                        "description": "filter by SQL source",
-                        "name": "filter_source",
-                        "in": "query",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "order by field",
-                        "name": "order_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "is ascending order",
-                        "name": "is_asc",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query",
+                        "description": "sql version id",
+                        "name": "sql_version_id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query",
+                        "type": "string",
+                        "description": "sql version stage id",
+                        "name": "sql_version_stage_id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "batch associate workflows with version request",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchAssociateWorkflowsWithVersionReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlPerformanceInsightsRelatedSQLResp"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_performance_insights/related_sql/related_transaction": {
+        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/sql_version_stages/{sql_version_stage_id}/dependencies": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get related transaction for the selected SQL in SQL performance insights",
+                "description": "get dependencies between stage instance",
                 "tags": [
-                    "SqlInsight"
+                    "sql_version"
                 ],
-                "summary": "获取sql洞察 相关SQL中具体一条SQL 的关联事务",
-                "operationId": "GetSqlPerformanceInsightsRelatedTransaction",
+                "summary": "获取当前阶段与下一阶段实例的依赖信息",
+                "operationId": "getDependenciesBetweenStageInstanceV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6463,16 +8329,16 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "instance id",
-                        "name": "instance_id",
-                        "in": "query",
+                        "description": "sql version id",
+                        "name": "sql_version_id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "sql id",
-                        "name": "sql_id",
-                        "in": "query",
+                        "description": "sql version stage id",
+                        "name": "sql_version_stage_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -6480,28 +8346,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlPerformanceInsightsRelatedTransactionResp"
+                            "$ref": "#/definitions/v1.GetDepBetweenStageInstanceResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_versions": {
+        "/v1/projects/{project_name}/statistic/audit_plans": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "sql version list",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "statistic audit plan",
                 "tags": [
-                    "sql_version"
+                    "statistic"
                 ],
-                "summary": "获取SQL版本列表",
-                "operationId": "getSqlVersionListV1",
+                "summary": "获取各类型数据源上的扫描任务数量",
+                "operationId": "statisticAuditPlanV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6509,82 +8372,31 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter by created at from",
-                        "name": "filter_by_created_at_from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter by created at to",
-                        "name": "filter_by_created_at_to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter by lock time from",
-                        "name": "filter_by_lock_time_from",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter by lock time to",
-                        "name": "filter_by_lock_time_to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter by version status",
-                        "name": "filter_by_version_status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "fuzzy search",
-                        "name": "fuzzy_search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page index",
-                        "name": "page_index",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "size of per page",
-                        "name": "page_size",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlVersionListResV1"
+                            "$ref": "#/definitions/v1.StatisticAuditPlanResV1"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/projects/{project_name}/statistic/audited_sqls": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create sql version",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "statistics audited sql",
                 "tags": [
-                    "sql_version"
+                    "statistic"
                 ],
-                "summary": "创建SQL版本记录",
-                "operationId": "createSqlVersionV1",
+                "summary": "获取审核SQL总数，以及触发审核规则的SQL数量",
+                "operationId": "statisticsAuditedSQLV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6592,40 +8404,31 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "create sql version request",
-                        "name": "sql_version",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.CreateSqlVersionReqV1"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateSqlVersionResV1"
+                            "$ref": "#/definitions/v1.StatisticsAuditedSQLResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/": {
+        "/v1/projects/{project_name}/statistic/instance_health": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get sql version detail",
+                "description": "get instance health",
                 "tags": [
-                    "sql_version"
+                    "statistic"
                 ],
-                "summary": "获取SQL版本详情",
-                "operationId": "getSqlVersionDetailV1",
+                "summary": "获取各类型数据源的健康情况",
+                "operationId": "GetInstanceHealthV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6633,11 +8436,36 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetInstanceHealthResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/statistic/optimization_performance_improve_overview": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get db optimization performance improvements",
+                "tags": [
+                    "sql_optimization"
+                ],
+                "summary": "获取实例性能提升概览",
+                "operationId": "getDBPerformanceImproveOverview",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     }
@@ -6646,35 +8474,44 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlVersionDetailResV1"
+                            "$ref": "#/definitions/v1.GetDBPerformanceImproveOverviewResp"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/projects/{project_name}/statistic/optimization_record_overview": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "delete sql version",
+                "description": "get sql optimization record overview",
                 "tags": [
-                    "sql_version"
+                    "sql_optimization"
                 ],
-                "summary": "删除SQL版本",
-                "operationId": "deleteSqlVersionV1",
+                "summary": "获取SQL优化记录概览",
+                "operationId": "getOptimizationOverview",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
-                        "in": "path",
+                        "description": "create time from",
+                        "name": "filter_create_time_from",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
+                        "description": "create time to",
+                        "name": "filter_create_time_to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     }
@@ -6683,23 +8520,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetOptimizationOverviewResp"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/projects/{project_name}/statistic/project_score": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update sql version",
+                "description": "get project score",
                 "tags": [
-                    "sql_version"
+                    "statistic"
                 ],
-                "summary": "更新SQL版本信息",
-                "operationId": "updateSqlVersionV1",
+                "summary": "获取项目分数",
+                "operationId": "GetProjectScoreV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6707,46 +8546,63 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "update sql version request",
-                        "name": "sql_version",
-                        "in": "body",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateSqlVersionReqV1"
+                            "$ref": "#/definitions/v1.GetProjectScoreResV1"
                         }
                     }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/statistic/risk_audit_plans": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get risk audit plan",
+                "tags": [
+                    "statistic"
+                ],
+                "summary": "获取扫描任务报告评分低于60的扫描任务",
+                "operationId": "getRiskAuditPlanV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetRiskAuditPlanResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/batch_execute_workflows": {
-            "post": {
+        "/v1/projects/{project_name}/statistic/risk_workflow": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "batch execute workflows",
+                "description": "statistic risk workflow",
                 "tags": [
-                    "sql_version"
+                    "statistic"
                 ],
-                "summary": "工单批量上线",
-                "operationId": "batchExecuteWorkflowsV1",
+                "summary": "获取存在风险的工单",
+                "operationId": "statisticRiskWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6754,47 +8610,31 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "batch execute workflows request",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.BatchExecuteWorkflowsReqV1"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.StatisticRiskWorkflowResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/batch_release_workflows": {
-            "post": {
+        "/v1/projects/{project_name}/statistic/role_user": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "batch release workflow",
+                "description": "get role user count",
                 "tags": [
-                    "sql_version"
+                    "statistic"
                 ],
-                "summary": "批量发布工单（在版本的下一阶段创建工单）",
-                "operationId": "batchReleaseWorkflowsV1",
+                "summary": "获取各角色类型对应的成员数量",
+                "operationId": "getRoleUserCountV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6802,47 +8642,31 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "batch release workflow request",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.BatchReleaseWorkflowReqV1"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetRoleUserCountResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/lock": {
-            "post": {
+        "/v1/projects/{project_name}/statistic/workflow_status": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "lock sql version",
+                "description": "statistic workflow status",
                 "tags": [
-                    "sql_version"
+                    "statistic"
                 ],
-                "summary": "锁定SQL版本",
-                "operationId": "lockSqlVersionV1",
+                "summary": "获取项目下工单各个状态的数量",
+                "operationId": "statisticWorkflowStatusV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6850,47 +8674,31 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "lock sql version request",
-                        "name": "sql_version",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.LockSqlVersionReqV1"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetWorkflowStatusCountResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/sql_version_stages/{sql_version_stage_id}/associate_workflows": {
+        "/v1/projects/{project_name}/statistics": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get workflows that can be associated to version",
+                "description": "get project statistics",
                 "tags": [
-                    "sql_version"
+                    "statistic"
                 ],
-                "summary": "获取可与版本关联的工单",
-                "operationId": "GetWorkflowsThatCanBeAssociatedToVersionV1",
+                "summary": "获取项目统计信息",
+                "operationId": "getProjectStatisticsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6898,43 +8706,37 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "sql version stage id",
-                        "name": "sql_version_stage_id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetWorkflowsThatCanBeAssociatedToVersionResV1"
+                            "$ref": "#/definitions/v1.GetProjectStatisticsResV1"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/projects/{project_name}/task_groups": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "batch associate workflows with version",
+                "description": "create tasks group.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "sql_version"
+                    "task"
                 ],
-                "summary": "批量关联工单到版本",
-                "operationId": "batchAssociateWorkflowsWithVersionV1",
+                "summary": "创建审核任务组",
+                "operationId": "createAuditTasksV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -6944,26 +8746,12 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "sql version stage id",
-                        "name": "sql_version_stage_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "batch associate workflows with version request",
-                        "name": "data",
+                        "description": "parameters for creating audit tasks group",
+                        "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.BatchAssociateWorkflowsWithVersionReqV1"
+                            "$ref": "#/definitions/v1.CreateAuditTasksGroupReqV1"
                         }
                     }
                 ],
@@ -6971,25 +8759,31 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.CreateAuditTasksGroupResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/sql_versions/{sql_version_id}/sql_version_stages/{sql_version_stage_id}/dependencies": {
-            "get": {
+        "/v1/projects/{project_name}/tasks/audits": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get dependencies between stage instance",
+                "description": "create and audit a task, you can upload sql content in three ways, any one can be used, but only one is effective.\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "sql_version"
+                    "task"
                 ],
-                "summary": "获取当前阶段与下一阶段实例的依赖信息",
-                "operationId": "getDependenciesBetweenStageInstanceV1",
+                "summary": "创建Sql扫描任务并提交审核",
+                "operationId": "createAndAuditTaskV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7000,74 +8794,104 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "sql version id",
-                        "name": "sql_version_id",
-                        "in": "path",
+                        "description": "instance name",
+                        "name": "instance_name",
+                        "in": "formData",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "sql version stage id",
-                        "name": "sql_version_stage_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/v1.GetDepBetweenStageInstanceResV1"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/projects/{project_name}/statistic/audit_plans": {
-            "get": {
-                "security": [
+                        "description": "schema of instance",
+                        "name": "instance_schema",
+                        "in": "formData"
+                    },
                     {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "statistic audit plan",
-                "tags": [
-                    "statistic"
-                ],
-                "summary": "获取各类型数据源上的扫描任务数量",
-                "operationId": "statisticAuditPlanV1",
-                "parameters": [
+                        "type": "boolean",
+                        "description": "enable backup",
+                        "name": "enable_backup",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "backup max rows",
+                        "name": "backup_max_rows",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sqls for audit",
+                        "name": "sql",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "rule template name",
+                        "name": "rule_template_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input SQL file",
+                        "name": "input_sql_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input mybatis XML file",
+                        "name": "input_mybatis_xml_file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "input ZIP file",
+                        "name": "input_zip_file",
+                        "in": "formData"
+                    },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
-                        "in": "path",
-                        "required": true
+                        "description": "exec mode",
+                        "name": "exec_mode",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "file order method",
+                        "name": "file_order_method",
+                        "in": "formData"
+                    },
+                    {
+                        "description": "create and audit task",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateAuditTaskReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.StatisticAuditPlanResV1"
+                            "$ref": "#/definitions/v1.GetAuditTaskResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/statistic/audited_sqls": {
+        "/v1/projects/{project_name}/workflow_duty_policy": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "statistics audited sql",
+                "description": "get the separation of duties policy of a workflow template, the default workflow template of the project is used if workflow_template_id is empty",
                 "tags": [
-                    "statistic"
+                    "workflow"
                 ],
-                "summary": "获取审核SQL总数，以及触发审核规则的SQL数量",
-                "operationId": "statisticsAuditedSQLV1",
+                "summary": "获取审批流程模板的职责分离策略",
+                "operationId": "getWorkflowDutyPolicyV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7075,31 +8899,38 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "workflow template id",
+                        "name": "workflow_template_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.StatisticsAuditedSQLResV1"
+                            "$ref": "#/definitions/v1.GetWorkflowDutyPolicyResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/statistic/instance_health": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get instance health",
+                "description": "update the separation of duties policy of a workflow template, the default workflow template of the project is used if workflow_template_id is empty. The policy is checked when the workflows are approved or executed.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "statistic"
+                    "workflow"
                 ],
-                "summary": "获取各类型数据源的健康情况",
-                "operationId": "GetInstanceHealthV1",
+                "summary": "更新审批流程模板的职责分离策略",
+                "operationId": "updateWorkflowDutyPolicyV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7107,31 +8938,40 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "update workflow duty policy request",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateWorkflowDutyPolicyReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetInstanceHealthResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/statistic/optimization_performance_improve_overview": {
+        "/v1/projects/{project_name}/workflow_sla_breaches": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get db optimization performance improvements",
+                "description": "get the workflow steps escalated for breaching their sla",
                 "tags": [
-                    "sql_optimization"
+                    "workflow"
                 ],
-                "summary": "获取实例性能提升概览",
-                "operationId": "getDBPerformanceImproveOverview",
+                "summary": "获取审批SLA超时记录",
+                "operationId": "getWorkflowSLABreachesV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7139,51 +8979,37 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/v1.GetDBPerformanceImproveOverviewResp"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/projects/{project_name}/statistic/optimization_record_overview": {
-            "get": {
-                "security": [
+                    },
                     {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "get sql optimization record overview",
-                "tags": [
-                    "sql_optimization"
-                ],
-                "summary": "获取SQL优化记录概览",
-                "operationId": "getOptimizationOverview",
-                "parameters": [
+                        "type": "string",
+                        "description": "filter workflow id",
+                        "name": "filter_workflow_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "create time from",
-                        "name": "filter_create_time_from",
-                        "in": "query",
-                        "required": true
+                        "description": "filter breached at from, in RFC3339 format",
+                        "name": "filter_breached_at_from",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "create time to",
-                        "name": "filter_create_time_to",
+                        "description": "filter breached at to, in RFC3339 format",
+                        "name": "filter_breached_at_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
-                        "in": "path",
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -7191,25 +9017,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetOptimizationOverviewResp"
+                            "$ref": "#/definitions/v1.GetWorkflowSLABreachesResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/statistic/project_score": {
+        "/v1/projects/{project_name}/workflow_step_slas": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get project score",
+                "description": "get the sla of the steps of a workflow template, the default workflow template of the project is used if workflow_template_id is empty",
                 "tags": [
-                    "statistic"
+                    "workflow"
                 ],
-                "summary": "获取项目分数",
-                "operationId": "GetProjectScoreV1",
+                "summary": "获取审批流程模板各步骤的SLA配置",
+                "operationId": "getWorkflowStepSLAsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7217,31 +9043,38 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "workflow template id",
+                        "name": "workflow_template_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetProjectScoreResV1"
+                            "$ref": "#/definitions/v1.GetWorkflowStepSLAsResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/statistic/risk_audit_plans": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get risk audit plan",
+                "description": "update the sla of the steps of a workflow template, the default workflow template of the project is used if workflow_template_id is empty. The steps not in the request are unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "statistic"
+                    "workflow"
                 ],
-                "summary": "获取扫描任务报告评分低于60的扫描任务",
-                "operationId": "getRiskAuditPlanV1",
+                "summary": "更新审批流程模板各步骤的SLA配置",
+                "operationId": "updateWorkflowStepSLAsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7249,31 +9082,40 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "update workflow step sla request",
+                        "name": "steps",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateWorkflowStepSLAsReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetRiskAuditPlanResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/statistic/risk_workflow": {
+        "/v1/projects/{project_name}/workflow_template": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "statistic risk workflow",
+                "description": "get workflow template detail; query by workflow_template_id or by workflow_type default template",
                 "tags": [
-                    "statistic"
+                    "workflow"
                 ],
-                "summary": "获取存在风险的工单",
-                "operationId": "statisticRiskWorkflowV1",
+                "summary": "获取审批流程模板详情",
+                "operationId": "getWorkflowTemplateV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7281,31 +9123,53 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "workflow",
+                            "data_export"
+                        ],
+                        "type": "string",
+                        "description": "filter workflow type",
+                        "name": "workflow_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "workflow template id",
+                        "name": "workflow_template_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.StatisticRiskWorkflowResV1"
+                            "$ref": "#/definitions/v1.GetWorkflowTemplateResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/statistic/role_user": {
-            "get": {
+        "/v1/projects/{project_name}/workflow_template/{workflow_type}/": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get role user count",
+                "description": "update the workflow template",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "statistic"
+                    "workflow"
                 ],
-                "summary": "获取各角色类型对应的成员数量",
-                "operationId": "getRoleUserCountV1",
+                "summary": "更新Sql审批流程模板",
+                "operationId": "updateWorkflowTemplateV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7313,63 +9177,51 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/v1.GetRoleUserCountResV1"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/projects/{project_name}/statistic/workflow_status": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "statistic workflow status",
-                "tags": [
-                    "statistic"
-                ],
-                "summary": "获取项目下工单各个状态的数量",
-                "operationId": "statisticWorkflowStatusV1",
-                "parameters": [
+                    },
                     {
+                        "enum": [
+                            "workflow",
+                            "data_export"
+                        ],
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "workflow type",
+                        "name": "workflow_type",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "create workflow template",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateWorkflowTemplateReqV1"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetWorkflowStatusCountResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/statistics": {
+        "/v1/projects/{project_name}/workflow_template_route_rules": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get project statistics",
+                "description": "get the route rules to select the workflow template of the new workflows, the enabled rules are evaluated by priority in ascending order and the default workflow template is used if none matches",
                 "tags": [
-                    "statistic"
-                ],
-                "summary": "获取项目统计信息",
-                "operationId": "getProjectStatisticsV1",
+                    "workflow"
+                ],
+                "summary": "获取审批流程模板路由规则列表",
+                "operationId": "getWorkflowTemplateRouteRulesV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7383,31 +9235,26 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetProjectStatisticsResV1"
+                            "$ref": "#/definitions/v1.GetWorkflowTemplateRouteRulesResV1"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/task_groups": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create tasks group.",
+                "description": "create a route rule to select the workflow template of the new workflows whose attributes match all its conditions, an empty condition always matches",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "task"
+                    "workflow"
                 ],
-                "summary": "创建审核任务组",
-                "operationId": "createAuditTasksV1",
+                "summary": "添加审批流程模板路由规则",
+                "operationId": "createWorkflowTemplateRouteRuleV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7417,12 +9264,12 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "parameters for creating audit tasks group",
-                        "name": "req",
+                        "description": "create workflow template route rule request",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateAuditTasksGroupReqV1"
+                            "$ref": "#/definitions/v1.CreateWorkflowTemplateRouteRuleReqV1"
                         }
                     }
                 ],
@@ -7430,31 +9277,28 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateAuditTasksGroupResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/tasks/audits": {
+        "/v1/projects/{project_name}/workflow_template_route_rules/evaluate": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "create and audit a task, you can upload sql content in three ways, any one can be used, but only one is effective.\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.",
+                "description": "explain which workflow template would be selected for a workflow of the audited tasks and why, nothing is created",
                 "consumes": [
-                    "multipart/form-data"
-                ],
-                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "task"
+                    "workflow"
                 ],
-                "summary": "创建Sql扫描任务并提交审核",
-                "operationId": "createAndAuditTaskV1",
+                "summary": "预览审批流程模板路由结果",
+                "operationId": "evaluateWorkflowTemplateRouteV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7464,79 +9308,12 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "instance name",
-                        "name": "instance_name",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "schema of instance",
-                        "name": "instance_schema",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "enable backup",
-                        "name": "enable_backup",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "backup max rows",
-                        "name": "backup_max_rows",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "sqls for audit",
-                        "name": "sql",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "rule template name",
-                        "name": "rule_template_name",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input SQL file",
-                        "name": "input_sql_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input mybatis XML file",
-                        "name": "input_mybatis_xml_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "input ZIP file",
-                        "name": "input_zip_file",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "exec mode",
-                        "name": "exec_mode",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "file order method",
-                        "name": "file_order_method",
-                        "in": "formData"
-                    },
-                    {
-                        "description": "create and audit task",
+                        "description": "evaluate workflow template route request",
                         "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.CreateAuditTaskReqV1"
+                            "$ref": "#/definitions/v1.EvaluateWorkflowTemplateRouteReqV1"
                         }
                     }
                 ],
@@ -7544,25 +9321,25 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetAuditTaskResV1"
+                            "$ref": "#/definitions/v1.EvaluateWorkflowTemplateRouteResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/workflow_template": {
-            "get": {
+        "/v1/projects/{project_name}/workflow_template_route_rules/{rule_id}/": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get workflow template detail; query by workflow_template_id or by workflow_type default template",
+                "description": "delete a route rule of the workflow templates",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "获取审批流程模板详情",
-                "operationId": "getWorkflowTemplateV1",
+                "summary": "删除审批流程模板路由规则",
+                "operationId": "deleteWorkflowTemplateRouteRuleV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7572,51 +9349,37 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "enum": [
-                            "workflow",
-                            "data_export"
-                        ],
                         "type": "string",
-                        "description": "filter workflow type",
-                        "name": "workflow_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "workflow template id",
-                        "name": "workflow_template_id",
-                        "in": "query"
+                        "description": "route rule id",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetWorkflowTemplateResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
-            }
-        },
-        "/v1/projects/{project_name}/workflow_template/{workflow_type}/": {
+            },
             "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update the workflow template",
+                "description": "update a route rule of the workflow templates, the workflows created are not affected",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "workflow"
                 ],
-                "summary": "更新Sql审批流程模板",
-                "operationId": "updateWorkflowTemplateV1",
+                "summary": "更新审批流程模板路由规则",
+                "operationId": "updateWorkflowTemplateRouteRuleV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -7626,23 +9389,19 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "enum": [
-                            "workflow",
-                            "data_export"
-                        ],
                         "type": "string",
-                        "description": "workflow type",
-                        "name": "workflow_type",
+                        "description": "route rule id",
+                        "name": "rule_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "create workflow template",
-                        "name": "instance",
+                        "description": "update workflow template route rule request",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateWorkflowTemplateReqV1"
+                            "$ref": "#/definitions/v1.UpdateWorkflowTemplateRouteRuleReqV1"
                         }
                     }
                 ],
@@ -9259,7 +11018,7 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update custom rule knowledge",
+                "description": "update the knowledge of custom rule in the language of the user, a new version is recorded",
                 "tags": [
                     "rule_template"
                 ],
@@ -9344,7 +11103,7 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update rule knowledge",
+                "description": "update the knowledge of rule in the language of the user, a new version is recorded",
                 "tags": [
                     "rule_template"
                 ],
@@ -10895,12 +12654,139 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get SQL explain and related table metadata for analysis",
+                "description": "get SQL explain and related table metadata for analysis",
+                "tags": [
+                    "task"
+                ],
+                "summary": "获取task相关的SQL执行计划和表元数据",
+                "operationId": "getTaskAnalysisData",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "sql number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetTaskAnalysisDataResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tasks/audits/{task_id}/sqls/{number}/chunked_execution": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the progress of the chunked execution of the SQL, including the completed batches",
+                "tags": [
+                    "task"
+                ],
+                "summary": "获取工单中 SQL 分批执行的进度",
+                "operationId": "getSQLChunkedExecutionV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSQLChunkedExecutionResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "set whether the UPDATE/DELETE is executed in primary key range batches, each batch is committed and the execution sleeps between batches",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "task"
+                ],
+                "summary": "设置工单中 UPDATE/DELETE 是否按主键范围分批执行",
+                "operationId": "updateSQLChunkedExecutionV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sql number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "chunked execution settings",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSQLChunkedExecutionReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/tasks/audits/{task_id}/sqls/{number}/chunked_execution/pause": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "pause the chunked execution after the current batch, re-execute the SQL to resume from the last completed primary key range",
                 "tags": [
                     "task"
                 ],
-                "summary": "获取task相关的SQL执行计划和表元数据",
-                "operationId": "getTaskAnalysisData",
+                "summary": "暂停工单中 SQL 的分批执行",
+                "operationId": "pauseSQLChunkedExecutionV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -10910,7 +12796,7 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
+                        "type": "string",
                         "description": "sql number",
                         "name": "number",
                         "in": "path",
@@ -10921,7 +12807,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetTaskAnalysisDataResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
@@ -11080,6 +12966,38 @@ var doc = `{
                 }
             }
         },
+        "/v1/tasks/audits/{task_id}/throttle_events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the events that the execution of the task is throttled, resumed or aborted",
+                "tags": [
+                    "task"
+                ],
+                "summary": "获取工单任务上线过程中的限流事件",
+                "operationId": "getTaskThrottleEventsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetTaskThrottleEventsResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/tasks/file_order_methods": {
             "get": {
                 "security": [
@@ -11109,6 +13027,118 @@ var doc = `{
                 }
             }
         },
+        "/v1/user_delegations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the delegations the current user delegates or is delegated",
+                "tags": [
+                    "user_delegation"
+                ],
+                "summary": "获取审批委托列表",
+                "operationId": "getUserDelegationsV1",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "include the expired delegations",
+                        "name": "with_expired",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetUserDelegationsResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delegate the workflow steps assigned to the current user to another user during a period, e.g. when the current user is out of office. The delegate can operate the steps on behalf of the current user and receives the notifications of the current user during the period.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_delegation"
+                ],
+                "summary": "添加审批委托",
+                "operationId": "createUserDelegationV1",
+                "parameters": [
+                    {
+                        "description": "create user delegation request",
+                        "name": "delegation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateUserDelegationReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user_delegations/{delegation_id}/": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete a delegation of the current user, the delegate can not operate the steps of the current user any more",
+                "tags": [
+                    "user_delegation"
+                ],
+                "summary": "删除审批委托",
+                "operationId": "deleteUserDelegationV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "delegation id",
+                        "name": "delegation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/user_tips": {
             "get": {
                 "security": [
@@ -14489,6 +16519,24 @@ var doc = `{
                 }
             }
         },
+        "model.CategoryScorePenalty": {
+            "type": "object",
+            "properties": {
+                "cap": {
+                    "type": "number"
+                },
+                "capped": {
+                    "description": "Capped is true if the penalty of the rules in the category exceeds the cap",
+                    "type": "boolean"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "penalty": {
+                    "type": "number"
+                }
+            }
+        },
         "model.I18nAuditResultInfo": {
             "type": "object",
             "additionalProperties": {
@@ -14515,6 +16563,55 @@ var doc = `{
                 }
             }
         },
+        "model.ScoreBreakdown": {
+            "type": "object",
+            "properties": {
+                "base_score": {
+                    "description": "the fields below are only set by the project policy",
+                    "type": "integer"
+                },
+                "category_penalties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CategoryScorePenalty"
+                    }
+                },
+                "hard_fail_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "level_counts": {
+                    "description": "LevelCounts is the number of SQLs at each audit level",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "level_penalties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "policy": {
+                    "type": "string"
+                },
+                "rule_penalties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "total_penalty": {
+                    "type": "number"
+                }
+            }
+        },
         "sql_flash.AdvisedIndex": {
             "type": "object",
             "properties": {
@@ -15533,6 +17630,10 @@ var doc = `{
                 "score": {
                     "type": "integer"
                 },
+                "score_breakdown": {
+                    "type": "object",
+                    "$ref": "#/definitions/model.ScoreBreakdown"
+                },
                 "sql_source": {
                     "type": "string",
                     "enum": [
@@ -16386,34 +18487,171 @@ var doc = `{
                 "desc": {
                     "type": "string"
                 },
-                "rule_list": {
+                "rule_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleReqV1"
+                    }
+                },
+                "rule_template_name": {
+                    "type": "string"
+                },
+                "rule_version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.CreateSQLAuditRecordResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SQLAuditRecordResData"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.CreateSQLQueryMaskingRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "column_pattern": {
+                    "type": "string",
+                    "example": "^(phone|mobile)$"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "masking_type": {
+                    "type": "string",
+                    "enum": [
+                        "phone",
+                        "id_card",
+                        "email",
+                        "regex"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "type": "string",
+                    "example": "***"
+                },
+                "value_pattern": {
+                    "type": "string",
+                    "example": "\\d{16}"
+                }
+            }
+        },
+        "v1.CreateSchemaBaselineReqV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string",
+                    "example": "baseline after release v1.2"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "snapshot",
+                        "workflow"
+                    ]
+                }
+            }
+        },
+        "v1.CreateSqlManageTriageRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "assignees": {
+                    "description": "Assignees are the user ids the SQL is assigned to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "comment": {
+                    "description": "Comment is appended to the remark of the SQL",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "inactive_days": {
+                    "description": "InactiveDays is the days the SQL has not been seen before it is closed by the auto_close rule",
+                    "type": "integer"
+                },
+                "instance_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "metric_conditions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.RuleReqV1"
+                        "$ref": "#/definitions/v1.SqlManageMetricConditionV1"
                     }
                 },
-                "rule_template_name": {
+                "min_audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "name": {
                     "type": "string"
                 },
-                "rule_version": {
+                "priority": {
                     "type": "integer"
-                }
-            }
-        },
-        "v1.CreateSQLAuditRecordResV1": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 0
                 },
-                "data": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.SQLAuditRecordResData"
+                "schema_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "message": {
+                "sources": {
+                    "description": "Sources are the audit plan types collecting the SQL",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sql_priority": {
+                    "description": "SQLPriority is not changed if it is null",
                     "type": "string",
-                    "example": "ok"
+                    "enum": [
+                        "",
+                        "high"
+                    ]
+                },
+                "status": {
+                    "description": "Status is only set by the match rule, the auto_close rule solves the SQL and the auto_reopen rule reopens it",
+                    "type": "string",
+                    "enum": [
+                        "unhandled",
+                        "solved",
+                        "ignored",
+                        "manual_audited"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "match",
+                        "auto_close",
+                        "auto_reopen"
+                    ]
                 }
             }
         },
@@ -16489,6 +18727,59 @@ var doc = `{
                 }
             }
         },
+        "v1.CreateTeamKnowledgeReqV1": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "examples": {
+                    "type": "string"
+                },
+                "language": {
+                    "description": "为空时适用于所有语言",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "标签，与规则知识有相同标签时关联到规则",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CreateUserDelegationReqV1": {
+            "type": "object",
+            "properties": {
+                "delegate_user_id": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "instance_name": {
+                    "description": "InstanceName limits the delegation to the workflows only on the instance, project_name is required with it",
+                    "type": "string"
+                },
+                "project_name": {
+                    "description": "ProjectName limits the delegation to the project, empty means all projects",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.CreateWorkflowReqV1": {
             "type": "object",
             "properties": {
@@ -16542,6 +18833,87 @@ var doc = `{
                 }
             }
         },
+        "v1.CreateWorkflowTemplateRouteRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "any_sql_types": {
+                    "description": "AnySQLTypes matches if the workflow has any statement of the types",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "dml",
+                            "ddl",
+                            "dql",
+                            "dcl"
+                        ]
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "environment_tags": {
+                    "description": "EnvironmentTags matches if the environment tags of all the instances of the workflow are in it",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_affected_rows": {
+                    "type": "integer"
+                },
+                "max_audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "min_affected_rows": {
+                    "description": "MinAffectedRows and MaxAffectedRows limit the total estimated affected rows of the DML statements of the workflow",
+                    "type": "integer"
+                },
+                "min_audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "only_sql_types": {
+                    "description": "OnlySQLTypes matches if the workflow only has the statements of the types",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "dml",
+                            "ddl",
+                            "dql",
+                            "dcl"
+                        ]
+                    }
+                },
+                "ops_type_uids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "workflow_template_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.CustomRuleResV1": {
             "type": "object",
             "properties": {
@@ -16987,32 +19359,151 @@ var doc = `{
                     "type": "string",
                     "example": "高危拦截35次"
                 },
-                "metric_evaluation": {
-                    "description": "效能评价",
-                    "type": "string",
-                    "example": "S级,+450%,123h"
+                "metric_evaluation": {
+                    "description": "效能评价",
+                    "type": "string",
+                    "example": "S级,+450%,123h"
+                },
+                "metric_title": {
+                    "description": "效能指标标题",
+                    "type": "string",
+                    "enum": [
+                        "security_defense",
+                        "resource_cost",
+                        "code_standard",
+                        "rd_efficiency",
+                        "query_performance"
+                    ]
+                }
+            }
+        },
+        "v1.EnumsValueResV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.EvaluateWorkflowTemplateRouteReqV1": {
+            "type": "object",
+            "properties": {
+                "ops_type_uid": {
+                    "type": "string"
+                },
+                "task_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "v1.EvaluateWorkflowTemplateRouteResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.WorkflowTemplateRouteResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.ExecutePlanDiffRowResV1": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.ExecutePlanRowResV1"
+                },
+                "select_id": {
+                    "type": "integer"
+                },
+                "table": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.ExecutePlanRowResV1"
+                }
+            }
+        },
+        "v1.ExecutePlanRowResV1": {
+            "type": "object",
+            "properties": {
+                "extra": {
+                    "type": "string"
+                },
+                "filtered": {
+                    "type": "number"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "key_len": {
+                    "type": "integer"
+                },
+                "possible_keys": {
+                    "type": "string"
+                },
+                "ref": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "select_type": {
+                    "type": "string"
                 },
-                "metric_title": {
-                    "description": "效能指标标题",
-                    "type": "string",
-                    "enum": [
-                        "security_defense",
-                        "resource_cost",
-                        "code_standard",
-                        "rd_efficiency",
-                        "query_performance"
-                    ]
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "v1.EnumsValueResV1": {
+        "v1.ExecutionThrottleResV1": {
             "type": "object",
             "properties": {
-                "desc": {
-                    "type": "string"
+                "check_interval_second": {
+                    "type": "integer",
+                    "example": 5
                 },
-                "value": {
-                    "type": "string"
+                "discover_replicas": {
+                    "type": "boolean"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "max_replication_lag_sec": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "max_threads_running": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "replicas": {
+                    "description": "Replicas are the replicas in the DSNs without password, e.g. \"repl@10.10.10.10:3306\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout_second": {
+                    "type": "integer",
+                    "example": 1800
                 }
             }
         },
@@ -17948,6 +20439,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetExecutionThrottleResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.ExecutionThrottleResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetFeishuAuditConfigurationResV1": {
             "type": "object",
             "properties": {
@@ -18191,6 +20699,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetKnowledgeBaseResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.KnowledgeBaseDetail"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetKnowledgeBaseTagListRes": {
             "type": "object",
             "properties": {
@@ -18213,6 +20738,25 @@ var doc = `{
                 }
             }
         },
+        "v1.GetKnowledgeBaseVersionsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.KnowledgeBaseVersion"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetKnowledgeGraphResp": {
             "type": "object",
             "properties": {
@@ -18443,6 +20987,25 @@ var doc = `{
                 }
             }
         },
+        "v1.GetPluginStatusesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.PluginStatusResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetProjectRuleTemplateResV1": {
             "type": "object",
             "properties": {
@@ -18539,7 +21102,161 @@ var doc = `{
                 }
             }
         },
-        "v1.GetReportPushConfigsListResV1": {
+        "v1.GetReportPushConfigsListResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ReportPushConfigList"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetRiskAuditPlanResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RiskAuditPlan"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetRoleUserCountResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RoleUserCount"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetRuleCategoryStatisticResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/model.RuleCategoryStatistic"
+                        }
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetRuleKnowledgeResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.RuleKnowledgeResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetRuleTemplateResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.RuleTemplateDetailResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetRuleTemplateTipsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleTemplateTipResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetRuleTemplatesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleTemplateResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.GetRuleTypeByDBTypeResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18549,7 +21266,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.ReportPushConfigList"
+                        "$ref": "#/definitions/v1.RuleTypeV1"
                     }
                 },
                 "message": {
@@ -18558,7 +21275,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRiskAuditPlanResV1": {
+        "v1.GetRulesResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18568,7 +21285,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.RiskAuditPlan"
+                        "$ref": "#/definitions/v1.RuleResV1"
                     }
                 },
                 "message": {
@@ -18577,7 +21294,22 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRoleUserCountResV1": {
+        "v1.GetSQLAnalysisDataResItemV1": {
+            "type": "object",
+            "properties": {
+                "sql_explain": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SQLExplain"
+                },
+                "table_metas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TableMeta"
+                    }
+                }
+            }
+        },
+        "v1.GetSQLAuditRecordResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18585,10 +21317,8 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.RoleUserCount"
-                    }
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SQLAuditRecord"
                 },
                 "message": {
                     "type": "string",
@@ -18596,7 +21326,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRuleCategoryStatisticResV1": {
+        "v1.GetSQLAuditRecordTagTipsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18604,12 +21334,9 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "$ref": "#/definitions/model.RuleCategoryStatistic"
-                        }
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 },
                 "message": {
@@ -18618,7 +21345,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRuleKnowledgeResV1": {
+        "v1.GetSQLAuditRecordsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18626,16 +21353,21 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.RuleKnowledgeResV1"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SQLAuditRecord"
+                    }
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.GetRuleTemplateResV1": {
+        "v1.GetSQLChunkedExecutionResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18644,7 +21376,7 @@ var doc = `{
                 },
                 "data": {
                     "type": "object",
-                    "$ref": "#/definitions/v1.RuleTemplateDetailResV1"
+                    "$ref": "#/definitions/v1.SQLChunkedExecutionResV1"
                 },
                 "message": {
                     "type": "string",
@@ -18652,7 +21384,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRuleTemplateTipsResV1": {
+        "v1.GetSQLQueryMaskingRulesResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18662,7 +21394,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.RuleTemplateTipResV1"
+                        "$ref": "#/definitions/v1.SQLQueryMaskingRuleResV1"
                     }
                 },
                 "message": {
@@ -18671,7 +21403,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRuleTemplatesResV1": {
+        "v1.GetSQLQueryRecordsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18681,7 +21413,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.RuleTemplateResV1"
+                        "$ref": "#/definitions/v1.SQLQueryRecordResV1"
                     }
                 },
                 "message": {
@@ -18693,7 +21425,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRuleTypeByDBTypeResV1": {
+        "v1.GetSchemaBaselineResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18701,10 +21433,8 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.RuleTypeV1"
-                    }
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SchemaBaselineResV1"
                 },
                 "message": {
                     "type": "string",
@@ -18712,7 +21442,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRulesResV1": {
+        "v1.GetSchemaDriftFindingsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18722,31 +21452,19 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.RuleResV1"
+                        "$ref": "#/definitions/v1.SchemaDriftFindingResV1"
                     }
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
-                }
-            }
-        },
-        "v1.GetSQLAnalysisDataResItemV1": {
-            "type": "object",
-            "properties": {
-                "sql_explain": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.SQLExplain"
                 },
-                "table_metas": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.TableMeta"
-                    }
+                "total_nums": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.GetSQLAuditRecordResV1": {
+        "v1.GetScoringPolicyResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18755,7 +21473,7 @@ var doc = `{
                 },
                 "data": {
                     "type": "object",
-                    "$ref": "#/definitions/v1.SQLAuditRecord"
+                    "$ref": "#/definitions/v1.ScoringPolicyV1"
                 },
                 "message": {
                     "type": "string",
@@ -18763,7 +21481,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetSQLAuditRecordTagTipsResV1": {
+        "v1.GetSqlAverageExecutionTimeResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18773,7 +21491,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/v1.SqlAverageExecutionTime"
                     }
                 },
                 "message": {
@@ -18782,7 +21500,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetSQLAuditRecordsResV1": {
+        "v1.GetSqlDEVRecordListResp": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18792,7 +21510,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.SQLAuditRecord"
+                        "$ref": "#/definitions/v1.SqlDEVRecord"
                     }
                 },
                 "message": {
@@ -18804,7 +21522,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetSqlAverageExecutionTimeResV1": {
+        "v1.GetSqlExecutionFailPercentResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18814,7 +21532,7 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.SqlAverageExecutionTime"
+                        "$ref": "#/definitions/v1.SqlExecutionFailPercent"
                     }
                 },
                 "message": {
@@ -18823,7 +21541,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetSqlDEVRecordListResp": {
+        "v1.GetSqlFileOrderMethodResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18831,21 +21549,33 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.SqlDEVRecord"
-                    }
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SqlFileOrderMethodRes"
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
+                }
+            }
+        },
+        "v1.GetSqlManageExecutePlanDiffResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "total_nums": {
-                    "type": "integer"
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SqlManageExecutePlanDiffResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
                 }
             }
         },
-        "v1.GetSqlExecutionFailPercentResV1": {
+        "v1.GetSqlManageExecutePlansResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18853,10 +21583,8 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.SqlExecutionFailPercent"
-                    }
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SqlManageExecutePlanHistoryResV1"
                 },
                 "message": {
                     "type": "string",
@@ -18864,7 +21592,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetSqlFileOrderMethodResV1": {
+        "v1.GetSqlManageIndexAdviceResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -18873,7 +21601,7 @@ var doc = `{
                 },
                 "data": {
                     "type": "object",
-                    "$ref": "#/definitions/v1.SqlFileOrderMethodRes"
+                    "$ref": "#/definitions/v1.SqlManageIndexAdviceResV1"
                 },
                 "message": {
                     "type": "string",
@@ -18946,6 +21674,47 @@ var doc = `{
                 }
             }
         },
+        "v1.GetSqlManageTriageRecordsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SqlManageTriageRecordResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.GetSqlManageTriageRulesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SqlManageTriageRuleResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetSqlPerformanceInsightsRelatedSQLResp": {
             "type": "object",
             "properties": {
@@ -19107,6 +21876,47 @@ var doc = `{
                 }
             }
         },
+        "v1.GetTaskThrottleEventsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TaskThrottleEventResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetUserDelegationsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.UserDelegationResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.GetUserTipsResV1": {
             "type": "object",
             "properties": {
@@ -19228,6 +22038,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetWorkflowDutyPolicyResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.WorkflowDutyPolicyResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetWorkflowPassPercentResV1": {
             "type": "object",
             "properties": {
@@ -19317,7 +22144,48 @@ var doc = `{
                 }
             }
         },
-        "v1.GetWorkflowStatisticOfInstancesResV1": {
+        "v1.GetWorkflowSLABreachesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkflowSLABreachResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.GetWorkflowStatisticOfInstancesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkflowStatisticOfInstance"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetWorkflowStatusCountResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -19325,10 +22193,8 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.WorkflowStatisticOfInstance"
-                    }
+                    "type": "object",
+                    "$ref": "#/definitions/v1.WorkflowStatusCountV1"
                 },
                 "message": {
                     "type": "string",
@@ -19336,7 +22202,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetWorkflowStatusCountResV1": {
+        "v1.GetWorkflowStepSLAsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -19345,7 +22211,7 @@ var doc = `{
                 },
                 "data": {
                     "type": "object",
-                    "$ref": "#/definitions/v1.WorkflowStatusCountV1"
+                    "$ref": "#/definitions/v1.WorkflowStepSLAsResDataV1"
                 },
                 "message": {
                     "type": "string",
@@ -19461,6 +22327,25 @@ var doc = `{
                 }
             }
         },
+        "v1.GetWorkflowTemplateRouteRulesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkflowTemplateRouteRuleResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetWorkflowsResV1": {
             "type": "object",
             "properties": {
@@ -19985,14 +22870,105 @@ var doc = `{
                     "description": "内容",
                     "type": "string"
                 },
+                "db_type": {
+                    "description": "规则的数据库类型",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "描述",
+                    "type": "string"
+                },
+                "examples": {
+                    "description": "示例",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "知识库ID",
+                    "type": "integer"
+                },
+                "is_custom_rule": {
+                    "description": "是否为自定义规则的知识",
+                    "type": "boolean"
+                },
+                "is_team_knowledge": {
+                    "description": "是否为项目的团队知识",
+                    "type": "boolean"
+                },
+                "language": {
+                    "description": "语言，为空时适用于所有语言",
+                    "type": "string"
+                },
+                "rule_name": {
+                    "description": "规则名称",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "标签",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.Tag"
+                    }
+                },
+                "title": {
+                    "description": "标题",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "更新时间",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "版本",
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.KnowledgeBaseDetail": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "内容",
+                    "type": "string"
+                },
+                "db_type": {
+                    "description": "规则的数据库类型",
+                    "type": "string"
+                },
                 "description": {
                     "description": "描述",
                     "type": "string"
                 },
+                "editor_name": {
+                    "description": "最后编辑人，为空时由系统生成",
+                    "type": "string"
+                },
+                "examples": {
+                    "description": "示例",
+                    "type": "string"
+                },
                 "id": {
                     "description": "知识库ID",
                     "type": "integer"
                 },
+                "is_custom_rule": {
+                    "description": "是否为自定义规则的知识",
+                    "type": "boolean"
+                },
+                "is_team_knowledge": {
+                    "description": "是否为项目的团队知识",
+                    "type": "boolean"
+                },
+                "language": {
+                    "description": "语言，为空时适用于所有语言",
+                    "type": "string"
+                },
+                "related_rules": {
+                    "description": "与知识有相同标签的规则",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.KnowledgeRelatedRule"
+                    }
+                },
                 "rule_name": {
                     "description": "规则名称",
                     "type": "string"
@@ -20007,6 +22983,58 @@ var doc = `{
                 "title": {
                     "description": "标题",
                     "type": "string"
+                },
+                "updated_at": {
+                    "description": "更新时间",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "版本",
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.KnowledgeBaseVersion": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "editor_name": {
+                    "type": "string"
+                },
+                "examples": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.KnowledgeRelatedRule": {
+            "type": "object",
+            "properties": {
+                "db_type": {
+                    "type": "string"
+                },
+                "is_custom_rule": {
+                    "type": "boolean"
+                },
+                "rule_name": {
+                    "type": "string"
+                },
+                "shared_tags": {
+                    "description": "与知识相同的标签数",
+                    "type": "integer"
                 }
             }
         },
@@ -20479,6 +23507,77 @@ var doc = `{
                 }
             }
         },
+        "v1.PluginRestartRecordV1": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "exited",
+                        "unhealthy"
+                    ]
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.PluginStatusResV1": {
+            "type": "object",
+            "properties": {
+                "built_in": {
+                    "type": "boolean"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "in_flight_sessions": {
+                    "type": "integer"
+                },
+                "last_checked_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "plugin_name": {
+                    "type": "string"
+                },
+                "protocol_version": {
+                    "type": "integer"
+                },
+                "restart_count": {
+                    "type": "integer"
+                },
+                "restart_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.PluginRestartRecordV1"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "unhealthy",
+                        "crashed",
+                        "draining"
+                    ]
+                }
+            }
+        },
         "v1.PostSqlManageCodingResp": {
             "type": "object",
             "properties": {
@@ -20517,36 +23616,103 @@ var doc = `{
                     "type": "string",
                     "example": "+35%"
                 },
-                "project_name": {
-                    "description": "项目名称",
-                    "type": "string"
+                "project_name": {
+                    "description": "项目名称",
+                    "type": "string"
+                },
+                "time_saved": {
+                    "description": "节省工时",
+                    "type": "number",
+                    "example": 45.5
+                }
+            }
+        },
+        "v1.ProjectRuleTemplateResV1": {
+            "type": "object",
+            "properties": {
+                "db_type": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "rule_template_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ProjectScore": {
+            "type": "object",
+            "properties": {
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.QueryInstanceReqV1": {
+            "type": "object",
+            "properties": {
+                "schema_name": {
+                    "type": "string",
+                    "example": "db1"
+                },
+                "sql": {
+                    "type": "string",
+                    "example": "select * from t1 where id = 1"
+                }
+            }
+        },
+        "v1.QueryInstanceResDataV1": {
+            "type": "object",
+            "properties": {
+                "audit_result": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditResDataV1"
+                },
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "masked_columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
                 },
-                "time_saved": {
-                    "description": "节省工时",
-                    "type": "number",
-                    "example": 45.5
+                "truncated": {
+                    "description": "Truncated is true if the result has more rows than the max_pre_query_rows of the instance",
+                    "type": "boolean"
                 }
             }
         },
-        "v1.ProjectRuleTemplateResV1": {
+        "v1.QueryInstanceResV1": {
             "type": "object",
             "properties": {
-                "db_type": {
-                    "type": "string"
+                "code": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "desc": {
-                    "type": "string"
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.QueryInstanceResDataV1"
                 },
-                "rule_template_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "v1.ProjectScore": {
-            "type": "object",
-            "properties": {
-                "score": {
-                    "type": "integer"
+                "message": {
+                    "type": "string",
+                    "example": "ok"
                 }
             }
         },
@@ -20904,9 +24070,33 @@ var doc = `{
                 "knowledge_content": {
                     "type": "string"
                 },
+                "knowledge_examples": {
+                    "description": "示例",
+                    "type": "string"
+                },
+                "knowledge_id": {
+                    "description": "为0时规则还没有知识",
+                    "type": "integer"
+                },
+                "related_rules": {
+                    "description": "与知识有相同标签的规则",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.KnowledgeRelatedRule"
+                    }
+                },
                 "rule": {
                     "type": "object",
                     "$ref": "#/definitions/v1.RuleInfo"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -21233,6 +24423,91 @@ var doc = `{
                 }
             }
         },
+        "v1.SQLChunkedExecutionBatchV1": {
+            "type": "object",
+            "properties": {
+                "batch_no": {
+                    "type": "integer"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "end_pk": {
+                    "type": "string"
+                },
+                "executed_at": {
+                    "type": "string"
+                },
+                "row_affects": {
+                    "type": "integer"
+                },
+                "start_pk": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.SQLChunkedExecutionResV1": {
+            "type": "object",
+            "properties": {
+                "batch_count": {
+                    "type": "integer"
+                },
+                "batches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SQLChunkedExecutionBatchV1"
+                    }
+                },
+                "chunk_size": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "last_pk": {
+                    "type": "string"
+                },
+                "max_pk": {
+                    "type": "string"
+                },
+                "min_pk": {
+                    "type": "string"
+                },
+                "pk_column": {
+                    "type": "string"
+                },
+                "row_affects": {
+                    "type": "integer"
+                },
+                "sleep_millisecond": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "initialized",
+                        "running",
+                        "pausing",
+                        "paused",
+                        "finished",
+                        "failed",
+                        "not_eligible"
+                    ]
+                },
+                "table": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.SQLExplain": {
             "type": "object",
             "properties": {
@@ -21453,6 +24728,93 @@ var doc = `{
                 }
             }
         },
+        "v1.SQLQueryMaskingRuleResV1": {
+            "type": "object",
+            "properties": {
+                "column_pattern": {
+                    "type": "string",
+                    "example": "^(phone|mobile)$"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "masking_type": {
+                    "type": "string",
+                    "enum": [
+                        "phone",
+                        "id_card",
+                        "email",
+                        "regex"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "type": "string",
+                    "example": "***"
+                },
+                "value_pattern": {
+                    "type": "string",
+                    "example": "\\d{16}"
+                }
+            }
+        },
+        "v1.SQLQueryRecordResV1": {
+            "type": "object",
+            "properties": {
+                "audit_level": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance_id": {
+                    "type": "string"
+                },
+                "masked_columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "schema_name": {
+                    "type": "string"
+                },
+                "sql": {
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "rejected",
+                        "failed"
+                    ]
+                },
+                "truncated": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.SQLStatement": {
             "type": "object",
             "properties": {
@@ -21525,46 +24887,151 @@ var doc = `{
                         }
                     }
                 },
-                "is_in_transaction": {
-                    "description": "是否在事务中",
-                    "type": "boolean"
+                "is_in_transaction": {
+                    "description": "是否在事务中",
+                    "type": "boolean"
+                },
+                "sql": {
+                    "description": "SQL语句",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "时间点",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ScheduleTaskDefaultOption": {
+            "type": "object",
+            "properties": {
+                "default_selector": {
+                    "type": "string",
+                    "enum": [
+                        "wechat",
+                        "feishu"
+                    ]
+                }
+            }
+        },
+        "v1.ScheduledTaskDefaultOptionV1Rsp": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.ScheduleTaskDefaultOption"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.SchemaBaselineResV1": {
+            "type": "object",
+            "properties": {
+                "create_user_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "snapshot",
+                        "workflow"
+                    ]
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SchemaBaselineTableResV1"
+                    }
+                }
+            }
+        },
+        "v1.SchemaBaselineTableResV1": {
+            "type": "object",
+            "properties": {
+                "create_table_sql": {
+                    "type": "string"
+                },
+                "schema_name": {
+                    "type": "string"
                 },
-                "sql": {
-                    "description": "SQL语句",
+                "table_name": {
                     "type": "string"
                 },
-                "time": {
-                    "description": "时间点",
+                "workflow_id": {
                     "type": "string"
                 }
             }
         },
-        "v1.ScheduleTaskDefaultOption": {
+        "v1.SchemaDriftFindingResV1": {
             "type": "object",
             "properties": {
-                "default_selector": {
+                "baseline_definition": {
+                    "type": "string"
+                },
+                "current_definition": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "string"
+                },
+                "drift_type": {
                     "type": "string",
                     "enum": [
-                        "wechat",
-                        "feishu"
+                        "table_added",
+                        "table_removed",
+                        "column_added",
+                        "column_removed",
+                        "column_changed",
+                        "index_added",
+                        "index_removed",
+                        "index_changed"
                     ]
-                }
-            }
-        },
-        "v1.ScheduledTaskDefaultOptionV1Rsp": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 0
                 },
-                "data": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.ScheduleTaskDefaultOption"
+                "first_detected_at": {
+                    "type": "string"
                 },
-                "message": {
+                "id": {
+                    "type": "integer"
+                },
+                "last_detected_at": {
+                    "type": "string"
+                },
+                "object_name": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "schema_name": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string",
-                    "example": "ok"
+                    "enum": [
+                        "open",
+                        "resolved"
+                    ]
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "workflow_id": {
+                    "type": "string"
                 }
             }
         },
@@ -21597,6 +25064,46 @@ var doc = `{
                 }
             }
         },
+        "v1.ScoringPolicyV1": {
+            "type": "object",
+            "properties": {
+                "base_score": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "category_caps": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "hard_fail_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "level_penalties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "min_submit_score": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "rule_penalties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
         "v1.Source": {
             "type": "object",
             "properties": {
@@ -21853,39 +25360,328 @@ var doc = `{
                 },
                 "message": {
                     "type": "string",
-                    "example": "ok"
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.SqlManageCodingReq": {
+            "type": "object",
+            "properties": {
+                "coding_project_name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "LOW",
+                        "MEDIUM",
+                        "HIGH",
+                        "EMERGENCY"
+                    ]
+                },
+                "sql_manage_id_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "DEFECT",
+                        "MISSION",
+                        "REQUIREMENT",
+                        "EPIC",
+                        "SUB_TASK"
+                    ]
+                }
+            }
+        },
+        "v1.SqlManageExecutePlanChangeResV1": {
+            "type": "object",
+            "properties": {
+                "change_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "index_to_full_scan",
+                            "full_scan_to_index",
+                            "index_changed",
+                            "access_type_changed",
+                            "join_order_changed"
+                        ]
+                    }
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "from_plan_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_regression": {
+                    "type": "boolean"
+                },
+                "to_plan_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.SqlManageExecutePlanDiffResV1": {
+            "type": "object",
+            "properties": {
+                "change_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "index_to_full_scan",
+                            "full_scan_to_index",
+                            "index_changed",
+                            "access_type_changed",
+                            "join_order_changed"
+                        ]
+                    }
+                },
+                "from_plan": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SqlManageExecutePlanResV1"
+                },
+                "is_regression": {
+                    "type": "boolean"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ExecutePlanDiffRowResV1"
+                    }
+                },
+                "to_plan": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SqlManageExecutePlanResV1"
+                }
+            }
+        },
+        "v1.SqlManageExecutePlanHistoryResV1": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SqlManageExecutePlanChangeResV1"
+                    }
+                },
+                "plans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SqlManageExecutePlanResV1"
+                    }
+                }
+            }
+        },
+        "v1.SqlManageExecutePlanResV1": {
+            "type": "object",
+            "properties": {
+                "first_seen_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "plan_hash": {
+                    "type": "string"
+                },
+                "plan_summary": {
+                    "type": "string",
+                    "example": "t1(ALL) -\u003e t2(ref, idx_a)"
+                },
+                "seen_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.SqlManageIndexAdviceResV1": {
+            "type": "object",
+            "properties": {
+                "indexes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkloadIndexResV1"
+                    }
+                },
+                "max_index_count": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkloadIndexResV1"
+                    }
+                }
+            }
+        },
+        "v1.SqlManageMetricConditionV1": {
+            "type": "object",
+            "properties": {
+                "metric": {
+                    "description": "Metric is a numeric metric collected by the audit plan, e.g. query_time_avg",
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string",
+                    "enum": [
+                        "\u003e",
+                        "\u003e=",
+                        "\u003c",
+                        "\u003c=",
+                        "="
+                    ]
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "v1.SqlManageTriageRecordResV1": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string",
+                    "enum": [
+                        "status",
+                        "assignees",
+                        "priority",
+                        "remark"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_value": {
+                    "type": "string"
+                },
+                "old_value": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "rule_name": {
+                    "type": "string"
+                },
+                "rule_type": {
+                    "type": "string",
+                    "enum": [
+                        "match",
+                        "auto_close",
+                        "auto_reopen"
+                    ]
+                },
+                "sql_manage_id": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.SqlManageCodingReq": {
+        "v1.SqlManageTriageRuleResV1": {
             "type": "object",
             "properties": {
-                "coding_project_name": {
+                "assignees": {
+                    "description": "Assignees are the user ids the SQL is assigned to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "comment": {
+                    "description": "Comment is appended to the remark of the SQL",
                     "type": "string"
                 },
-                "priority": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inactive_days": {
+                    "description": "InactiveDays is the days the SQL has not been seen before it is closed by the auto_close rule",
+                    "type": "integer"
+                },
+                "instance_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "metric_conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SqlManageMetricConditionV1"
+                    }
+                },
+                "min_audit_level": {
                     "type": "string",
                     "enum": [
-                        "LOW",
-                        "MEDIUM",
-                        "HIGH",
-                        "EMERGENCY"
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
                     ]
                 },
-                "sql_manage_id_list": {
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "schema_names": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
+                    }
+                },
+                "sources": {
+                    "description": "Sources are the audit plan types collecting the SQL",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 },
+                "sql_priority": {
+                    "description": "SQLPriority is not changed if it is null",
+                    "type": "string",
+                    "enum": [
+                        "",
+                        "high"
+                    ]
+                },
+                "status": {
+                    "description": "Status is only set by the match rule, the auto_close rule solves the SQL and the auto_reopen rule reopens it",
+                    "type": "string",
+                    "enum": [
+                        "unhandled",
+                        "solved",
+                        "ignored",
+                        "manual_audited"
+                    ]
+                },
                 "type": {
                     "type": "string",
                     "enum": [
-                        "DEFECT",
-                        "MISSION",
-                        "REQUIREMENT",
-                        "EPIC",
-                        "SUB_TASK"
+                        "match",
+                        "auto_close",
+                        "auto_reopen"
                     ]
                 }
             }
@@ -22201,6 +25997,37 @@ var doc = `{
                 }
             }
         },
+        "v1.TaskThrottleEventResV1": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string",
+                    "enum": [
+                        "throttled",
+                        "resumed",
+                        "aborted"
+                    ]
+                },
+                "execute_sql_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "replication_lag_sec": {
+                    "type": "integer"
+                },
+                "threads_running": {
+                    "type": "integer"
+                },
+                "waited_second": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.TestAuditPlanNotifyConfigResDataV1": {
             "type": "object",
             "properties": {
@@ -22789,6 +26616,40 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateExecutionThrottleReqV1": {
+            "type": "object",
+            "properties": {
+                "check_interval_second": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "discover_replicas": {
+                    "type": "boolean"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "max_replication_lag_sec": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "max_threads_running": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "replica_dsns": {
+                    "description": "ReplicaDSNs replaces the replicas if it is not null, e.g. [\"repl:password@tcp(10.10.10.10:3306)/\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout_second": {
+                    "type": "integer",
+                    "example": 1800
+                }
+            }
+        },
         "v1.UpdateFeishuConfigurationReqV1": {
             "type": "object",
             "required": [
@@ -22907,6 +26768,16 @@ var doc = `{
             "properties": {
                 "knowledge_content": {
                     "type": "string"
+                },
+                "knowledge_examples": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "为空时不修改标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -22916,47 +26787,224 @@ var doc = `{
                 "desc": {
                     "type": "string"
                 },
-                "rule_list": {
+                "rule_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleReqV1"
+                    }
+                }
+            }
+        },
+        "v1.UpdateSQLAuditRecordReqV1": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.UpdateSQLChunkedExecutionReqV1": {
+            "type": "object",
+            "properties": {
+                "chunk_size": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "sleep_millisecond": {
+                    "type": "integer",
+                    "example": 500
+                }
+            }
+        },
+        "v1.UpdateSQLQueryMaskingRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "column_pattern": {
+                    "type": "string",
+                    "example": "^(phone|mobile)$"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "masking_type": {
+                    "type": "string",
+                    "enum": [
+                        "phone",
+                        "id_card",
+                        "email",
+                        "regex"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "type": "string",
+                    "example": "***"
+                },
+                "value_pattern": {
+                    "type": "string",
+                    "example": "\\d{16}"
+                }
+            }
+        },
+        "v1.UpdateScoringPolicyReqV1": {
+            "type": "object",
+            "properties": {
+                "base_score": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "category_caps": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "hard_fail_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "level_penalties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "min_submit_score": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "rule_penalties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "v1.UpdateSqlBackupStrategyReq": {
+            "type": "object",
+            "properties": {
+                "strategy": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "manual",
+                        "reverse_sql",
+                        "original_row"
+                    ]
+                }
+            }
+        },
+        "v1.UpdateSqlFileOrderV1Req": {
+            "type": "object",
+            "properties": {
+                "files_to_sort": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.FileToSort"
+                    }
+                }
+            }
+        },
+        "v1.UpdateSqlManageTriageRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "assignees": {
+                    "description": "Assignees are the user ids the SQL is assigned to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "comment": {
+                    "description": "Comment is appended to the remark of the SQL",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "inactive_days": {
+                    "description": "InactiveDays is the days the SQL has not been seen before it is closed by the auto_close rule",
+                    "type": "integer"
+                },
+                "instance_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "metric_conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SqlManageMetricConditionV1"
+                    }
+                },
+                "min_audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "schema_names": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.RuleReqV1"
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "v1.UpdateSQLAuditRecordReqV1": {
-            "type": "object",
-            "properties": {
-                "tags": {
+                },
+                "sources": {
+                    "description": "Sources are the audit plan types collecting the SQL",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
-                }
-            }
-        },
-        "v1.UpdateSqlBackupStrategyReq": {
-            "type": "object",
-            "properties": {
-                "strategy": {
+                },
+                "sql_priority": {
+                    "description": "SQLPriority is not changed if it is null",
                     "type": "string",
                     "enum": [
-                        "none",
-                        "manual",
-                        "reverse_sql",
-                        "original_row"
+                        "",
+                        "high"
+                    ]
+                },
+                "status": {
+                    "description": "Status is only set by the match rule, the auto_close rule solves the SQL and the auto_reopen rule reopens it",
+                    "type": "string",
+                    "enum": [
+                        "unhandled",
+                        "solved",
+                        "ignored",
+                        "manual_audited"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "match",
+                        "auto_close",
+                        "auto_reopen"
                     ]
-                }
-            }
-        },
-        "v1.UpdateSqlFileOrderV1Req": {
-            "type": "object",
-            "properties": {
-                "files_to_sort": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.FileToSort"
-                    }
                 }
             }
         },
@@ -23021,6 +27069,30 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateTeamKnowledgeReqV1": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "examples": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "为空时不修改标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateWechatConfigurationReqV1": {
             "type": "object",
             "required": [
@@ -23038,6 +27110,36 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateWorkflowDutyPolicyReqV1": {
+            "type": "object",
+            "properties": {
+                "forbid_approver_execute": {
+                    "description": "ForbidApproverExecute forbids the approvers of a workflow to execute it",
+                    "type": "boolean"
+                },
+                "forbid_creator_approve": {
+                    "description": "ForbidCreatorApprove forbids the creator of a workflow to approve it",
+                    "type": "boolean"
+                },
+                "min_distinct_participants": {
+                    "description": "MinDistinctParticipants is the least number of the distinct creator, approvers and executor, 0 means disabled",
+                    "type": "integer"
+                },
+                "min_participants_audit_level": {
+                    "description": "MinParticipantsAuditLevel limits min_distinct_participants to the workflows whose audit level is not lower than it, empty means all workflows",
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "workflow_template_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.UpdateWorkflowReqV1": {
             "type": "object",
             "properties": {
@@ -23057,6 +27159,20 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateWorkflowStepSLAsReqV1": {
+            "type": "object",
+            "properties": {
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkflowStepSLAV1"
+                    }
+                },
+                "workflow_template_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.UpdateWorkflowTemplateByIdReqV1": {
             "type": "object",
             "properties": {
@@ -23109,6 +27225,119 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateWorkflowTemplateRouteRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "any_sql_types": {
+                    "description": "AnySQLTypes matches if the workflow has any statement of the types",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "dml",
+                            "ddl",
+                            "dql",
+                            "dcl"
+                        ]
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "environment_tags": {
+                    "description": "EnvironmentTags matches if the environment tags of all the instances of the workflow are in it",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_affected_rows": {
+                    "type": "integer"
+                },
+                "max_audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "min_affected_rows": {
+                    "description": "MinAffectedRows and MaxAffectedRows limit the total estimated affected rows of the DML statements of the workflow",
+                    "type": "integer"
+                },
+                "min_audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "only_sql_types": {
+                    "description": "OnlySQLTypes matches if the workflow only has the statements of the types",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "dml",
+                            "ddl",
+                            "dql",
+                            "dcl"
+                        ]
+                    }
+                },
+                "ops_type_uids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "workflow_template_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.UserDelegationResV1": {
+            "type": "object",
+            "properties": {
+                "delegate_name": {
+                    "type": "string"
+                },
+                "delegator_name": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance_name": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.UserTipResV1": {
             "type": "object",
             "properties": {
@@ -23371,6 +27600,39 @@ var doc = `{
                 }
             }
         },
+        "v1.WorkflowDutyPolicyResV1": {
+            "type": "object",
+            "properties": {
+                "forbid_approver_execute": {
+                    "description": "ForbidApproverExecute forbids the approvers of a workflow to execute it",
+                    "type": "boolean"
+                },
+                "forbid_creator_approve": {
+                    "description": "ForbidCreatorApprove forbids the creator of a workflow to approve it",
+                    "type": "boolean"
+                },
+                "min_distinct_participants": {
+                    "description": "MinDistinctParticipants is the least number of the distinct creator, approvers and executor, 0 means disabled",
+                    "type": "integer"
+                },
+                "min_participants_audit_level": {
+                    "description": "MinParticipantsAuditLevel limits min_distinct_participants to the workflows whose audit level is not lower than it, empty means all workflows",
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "workflow_template_id": {
+                    "type": "integer"
+                },
+                "workflow_template_name": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.WorkflowPassPercentV1": {
             "type": "object",
             "properties": {
@@ -23493,13 +27755,62 @@ var doc = `{
                     "type": "object",
                     "$ref": "#/definitions/v1.WorkflowRecordResV1"
                 },
-                "record_history_list": {
+                "record_history_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkflowRecordResV1"
+                    }
+                },
+                "workflow_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.WorkflowSLABreachResV1": {
+            "type": "object",
+            "properties": {
+                "breached_at": {
+                    "type": "string"
+                },
+                "escalate_mode": {
+                    "type": "string",
+                    "enum": [
+                        "add",
+                        "reassign"
+                    ]
+                },
+                "escalated_to": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "operate_at": {
+                    "description": "OperateAt and OperationUser are empty if the step is still pending",
+                    "type": "string"
+                },
+                "operation_user": {
+                    "type": "string"
+                },
+                "original_assignees": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.WorkflowRecordResV1"
+                        "type": "string"
                     }
                 },
-                "workflow_name": {
+                "remind_count": {
+                    "type": "integer"
+                },
+                "step_number": {
+                    "type": "integer"
+                },
+                "step_start_at": {
+                    "type": "string"
+                },
+                "workflow_id": {
+                    "type": "string"
+                },
+                "workflow_subject": {
                     "type": "string"
                 }
             }
@@ -23618,6 +27929,97 @@ var doc = `{
                 }
             }
         },
+        "v1.WorkflowStepSLAResV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "escalate_after_minute": {
+                    "description": "EscalateAfterMinute escalates the step once it is pending longer than the duration, 0 means disabled",
+                    "type": "integer"
+                },
+                "escalate_member_group_uid": {
+                    "type": "string"
+                },
+                "escalate_mode": {
+                    "type": "string",
+                    "enum": [
+                        "add",
+                        "reassign"
+                    ]
+                },
+                "escalate_user_id_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "remind_interval_minute": {
+                    "description": "RemindIntervalMinute reminds the assignees every interval while the step is pending, 0 means disabled",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "sql_review",
+                        "sql_execute"
+                    ]
+                }
+            }
+        },
+        "v1.WorkflowStepSLAV1": {
+            "type": "object",
+            "properties": {
+                "escalate_after_minute": {
+                    "description": "EscalateAfterMinute escalates the step once it is pending longer than the duration, 0 means disabled",
+                    "type": "integer"
+                },
+                "escalate_member_group_uid": {
+                    "type": "string"
+                },
+                "escalate_mode": {
+                    "type": "string",
+                    "enum": [
+                        "add",
+                        "reassign"
+                    ]
+                },
+                "escalate_user_id_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "remind_interval_minute": {
+                    "description": "RemindIntervalMinute reminds the assignees every interval while the step is pending, 0 means disabled",
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.WorkflowStepSLAsResDataV1": {
+            "type": "object",
+            "properties": {
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkflowStepSLAResV1"
+                    }
+                },
+                "workflow_template_id": {
+                    "type": "integer"
+                },
+                "workflow_template_name": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.WorkflowTaskItem": {
             "type": "object",
             "properties": {
@@ -23668,6 +28070,220 @@ var doc = `{
                 }
             }
         },
+        "v1.WorkflowTemplateRouteResV1": {
+            "type": "object",
+            "properties": {
+                "affected_rows": {
+                    "type": "integer"
+                },
+                "affected_rows_err": {
+                    "type": "string"
+                },
+                "audit_level": {
+                    "type": "string"
+                },
+                "environment_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "evaluations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkflowTemplateRouteRuleEvaluationV1"
+                    }
+                },
+                "matched_rule_name": {
+                    "description": "MatchedRuleName is empty if the default workflow template is selected",
+                    "type": "string"
+                },
+                "sql_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "workflow_template_id": {
+                    "type": "integer"
+                },
+                "workflow_template_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.WorkflowTemplateRouteRuleEvaluationV1": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "type": "boolean"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "rule_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.WorkflowTemplateRouteRuleResV1": {
+            "type": "object",
+            "properties": {
+                "any_sql_types": {
+                    "description": "AnySQLTypes matches if the workflow has any statement of the types",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "dml",
+                            "ddl",
+                            "dql",
+                            "dcl"
+                        ]
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "environment_tags": {
+                    "description": "EnvironmentTags matches if the environment tags of all the instances of the workflow are in it",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_affected_rows": {
+                    "type": "integer"
+                },
+                "max_audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "min_affected_rows": {
+                    "description": "MinAffectedRows and MaxAffectedRows limit the total estimated affected rows of the DML statements of the workflow",
+                    "type": "integer"
+                },
+                "min_audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "only_sql_types": {
+                    "description": "OnlySQLTypes matches if the workflow only has the statements of the types",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "dml",
+                            "ddl",
+                            "dql",
+                            "dcl"
+                        ]
+                    }
+                },
+                "ops_type_uids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "workflow_template_id": {
+                    "type": "integer"
+                },
+                "workflow_template_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.WorkloadIndexQueryResV1": {
+            "type": "object",
+            "properties": {
+                "execution_count": {
+                    "type": "integer"
+                },
+                "query_time_avg": {
+                    "type": "number"
+                },
+                "query_time_known": {
+                    "description": "QueryTimeKnown is false if the query time is assumed to be the median of the workload",
+                    "type": "boolean"
+                },
+                "sql_fingerprint": {
+                    "type": "string"
+                },
+                "sql_manage_id": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "v1.WorkloadIndexResV1": {
+            "type": "object",
+            "properties": {
+                "benefit": {
+                    "type": "number"
+                },
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "create_sql": {
+                    "type": "string"
+                },
+                "index_name": {
+                    "type": "string"
+                },
+                "queries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.WorkloadIndexQueryResV1"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "storage_bytes": {
+                    "type": "integer"
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "write_amplification": {
+                    "type": "number"
+                },
+                "write_weight": {
+                    "type": "number"
+                }
+            }
+        },
         "v1.createPipelineResData": {
             "type": "object",
             "properties": {
@@ -24226,6 +28842,10 @@ var doc = `{
                     "type": "object",
                     "$ref": "#/definitions/model.I18nAuditResultInfo"
                 },
+                "knowledge_id": {
+                    "description": "规则的知识ID，用于跳转到规则知识",
+                    "type": "integer"
+                },
                 "level": {
                     "type": "string",
                     "example": "warn"
@@ -25758,6 +30378,9 @@ var doc = `{
                 "number": {
                     "type": "integer"
                 },
+                "on_behalf_of_user_name": {
+                    "type": "string"
+                },
                 "operation_time": {
                     "type": "string"
                 },
@@ -3,15 +3,19 @@ package common
 import (
 	"math"
 
+	"github.com/actiontech/sqle/sqle/tracing"
 	"google.golang.org/grpc"
 )
 
 // custom GRPC client options
-var GRPCDialOptions = []grpc.DialOption{}
+var GRPCDialOptions = []grpc.DialOption{
+	grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor()),
+}
 
 // custom GRPC server options
 var GRPCServerOptions = []grpc.ServerOption{
 	grpc.MaxRecvMsgSize(math.MaxInt32),
+	grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor()),
 }
 
 func NewGRPCServer(opts []grpc.ServerOption) *grpc.Server {
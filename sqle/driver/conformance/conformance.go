@@ -0,0 +1,676 @@
+// Package conformance checks whether a driver plugin conforms to the v2 plugin protocol. It launches the
+// plugin binary through go-plugin like sqled does, calls the RPCs of driver_v2.proto and checks the
+// invariants sqled relies on, so a third-party plugin can be verified before it is deployed.
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"github.com/actiontech/sqle/sqle/driver/common"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	protoV2 "github.com/actiontech/sqle/sqle/driver/v2/proto"
+
+	"github.com/hashicorp/go-hclog"
+	goPlugin "github.com/hashicorp/go-plugin"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultTimeout  = 30 * time.Second
+	defaultQuerySQL = "SELECT 1"
+)
+
+// DefaultSQLs are the sample SQLs used if Config.SQLs is empty, they are written in the common SQL
+// dialect which most databases can parse.
+var DefaultSQLs = []string{
+	"CREATE TABLE conformance_t1 (id INT PRIMARY KEY, name VARCHAR(32))",
+	"INSERT INTO conformance_t1 (id, name) VALUES (1, 'a')",
+	"UPDATE conformance_t1 SET name = 'b' WHERE id = 1",
+	"SELECT id, name FROM conformance_t1 WHERE id = 1",
+	"DELETE FROM conformance_t1 WHERE id = 1",
+}
+
+type Config struct {
+	PluginPath string
+	PluginArgs []string
+	// DSN is the database which the online RPCs connect to, the online RPCs are skipped if it is nil.
+	DSN *driverV2.DSN
+	// SQLs are the sample SQLs in the dialect of the plugin, each one is a single SQL.
+	SQLs []string
+	// QuerySQL is a read-only query for the Query RPC.
+	QuerySQL string
+	// Table is used by GetTableMeta, GetDatabaseObjectDDL and GetDatabaseDiffModifySQL, they are skipped if it is nil.
+	Table *driverV2.Table
+	// Timeout is the timeout of each RPC.
+	Timeout time.Duration
+}
+
+func (c *Config) sqls() []string {
+	if len(c.SQLs) > 0 {
+		return c.SQLs
+	}
+	return DefaultSQLs
+}
+
+// sqlOfType returns the first sample SQL starting with one of the keywords, or the first sample SQL.
+func (c *Config) sqlOfType(keywords ...string) string {
+	sqls := c.sqls()
+	for _, sql := range sqls {
+		upper := strings.ToUpper(strings.TrimSpace(sql))
+		for _, keyword := range keywords {
+			if strings.HasPrefix(upper, keyword) {
+				return sql
+			}
+		}
+	}
+	return sqls[0]
+}
+
+// Run launches the plugin and checks it, the error is returned if the plugin can not be launched.
+func Run(cfg *Config) (*Report, error) {
+	client := goPlugin.NewClient(&goPlugin.ClientConfig{
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   "plugin",
+			Output: os.Stderr,
+			Level:  hclog.Warn,
+		}),
+		HandshakeConfig: driverV2.HandshakeConfig,
+		VersionedPlugins: map[int]goPlugin.PluginSet{
+			driverV2.ProtocolVersion: driverV2.PluginSet,
+		},
+		Cmd:              exec.Command(cfg.PluginPath, cfg.PluginArgs...),
+		AllowedProtocols: []goPlugin.Protocol{goPlugin.ProtocolGRPC},
+		GRPCDialOptions:  common.GRPCDialOptions,
+		StartTimeout:     time.Minute,
+	})
+	defer client.Kill()
+
+	cp, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("launch plugin %s failed, only the plugins of protocol version %d are supported: %v",
+			cfg.PluginPath, driverV2.ProtocolVersion, err)
+	}
+	rawI, err := cp.Dispense(driverV2.PluginSetName)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := rawI.(protoV2.DriverClient)
+	if !ok {
+		return nil, fmt.Errorf("client is not implement protoV2.DriverClient")
+	}
+	report := Check(context.Background(), c, cfg)
+	report.PluginPath = cfg.PluginPath
+	return report, nil
+}
+
+// Check calls the RPCs of the plugin by the client and returns the report.
+func Check(ctx context.Context, client protoV2.DriverClient, cfg *Config) *Report {
+	ck := &checker{
+		ctx:    ctx,
+		client: client,
+		cfg:    cfg,
+		report: &Report{StartedAt: time.Now(), Online: cfg.DSN != nil},
+	}
+	ck.run()
+	ck.report.Duration = time.Since(ck.report.StartedAt)
+	return ck.report
+}
+
+type checker struct {
+	ctx    context.Context
+	client protoV2.DriverClient
+	cfg    *Config
+	report *Report
+
+	metas     *protoV2.MetasResponse
+	isI18n    bool
+	ruleNames map[string]struct{}
+}
+
+// call calls the RPC with the timeout and returns the duration of the call.
+func (c *checker) call(fn func(ctx context.Context) error) (time.Duration, error) {
+	timeout := c.cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	defer cancel()
+	begin := time.Now()
+	err := fn(ctx)
+	return time.Since(begin), err
+}
+
+func (c *checker) run() {
+	if !c.checkMetas() {
+		return
+	}
+	session := c.init("init.offline", nil)
+	if session == nil {
+		return
+	}
+	defer c.close("close.offline", session)
+	c.checkParse(session)
+	c.checkAudit(session)
+
+	if c.cfg.DSN != nil {
+		online := c.init("init.online", c.cfg.DSN)
+		if online == nil {
+			return
+		}
+		defer c.close("close.online", online)
+		c.checkOnline(online)
+		c.checkOptionalModules(online)
+		return
+	}
+	c.checkOptionalModules(session)
+}
+
+func (c *checker) checkMetas() bool {
+	d, err := c.call(func(ctx context.Context) (err error) {
+		c.metas, err = c.client.Metas(ctx, &protoV2.Empty{})
+		return err
+	})
+	if err != nil {
+		c.report.fail("metas", "Metas", "call failed: %v", err).Duration = d
+		return false
+	}
+	c.report.pass("metas", "Metas").Duration = d
+
+	if c.metas.PluginName == "" {
+		c.report.fail("metas.plugin_name", "Metas", "plugin name is empty")
+	} else {
+		c.report.PluginName = c.metas.PluginName
+		c.report.pass("metas.plugin_name", "Metas")
+	}
+
+	for _, m := range c.metas.EnabledOptionalModule {
+		if driverV2.OptionalModule(m) == driverV2.OptionalModuleI18n {
+			c.isI18n = true
+		}
+	}
+	c.checkOptionalModuleClaims()
+	c.checkRules()
+
+	if _, err := driverV2.ConvertProtoParamToParam(c.metas.DatabaseAdditionalParams); err != nil {
+		c.report.fail("metas.additional_params", "Metas", "%v", err)
+	} else {
+		c.report.pass("metas.additional_params", "Metas")
+	}
+	return true
+}
+
+func (c *checker) checkOptionalModuleClaims() {
+	seen := map[protoV2.OptionalModule]struct{}{}
+	problems := []string{}
+	for _, m := range c.metas.EnabledOptionalModule {
+		if _, ok := seen[m]; ok {
+			problems = append(problems, fmt.Sprintf("%s is duplicated", driverV2.OptionalModule(m)))
+		}
+		seen[m] = struct{}{}
+		if driverV2.OptionalModule(m).String() == "Unknown" {
+			problems = append(problems, fmt.Sprintf("%d is not a known module", m))
+		}
+	}
+	if len(problems) > 0 {
+		c.report.warn("metas.optional_modules", "Metas", "%s", strings.Join(problems, "; "))
+		return
+	}
+	c.report.pass("metas.optional_modules", "Metas")
+}
+
+func (c *checker) checkRules() {
+	c.ruleNames = map[string]struct{}{}
+	duplicated := []string{}
+	invalid := []string{}
+	languages := map[string]struct{}{}
+	for _, rule := range c.metas.Rules {
+		if _, ok := c.ruleNames[rule.Name]; ok {
+			duplicated = append(duplicated, rule.Name)
+		}
+		c.ruleNames[rule.Name] = struct{}{}
+		if rule.Name == "" {
+			invalid = append(invalid, "a rule has no name")
+		}
+		if !isValidRuleLevel(rule.Level) || rule.Level == "" {
+			invalid = append(invalid, fmt.Sprintf("rule %s has invalid level %q", rule.Name, rule.Level))
+		}
+		if _, err := driverV2.ConvertI18nRuleFromProtoToDriver(rule, c.metas.PluginName, c.isI18n); err != nil {
+			invalid = append(invalid, fmt.Sprintf("rule %s: %v", rule.Name, err))
+		}
+		for lang := range rule.I18NRuleInfo {
+			languages[lang] = struct{}{}
+		}
+	}
+	if len(c.metas.Rules) == 0 {
+		c.report.warn("metas.rules", "Metas", "the plugin has no rules")
+	}
+	if len(duplicated) > 0 {
+		c.report.fail("metas.rules.unique_name", "Metas", "duplicated rule names: %s", strings.Join(duplicated, ", "))
+	} else {
+		c.report.pass("metas.rules.unique_name", "Metas")
+	}
+	if len(invalid) > 0 {
+		c.report.fail("metas.rules.valid", "Metas", "%s", strings.Join(invalid, "; "))
+	} else {
+		c.report.pass("metas.rules.valid", "Metas")
+	}
+
+	if !c.isI18n {
+		c.report.skip("metas.rules.i18n", "Metas", "the I18n module is not enabled")
+		return
+	}
+	// every rule should be described in every language supported by the plugin, and the default language is required by sqled
+	languages[i18nPkg.DefaultLang.String()] = struct{}{}
+	sortedLanguages := make([]string, 0, len(languages))
+	for lang := range languages {
+		sortedLanguages = append(sortedLanguages, lang)
+	}
+	sort.Strings(sortedLanguages)
+	problems := []string{}
+	for _, lang := range sortedLanguages {
+		if _, err := language.Parse(lang); err != nil {
+			problems = append(problems, fmt.Sprintf("language %s is invalid", lang))
+		}
+	}
+	for _, rule := range c.metas.Rules {
+		missing := []string{}
+		for _, lang := range sortedLanguages {
+			info, ok := rule.I18NRuleInfo[lang]
+			if !ok || info == nil || info.Desc == "" {
+				missing = append(missing, lang)
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("rule %s has no I18nRuleInfo of %s", rule.Name, strings.Join(missing, ",")))
+		}
+	}
+	if len(problems) > 0 {
+		c.report.fail("metas.rules.i18n", "Metas", "%s", strings.Join(problems, "; "))
+		return
+	}
+	c.report.pass("metas.rules.i18n", "Metas")
+}
+
+func (c *checker) init(name string, dsn *driverV2.DSN) *protoV2.Session {
+	req := &protoV2.InitRequest{Rules: c.metas.Rules}
+	if dsn != nil {
+		req.Dsn = &protoV2.DSN{
+			Host:             dsn.Host,
+			Port:             dsn.Port,
+			User:             dsn.User,
+			Password:         dsn.Password,
+			Database:         dsn.DatabaseName,
+			AdditionalParams: driverV2.ConvertParamToProtoParam(dsn.AdditionalParams),
+		}
+	}
+	var resp *protoV2.InitResponse
+	d, err := c.call(func(ctx context.Context) (err error) {
+		resp, err = c.client.Init(ctx, req)
+		return err
+	})
+	if err != nil {
+		c.report.fail(name, "Init", "call failed: %v", err).Duration = d
+		return nil
+	}
+	if resp.Session == nil || resp.Session.Id == "" {
+		c.report.fail(name, "Init", "session id is empty").Duration = d
+		return nil
+	}
+	c.report.pass(name, "Init").Duration = d
+	return resp.Session
+}
+
+func (c *checker) close(name string, session *protoV2.Session) {
+	d, err := c.call(func(ctx context.Context) error {
+		_, err := c.client.Close(ctx, &protoV2.CloseRequest{Session: session})
+		return err
+	})
+	if err != nil {
+		c.report.fail(name, "Close", "call failed: %v", err).Duration = d
+		return
+	}
+	c.report.pass(name, "Close").Duration = d
+}
+
+func (c *checker) parse(session *protoV2.Session, sql string) ([]*protoV2.Node, time.Duration, error) {
+	var resp *protoV2.ParseResponse
+	d, err := c.call(func(ctx context.Context) (err error) {
+		resp, err = c.client.Parse(ctx, &protoV2.ParseRequest{Session: session, Sql: &protoV2.ParsedSQL{Query: sql}})
+		return err
+	})
+	if err != nil {
+		return nil, d, err
+	}
+	return resp.Nodes, d, nil
+}
+
+func (c *checker) checkParse(session *protoV2.Session) {
+	sqls := c.cfg.sqls()
+	for i, sql := range sqls {
+		name := fmt.Sprintf("parse.sql[%d]", i)
+		nodes, d, err := c.parse(session, sql)
+		switch {
+		case err != nil:
+			c.report.fail(name, "Parse", "parse %q failed: %v", sql, err).Duration = d
+		case len(nodes) != 1:
+			c.report.fail(name, "Parse", "a single SQL is parsed to %d nodes", len(nodes)).Duration = d
+		case strings.TrimSpace(nodes[0].Text) == "":
+			c.report.fail(name, "Parse", "the text of the node is empty").Duration = d
+		case nodes[0].Fingerprint == "":
+			c.report.warn(name, "Parse", "the fingerprint of the node is empty, the SQL can not be matched by fingerprint").Duration = d
+		default:
+			c.report.pass(name, "Parse").Duration = d
+		}
+	}
+
+	// the SQL file of a workflow is parsed as a whole, the nodes should keep the order and the lines of the SQLs
+	batch := strings.Join(sqls, ";\n") + ";"
+	nodes, d, err := c.parse(session, batch)
+	if err != nil {
+		c.report.fail("parse.batch", "Parse", "parse the sample SQLs as a batch failed: %v", err).Duration = d
+		return
+	}
+	if len(nodes) != len(sqls) {
+		c.report.fail("parse.batch", "Parse", "%d SQLs are parsed to %d nodes", len(sqls), len(nodes)).Duration = d
+	} else {
+		c.report.pass("parse.batch", "Parse").Duration = d
+	}
+	c.checkStartLine(nodes)
+}
+
+func (c *checker) checkStartLine(nodes []*protoV2.Node) {
+	if len(nodes) < 2 {
+		c.report.skip("parse.start_line", "Parse", "less than 2 nodes")
+		return
+	}
+	allZero := true
+	for i, node := range nodes {
+		if node.StartLine != 0 {
+			allZero = false
+		}
+		if i > 0 && node.StartLine < nodes[i-1].StartLine {
+			c.report.fail("parse.start_line", "Parse", "StartLine is not monotonic, node %d starts at line %d but node %d starts at line %d",
+				i-1, nodes[i-1].StartLine, i, node.StartLine)
+			return
+		}
+	}
+	if allZero {
+		c.report.warn("parse.start_line", "Parse", "StartLine of the nodes is not set, the lines of the SQLs in a SQL file are unknown")
+		return
+	}
+	c.report.pass("parse.start_line", "Parse")
+}
+
+func (c *checker) checkAudit(session *protoV2.Session) {
+	sqls := c.cfg.sqls()
+	auditSqls := make([]*protoV2.AuditSQL, 0, len(sqls))
+	for _, sql := range sqls {
+		auditSqls = append(auditSqls, &protoV2.AuditSQL{Query: sql})
+	}
+	var resp *protoV2.AuditResponse
+	d, err := c.call(func(ctx context.Context) (err error) {
+		resp, err = c.client.Audit(ctx, &protoV2.AuditRequest{Session: session, Sqls: auditSqls})
+		return err
+	})
+	if err != nil {
+		c.report.fail("audit", "Audit", "call failed: %v", err).Duration = d
+		return
+	}
+	if len(resp.AuditResults) != len(sqls) {
+		c.report.fail("audit", "Audit", "audit results [%d] does not match the number of SQL [%d]", len(resp.AuditResults), len(sqls)).Duration = d
+		return
+	}
+	c.report.pass("audit", "Audit").Duration = d
+
+	invalid := []string{}
+	unknownRules := map[string]struct{}{}
+	for i, results := range resp.AuditResults {
+		for _, result := range results.Results {
+			if !isValidRuleLevel(result.Level) {
+				invalid = append(invalid, fmt.Sprintf("sql[%d] has invalid level %q", i, result.Level))
+			}
+			if _, err := driverV2.ConvertI18nAuditResultFromProtoToDriver(result, c.isI18n); err != nil {
+				invalid = append(invalid, fmt.Sprintf("sql[%d]: %v", i, err))
+			}
+			if _, ok := c.ruleNames[result.RuleName]; result.RuleName != "" && !ok {
+				unknownRules[result.RuleName] = struct{}{}
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		c.report.fail("audit.results", "Audit", "%s", strings.Join(invalid, "; "))
+	} else {
+		c.report.pass("audit.results", "Audit")
+	}
+	if len(unknownRules) > 0 {
+		names := make([]string, 0, len(unknownRules))
+		for name := range unknownRules {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		c.report.warn("audit.rule_names", "Audit", "the results refer to the rules not in Metas: %s", strings.Join(names, ", "))
+	} else {
+		c.report.pass("audit.rule_names", "Audit")
+	}
+}
+
+func (c *checker) checkOnline(session *protoV2.Session) {
+	d, err := c.call(func(ctx context.Context) error {
+		_, err := c.client.Ping(ctx, &protoV2.PingRequest{Session: session})
+		return err
+	})
+	if err != nil {
+		c.report.fail("ping", "Ping", "call failed: %v", err).Duration = d
+	} else {
+		c.report.pass("ping", "Ping").Duration = d
+	}
+
+	d, err = c.call(func(ctx context.Context) error {
+		_, err := c.client.GetDatabases(ctx, &protoV2.GetDatabasesRequest{Session: session})
+		return err
+	})
+	if err != nil {
+		c.report.fail("get_databases", "GetDatabases", "call failed: %v", err).Duration = d
+	} else {
+		c.report.pass("get_databases", "GetDatabases").Duration = d
+	}
+
+	d, err = c.call(func(ctx context.Context) error {
+		_, err := c.client.GetSelectivityOfSQLColumns(ctx, &protoV2.GetSelectivityOfSQLColumnsRequest{
+			Session: session,
+			Sql:     c.cfg.sqlOfType("SELECT"),
+		})
+		return err
+	})
+	switch {
+	case err == nil:
+		c.report.pass("get_selectivity_of_sql_columns", "GetSelectivityOfSQLColumns").Duration = d
+	case isUnimplemented(err):
+		c.report.skip("get_selectivity_of_sql_columns", "GetSelectivityOfSQLColumns", "not implemented").Duration = d
+	default:
+		c.report.warn("get_selectivity_of_sql_columns", "GetSelectivityOfSQLColumns", "call failed: %v", err).Duration = d
+	}
+
+	// Exec and Tx change the database, they are verified by the ExecBatch claim only
+	c.report.skip("exec", "Exec", "not called because it changes the database")
+	c.report.skip("tx", "Tx", "not called because it changes the database")
+}
+
+// moduleProbe calls the RPC of an optional module to find whether it is implemented.
+type moduleProbe struct {
+	module driverV2.OptionalModule
+	rpc    string
+	// online is true if the RPC needs a database
+	online bool
+	// skip returns the reason if the RPC can not be called
+	skip func() string
+	call func(ctx context.Context, session *protoV2.Session) error
+}
+
+func (c *checker) probes() []*moduleProbe {
+	needTable := func() string {
+		if c.cfg.Table == nil {
+			return "no table is given"
+		}
+		return ""
+	}
+	table := func() *protoV2.Table {
+		return &protoV2.Table{Name: c.cfg.Table.Name, Schema: c.cfg.Table.Schema}
+	}
+	querySQL := c.cfg.QuerySQL
+	if querySQL == "" {
+		querySQL = defaultQuerySQL
+	}
+	return []*moduleProbe{
+		{module: driverV2.OptionalModuleGenRollbackSQL, rpc: "GenRollbackSQL", call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.GenRollbackSQL(ctx, &protoV2.GenRollbackSQLRequest{Session: s, Sql: &protoV2.NeedRollbackSQL{Query: c.cfg.sqlOfType("UPDATE", "DELETE", "INSERT")}})
+			return err
+		}},
+		{module: driverV2.OptionalModuleExtractTableFromSQL, rpc: "ExtractTableFromSQL", call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.ExtractTableFromSQL(ctx, &protoV2.ExtractTableFromSQLRequest{Session: s, Sql: &protoV2.ExtractedSQL{Query: c.cfg.sqlOfType("SELECT")}})
+			return err
+		}},
+		{module: driverV2.OptionalModuleQuery, rpc: "Query", online: true, call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.Query(ctx, &protoV2.QueryRequest{Session: s, Sql: &protoV2.QuerySQL{Query: querySQL}, Conf: &protoV2.QueryConf{TimeoutSecond: 10}})
+			return err
+		}},
+		{module: driverV2.OptionalModuleExplain, rpc: "Explain", online: true, call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.Explain(ctx, &protoV2.ExplainRequest{Session: s, Sql: &protoV2.ExplainSQL{Query: querySQL}})
+			return err
+		}},
+		{module: driverV2.OptionalModuleGetTableMeta, rpc: "GetTableMeta", online: true, skip: needTable, call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.GetTableMeta(ctx, &protoV2.GetTableMetaRequest{Session: s, Table: table()})
+			return err
+		}},
+		{module: driverV2.OptionalModuleEstimateSQLAffectRows, rpc: "EstimateSQLAffectRows", online: true, call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.EstimateSQLAffectRows(ctx, &protoV2.EstimateSQLAffectRowsRequest{Session: s, Sql: &protoV2.AffectRowsSQL{Query: c.cfg.sqlOfType("UPDATE", "DELETE")}})
+			return err
+		}},
+		{module: driverV2.OptionalExecBatch, rpc: "ExecBatch", online: true, skip: func() string {
+			return "not called because it changes the database"
+		}},
+		{module: driverV2.OptionalGetDatabaseObjectDDL, rpc: "GetDatabaseObjectDDL", online: true, skip: needTable, call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.GetDatabaseObjectDDL(ctx, &protoV2.DatabaseObjectInfoRequest{Session: s, DatabaseSchemaInfo: []*protoV2.DatabaseSchemaInfo{{
+				SchemaName:     c.cfg.Table.Schema,
+				DatabaseObject: []*protoV2.DatabaseObject{{ObjectName: c.cfg.Table.Name, ObjectType: "TABLE"}},
+			}}})
+			return err
+		}},
+		{module: driverV2.OptionalGetDatabaseDiffModifySQL, rpc: "GetDatabaseDiffModifySQL", online: true, skip: needTable, call: func(ctx context.Context, s *protoV2.Session) error {
+			dsn := c.cfg.DSN
+			_, err := c.client.GetDatabaseDiffModifySQL(ctx, &protoV2.DatabaseDiffModifyRequest{
+				Session: s,
+				CalibratedDSN: &protoV2.DSN{
+					Host:             dsn.Host,
+					Port:             dsn.Port,
+					User:             dsn.User,
+					Password:         dsn.Password,
+					Database:         dsn.DatabaseName,
+					AdditionalParams: driverV2.ConvertParamToProtoParam(dsn.AdditionalParams),
+				},
+				ObjInfos: []*protoV2.DatabasDiffSchemaInfo{{
+					BaseSchemaName:     c.cfg.Table.Schema,
+					ComparedSchemaName: c.cfg.Table.Schema,
+					DatabaseObject:     []*protoV2.DatabaseObject{{ObjectName: c.cfg.Table.Name, ObjectType: "TABLE"}},
+				}},
+			})
+			return err
+		}},
+		{module: driverV2.OptionalBackup, rpc: "RecommendBackupStrategy", online: true, call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.RecommendBackupStrategy(ctx, &protoV2.RecommendBackupStrategyReq{Session: s, Sql: c.cfg.sqlOfType("UPDATE", "DELETE")})
+			return err
+		}},
+		{module: driverV2.OptionalBackup, rpc: "Backup", online: true, call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.Backup(ctx, &protoV2.BackupReq{Session: s, BackupStrategy: protoV2.BackupStrategy_None, Sql: c.cfg.sqlOfType("UPDATE", "DELETE"), BackupMaxRows: 1})
+			return err
+		}},
+		// KillProcess kills the connection of the session, so it is the last one
+		{module: driverV2.OptionalModuleKillProcess, rpc: "KillProcess", online: true, call: func(ctx context.Context, s *protoV2.Session) error {
+			_, err := c.client.KillProcess(ctx, &protoV2.KillProcessRequest{Session: s})
+			return err
+		}},
+	}
+}
+
+// checkOptionalModules checks that the plugin implements the RPCs of the claimed optional modules.
+func (c *checker) checkOptionalModules(session *protoV2.Session) {
+	claimed := map[driverV2.OptionalModule]bool{}
+	for _, m := range c.metas.EnabledOptionalModule {
+		claimed[driverV2.OptionalModule(m)] = true
+	}
+	for _, probe := range c.probes() {
+		name := "module." + probe.module.String()
+		if probe.rpc != probe.module.String() {
+			name += "." + probe.rpc
+		}
+		isClaimed := claimed[probe.module]
+		if probe.skip != nil {
+			if reason := probe.skip(); reason != "" {
+				c.report.skip(name, probe.rpc, "%s", reason)
+				continue
+			}
+		}
+		if probe.online && c.cfg.DSN == nil {
+			c.report.skip(name, probe.rpc, "the RPC needs a database but no DSN is given")
+			continue
+		}
+
+		d, err := c.call(func(ctx context.Context) error {
+			return probe.call(ctx, session)
+		})
+		switch {
+		case err == nil && isClaimed:
+			c.report.pass(name, probe.rpc).Duration = d
+		case err == nil:
+			c.report.warn(name, probe.rpc, "the RPC is implemented but the module is not claimed, sqled never calls it").Duration = d
+		case isUnimplemented(err) && isClaimed:
+			c.report.fail(name, probe.rpc, "the module is claimed but the RPC is not implemented: %v", err).Duration = d
+		case isUnimplemented(err):
+			c.report.pass(name, probe.rpc).Duration = d
+		case isClaimed:
+			// the RPC is implemented but fails on the sample input, such as the sample table does not exist
+			c.report.warn(name, probe.rpc, "call failed: %v", err).Duration = d
+		default:
+			c.report.pass(name, probe.rpc).Duration = d
+		}
+	}
+}
+
+var unimplementedMessages = []string{"not support", "unsupported", "not implement", "unimplemented"}
+
+// isUnimplemented reports whether the error means the RPC is not implemented, the RPCs unknown by an old
+// plugin return codes.Unimplemented, and the plugins return errors like "xxx not support" for the
+// RPCs they do not implement.
+func isUnimplemented(err error) bool {
+	if err == nil {
+		return false
+	}
+	s := status.Convert(err)
+	if s.Code() == codes.Unimplemented {
+		return true
+	}
+	msg := strings.ToLower(s.Message())
+	for _, m := range unimplementedMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidRuleLevel(level string) bool {
+	switch driverV2.RuleLevel(level) {
+	case driverV2.RuleLevelNull, driverV2.RuleLevelNormal, driverV2.RuleLevelNotice, driverV2.RuleLevelWarn, driverV2.RuleLevelError:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package conformance
+
+import (
+	"context"
+	sqlDriver "database/sql/driver"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	protoV2 "github.com/actiontech/sqle/sqle/driver/v2/proto"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc"
+)
+
+var errNotSupport = fmt.Errorf("not support")
+
+// fakeDriver splits the SQLs by ";" and reports a notice for every SQL, the optional RPCs are implemented
+// only if they are in implemented.
+type fakeDriver struct {
+	implemented map[driverV2.OptionalModule]bool
+	zeroLine    bool
+}
+
+func (d *fakeDriver) Close(ctx context.Context) {}
+
+func (d *fakeDriver) Parse(ctx context.Context, sql string) ([]driverV2.Node, error) {
+	nodes := []driverV2.Node{}
+	line := uint64(1)
+	for _, text := range strings.Split(sql, ";") {
+		trimmed := strings.TrimSpace(text)
+		startLine := line + uint64(strings.Count(text[:strings.Index(text, trimmed)], "\n"))
+		line += uint64(strings.Count(text, "\n"))
+		if trimmed == "" {
+			continue
+		}
+		if d.zeroLine {
+			startLine = 0
+		}
+		nodes = append(nodes, driverV2.Node{Text: trimmed, Type: driverV2.SQLTypeDML, Fingerprint: trimmed, StartLine: startLine})
+	}
+	return nodes, nil
+}
+
+func (d *fakeDriver) Audit(ctx context.Context, sqls []string) ([]*driverV2.AuditResults, error) {
+	results := make([]*driverV2.AuditResults, 0, len(sqls))
+	for range sqls {
+		rs := driverV2.NewAuditResults()
+		rs.Add(driverV2.RuleLevelNotice, "rule_a", i18nPkg.ConvertStr2I18nAsDefaultLang("notice"))
+		results = append(results, rs)
+	}
+	return results, nil
+}
+
+func (d *fakeDriver) GenRollbackSQL(ctx context.Context, sql string) (string, string, error) {
+	if !d.implemented[driverV2.OptionalModuleGenRollbackSQL] {
+		return "", "", errNotSupport
+	}
+	return "", "", nil
+}
+
+func (d *fakeDriver) Ping(ctx context.Context) error { return nil }
+
+func (d *fakeDriver) Exec(ctx context.Context, sql string) (sqlDriver.Result, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) ExecBatch(ctx context.Context, sqls ...string) ([]sqlDriver.Result, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) Tx(ctx context.Context, sqls ...string) (*driverV2.TxResponse, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) Query(ctx context.Context, sql string, conf *driverV2.QueryConf) (*driverV2.QueryResult, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) Explain(ctx context.Context, conf *driverV2.ExplainConf) (*driverV2.ExplainResult, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) GetDatabases(ctx context.Context) ([]string, error) { return []string{}, nil }
+
+func (d *fakeDriver) GetTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) ExtractTableFromSQL(ctx context.Context, sql string) ([]*driverV2.Table, error) {
+	if !d.implemented[driverV2.OptionalModuleExtractTableFromSQL] {
+		return nil, errNotSupport
+	}
+	return []*driverV2.Table{{Name: "conformance_t1"}}, nil
+}
+
+func (d *fakeDriver) EstimateSQLAffectRows(ctx context.Context, sql string) (*driverV2.EstimatedAffectRows, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) KillProcess(ctx context.Context) (*driverV2.KillProcessInfo, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) GetDatabaseObjectDDL(ctx context.Context, objInfos []*driverV2.DatabaseSchemaInfo) ([]*driverV2.DatabaseSchemaObjectResult, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) GetDatabaseDiffModifySQL(ctx context.Context, calibratedDSN *driverV2.DSN, objInfos []*driverV2.DatabasCompareSchemaInfo) ([]*driverV2.DatabaseDiffModifySQLResult, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) Backup(ctx context.Context, req *driverV2.BackupReq) (*driverV2.BackupRes, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) RecommendBackupStrategy(ctx context.Context, req *driverV2.RecommendBackupStrategyReq) (*driverV2.RecommendBackupStrategyRes, error) {
+	return nil, errNotSupport
+}
+
+func (d *fakeDriver) GetSelectivityOfSQLColumns(ctx context.Context, sql string) (map[string]map[string]float32, error) {
+	return nil, errNotSupport
+}
+
+func newRule(name string, langs ...language.Tag) *driverV2.Rule {
+	info := driverV2.I18nRuleInfo{}
+	for _, lang := range langs {
+		info[lang] = &driverV2.RuleInfo{Desc: name + " " + lang.String(), Category: "test"}
+	}
+	return &driverV2.Rule{Name: name, Level: driverV2.RuleLevelNotice, I18nRuleInfo: info}
+}
+
+func serve(t *testing.T, meta driverV2.DriverMetas, d *fakeDriver) protoV2.DriverClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	s := grpc.NewServer()
+	protoV2.RegisterDriverServer(s, &driverV2.DriverGrpcServer{
+		Meta: meta,
+		DriverFactory: func(*driverV2.Config) (driverV2.Driver, error) {
+			return d, nil
+		},
+		Drivers: map[string]driverV2.Driver{},
+	})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return protoV2.NewDriverClient(conn)
+}
+
+func getCheck(r *Report, name string) *CheckResult {
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestCheckConformingPlugin(t *testing.T) {
+	client := serve(t, driverV2.DriverMetas{
+		PluginName: "fake",
+		Rules: []*driverV2.Rule{
+			newRule("rule_a", language.Chinese, language.English),
+			newRule("rule_b", language.Chinese, language.English),
+		},
+		EnabledOptionalModule: []driverV2.OptionalModule{
+			driverV2.OptionalModuleI18n,
+			driverV2.OptionalModuleGenRollbackSQL,
+			driverV2.OptionalModuleExtractTableFromSQL,
+		},
+	}, &fakeDriver{implemented: map[driverV2.OptionalModule]bool{
+		driverV2.OptionalModuleGenRollbackSQL:      true,
+		driverV2.OptionalModuleExtractTableFromSQL: true,
+	}})
+
+	report := Check(context.Background(), client, &Config{})
+	for _, c := range report.Checks {
+		assert.NotEqual(t, StatusFail, c.Status, "%s: %s", c.Name, c.Message)
+	}
+	assert.True(t, report.Passed())
+	assert.Equal(t, "fake", report.PluginName)
+	assert.Equal(t, StatusPass, getCheck(report, "parse.start_line").Status)
+	assert.Equal(t, StatusPass, getCheck(report, "metas.rules.i18n").Status)
+	assert.Equal(t, StatusPass, getCheck(report, "module.GenRollbackSQL").Status)
+	assert.Equal(t, StatusPass, getCheck(report, "module.ExtractTableFromSQL").Status)
+	// the online RPCs are skipped without a DSN
+	assert.Nil(t, getCheck(report, "ping"))
+	assert.Equal(t, StatusSkip, getCheck(report, "module.Query").Status)
+	assert.Equal(t, StatusPass, getCheck(report, "close.offline").Status)
+}
+
+func TestCheckNonConformingPlugin(t *testing.T) {
+	client := serve(t, driverV2.DriverMetas{
+		PluginName: "fake",
+		Rules: []*driverV2.Rule{
+			newRule("rule_a", language.Chinese, language.English),
+			newRule("rule_a", language.Chinese, language.English),
+			newRule("rule_b", language.Chinese),
+		},
+		EnabledOptionalModule: []driverV2.OptionalModule{
+			driverV2.OptionalModuleI18n,
+			driverV2.OptionalModuleGenRollbackSQL,
+		},
+	}, &fakeDriver{
+		implemented: map[driverV2.OptionalModule]bool{
+			driverV2.OptionalModuleExtractTableFromSQL: true,
+		},
+		zeroLine: true,
+	})
+
+	report := Check(context.Background(), client, &Config{})
+	assert.False(t, report.Passed())
+	assert.Equal(t, StatusFail, getCheck(report, "metas.rules.unique_name").Status)
+	assert.Contains(t, getCheck(report, "metas.rules.i18n").Message, "rule rule_b has no I18nRuleInfo of en")
+	assert.Equal(t, StatusFail, getCheck(report, "module.GenRollbackSQL").Status)
+	assert.Equal(t, StatusWarn, getCheck(report, "module.ExtractTableFromSQL").Status)
+	assert.Equal(t, StatusWarn, getCheck(report, "parse.start_line").Status)
+}
+
+func TestCheckStartLine(t *testing.T) {
+	r := &Report{}
+	ck := &checker{report: r}
+	ck.checkStartLine([]*protoV2.Node{{StartLine: 1}, {StartLine: 3}, {StartLine: 2}})
+	assert.Equal(t, StatusFail, r.Checks[0].Status)
+
+	ck.checkStartLine([]*protoV2.Node{{StartLine: 1}, {StartLine: 1}, {StartLine: 2}})
+	assert.Equal(t, StatusPass, r.Checks[1].Status)
+}
+
+func TestIsUnimplemented(t *testing.T) {
+	assert.False(t, isUnimplemented(nil))
+	assert.True(t, isUnimplemented(fmt.Errorf("GenRollbackSQL is not supported")))
+	assert.True(t, isUnimplemented(fmt.Errorf("method Backup not implemented")))
+	assert.False(t, isUnimplemented(fmt.Errorf("table conformance_t1 doesn't exist")))
+}
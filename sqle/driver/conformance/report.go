@@ -0,0 +1,112 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	// StatusSkip means the check is not run, such as the RPC needs a database but no DSN is given.
+	StatusSkip Status = "skip"
+)
+
+type CheckResult struct {
+	Name     string        `json:"name"`
+	RPC      string        `json:"rpc,omitempty"`
+	Status   Status        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report is the result of checking a plugin, the plugin conforms to the protocol if no check fails.
+type Report struct {
+	PluginPath string         `json:"plugin_path"`
+	PluginName string         `json:"plugin_name"`
+	Online     bool           `json:"online"`
+	StartedAt  time.Time      `json:"started_at"`
+	Duration   time.Duration  `json:"duration_ns"`
+	Checks     []*CheckResult `json:"checks"`
+}
+
+func (r *Report) add(name, rpc string, status Status, format string, args ...interface{}) *CheckResult {
+	c := &CheckResult{Name: name, RPC: rpc, Status: status, Message: fmt.Sprintf(format, args...)}
+	r.Checks = append(r.Checks, c)
+	return c
+}
+
+func (r *Report) pass(name, rpc string) *CheckResult {
+	return r.add(name, rpc, StatusPass, "")
+}
+
+func (r *Report) warn(name, rpc string, format string, args ...interface{}) *CheckResult {
+	return r.add(name, rpc, StatusWarn, format, args...)
+}
+
+func (r *Report) fail(name, rpc string, format string, args ...interface{}) *CheckResult {
+	return r.add(name, rpc, StatusFail, format, args...)
+}
+
+func (r *Report) skip(name, rpc string, format string, args ...interface{}) *CheckResult {
+	return r.add(name, rpc, StatusSkip, format, args...)
+}
+
+// Passed reports whether no check fails.
+func (r *Report) Passed() bool {
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of the checks of each status.
+func (r *Report) Count() map[Status]int {
+	count := map[Status]int{}
+	for _, c := range r.Checks {
+		count[c.Status]++
+	}
+	return count
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func (r *Report) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "plugin: %s (%s)\n", r.PluginName, r.PluginPath)
+	mode := "offline, the RPCs need a database are skipped"
+	if r.Online {
+		mode = "online"
+	}
+	fmt.Fprintf(w, "mode: %s\n\n", mode)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tCHECK\tRPC\tMESSAGE")
+	for _, c := range r.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", strings.ToUpper(string(c.Status)), c.Name, c.RPC, strings.ReplaceAll(c.Message, "\n", " "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	count := r.Count()
+	result := "PASSED"
+	if !r.Passed() {
+		result = "FAILED"
+	}
+	_, err := fmt.Fprintf(w, "\n%s: %d passed, %d warnings, %d failed, %d skipped in %v\n",
+		result, count[StatusPass], count[StatusWarn], count[StatusFail], count[StatusSkip], r.Duration.Round(time.Millisecond))
+	return err
+}
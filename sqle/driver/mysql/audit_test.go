@@ -8223,3 +8223,41 @@ func TestNotAllowInsertAutoincrement(t *testing.T) {
 		)
 	}
 }
+
+func TestDDLCheckAlterTableAlgorithm(t *testing.T) {
+	rule := rulepkg.RuleHandlerMap[rulepkg.DDLCheckAlterTableAlgorithm].Rule
+
+	// the version is unknown
+	runSingleRuleInspectCase(rule, t, "version unknown", DefaultMysqlInspect(),
+		"alter table exist_db.exist_tb_1 add column v3 varchar(255);",
+		newTestResult(),
+	)
+
+	inspect := DefaultMysqlInspect()
+	inspect.Ctx.AddSystemVariable("version", "8.0.30")
+	runSingleRuleInspectCase(rule, t, "instant", inspect,
+		"alter table exist_db.exist_tb_1 add column v3 varchar(255);",
+		newTestResult().addResult(rulepkg.DDLCheckAlterTableAlgorithm, "8.0.30",
+			"ALGORITHM=INSTANT, LOCK=NONE, REBUILD=NO",
+			"ADD COLUMN `v3` VARCHAR(255): ALGORITHM=INSTANT, LOCK=NONE, REBUILD=NO"),
+	)
+
+	inspect = DefaultMysqlInspect()
+	inspect.Ctx.AddSystemVariable("version", "5.7.44-log")
+	runSingleRuleInspectCase(rule, t, "copy", inspect,
+		"alter table exist_db.exist_tb_1 add column v3 varchar(255), modify column v2 int;",
+		newTestResult().addResult(rulepkg.DDLCheckAlterTableAlgorithm, "5.7.44",
+			"ALGORITHM=COPY, LOCK=SHARED, REBUILD=YES",
+			"ADD COLUMN `v3` VARCHAR(255): ALGORITHM=INPLACE, LOCK=NONE, REBUILD=YES; "+
+				"MODIFY COLUMN `v2` INT: ALGORITHM=COPY, LOCK=SHARED, REBUILD=YES"),
+	)
+
+	inspect = DefaultMysqlInspect()
+	inspect.Ctx.AddSystemVariable("version", "8.0.30")
+	runSingleRuleInspectCase(rule, t, "unsupported algorithm", inspect,
+		"alter table exist_db.exist_tb_1 modify column v2 int, algorithm=inplace;",
+		newTestResult().add(rule.Level, rule.Name,
+			plocale.Bundle.LocalizeMsgByLang(i18nPkg.DefaultLang, plocale.DDLCheckAlterTableAlgorithmUnsupportedMessage),
+			"ALGORITHM=INPLACE, LOCK=DEFAULT", "ALGORITHM=COPY, LOCK=SHARED, REBUILD=YES"),
+	)
+}
@@ -0,0 +1,230 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/mysql"
+)
+
+var integerLiteral = regexp.MustCompile(`^-?[0-9]+$`)
+
+// the MySQL plugin is registered in driver.ChunkedDMLPlugins
+var _ driver.ChunkedDMLExecutor = (*MysqlDriverImpl)(nil)
+
+func notChunkable(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", driver.ErrNotChunkable, fmt.Sprintf(format, args...))
+}
+
+// chunkableDML returns the statement and its table if the SQL is a single-table UPDATE/DELETE without
+// ORDER BY and LIMIT, such a statement can be split by primary key range without changing its result.
+func chunkableDML(sql string) (ast.StmtNode, *ast.TableName, error) {
+	stmt, err := util.ParseOneSql(sql)
+	if err != nil {
+		return nil, nil, err
+	}
+	var refs *ast.TableRefsClause
+	switch s := stmt.(type) {
+	case *ast.UpdateStmt:
+		if s.MultipleTable || s.Order != nil || s.Limit != nil {
+			return nil, nil, notChunkable("only single-table UPDATE without ORDER BY and LIMIT is supported")
+		}
+		refs = s.TableRefs
+	case *ast.DeleteStmt:
+		if s.IsMultiTable || s.Order != nil || s.Limit != nil {
+			return nil, nil, notChunkable("only single-table DELETE without ORDER BY and LIMIT is supported")
+		}
+		refs = s.TableRefs
+	default:
+		return nil, nil, notChunkable("only UPDATE and DELETE are supported")
+	}
+	if refs == nil || refs.TableRefs == nil || refs.TableRefs.Right != nil {
+		return nil, nil, notChunkable("only single-table statement is supported")
+	}
+	source, ok := refs.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return nil, nil, notChunkable("only single-table statement is supported")
+	}
+	table, ok := source.Source.(*ast.TableName)
+	if !ok {
+		return nil, nil, notChunkable("the statement does not update a table")
+	}
+	return stmt, table, nil
+}
+
+// chunkPKColumn returns the primary key column if the primary key is a single integer column.
+func chunkPKColumn(createTableStmt *ast.CreateTableStmt) (string, error) {
+	pks, hasPk := util.GetPrimaryKey(createTableStmt)
+	if !hasPk {
+		return "", notChunkable("table %s has no primary key", createTableStmt.Table.Name.O)
+	}
+	if len(pks) != 1 {
+		return "", notChunkable("the primary key of table %s is not a single column", createTableStmt.Table.Name.O)
+	}
+	for _, col := range createTableStmt.Cols {
+		if _, ok := pks[col.Name.Name.L]; !ok {
+			continue
+		}
+		switch col.Tp.Tp {
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+			return col.Name.Name.O, nil
+		}
+		return "", notChunkable("the primary key of table %s is not an integer", createTableStmt.Table.Name.O)
+	}
+	return "", notChunkable("the primary key of table %s is not found", createTableStmt.Table.Name.O)
+}
+
+// PlanChunkedDML implements driver.ChunkedDMLExecutor.
+func (i *MysqlDriverImpl) PlanChunkedDML(ctx context.Context, sql string) (*driver.ChunkedDMLPlan, error) {
+	if i.IsOfflineAudit() {
+		return nil, notChunkable("the instance is not connected")
+	}
+	_, table, err := chunkableDML(sql)
+	if err != nil {
+		return nil, err
+	}
+	createTableStmt, exist, err := i.Ctx.GetCreateTableStmt(table)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, notChunkable("table %s does not exist", i.getTableName(table))
+	}
+	pk, err := chunkPKColumn(createTableStmt)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.Db.Query(fmt.Sprintf("SELECT MIN(`%s`) AS min_pk, MAX(`%s`) AS max_pk FROM %s",
+		pk, pk, i.getTableNameWithQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+	plan := &driver.ChunkedDMLPlan{
+		Schema:   i.Ctx.GetSchemaName(table),
+		Table:    table.Name.O,
+		PKColumn: pk,
+	}
+	if len(rows) > 0 && rows[0]["min_pk"].Valid {
+		plan.MinPK = rows[0]["min_pk"].String
+		plan.MaxPK = rows[0]["max_pk"].String
+	}
+	return plan, nil
+}
+
+// NextDMLChunk implements driver.ChunkedDMLExecutor.
+func (i *MysqlDriverImpl) NextDMLChunk(ctx context.Context, sql string, plan *driver.ChunkedDMLPlan, lastPK string, chunkSize uint64) (*driver.DMLChunk, error) {
+	if chunkSize == 0 {
+		return nil, fmt.Errorf("chunk size must be greater than 0")
+	}
+	for _, pk := range []string{plan.MinPK, plan.MaxPK, lastPK} {
+		if pk != "" && !integerLiteral.MatchString(pk) {
+			return nil, fmt.Errorf("primary key %q is not an integer", pk)
+		}
+	}
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	table := chunkTableName(plan)
+	chunk := &driver.DMLChunk{StartPK: lastPK}
+	minPK, maxPK := plan.MinPK, plan.MaxPK
+	if maxPK == "" || lastPK == maxPK {
+		// the rows in the range of the plan are done, the rows inserted after the plan are executed too
+		query := fmt.Sprintf("SELECT MIN(`%s`) AS min_pk, MAX(`%s`) AS max_pk FROM %s", plan.PKColumn, plan.PKColumn, table)
+		if lastPK != "" {
+			query += fmt.Sprintf(" WHERE `%s` > %s", plan.PKColumn, lastPK)
+		}
+		rows, err := conn.Db.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 || !rows[0]["max_pk"].Valid {
+			chunk.EndPK = lastPK
+			chunk.Done = true
+			return chunk, nil
+		}
+		if minPK == "" {
+			minPK = rows[0]["min_pk"].String
+		}
+		maxPK = rows[0]["max_pk"].String
+		if !integerLiteral.MatchString(minPK) || !integerLiteral.MatchString(maxPK) {
+			return nil, fmt.Errorf("primary key range [%q, %q] is not integers", minPK, maxPK)
+		}
+		chunk.MinPK, chunk.MaxPK = minPK, maxPK
+	}
+
+	lower := fmt.Sprintf("`%s` >= %s", plan.PKColumn, minPK)
+	if lastPK != "" {
+		lower = fmt.Sprintf("`%s` > %s", plan.PKColumn, lastPK)
+	}
+	// find the upper bound of the batch by the primary key index, the batch has at most chunkSize rows
+	rows, err := conn.Db.Query(fmt.Sprintf("SELECT `%s` AS pk FROM %s WHERE %s AND `%s` <= %s ORDER BY `%s` LIMIT 1 OFFSET %d",
+		plan.PKColumn, table, lower, plan.PKColumn, maxPK, plan.PKColumn, chunkSize-1))
+	if err != nil {
+		return nil, err
+	}
+	chunk.EndPK = maxPK
+	if len(rows) > 0 && rows[0]["pk"].Valid {
+		chunk.EndPK = rows[0]["pk"].String
+	}
+	if !integerLiteral.MatchString(chunk.EndPK) {
+		return nil, fmt.Errorf("primary key %q is not an integer", chunk.EndPK)
+	}
+	chunk.SQL, err = chunkSQL(sql, fmt.Sprintf("%s AND `%s` <= %s", lower, plan.PKColumn, chunk.EndPK))
+	if err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// chunkTableName returns the quoted table of the plan, the schema is omitted if it is empty, then the table
+// is in the current schema of the connection.
+func chunkTableName(plan *driver.ChunkedDMLPlan) string {
+	if plan.Schema == "" {
+		return fmt.Sprintf("`%s`", plan.Table)
+	}
+	return fmt.Sprintf("`%s`.`%s`", plan.Schema, plan.Table)
+}
+
+// chunkSQL adds the primary key range to the WHERE of the UPDATE/DELETE, the WHERE is the last clause
+// since the statement has no ORDER BY and LIMIT.
+func chunkSQL(sql string, pkRange string) (string, error) {
+	stmt, _, err := chunkableDML(sql)
+	if err != nil {
+		return "", err
+	}
+	var where ast.ExprNode
+	switch s := stmt.(type) {
+	case *ast.UpdateStmt:
+		where, s.Where = s.Where, nil
+	case *ast.DeleteStmt:
+		where, s.Where = s.Where, nil
+	}
+
+	var buf strings.Builder
+	if err := stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)); err != nil {
+		return "", err
+	}
+	buf.WriteString(" WHERE ")
+	if where != nil {
+		buf.WriteString("(")
+		if err := where.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)); err != nil {
+			return "", err
+		}
+		buf.WriteString(") AND ")
+	}
+	buf.WriteString(pkRange)
+	return buf.String(), nil
+}
@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkSQL(t *testing.T) {
+	for _, c := range []struct {
+		sql    string
+		expect string
+	}{
+		{
+			sql:    "UPDATE exist_tb_1 SET v1 = 'a' WHERE v2 = 'b' OR v2 IS NULL",
+			expect: "UPDATE `exist_tb_1` SET `v1`='a' WHERE (`v2`='b' OR `v2` IS NULL) AND `id` > 0 AND `id` <= 10",
+		},
+		{
+			sql:    "DELETE FROM exist_db.exist_tb_1",
+			expect: "DELETE FROM `exist_db`.`exist_tb_1` WHERE `id` > 0 AND `id` <= 10",
+		},
+	} {
+		actual, err := chunkSQL(c.sql, "`id` > 0 AND `id` <= 10")
+		assert.NoError(t, err)
+		assert.Equal(t, c.expect, actual)
+	}
+
+	for _, sql := range []string{
+		"INSERT INTO exist_tb_1 (id) VALUES (1)",
+		"UPDATE exist_tb_1 SET v1 = 'a' ORDER BY id LIMIT 10",
+		"DELETE FROM exist_tb_1 WHERE id > 10 LIMIT 10",
+		"UPDATE exist_tb_1 a JOIN exist_tb_2 b ON a.id = b.id SET a.v1 = b.v1",
+		"DELETE a FROM exist_tb_1 a JOIN exist_tb_2 b ON a.id = b.id",
+	} {
+		_, err := chunkSQL(sql, "`id` > 0")
+		assert.True(t, errors.Is(err, driver.ErrNotChunkable), sql)
+	}
+}
+
+func TestNextDMLChunk(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+	sql := "UPDATE exist_tb_1 SET v1 = 'a' WHERE v2 = 'b'"
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT MIN(`id`) AS min_pk, MAX(`id`) AS max_pk FROM `exist_db`.`exist_tb_1`")).
+		WillReturnRows(sqlmock.NewRows([]string{"min_pk", "max_pk"}).AddRow("1", "2500"))
+	plan, err := i.PlanChunkedDML(context.TODO(), sql)
+	assert.NoError(t, err)
+	assert.Equal(t, &driver.ChunkedDMLPlan{Schema: "exist_db", Table: "exist_tb_1", PKColumn: "id", MinPK: "1", MaxPK: "2500"}, plan)
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT `id` AS pk FROM `exist_db`.`exist_tb_1` WHERE `id` >= 1 AND `id` <= 2500 ORDER BY `id` LIMIT 1 OFFSET 999")).
+		WillReturnRows(sqlmock.NewRows([]string{"pk"}).AddRow("1000"))
+	chunk, err := i.NextDMLChunk(context.TODO(), sql, plan, "", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, &driver.DMLChunk{StartPK: "", EndPK: "1000",
+		SQL: "UPDATE `exist_tb_1` SET `v1`='a' WHERE (`v2`='b') AND `id` >= 1 AND `id` <= 1000"}, chunk)
+
+	// the batch ends at the max primary key of the plan
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT `id` AS pk FROM `exist_db`.`exist_tb_1` WHERE `id` > 1000 AND `id` <= 2500 ORDER BY `id` LIMIT 1 OFFSET 999")).
+		WillReturnRows(sqlmock.NewRows([]string{"pk"}))
+	chunk, err = i.NextDMLChunk(context.TODO(), sql, plan, "1000", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, &driver.DMLChunk{StartPK: "1000", EndPK: "2500",
+		SQL: "UPDATE `exist_tb_1` SET `v1`='a' WHERE (`v2`='b') AND `id` > 1000 AND `id` <= 2500"}, chunk)
+
+	// the rows inserted after the plan are executed too
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT MIN(`id`) AS min_pk, MAX(`id`) AS max_pk FROM `exist_db`.`exist_tb_1` WHERE `id` > 2500")).
+		WillReturnRows(sqlmock.NewRows([]string{"min_pk", "max_pk"}).AddRow("2501", "2600"))
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT `id` AS pk FROM `exist_db`.`exist_tb_1` WHERE `id` > 2500 AND `id` <= 2600 ORDER BY `id` LIMIT 1 OFFSET 999")).
+		WillReturnRows(sqlmock.NewRows([]string{"pk"}))
+	chunk, err = i.NextDMLChunk(context.TODO(), sql, plan, "2500", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, &driver.DMLChunk{StartPK: "2500", EndPK: "2600", MinPK: "1", MaxPK: "2600",
+		SQL: "UPDATE `exist_tb_1` SET `v1`='a' WHERE (`v2`='b') AND `id` > 2500 AND `id` <= 2600"}, chunk)
+
+	plan.MaxPK = chunk.MaxPK
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT MIN(`id`) AS min_pk, MAX(`id`) AS max_pk FROM `exist_db`.`exist_tb_1` WHERE `id` > 2600")).
+		WillReturnRows(sqlmock.NewRows([]string{"min_pk", "max_pk"}).AddRow(nil, nil))
+	chunk, err = i.NextDMLChunk(context.TODO(), sql, plan, "2600", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, &driver.DMLChunk{StartPK: "2600", EndPK: "2600", Done: true}, chunk)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestNextDMLChunkWithoutSchema(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+	plan := &driver.ChunkedDMLPlan{Table: "t1", PKColumn: "id", MinPK: "1", MaxPK: "10"}
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT `id` AS pk FROM `t1` WHERE `id` >= 1 AND `id` <= 10 ORDER BY `id` LIMIT 1 OFFSET 99")).
+		WillReturnRows(sqlmock.NewRows([]string{"pk"}))
+	chunk, err := i.NextDMLChunk(context.TODO(), "DELETE FROM t1", plan, "", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `t1` WHERE `id` >= 1 AND `id` <= 10", chunk.SQL)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestPlanChunkedDMLNotChunkable(t *testing.T) {
+	i := DefaultMysqlInspect()
+	// exist_tb_2 has no integer primary key
+	_, err := i.PlanChunkedDML(context.TODO(), "DELETE FROM exist_tb_2 WHERE v1 = 'a'")
+	assert.True(t, errors.Is(err, driver.ErrNotChunkable))
+
+	_, err = i.PlanChunkedDML(context.TODO(), "DELETE FROM not_exist_tb WHERE v1 = 'a'")
+	assert.True(t, errors.Is(err, driver.ErrNotChunkable))
+}
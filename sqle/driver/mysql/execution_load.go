@@ -0,0 +1,154 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	gomysql "github.com/go-sql-driver/mysql"
+)
+
+// ProbeExecutionLoad implements driver.ExecutionLoadProber.
+func (i *MysqlDriverImpl) ProbeExecutionLoad(ctx context.Context, replicaDSNs []string, discoverReplicas bool) (*driver.ExecutionLoad, error) {
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.Db.Query("SHOW GLOBAL STATUS LIKE 'Threads_running'")
+	if err != nil {
+		return nil, err
+	}
+	load := &driver.ExecutionLoad{}
+	if len(rows) > 0 {
+		load.ThreadsRunning, _ = strconv.ParseInt(rows[0]["Value"].String, 10, 64)
+	}
+
+	replicas := []*driverV2.DSN{}
+	for _, dsn := range replicaDSNs {
+		replica, err := ParseReplicaDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		replicas = append(replicas, replica)
+	}
+	if discoverReplicas {
+		discovered, err := i.discoverReplicas(conn)
+		if err != nil {
+			return nil, err
+		}
+		replicas = append(replicas, discovered...)
+	}
+
+	probed := map[string]struct{}{}
+	for _, replica := range replicas {
+		addr := net.JoinHostPort(replica.Host, replica.Port)
+		if _, ok := probed[addr]; ok {
+			continue
+		}
+		probed[addr] = struct{}{}
+		lag := &driver.ReplicaLag{Replica: addr, LagSecond: -1}
+		if lag.LagSecond, err = i.probeReplicationLag(replica); err != nil {
+			lag.Error = err.Error()
+		}
+		load.Replicas = append(load.Replicas, lag)
+	}
+	return load, nil
+}
+
+// ParseReplicaDSN parses the DSN in the format of go-sql-driver, e.g. "user:password@tcp(10.10.10.10:3306)/".
+func ParseReplicaDSN(dsn string) (*driverV2.DSN, error) {
+	cfg, err := gomysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replica dsn: %v", err)
+	}
+	host, port, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replica address %s: %v", cfg.Addr, err)
+	}
+	return &driverV2.DSN{
+		Host:     host,
+		Port:     port,
+		User:     cfg.User,
+		Password: cfg.Passwd,
+	}, nil
+}
+
+// discoverReplicas lists the replicas registered on the instance, they are connected with the user of the
+// instance. SHOW REPLICAS is supported since 8.0.22, SHOW SLAVE HOSTS is used for the earlier versions.
+func (i *MysqlDriverImpl) discoverReplicas(conn *executor.Executor) ([]*driverV2.DSN, error) {
+	rows, err := conn.Db.Query("SHOW REPLICAS")
+	if err != nil {
+		rows, err = conn.Db.Query("SHOW SLAVE HOSTS")
+		if err != nil {
+			return nil, err
+		}
+	}
+	replicas := []*driverV2.DSN{}
+	for _, row := range rows {
+		// the host is empty if report_host is not set on the replica
+		if row["Host"].String == "" {
+			i.log.Warnf("replica %s is ignored since its report_host is not set", firstValid(row, "Replica_UUID", "Slave_UUID"))
+			continue
+		}
+		replicas = append(replicas, &driverV2.DSN{
+			Host:     row["Host"].String,
+			Port:     row["Port"].String,
+			User:     i.inst.User,
+			Password: i.inst.Password,
+		})
+	}
+	return replicas, nil
+}
+
+func (i *MysqlDriverImpl) probeReplicationLag(replica *driverV2.DSN) (int64, error) {
+	conn, err := executor.NewExecutor(i.log, replica, "")
+	if err != nil {
+		return -1, err
+	}
+	defer conn.Db.Close()
+	rows, err := conn.Db.Query("SHOW REPLICA STATUS")
+	if err != nil {
+		rows, err = conn.Db.Query("SHOW SLAVE STATUS")
+		if err != nil {
+			return -1, err
+		}
+	}
+	return replicationLag(rows)
+}
+
+// replicationLag returns the max lag of the replication channels.
+func replicationLag(rows []map[string]sql.NullString) (int64, error) {
+	if len(rows) == 0 {
+		return -1, fmt.Errorf("the instance is not a replica")
+	}
+	var maxLag int64
+	for _, row := range rows {
+		lag := firstValid(row, "Seconds_Behind_Source", "Seconds_Behind_Master")
+		if lag == "" {
+			return -1, fmt.Errorf("the replication is not running")
+		}
+		v, err := strconv.ParseInt(lag, 10, 64)
+		if err != nil {
+			return -1, fmt.Errorf("invalid replication lag %s", lag)
+		}
+		if v > maxLag {
+			maxLag = v
+		}
+	}
+	return maxLag, nil
+}
+
+func firstValid(row map[string]sql.NullString, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := row[key]; ok && v.Valid {
+			return v.String
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReplicaDSN(t *testing.T) {
+	dsn, err := ParseReplicaDSN("repl:pass@tcp(10.10.10.10:3307)/")
+	assert.NoError(t, err)
+	assert.Equal(t, &driverV2.DSN{Host: "10.10.10.10", Port: "3307", User: "repl", Password: "pass"}, dsn)
+
+	_, err = ParseReplicaDSN("10.10.10.10")
+	assert.Error(t, err)
+}
+
+func TestReplicationLag(t *testing.T) {
+	lag, err := replicationLag([]map[string]sql.NullString{
+		{"Seconds_Behind_Source": {String: "3", Valid: true}},
+		{"Seconds_Behind_Source": {String: "12", Valid: true}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), lag)
+
+	lag, err = replicationLag([]map[string]sql.NullString{
+		{"Seconds_Behind_Master": {String: "5", Valid: true}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), lag)
+
+	_, err = replicationLag([]map[string]sql.NullString{
+		{"Seconds_Behind_Source": {Valid: false}},
+	})
+	assert.EqualError(t, err, "the replication is not running")
+
+	_, err = replicationLag(nil)
+	assert.EqualError(t, err, "the instance is not a replica")
+}
+
+func TestProbeExecutionLoad(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW GLOBAL STATUS LIKE 'Threads_running'")).
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("Threads_running", "42"))
+	handler.ExpectQuery(regexp.QuoteMeta("SHOW REPLICAS")).
+		WillReturnRows(sqlmock.NewRows([]string{"Server_Id", "Host", "Port", "Source_Id", "Replica_UUID"}).
+			AddRow("2", "", "3306", "1", "uuid-2"))
+	load, err := i.ProbeExecutionLoad(context.TODO(), nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), load.ThreadsRunning)
+	// the replica without report_host is ignored
+	assert.Len(t, load.Replicas, 0)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
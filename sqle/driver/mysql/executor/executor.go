@@ -26,6 +26,8 @@ type Db interface {
 	Transact(qs ...string) (*driverV2.TxResponse, error)
 	Query(query string, args ...interface{}) ([]map[string]sql.NullString, error)
 	QueryWithContext(ctx context.Context, query string, args ...interface{}) (column []string, row [][]sql.NullString, err error)
+	QueryWithLimit(ctx context.Context, query string, limit int, args ...interface{}) (column []string, row [][]sql.NullString, err error)
+	QueryReadOnly(ctx context.Context, query string, limit int, args ...interface{}) (column []string, row [][]sql.NullString, err error)
 	Logger() *logrus.Entry
 	GetConnectionID() string
 }
@@ -190,6 +192,11 @@ func (c *BaseConn) Transact(qs ...string) (*driverV2.TxResponse, error) {
 	return results, nil
 }
 func (c *BaseConn) QueryWithContext(ctx context.Context, query string, args ...interface{}) (column []string, row [][]sql.NullString, err error) {
+	return c.QueryWithLimit(ctx, query, 0, args...)
+}
+
+// QueryWithLimit stops reading the result after limit rows, 0 means no limit.
+func (c *BaseConn) QueryWithLimit(ctx context.Context, query string, limit int, args ...interface{}) (column []string, row [][]sql.NullString, err error) {
 	rows, err := c.conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		c.Logger().Errorf("query sql failed; host: %s, port: %s, user: %s, query: %s, error: %s\n",
@@ -200,6 +207,35 @@ func (c *BaseConn) QueryWithContext(ctx context.Context, query string, args ...i
 			c.host, c.port, c.user, query)
 	}
 	defer rows.Close()
+	return c.readRows(rows, limit)
+}
+
+// QueryReadOnly runs the query like QueryWithLimit in a read-only transaction which is rolled back after the
+// rows are read, so the query can not write data even by the stored functions it calls.
+func (c *BaseConn) QueryReadOnly(ctx context.Context, query string, limit int, args ...interface{}) (column []string, row [][]sql.NullString, err error) {
+	tx, err := c.conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, errors.New(errors.ConnectRemoteDatabaseError, err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			c.Logger().Errorf("rollback read-only transaction failed, error: %v", err)
+		}
+	}()
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		c.Logger().Errorf("read-only query sql failed; host: %s, port: %s, user: %s, query: %s, error: %s\n",
+			c.host, c.port, c.user, query, err.Error())
+		return nil, nil, errors.New(errors.ConnectRemoteDatabaseError, err)
+	}
+	c.Logger().Infof("read-only query sql success; host: %s, port: %s, user: %s, query: %s\n",
+		c.host, c.port, c.user, query)
+	defer rows.Close()
+	return c.readRows(rows, limit)
+}
+
+// readRows reads at most limit rows, 0 means no limit.
+func (c *BaseConn) readRows(rows *sql.Rows, limit int) (column []string, row [][]sql.NullString, err error) {
 	columns, err := rows.Columns()
 	if err != nil {
 		// unknown error
@@ -208,6 +244,9 @@ func (c *BaseConn) QueryWithContext(ctx context.Context, query string, args ...i
 	}
 	result := make([][]sql.NullString, 0)
 	for rows.Next() {
+		if limit > 0 && len(result) >= limit {
+			break
+		}
 		buf := make([]interface{}, len(columns))
 		data := make([]sql.NullString, len(columns))
 		for i := range buf {
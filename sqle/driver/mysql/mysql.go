@@ -6,6 +6,7 @@ import (
 	_driver "database/sql/driver"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
 	"github.com/actiontech/sqle/sqle/driver"
@@ -19,7 +20,9 @@ import (
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/metrics"
 	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/tracing"
 	"github.com/pingcap/parser/ast"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -78,6 +81,18 @@ func NewInspect(log *logrus.Entry, cfg *driverV2.Config) (*MysqlDriverImpl, erro
 	return inspect, nil
 }
 
+// NewInspectWithContext creates the inspector auditing with the schemas and tables in the context instead of an
+// instance, such as the context loaded from a schema snapshot. The rules checking the table are not skipped as the
+// offline audit does, the rules requiring the connection get session.ErrNoExecutor instead. The explain pre-check
+// should not be enabled.
+func NewInspectWithContext(log *logrus.Entry, cfg *driverV2.Config, ctx *session.Context) *MysqlDriverImpl {
+	var inspect = &MysqlDriverImpl{}
+	inspect.initializeInspectWithoutConn(log, cfg)
+	inspect.Ctx = ctx
+	inspect.isOfflineAudit = false
+	return inspect
+}
+
 func (inspect *MysqlDriverImpl) initializeInspectWithConn(conn *executor.Executor, log *logrus.Entry, cfg *driverV2.Config) {
 	inspect.log = log
 	inspect.isConnected = true
@@ -237,8 +252,40 @@ func (i *MysqlDriverImpl) onlineddlWithGhost(query string) (bool, error) {
 	if err != nil {
 		return false, errors.Wrap(err, "get table size")
 	}
+	if int64(tableSize) <= i.cnf.DDLGhostMinSize {
+		return false, nil
+	}
 
-	return int64(tableSize) > i.cnf.DDLGhostMinSize, nil
+	createTableStmt, exist, err := i.Ctx.GetCreateTableStmt(stmt.Table)
+	if err != nil {
+		return false, errors.Wrap(err, "get create table statement")
+	}
+	if exist {
+		// the INSTANT ALTER TABLE only modifies the metadata, it is cheap even on the large table
+		if prediction := i.predictAlterTable(stmt, createTableStmt); prediction != nil && prediction.IsLightweight() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// predictAlterTable predicts the algorithm and the lock level of the ALTER TABLE by the version of the server,
+// it returns nil if the version is unknown.
+func (i *MysqlDriverImpl) predictAlterTable(stmt *ast.AlterTableStmt, createTableStmt *ast.CreateTableStmt) *onlineddl.AlterTablePrediction {
+	version, err := i.Ctx.GetSystemVariable("version")
+	if err != nil {
+		i.Logger().Warnf("get version failed when predict alter table, err: %v", err)
+		return nil
+	}
+	if version == "" {
+		return nil
+	}
+	prediction, err := onlineddl.PredictAlterTable(version, stmt, createTableStmt)
+	if err != nil {
+		i.Logger().Warnf("predict alter table failed, err: %v", err)
+		return nil
+	}
+	return prediction
 }
 
 func (i *MysqlDriverImpl) Tx(ctx context.Context, queries ...string) (*driverV2.TxResponse, error) {
@@ -407,7 +454,13 @@ func (i *MysqlDriverImpl) audit(ctx context.Context, sql string) (*driverV2.Audi
 			Node: nodes[0],
 		}
 
-		if err := handler.Func(input); err != nil {
+		begin := time.Now()
+		_, span := tracing.Start(ctx, "rule "+rule.Name, tracing.WithAttributes(tracing.Attr("rule", rule.Name)))
+		err := handler.Func(input)
+		span.RecordError(err)
+		span.End()
+		metrics.ObserveSince(metrics.RuleEvaluationDuration, begin, driverV2.DriverTypeMySQL, rule.Name)
+		if err != nil {
 			i.result.AddResultWithError(rule.Level, rule.Name, err.Error(), true, plocale.Bundle.LocalizeAll(handler.Message))
 			i.Logger().Errorf("rule_desc_name=%v rule_desc=%v err:%v", rule.Name, rule.I18nRuleInfo[i18nPkg.DefaultLang].Desc, err.Error())
 		}
@@ -727,4 +780,15 @@ func (p *PluginProcessor) Stop() error {
 
 func init() {
 	driver.BuiltInPluginProcessors[driverV2.DriverTypeMySQL] = &PluginProcessor{}
+	driver.ChunkedDMLPlugins[driverV2.DriverTypeMySQL] = struct{}{}
+}
+
+// LoadLocaleDir loads the extra locale files in dir and localizes the rules by them,
+// it should be called before the plugin manager starts.
+func LoadLocaleDir(dir string) error {
+	if err := plocale.LoadLocaleDir(dir); err != nil {
+		return err
+	}
+	rulepkg.LocalizeRules(plocale.Bundle)
+	return nil
 }
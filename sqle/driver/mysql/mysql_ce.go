@@ -11,7 +11,7 @@ import (
 )
 
 func (i *MysqlDriverImpl) Query(ctx context.Context, sql string, conf *driverV2.QueryConf) (*driverV2.QueryResult, error) {
-	return nil, fmt.Errorf("only support Query in enterprise edition")
+	return i.queryReadOnly(ctx, sql, conf)
 }
 
 func (i *MysqlDriverImpl) GetDatabaseDiffModifySQL(ctx context.Context, calibratedDSN *driverV2.DSN, objInfos []*driverV2.DatabasCompareSchemaInfo) ([]*driverV2.DatabaseDiffModifySQLResult, error) {
@@ -68,6 +68,30 @@ func TestInspect_onlineddlWithGhost(t *testing.T) {
 			wantErr: false,
 		},
 
+		{
+			name: "alter stmt(true); config onlineddl(true); table size enough(true); instant",
+			setUp: func(i *MysqlDriverImpl) *MysqlDriverImpl {
+				i.Ctx.Schemas()["exist_db"].Tables["exist_tb_1"].Size = 17
+				i.Ctx.AddSystemVariable("version", "8.0.30")
+				return i
+			},
+			args:    args{query: "alter table exist_db.exist_tb_1 add column col1 varchar(100);"},
+			want:    false,
+			wantErr: false,
+		},
+
+		{
+			name: "alter stmt(true); config onlineddl(true); table size enough(true); add index",
+			setUp: func(i *MysqlDriverImpl) *MysqlDriverImpl {
+				i.Ctx.Schemas()["exist_db"].Tables["exist_tb_1"].Size = 17
+				i.Ctx.AddSystemVariable("version", "8.0.30")
+				return i
+			},
+			args:    args{query: "alter table exist_db.exist_tb_1 add index idx_v2(v2);"},
+			want:    true,
+			wantErr: false,
+		},
+
 		{
 			name: "alter stmt(true); config onlineddl(true); table size enough(false)",
 			setUp: func(i *MysqlDriverImpl) *MysqlDriverImpl {
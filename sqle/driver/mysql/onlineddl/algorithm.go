@@ -0,0 +1,540 @@
+package onlineddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+)
+
+// The prediction follows the InnoDB online DDL support of MySQL,
+// see https://dev.mysql.com/doc/refman/8.0/en/innodb-online-ddl-operations.html
+// and https://dev.mysql.com/doc/refman/5.7/en/innodb-online-ddl-operations.html.
+var (
+	version56   = semver.MustParse("5.6.0")
+	version57   = semver.MustParse("5.7.0")
+	version80   = semver.MustParse("8.0.0")
+	version8012 = semver.MustParse("8.0.12")
+	version8028 = semver.MustParse("8.0.28")
+	version8029 = semver.MustParse("8.0.29")
+)
+
+// ClausePrediction is the predicted way MySQL runs a clause of ALTER TABLE.
+type ClausePrediction struct {
+	Clause    string
+	Algorithm ast.AlgorithmType
+	// Lock is the least restrictive lock MySQL permits, LOCK=NONE means the concurrent DML is permitted
+	Lock          ast.LockType
+	RebuildsTable bool
+	// Assumed is true if the clause can not be predicted, it is assumed to be run as COPY
+	Assumed bool
+}
+
+func (c *ClausePrediction) BlocksDML() bool {
+	return c.Lock >= ast.LockTypeShared
+}
+
+// AlterTablePrediction is the predicted way MySQL runs an ALTER TABLE, the statement is run as the least efficient
+// algorithm and the most restrictive lock of its clauses.
+type AlterTablePrediction struct {
+	Version       *semver.Version
+	Clauses       []*ClausePrediction
+	Algorithm     ast.AlgorithmType
+	Lock          ast.LockType
+	RebuildsTable bool
+
+	// RequestedAlgorithm and RequestedLock are the ALGORITHM and LOCK clauses of the statement
+	RequestedAlgorithm ast.AlgorithmType
+	RequestedLock      ast.LockType
+}
+
+func (p *AlterTablePrediction) BlocksDML() bool {
+	return p.Lock >= ast.LockTypeShared
+}
+
+// IsLightweight returns true if the ALTER TABLE only modifies the metadata by ALGORITHM=INSTANT, it is unnecessary
+// to run it by an online schema change tool. The INPLACE ALTER TABLE without rebuilding the table, e.g. ADD INDEX,
+// still scans the table and delays the replicas on the large table.
+func (p *AlterTablePrediction) IsLightweight() bool {
+	return p.Algorithm == ast.AlgorithmTypeInstant
+}
+
+// IsRequestUnsupported returns true if the requested ALGORITHM or LOCK is more efficient than MySQL supports for the
+// statement, MySQL rejects the statement in this case.
+func (p *AlterTablePrediction) IsRequestUnsupported() bool {
+	if p.RequestedAlgorithm != ast.AlgorithmTypeDefault && p.RequestedAlgorithm > p.Algorithm {
+		return true
+	}
+	if p.RequestedLock != ast.LockTypeDefault && p.RequestedLock < p.Lock {
+		return true
+	}
+	return false
+}
+
+// ParseMySQLVersion parses the value of the system variable `version`, such as "8.0.32" and "5.7.44-log".
+func ParseMySQLVersion(version string) (*semver.Version, error) {
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		return nil, fmt.Errorf("unsupported MariaDB version %s", version)
+	}
+	if idx := strings.IndexAny(version, "-+"); idx >= 0 {
+		version = version[:idx]
+	}
+	return semver.NewVersion(version)
+}
+
+// PredictAlterTable predicts how the MySQL server of `version` runs the ALTER TABLE on the table created by
+// `createTable`, the clauses modifying the existing columns are assumed to be run as COPY if `createTable` is nil.
+func PredictAlterTable(version string, stmt *ast.AlterTableStmt, createTable *ast.CreateTableStmt) (*AlterTablePrediction, error) {
+	v, err := ParseMySQLVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	prediction := &AlterTablePrediction{
+		Version:            v,
+		Algorithm:          ast.AlgorithmTypeInstant,
+		Lock:               ast.LockTypeNone,
+		RequestedAlgorithm: ast.AlgorithmTypeDefault,
+		RequestedLock:      ast.LockTypeDefault,
+	}
+	for _, spec := range stmt.Specs {
+		switch spec.Tp {
+		case ast.AlterTableAlgorithm:
+			prediction.RequestedAlgorithm = spec.Algorithm
+		case ast.AlterTableLock:
+			prediction.RequestedLock = spec.LockType
+		}
+	}
+
+	p := newAlterTablePredictor(v, stmt, createTable)
+	// ALGORITHM=INPLACE and ALGORITHM=COPY disable the INSTANT algorithm
+	p.allowInstant = prediction.RequestedAlgorithm != ast.AlgorithmTypeInplace &&
+		prediction.RequestedAlgorithm != ast.AlgorithmTypeCopy
+	for _, spec := range stmt.Specs {
+		clause := p.predictSpec(spec)
+		if clause == nil {
+			continue
+		}
+		clause.Clause = restoreAlterTableSpec(spec)
+		prediction.Clauses = append(prediction.Clauses, clause)
+		if clause.Algorithm < prediction.Algorithm {
+			prediction.Algorithm = clause.Algorithm
+		}
+		if clause.Lock > prediction.Lock {
+			prediction.Lock = clause.Lock
+		}
+		prediction.RebuildsTable = prediction.RebuildsTable || clause.RebuildsTable
+	}
+	// ALGORITHM=COPY rebuilds the table even if all the clauses support a more efficient algorithm
+	if prediction.RequestedAlgorithm == ast.AlgorithmTypeCopy {
+		prediction.Algorithm = ast.AlgorithmTypeCopy
+		prediction.RebuildsTable = true
+		if prediction.Lock < ast.LockTypeShared {
+			prediction.Lock = ast.LockTypeShared
+		}
+	}
+	return prediction, nil
+}
+
+func restoreAlterTableSpec(spec *ast.AlterTableSpec) string {
+	var builder strings.Builder
+	if err := spec.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &builder)); err != nil {
+		return ""
+	}
+	return builder.String()
+}
+
+type alterTablePredictor struct {
+	version      *semver.Version
+	stmt         *ast.AlterTableStmt
+	allowInstant bool
+
+	// the following fields are empty if the create table statement is unknown
+	columns       map[string] /*lower column name*/ *ast.ColumnDef
+	tableCharset  string
+	tableEngine   string
+	hasFulltext   bool
+	rowCompressed bool
+	tableKnown    bool
+}
+
+func newAlterTablePredictor(version *semver.Version, stmt *ast.AlterTableStmt, createTable *ast.CreateTableStmt) *alterTablePredictor {
+	p := &alterTablePredictor{
+		version: version,
+		stmt:    stmt,
+		columns: map[string]*ast.ColumnDef{},
+	}
+	if createTable == nil {
+		return p
+	}
+	p.tableKnown = true
+	for _, col := range createTable.Cols {
+		p.columns[col.Name.Name.L] = col
+	}
+	for _, op := range createTable.Options {
+		switch op.Tp {
+		case ast.TableOptionCharset:
+			p.tableCharset = op.StrValue
+		case ast.TableOptionEngine:
+			p.tableEngine = op.StrValue
+		case ast.TableOptionRowFormat:
+			p.rowCompressed = op.UintValue == ast.RowFormatCompressed
+		}
+	}
+	for _, constraint := range createTable.Constraints {
+		if constraint.Tp == ast.ConstraintFulltext {
+			p.hasFulltext = true
+		}
+	}
+	return p
+}
+
+func (p *alterTablePredictor) supportInstant(minVersion *semver.Version) bool {
+	return p.allowInstant && !p.version.LessThan(minVersion)
+}
+
+func instant() *ClausePrediction {
+	return &ClausePrediction{Algorithm: ast.AlgorithmTypeInstant, Lock: ast.LockTypeNone}
+}
+
+func inplace(rebuildsTable bool) *ClausePrediction {
+	return &ClausePrediction{Algorithm: ast.AlgorithmTypeInplace, Lock: ast.LockTypeNone, RebuildsTable: rebuildsTable}
+}
+
+func copyTable() *ClausePrediction {
+	return &ClausePrediction{Algorithm: ast.AlgorithmTypeCopy, Lock: ast.LockTypeShared, RebuildsTable: true}
+}
+
+func assumed() *ClausePrediction {
+	c := copyTable()
+	c.Assumed = true
+	return c
+}
+
+// metadataOnly is the prediction of the clause which only modifies the metadata of the table since `instantVersion`.
+func (p *alterTablePredictor) metadataOnly(instantVersion *semver.Version) *ClausePrediction {
+	if p.supportInstant(instantVersion) {
+		return instant()
+	}
+	return inplace(false)
+}
+
+// worse returns the less efficient prediction of the two.
+func worse(a, b *ClausePrediction) *ClausePrediction {
+	if a == nil {
+		return b
+	}
+	if b.Algorithm < a.Algorithm {
+		a.Algorithm = b.Algorithm
+	}
+	if b.Lock > a.Lock {
+		a.Lock = b.Lock
+	}
+	a.RebuildsTable = a.RebuildsTable || b.RebuildsTable
+	a.Assumed = a.Assumed || b.Assumed
+	return a
+}
+
+func (p *alterTablePredictor) predictSpec(spec *ast.AlterTableSpec) *ClausePrediction {
+	switch spec.Tp {
+	case ast.AlterTableAlgorithm, ast.AlterTableLock:
+		return nil
+	}
+	// online DDL is introduced in MySQL 5.6
+	if p.version.LessThan(version56) {
+		return copyTable()
+	}
+
+	switch spec.Tp {
+	case ast.AlterTableAddColumns:
+		var prediction *ClausePrediction
+		for _, col := range spec.NewColumns {
+			prediction = worse(prediction, p.predictAddColumn(col, spec.Position))
+		}
+		if prediction == nil {
+			return assumed()
+		}
+		return prediction
+	case ast.AlterTableDropColumn:
+		if p.supportInstant(version8029) {
+			return instant()
+		}
+		return inplace(true)
+	case ast.AlterTableRenameColumn:
+		return p.metadataOnly(version8028)
+	case ast.AlterTableAlterColumn:
+		// SET DEFAULT and DROP DEFAULT
+		return p.metadataOnly(version80)
+	case ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+		return p.predictModifyColumn(spec)
+	case ast.AlterTableAddConstraint:
+		return p.predictAddConstraint(spec.Constraint)
+	case ast.AlterTableDropIndex, ast.AlterTableDropForeignKey:
+		return inplace(false)
+	case ast.AlterTableRenameIndex, ast.AlterTableIndexInvisible:
+		return p.metadataOnly(version80)
+	case ast.AlterTableDropPrimaryKey:
+		// dropping the primary key without adding a new one is only supported by COPY
+		for _, other := range p.stmt.Specs {
+			if other.Tp == ast.AlterTableAddConstraint && other.Constraint != nil &&
+				other.Constraint.Tp == ast.ConstraintPrimaryKey {
+				return inplace(true)
+			}
+		}
+		return copyTable()
+	case ast.AlterTableRenameTable:
+		return p.metadataOnly(version80)
+	case ast.AlterTableOption:
+		var prediction *ClausePrediction
+		for _, op := range spec.Options {
+			prediction = worse(prediction, p.predictTableOption(op))
+		}
+		if prediction == nil {
+			return assumed()
+		}
+		return prediction
+	case ast.AlterTableForce:
+		return inplace(true)
+	default:
+		return assumed()
+	}
+}
+
+func (p *alterTablePredictor) predictAddColumn(col *ast.ColumnDef, position *ast.ColumnPosition) *ClausePrediction {
+	for _, op := range col.Options {
+		switch op.Tp {
+		case ast.ColumnOptionGenerated:
+			if op.Stored {
+				return copyTable()
+			}
+			return p.metadataOnly(version80)
+		case ast.ColumnOptionAutoIncrement:
+			prediction := inplace(true)
+			prediction.Lock = ast.LockTypeShared
+			return prediction
+		case ast.ColumnOptionPrimaryKey, ast.ColumnOptionUniqKey, ast.ColumnOptionFulltext:
+			return worse(inplace(true), p.predictAddConstraint(&ast.Constraint{Tp: columnOptionConstraint[op.Tp]}))
+		}
+	}
+	// INSTANT ADD COLUMN is supported since 8.0.12, and only for the last column before 8.0.29
+	isLast := position == nil || position.Tp == ast.ColumnPositionNone
+	if p.supportInstant(version8012) && !p.hasFulltext && !p.rowCompressed &&
+		(isLast || !p.version.LessThan(version8029)) {
+		return instant()
+	}
+	return inplace(true)
+}
+
+var columnOptionConstraint = map[ast.ColumnOptionType]ast.ConstraintType{
+	ast.ColumnOptionPrimaryKey: ast.ConstraintPrimaryKey,
+	ast.ColumnOptionUniqKey:    ast.ConstraintUniq,
+	ast.ColumnOptionFulltext:   ast.ConstraintFulltext,
+}
+
+func (p *alterTablePredictor) predictAddConstraint(constraint *ast.Constraint) *ClausePrediction {
+	if constraint == nil {
+		return assumed()
+	}
+	switch constraint.Tp {
+	case ast.ConstraintPrimaryKey:
+		return inplace(true)
+	case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		return inplace(false)
+	case ast.ConstraintFulltext:
+		// the first FULLTEXT index rebuilds the table to add the FTS_DOC_ID column
+		prediction := inplace(p.tableKnown && !p.hasFulltext)
+		prediction.Lock = ast.LockTypeShared
+		return prediction
+	case ast.ConstraintSpatial:
+		if p.version.LessThan(version57) {
+			return copyTable()
+		}
+		prediction := inplace(false)
+		prediction.Lock = ast.LockTypeShared
+		return prediction
+	case ast.ConstraintForeignKey:
+		// INPLACE is only supported when foreign_key_checks is disabled
+		return copyTable()
+	default:
+		return assumed()
+	}
+}
+
+func (p *alterTablePredictor) predictTableOption(op *ast.TableOption) *ClausePrediction {
+	switch op.Tp {
+	case ast.TableOptionEngine:
+		if p.tableEngine != "" && !strings.EqualFold(p.tableEngine, op.StrValue) {
+			return copyTable()
+		}
+		return inplace(true)
+	case ast.TableOptionRowFormat, ast.TableOptionKeyBlockSize:
+		return inplace(true)
+	case ast.TableOptionCharset, ast.TableOptionCollate:
+		if op.UintValue == ast.TableOptionCharsetWithConvertTo {
+			return copyTable()
+		}
+		prediction := inplace(true)
+		prediction.Lock = ast.LockTypeShared
+		return prediction
+	case ast.TableOptionAutoIncrement, ast.TableOptionComment, ast.TableOptionStatsPersistent,
+		ast.TableOptionStatsAutoRecalc, ast.TableOptionStatsSamplePages:
+		return inplace(false)
+	default:
+		return assumed()
+	}
+}
+
+func (p *alterTablePredictor) predictModifyColumn(spec *ast.AlterTableSpec) *ClausePrediction {
+	if len(spec.NewColumns) == 0 {
+		return assumed()
+	}
+	newCol := spec.NewColumns[0]
+	oldName := newCol.Name.Name.L
+	if spec.Tp == ast.AlterTableChangeColumn && spec.OldColumnName != nil {
+		oldName = spec.OldColumnName.Name.L
+	}
+	oldCol, ok := p.columns[oldName]
+	if !ok {
+		return assumed()
+	}
+
+	var prediction *ClausePrediction
+	switch {
+	case p.isSameType(oldCol.Tp, newCol.Tp):
+		renamed := oldName != newCol.Name.Name.L
+		if isNotNull(oldCol) != isNotNull(newCol) {
+			prediction = inplace(true)
+		} else if renamed {
+			prediction = p.metadataOnly(version8028)
+		} else {
+			prediction = p.metadataOnly(version80)
+		}
+	case p.isVarcharExtension(oldCol.Tp, newCol.Tp):
+		prediction = inplace(false)
+	case isEnumOrSetAppending(oldCol.Tp, newCol.Tp):
+		prediction = p.metadataOnly(version80)
+	default:
+		return copyTable()
+	}
+	// reordering the columns rebuilds the table
+	if spec.Position != nil && spec.Position.Tp != ast.ColumnPositionNone {
+		prediction = worse(prediction, inplace(true))
+	}
+	return prediction
+}
+
+func isNotNull(col *ast.ColumnDef) bool {
+	for _, op := range col.Options {
+		if op.Tp == ast.ColumnOptionNotNull || op.Tp == ast.ColumnOptionPrimaryKey {
+			return true
+		}
+	}
+	return false
+}
+
+func isStringType(tp byte) bool {
+	switch tp {
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob, mysql.TypeMediumBlob,
+		mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeEnum, mysql.TypeSet:
+		return true
+	}
+	return false
+}
+
+func isIntegerType(tp byte) bool {
+	switch tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		return true
+	}
+	return false
+}
+
+func (p *alterTablePredictor) columnCharset(tp *types.FieldType) string {
+	if tp.Charset != "" {
+		return strings.ToLower(tp.Charset)
+	}
+	if p.tableCharset != "" {
+		return strings.ToLower(p.tableCharset)
+	}
+	return mysql.DefaultCharset
+}
+
+func (p *alterTablePredictor) isSameType(oldTp, newTp *types.FieldType) bool {
+	if oldTp.Tp != newTp.Tp {
+		return false
+	}
+	// the length of the integer types is the display width
+	if !isIntegerType(oldTp.Tp) && newTp.Flen != types.UnspecifiedLength && oldTp.Flen != newTp.Flen {
+		return false
+	}
+	if newTp.Decimal != types.UnspecifiedLength && oldTp.Decimal != newTp.Decimal {
+		return false
+	}
+	if mysql.HasUnsignedFlag(oldTp.Flag) != mysql.HasUnsignedFlag(newTp.Flag) {
+		return false
+	}
+	if strings.Join(oldTp.Elems, ",") != strings.Join(newTp.Elems, ",") {
+		return false
+	}
+	if isStringType(oldTp.Tp) {
+		if p.columnCharset(oldTp) != p.columnCharset(newTp) {
+			return false
+		}
+		if newTp.Collate != "" && !strings.EqualFold(oldTp.Collate, newTp.Collate) {
+			return false
+		}
+	}
+	return true
+}
+
+// isVarcharExtension returns true if the VARCHAR column is extended in place, it is supported since 5.7 when the
+// number of the length bytes is unchanged, that is both lengths are less than 256 bytes or not.
+func (p *alterTablePredictor) isVarcharExtension(oldTp, newTp *types.FieldType) bool {
+	if p.version.LessThan(version57) {
+		return false
+	}
+	if oldTp.Tp != mysql.TypeVarchar || newTp.Tp != mysql.TypeVarchar || newTp.Flen < oldTp.Flen {
+		return false
+	}
+	cs := p.columnCharset(oldTp)
+	if cs != p.columnCharset(newTp) {
+		return false
+	}
+	maxLen := 4
+	if desc, err := charset.GetCharsetDesc(cs); err == nil {
+		maxLen = desc.Maxlen
+	}
+	return (oldTp.Flen*maxLen < 256) == (newTp.Flen*maxLen < 256)
+}
+
+// isEnumOrSetAppending returns true if the members are appended to the end of the ENUM or SET column and the storage
+// size of the column is unchanged.
+func isEnumOrSetAppending(oldTp, newTp *types.FieldType) bool {
+	if oldTp.Tp != newTp.Tp || (oldTp.Tp != mysql.TypeEnum && oldTp.Tp != mysql.TypeSet) {
+		return false
+	}
+	if len(newTp.Elems) < len(oldTp.Elems) {
+		return false
+	}
+	for i, elem := range oldTp.Elems {
+		if elem != newTp.Elems[i] {
+			return false
+		}
+	}
+	if oldTp.Tp == mysql.TypeEnum {
+		return (len(oldTp.Elems) <= 255) == (len(newTp.Elems) <= 255)
+	}
+	return setStorageBytes(len(oldTp.Elems)) == setStorageBytes(len(newTp.Elems))
+}
+
+func setStorageBytes(members int) int {
+	bytes := (members + 7) / 8
+	if bytes > 4 {
+		return 8
+	}
+	return bytes
+}
@@ -0,0 +1,100 @@
+package onlineddl
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCreateTable = "CREATE TABLE `t1` (" +
+	"`id` bigint(20) unsigned NOT NULL AUTO_INCREMENT," +
+	"`v1` varchar(32) DEFAULT NULL," +
+	"`v2` varchar(255) NOT NULL DEFAULT ''," +
+	"`v3` int(11) DEFAULT NULL," +
+	"`v4` enum('a','b') DEFAULT NULL," +
+	"PRIMARY KEY (`id`)," +
+	"KEY `idx_v1` (`v1`)" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+
+func TestPredictAlterTable(t *testing.T) {
+	createTable, err := parser.New().ParseOneStmt(testCreateTable, "", "")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		version       string
+		alter         string
+		algorithm     ast.AlgorithmType
+		blocksDML     bool
+		rebuildsTable bool
+		unsupported   bool
+	}{
+		{"8.0.30", "alter table t1 add column v5 int", ast.AlgorithmTypeInstant, false, false, false},
+		{"8.0.30", "alter table t1 add column v5 int after id", ast.AlgorithmTypeInstant, false, false, false},
+		{"8.0.20-log", "alter table t1 add column v5 int after id", ast.AlgorithmTypeInplace, false, true, false},
+		{"8.0.20", "alter table t1 add column v5 int", ast.AlgorithmTypeInstant, false, false, false},
+		{"5.7.44-log", "alter table t1 add column v5 int", ast.AlgorithmTypeInplace, false, true, false},
+		{"5.5.62", "alter table t1 add index idx_v2(v2)", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 add column v5 int auto_increment", ast.AlgorithmTypeInplace, true, true, false},
+		{"8.0.30", "alter table t1 add column v5 int as (v3 + 1) stored", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 drop column v3", ast.AlgorithmTypeInstant, false, false, false},
+		{"8.0.27", "alter table t1 drop column v3", ast.AlgorithmTypeInplace, false, true, false},
+		{"8.0.28", "alter table t1 rename column v3 to v5", ast.AlgorithmTypeInstant, false, false, false},
+		{"8.0.30", "alter table t1 alter column v3 set default 1", ast.AlgorithmTypeInstant, false, false, false},
+		{"5.7.44", "alter table t1 alter column v3 set default 1", ast.AlgorithmTypeInplace, false, false, false},
+		{"8.0.30", "alter table t1 add index idx_v2(v2)", ast.AlgorithmTypeInplace, false, false, false},
+		{"8.0.30", "alter table t1 add fulltext index idx_v2(v2)", ast.AlgorithmTypeInplace, true, true, false},
+		{"8.0.30", "alter table t1 drop index idx_v1", ast.AlgorithmTypeInplace, false, false, false},
+		{"8.0.30", "alter table t1 add foreign key (v3) references t2(id)", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 drop primary key", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 drop primary key, add primary key (id, v2)", ast.AlgorithmTypeInplace, false, true, false},
+		// the display width of the integer is ignored
+		{"8.0.30", "alter table t1 modify column v3 int default 1", ast.AlgorithmTypeInstant, false, false, false},
+		{"8.0.30", "alter table t1 modify column v3 bigint", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 modify column v3 int not null", ast.AlgorithmTypeInplace, false, true, false},
+		{"8.0.30", "alter table t1 modify column v3 int after id", ast.AlgorithmTypeInplace, false, true, false},
+		// 32*4 bytes and 63*4 bytes both use 1 length byte
+		{"8.0.30", "alter table t1 modify column v1 varchar(63)", ast.AlgorithmTypeInplace, false, false, false},
+		{"8.0.30", "alter table t1 modify column v1 varchar(64)", ast.AlgorithmTypeCopy, true, true, false},
+		{"5.6.51", "alter table t1 modify column v1 varchar(63)", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 modify column v1 varchar(16)", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 modify column v4 enum('a','b','c')", ast.AlgorithmTypeInstant, false, false, false},
+		{"8.0.30", "alter table t1 modify column v4 enum('b','a')", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 change column v3 v5 int(11)", ast.AlgorithmTypeInstant, false, false, false},
+		{"8.0.30", "alter table t1 modify column not_exist_col int", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 engine=InnoDB", ast.AlgorithmTypeInplace, false, true, false},
+		{"8.0.30", "alter table t1 engine=MyISAM", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 convert to character set utf8", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 auto_increment=100", ast.AlgorithmTypeInplace, false, false, false},
+		{"8.0.30", "alter table t1 rename to t2", ast.AlgorithmTypeInstant, false, false, false},
+		// the statement is run as the least efficient clause
+		{"8.0.30", "alter table t1 add column v5 int, modify column v3 bigint", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 add column v5 int, algorithm=inplace", ast.AlgorithmTypeInplace, false, true, false},
+		{"8.0.30", "alter table t1 add index idx_v2(v2), algorithm=copy", ast.AlgorithmTypeCopy, true, true, false},
+		{"8.0.30", "alter table t1 modify column v3 bigint, algorithm=inplace", ast.AlgorithmTypeCopy, true, true, true},
+		{"8.0.30", "alter table t1 add fulltext index idx_v2(v2), lock=none", ast.AlgorithmTypeInplace, true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.alter, func(t *testing.T) {
+			stmt, err := parser.New().ParseOneStmt(tt.alter, "", "")
+			assert.NoError(t, err)
+			prediction, err := PredictAlterTable(tt.version, stmt.(*ast.AlterTableStmt), createTable.(*ast.CreateTableStmt))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.algorithm, prediction.Algorithm)
+			assert.Equal(t, tt.blocksDML, prediction.BlocksDML())
+			assert.Equal(t, tt.rebuildsTable, prediction.RebuildsTable)
+			assert.Equal(t, tt.unsupported, prediction.IsRequestUnsupported())
+			assert.Equal(t, tt.algorithm == ast.AlgorithmTypeInstant, prediction.IsLightweight())
+		})
+	}
+}
+
+func TestParseMySQLVersion(t *testing.T) {
+	v, err := ParseMySQLVersion("5.7.44-48-log")
+	assert.NoError(t, err)
+	assert.Equal(t, "5.7.44", v.String())
+
+	_, err = ParseMySQLVersion("10.5.8-MariaDB")
+	assert.Error(t, err)
+}
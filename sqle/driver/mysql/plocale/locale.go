@@ -4,6 +4,7 @@ import (
 	"embed"
 
 	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/log"
 )
 
@@ -19,3 +20,13 @@ func init() {
 	}
 	Bundle = b
 }
+
+// LoadLocaleDir reloads Bundle with the extra locale files in dir, the rules should be localized again after it.
+func LoadLocaleDir(dir string) error {
+	b, err := locale.NewBundleWithDir(localeFS, dir)
+	if err != nil {
+		return err
+	}
+	Bundle = b
+	return nil
+}
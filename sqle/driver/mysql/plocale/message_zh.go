@@ -15,6 +15,10 @@ var (
 	AdvisorIndexTypeComposite  = &i18n.Message{ID: "AdvisorIndexTypeComposite", Other: "复合"}
 	AdvisorIndexTypeSingle     = &i18n.Message{ID: "AdvisorIndexTypeSingle", Other: "单列"}
 	SQLCostChartOnlySupportDML = &i18n.Message{ID: "SQLCostChartOnlySupportDML", Other: "该SQL不是DML，无需分析SQL执行计划代价趋势"}
+
+	WorkloadIndexCoveredByExisting = &i18n.Message{ID: "WorkloadIndexCoveredByExisting", Other: "已有索引%s覆盖了该索引的列"}
+	WorkloadIndexExceedCountLimit  = &i18n.Message{ID: "WorkloadIndexExceedCountLimit", Other: "表%s的索引数量将超过上限%d，该索引的收益低于已采纳的索引"}
+	WorkloadIndexWriteOutweighRead = &i18n.Message{ID: "WorkloadIndexWriteOutweighRead", Other: "需要维护该索引的写入负载(%.2f)超过了其服务的查询负载(%.2f)"}
 )
 
 // analysis
@@ -96,6 +100,18 @@ var (
 	NotSupportExceedMaxRowsRollback           = &i18n.Message{ID: "NotSupportExceedMaxRowsRollback", Other: "预计影响行数超过配置的最大值，不生成回滚语句"}
 )
 
+// rewrite
+var (
+	RewriteInSubqueryToJoinDesc            = &i18n.Message{ID: "RewriteInSubqueryToJoinDesc", Other: "将IN子查询改写为与去重派生表的JOIN，避免子查询按外层的行逐行执行"}
+	RewriteOrToUnionAllDesc                = &i18n.Message{ID: "RewriteOrToUnionAllDesc", Other: "将不同列上的OR条件拆分为UNION ALL，每个分支都可以使用各自列上的索引，后面的分支排除了前面分支已返回的行"}
+	RewriteFuncOnColumnDesc                = &i18n.Message{ID: "RewriteFuncOnColumnDesc", Other: "将条件列上的函数或运算改写为对列本身的范围条件，使条件可以使用列上的索引"}
+	RewriteImplicitConversionDesc          = &i18n.Message{ID: "RewriteImplicitConversionDesc", Other: "按列的类型改写比较的常量，避免隐式类型转换导致索引失效"}
+	RewriteImplicitConversionNonEquivalent = &i18n.Message{ID: "RewriteImplicitConversionNonEquivalent", Other: "字符串列与数字比较时按数值匹配（如'01'与1相等），改为字符串比较后这类值不再匹配"}
+	RewriteSelectAllDesc                   = &i18n.Message{ID: "RewriteSelectAllDesc", Other: "将SELECT *展开为表当前的列清单，表结构变更不会再改变查询结果的列"}
+	RewriteKeysetPaginationDesc            = &i18n.Message{ID: "RewriteKeysetPaginationDesc", Other: "将大偏移量的分页改写为按排序键定位的游标分页，不再扫描并丢弃偏移量之前的行"}
+	RewriteKeysetPaginationNonEquivalent   = &i18n.Message{ID: "RewriteKeysetPaginationNonEquivalent", Other: "占位符需传入上一页最后一行的排序键值，第一页不带该条件查询；只能逐页翻页，不能直接跳转到指定页；排序键为NULL的行不会被返回"}
+)
+
 // rule Category
 var (
 	RuleTypeGlobalConfig             = &i18n.Message{ID: "RuleTypeGlobalConfig", Other: "全局配置"}
@@ -269,6 +285,10 @@ var (
 	DDLCheckAlterTableNeedMergeDesc                              = &i18n.Message{ID: "DDLCheckAlterTableNeedMergeDesc", Other: "存在多条对同一个表的修改语句，建议合并成一个ALTER语句"}
 	DDLCheckAlterTableNeedMergeAnnotation                        = &i18n.Message{ID: "DDLCheckAlterTableNeedMergeAnnotation", Other: "避免多次 TABLE REBUILD 带来的消耗、以及对线上业务的影响"}
 	DDLCheckAlterTableNeedMergeMessage                           = &i18n.Message{ID: "DDLCheckAlterTableNeedMergeMessage", Other: "已存在对该表的修改语句，建议合并成一个ALTER语句"}
+	DDLCheckAlterTableAlgorithmDesc                              = &i18n.Message{ID: "DDLCheckAlterTableAlgorithmDesc", Other: "预测ALTER TABLE语句的执行算法和锁级别"}
+	DDLCheckAlterTableAlgorithmAnnotation                        = &i18n.Message{ID: "DDLCheckAlterTableAlgorithmAnnotation", Other: "根据数据源的MySQL版本，预测ALTER TABLE的各子句以INSTANT、INPLACE还是COPY算法执行、是否阻塞并发DML以及是否重建表，帮助评估变更代价；不阻塞DML且不重建表的变更不会使用gh-ost或pt-osc上线"}
+	DDLCheckAlterTableAlgorithmMessage                           = &i18n.Message{ID: "DDLCheckAlterTableAlgorithmMessage", Other: "预计在MySQL %v上以%v执行，各子句：%v"}
+	DDLCheckAlterTableAlgorithmUnsupportedMessage                = &i18n.Message{ID: "DDLCheckAlterTableAlgorithmUnsupportedMessage", Other: "语句指定的%v不被支持，MySQL将拒绝执行，预计只能以%v执行"}
 	DMLDisableSelectAllColumnDesc                                = &i18n.Message{ID: "DMLDisableSelectAllColumnDesc", Other: "不建议使用SELECT *"}
 	DMLDisableSelectAllColumnAnnotation                          = &i18n.Message{ID: "DMLDisableSelectAllColumnAnnotation", Other: "当表结构变更时，使用*通配符选择所有列将导致查询行为会发生更改，与业务期望不符；同时SELECT * 中的无用字段会带来不必要的磁盘I/O，以及网络开销，且无法覆盖索引进而回表，大幅度降低查询效率"}
 	DMLDisableSelectAllColumnMessage                             = &i18n.Message{ID: "DMLDisableSelectAllColumnMessage", Other: "不建议使用SELECT *"}
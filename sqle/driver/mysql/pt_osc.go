@@ -57,6 +57,11 @@ func (i *MysqlDriverImpl) generateOSCCommandLine(node ast.Node) (i18nPkg.I18nStr
 		return nil, err
 	}
 
+	// the INSTANT ALTER TABLE only modifies the metadata, it is cheap even on the large table
+	if prediction := i.predictAlterTable(stmt, createTableStmt); prediction != nil && prediction.IsLightweight() {
+		return nil, nil
+	}
+
 	// In almost all cases a PRIMARY KEY or UNIQUE INDEX needs to be present in the table.
 	// This is necessary because the tool creates a DELETE trigger to keep the new table
 	// updated while the process is running.
@@ -62,3 +62,30 @@ func runOSCCase(t *testing.T, desc string, sql, expect string) {
 	}
 	assert.Equal(t, expect, actual[i18nPkg.DefaultLang], desc)
 }
+
+func TestPTOSCWithAlgorithmPrediction(t *testing.T) {
+	expect := "[osc]pt-online-schema-change D=exist_db,t=%s --alter='%s' --host=127.0.0.1 --user=root --port=3306 --ask-pass --print --execute"
+	cases := []struct {
+		version string
+		sql     string
+		expect  string
+	}{
+		// INSTANT ADD COLUMN
+		{"8.0.30", "alter table exist_tb_1 add column v3 varchar(255);", ""},
+		{"5.7.44-log", "alter table exist_tb_1 add column v3 varchar(255);", fmt.Sprintf(expect, "exist_tb_1", "ADD COLUMN `v3` varchar(255)")},
+		// INPLACE without rebuilding the table still scans the large table
+		{"8.0.30", "alter table exist_tb_1 add index idx_2 (v2);", fmt.Sprintf(expect, "exist_tb_1", "ADD INDEX `idx_2` (`v2`)")},
+		// COPY
+		{"8.0.30", "alter table exist_tb_1 modify column v2 int(11);", fmt.Sprintf(expect, "exist_tb_1", "MODIFY COLUMN `v2` int(11)")},
+	}
+	for _, c := range cases {
+		i := DefaultMysqlInspect()
+		i.cnf.DDLOSCMinSize = 0
+		i.Ctx.AddSystemVariable("version", c.version)
+		stmt, err := util.ParseOneSql(c.sql)
+		assert.NoError(t, err)
+		actual, err := i.generateOSCCommandLine(stmt)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expect, actual[i18nPkg.DefaultLang], c.version+" "+c.sql)
+	}
+}
@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+)
+
+// queryReadOnly runs a read-only statement in a read-only transaction and reads at most conf.MaxRows rows of
+// the result.
+func (i *MysqlDriverImpl) queryReadOnly(ctx context.Context, sql string, conf *driverV2.QueryConf) (*driverV2.QueryResult, error) {
+	node, err := util.ParseOneSql(sql)
+	if err != nil {
+		return nil, err
+	}
+	if !isReadOnlyQuery(node) {
+		return nil, fmt.Errorf("only read-only statements are allowed, such as SELECT, SHOW and EXPLAIN")
+	}
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	if conf.TimeOutSecond > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(conf.TimeOutSecond)*time.Second)
+		defer cancel()
+	}
+	if sql, err = limitQuery(sql, node, conf.MaxRows); err != nil {
+		return nil, err
+	}
+	// the parser can not tell whether the functions called by the statement write, the read-only transaction
+	// makes the instance reject the writes
+	columns, rows, err := conn.Db.QueryReadOnly(ctx, sql, int(conf.MaxRows))
+	if err != nil {
+		return nil, err
+	}
+	res := &driverV2.QueryResult{
+		Column: params.Params{},
+		Rows:   make([]*driverV2.QueryResultRow, 0, len(rows)),
+	}
+	for _, column := range columns {
+		res.Column = append(res.Column, &params.Param{
+			Key:   column,
+			Value: column,
+			Type:  params.ParamTypeString,
+		})
+	}
+	for _, row := range rows {
+		r := &driverV2.QueryResultRow{Values: make([]*driverV2.QueryResultValue, 0, len(row))}
+		for _, value := range row {
+			r.Values = append(r.Values, &driverV2.QueryResultValue{Value: value.String})
+		}
+		res.Rows = append(res.Rows, r)
+	}
+	return res, nil
+}
+
+// limitQuery pushes the max rows into the LIMIT of the SELECT, so the instance stops producing the rows which
+// are not read. The LIMIT of the statement is kept if it is smaller.
+func limitQuery(sql string, node ast.StmtNode, maxRows uint32) (string, error) {
+	if maxRows == 0 {
+		return sql, nil
+	}
+	var limit **ast.Limit
+	switch stmt := node.(type) {
+	case *ast.SelectStmt:
+		limit = &stmt.Limit
+	case *ast.UnionStmt:
+		limit = &stmt.Limit
+	default:
+		return sql, nil
+	}
+	if *limit == nil {
+		*limit = &ast.Limit{}
+	}
+	if count, ok := (*limit).Count.(ast.ValueExpr); ok {
+		if v, ok := count.GetValue().(uint64); ok && v <= uint64(maxRows) {
+			return sql, nil
+		}
+	}
+	(*limit).Count = ast.NewValueExpr(uint64(maxRows), "", "")
+	var buf strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// isReadOnlyQuery reports whether the statement only reads data, SELECT ... FOR UPDATE, LOCK IN SHARE MODE,
+// SELECT ... INTO and EXPLAIN ANALYZE of a write are rejected.
+func isReadOnlyQuery(node ast.Node) bool {
+	switch stmt := node.(type) {
+	case *ast.SelectStmt:
+		if stmt.SelectIntoOpt != nil || stmt.LockTp != ast.SelectLockNone {
+			return false
+		}
+		return ast.IsReadOnly(stmt)
+	case *ast.UnionStmt:
+		if stmt.SelectList == nil {
+			return false
+		}
+		for _, sel := range stmt.SelectList.Selects {
+			if !isReadOnlyQuery(sel) {
+				return false
+			}
+		}
+		return true
+	case *ast.ExplainStmt:
+		return !stmt.Analyze || isReadOnlyQuery(stmt.Stmt)
+	case *ast.ShowStmt:
+		return true
+	default:
+		return false
+	}
+}
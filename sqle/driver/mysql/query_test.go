@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnlyQuery(t *testing.T) {
+	for sql, expected := range map[string]bool{
+		"SELECT * FROM t1 WHERE id = 1":                        true,
+		"SELECT id FROM t1 UNION SELECT id FROM t2":            true,
+		"SHOW CREATE TABLE t1":                                 true,
+		"EXPLAIN SELECT * FROM t1":                             true,
+		"EXPLAIN DELETE FROM t1":                               true,
+		"EXPLAIN ANALYZE DELETE FROM t1":                       false,
+		"SELECT * FROM t1 FOR UPDATE":                          false,
+		"SELECT * FROM t1 LOCK IN SHARE MODE":                  false,
+		"SELECT id FROM t1 UNION SELECT id FROM t2 FOR UPDATE": false,
+		"SELECT * FROM t1 INTO OUTFILE '/tmp/t1'":              false,
+		"SELECT @a := 1":                                       false,
+		"UPDATE t1 SET a = 1":                                  false,
+		"SET @a = 1":                                           false,
+		"CREATE TABLE t3 (id INT)":                             false,
+	} {
+		node, err := util.ParseOneSql(sql)
+		assert.NoError(t, err, sql)
+		assert.Equal(t, expected, isReadOnlyQuery(node), sql)
+	}
+}
+
+func TestQueryReadOnly(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+
+	handler.ExpectBegin()
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT `id`,`phone` FROM `t1` LIMIT 2")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "phone"}).
+			AddRow("1", "13800001111").
+			AddRow("2", nil).
+			AddRow("3", "13800003333"))
+	handler.ExpectRollback()
+	res, err := i.queryReadOnly(context.TODO(), "SELECT id, phone FROM t1", &driverV2.QueryConf{TimeOutSecond: 10, MaxRows: 2})
+	assert.NoError(t, err)
+	assert.Len(t, res.Column, 2)
+	assert.Equal(t, "phone", res.Column[1].Key)
+	assert.Len(t, res.Rows, 2)
+	assert.Equal(t, "13800001111", res.Rows[0].Values[1].Value)
+	assert.Equal(t, "", res.Rows[1].Values[1].Value)
+	assert.NoError(t, handler.ExpectationsWereMet())
+
+	_, err = i.queryReadOnly(context.TODO(), "DELETE FROM t1", &driverV2.QueryConf{})
+	assert.Error(t, err)
+}
+
+func TestQueryReadOnlyRejectsWriteInFunction(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+
+	// the parser accepts the call of the stored function which writes, the read-only transaction rejects it
+	node, err := util.ParseOneSql("SELECT f_insert_log(1)")
+	assert.NoError(t, err)
+	assert.True(t, isReadOnlyQuery(node))
+
+	handler.ExpectBegin()
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT f_insert_log(1)")).
+		WillReturnError(&mysql.MySQLError{Number: 1792, Message: "Cannot execute statement in a READ ONLY transaction."})
+	handler.ExpectRollback()
+	_, err = i.queryReadOnly(context.TODO(), "SELECT f_insert_log(1)", &driverV2.QueryConf{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "READ ONLY transaction")
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestLimitQuery(t *testing.T) {
+	for sql, expected := range map[string]string{
+		"SELECT * FROM t1":                          "SELECT * FROM `t1` LIMIT 100",
+		"SELECT * FROM t1 LIMIT 10":                 "SELECT * FROM t1 LIMIT 10",
+		"SELECT * FROM t1 LIMIT 20, 1000":           "SELECT * FROM `t1` LIMIT 20,100",
+		"SELECT id FROM t1 UNION SELECT id FROM t2": "SELECT `id` FROM `t1` UNION SELECT `id` FROM `t2` LIMIT 100",
+		"SHOW TABLES":                               "SHOW TABLES",
+	} {
+		node, err := util.ParseOneSql(sql)
+		assert.NoError(t, err, sql)
+		actual, err := limitQuery(sql, node, 100)
+		assert.NoError(t, err, sql)
+		assert.Equal(t, expected, actual, sql)
+	}
+}
@@ -0,0 +1,304 @@
+// Package rewrite rewrites the MySQL statements violating the audit rules in process, the rewrites work on the
+// AST and the table definitions of the session context only, so they do not depend on any external service.
+package rewrite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"github.com/actiontech/sqle/sqle/driver/mysql/plocale"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/opcode"
+)
+
+type rewriter struct {
+	name string
+	// rules are the audit rules which trigger the rewriter
+	rules []string
+	desc  *i18n.Message
+	// nonEquivalentDesc explains how the rewritten statement differs from the original one, nil means equivalent
+	nonEquivalentDesc *i18n.Message
+	// rewrite returns the rewritten statement, or nil if the rewriter does not apply to the statement. The
+	// statement is a copy which can be modified.
+	rewrite func(ctx *session.Context, stmt ast.StmtNode) (ast.StmtNode, error)
+}
+
+// rewriters are applied in order, the ones producing a UNION are the last since the others only rewrite SELECT.
+var rewriters = []*rewriter{
+	inSubqueryToJoinRewriter,
+	funcOnColumnRewriter,
+	implicitConversionRewriter,
+	selectAllRewriter,
+	keysetPaginationRewriter,
+	orToUnionAllRewriter,
+}
+
+type Suggestion struct {
+	// RuleName is the audit rule which triggers the rewrite
+	RuleName          string
+	Rewriter          string
+	Desc              i18nPkg.I18nStr
+	NonEquivalentDesc i18nPkg.I18nStr
+	// RewrittenSQL is the statement rewritten by the rewriter alone
+	RewrittenSQL string
+}
+
+type Result struct {
+	Suggestions []*Suggestion
+	// RewrittenSQL is the statement rewritten by all the suggestions in order, it is empty if no rewriter applies
+	RewrittenSQL      string
+	NonEquivalentDesc i18nPkg.I18nStr
+}
+
+// Rewrite rewrites the statement by the rewriters of the audit rules it violates. The rewriters which need the table
+// definitions are skipped if the context can not provide them.
+func Rewrite(ctx *session.Context, sql string, ruleNames []string) (*Result, error) {
+	origin, err := util.ParseOneSql(sql)
+	if err != nil {
+		return nil, err
+	}
+	triggered := map[string]bool{}
+	for _, name := range ruleNames {
+		triggered[name] = true
+	}
+
+	result := &Result{}
+	current := origin
+	nonEquivalentDescs := []i18nPkg.I18nStr{}
+	for _, r := range rewriters {
+		ruleName := ""
+		for _, name := range r.rules {
+			if triggered[name] {
+				ruleName = name
+				break
+			}
+		}
+		if ruleName == "" {
+			continue
+		}
+
+		alone, err := applyRewriter(ctx, r, origin)
+		if err != nil {
+			return nil, fmt.Errorf("rewriter %s failed: %v", r.name, err)
+		}
+		if alone == nil {
+			continue
+		}
+		suggestion := &Suggestion{
+			RuleName:     ruleName,
+			Rewriter:     r.name,
+			Desc:         plocale.Bundle.LocalizeAll(r.desc),
+			RewrittenSQL: alone.Text(),
+		}
+		if r.nonEquivalentDesc != nil {
+			suggestion.NonEquivalentDesc = plocale.Bundle.LocalizeAll(r.nonEquivalentDesc)
+			nonEquivalentDescs = append(nonEquivalentDescs, suggestion.NonEquivalentDesc)
+		}
+		result.Suggestions = append(result.Suggestions, suggestion)
+
+		if current == origin {
+			current = alone
+			continue
+		}
+		chained, err := applyRewriter(ctx, r, current)
+		if err != nil {
+			return nil, fmt.Errorf("rewriter %s failed: %v", r.name, err)
+		}
+		if chained != nil {
+			current = chained
+		}
+	}
+	if current != origin {
+		result.RewrittenSQL = current.Text()
+	}
+	if len(nonEquivalentDescs) > 0 {
+		result.NonEquivalentDesc = plocale.Bundle.JoinI18nStr(nonEquivalentDescs, "\n")
+	}
+	return result, nil
+}
+
+// applyRewriter applies the rewriter to a copy of the statement, the text of the rewritten statement is set.
+func applyRewriter(ctx *session.Context, r *rewriter, stmt ast.StmtNode) (ast.StmtNode, error) {
+	copied, err := copyStmt(stmt)
+	if err != nil {
+		return nil, err
+	}
+	rewritten, err := r.rewrite(ctx, copied)
+	if err != nil || rewritten == nil {
+		return nil, err
+	}
+	// restore and parse again to make sure the rewritten statement is valid
+	sql, err := restore(rewritten)
+	if err != nil {
+		return nil, err
+	}
+	return util.ParseOneSql(sql)
+}
+
+func copyStmt(stmt ast.StmtNode) (ast.StmtNode, error) {
+	sql, err := restore(stmt)
+	if err != nil {
+		return nil, err
+	}
+	return util.ParseOneSql(sql)
+}
+
+func restore(node ast.Node) (string, error) {
+	var buf strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// parseExpr parses the expression text, the operands of the composed expressions should be restored by
+// restoreOperand to keep the precedence.
+func parseExpr(expr string) (ast.ExprNode, error) {
+	node, err := util.ParseOneSql("SELECT " + expr)
+	if err != nil {
+		return nil, err
+	}
+	return node.(*ast.SelectStmt).Fields.Fields[0].Expr, nil
+}
+
+// restoreOperand restores the expression in parentheses if it is not a simple one.
+func restoreOperand(expr ast.ExprNode) (string, error) {
+	s, err := restore(expr)
+	if err != nil {
+		return "", err
+	}
+	switch x := expr.(type) {
+	case *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.FuncCallExpr, *ast.PatternInExpr, *ast.IsNullExpr:
+		return s, nil
+	case *ast.BinaryOperationExpr:
+		if x.Op != opcode.LogicOr && x.Op != opcode.LogicXor && x.Op != opcode.LogicAnd {
+			return s, nil
+		}
+	}
+	return "(" + s + ")", nil
+}
+
+// conjuncts splits the condition by AND.
+func conjuncts(expr ast.ExprNode) []ast.ExprNode {
+	switch x := expr.(type) {
+	case nil:
+		return nil
+	case *ast.BinaryOperationExpr:
+		if x.Op == opcode.LogicAnd {
+			return append(conjuncts(x.L), conjuncts(x.R)...)
+		}
+	case *ast.ParenthesesExpr:
+		if b, ok := x.Expr.(*ast.BinaryOperationExpr); ok && b.Op == opcode.LogicAnd {
+			return conjuncts(b)
+		}
+	}
+	return []ast.ExprNode{expr}
+}
+
+// disjuncts splits the condition by OR.
+func disjuncts(expr ast.ExprNode) []ast.ExprNode {
+	switch x := expr.(type) {
+	case *ast.BinaryOperationExpr:
+		if x.Op == opcode.LogicOr {
+			return append(disjuncts(x.L), disjuncts(x.R)...)
+		}
+	case *ast.ParenthesesExpr:
+		if b, ok := x.Expr.(*ast.BinaryOperationExpr); ok && b.Op == opcode.LogicOr {
+			return disjuncts(b)
+		}
+	}
+	return []ast.ExprNode{expr}
+}
+
+// and composes the conditions by AND, it returns nil if there is no condition.
+func and(exprs ...ast.ExprNode) (ast.ExprNode, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	operands := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		s, err := restoreOperand(expr)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, s)
+	}
+	return parseExpr(strings.Join(operands, " AND "))
+}
+
+func columnsOf(node ast.Node) []*ast.ColumnNameExpr {
+	visitor := &util.ColumnNameVisitor{}
+	node.Accept(visitor)
+	return visitor.ColumnNameList
+}
+
+// tableOfColumn returns the table source the column belongs to, it is nil if the column can not be resolved.
+func tableOfColumn(ctx *session.Context, sources []*ast.TableSource, col *ast.ColumnName) (*ast.TableSource, *ast.CreateTableStmt) {
+	for _, source := range sources {
+		table, ok := source.Source.(*ast.TableName)
+		if !ok {
+			continue
+		}
+		if col.Table.L != "" && col.Table.L != source.AsName.L && (source.AsName.L != "" || col.Table.L != table.Name.L) {
+			continue
+		}
+		createTable, exist, err := ctx.GetCreateTableStmt(table)
+		if err != nil || !exist {
+			if col.Table.L != "" {
+				return source, nil
+			}
+			continue
+		}
+		if util.TableExistCol(createTable, col.Name.L) {
+			return source, createTable
+		}
+	}
+	return nil, nil
+}
+
+// getColumnDef returns the definition of the column, it is nil if the column can not be resolved.
+func getColumnDef(createTable *ast.CreateTableStmt, name string) *ast.ColumnDef {
+	if createTable == nil {
+		return nil
+	}
+	for _, col := range createTable.Cols {
+		if col.Name.Name.L == name {
+			return col
+		}
+	}
+	return nil
+}
+
+// isColumnIndexed returns whether the column is the first column of an index, known is false if the table definition
+// is not available.
+func isColumnIndexed(createTable *ast.CreateTableStmt, name string) (indexed bool, known bool) {
+	if createTable == nil {
+		return false, false
+	}
+	for _, constraint := range createTable.Constraints {
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey, ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+			if len(constraint.Keys) > 0 && constraint.Keys[0].Column != nil && constraint.Keys[0].Column.Name.L == name {
+				return true, true
+			}
+		}
+	}
+	for _, col := range createTable.Cols {
+		if col.Name.Name.L == name && util.HasOneInOptions(col.Options, ast.ColumnOptionPrimaryKey, ast.ColumnOptionUniqKey) {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func tableSourcesOf(stmt *ast.SelectStmt) []*ast.TableSource {
+	if stmt.From == nil || stmt.From.TableRefs == nil {
+		return nil
+	}
+	return util.GetTableSources(stmt.From.TableRefs)
+}
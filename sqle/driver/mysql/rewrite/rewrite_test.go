@@ -0,0 +1,166 @@
+package rewrite
+
+import (
+	"testing"
+
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/driver/mysql/rule/ai"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewrite(t *testing.T) {
+	cases := []struct {
+		name      string
+		sql       string
+		rules     []string
+		rewriters []string
+		expected  string
+	}{
+		{
+			name:      "in subquery to join",
+			sql:       "SELECT * FROM exist_tb_1 WHERE id IN (SELECT user_id FROM exist_tb_2 WHERE v1 = 'a') AND v2 = 'b'",
+			rules:     []string{rulepkg.DMLNotRecommendSubquery},
+			rewriters: []string{"in_subquery_to_join"},
+			expected:  "SELECT `exist_tb_1`.* FROM `exist_tb_1` JOIN (SELECT DISTINCT `user_id` AS `in_sq1_col` FROM (`exist_tb_2`) WHERE `v1`='a') AS `in_sq1` ON `id`=`in_sq1`.`in_sq1_col` WHERE `v2`='b'",
+		},
+		{
+			name:      "in subquery to join keeps the columns of the outer tables",
+			sql:       "SELECT *, b.v1 FROM exist_tb_1 AS a JOIN exist_tb_2 AS b ON a.id = b.user_id WHERE a.id IN (SELECT user_id FROM exist_tb_2)",
+			rules:     []string{rulepkg.DMLNotRecommendSubquery},
+			rewriters: []string{"in_subquery_to_join"},
+			expected:  "SELECT `a`.*,`b`.*,`b`.`v1` FROM (`exist_tb_1` AS `a` JOIN `exist_tb_2` AS `b` ON `a`.`id`=`b`.`user_id`) JOIN (SELECT DISTINCT `user_id` AS `in_sq1_col` FROM (`exist_tb_2`)) AS `in_sq1` ON `a`.`id`=`in_sq1`.`in_sq1_col`",
+		},
+		{
+			name:  "in subquery of join by USING is not rewritten",
+			sql:   "SELECT * FROM exist_tb_1 JOIN exist_tb_2 USING (id) WHERE v1 IN (SELECT v1 FROM exist_tb_2)",
+			rules: []string{rulepkg.DMLNotRecommendSubquery},
+		},
+		{
+			name:  "correlated subquery is not rewritten",
+			sql:   "SELECT id FROM exist_tb_1 AS t WHERE id IN (SELECT user_id FROM exist_tb_2 WHERE exist_tb_2.v1 = t.v1)",
+			rules: []string{rulepkg.DMLNotRecommendSubquery},
+		},
+		{
+			name:      "or to union all",
+			sql:       "SELECT v1 FROM exist_tb_1 WHERE id > 1 AND (v1 = 'a' OR v2 = 'b') LIMIT 10",
+			rules:     []string{ai.SQLE00143},
+			rewriters: []string{"or_to_union_all"},
+			expected:  "SELECT `v1` FROM `exist_tb_1` WHERE `id`>1 AND `v1`='a' UNION ALL SELECT `v1` FROM `exist_tb_1` WHERE `id`>1 AND `v2`='b' AND (`v1`='a') IS NOT TRUE LIMIT 10",
+		},
+		{
+			name:  "or on the same column is not rewritten",
+			sql:   "SELECT v1 FROM exist_tb_1 WHERE v1 = 'a' OR v1 = 'b'",
+			rules: []string{ai.SQLE00143},
+		},
+		{
+			name:      "function on column",
+			sql:       "SELECT id FROM t WHERE DATE(created_at) = '2024-01-31' AND YEAR(c) > 2023",
+			rules:     []string{rulepkg.DMLCheckWhereExistFunc},
+			rewriters: []string{"func_on_column"},
+			expected:  "SELECT `id` FROM `t` WHERE (`created_at`>='2024-01-31' AND `created_at`<'2024-02-01') AND `c`>='2024-01-01'",
+		},
+		{
+			name:      "computation on column",
+			sql:       "UPDATE exist_tb_1 SET v2 = 'x' WHERE 10 < id + 1",
+			rules:     []string{rulepkg.DMLCheckMathComputationOrFuncOnIndex},
+			rewriters: []string{"func_on_column"},
+			expected:  "UPDATE `exist_tb_1` SET `v2`='x' WHERE `id`>9",
+		},
+		{
+			name:  "computation on column not indexed is not rewritten",
+			sql:   "SELECT v1 FROM exist_tb_1 WHERE v2 + 1 > 10",
+			rules: []string{rulepkg.DMLCheckMathComputationOrFuncOnIndex},
+		},
+		{
+			name:      "implicit conversion",
+			sql:       "SELECT id FROM exist_tb_1 WHERE v1 = 123 AND id IN ('1', '2')",
+			rules:     []string{rulepkg.DMLCheckWhereExistImplicitConversion},
+			rewriters: []string{"implicit_conversion"},
+			expected:  "SELECT `id` FROM `exist_tb_1` WHERE `v1`='123' AND `id` IN (1,2)",
+		},
+		{
+			name:  "implicit conversion of unknown table is not rewritten",
+			sql:   "SELECT id FROM t WHERE v1 = 123",
+			rules: []string{rulepkg.DMLCheckWhereExistImplicitConversion},
+		},
+		{
+			name:      "select all of join",
+			sql:       "SELECT * FROM exist_tb_1 AS a JOIN exist_tb_2 AS b ON a.id = b.user_id",
+			rules:     []string{rulepkg.DMLDisableSelectAllColumn},
+			rewriters: []string{"select_all"},
+			expected:  "SELECT `a`.`id`,`a`.`v1`,`a`.`v2`,`b`.`id`,`b`.`v1`,`b`.`v2`,`b`.`user_id` FROM `exist_tb_1` AS `a` JOIN `exist_tb_2` AS `b` ON `a`.`id`=`b`.`user_id`",
+		},
+		{
+			name:      "select all of qualified wildcard",
+			sql:       "SELECT b.* FROM exist_tb_1 AS a JOIN exist_tb_2 AS b ON a.id = b.user_id",
+			rules:     []string{ai.SQLE00053},
+			rewriters: []string{"select_all"},
+			expected:  "SELECT `b`.`id`,`b`.`v1`,`b`.`v2`,`b`.`user_id` FROM `exist_tb_1` AS `a` JOIN `exist_tb_2` AS `b` ON `a`.`id`=`b`.`user_id`",
+		},
+		{
+			name:  "select all of unknown table is not rewritten",
+			sql:   "SELECT * FROM t",
+			rules: []string{rulepkg.DMLDisableSelectAllColumn},
+		},
+		{
+			name:      "keyset pagination",
+			sql:       "SELECT id, v1 FROM exist_tb_1 WHERE v1 = 'a' ORDER BY v2 DESC LIMIT 10000, 20",
+			rules:     []string{rulepkg.DMLCheckLimitOffsetNum},
+			rewriters: []string{"keyset_pagination"},
+			expected:  "SELECT `id`,`v1` FROM `exist_tb_1` WHERE `v1`='a' AND ROW(`v2`,`id`)<ROW(?,?) ORDER BY `v2` DESC,`id` DESC LIMIT 20",
+		},
+		{
+			name:  "pagination with mixed directions is not rewritten",
+			sql:   "SELECT id FROM exist_tb_1 ORDER BY v1, v2 DESC LIMIT 20 OFFSET 10000",
+			rules: []string{rulepkg.DMLCheckLimitOffsetNum},
+		},
+		{
+			name:      "chained rewrites",
+			sql:       "SELECT * FROM exist_tb_1 ORDER BY id LIMIT 20 OFFSET 10000",
+			rules:     []string{rulepkg.DMLDisableSelectAllColumn, rulepkg.DMLCheckLimitOffsetNum},
+			rewriters: []string{"select_all", "keyset_pagination"},
+			expected:  "SELECT `id`,`v1`,`v2` FROM `exist_tb_1` WHERE `id`>? ORDER BY `id` LIMIT 20",
+		},
+		{
+			name:  "rewriter is not triggered",
+			sql:   "SELECT * FROM exist_tb_1",
+			rules: []string{rulepkg.DMLCheckLimitOffsetNum},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := Rewrite(session.NewMockContext(nil), c.sql, c.rules)
+			assert.NoError(t, err)
+			rewriters := []string{}
+			for _, s := range result.Suggestions {
+				rewriters = append(rewriters, s.Rewriter)
+				assert.Contains(t, c.rules, s.RuleName)
+				assert.NotEmpty(t, s.RewrittenSQL)
+			}
+			if len(c.rewriters) == 0 {
+				assert.Empty(t, rewriters)
+			} else {
+				assert.Equal(t, c.rewriters, rewriters)
+			}
+			assert.Equal(t, c.expected, result.RewrittenSQL)
+		})
+	}
+}
+
+func TestRewriteNonEquivalentDesc(t *testing.T) {
+	result, err := Rewrite(session.NewMockContext(nil), "SELECT id FROM exist_tb_1 WHERE v1 = 123 ORDER BY id LIMIT 100, 10",
+		[]string{rulepkg.DMLCheckWhereExistImplicitConversion, rulepkg.DMLCheckLimitOffsetNum})
+	assert.NoError(t, err)
+	assert.Len(t, result.Suggestions, 2)
+	for _, s := range result.Suggestions {
+		assert.NotEmpty(t, s.NonEquivalentDesc)
+	}
+	assert.NotEmpty(t, result.NonEquivalentDesc)
+
+	result, err = Rewrite(session.NewMockContext(nil), "SELECT id FROM exist_tb_1 WHERE id + 1 > 10", []string{rulepkg.DMLCheckWhereExistFunc})
+	assert.NoError(t, err)
+	assert.Len(t, result.Suggestions, 1)
+	assert.Empty(t, result.Suggestions[0].NonEquivalentDesc)
+	assert.Empty(t, result.NonEquivalentDesc)
+}
@@ -0,0 +1,804 @@
+package rewrite
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/plocale"
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/driver/mysql/rule/ai"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/opcode"
+	"github.com/pingcap/parser/types"
+)
+
+var inSubqueryToJoinRewriter = &rewriter{
+	name:    "in_subquery_to_join",
+	rules:   []string{rulepkg.DMLNotRecommendSubquery, rulepkg.DMLNotRecommendIn, ai.SQLE00132},
+	desc:    plocale.RewriteInSubqueryToJoinDesc,
+	rewrite: rewriteInSubqueryToJoin,
+}
+
+// rewriteInSubqueryToJoin rewrites
+//
+//	SELECT * FROM t1 WHERE a IN (SELECT b FROM t2 WHERE c = 1)
+//
+// to
+//
+//	SELECT t1.* FROM t1 JOIN (SELECT DISTINCT b AS in_sq1_col FROM t2 WHERE c = 1) AS in_sq1 ON a = in_sq1.in_sq1_col
+//
+// the DISTINCT keeps the rows of t1 from being duplicated, and the wildcard is qualified by the outer tables to keep
+// the joined column out of the result. The correlated subqueries are not rewritten.
+func rewriteInSubqueryToJoin(ctx *session.Context, stmt ast.StmtNode) (ast.StmtNode, error) {
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || sel.From == nil || sel.Where == nil || sel.Fields == nil {
+		return nil, nil
+	}
+	fields, ok := qualifyWildcards(sel)
+	if !ok {
+		return nil, nil
+	}
+	rest := []ast.ExprNode{}
+	joined := 0
+	for _, cond := range conjuncts(sel.Where) {
+		sub := inSubqueryOf(ctx, cond)
+		if sub == nil {
+			rest = append(rest, cond)
+			continue
+		}
+		joined++
+		alias := fmt.Sprintf("in_sq%d", joined)
+		col := alias + "_col"
+		sub.Distinct = true
+		sub.Fields.Fields[0].AsName = model.NewCIStr(col)
+		on := &ast.OnCondition{Expr: &ast.BinaryOperationExpr{
+			Op: opcode.EQ,
+			L:  cond.(*ast.PatternInExpr).Expr,
+			R:  &ast.ColumnNameExpr{Name: &ast.ColumnName{Table: model.NewCIStr(alias), Name: model.NewCIStr(col)}},
+		}}
+		source := &ast.TableSource{Source: sub, AsName: model.NewCIStr(alias)}
+		refs := sel.From.TableRefs
+		if refs.Right == nil {
+			refs.Right, refs.Tp, refs.On = source, ast.CrossJoin, on
+		} else {
+			sel.From.TableRefs = &ast.Join{Left: refs, Right: source, Tp: ast.CrossJoin, On: on}
+		}
+	}
+	if joined == 0 {
+		return nil, nil
+	}
+	where, err := and(rest...)
+	if err != nil {
+		return nil, err
+	}
+	sel.Where = where
+	sel.Fields.Fields = fields
+	return sel, nil
+}
+
+// qualifyWildcards returns the fields of the SELECT whose unqualified wildcard is replaced by the wildcards of each
+// of its tables. It is not ok if any table can not be qualified, or the tables are joined by USING or NATURAL JOIN,
+// the wildcard of which merges the join columns.
+func qualifyWildcards(sel *ast.SelectStmt) ([]*ast.SelectField, bool) {
+	fields := []*ast.SelectField{}
+	for _, field := range sel.Fields.Fields {
+		if field.WildCard == nil || field.WildCard.Table.L != "" {
+			fields = append(fields, field)
+			continue
+		}
+		if hasUsingOrNatural(sel.From.TableRefs) {
+			return nil, false
+		}
+		for _, source := range tableSourcesOf(sel) {
+			qualifier := source.AsName
+			if table, ok := source.Source.(*ast.TableName); ok && qualifier.L == "" {
+				qualifier = table.Name
+			}
+			if qualifier.L == "" {
+				return nil, false
+			}
+			fields = append(fields, &ast.SelectField{WildCard: &ast.WildCardField{Table: qualifier}})
+		}
+	}
+	return fields, true
+}
+
+// inSubqueryOf returns the subquery of the condition "column IN (subquery)" which can be joined.
+func inSubqueryOf(ctx *session.Context, cond ast.ExprNode) *ast.SelectStmt {
+	in, ok := cond.(*ast.PatternInExpr)
+	if !ok || in.Not || in.Sel == nil {
+		return nil
+	}
+	if _, ok := in.Expr.(*ast.ColumnNameExpr); !ok {
+		return nil
+	}
+	subquery, ok := in.Sel.(*ast.SubqueryExpr)
+	if !ok {
+		return nil
+	}
+	sub, ok := subquery.Query.(*ast.SelectStmt)
+	if !ok || sub.From == nil || sub.Limit != nil || sub.Fields == nil || len(sub.Fields.Fields) != 1 || sub.Fields.Fields[0].WildCard != nil {
+		return nil
+	}
+	if isCorrelated(ctx, sub) {
+		return nil
+	}
+	return sub
+}
+
+// isCorrelated returns whether the subquery references the columns of the outer query. The unqualified columns are
+// considered to be the ones of the subquery if the definitions of its tables are not available.
+func isCorrelated(ctx *session.Context, sub *ast.SelectStmt) bool {
+	sources := tableSourcesOf(sub)
+	names := map[string]bool{}
+	allKnown := true
+	for _, source := range sources {
+		if source.AsName.L != "" {
+			names[source.AsName.L] = true
+		}
+		table, ok := source.Source.(*ast.TableName)
+		if !ok {
+			allKnown = false
+			continue
+		}
+		if source.AsName.L == "" {
+			names[table.Name.L] = true
+		}
+		if _, exist, err := ctx.GetCreateTableStmt(table); err != nil || !exist {
+			allKnown = false
+		}
+	}
+	for _, col := range columnsOf(sub) {
+		if col.Name.Table.L != "" {
+			if !names[col.Name.Table.L] {
+				return true
+			}
+			continue
+		}
+		if source, _ := tableOfColumn(ctx, sources, col.Name); source == nil && allKnown {
+			return true
+		}
+	}
+	return false
+}
+
+var orToUnionAllRewriter = &rewriter{
+	name:    "or_to_union_all",
+	rules:   []string{ai.SQLE00143, rulepkg.DMLMustMatchLeftMostPrefix},
+	desc:    plocale.RewriteOrToUnionAllDesc,
+	rewrite: rewriteOrToUnionAll,
+}
+
+// rewriteOrToUnionAll rewrites
+//
+//	SELECT * FROM t WHERE a = 1 OR b = 2
+//
+// to
+//
+//	SELECT * FROM t WHERE a = 1 UNION ALL SELECT * FROM t WHERE b = 2 AND (a = 1) IS NOT TRUE
+//
+// the later branches exclude the rows of the former ones, so no row is returned twice.
+func rewriteOrToUnionAll(ctx *session.Context, stmt ast.StmtNode) (ast.StmtNode, error) {
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || sel.From == nil || sel.Where == nil || sel.Distinct || sel.GroupBy != nil || sel.Having != nil ||
+		sel.OrderBy != nil || sel.LockTp != ast.SelectLockNone || sel.SelectIntoOpt != nil || hasAggregate(sel.Fields) {
+		return nil, nil
+	}
+	// the LIMIT applies to the whole UNION
+	limit := sel.Limit
+	conds := conjuncts(sel.Where)
+	for i, cond := range conds {
+		ds := disjuncts(cond)
+		if len(ds) < 2 || !onDifferentColumns(ds) {
+			continue
+		}
+		others := append(append([]ast.ExprNode{}, conds[:i]...), conds[i+1:]...)
+		branches := make([]string, 0, len(ds))
+		for k, d := range ds {
+			operands := make([]string, 0, len(others)+k+1)
+			for _, expr := range append(others, d) {
+				s, err := restoreOperand(expr)
+				if err != nil {
+					return nil, err
+				}
+				operands = append(operands, s)
+			}
+			for _, former := range ds[:k] {
+				s, err := restore(former)
+				if err != nil {
+					return nil, err
+				}
+				operands = append(operands, "("+s+") IS NOT TRUE")
+			}
+			where, err := parseExpr(strings.Join(operands, " AND "))
+			if err != nil {
+				return nil, err
+			}
+			sel.Where, sel.Limit = where, nil
+			branch, err := restore(sel)
+			if err != nil {
+				return nil, err
+			}
+			branches = append(branches, branch)
+		}
+		sql := strings.Join(branches, " UNION ALL ")
+		if limit != nil {
+			s, err := restore(limit)
+			if err != nil {
+				return nil, err
+			}
+			sql += " " + s
+		}
+		return util.ParseOneSql(sql)
+	}
+	return nil, nil
+}
+
+// onDifferentColumns returns whether the conditions are on different columns, each of them should have a column.
+func onDifferentColumns(conds []ast.ExprNode) bool {
+	var first string
+	different := false
+	for i, cond := range conds {
+		cols := []string{}
+		for _, col := range columnsOf(cond) {
+			cols = append(cols, col.Name.String())
+		}
+		if len(cols) == 0 {
+			return false
+		}
+		key := strings.Join(util.RemoveArrayRepeat(cols), ",")
+		if i == 0 {
+			first = key
+		} else if key != first {
+			different = true
+		}
+	}
+	return different
+}
+
+func hasAggregate(fields *ast.FieldList) bool {
+	if fields == nil {
+		return false
+	}
+	visitor := &aggregateVisitor{}
+	fields.Accept(visitor)
+	return visitor.found
+}
+
+type aggregateVisitor struct {
+	found bool
+}
+
+func (v *aggregateVisitor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch in.(type) {
+	case *ast.AggregateFuncExpr, *ast.WindowFuncExpr:
+		v.found = true
+		return in, true
+	}
+	return in, false
+}
+
+func (v *aggregateVisitor) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, true
+}
+
+var funcOnColumnRewriter = &rewriter{
+	name:    "func_on_column",
+	rules:   []string{rulepkg.DMLCheckWhereExistFunc, rulepkg.DMLNotRecommendFuncInWhere, rulepkg.DMLCheckMathComputationOrFuncOnIndex, ai.SQLE00009},
+	desc:    plocale.RewriteFuncOnColumnDesc,
+	rewrite: rewriteFuncOnColumn,
+}
+
+// rewriteFuncOnColumn rewrites the comparisons of DATE(col), YEAR(col), col + n and col - n with constants to the
+// comparisons of the column, e.g.
+//
+//	DATE(created_at) = '2024-01-01'  =>  created_at >= '2024-01-01' AND created_at < '2024-01-02'
+//	id + 1 > 10                      =>  id > 9
+//
+// the columns known not to be indexed are not rewritten since the rewrite does not help.
+func rewriteFuncOnColumn(ctx *session.Context, stmt ast.StmtNode) (ast.StmtNode, error) {
+	where, sources := whereOf(stmt)
+	if where == nil || *where == nil {
+		return nil, nil
+	}
+	conds := conjuncts(*where)
+	rewritten := false
+	for i, cond := range conds {
+		expr, err := rewriteComparisonOnColumn(ctx, sources, cond)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			conds[i] = expr
+			rewritten = true
+		}
+	}
+	if !rewritten {
+		return nil, nil
+	}
+	newWhere, err := and(conds...)
+	if err != nil {
+		return nil, err
+	}
+	*where = newWhere
+	return stmt, nil
+}
+
+func rewriteComparisonOnColumn(ctx *session.Context, sources []*ast.TableSource, cond ast.ExprNode) (ast.ExprNode, error) {
+	b, ok := cond.(*ast.BinaryOperationExpr)
+	if !ok {
+		return nil, nil
+	}
+	op, ok := comparisonOps[b.Op]
+	if !ok {
+		return nil, nil
+	}
+	left, right := b.L, b.R
+	if _, ok := left.(ast.ValueExpr); ok {
+		// 10 < id + 1  =>  id + 1 > 10
+		left, right, op = right, left, reversedOps[op]
+	}
+	value, ok := right.(ast.ValueExpr)
+	if !ok {
+		return nil, nil
+	}
+
+	var col *ast.ColumnNameExpr
+	var lower, upper string
+	switch l := left.(type) {
+	case *ast.FuncCallExpr:
+		if len(l.Args) != 1 {
+			return nil, nil
+		}
+		if col, ok = l.Args[0].(*ast.ColumnNameExpr); !ok {
+			return nil, nil
+		}
+		switch l.FnName.L {
+		case "date":
+			s, ok := value.GetValue().(string)
+			if !ok {
+				return nil, nil
+			}
+			day, err := time.Parse("2006-01-02", s)
+			if err != nil {
+				return nil, nil
+			}
+			lower, upper = day.Format("2006-01-02"), day.AddDate(0, 0, 1).Format("2006-01-02")
+		case "year":
+			year, ok := intValue(value)
+			if !ok || year < 1000 || year > 9998 {
+				return nil, nil
+			}
+			lower, upper = fmt.Sprintf("%d-01-01", year), fmt.Sprintf("%d-01-01", year+1)
+		default:
+			return nil, nil
+		}
+	case *ast.BinaryOperationExpr:
+		if l.Op != opcode.Plus && l.Op != opcode.Minus {
+			return nil, nil
+		}
+		if col, ok = l.L.(*ast.ColumnNameExpr); !ok {
+			return nil, nil
+		}
+		n, ok := l.R.(ast.ValueExpr)
+		if !ok {
+			return nil, nil
+		}
+		delta, ok := intValue(n)
+		if !ok {
+			return nil, nil
+		}
+		m, ok := intValue(value)
+		if !ok {
+			return nil, nil
+		}
+		if l.Op == opcode.Plus {
+			m -= delta
+		} else {
+			m += delta
+		}
+		if !isIndexedOrUnknown(ctx, sources, col) {
+			return nil, nil
+		}
+		colText, err := restore(col)
+		if err != nil {
+			return nil, err
+		}
+		return parseExpr(fmt.Sprintf("%s %s %d", colText, op, m))
+	default:
+		return nil, nil
+	}
+
+	if !isIndexedOrUnknown(ctx, sources, col) {
+		return nil, nil
+	}
+	colText, err := restore(col)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "=":
+		return parseExpr(fmt.Sprintf("%s >= '%s' AND %s < '%s'", colText, lower, colText, upper))
+	case "<":
+		return parseExpr(fmt.Sprintf("%s < '%s'", colText, lower))
+	case "<=":
+		return parseExpr(fmt.Sprintf("%s < '%s'", colText, upper))
+	case ">":
+		return parseExpr(fmt.Sprintf("%s >= '%s'", colText, upper))
+	case ">=":
+		return parseExpr(fmt.Sprintf("%s >= '%s'", colText, lower))
+	}
+	return nil, nil
+}
+
+var comparisonOps = map[opcode.Op]string{
+	opcode.EQ: "=",
+	opcode.LT: "<",
+	opcode.LE: "<=",
+	opcode.GT: ">",
+	opcode.GE: ">=",
+}
+
+var reversedOps = map[string]string{
+	"=":  "=",
+	"<":  ">",
+	"<=": ">=",
+	">":  "<",
+	">=": "<=",
+}
+
+func intValue(value ast.ValueExpr) (int64, bool) {
+	switch v := value.GetValue().(type) {
+	case int64:
+		return v, true
+	case uint64:
+		if v > 1<<62 {
+			return 0, false
+		}
+		return int64(v), true
+	}
+	return 0, false
+}
+
+func isIndexedOrUnknown(ctx *session.Context, sources []*ast.TableSource, col *ast.ColumnNameExpr) bool {
+	_, createTable := tableOfColumn(ctx, sources, col.Name)
+	indexed, known := isColumnIndexed(createTable, col.Name.Name.L)
+	return indexed || !known
+}
+
+// whereOf returns the WHERE clause of the SELECT, UPDATE and DELETE statements and their tables.
+func whereOf(stmt ast.StmtNode) (*ast.ExprNode, []*ast.TableSource) {
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		return &s.Where, tableSourcesOf(s)
+	case *ast.UpdateStmt:
+		if s.TableRefs == nil {
+			return nil, nil
+		}
+		return &s.Where, util.GetTableSources(s.TableRefs.TableRefs)
+	case *ast.DeleteStmt:
+		if s.TableRefs == nil {
+			return nil, nil
+		}
+		return &s.Where, util.GetTableSources(s.TableRefs.TableRefs)
+	}
+	return nil, nil
+}
+
+var implicitConversionRewriter = &rewriter{
+	name:              "implicit_conversion",
+	rules:             []string{rulepkg.DMLCheckWhereExistImplicitConversion, ai.SQLE00179},
+	desc:              plocale.RewriteImplicitConversionDesc,
+	nonEquivalentDesc: plocale.RewriteImplicitConversionNonEquivalent,
+	rewrite:           rewriteImplicitConversion,
+}
+
+var numberPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// rewriteImplicitConversion rewrites the constants compared with the columns to the types of the columns, e.g.
+//
+//	phone = 13800000000  =>  phone = '13800000000'  (phone is VARCHAR)
+//	id IN ('1', '2')     =>  id IN (1, 2)           (id is INT)
+//
+// the column types come from the table definitions of the context.
+func rewriteImplicitConversion(ctx *session.Context, stmt ast.StmtNode) (ast.StmtNode, error) {
+	where, sources := whereOf(stmt)
+	if where == nil || *where == nil {
+		return nil, nil
+	}
+	visitor := &implicitConversionVisitor{ctx: ctx, sources: sources}
+	(*where).Accept(visitor)
+	if visitor.err != nil {
+		return nil, visitor.err
+	}
+	if !visitor.rewritten {
+		return nil, nil
+	}
+	return stmt, nil
+}
+
+type implicitConversionVisitor struct {
+	ctx       *session.Context
+	sources   []*ast.TableSource
+	rewritten bool
+	err       error
+}
+
+func (v *implicitConversionVisitor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch x := in.(type) {
+	case *ast.SubqueryExpr:
+		// the columns of the subqueries belong to other tables
+		return in, true
+	case *ast.BinaryOperationExpr:
+		if _, ok := comparisonOps[x.Op]; !ok {
+			return in, false
+		}
+		if col, ok := x.L.(*ast.ColumnNameExpr); ok {
+			x.R = v.convert(col, x.R)
+		} else if col, ok := x.R.(*ast.ColumnNameExpr); ok {
+			x.L = v.convert(col, x.L)
+		}
+	case *ast.PatternInExpr:
+		col, ok := x.Expr.(*ast.ColumnNameExpr)
+		if !ok {
+			return in, false
+		}
+		for i, item := range x.List {
+			x.List[i] = v.convert(col, item)
+		}
+	}
+	return in, false
+}
+
+func (v *implicitConversionVisitor) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, true
+}
+
+// convert returns the constant of the column type, or the expression itself if it needs no conversion.
+func (v *implicitConversionVisitor) convert(col *ast.ColumnNameExpr, expr ast.ExprNode) ast.ExprNode {
+	value, ok := expr.(ast.ValueExpr)
+	if !ok || value.GetValue() == nil || v.err != nil {
+		return expr
+	}
+	_, createTable := tableOfColumn(v.ctx, v.sources, col.Name)
+	def := getColumnDef(createTable, col.Name.Name.L)
+	if def == nil || def.Tp == nil {
+		return expr
+	}
+	s, isString := value.GetValue().(string)
+	var converted ast.ExprNode
+	var err error
+	switch def.Tp.EvalType() {
+	case types.ETString:
+		if isString {
+			return expr
+		}
+		text, err := restore(value)
+		if err != nil || !numberPattern.MatchString(text) {
+			return expr
+		}
+		converted, err = parseExpr(quoteString(text))
+		if err != nil {
+			v.err = err
+			return expr
+		}
+	case types.ETInt:
+		if !isString {
+			return expr
+		}
+		if _, parseErr := strconv.ParseInt(s, 10, 64); parseErr != nil {
+			return expr
+		}
+		converted, err = parseExpr(s)
+	case types.ETDecimal, types.ETReal:
+		if !isString || !numberPattern.MatchString(s) {
+			return expr
+		}
+		converted, err = parseExpr(s)
+	default:
+		return expr
+	}
+	if err != nil {
+		v.err = err
+		return expr
+	}
+	v.rewritten = true
+	return converted
+}
+
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+var selectAllRewriter = &rewriter{
+	name:    "select_all",
+	rules:   []string{rulepkg.DMLDisableSelectAllColumn, ai.SQLE00053},
+	desc:    plocale.RewriteSelectAllDesc,
+	rewrite: rewriteSelectAll,
+}
+
+// rewriteSelectAll expands the wildcards of the SELECT statement to the columns of the tables, the columns are
+// qualified by the tables if there are more than one table.
+func rewriteSelectAll(ctx *session.Context, stmt ast.StmtNode) (ast.StmtNode, error) {
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || sel.From == nil || sel.Fields == nil || hasUsingOrNatural(sel.From.TableRefs) {
+		return nil, nil
+	}
+	sources := tableSourcesOf(sel)
+	fields := []*ast.SelectField{}
+	expanded := false
+	for _, field := range sel.Fields.Fields {
+		if field.WildCard == nil {
+			fields = append(fields, field)
+			continue
+		}
+		matched := false
+		for _, source := range sources {
+			table, ok := source.Source.(*ast.TableName)
+			if !ok {
+				// the columns of the derived tables are unknown
+				return nil, nil
+			}
+			qualifier := source.AsName
+			if qualifier.L == "" {
+				qualifier = table.Name
+			}
+			if field.WildCard.Table.L != "" && field.WildCard.Table.L != qualifier.L {
+				continue
+			}
+			createTable, exist, err := ctx.GetCreateTableStmt(table)
+			if err != nil || !exist {
+				return nil, nil
+			}
+			for _, col := range createTable.Cols {
+				name := &ast.ColumnName{Name: col.Name.Name}
+				if len(sources) > 1 || field.WildCard.Table.L != "" {
+					name.Table = qualifier
+				}
+				fields = append(fields, &ast.SelectField{Expr: &ast.ColumnNameExpr{Name: name}})
+			}
+			matched = true
+		}
+		if !matched {
+			return nil, nil
+		}
+		expanded = true
+	}
+	if !expanded {
+		return nil, nil
+	}
+	sel.Fields.Fields = fields
+	return sel, nil
+}
+
+// hasUsingOrNatural returns whether the tables are joined by USING or NATURAL JOIN, the wildcard of which merges the
+// join columns.
+func hasUsingOrNatural(node ast.ResultSetNode) bool {
+	join, ok := node.(*ast.Join)
+	if !ok || join == nil {
+		return false
+	}
+	if len(join.Using) > 0 || join.NaturalJoin {
+		return true
+	}
+	return hasUsingOrNatural(join.Left) || hasUsingOrNatural(join.Right)
+}
+
+var keysetPaginationRewriter = &rewriter{
+	name:              "keyset_pagination",
+	rules:             []string{rulepkg.DMLCheckLimitOffsetNum, ai.SQLE00045},
+	desc:              plocale.RewriteKeysetPaginationDesc,
+	nonEquivalentDesc: plocale.RewriteKeysetPaginationNonEquivalent,
+	rewrite:           rewriteKeysetPagination,
+}
+
+// rewriteKeysetPagination rewrites
+//
+//	SELECT * FROM t WHERE a = 1 ORDER BY b LIMIT 100000, 20
+//
+// to
+//
+//	SELECT * FROM t WHERE a = 1 AND (b, id) > (?, ?) ORDER BY b, id LIMIT 20
+//
+// the primary key is appended to the sort key to make it unique if the table definition is available.
+func rewriteKeysetPagination(ctx *session.Context, stmt ast.StmtNode) (ast.StmtNode, error) {
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || sel.Limit == nil || sel.Limit.Offset == nil || sel.OrderBy == nil ||
+		sel.Distinct || sel.GroupBy != nil || sel.Having != nil {
+		return nil, nil
+	}
+	if offset, ok := sel.Limit.Offset.(ast.ValueExpr); !ok {
+		return nil, nil
+	} else if n, ok := intValue(offset); !ok || n <= 0 {
+		return nil, nil
+	}
+	sources := tableSourcesOf(sel)
+	if len(sources) != 1 {
+		return nil, nil
+	}
+	table, ok := sources[0].Source.(*ast.TableName)
+	if !ok {
+		return nil, nil
+	}
+
+	desc := sel.OrderBy.Items[0].Desc
+	keys := map[string]bool{}
+	for _, item := range sel.OrderBy.Items {
+		col, ok := item.Expr.(*ast.ColumnNameExpr)
+		if !ok || item.Desc != desc {
+			return nil, nil
+		}
+		keys[col.Name.Name.L] = true
+	}
+	if createTable, exist, err := ctx.GetCreateTableStmt(table); err == nil && exist {
+		for _, pk := range primaryKeyColumns(createTable) {
+			if !keys[pk.L] {
+				sel.OrderBy.Items = append(sel.OrderBy.Items, &ast.ByItem{
+					Expr: &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: pk}},
+					Desc: desc,
+				})
+				keys[pk.L] = true
+			}
+		}
+	}
+
+	cols := make([]string, 0, len(sel.OrderBy.Items))
+	markers := make([]string, 0, len(sel.OrderBy.Items))
+	for _, item := range sel.OrderBy.Items {
+		s, err := restore(item.Expr)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, s)
+		markers = append(markers, "?")
+	}
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	var seek ast.ExprNode
+	var err error
+	if len(cols) == 1 {
+		seek, err = parseExpr(fmt.Sprintf("%s %s ?", cols[0], op))
+	} else {
+		seek, err = parseExpr(fmt.Sprintf("(%s) %s (%s)", strings.Join(cols, ", "), op, strings.Join(markers, ", ")))
+	}
+	if err != nil {
+		return nil, err
+	}
+	where, err := and(append(conjuncts(sel.Where), seek)...)
+	if err != nil {
+		return nil, err
+	}
+	sel.Where = where
+	sel.Limit.Offset = nil
+	return sel, nil
+}
+
+func primaryKeyColumns(createTable *ast.CreateTableStmt) []model.CIStr {
+	for _, constraint := range createTable.Constraints {
+		if constraint.Tp != ast.ConstraintPrimaryKey {
+			continue
+		}
+		cols := []model.CIStr{}
+		for _, key := range constraint.Keys {
+			if key.Column == nil {
+				return nil
+			}
+			cols = append(cols, key.Column.Name)
+		}
+		return cols
+	}
+	for _, col := range createTable.Cols {
+		if util.HasOneInOptions(col.Options, ast.ColumnOptionPrimaryKey) {
+			return []model.CIStr{col.Name.Name}
+		}
+	}
+	return nil
+}
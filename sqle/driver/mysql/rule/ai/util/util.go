@@ -556,6 +556,9 @@ func GetExecutionPlan(context *session.Context, sql string) (*executor.ExplainWi
 
 // a helper function to get the execution tree plan of a SQL statement in MySQL
 func GetExecutionTreePlan(context *session.Context, sql string) (string, error) {
+	if context.GetExecutor() == nil {
+		return "", session.ErrNoExecutor
+	}
 	return context.GetExecutor().ExplainTree(sql)
 }
 
@@ -837,6 +840,9 @@ func ScanWhereStmt(fn func(expr ast.ExprNode) (skip bool), exprs ...ast.ExprNode
 
 // a helper function to return the maximum character length of a specified column in a specified table.
 func GetCurrentMaxColumnWidth(ctx *session.Context, table *ast.TableName, columnName string) (int, error) {
+	if ctx.GetExecutor() == nil {
+		return 0, session.ErrNoExecutor
+	}
 	return ctx.GetExecutor().ShowCurrentMaxColumnWidth(table.Name.O, columnName)
 }
 
@@ -845,6 +851,9 @@ func IsColumnAllNull(ctx *session.Context, tableName string, columnName string)
 	// Construct the SQL query to check for all NULL values in the specified column
 	checkSQL := fmt.Sprintf("SELECT (SELECT COUNT(*) FROM %s) - (SELECT COUNT(*) FROM %s WHERE %s IS NOT NULL) RESULT;", tableName, tableName, columnName)
 	// Execute the query and retrieve the result
+	if ctx.GetExecutor() == nil {
+		return false, session.ErrNoExecutor
+	}
 	result, err := ctx.GetExecutor().Db.Query(checkSQL)
 	if err != nil {
 		return false, fmt.Errorf("failed to execute IsColumnAllNull query: %v", err)
@@ -942,5 +951,8 @@ type TableAliasInfo struct {
 
 func getTableIndexes(context *session.Context, tableName, schemaName string) ([]*executor.TableIndexesInfo, error) {
 	schemaName = GetSchemaName(context, schemaName)
+	if context.GetExecutor() == nil {
+		return nil, session.ErrNoExecutor
+	}
 	return context.GetExecutor().GetTableIndexesInfo(supplementalQuotationMarks(schemaName), supplementalQuotationMarks(tableName))
 }
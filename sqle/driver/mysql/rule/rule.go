@@ -14,6 +14,7 @@ import (
 	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
 	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
 	"github.com/actiontech/sqle/sqle/driver/mysql/keyword"
+	"github.com/actiontech/sqle/sqle/driver/mysql/onlineddl"
 	"github.com/actiontech/sqle/sqle/driver/mysql/plocale"
 	"github.com/actiontech/sqle/sqle/driver/mysql/session"
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
@@ -67,6 +68,7 @@ const (
 	DDLCheckTableCharacterSet                          = "ddl_check_table_character_set"
 	DDLCheckIndexedColumnWithBlob                      = "ddl_check_index_column_with_blob"
 	DDLCheckAlterTableNeedMerge                        = "ddl_check_alter_table_need_merge"
+	DDLCheckAlterTableAlgorithm                        = "ddl_check_alter_table_algorithm"
 	DDLDisableDropStatement                            = "ddl_disable_drop_statement"
 	DDLCheckTableWithoutComment                        = "ddl_check_table_without_comment"
 	DDLCheckColumnWithoutComment                       = "ddl_check_column_without_comment"
@@ -1377,6 +1379,64 @@ func checkMergeAlterTable(input *RuleHandlerInput) error {
 	return nil
 }
 
+func checkAlterTableAlgorithm(input *RuleHandlerInput) error {
+	stmt, ok := input.Node.(*ast.AlterTableStmt)
+	if !ok {
+		return nil
+	}
+	version, err := input.Ctx.GetSystemVariable("version")
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		return nil
+	}
+	createTableStmt, exist, err := input.Ctx.GetCreateTableStmt(stmt.Table)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+	prediction, err := onlineddl.PredictAlterTable(version, stmt, createTableStmt)
+	if err != nil {
+		// the version is not MySQL, e.g. MariaDB
+		log.NewEntry().Warnf("predict alter table algorithm failed: %v", err)
+		return nil
+	}
+	if len(prediction.Clauses) == 0 {
+		return nil
+	}
+
+	if prediction.IsRequestUnsupported() {
+		input.Res.Add(input.Rule.Level, input.Rule.Name, plocale.Bundle.LocalizeAll(plocale.DDLCheckAlterTableAlgorithmUnsupportedMessage),
+			fmt.Sprintf("ALGORITHM=%s, LOCK=%s", prediction.RequestedAlgorithm, prediction.RequestedLock),
+			formatAlterTablePrediction(prediction.Algorithm, prediction.Lock, prediction.RebuildsTable, false))
+		return nil
+	}
+	clauses := make([]string, 0, len(prediction.Clauses))
+	for _, clause := range prediction.Clauses {
+		clauses = append(clauses, fmt.Sprintf("%s: %s", clause.Clause,
+			formatAlterTablePrediction(clause.Algorithm, clause.Lock, clause.RebuildsTable, clause.Assumed)))
+	}
+	addResult(input.Res, input.Rule, DDLCheckAlterTableAlgorithm, prediction.Version,
+		formatAlterTablePrediction(prediction.Algorithm, prediction.Lock, prediction.RebuildsTable, false),
+		strings.Join(clauses, "; "))
+	return nil
+}
+
+func formatAlterTablePrediction(algorithm ast.AlgorithmType, lock ast.LockType, rebuildsTable, assumed bool) string {
+	rebuild := "NO"
+	if rebuildsTable {
+		rebuild = "YES"
+	}
+	text := fmt.Sprintf("ALGORITHM=%s, LOCK=%s, REBUILD=%s", algorithm, lock, rebuild)
+	if assumed {
+		text += ", ASSUMED"
+	}
+	return text
+}
+
 func checkEngine(input *RuleHandlerInput) error {
 	var tableName *ast.TableName
 	var engine string
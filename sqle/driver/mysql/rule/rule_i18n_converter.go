@@ -47,10 +47,14 @@ type SourceHandler struct {
 	NotSupportExecutedSQLAuditStmts []ast.Node
 }
 
+// sourceRules 记录生成 RuleHandler 的 SourceRule，用于加载新的语言包后重新翻译规则
+var sourceRules = map[string]*SourceRule{}
+
 // GenerateI18nRuleHandlers 根据规则初始化时定义的 SourceHandler 生成支持多语言的 RuleHandler
 func GenerateI18nRuleHandlers(bundle *i18nPkg.Bundle, shs []*SourceHandler, dbType string) []RuleHandler {
 	rhs := make([]RuleHandler, len(shs))
 	for k, v := range shs {
+		sourceRules[v.Rule.Name] = &v.Rule
 		rhs[k] = RuleHandler{
 			Rule:                            *ConvertSourceRule(bundle, &v.Rule, dbType),
 			Message:                         v.Message,
@@ -94,14 +98,45 @@ func ConvertSourceRule(bundle *i18nPkg.Bundle, sr *SourceRule, dbType string) *d
 func genAllI18nRuleInfo(bundle *i18nPkg.Bundle, sr *SourceRule) map[language.Tag]*driverV2.RuleInfo {
 	result := make(map[language.Tag]*driverV2.RuleInfo, len(bundle.LanguageTags()))
 	for _, langTag := range bundle.LanguageTags() {
-		newInfo := &driverV2.RuleInfo{
-			Desc:       bundle.LocalizeMsgByLang(langTag, sr.Desc),
-			Annotation: bundle.LocalizeMsgByLang(langTag, sr.Annotation),
-			Category:   bundle.LocalizeMsgByLang(langTag, sr.Category),
-			Knowledge:  driverV2.RuleKnowledge{Content: sr.Knowledge.Content}, //todo i18n Knowledge
-		}
-
-		result[langTag] = newInfo
+		result[langTag] = genRuleInfo(bundle, langTag, sr)
 	}
 	return result
 }
+
+func genRuleInfo(bundle *i18nPkg.Bundle, langTag language.Tag, sr *SourceRule) *driverV2.RuleInfo {
+	return &driverV2.RuleInfo{
+		Desc:       bundle.LocalizeMsgByLang(langTag, sr.Desc),
+		Annotation: bundle.LocalizeMsgByLang(langTag, sr.Annotation),
+		Category:   bundle.LocalizeMsgByLang(langTag, sr.Category),
+		Knowledge:  driverV2.RuleKnowledge{Content: sr.Knowledge.Content}, //todo i18n Knowledge
+	}
+}
+
+// LocalizeRules 使用新的语言包重新翻译 AllRules 中的规则，已有的规则知识保持不变。
+// RuleHandlerMap 和 AIRuleHandlerMap 中的规则与 AllRules 共享多语言信息，因此会同步更新。
+func LocalizeRules(bundle *i18nPkg.Bundle) {
+	for _, r := range AllRules {
+		sr, ok := sourceRules[r.Name]
+		if !ok {
+			continue
+		}
+		for _, langTag := range bundle.LanguageTags() {
+			info := genRuleInfo(bundle, langTag, sr)
+			if old, ok := r.I18nRuleInfo[langTag]; ok {
+				info.Knowledge = old.Knowledge
+			}
+			r.I18nRuleInfo[langTag] = info
+		}
+		for _, p := range r.Params {
+			for _, sp := range sr.Params {
+				if sp.Key != p.Key {
+					continue
+				}
+				p.Desc = bundle.LocalizeMsgByLang(i18nPkg.DefaultLang, sp.Desc)
+				for langTag, desc := range bundle.LocalizeAll(sp.Desc) {
+					p.I18nDesc.SetStrInLang(langTag, desc)
+				}
+			}
+		}
+	}
+}
@@ -541,6 +541,19 @@ var sourceRuleHandlers = []*SourceHandler{
 		OnlyAuditNotExecutedSQL: true,
 		Func:                    checkMergeAlterTable,
 	},
+	{
+		Rule: SourceRule{
+			Name:         DDLCheckAlterTableAlgorithm,
+			Desc:         plocale.DDLCheckAlterTableAlgorithmDesc,
+			Annotation:   plocale.DDLCheckAlterTableAlgorithmAnnotation,
+			Level:        driverV2.RuleLevelNotice,
+			Category:     plocale.RuleTypeUsageSuggestion,
+			AllowOffline: false,
+		},
+		Message:                 plocale.DDLCheckAlterTableAlgorithmMessage,
+		OnlyAuditNotExecutedSQL: true,
+		Func:                    checkAlterTableAlgorithm,
+	},
 	{
 		Rule: SourceRule{
 			Name:         DMLDisableSelectAllColumn,
@@ -1,12 +1,16 @@
 package rule
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
 	"github.com/actiontech/sqle/sqle/driver/mysql/plocale"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
 )
 
 func TestInspectResults(t *testing.T) {
@@ -191,3 +195,28 @@ func TestCheckAlterTableRedundantIndex(t *testing.T) {
 	assert.Equal(t, len(redundancy), 0, "indexs3,redundancy")
 
 }
+
+func TestLocalizeRules(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "active.ja.toml"), []byte(`DDLCheckIndexCountDesc = "インデックス数が閾値を超えないこと"`), 0644))
+	bundle, err := locale.NewBundleWithDir(os.DirFS("../plocale"), dir)
+	assert.NoError(t, err)
+	defer func() {
+		for _, r := range AllRules {
+			delete(r.I18nRuleInfo, language.Japanese)
+			for _, p := range r.Params {
+				delete(p.I18nDesc, language.Japanese)
+			}
+		}
+	}()
+
+	rule := RuleHandlerMap[DDLCheckIndexCount].Rule
+	knowledge := rule.I18nRuleInfo[i18nPkg.DefaultLang].Knowledge
+	LocalizeRules(bundle)
+	assert.Equal(t, "インデックス数が閾値を超えないこと", rule.I18nRuleInfo.GetRuleInfoByLangTag(language.Japanese).Desc)
+	// the untranslated text falls back to English
+	assert.Equal(t, rule.I18nRuleInfo[language.English].Annotation, rule.I18nRuleInfo[language.Japanese].Annotation)
+	assert.Equal(t, "Maximum number of indexes", rule.Params[0].I18nDesc.GetStrInLang(language.Japanese))
+	// the knowledge is kept
+	assert.Equal(t, knowledge, rule.I18nRuleInfo[i18nPkg.DefaultLang].Knowledge)
+}
@@ -466,6 +466,42 @@ func (c *Context) UpdateContext(node ast.Node) {
 	}
 }
 
+// LoadSchemaSnapshot loads the schemas and tables from the DDL of a schema snapshot, such as the output of
+// "mysqldump --no-data". The schemas are regarded as all loaded, so the context can answer whether a schema or a
+// table exists without the executor.
+func (c *Context) LoadSchemaSnapshot(nodes []ast.Node) {
+	c.setSchemasLoad()
+	for _, node := range nodes {
+		switch s := node.(type) {
+		case *ast.CreateDatabaseStmt:
+			c.addSchema(s.Name)
+		case *ast.UseStmt:
+			c.addSchema(s.DBName)
+			c.SetCurrentSchema(s.DBName)
+		case *ast.CreateTableStmt:
+			schemaName := c.GetSchemaName(s.Table)
+			c.addSchema(schemaName)
+			c.addTable(schemaName, s.Table.Name.String(),
+				&TableInfo{
+					sizeLoad:      true,
+					isLoad:        true,
+					OriginalTable: s,
+					AlterTables:   []*ast.AlterTableStmt{},
+				})
+		case *ast.CreateViewStmt:
+			schemaName := c.GetSchemaName(s.ViewName)
+			c.addSchema(schemaName)
+			if schema, ok := c.getSchema(schemaName); ok {
+				viewName := s.ViewName.Name.String()
+				if c.IsLowerCaseTableName() {
+					viewName = strings.ToLower(viewName)
+				}
+				schema.Views[viewName] = &ViewInfo{isLoad: true}
+			}
+		}
+	}
+}
+
 // GetSchemaName get schema name from AST or current schema.
 func (c *Context) GetSchemaName(stmt *ast.TableName) string {
 	if stmt.Schema.String() == "" {
@@ -1299,7 +1335,14 @@ func (c *Context) GetColumnCardinality(tn *ast.TableName, columnName string) (in
 	return *ti.columns[columnName].cardinality, nil
 }
 
+// ErrNoExecutor is returned when the context without the connection, such as the one loaded from a schema snapshot, is
+// asked for the information only the instance has.
+var ErrNoExecutor = errors.New("the context has no connection to the instance")
+
 func (c *Context) UseSchema(schemaName string) error {
+	if c.e == nil {
+		return nil
+	}
 	_, err := c.e.Db.Exec(fmt.Sprintf("use `%s`", schemaName))
 	if err != nil {
 		return errors.Wrap(err, "exec use schema")
@@ -1312,6 +1355,9 @@ func (c *Context) GetExecutor() *executor.Executor {
 }
 
 func (c *Context) GetTableIndexesInfo(schema, tableName string) ([]*executor.TableIndexesInfo, error) {
+	if c.e == nil {
+		return nil, ErrNoExecutor
+	}
 	return c.e.GetTableIndexesInfo(utils.SupplementalQuotationMarks(schema), utils.SupplementalQuotationMarks(tableName))
 }
 
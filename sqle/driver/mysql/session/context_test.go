@@ -4,6 +4,9 @@ import (
 	"testing"
 	"unicode"
 
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -56,3 +59,43 @@ func Test_LoadTablesLowerCaseTableNamesOpen(t *testing.T) {
 		}
 	}
 }
+
+func Test_LoadSchemaSnapshot(t *testing.T) {
+	nodes, _, err := parser.New().Parse("CREATE DATABASE db1; USE db1; CREATE TABLE t1 (id int primary key);"+
+		"CREATE TABLE db2.t2 (id int); CREATE VIEW v1 AS SELECT * FROM t1;", "", "")
+	assert.NoError(t, err)
+	stmts := make([]ast.Node, 0, len(nodes))
+	for _, node := range nodes {
+		stmts = append(stmts, node)
+	}
+
+	context := NewContext(nil)
+	context.AddSystemVariable(SysVarLowerCaseTableNames, "0")
+	context.LoadSchemaSnapshot(stmts)
+	assert.Equal(t, "db1", context.CurrentSchema())
+
+	for _, tc := range []struct {
+		schema string
+		table  string
+		exist  bool
+	}{
+		{"", "t1", true},
+		{"db2", "t2", true},
+		{"", "t2", false},
+		{"db3", "t1", false},
+	} {
+		exist, err := context.IsTableExist(&ast.TableName{Schema: model.NewCIStr(tc.schema), Name: model.NewCIStr(tc.table)})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.exist, exist, tc.table)
+	}
+
+	stmt, exist, err := context.GetCreateTableStmt(&ast.TableName{Name: model.NewCIStr("t1")})
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Len(t, stmt.Cols, 1)
+
+	exist, err = context.IsSchemaExist("db3")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	assert.True(t, context.hasView("db1", "v1"))
+}
@@ -136,6 +136,9 @@ func GetAffectedRowNum(ctx context.Context, originSql string, conn *executor.Exe
 		return affetcCount, nil
 	}
 
+	if conn == nil {
+		return 0, errors.New("no connection to count the affected rows")
+	}
 	_, row, err := conn.Db.QueryWithContext(ctx, affectedRowSql)
 	if err != nil {
 		return 0, fmt.Errorf("get affected rows failed, sql statement: %s, error: %v", affectedRowSql, err)
@@ -0,0 +1,440 @@
+package mysql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"github.com/actiontech/sqle/sqle/driver/mysql/plocale"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/utils"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// indexPageFillFactor is the average fill factor of the pages of a secondary index built by random inserts
+	indexPageFillFactor = 0.7
+	// indexRecordOverhead is the bytes of the record header and the directory slot of an index record
+	indexRecordOverhead = 8
+	// maxIndexNameLength is the max length of the identifiers of MySQL
+	maxIndexNameLength = 64
+)
+
+// workloadAdvisorNames are the advisors whose advices are plain column indexes, which can be merged across the
+// workload. The function and prefix index advisors need virtual columns or functional key parts.
+var workloadAdvisorNames = map[string]bool{
+	"three_star_index_advisor": true,
+	"join_index_advisor":       true,
+}
+
+// WorkloadQuery is a statement of the workload, Weight is its share of the workload, e.g. its total execution time.
+type WorkloadQuery struct {
+	SQLID  string
+	SQL    string
+	Weight float64
+}
+
+type WorkloadIndex struct {
+	TableName string
+	IndexName string
+	Columns   []string
+	CreateSQL string
+	// Benefit is the total weight of the queries served by the index
+	Benefit float64
+	// WriteWeight is the total weight of the writes in the workload which maintain the index
+	WriteWeight float64
+	// WriteAmplification is the ratio of the index trees an INSERT to the table writes after and before the index
+	// is added, the indexes ranked before it are considered added.
+	WriteAmplification float64
+	// StorageBytes is the estimated size of the index
+	StorageBytes int64
+	// SQLIDs are the queries served by the index
+	SQLIDs []string
+	// Reason explains why the index is rejected, it is empty for the accepted indexes
+	Reason i18nPkg.I18nStr
+}
+
+// WorkloadIndexPlan is the indexes advised for the workload, the accepted indexes are ranked by their benefit minus
+// their write weight.
+type WorkloadIndexPlan struct {
+	Indexes  []*WorkloadIndex
+	Rejected []*WorkloadIndex
+}
+
+// AdviseWorkloadIndexes advises the indexes for the workload of a schema. The index advices of each query are
+// merged, an index whose columns are the prefix of another one is served by the longer one. The indexes covered by
+// the existing ones, outweighed by their write cost or exceeding maxIndexCount of the table are rejected. The
+// context should be connected to the instance since the advisors need the execution plans.
+func AdviseWorkloadIndexes(l *logrus.Entry, ctx *session.Context, queries []*WorkloadQuery, params params.Params, maxIndexCount int) *WorkloadIndexPlan {
+	candidates := []*WorkloadIndex{}
+	writes := []*workloadWrite{}
+	for _, query := range queries {
+		node, err := util.ParseOneSql(query.SQL)
+		if err != nil {
+			l.Warnf("workload advisor skips the query %s which can not be parsed: %v", query.SQLID, err)
+			continue
+		}
+		if write := getWorkloadWrite(node, query.Weight); write != nil {
+			writes = append(writes, write)
+			continue
+		}
+		candidates = append(candidates, getWorkloadIndexCandidates(l, ctx, node, query, params)...)
+	}
+	return planWorkloadIndexes(ctx, candidates, writes, maxIndexCount)
+}
+
+func getWorkloadIndexCandidates(l *logrus.Entry, ctx *session.Context, node ast.Node, query *WorkloadQuery, params params.Params) []*WorkloadIndex {
+	if !canOptimize(l, ctx, node) {
+		return nil
+	}
+	extractor := &util.TableSourceExtractor{TableSources: map[string]*ast.TableSource{}}
+	node.Accept(extractor)
+
+	candidates := []*WorkloadIndex{}
+	for _, meta := range AdvisorMetaList {
+		if !workloadAdvisorNames[meta.advisorName] {
+			continue
+		}
+		for _, advice := range meta.newFunction(ctx, l, node, params).GiveAdvices() {
+			// the join index advisor gives the alias of the driven table
+			tableName := advice.TableName
+			for name, source := range extractor.TableSources {
+				if strings.EqualFold(name, advice.TableName) {
+					if table, ok := source.Source.(*ast.TableName); ok {
+						tableName = table.Name.O
+					}
+					break
+				}
+			}
+			columns := append([]string{}, advice.IndexedColumns...)
+			if meta.advisorName == "join_index_advisor" {
+				// the columns of a join condition are all equal conditions, the order does not matter
+				sort.Strings(columns)
+			}
+			candidates = append(candidates, &WorkloadIndex{
+				TableName: tableName,
+				Columns:   columns,
+				Benefit:   query.Weight,
+				SQLIDs:    []string{query.SQLID},
+			})
+		}
+	}
+	return candidates
+}
+
+// workloadWrite is a write of the workload, columns is nil if the write maintains all the indexes of the table.
+type workloadWrite struct {
+	tableName string
+	columns   map[string]bool
+	weight    float64
+}
+
+func getWorkloadWrite(node ast.Node, weight float64) *workloadWrite {
+	var refs *ast.Join
+	var columns map[string]bool
+	switch stmt := node.(type) {
+	case *ast.InsertStmt:
+		if stmt.Table != nil {
+			refs = stmt.Table.TableRefs
+		}
+	case *ast.DeleteStmt:
+		if stmt.TableRefs != nil {
+			refs = stmt.TableRefs.TableRefs
+		}
+	case *ast.UpdateStmt:
+		if stmt.TableRefs != nil {
+			refs = stmt.TableRefs.TableRefs
+		}
+		columns = map[string]bool{}
+		for _, assignment := range stmt.List {
+			columns[assignment.Column.Name.L] = true
+		}
+	default:
+		return nil
+	}
+	write := &workloadWrite{columns: columns, weight: weight}
+	if refs == nil {
+		return write
+	}
+	// the multi-table writes are counted on the first table only
+	for _, source := range util.GetTableSources(refs) {
+		if table, ok := source.Source.(*ast.TableName); ok {
+			write.tableName = table.Name.O
+			break
+		}
+	}
+	return write
+}
+
+// maintains returns whether the write maintains the index on the columns.
+func (w *workloadWrite) maintains(tableName string, columns []string) bool {
+	if !strings.EqualFold(w.tableName, tableName) {
+		return false
+	}
+	if w.columns == nil {
+		return true
+	}
+	for _, col := range columns {
+		if w.columns[strings.ToLower(col)] {
+			return true
+		}
+	}
+	return false
+}
+
+func planWorkloadIndexes(ctx *session.Context, candidates []*WorkloadIndex, writes []*workloadWrite, maxIndexCount int) *WorkloadIndexPlan {
+	plan := &WorkloadIndexPlan{Indexes: []*WorkloadIndex{}, Rejected: []*WorkloadIndex{}}
+	indexes := mergeWorkloadIndexes(candidates)
+
+	tables := map[string]*workloadTable{}
+	ranked := []*WorkloadIndex{}
+	for _, index := range indexes {
+		key := strings.ToLower(index.TableName)
+		table, ok := tables[key]
+		if !ok {
+			table = newWorkloadTable(ctx, index.TableName)
+			tables[key] = table
+		}
+		if table.createTable == nil {
+			// the advices are given on the existing tables, the table may be dropped after that
+			continue
+		}
+		if existing := table.coveringIndex(index.Columns); existing != "" {
+			index.Reason = plocale.Bundle.LocalizeAllWithArgs(plocale.WorkloadIndexCoveredByExisting, existing)
+			plan.Rejected = append(plan.Rejected, index)
+			continue
+		}
+		for _, write := range writes {
+			if write.maintains(index.TableName, index.Columns) {
+				index.WriteWeight += write.weight
+			}
+		}
+		if index.WriteWeight > index.Benefit {
+			index.Reason = plocale.Bundle.LocalizeAllWithArgs(plocale.WorkloadIndexWriteOutweighRead, index.WriteWeight, index.Benefit)
+			plan.Rejected = append(plan.Rejected, index)
+			continue
+		}
+		ranked = append(ranked, index)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Benefit-ranked[i].WriteWeight > ranked[j].Benefit-ranked[j].WriteWeight
+	})
+	for _, index := range ranked {
+		table := tables[strings.ToLower(index.TableName)]
+		if maxIndexCount > 0 && table.indexCount >= maxIndexCount {
+			index.Reason = plocale.Bundle.LocalizeAllWithArgs(plocale.WorkloadIndexExceedCountLimit, index.TableName, maxIndexCount)
+			plan.Rejected = append(plan.Rejected, index)
+			continue
+		}
+		index.WriteAmplification = float64(table.indexCount+2) / float64(table.indexCount+1)
+		index.StorageBytes = table.estimateIndexBytes(index.Columns)
+		index.IndexName = table.indexName(index.Columns)
+		index.CreateSQL = fmt.Sprintf("ALTER TABLE `%s` ADD INDEX `%s` (%s);", index.TableName, index.IndexName,
+			"`"+strings.Join(index.Columns, "`,`")+"`")
+		table.indexCount++
+		plan.Indexes = append(plan.Indexes, index)
+	}
+	return plan
+}
+
+// mergeWorkloadIndexes deduplicates the index candidates, and merges an index whose columns are the prefix of another
+// one into the longer one.
+func mergeWorkloadIndexes(candidates []*WorkloadIndex) []*WorkloadIndex {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].Columns) > len(candidates[j].Columns)
+	})
+	merged := []*WorkloadIndex{}
+	for _, candidate := range candidates {
+		var into *WorkloadIndex
+		for _, index := range merged {
+			if strings.EqualFold(index.TableName, candidate.TableName) && isColumnsPrefix(candidate.Columns, index.Columns) {
+				into = index
+				break
+			}
+		}
+		if into == nil {
+			merged = append(merged, candidate)
+			continue
+		}
+		for _, sqlId := range candidate.SQLIDs {
+			if !utils.StringsContains(into.SQLIDs, sqlId) {
+				into.SQLIDs = append(into.SQLIDs, sqlId)
+				into.Benefit += candidate.Benefit
+			}
+		}
+	}
+	return merged
+}
+
+// isColumnsPrefix returns whether the columns are the leftmost prefix of the index columns.
+func isColumnsPrefix(columns, indexColumns []string) bool {
+	if len(columns) == 0 || len(columns) > len(indexColumns) {
+		return false
+	}
+	for i, col := range columns {
+		if !strings.EqualFold(col, indexColumns[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+type workloadTable struct {
+	createTable *ast.CreateTableStmt
+	rowCount    int
+	indexCount  int
+	indexes     map[string][]string
+}
+
+func newWorkloadTable(ctx *session.Context, name string) *workloadTable {
+	table := &workloadTable{indexes: map[string][]string{}}
+	tableName := &ast.TableName{Name: model.NewCIStr(name)}
+	createTable, exist, err := ctx.GetCreateTableStmt(tableName)
+	if err != nil || !exist {
+		return table
+	}
+	table.createTable = createTable
+	// the row count is used to estimate the storage only
+	table.rowCount, _ = ctx.GetTableRowCount(tableName)
+	for _, constraint := range createTable.Constraints {
+		columns := []string{}
+		for _, key := range constraint.Keys {
+			if key.Column != nil {
+				columns = append(columns, key.Column.Name.O)
+			}
+		}
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey:
+			table.indexes["PRIMARY"] = columns
+		case ast.ConstraintIndex, ast.ConstraintKey, ast.ConstraintUniq, ast.ConstraintUniqIndex, ast.ConstraintUniqKey:
+			table.indexes[constraint.Name] = columns
+			table.indexCount++
+		}
+	}
+	for _, col := range createTable.Cols {
+		if util.HasOneInOptions(col.Options, ast.ColumnOptionPrimaryKey) {
+			table.indexes["PRIMARY"] = []string{col.Name.Name.O}
+		} else if util.HasOneInOptions(col.Options, ast.ColumnOptionUniqKey) {
+			table.indexes[col.Name.Name.O] = []string{col.Name.Name.O}
+			table.indexCount++
+		}
+	}
+	return table
+}
+
+// coveringIndex returns the name of the existing index whose leftmost prefix is the columns.
+func (t *workloadTable) coveringIndex(columns []string) string {
+	names := make([]string, 0, len(t.indexes))
+	for name := range t.indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if isColumnsPrefix(columns, t.indexes[name]) {
+			return name
+		}
+	}
+	return ""
+}
+
+// indexName returns the name of the index on the columns which does not conflict with the existing indexes.
+func (t *workloadTable) indexName(columns []string) string {
+	base := "idx_" + strings.ToLower(strings.Join(columns, "_"))
+	if len(base) > maxIndexNameLength-3 {
+		base = base[:maxIndexNameLength-3]
+	}
+	name := base
+	for i := 1; ; i++ {
+		if _, ok := t.indexes[name]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	t.indexes[name] = columns
+	return name
+}
+
+// estimateIndexBytes estimates the size of the secondary index on the columns, each index record has the index
+// columns and the primary key columns.
+func (t *workloadTable) estimateIndexBytes(columns []string) int64 {
+	var recordBytes int64 = indexRecordOverhead
+	for _, col := range columns {
+		recordBytes += columnBytes(t.createTable, col)
+	}
+	pk, ok := t.indexes["PRIMARY"]
+	if !ok {
+		// the hidden row id of InnoDB
+		recordBytes += 6
+	}
+	for _, col := range pk {
+		if !utils.StringsContains(columns, col) {
+			recordBytes += columnBytes(t.createTable, col)
+		}
+	}
+	return int64(float64(int64(t.rowCount)*recordBytes) / indexPageFillFactor)
+}
+
+// columnBytes estimates the average bytes of the column stored in an index, the variable-length strings are assumed
+// half full.
+func columnBytes(createTable *ast.CreateTableStmt, name string) int64 {
+	var def *ast.ColumnDef
+	for _, col := range createTable.Cols {
+		if col.Name.Name.L == strings.ToLower(name) {
+			def = col
+			break
+		}
+	}
+	if def == nil || def.Tp == nil {
+		return 8
+	}
+	var bytes int64
+	switch def.Tp.Tp {
+	case mysql.TypeTiny, mysql.TypeYear:
+		bytes = 1
+	case mysql.TypeShort:
+		bytes = 2
+	case mysql.TypeInt24, mysql.TypeDate, mysql.TypeDuration:
+		bytes = 3
+	case mysql.TypeLong, mysql.TypeFloat, mysql.TypeTimestamp:
+		bytes = 4
+	case mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeDatetime:
+		bytes = 8
+	case mysql.TypeNewDecimal:
+		bytes = int64(def.Tp.Flen)/2 + 1
+	case mysql.TypeString:
+		bytes = int64(def.Tp.Flen) * charsetMaxBytes(def.Tp.Charset)
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		bytes = int64(def.Tp.Flen)*charsetMaxBytes(def.Tp.Charset)/2 + 2
+	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		// the index on a BLOB or TEXT column should be a prefix index, whose max length is 767 bytes
+		bytes = 767
+	default:
+		bytes = 8
+	}
+	if bytes <= 0 {
+		bytes = 8
+	}
+	if !util.HasOneInOptions(def.Options, ast.ColumnOptionNotNull, ast.ColumnOptionPrimaryKey) {
+		// the null bitmap
+		bytes++
+	}
+	return bytes
+}
+
+func charsetMaxBytes(charset string) int64 {
+	switch strings.ToLower(charset) {
+	case "latin1", "ascii", "binary":
+		return 1
+	case "utf8", "utf8mb3":
+		return 3
+	default:
+		return 4
+	}
+}
@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeWorkloadIndexes(t *testing.T) {
+	merged := mergeWorkloadIndexes([]*WorkloadIndex{
+		{TableName: "t1", Columns: []string{"a"}, Benefit: 10, SQLIDs: []string{"q1"}},
+		{TableName: "t1", Columns: []string{"a", "b"}, Benefit: 5, SQLIDs: []string{"q2"}},
+		{TableName: "T1", Columns: []string{"A"}, Benefit: 5, SQLIDs: []string{"q2"}},
+		{TableName: "t1", Columns: []string{"b"}, Benefit: 1, SQLIDs: []string{"q3"}},
+		{TableName: "t2", Columns: []string{"a"}, Benefit: 2, SQLIDs: []string{"q4"}},
+	})
+	assert.Len(t, merged, 3)
+	assert.Equal(t, []string{"a", "b"}, merged[0].Columns)
+	assert.Equal(t, float64(15), merged[0].Benefit)
+	assert.Equal(t, []string{"q2", "q1"}, merged[0].SQLIDs)
+	assert.Equal(t, []string{"b"}, merged[1].Columns)
+	assert.Equal(t, "t2", merged[2].TableName)
+}
+
+func TestPlanWorkloadIndexes(t *testing.T) {
+	writeOf := func(sql string, weight float64) *workloadWrite {
+		node, err := util.ParseOneSql(sql)
+		assert.NoError(t, err)
+		return getWorkloadWrite(node, weight)
+	}
+	candidates := []*WorkloadIndex{
+		// covered by idx_1
+		{TableName: "exist_tb_1", Columns: []string{"v1"}, Benefit: 10, SQLIDs: []string{"q1"}},
+		// outweighed by the inserts
+		{TableName: "exist_tb_1", Columns: []string{"v2"}, Benefit: 1, SQLIDs: []string{"q2"}},
+		{TableName: "exist_tb_2", Columns: []string{"v1"}, Benefit: 10, SQLIDs: []string{"q3"}},
+		{TableName: "exist_tb_2", Columns: []string{"v1", "v2"}, Benefit: 5, SQLIDs: []string{"q4"}},
+		// exceeds the index count limit
+		{TableName: "exist_tb_2", Columns: []string{"user_id"}, Benefit: 4, SQLIDs: []string{"q5"}},
+		// the table does not exist
+		{TableName: "not_exist_tb", Columns: []string{"v1"}, Benefit: 100, SQLIDs: []string{"q6"}},
+	}
+	writes := []*workloadWrite{
+		writeOf("INSERT INTO exist_tb_1 (v1, v2) VALUES ('a', 'b')", 3),
+		writeOf("UPDATE exist_tb_2 SET v2 = 'a' WHERE id = 1", 1),
+		writeOf("UPDATE exist_tb_2 SET user_id = 1 WHERE id = 1", 1),
+	}
+	plan := planWorkloadIndexes(session.NewMockContext(nil), candidates, writes, 2)
+
+	assert.Len(t, plan.Indexes, 1)
+	index := plan.Indexes[0]
+	assert.Equal(t, "exist_tb_2", index.TableName)
+	assert.Equal(t, []string{"v1", "v2"}, index.Columns)
+	assert.Equal(t, "idx_v1_v2", index.IndexName)
+	assert.Equal(t, "ALTER TABLE `exist_tb_2` ADD INDEX `idx_v1_v2` (`v1`,`v2`);", index.CreateSQL)
+	assert.Equal(t, float64(15), index.Benefit)
+	assert.Equal(t, float64(1), index.WriteWeight)
+	assert.Equal(t, 1.5, index.WriteAmplification)
+	assert.ElementsMatch(t, []string{"q3", "q4"}, index.SQLIDs)
+	assert.Empty(t, index.Reason)
+
+	assert.Len(t, plan.Rejected, 3)
+	rejected := map[string]*WorkloadIndex{}
+	for _, index := range plan.Rejected {
+		assert.NotEmpty(t, index.Reason)
+		rejected[index.SQLIDs[0]] = index
+	}
+	assert.Contains(t, rejected, "q1")
+	assert.Contains(t, rejected, "q2")
+	assert.Equal(t, float64(3), rejected["q2"].WriteWeight)
+	assert.Contains(t, rejected, "q5")
+}
+
+func TestEstimateWorkloadIndexBytes(t *testing.T) {
+	table := newWorkloadTable(session.NewMockContext(nil), "exist_tb_1")
+	table.rowCount = 1000
+	// v1 varchar(255) utf8mb4 not null: 255*4/2+2, id bigint: 8, overhead: 8
+	var recordBytes int64 = 512 + 8 + 8
+	assert.Equal(t, int64(float64(1000*recordBytes)/indexPageFillFactor), table.estimateIndexBytes([]string{"v1"}))
+	assert.Equal(t, 2, table.indexCount)
+	assert.Equal(t, "uniq_1", table.coveringIndex([]string{"v1", "v2"}))
+	assert.Equal(t, "", table.coveringIndex([]string{"v2"}))
+}
@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	protoV2 "github.com/actiontech/sqle/sqle/driver/v2/proto"
 	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/metrics"
 	"github.com/actiontech/sqle/sqle/pkg/params"
 	"golang.org/x/text/language"
 
@@ -26,30 +29,86 @@ type PluginProcessorV2 struct {
 	cmdArgs           []string
 	client            *goPlugin.Client
 	meta              *driverV2.DriverMetas
+	fileName          string
 	pluginPidFilePath string
+	startedAt         time.Time
+
+	// the fields below are the state of the plugin process reported by the supervisor
+	lastCheckedAt  time.Time
+	lastCheckErr   error
+	restartCount   int
+	restartHistory []PluginRestartRecord
+	// sessions is the number of the opened plugins which are not closed, the plugin process is
+	// stopped after they are closed when the plugin is upgraded or unloaded.
+	sessions int
+	draining bool
 	sync.Mutex
 }
 
+// restart starts a new plugin process to replace the exited or unhealthy process `old`. The new process is
+// started without holding the lock, the lock is only held to swap the client. It does nothing if `old` is
+// replaced by another restart or the plugin is draining.
+func (d *PluginProcessorV2) restart(l *logrus.Entry, reason string, old *goPlugin.Client) error {
+	record := PluginRestartRecord{Time: time.Now(), Reason: reason}
+	newClient := goPlugin.NewClient(d.cfg(d.cmdBase, d.cmdArgs))
+	_, err := newClient.Client()
+
+	d.Lock()
+	if d.client != old || d.draining {
+		d.Unlock()
+		newClient.Kill()
+		return nil
+	}
+	if err == nil && d.pluginPidFilePath != "" {
+		if err = WritePidFile(d.pluginPidFilePath, int64(newClient.ReattachConfig().Pid)); err != nil {
+			newClient.Kill()
+			err = fmt.Errorf("write plugin pid file %s failed, error: %v", d.pluginPidFilePath, err)
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+		d.addRestartRecord(record)
+		d.Unlock()
+		return err
+	}
+	record.Success = true
+	d.addRestartRecord(record)
+	d.client = newClient
+	d.startedAt = record.Time
+	d.Unlock()
+
+	l.Infof("restart plugin success")
+	if d.meta != nil {
+		metrics.PluginRestarts.Inc(d.meta.PluginName)
+	}
+	old.Kill()
+	return nil
+}
+
+func (d *PluginProcessorV2) addRestartRecord(record PluginRestartRecord) {
+	d.restartCount++
+	d.restartHistory = append(d.restartHistory, record)
+	if len(d.restartHistory) > maxPluginRestartHistory {
+		d.restartHistory = d.restartHistory[len(d.restartHistory)-maxPluginRestartHistory:]
+	}
+}
+
 func (d *PluginProcessorV2) getDriverClient(l *logrus.Entry) (protoV2.DriverClient, error) {
 	var client *goPlugin.Client
 
 	d.Lock()
-	if d.client.Exited() {
+	client = d.client
+	d.Unlock()
+	if client.Exited() {
 		l.Infof("plugin process is exited, restart it")
-		newClient := goPlugin.NewClient(d.cfg(d.cmdBase, d.cmdArgs))
-		_, err := newClient.Client()
-		if err != nil {
-			d.Unlock()
+		if err := d.restart(l, pluginRestartReasonExited, client); err != nil {
 			return nil, err
 		}
-		l.Infof("restart plugin success")
-		d.client.Kill()
-		d.client = newClient
+		d.Lock()
+		client = d.client
+		d.Unlock()
 	}
 
-	client = d.client
-	d.Unlock()
-
 	cp, err := client.Client()
 	if err != nil {
 		return nil, err
@@ -156,31 +215,219 @@ func (d *PluginProcessorV2) Open(l *logrus.Entry, cfgV2 *driverV2.Config) (Plugi
 		return nil, err
 	}
 	l.Infof("call plugin interface [Init] success")
+	d.Lock()
+	d.sessions++
+	d.Unlock()
 	return &PluginImplV2{
 		client:  c,
 		Session: result.Session,
 		l:       l.WithField("session_id", result.Session.Id),
 		meta:    d.meta,
+		release: d.releaseSession,
 	}, nil
 }
 
+func (d *PluginProcessorV2) releaseSession() {
+	d.Lock()
+	if d.sessions > 0 {
+		d.sessions--
+	}
+	d.Unlock()
+}
+
 func (d *PluginProcessorV2) Stop() error {
 	d.Lock()
 	if d.client != nil {
-		log.NewEntry().Infof("stopping plugin %s", d.meta.PluginName)
+		name := d.fileName
+		if d.meta != nil {
+			name = d.meta.PluginName
+		}
+		log.NewEntry().Infof("stopping plugin %s", name)
 		d.client.Kill()
-		log.NewEntry().Infof("plugin %s stopped", d.meta.PluginName)
+		log.NewEntry().Infof("plugin %s stopped", name)
 	}
 	os.Remove(d.pluginPidFilePath)
 	d.Unlock()
 	return nil
 }
 
+// healthCheck pings the plugin process and calls the Metas RPC, it restarts the process if the check
+// fails and canRestart is true. restarted reports whether a restart is attempted. The lock is not held
+// during the RPC and the restart, so the plugin can be opened meanwhile.
+func (d *PluginProcessorV2) healthCheck(l *logrus.Entry, canRestart bool) (restarted bool, err error) {
+	d.Lock()
+	if d.draining {
+		d.Unlock()
+		return false, nil
+	}
+	client := d.client
+	d.Unlock()
+
+	err = pingPlugin(client)
+	d.setCheckResult(client, err)
+	if err == nil || !canRestart {
+		return false, err
+	}
+	reason := pluginRestartReasonUnhealthy
+	if client.Exited() {
+		reason = pluginRestartReasonExited
+	}
+	l.Warnf("plugin health check failed, restart it, error: %v", err)
+	if err := d.restart(l, reason, client); err != nil {
+		d.setCheckResult(client, err)
+		return true, err
+	}
+
+	d.Lock()
+	client = d.client
+	d.Unlock()
+	err = pingPlugin(client)
+	d.setCheckResult(client, err)
+	return true, err
+}
+
+// setCheckResult records the result of the health check, it is dropped if the checked process is replaced.
+func (d *PluginProcessorV2) setCheckResult(client *goPlugin.Client, err error) {
+	d.Lock()
+	defer d.Unlock()
+	if d.client != client {
+		return
+	}
+	d.lastCheckedAt = time.Now()
+	d.lastCheckErr = err
+}
+
+func pingPlugin(client *goPlugin.Client) error {
+	if client.Exited() {
+		return errPluginProcessExited
+	}
+	cp, err := client.Client()
+	if err != nil {
+		return err
+	}
+	if err := cp.Ping(); err != nil {
+		return err
+	}
+	rawI, err := cp.Dispense(driverV2.PluginSetName)
+	if err != nil {
+		return err
+	}
+	c, ok := rawI.(protoV2.DriverClient)
+	if !ok {
+		return fmt.Errorf("client is not implement protoV2.DriverClient")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pluginHealthCheckTimeout)
+	defer cancel()
+	_, err = c.Metas(ctx, &protoV2.Empty{})
+	return err
+}
+
+// drain stops the plugin process after the opened plugins are closed or the timeout is reached.
+func (d *PluginProcessorV2) drain(l *logrus.Entry, timeout time.Duration) {
+	d.Lock()
+	d.draining = true
+	d.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		d.Lock()
+		sessions := d.sessions
+		d.Unlock()
+		if sessions == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			l.Warnf("plugin still has %d opened sessions after %v, stop it", sessions, timeout)
+			break
+		}
+		time.Sleep(pluginDrainCheckInterval)
+	}
+	if err := d.Stop(); err != nil {
+		l.Warnf("stop plugin failed, error: %v", err)
+	}
+}
+
+// retirePidFile moves the pid of the process to a new pid file, so the pid file of the plugin file can be
+// written by the new process while this one is draining. The retired pid file is removed when the process
+// is stopped, or the residual process is killed by the next start of sqled.
+// It returns the pid file path before the retirement, which is empty if the pid file is not retired.
+func (d *PluginProcessorV2) retirePidFile() string {
+	d.Lock()
+	defer d.Unlock()
+	if d.pluginPidFilePath == "" || d.client.Exited() || d.client.ReattachConfig() == nil {
+		return ""
+	}
+	pid := d.client.ReattachConfig().Pid
+	retired := strings.TrimSuffix(d.pluginPidFilePath, ".pid") + fmt.Sprintf(".%d.pid", pid)
+	if err := WritePidFile(retired, int64(pid)); err != nil {
+		log.NewEntry().Warnf("write retired plugin pid file %s failed, error: %v", retired, err)
+		return ""
+	}
+	original := d.pluginPidFilePath
+	d.pluginPidFilePath = retired
+	return original
+}
+
+// restorePidFile moves the pid of the process back to the pid file retired by retirePidFile, it is used
+// when the process replacing this one fails to start.
+func (d *PluginProcessorV2) restorePidFile(original string) {
+	d.Lock()
+	defer d.Unlock()
+	if original == "" || d.client.Exited() || d.client.ReattachConfig() == nil {
+		return
+	}
+	if err := WritePidFile(original, int64(d.client.ReattachConfig().Pid)); err != nil {
+		log.NewEntry().Warnf("restore plugin pid file %s failed, error: %v", original, err)
+		return
+	}
+	os.Remove(d.pluginPidFilePath)
+	d.pluginPidFilePath = original
+}
+
+func (d *PluginProcessorV2) status() *PluginStatus {
+	d.Lock()
+	defer d.Unlock()
+	s := &PluginStatus{
+		FileName:         d.fileName,
+		ProtocolVersion:  driverV2.ProtocolVersion,
+		StartedAt:        d.startedAt,
+		LastCheckedAt:    d.lastCheckedAt,
+		InFlightSessions: d.sessions,
+		RestartCount:     d.restartCount,
+		RestartHistory:   append([]PluginRestartRecord{}, d.restartHistory...),
+	}
+	if d.meta != nil {
+		s.PluginName = d.meta.PluginName
+	}
+	switch {
+	case d.draining:
+		s.Status = PluginStatusDraining
+	case d.client.Exited():
+		s.Status = PluginStatusCrashed
+	case d.lastCheckErr != nil:
+		s.Status = PluginStatusUnhealthy
+	default:
+		s.Status = PluginStatusRunning
+	}
+	if d.lastCheckErr != nil {
+		s.LastError = d.lastCheckErr.Error()
+	}
+	if !d.client.Exited() {
+		if rc := d.client.ReattachConfig(); rc != nil {
+			s.Pid = rc.Pid
+		}
+	}
+	return s
+}
+
 type PluginImplV2 struct {
 	l       *logrus.Entry
 	client  protoV2.DriverClient
 	Session *protoV2.Session
 	meta    *driverV2.DriverMetas
+
+	release     func()
+	releaseOnce sync.Once
 }
 
 func (s *PluginImplV2) Backup(ctx context.Context, backupStrategy string, sql string, backupMaxRows uint64) (backupSqls []string, executeResult string, err error) {
@@ -251,6 +498,9 @@ func (s *PluginImplV2) Close(ctx context.Context) {
 		Session: s.Session,
 	})
 	s.afterLog(api, err)
+	if s.release != nil {
+		s.releaseOnce.Do(s.release)
+	}
 }
 
 func (s *PluginImplV2) KillProcess(ctx context.Context) error {
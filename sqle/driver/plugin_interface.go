@@ -3,6 +3,7 @@ package driver
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
 
 	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
@@ -80,3 +81,68 @@ type TableMeta struct {
 	driverV2.Table
 	driverV2.TableMeta
 }
+
+// ChunkedDMLExecutor is implemented by the plugins which can execute a large UPDATE/DELETE in primary
+// key range batches. Only the built-in plugins can implement it, they register themselves in ChunkedDMLPlugins.
+type ChunkedDMLExecutor interface {
+	// PlanChunkedDML checks whether the SQL can be split by primary key range and returns the range,
+	// ErrNotChunkable is returned if the SQL is not eligible.
+	PlanChunkedDML(ctx context.Context, sql string) (*ChunkedDMLPlan, error)
+	// NextDMLChunk returns the SQL restricted to the next batch of at most chunkSize rows after lastPK, lastPK
+	// is empty for the first batch. The SQL is executed by Exec of the plugin, so it is backed up like others.
+	NextDMLChunk(ctx context.Context, sql string, plan *ChunkedDMLPlan, lastPK string, chunkSize uint64) (*DMLChunk, error)
+}
+
+// ChunkedDMLPlugins are the plugins implementing ChunkedDMLExecutor, so the support is known without opening the plugin.
+var ChunkedDMLPlugins = map[string] /*plugin name*/ struct{}{}
+
+func IsChunkedDMLSupported(pluginName string) bool {
+	_, ok := ChunkedDMLPlugins[pluginName]
+	return ok
+}
+
+var ErrNotChunkable = errors.New("sql can not be executed in chunks")
+
+type ChunkedDMLPlan struct {
+	Schema   string
+	Table    string
+	PKColumn string
+	// MinPK and MaxPK are the range of the primary key when the plan is made, they are empty if the table is empty.
+	MinPK string
+	MaxPK string
+}
+
+type DMLChunk struct {
+	// StartPK is exclusive and EndPK is inclusive, StartPK is empty for the first batch
+	StartPK string
+	EndPK   string
+	// SQL is the statement restricted to the range, it is empty if Done
+	SQL string
+	// MinPK and MaxPK are set if the range of the plan is extended to the rows inserted after the plan,
+	// the max primary key is read again when the batches reach it.
+	MinPK string
+	MaxPK string
+	// Done is true if there is no row after lastPK
+	Done bool
+}
+
+// ExecutionLoadProber is implemented by the plugins which can report the load of the instance, sqled
+// throttles the execution of a task by it. Like ChunkedDMLExecutor, only the built-in plugins can implement it.
+type ExecutionLoadProber interface {
+	// ProbeExecutionLoad returns the Threads_running of the instance and the replication lag of the replicas,
+	// the replicas are replicaDSNs and the ones discovered from the instance if discoverReplicas is true.
+	ProbeExecutionLoad(ctx context.Context, replicaDSNs []string, discoverReplicas bool) (*ExecutionLoad, error)
+}
+
+type ExecutionLoad struct {
+	ThreadsRunning int64
+	Replicas       []*ReplicaLag
+}
+
+type ReplicaLag struct {
+	// Replica is the address of the replica, e.g. "10.10.10.10:3306"
+	Replica string
+	// LagSecond is -1 if the lag is unknown, e.g. the replica can not be connected or the replication is stopped.
+	LagSecond int64
+	Error     string
+}
@@ -33,16 +33,33 @@ func NewErrPluginAPINotImplement(m driverV2.OptionalModule) error {
 
 var BuiltInPluginProcessors = map[string] /*plugin name*/ PluginProcessor{}
 
+// AfterPluginLoadedHook is called after a plugin is loaded or upgraded at runtime, it is used to
+// create the rules and the default rule template of the plugin.
+var AfterPluginLoadedHook func(meta *driverV2.DriverMetas)
+
 type pluginManager struct {
+	// mutex protects the fields below, the plugins are loaded and unloaded at runtime by the supervisor
+	mutex            sync.RWMutex
 	pluginNames      []string
 	metas            map[string]driverV2.DriverMetas
 	pluginProcessors map[string]PluginProcessor
+	// pluginFiles is the plugin name of each plugin file in the plugin dir
+	pluginFiles map[string] /*file name*/ *pluginFile
+	// drainingProcessors are the upgraded or unloaded plugins waiting for the opened sessions to close
+	drainingProcessors map[*PluginProcessorV2]struct{}
+
+	pluginDir        string
+	pluginConfigList []config.PluginConfig
+	supervisor       *pluginSupervisor
 }
 
 var PluginManager = &pluginManager{
 	pluginNames:      []string{},
 	metas:            map[string]driverV2.DriverMetas{},
 	pluginProcessors: map[string]PluginProcessor{},
+	pluginFiles:      map[string]*pluginFile{},
+
+	drainingProcessors: map[*PluginProcessorV2]struct{}{},
 }
 
 func GetPluginManager() *pluginManager {
@@ -50,6 +67,8 @@ func GetPluginManager() *pluginManager {
 }
 
 func (pm *pluginManager) GetAllRules() map[string][]*driverV2.Rule {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
 	rules := map[string][]*driverV2.Rule{}
 	for _, p := range pm.pluginNames {
 		meta := pm.metas[p]
@@ -59,6 +78,8 @@ func (pm *pluginManager) GetAllRules() map[string][]*driverV2.Rule {
 }
 
 func (pm *pluginManager) GetDriverMetasOfPlugin(pluginName string) *driverV2.DriverMetas {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
 	if dm, exist := pm.metas[pluginName]; exist {
 		return &dm
 	}
@@ -66,10 +87,14 @@ func (pm *pluginManager) GetDriverMetasOfPlugin(pluginName string) *driverV2.Dri
 }
 
 func (pm *pluginManager) AllDrivers() []string {
-	return pm.pluginNames
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+	return append([]string{}, pm.pluginNames...)
 }
 
 func (pm *pluginManager) AllDriverMetas() []*driverV2.DriverMetas {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
 	metas := make([]*driverV2.DriverMetas, len(pm.metas))
 
 	for i := range pm.pluginNames {
@@ -82,6 +107,8 @@ func (pm *pluginManager) AllDriverMetas() []*driverV2.DriverMetas {
 }
 
 func (pm *pluginManager) AllLogo() map[string][]byte {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
 	logoMap := map[string][]byte{}
 	for _, pluginName := range pm.pluginNames {
 		meta := pm.metas[pluginName]
@@ -93,6 +120,8 @@ func (pm *pluginManager) AllLogo() map[string][]byte {
 }
 
 func (pm *pluginManager) AllAdditionalParams() map[string] /*driver name*/ params.Params {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
 	newParams := map[string]params.Params{}
 	for k, v := range pm.metas {
 		newParams[k] = v.DatabaseAdditionalParams.Copy()
@@ -101,6 +130,8 @@ func (pm *pluginManager) AllAdditionalParams() map[string] /*driver name*/ param
 }
 
 func (pm *pluginManager) IsOptionalModuleEnabled(pluginName string, expectModule driverV2.OptionalModule) bool {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
 	meta, ok := pm.metas[pluginName]
 	if !ok {
 		return false
@@ -108,18 +139,38 @@ func (pm *pluginManager) IsOptionalModuleEnabled(pluginName string, expectModule
 	return meta.IsOptionalModuleEnabled(expectModule)
 }
 
-func (pm *pluginManager) register(pp PluginProcessor) error {
+// register adds the plugin to the manager, file is the plugin file in the plugin dir, it is nil for the built-in plugins.
+func (pm *pluginManager) register(pp PluginProcessor, file os.FileInfo) (*driverV2.DriverMetas, error) {
 	meta, err := pp.GetDriverMetas()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
 	if _, ok := pm.metas[meta.PluginName]; ok {
-		return fmt.Errorf("duplicated driver name %s", meta.PluginName)
+		return nil, fmt.Errorf("duplicated driver name %s", meta.PluginName)
 	}
 	pm.pluginNames = append(pm.pluginNames, meta.PluginName)
 	pm.metas[meta.PluginName] = *meta
 	pm.pluginProcessors[meta.PluginName] = pp
-	return nil
+	if file != nil {
+		pm.pluginFiles[file.Name()] = newPluginFile(file, meta.PluginName)
+	}
+	return meta, nil
+}
+
+// unregisterLocked removes the plugin from the manager, the caller must hold the lock.
+func (pm *pluginManager) unregisterLocked(pluginName string) PluginProcessor {
+	pp := pm.pluginProcessors[pluginName]
+	delete(pm.metas, pluginName)
+	delete(pm.pluginProcessors, pluginName)
+	for i, name := range pm.pluginNames {
+		if name == pluginName {
+			pm.pluginNames = append(pm.pluginNames[:i:i], pm.pluginNames[i+1:]...)
+			break
+		}
+	}
+	return pp
 }
 
 func getClientConfig(cmdBase string, cmdArgs []string) *goPlugin.ClientConfig {
@@ -144,7 +195,7 @@ func getClientConfig(cmdBase string, cmdArgs []string) *goPlugin.ClientConfig {
 func (pm *pluginManager) Start(pluginDir string, pluginConfigList []config.PluginConfig) error {
 	// register built-in plugin, now is MySQL.
 	for name, b := range BuiltInPluginProcessors {
-		err := pm.register(b)
+		_, err := pm.register(b, nil)
 		if err != nil {
 			return fmt.Errorf("start built-in %s plugin failed, error: %v", name, err)
 		}
@@ -153,20 +204,12 @@ func (pm *pluginManager) Start(pluginDir string, pluginConfigList []config.Plugi
 	if pluginDir == "" {
 		return nil
 	}
+	pm.pluginDir = pluginDir
+	pm.pluginConfigList = pluginConfigList
 
 	// read plugin file
-	var plugins []os.FileInfo
-	if err := filepath.Walk(pluginDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return errors.Wrap(err, "init plugin")
-		}
-
-		if info.IsDir() || info.Mode()&0111 == 0 {
-			return nil
-		}
-		plugins = append(plugins, info)
-		return nil
-	}); err != nil {
+	plugins, err := readPluginFiles(pluginDir)
+	if err != nil {
 		return err
 	}
 
@@ -192,56 +235,91 @@ func (pm *pluginManager) Start(pluginDir string, pluginConfigList []config.Plugi
 		log.NewEntry().Warnf("stop residual plugin file path walk error: %v", err)
 	}
 	wg.Wait()
-	jdkPath := getJdkPath()
 
 	// register plugin
 	for _, p := range plugins {
-		cmdBase := filepath.Join(pluginDir, p.Name())
-		cmdArgs := make([]string, 0)
-
-		for _, pluginConfig := range pluginConfigList {
-			if p.Name() == pluginConfig.PluginName {
-				cmdBase = "sh"
-				cmdArgs = append(cmdArgs, "-c", pluginConfig.CMD)
-				break
-			}
-		}
-		if len(cmdArgs) == 0 && strings.HasSuffix(p.Name(), ".jar") {
-			javaPluginCmd := fmt.Sprintf("%s/bin/java -jar %s", jdkPath, cmdBase)
-			cmdBase = "sh"
-			cmdArgs = append(cmdArgs, "-c", javaPluginCmd)
-		}
-
-		client := goPlugin.NewClient(getClientConfig(cmdBase, cmdArgs))
-		_, err := client.Client()
-		if err != nil {
-			return fmt.Errorf("plugin %v failed to start, error: %v Please check the sqled.log for more details", p.Name(), err)
-		}
-
-		pluginPidFilePath := GetPluginPidFilePath(pluginDir, p.Name())
-		err = WritePidFile(pluginPidFilePath, int64(client.ReattachConfig().Pid))
+		pp, err := startPluginProcess(pluginDir, p.Name(), pluginConfigList)
 		if err != nil {
-			return fmt.Errorf("write plugin %s pid file failed, error: %v", pluginPidFilePath, err)
-		}
-		var pp PluginProcessor
-		switch client.NegotiatedVersion() {
-		case driverV1.ProtocolVersion:
-			pp = &PluginProcessorV1{cfg: getClientConfig, cmdBase: cmdBase, cmdArgs: cmdArgs, client: client}
-		case driverV2.ProtocolVersion:
-			pp = &PluginProcessorV2{cfg: getClientConfig, cmdBase: cmdBase, cmdArgs: cmdArgs, client: client, pluginPidFilePath: pluginPidFilePath}
+			return err
 		}
-		if err := pm.register(pp); err != nil {
+		if _, err := pm.register(pp, p); err != nil {
 			stopErr := pp.Stop()
 			if stopErr != nil {
 				log.NewEntry().Warnf("stop plugin %s failed, error: %v", p.Name(), stopErr)
 			}
 			return fmt.Errorf("unable to load plugin: %v, error: %v", p.Name(), err)
 		}
-
 	}
 	return nil
 }
 
+// readPluginFiles returns the executable files in the plugin dir.
+func readPluginFiles(pluginDir string) ([]os.FileInfo, error) {
+	var plugins []os.FileInfo
+	if err := filepath.Walk(pluginDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Wrap(err, "init plugin")
+		}
+
+		if info.IsDir() || info.Mode()&0111 == 0 {
+			return nil
+		}
+		plugins = append(plugins, info)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
+// startPluginProcess starts the process of the plugin file and writes its pid file.
+func startPluginProcess(pluginDir, fileName string, pluginConfigList []config.PluginConfig) (PluginProcessor, error) {
+	cmdBase := filepath.Join(pluginDir, fileName)
+	cmdArgs := make([]string, 0)
+
+	for _, pluginConfig := range pluginConfigList {
+		if fileName == pluginConfig.PluginName {
+			cmdBase = "sh"
+			cmdArgs = append(cmdArgs, "-c", pluginConfig.CMD)
+			break
+		}
+	}
+	if len(cmdArgs) == 0 && strings.HasSuffix(fileName, ".jar") {
+		javaPluginCmd := fmt.Sprintf("%s/bin/java -jar %s", getJdkPath(), cmdBase)
+		cmdBase = "sh"
+		cmdArgs = append(cmdArgs, "-c", javaPluginCmd)
+	}
+
+	client := goPlugin.NewClient(getClientConfig(cmdBase, cmdArgs))
+	_, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %v failed to start, error: %v Please check the sqled.log for more details", fileName, err)
+	}
+
+	pluginPidFilePath := GetPluginPidFilePath(pluginDir, fileName)
+	err = WritePidFile(pluginPidFilePath, int64(client.ReattachConfig().Pid))
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("write plugin %s pid file failed, error: %v", pluginPidFilePath, err)
+	}
+	switch client.NegotiatedVersion() {
+	case driverV1.ProtocolVersion:
+		return &PluginProcessorV1{cfg: getClientConfig, cmdBase: cmdBase, cmdArgs: cmdArgs, client: client}, nil
+	case driverV2.ProtocolVersion:
+		return &PluginProcessorV2{
+			cfg:               getClientConfig,
+			cmdBase:           cmdBase,
+			cmdArgs:           cmdArgs,
+			client:            client,
+			fileName:          fileName,
+			pluginPidFilePath: pluginPidFilePath,
+			startedAt:         time.Now(),
+		}, nil
+	}
+	client.Kill()
+	return nil, fmt.Errorf("plugin %v uses unsupported protocol version %d", fileName, client.NegotiatedVersion())
+}
+
 func getJdkPath() string {
 	nowDir, err := os.Getwd()
 	if err != nil {
@@ -254,26 +332,31 @@ func getJdkPath() string {
 
 func (pm *pluginManager) Stop() {
 	log.NewEntry().Info("plugin manager is stopping")
+	pm.stopSupervisor()
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
 	for name, pp := range pm.pluginProcessors {
 		err := pp.Stop()
 		if err != nil {
 			log.NewEntry().Warnf("stop %s plugin failed, error: %v", name, err)
 		}
 	}
-}
-
-func (pm *pluginManager) isPluginExists(pluginName string) bool {
-	if _, ok := pm.metas[pluginName]; ok {
-		return true
+	// the plugins being drained are stopped without waiting
+	for pp := range pm.drainingProcessors {
+		if err := pp.Stop(); err != nil {
+			log.NewEntry().Warnf("stop draining plugin failed, error: %v", err)
+		}
 	}
-	return false
 }
 
 func (pm *pluginManager) OpenPlugin(l *logrus.Entry, pluginName string, cfg *driverV2.Config) (Plugin, error) {
-	if !pm.isPluginExists(pluginName) {
+	pm.mutex.RLock()
+	pp, ok := pm.pluginProcessors[pluginName]
+	pm.mutex.RUnlock()
+	if !ok {
 		return nil, ErrPluginNotFound
 	}
-	return pm.pluginProcessors[pluginName].Open(l, cfg)
+	return pp.Open(l, cfg)
 }
 
 func KillResidualPluginsProcess(pidFile string) error {
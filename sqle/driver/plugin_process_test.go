@@ -0,0 +1,140 @@
+package driver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/config"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// envTestPluginName makes the test binary serve as a plugin with the name, so the tests start real plugin processes.
+const envTestPluginName = "SQLE_TEST_PLUGIN_NAME"
+
+func TestMain(m *testing.M) {
+	if name := os.Getenv(envTestPluginName); name != "" {
+		driverV2.ServePlugin(driverV2.DriverMetas{PluginName: name, DatabaseDefaultPort: 3306}, func(cfg *driverV2.Config) (driverV2.Driver, error) {
+			return nil, fmt.Errorf("plugin %s does not open sessions", name)
+		})
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func testPluginConfig(fileName, pluginName string) config.PluginConfig {
+	if pluginName == "" {
+		return config.PluginConfig{PluginName: fileName, CMD: "exit 1"}
+	}
+	return config.PluginConfig{
+		PluginName: fileName,
+		CMD:        fmt.Sprintf("%s=%s exec %s -test.run=^$", envTestPluginName, pluginName, os.Args[0]),
+	}
+}
+
+func readPid(t *testing.T, path string) int {
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	pid, err := strconv.Atoi(string(b))
+	assert.NoError(t, err)
+	return pid
+}
+
+func startTestPlugin(t *testing.T, dir, fileName, pluginName string) *PluginProcessorV2 {
+	pp, err := startPluginProcess(dir, fileName, []config.PluginConfig{testPluginConfig(fileName, pluginName)})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	v2, ok := pp.(*PluginProcessorV2)
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	_, err = v2.GetDriverMetas()
+	assert.NoError(t, err)
+	return v2
+}
+
+func TestPluginHealthCheckRestart(t *testing.T) {
+	dir := t.TempDir()
+	pp := startTestPlugin(t, dir, "test-plugin", "plugin1")
+	defer pp.Stop()
+	pidFile := GetPluginPidFilePath(dir, "test-plugin")
+
+	restarted, err := pp.healthCheck(log.NewEntry(), true)
+	assert.NoError(t, err)
+	assert.False(t, restarted)
+
+	oldPid := pp.client.ReattachConfig().Pid
+	pp.client.Kill()
+	restarted, err = pp.healthCheck(log.NewEntry(), true)
+	assert.NoError(t, err)
+	assert.True(t, restarted)
+
+	status := pp.status()
+	assert.Equal(t, PluginStatusRunning, status.Status)
+	assert.Equal(t, 1, status.RestartCount)
+	newPid := pp.client.ReattachConfig().Pid
+	assert.NotEqual(t, oldPid, newPid)
+	assert.Equal(t, newPid, readPid(t, pidFile))
+
+	// the plugin is usable while the restarted process is checked
+	_, err = pp.getDriverClient(log.NewEntry())
+	assert.NoError(t, err)
+}
+
+func TestUpgradePlugin(t *testing.T) {
+	dir := t.TempDir()
+	fileName := "test-plugin"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, fileName), []byte("plugin"), 0755))
+	info, err := os.Stat(filepath.Join(dir, fileName))
+	assert.NoError(t, err)
+	pidFile := GetPluginPidFilePath(dir, fileName)
+
+	old := startTestPlugin(t, dir, fileName, "plugin1")
+	defer old.Stop()
+	oldPid := old.client.ReattachConfig().Pid
+	pm := &pluginManager{
+		pluginNames:        []string{"plugin1"},
+		metas:              map[string]driverV2.DriverMetas{"plugin1": *old.meta},
+		pluginProcessors:   map[string]PluginProcessor{"plugin1": old},
+		pluginFiles:        map[string]*pluginFile{fileName: newPluginFile(info, "plugin1")},
+		drainingProcessors: map[*PluginProcessorV2]struct{}{},
+		pluginDir:          dir,
+	}
+
+	// the old process keeps running and owns the pid file if the new process fails to start
+	pm.pluginConfigList = []config.PluginConfig{testPluginConfig(fileName, "")}
+	assert.Error(t, pm.upgradePlugin(log.NewEntry(), info, "plugin1"))
+	assert.Equal(t, oldPid, readPid(t, pidFile))
+	assert.Equal(t, pidFile, old.pluginPidFilePath)
+	assert.False(t, old.client.Exited())
+	retired, err := filepath.Glob(filepath.Join(GetPluginPidDirPath(dir), fileName+".*.pid"))
+	assert.NoError(t, err)
+	assert.Len(t, retired, 0)
+
+	// the new process owns the pid file and the old one is stopped after it is drained
+	pm.pluginConfigList = []config.PluginConfig{testPluginConfig(fileName, "plugin2")}
+	assert.NoError(t, pm.upgradePlugin(log.NewEntry(), info, "plugin1"))
+	pm.mutex.RLock()
+	_, oldExist := pm.metas["plugin1"]
+	newPP, newExist := pm.pluginProcessors["plugin2"].(*PluginProcessorV2)
+	pm.mutex.RUnlock()
+	assert.False(t, oldExist)
+	if !assert.True(t, newExist) {
+		t.FailNow()
+	}
+	defer newPP.Stop()
+	assert.Equal(t, newPP.client.ReattachConfig().Pid, readPid(t, pidFile))
+	assert.Eventually(t, func() bool {
+		return old.client.Exited()
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		retired, _ := filepath.Glob(filepath.Join(GetPluginPidDirPath(dir), fileName+".*.pid"))
+		return len(retired) == 0
+	}, 10*time.Second, 100*time.Millisecond)
+}
@@ -0,0 +1,418 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	driverV1 "github.com/actiontech/sqle/sqle/driver/v1"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/log"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	pluginSupervisorInterval = 10 * time.Second
+	pluginHealthCheckTimeout = 10 * time.Second
+	pluginDrainTimeout       = 10 * time.Minute
+	pluginDrainCheckInterval = time.Second
+	maxPluginRestartHistory  = 20
+
+	// the restart delay doubles after each restart until the max delay, it is reset after the plugin
+	// keeps healthy for pluginRestartBackoffReset.
+	pluginRestartMinDelay     = 10 * time.Second
+	pluginRestartMaxDelay     = 5 * time.Minute
+	pluginRestartBackoffReset = 10 * time.Minute
+)
+
+const (
+	pluginRestartReasonExited    = "exited"
+	pluginRestartReasonUnhealthy = "unhealthy"
+)
+
+const (
+	PluginStatusRunning   = "running"
+	PluginStatusUnhealthy = "unhealthy"
+	PluginStatusCrashed   = "crashed"
+	PluginStatusDraining  = "draining"
+)
+
+var errPluginProcessExited = errors.New("plugin process is exited")
+
+type PluginRestartRecord struct {
+	Time    time.Time
+	Reason  string
+	Success bool
+	Error   string
+}
+
+// PluginStatus is the state of a plugin reported by the supervisor.
+type PluginStatus struct {
+	PluginName       string
+	FileName         string
+	BuiltIn          bool
+	ProtocolVersion  int
+	Status           string
+	Pid              int
+	StartedAt        time.Time
+	LastCheckedAt    time.Time
+	LastError        string
+	InFlightSessions int
+	RestartCount     int
+	RestartHistory   []PluginRestartRecord
+}
+
+// pluginFile is the plugin file loaded by the manager, the plugin is upgraded when the file is changed.
+type pluginFile struct {
+	pluginName string
+	modTime    time.Time
+	size       int64
+}
+
+func newPluginFile(info os.FileInfo, pluginName string) *pluginFile {
+	return &pluginFile{pluginName: pluginName, modTime: info.ModTime(), size: info.Size()}
+}
+
+func (f *pluginFile) isSame(info os.FileInfo) bool {
+	return f.modTime.Equal(info.ModTime()) && f.size == info.Size()
+}
+
+type restartBackoff struct {
+	delay       time.Duration
+	next        time.Time
+	lastRestart time.Time
+}
+
+func (b *restartBackoff) allow(now time.Time) bool {
+	return !now.Before(b.next)
+}
+
+func (b *restartBackoff) restarted(now time.Time) {
+	if b.delay == 0 {
+		b.delay = pluginRestartMinDelay
+	} else {
+		b.delay *= 2
+	}
+	if b.delay > pluginRestartMaxDelay {
+		b.delay = pluginRestartMaxDelay
+	}
+	b.lastRestart = now
+	b.next = now.Add(b.delay)
+}
+
+func (b *restartBackoff) healthy(now time.Time) {
+	if b.delay != 0 && now.Sub(b.lastRestart) >= pluginRestartBackoffReset {
+		*b = restartBackoff{}
+	}
+}
+
+// pluginSupervisor checks the health of the plugin processes and watches the plugin dir, it only
+// runs in the goroutine started by StartSupervisor.
+type pluginSupervisor struct {
+	l        *logrus.Entry
+	backoffs map[string] /*plugin name*/ *restartBackoff
+	// pending is the changed plugin files, a file is loaded after it keeps unchanged in two checks,
+	// so the file being copied is not loaded.
+	pending map[string] /*file name*/ *pluginFile
+	// failed is the plugin files failed to load, they are retried after they are changed.
+	failed map[string] /*file name*/ *pluginFile
+	exit   chan struct{}
+	done   chan struct{}
+}
+
+// StartSupervisor starts to restart the crashed plugins and load, upgrade or unload the plugins when the
+// files in the plugin dir are changed.
+func (pm *pluginManager) StartSupervisor() {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+	if pm.supervisor != nil {
+		return
+	}
+	sv := &pluginSupervisor{
+		l:        log.NewEntry().WithField("job", "plugin_supervisor"),
+		backoffs: map[string]*restartBackoff{},
+		pending:  map[string]*pluginFile{},
+		failed:   map[string]*pluginFile{},
+		exit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	pm.supervisor = sv
+	go pm.supervise(sv)
+}
+
+func (pm *pluginManager) stopSupervisor() {
+	pm.mutex.Lock()
+	sv := pm.supervisor
+	pm.supervisor = nil
+	pm.mutex.Unlock()
+	if sv == nil {
+		return
+	}
+	close(sv.exit)
+	<-sv.done
+}
+
+func (pm *pluginManager) supervise(sv *pluginSupervisor) {
+	defer close(sv.done)
+	ticker := time.NewTicker(pluginSupervisorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sv.exit:
+			return
+		case <-ticker.C:
+			pm.checkPlugins(sv)
+			if pm.pluginDir != "" {
+				pm.reloadPlugins(sv)
+			}
+		}
+	}
+}
+
+func (pm *pluginManager) checkPlugins(sv *pluginSupervisor) {
+	pm.mutex.RLock()
+	processors := map[string]*PluginProcessorV2{}
+	for name, pp := range pm.pluginProcessors {
+		if v2, ok := pp.(*PluginProcessorV2); ok {
+			processors[name] = v2
+		}
+	}
+	pm.mutex.RUnlock()
+
+	for name, pp := range processors {
+		b, ok := sv.backoffs[name]
+		if !ok {
+			b = &restartBackoff{}
+			sv.backoffs[name] = b
+		}
+		now := time.Now()
+		restarted, err := pp.healthCheck(sv.l.WithField("plugin", name), b.allow(now))
+		if restarted {
+			b.restarted(now)
+		}
+		if err != nil {
+			sv.l.Warnf("plugin %s is unhealthy, error: %v", name, err)
+		} else {
+			b.healthy(now)
+		}
+	}
+}
+
+func (pm *pluginManager) reloadPlugins(sv *pluginSupervisor) {
+	files, err := readPluginFiles(pm.pluginDir)
+	if err != nil {
+		sv.l.Warnf("read plugin dir failed, error: %v", err)
+		return
+	}
+
+	pm.mutex.RLock()
+	loaded := make(map[string]*pluginFile, len(pm.pluginFiles))
+	for name, f := range pm.pluginFiles {
+		loaded[name] = f
+	}
+	pm.mutex.RUnlock()
+
+	seen := map[string]struct{}{}
+	for _, info := range files {
+		fileName := info.Name()
+		seen[fileName] = struct{}{}
+		current, isLoaded := loaded[fileName]
+		if isLoaded && current.isSame(info) {
+			delete(sv.pending, fileName)
+			continue
+		}
+		if f, ok := sv.failed[fileName]; ok && f.isSame(info) {
+			continue
+		}
+		if f, ok := sv.pending[fileName]; !ok || !f.isSame(info) {
+			sv.pending[fileName] = newPluginFile(info, "")
+			continue
+		}
+		delete(sv.pending, fileName)
+		delete(sv.failed, fileName)
+
+		if isLoaded {
+			err = pm.upgradePlugin(sv.l, info, current.pluginName)
+		} else {
+			err = pm.loadPlugin(sv.l, info)
+		}
+		if err != nil {
+			sv.l.Errorf("reload plugin file %s failed, error: %v", fileName, err)
+			sv.failed[fileName] = newPluginFile(info, "")
+		}
+	}
+
+	for fileName, f := range loaded {
+		if _, ok := seen[fileName]; !ok {
+			pm.unloadPlugin(sv.l, fileName, f.pluginName)
+			delete(sv.backoffs, f.pluginName)
+		}
+	}
+	for fileName := range sv.pending {
+		if _, ok := seen[fileName]; !ok {
+			delete(sv.pending, fileName)
+		}
+	}
+	for fileName := range sv.failed {
+		if _, ok := seen[fileName]; !ok {
+			delete(sv.failed, fileName)
+		}
+	}
+}
+
+func (pm *pluginManager) loadPlugin(l *logrus.Entry, info os.FileInfo) error {
+	l.Infof("load plugin file %s", info.Name())
+	pp, err := startPluginProcess(pm.pluginDir, info.Name(), pm.pluginConfigList)
+	if err != nil {
+		return err
+	}
+	meta, err := pm.register(pp, info)
+	if err != nil {
+		if stopErr := pp.Stop(); stopErr != nil {
+			l.Warnf("stop plugin %s failed, error: %v", info.Name(), stopErr)
+		}
+		return fmt.Errorf("unable to load plugin: %v, error: %v", info.Name(), err)
+	}
+	l.Infof("plugin %s is loaded from file %s", meta.PluginName, info.Name())
+	if AfterPluginLoadedHook != nil {
+		AfterPluginLoadedHook(meta)
+	}
+	return nil
+}
+
+// upgradePlugin starts the process of the new plugin file, the new sessions are opened by the new process
+// and the old process is stopped after its sessions are closed.
+func (pm *pluginManager) upgradePlugin(l *logrus.Entry, info os.FileInfo, oldPluginName string) error {
+	l.Infof("upgrade plugin %s from file %s", oldPluginName, info.Name())
+	pm.mutex.RLock()
+	oldPP := pm.pluginProcessors[oldPluginName]
+	pm.mutex.RUnlock()
+	// the new process writes the pid file of the plugin file, keep the pid of the old process in another file,
+	// and move it back if the new process fails, so the old process which keeps running is still tracked.
+	oldV2, _ := oldPP.(*PluginProcessorV2)
+	var oldPidFilePath string
+	if oldV2 != nil {
+		oldPidFilePath = oldV2.retirePidFile()
+	}
+	abort := func(pp PluginProcessor) {
+		if pp != nil {
+			if stopErr := pp.Stop(); stopErr != nil {
+				l.Warnf("stop plugin %s failed, error: %v", info.Name(), stopErr)
+			}
+		}
+		if oldV2 != nil {
+			oldV2.restorePidFile(oldPidFilePath)
+		}
+	}
+
+	pp, err := startPluginProcess(pm.pluginDir, info.Name(), pm.pluginConfigList)
+	if err != nil {
+		abort(nil)
+		return err
+	}
+	meta, err := pp.GetDriverMetas()
+	if err != nil {
+		abort(pp)
+		return err
+	}
+
+	pm.mutex.Lock()
+	if _, ok := pm.metas[meta.PluginName]; ok && meta.PluginName != oldPluginName {
+		pm.mutex.Unlock()
+		abort(pp)
+		return fmt.Errorf("duplicated driver name %s", meta.PluginName)
+	}
+	pm.unregisterLocked(oldPluginName)
+	pm.pluginNames = append(pm.pluginNames, meta.PluginName)
+	pm.metas[meta.PluginName] = *meta
+	pm.pluginProcessors[meta.PluginName] = pp
+	pm.pluginFiles[info.Name()] = newPluginFile(info, meta.PluginName)
+	pm.mutex.Unlock()
+
+	l.Infof("plugin %s is upgraded from file %s", meta.PluginName, info.Name())
+	if AfterPluginLoadedHook != nil {
+		AfterPluginLoadedHook(meta)
+	}
+	pm.retirePlugin(l, oldPP, "")
+	return nil
+}
+
+// unloadPlugin removes the plugin whose file is deleted, the process is stopped after its sessions are closed.
+func (pm *pluginManager) unloadPlugin(l *logrus.Entry, fileName, pluginName string) {
+	l.Infof("unload plugin %s, the file %s is removed", pluginName, fileName)
+	pm.mutex.Lock()
+	pp := pm.unregisterLocked(pluginName)
+	delete(pm.pluginFiles, fileName)
+	pm.mutex.Unlock()
+	pm.retirePlugin(l, pp, GetPluginPidFilePath(pm.pluginDir, fileName))
+}
+
+// retirePlugin stops the plugin which is removed from the manager, pidFilePath is removed if the plugin
+// is not a v2 plugin which removes its own pid file.
+func (pm *pluginManager) retirePlugin(l *logrus.Entry, pp PluginProcessor, pidFilePath string) {
+	if pp == nil {
+		return
+	}
+	v2, ok := pp.(*PluginProcessorV2)
+	if !ok {
+		if err := pp.Stop(); err != nil {
+			l.Warnf("stop plugin failed, error: %v", err)
+		}
+		if pidFilePath != "" {
+			os.Remove(pidFilePath)
+		}
+		return
+	}
+	pm.mutex.Lock()
+	pm.drainingProcessors[v2] = struct{}{}
+	pm.mutex.Unlock()
+	go func() {
+		v2.drain(l.WithField("plugin", v2.fileName), pluginDrainTimeout)
+		pm.mutex.Lock()
+		delete(pm.drainingProcessors, v2)
+		pm.mutex.Unlock()
+	}()
+}
+
+// PluginStatuses returns the status of the loaded plugins and the plugins being drained.
+func (pm *pluginManager) PluginStatuses() []*PluginStatus {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+	fileNames := map[string]string{}
+	for fileName, f := range pm.pluginFiles {
+		fileNames[f.pluginName] = fileName
+	}
+
+	statuses := make([]*PluginStatus, 0, len(pm.pluginNames)+len(pm.drainingProcessors))
+	for _, name := range pm.pluginNames {
+		switch pp := pm.pluginProcessors[name].(type) {
+		case *PluginProcessorV2:
+			statuses = append(statuses, pp.status())
+		case *PluginProcessorV1:
+			statuses = append(statuses, &PluginStatus{
+				PluginName:      name,
+				FileName:        fileNames[name],
+				ProtocolVersion: driverV1.ProtocolVersion,
+				Status:          PluginStatusRunning,
+			})
+		default:
+			statuses = append(statuses, &PluginStatus{
+				PluginName:      name,
+				BuiltIn:         true,
+				ProtocolVersion: driverV2.ProtocolVersion,
+				Status:          PluginStatusRunning,
+			})
+		}
+	}
+	draining := make([]*PluginStatus, 0, len(pm.drainingProcessors))
+	for pp := range pm.drainingProcessors {
+		draining = append(draining, pp.status())
+	}
+	sort.Slice(draining, func(i, j int) bool {
+		return draining[i].StartedAt.Before(draining[j].StartedAt)
+	})
+	return append(statuses, draining...)
+}
@@ -0,0 +1,32 @@
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartBackoff(t *testing.T) {
+	b := &restartBackoff{}
+	now := time.Now()
+	assert.True(t, b.allow(now))
+
+	b.restarted(now)
+	assert.False(t, b.allow(now.Add(pluginRestartMinDelay-time.Second)))
+	assert.True(t, b.allow(now.Add(pluginRestartMinDelay)))
+
+	// the delay doubles until the max delay
+	for i := 0; i < 10; i++ {
+		b.restarted(now)
+	}
+	assert.Equal(t, pluginRestartMaxDelay, b.delay)
+
+	// the backoff is kept if the plugin crashes soon after the restart
+	b.healthy(now.Add(time.Minute))
+	assert.Equal(t, pluginRestartMaxDelay, b.delay)
+
+	b.healthy(now.Add(pluginRestartBackoffReset))
+	assert.Equal(t, time.Duration(0), b.delay)
+	assert.True(t, b.allow(now))
+}
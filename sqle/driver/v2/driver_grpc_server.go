@@ -45,10 +45,12 @@ type Rule struct {
 
 type I18nRuleInfo map[language.Tag]*RuleInfo
 
-// GetRuleInfoByLangTag if the lang not exists, return DefaultLang
+// GetRuleInfoByLangTag if the lang not exists, fall back to English, then DefaultLang
 func (i *I18nRuleInfo) GetRuleInfoByLangTag(lang language.Tag) *RuleInfo {
-	if ruleInfo, ok := (*i)[lang]; ok {
-		return ruleInfo
+	for _, tag := range FallbackLangTags(lang) {
+		if ruleInfo, ok := (*i)[tag]; ok {
+			return ruleInfo
+		}
 	}
 	return (*i)[i18nPkg.DefaultLang]
 }
@@ -262,6 +264,8 @@ func (d *DriverGrpcServer) Parse(ctx context.Context, req *protoV2.ParseRequest)
 			Text:        node.Text,
 			Type:        node.Type,
 			Fingerprint: node.Fingerprint,
+			StartLine:   node.StartLine,
+			BatchId:     node.ExecBatchId,
 		})
 	}
 	return resp, nil
@@ -285,13 +289,14 @@ func (d *DriverGrpcServer) Audit(ctx context.Context, req *protoV2.AuditRequest)
 		return &protoV2.AuditResponse{}, err
 	}
 
+	langs := EnabledLanguages()
 	resp := &protoV2.AuditResponse{}
 	for _, results := range auditResults {
 		rets := &protoV2.AuditResults{
 			Results: make([]*protoV2.AuditResult, 0, len(results.Results)),
 		}
 		for _, result := range results.Results {
-			rets.Results = append(rets.Results, ConvertI18nAuditResultFromDriverToProto(result))
+			rets.Results = append(rets.Results, ConvertI18nAuditResultFromDriverToProtoInLangs(result, langs))
 		}
 		resp.AuditResults = append(resp.AuditResults, rets)
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/actiontech/sqle/sqle/driver/common"
 	protoV2 "github.com/actiontech/sqle/sqle/driver/v2/proto"
 	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/tracing"
 	"golang.org/x/text/language"
 
 	goPlugin "github.com/hashicorp/go-plugin"
@@ -34,6 +35,10 @@ var HandshakeConfig = goPlugin.HandshakeConfig{
 }
 
 func ServePlugin(meta DriverMetas, fn func(cfg *Config) (Driver, error)) {
+	// the plugin process inherits the tracing environment variables from sqled
+	_ = tracing.InitFromEnv(meta.PluginName)
+	defer tracing.Shutdown()
+
 	goPlugin.Serve(&goPlugin.ServeConfig{
 		HandshakeConfig: HandshakeConfig,
 		Plugins: goPlugin.PluginSet{
@@ -304,6 +309,9 @@ func (rs *AuditResults) HasResult() bool {
 
 type QueryConf struct {
 	TimeOutSecond uint32
+	// MaxRows limits the rows read from the result, 0 means no limit. It is not sent to the plugins
+	// running in a separate process, the caller should truncate the result itself.
+	MaxRows uint32
 }
 
 // The data location in Values should be consistent with that in Column
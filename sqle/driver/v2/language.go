@@ -0,0 +1,47 @@
+package driverV2
+
+import (
+	"os"
+	"strings"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"golang.org/x/text/language"
+)
+
+// EnvEnabledLanguages is the environment variable by which sqled passes the enabled languages
+// to the plugin processes, the languages are separated by comma, e.g. "zh,en,ja".
+const EnvEnabledLanguages = "SQLE_ENABLED_LANGUAGES"
+
+// EnabledLanguages returns the languages enabled by sqled, the plugins should return
+// I18nRuleInfo and I18nAuditResultInfo in them. DefaultLang and English are always enabled.
+func EnabledLanguages() []language.Tag {
+	langs := []language.Tag{i18nPkg.DefaultLang, language.English}
+	for _, s := range strings.Split(os.Getenv(EnvEnabledLanguages), ",") {
+		tag, err := language.Parse(strings.TrimSpace(s))
+		if err != nil || containsLangTag(langs, tag) {
+			continue
+		}
+		langs = append(langs, tag)
+	}
+	return langs
+}
+
+// FallbackLangTags returns the languages to look up in order for lang: lang itself, English, DefaultLang.
+func FallbackLangTags(lang language.Tag) []language.Tag {
+	langs := []language.Tag{lang}
+	for _, tag := range []language.Tag{language.English, i18nPkg.DefaultLang} {
+		if !containsLangTag(langs, tag) {
+			langs = append(langs, tag)
+		}
+	}
+	return langs
+}
+
+func containsLangTag(langs []language.Tag, tag language.Tag) bool {
+	for _, l := range langs {
+		if l == tag {
+			return true
+		}
+	}
+	return false
+}
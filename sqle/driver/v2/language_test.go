@@ -0,0 +1,48 @@
+package driverV2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestEnabledLanguages(t *testing.T) {
+	t.Setenv(EnvEnabledLanguages, "")
+	assert.Equal(t, []language.Tag{language.Chinese, language.English}, EnabledLanguages())
+
+	t.Setenv(EnvEnabledLanguages, "zh, en,ja,ko,not a language")
+	assert.Equal(t, []language.Tag{language.Chinese, language.English, language.Japanese, language.Korean}, EnabledLanguages())
+}
+
+func TestGetRuleInfoByLangTag(t *testing.T) {
+	info := I18nRuleInfo{
+		language.Chinese: &RuleInfo{Desc: "规则"},
+		language.English: &RuleInfo{Desc: "rule"},
+	}
+	assert.Equal(t, "rule", info.GetRuleInfoByLangTag(language.Japanese).Desc)
+	assert.Equal(t, "规则", info.GetRuleInfoByLangTag(language.Chinese).Desc)
+
+	info[language.Japanese] = &RuleInfo{Desc: "ルール"}
+	assert.Equal(t, "ルール", info.GetRuleInfoByLangTag(language.Japanese).Desc)
+
+	delete(info, language.English)
+	assert.Equal(t, "规则", info.GetRuleInfoByLangTag(language.Korean).Desc)
+}
+
+func TestConvertI18nAuditResultFromDriverToProtoInLangs(t *testing.T) {
+	ar := &AuditResult{
+		RuleName: "rule",
+		Level:    RuleLevelWarn,
+		I18nAuditResultInfo: map[language.Tag]AuditResultInfo{
+			language.Chinese:  {Message: "消息"},
+			language.English:  {Message: "message"},
+			language.Japanese: {Message: "メッセージ"},
+		},
+	}
+	par := ConvertI18nAuditResultFromDriverToProtoInLangs(ar, []language.Tag{language.Chinese, language.English, language.Korean})
+	assert.Equal(t, "消息", par.Message)
+	assert.Len(t, par.I18NAuditResultInfo, 4)
+	assert.Equal(t, "message", par.I18NAuditResultInfo[language.Korean.String()].Message)
+	assert.Equal(t, "メッセージ", par.I18NAuditResultInfo[language.Japanese.String()].Message)
+}
@@ -177,6 +177,27 @@ func ConvertI18nAuditResultFromDriverToProto(ar *AuditResult) *protoV2.AuditResu
 	return par
 }
 
+// ConvertI18nAuditResultFromDriverToProtoInLangs converts the audit result like ConvertI18nAuditResultFromDriverToProto,
+// the languages in langs which the audit result is not translated into are filled by their fallback languages.
+func ConvertI18nAuditResultFromDriverToProtoInLangs(ar *AuditResult, langs []language.Tag) *protoV2.AuditResult {
+	par := ConvertI18nAuditResultFromDriverToProto(ar)
+	for _, lang := range langs {
+		if _, ok := par.I18NAuditResultInfo[lang.String()]; ok {
+			continue
+		}
+		for _, tag := range FallbackLangTags(lang) {
+			if info, ok := ar.I18nAuditResultInfo[tag]; ok {
+				par.I18NAuditResultInfo[lang.String()] = &protoV2.I18NAuditResultInfo{
+					Message:   info.Message,
+					ErrorInfo: info.ErrorInfo,
+				}
+				break
+			}
+		}
+	}
+	return par
+}
+
 func ConvertI18nRuleFromProtoToDriver(rule *protoV2.Rule, dbtype string, isI18n bool) (*Rule, error) {
 	ps, err := ConvertProtoParamToParam(rule.Params)
 	if err != nil {
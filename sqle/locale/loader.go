@@ -0,0 +1,100 @@
+package locale
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"testing/fstest"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"github.com/actiontech/sqle/sqle/log"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/text/language"
+)
+
+// localeFileRegexp matches the locale file names like active.ja.toml
+var localeFileRegexp = regexp.MustCompile(`^active\.([A-Za-z0-9-]+)\.toml$`)
+
+// NewBundleWithDir builds a bundle from the embedded locale files and the extra ones in dir.
+// The extra locale files are named active.<lang>.toml, the messages of a language which are not
+// translated in them fall back to the embedded ones of the same language, then to English.
+func NewBundleWithDir(embedded fs.FS, dir string) (*i18nPkg.Bundle, error) {
+	files, err := readLocaleFiles(embedded)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		extraFS := os.DirFS(dir)
+		extraFiles, err := readLocaleFiles(extraFS)
+		if err != nil {
+			return nil, fmt.Errorf("read locale files from %s failed: %v", dir, err)
+		}
+		for name, messages := range extraFiles {
+			base := files[name]
+			if base == nil {
+				base = files[localeFileName(language.English)]
+			}
+			for id, message := range base {
+				if _, ok := messages[id]; !ok {
+					messages[id] = message
+				}
+			}
+			files[name] = messages
+		}
+	}
+
+	localeFS := fstest.MapFS{}
+	for name, messages := range files {
+		buf := &bytes.Buffer{}
+		if err := toml.NewEncoder(buf).Encode(messages); err != nil {
+			return nil, fmt.Errorf("encode locale file %s failed: %v", name, err)
+		}
+		localeFS[name] = &fstest.MapFile{Data: buf.Bytes()}
+	}
+	return i18nPkg.NewBundleFromTomlDir(localeFS, log.NewEntry())
+}
+
+// LoadLocaleDir reloads Bundle with the extra locale files in dir, it should be called before serving.
+func LoadLocaleDir(dir string) error {
+	b, err := NewBundleWithDir(localeFS, dir)
+	if err != nil {
+		return err
+	}
+	Bundle = b
+	return nil
+}
+
+func localeFileName(tag language.Tag) string {
+	return fmt.Sprintf("active.%s.toml", tag)
+}
+
+// readLocaleFiles reads the messages of the locale files in the root of fsys by the file name
+func readLocaleFiles(fsys fs.FS) (map[string]map[string]interface{}, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		matches := localeFileRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		if _, err := language.Parse(matches[1]); err != nil {
+			return nil, fmt.Errorf("invalid language of locale file %s: %v", entry.Name(), err)
+		}
+		b, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		messages := map[string]interface{}{}
+		if err := toml.Unmarshal(b, &messages); err != nil {
+			return nil, fmt.Errorf("parse locale file %s failed: %v", entry.Name(), err)
+		}
+		files[entry.Name()] = messages
+	}
+	return files, nil
+}
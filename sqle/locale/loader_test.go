@@ -0,0 +1,46 @@
+package locale
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestNewBundleWithDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "active.ja.toml"), []byte(`WorkflowStatusWaitForAudit = "監査待ち"`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "active.en.toml"), []byte(`WorkflowStatusReject = "Refused"`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(`# locales`), 0644))
+
+	b, err := NewBundleWithDir(localeFS, dir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []language.Tag{language.Chinese, language.English, language.Japanese}, b.LanguageTags())
+
+	// the translated message
+	assert.Equal(t, "監査待ち", b.LocalizeMsgByLang(language.Japanese, WorkflowStatusWaitForAudit))
+	// the untranslated message falls back to English
+	assert.Equal(t, "Pending audit", b.LocalizeMsgByLang(language.English, WorkflowStatusWaitForAudit))
+	assert.Equal(t, b.LocalizeMsgByLang(language.English, WorkflowStatusCancel), b.LocalizeMsgByLang(language.Japanese, WorkflowStatusCancel))
+	// the message of the embedded language is overridden
+	assert.Equal(t, "Refused", b.LocalizeMsgByLang(language.English, WorkflowStatusReject))
+	assert.Equal(t, "待审核", b.LocalizeMsgByLang(language.Chinese, WorkflowStatusWaitForAudit))
+	assert.Len(t, b.LocalizeAll(WorkflowStatusWaitForAudit), 3)
+
+	// without the extra dir
+	b, err = NewBundleWithDir(localeFS, "")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []language.Tag{language.Chinese, language.English}, b.LanguageTags())
+}
+
+func TestNewBundleWithInvalidDir(t *testing.T) {
+	_, err := NewBundleWithDir(localeFS, filepath.Join(t.TempDir(), "not_exist"))
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "active.ja.toml"), []byte(`WorkflowStatusWaitForAudit = `), 0644))
+	_, err = NewBundleWithDir(localeFS, dir)
+	assert.Error(t, err)
+}
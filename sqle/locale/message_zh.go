@@ -53,6 +53,7 @@ var (
 	SQLExecuteStatusSucceeded        = &i18n.Message{ID: "SQLExecuteStatusSucceeded", Other: "执行成功"}
 	SQLExecuteStatusManuallyExecuted = &i18n.Message{ID: "SQLExecuteStatusManuallyExecuted", Other: "人工执行"}
 	SQLExecuteStatusNotExecuted      = &i18n.Message{ID: "SQLExecuteStatusNotExecuted", Other: "未执行"}
+	SQLExecuteStatusPaused           = &i18n.Message{ID: "SQLExecuteStatusPaused", Other: "已暂停"}
 	SQLExecuteStatusUnknown          = &i18n.Message{ID: "SQLExecuteStatusUnknown", Other: "未知"}
 
 	SQLNotExecutedReason       = &i18n.Message{ID: "SQLNotExecutedReason", Other: "前序 SQL 上线失败，本条 SQL 未执行"}
@@ -76,6 +77,7 @@ var (
 var (
 	WorkflowStepStateApprove = &i18n.Message{ID: "WorkflowStepStateApprove", Other: "通过"}
 	WorkflowStepStateReject  = &i18n.Message{ID: "WorkflowStepStateReject", Other: "驳回"}
+	WorkflowStepOnBehalfOf   = &i18n.Message{ID: "WorkflowStepOnBehalfOf", Other: "%s(代%s)"}
 
 	WorkflowStatusWaitForAudit     = &i18n.Message{ID: "WorkflowStatusWaitForAudit", Other: "待审核"}
 	WorkflowStatusWaitForExecution = &i18n.Message{ID: "WorkflowStatusWaitForExecution", Other: "待上线"}
@@ -418,6 +420,7 @@ var (
 	ApMetricNameRowsAffectedAvg         = &i18n.Message{ID: "ApMetricNameRowsAffectedAvg", Other: "平均影响行数"}
 	ApMetricNameChecksum                = &i18n.Message{ID: "ApMetricNameChecksum", Other: "校验和"}
 	ApMetricNameNoIndexUsedTotal        = &i18n.Message{ID: "ApMetricNameNoIndexUsedTotal", Other: "累计未使用索引次数"}
+	ApMetricNameEndpoints               = &i18n.Message{ID: "ApMetricNameEndpoints", Other: "端点信息"}
 
 	ApMetricNameCounterMoreThan        = &i18n.Message{ID: "ApMetricNameCounterMoreThan", Other: "出现次数 > "}
 	ApMetricNameQueryTimeAvgMoreThan   = &i18n.Message{ID: "ApMetricNameQueryTimeAvgMoreThan", Other: "平均执行时间(s) > "}
@@ -474,6 +477,7 @@ var (
 	ApMetaTiDBProcesslist                 = &i18n.Message{ID: "ApMetaTiDBProcesslist", Other: "TiDB Processlist"}
 	ApMetaMySQLTopSQL                     = &i18n.Message{ID: "ApMetaMySQLTopSQL", Other: "MySQL TOP SQL"}
 	ApMetaMSSQLTopSQL                     = &i18n.Message{ID: "ApMetaMSSQLTopSQL", Other: "SQL Server TOP SQL"}
+	ApMetaProxySQLQueryDigest             = &i18n.Message{ID: "ApMetaProxySQLQueryDigest", Other: "ProxySQL 查询摘要"}
 	ApMetricQueryTimeAvg                  = &i18n.Message{ID: "ApMetricQueryTimeAvg", Other: "平均查询时间(s)"}
 	ApMetricRowExaminedAvg                = &i18n.Message{ID: "ApMetricRowExaminedAvg", Other: "平均扫描行数"}
 	ApMetricHiveSlowLogDbUser             = &i18n.Message{ID: "ApMetricHiveSlowLogDbUser", Other: "执行用户"}
@@ -517,6 +521,11 @@ var (
 	ParamTimeSpan                        = &i18n.Message{ID: "ParamTimeSpan", Other: "时间跨度（小时）"}
 	ParamInstance                        = &i18n.Message{ID: "ParamInstance", Other: "节点地址（0 代表所有节点）"}
 	ParamKpiType                         = &i18n.Message{ID: "ParamkpiType", Other: "指标"}
+	ParamProxySQLHost                    = &i18n.Message{ID: "ParamProxySQLHost", Other: "ProxySQL 管理接口地址"}
+	ParamProxySQLPort                    = &i18n.Message{ID: "ParamProxySQLPort", Other: "ProxySQL 管理接口端口"}
+	ParamProxySQLUser                    = &i18n.Message{ID: "ParamProxySQLUser", Other: "ProxySQL 管理用户"}
+	ParamProxySQLPassword                = &i18n.Message{ID: "ParamProxySQLPassword", Other: "ProxySQL 管理用户密码"}
+	ParamProxySQLHostgroups              = &i18n.Message{ID: "ParamProxySQLHostgroups", Other: "采集的主机组（多个以逗号分隔，为空时采集所有主机组）"}
 
 	EnumKpiTypeQueryTime          = &i18n.Message{ID: "EnumkpiTypeQueryTime", Other: "执行时间"}
 	EnumKpiTypeMemMax             = &i18n.Message{ID: "EnumKpiTypeMemMax", Other: "使用的最大内存空间"}
@@ -565,6 +574,21 @@ var (
 	NotifyAuditPlanBody     = &i18n.Message{ID: "NotifyAuditPlanBody", Other: "\n- 扫描任务: %v\n- 审核时间: %v\n- 审核类型: %v\n- 数据源: %v\n- 数据库名: %v\n- 审核得分: %v\n- 审核通过率：%v\n- 审核结果等级: %v%v"}
 	NotifyAuditPlanBodyLink = &i18n.Message{ID: "NotifyAuditPlanBodyLink", Other: "\n- 扫描任务链接: %v"}
 
+	NotifySqlRegressionSubject    = &i18n.Message{ID: "NotifySqlRegressionSubject", Other: "SQLE SQL性能回退告警[%v]"}
+	NotifySqlRegressionBody       = &i18n.Message{ID: "NotifySqlRegressionBody", Other: "\n- SQL ID: %v\n- 回退类型: %v\n- 指标: %v\n- 基线中位数: %v\n- 基线P95: %v\n- 当前值: %v\n- 回退倍数: %.2f\n- 检测时间: %v\n- SQL: %v"}
+	SqlRegressionTypeLatency      = &i18n.Message{ID: "SqlRegressionTypeLatency", Other: "执行耗时"}
+	SqlRegressionTypeRowsExamined = &i18n.Message{ID: "SqlRegressionTypeRowsExamined", Other: "扫描行数"}
+	SqlRegressionTypeFrequency    = &i18n.Message{ID: "SqlRegressionTypeFrequency", Other: "执行频率"}
+	SqlRegressionPriorityReason   = &i18n.Message{ID: "SqlRegressionPriorityReason", Other: "【SQL性能回退：%v 当前值 %v，为基线中位数的 %.2f 倍】"}
+
+	NotifyWorkflowSLARemindSubject   = &i18n.Message{ID: "NotifyWorkflowSLARemindSubject", Other: "SQL工单[%v]待%s已等待%v分钟"}
+	NotifyWorkflowSLAEscalateSubject = &i18n.Message{ID: "NotifyWorkflowSLAEscalateSubject", Other: "SQL工单[%v]待%s已超时，已升级处理"}
+	NotifyWorkflowSLABody            = &i18n.Message{ID: "NotifyWorkflowSLABody", Other: "\n- 工单主题: %v\n- 工单ID: %v\n- 当前步骤: %v\n- 开始等待时间: %v\n- 已等待: %v分钟\n- 待操作人: %v"}
+
+	NotifySqlPlanRegressionSubject  = &i18n.Message{ID: "NotifySqlPlanRegressionSubject", Other: "SQLE SQL执行计划回退告警"}
+	NotifySqlPlanRegressionBody     = &i18n.Message{ID: "NotifySqlPlanRegressionBody", Other: "\n- SQL ID: %v\n- 变化类型: %v\n- 原执行计划: %v\n- 新执行计划: %v\n- 检测时间: %v\n- SQL: %v"}
+	SqlPlanRegressionPriorityReason = &i18n.Message{ID: "SqlPlanRegressionPriorityReason", Other: "【执行计划回退：%v】"}
+
 	NotifyManageRecordSubject    = &i18n.Message{ID: "NotifyManageRecordSubject", Other: "SQL管控记录"}
 	NotifyManageRecordBodyLink   = &i18n.Message{ID: "NotifyManageRecordBodyLink", Other: "\n- SQL管控记录链接: %v\n"}
 	NotifyManageRecordBodyRecord = &i18n.Message{ID: "NotifyManageRecordBodyRecord", Other: "- SQL ID: %v\n- 所在数据源名称: %v\n- 环境属性: %v\n- SQL: %v\n- 触发规则级别: %v\n- SQL审核建议: %v\n================================"}
@@ -0,0 +1,354 @@
+// Package metrics exports the internal state of sqled in the Prometheus text exposition format.
+// It implements the counter, gauge and histogram used by sqled without depending on the Prometheus client.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/actiontech/sqle/sqle/log"
+)
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefBuckets are the default histogram buckets in seconds, they cover the duration from 5ms to 10s.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metric interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds the registered metrics, the metrics are written in name order.
+type Registry struct {
+	mutex   sync.RWMutex
+	metrics map[string]metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]metric{}}
+}
+
+var DefaultRegistry = NewRegistry()
+
+func (r *Registry) mustRegister(m metric) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.metrics[m.name()]; ok {
+		panic(fmt.Sprintf("metric %s is registered", m.name()))
+	}
+	r.metrics[m.name()] = m
+}
+
+func (r *Registry) Write(w io.Writer) error {
+	r.mutex.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]metric, 0, len(names))
+	for _, name := range names {
+		metrics = append(metrics, r.metrics[name])
+	}
+	r.mutex.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler returns the http handler which writes the metrics of the default registry.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_ = DefaultRegistry.Write(w)
+	})
+}
+
+type desc struct {
+	metricName string
+	help       string
+	typ        string
+	labelNames []string
+}
+
+func (d *desc) name() string {
+	return d.metricName
+}
+
+func (d *desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.metricName, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.metricName, d.typ)
+}
+
+// key returns the key of the label values, it returns false if the number of the label values does not
+// match the label names, the sample is dropped then rather than breaking the caller.
+func (d *desc) key(labelValues []string) (string, bool) {
+	if len(labelValues) != len(d.labelNames) {
+		log.NewEntry().Errorf("metric %s expects %d label values, got %d, the sample is dropped", d.metricName, len(d.labelNames), len(labelValues))
+		return "", false
+	}
+	return strings.Join(labelValues, "\xff"), true
+}
+
+func (d *desc) labels(labelValues []string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(labelValues)+1)
+	for i, value := range labelValues {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, d.labelNames[i], escapeLabelValue(value)))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, escapeLabelValue(extraValue)))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// series is the value of a combination of label values, the value is stored as the bits of the float64
+// so it is updated atomically.
+type series struct {
+	labelValues []string
+	bits        uint64
+}
+
+func (s *series) add(delta float64) {
+	addFloat64(&s.bits, delta)
+}
+
+func (s *series) set(value float64) {
+	atomic.StoreUint64(&s.bits, math.Float64bits(value))
+}
+
+func (s *series) value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&s.bits))
+}
+
+// vec keeps a value for each combination of label values. The series are looked up without lock and the
+// values are updated atomically, so the goroutines updating the metric, e.g. the rule evaluations, do not
+// block each other.
+type vec struct {
+	desc
+	values sync.Map // the key of the label values -> *series
+}
+
+func (v *vec) series(labelValues []string) (*series, bool) {
+	key, ok := v.key(labelValues)
+	if !ok {
+		return nil, false
+	}
+	if s, ok := v.values.Load(key); ok {
+		return s.(*series), true
+	}
+	s, _ := v.values.LoadOrStore(key, &series{labelValues: append([]string{}, labelValues...)})
+	return s.(*series), true
+}
+
+func (v *vec) add(delta float64, labelValues []string) {
+	if s, ok := v.series(labelValues); ok {
+		s.add(delta)
+	}
+}
+
+func (v *vec) set(value float64, labelValues []string) {
+	if s, ok := v.series(labelValues); ok {
+		s.set(value)
+	}
+}
+
+// Delete removes the series of the label values, it is used when the object the series describes is gone.
+func (v *vec) Delete(labelValues ...string) {
+	if key, ok := v.key(labelValues); ok {
+		v.values.Delete(key)
+	}
+}
+
+func (v *vec) write(w *bufio.Writer) {
+	v.writeHeader(w)
+	for _, s := range sortedSeries[*series](&v.values) {
+		fmt.Fprintf(w, "%s%s %s\n", v.metricName, v.labels(s.labelValues, "", ""), formatFloat(s.value()))
+	}
+}
+
+type CounterVec struct {
+	vec
+}
+
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{vec{desc: desc{metricName: name, help: help, typ: typeCounter, labelNames: labelNames}}}
+	DefaultRegistry.mustRegister(c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.add(1, labelValues)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s can not decrease", c.metricName))
+	}
+	c.add(delta, labelValues)
+}
+
+type GaugeVec struct {
+	vec
+}
+
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	g := &GaugeVec{vec{desc: desc{metricName: name, help: help, typ: typeGauge, labelNames: labelNames}}}
+	DefaultRegistry.mustRegister(g)
+	return g
+}
+
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.set(value, labelValues)
+}
+
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.add(delta, labelValues)
+}
+
+// GaugeFunc is a gauge without labels whose value is computed when the metrics are scraped.
+type GaugeFunc struct {
+	desc
+	fn func() float64
+}
+
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	g := &GaugeFunc{desc: desc{metricName: name, help: help, typ: typeGauge}, fn: fn}
+	DefaultRegistry.mustRegister(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	value := g.fn()
+	g.writeHeader(w)
+	fmt.Fprintf(w, "%s %s\n", g.metricName, formatFloat(value))
+}
+
+// histogramSeries counts the observations in each bucket, the last count is for the +Inf bucket. The counts
+// are not cumulative, they are accumulated when the histogram is written.
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	sumBits     uint64
+}
+
+// HistogramVec is updated without lock like the vec.
+type HistogramVec struct {
+	desc
+	buckets []float64
+	values  sync.Map // the key of the label values -> *histogramSeries
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+	h := &HistogramVec{
+		desc:    desc{metricName: name, help: help, typ: typeHistogram, labelNames: labelNames},
+		buckets: sorted,
+	}
+	DefaultRegistry.mustRegister(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key, ok := h.key(labelValues)
+	if !ok {
+		return
+	}
+	v, ok := h.values.Load(key)
+	if !ok {
+		v, _ = h.values.LoadOrStore(key, &histogramSeries{
+			labelValues: append([]string{}, labelValues...),
+			counts:      make([]uint64, len(h.buckets)+1),
+		})
+	}
+	s := v.(*histogramSeries)
+	atomic.AddUint64(&s.counts[sort.SearchFloat64s(h.buckets, value)], 1)
+	addFloat64(&s.sumBits, value)
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.writeHeader(w)
+	for _, s := range sortedSeries[*histogramSeries](&h.values) {
+		var count uint64
+		for i, upper := range h.buckets {
+			count += atomic.LoadUint64(&s.counts[i])
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labels(s.labelValues, "le", formatFloat(upper)), count)
+		}
+		count += atomic.LoadUint64(&s.counts[len(h.buckets)])
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labels(s.labelValues, "le", "+Inf"), count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, h.labels(s.labelValues, "", ""), formatFloat(math.Float64frombits(atomic.LoadUint64(&s.sumBits))))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, h.labels(s.labelValues, "", ""), count)
+	}
+}
+
+// sortedSeries returns the series in the map ordered by the key, so the output is stable.
+func sortedSeries[T any](values *sync.Map) []T {
+	keys := []string{}
+	series := map[string]T{}
+	values.Range(func(key, value interface{}) bool {
+		keys = append(keys, key.(string))
+		series[key.(string)] = value.(T)
+		return true
+	})
+	sort.Strings(keys)
+	result := make([]T, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, series[key])
+	}
+	return result
+}
+
+// addFloat64 adds delta to the float64 stored as bits atomically.
+func addFloat64(bits *uint64, delta float64) {
+	for {
+		old := atomic.LoadUint64(bits)
+		if atomic.CompareAndSwapUint64(bits, old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(v string) string {
+	return helpReplacer.Replace(v)
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterAndGauge(t *testing.T) {
+	c := NewCounterVec("test_counter_total", "Test counter.", "channel")
+	c.Inc("dms")
+	c.Add(2, "dms")
+	c.Inc(`web"hook`)
+	assert.Panics(t, func() { c.Add(-1, "dms") })
+	// the samples with mismatched label values are dropped
+	assert.NotPanics(t, func() { c.Inc() })
+	assert.NotPanics(t, func() { c.Inc("dms", "extra") })
+
+	g := NewGaugeVec("test_gauge", "Test gauge.", "id")
+	g.Set(10, "1")
+	g.Set(20, "2")
+	g.Delete("2")
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, DefaultRegistry.Write(buf))
+	out := buf.String()
+	assert.Contains(t, out, "# HELP test_counter_total Test counter.\n# TYPE test_counter_total counter\n")
+	assert.Contains(t, out, "test_counter_total{channel=\"dms\"} 3\n")
+	assert.Contains(t, out, "test_counter_total{channel=\"web\\\"hook\"} 1\n")
+	assert.Contains(t, out, "test_gauge{id=\"1\"} 10\n")
+	assert.NotContains(t, out, "test_gauge{id=\"2\"}")
+
+	// metrics are written in name order
+	assert.Less(t, strings.Index(out, "test_counter_total"), strings.Index(out, "test_gauge"))
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogramVec("test_duration_seconds", "Test histogram.", []float64{1, 0.1}, "action")
+	h.Observe(0.05, "audit")
+	h.Observe(0.5, "audit")
+	h.Observe(5, "audit")
+	assert.NotPanics(t, func() { h.Observe(1) })
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, DefaultRegistry.Write(buf))
+	out := buf.String()
+	assert.Contains(t, out, `test_duration_seconds_bucket{action="audit",le="0.1"} 1
+test_duration_seconds_bucket{action="audit",le="1"} 2
+test_duration_seconds_bucket{action="audit",le="+Inf"} 3
+test_duration_seconds_sum{action="audit"} 5.55
+test_duration_seconds_count{action="audit"} 3
+`)
+}
+
+func TestRegisterTwice(t *testing.T) {
+	NewGaugeFunc("test_gauge_func", "Test gauge func.", func() float64 { return 1.5 })
+	assert.Panics(t, func() {
+		NewGaugeFunc("test_gauge_func", "Test gauge func.", func() float64 { return 1 })
+	})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, DefaultRegistry.Write(buf))
+	assert.Contains(t, buf.String(), "test_gauge_func 1.5\n")
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	c := NewCounterVec("test_concurrent_total", "Test concurrent counter.", "rule")
+	h := NewHistogramVec("test_concurrent_seconds", "Test concurrent histogram.", []float64{1}, "rule")
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc("rule1")
+				h.Observe(0.5, "rule1")
+			}
+		}()
+	}
+	wg.Wait()
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, DefaultRegistry.Write(buf))
+	out := buf.String()
+	assert.Contains(t, out, "test_concurrent_total{rule=\"rule1\"} 8000\n")
+	assert.Contains(t, out, "test_concurrent_seconds_bucket{rule=\"rule1\",le=\"1\"} 8000\n")
+	assert.Contains(t, out, "test_concurrent_seconds_sum{rule=\"rule1\"} 4000\n")
+}
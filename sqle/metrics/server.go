@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/actiontech/sqle/sqle/log"
+)
+
+// StartServer 启动独立的指标 HTTP 服务器，指标不经过 API 的用户鉴权，因此不在 API 端口上暴露
+// port: 指标服务器监听端口，如果为 0 则不启动
+func StartServer(port int) error {
+	if port <= 0 {
+		log.Logger().Infof("metrics server disabled (port: %d)", port)
+		return nil
+	}
+
+	address := fmt.Sprintf("0.0.0.0:%d", port)
+	log.Logger().Infof("starting metrics server on %s", address)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	if err := http.ListenAndServe(address, mux); err != nil {
+		return fmt.Errorf("metrics server failed: %v", err)
+	}
+
+	return nil
+}
+
+// StartServerAsync 异步启动独立的指标 HTTP 服务器
+func StartServerAsync(port int) {
+	if port <= 0 {
+		log.Logger().Infof("metrics server disabled (port: %d)", port)
+		return
+	}
+
+	go func() {
+		if err := StartServer(port); err != nil {
+			log.Logger().Errorf("metrics server error: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"runtime"
+	"time"
+)
+
+const namespace = "sqle_"
+
+var (
+	TaskDuration = NewHistogramVec(namespace+"task_duration_seconds",
+		"Duration of the audit, execute and rollback tasks run by sqled.",
+		[]float64{.1, .5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600},
+		"action", "db_type")
+	TaskFailures = NewCounterVec(namespace+"task_failures_total",
+		"Number of the audit, execute and rollback tasks which failed.",
+		"action", "db_type")
+
+	RuleEvaluationDuration = NewHistogramVec(namespace+"rule_evaluation_duration_seconds",
+		"Duration of evaluating a rule on a SQL.",
+		[]float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		"db_type", "rule")
+
+	AuditPlanCollectionDuration = NewHistogramVec(namespace+"audit_plan_collection_duration_seconds",
+		"Duration of collecting SQL by the audit plan.",
+		DefBuckets,
+		"audit_plan_type")
+	AuditPlanCollectionErrors = NewCounterVec(namespace+"audit_plan_collection_errors_total",
+		"Number of the failed collections of the audit plan.",
+		"audit_plan_type")
+	// the collection lag is `time() - sqle_audit_plan_last_collection_timestamp_seconds`
+	AuditPlanLastCollection = NewGaugeVec(namespace+"audit_plan_last_collection_timestamp_seconds",
+		"Unix time of the last successful collection of the audit plan.",
+		"audit_plan_id", "audit_plan_type")
+
+	JobDuration = NewHistogramVec(namespace+"job_duration_seconds",
+		"Duration of a run of the background job.",
+		DefBuckets,
+		"job")
+
+	PluginRestarts = NewCounterVec(namespace+"plugin_restarts_total",
+		"Number of the restarts of the plugin process after it exited.",
+		"plugin")
+
+	NotificationFailures = NewCounterVec(namespace+"notification_failures_total",
+		"Number of the notifications which failed to send.",
+		"channel")
+)
+
+var startTime = time.Now()
+
+func init() {
+	NewGaugeFunc(namespace+"start_time_seconds", "Unix time when sqled started.", func() float64 {
+		return float64(startTime.Unix())
+	})
+	NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+	NewGaugeFunc("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.", func() float64 {
+		m := runtime.MemStats{}
+		runtime.ReadMemStats(&m)
+		return float64(m.HeapAlloc)
+	})
+}
+
+// ObserveSince observes the duration since `begin` in seconds.
+func ObserveSince(h *HistogramVec, begin time.Time, labelValues ...string) {
+	h.Observe(time.Since(begin).Seconds(), labelValues...)
+}
@@ -99,3 +99,25 @@ func (s *Storage) FirstCustomRuleCategoryRelByCustomRuleId(customRuleId string)
 	}
 	return categoryRel, true, errors.New(errors.ConnectStorageError, err)
 }
+
+// GetRuleCategoryTags returns the category tags of the rules, keyed by the rule name.
+func (s *Storage) GetRuleCategoryTags(ruleNames []string, dbType string) (map[string][]string, error) {
+	var rows []struct {
+		RuleName string
+		Tag      string
+	}
+	err := s.db.Table("audit_rule_category_rels").
+		Joins("JOIN audit_rule_categories ON audit_rule_categories.id = audit_rule_category_rels.category_id").
+		Where("audit_rule_category_rels.rule_name IN (?) AND audit_rule_category_rels.rule_db_type = ?", ruleNames, dbType).
+		Select("audit_rule_category_rels.rule_name, audit_rule_categories.tag").
+		Order("audit_rule_categories.tag").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+	tags := map[string][]string{}
+	for _, row := range rows {
+		tags[row.RuleName] = append(tags[row.RuleName], row.Tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &ChunkedExecution{})
+	autoMigrateList = append(autoMigrateList, &ChunkedExecutionBatch{})
+}
+
+const (
+	DefaultChunkSize             uint64 = 1000
+	DefaultChunkSleepMillisecond uint64 = 500
+)
+
+const (
+	ChunkedExecutionStatusInitialized = "initialized"
+	ChunkedExecutionStatusRunning     = "running"
+	// ChunkedExecutionStatusPausing means the user asks to pause, the execution pauses after the current batch.
+	ChunkedExecutionStatusPausing  = "pausing"
+	ChunkedExecutionStatusPaused   = "paused"
+	ChunkedExecutionStatusFinished = "finished"
+	ChunkedExecutionStatusFailed   = "failed"
+	// ChunkedExecutionStatusNotEligible means the SQL can not be split by primary key range, it is executed as a whole.
+	ChunkedExecutionStatusNotEligible = "not_eligible"
+)
+
+// ChunkedExecution is the progress of executing an UPDATE/DELETE in primary key range batches, each batch
+// is committed separately, so the execution can resume from LastPK after it is paused or interrupted.
+type ChunkedExecution struct {
+	Model
+	TaskId           uint   `json:"task_id" gorm:"index"`
+	ExecuteSQLId     uint   `json:"execute_sql_id" gorm:"not null;uniqueIndex"`
+	ChunkSize        uint64 `json:"chunk_size" gorm:"not null"`
+	SleepMillisecond uint64 `json:"sleep_millisecond" gorm:"not null;default:0"`
+
+	// the plan is made on the first execution and kept for resuming
+	Schema   string `json:"schema" gorm:"type:varchar(255)"`
+	Table    string `json:"table" gorm:"type:varchar(255)"`
+	PKColumn string `json:"pk_column" gorm:"type:varchar(255)"`
+	MinPK    string `json:"min_pk" gorm:"type:varchar(64)"`
+	MaxPK    string `json:"max_pk" gorm:"type:varchar(64)"`
+
+	// LastPK is the upper bound of the last completed batch, it is empty if no batch is completed
+	LastPK     string     `json:"last_pk" gorm:"type:varchar(64)"`
+	BatchCount uint64     `json:"batch_count"`
+	RowAffects int64      `json:"row_affects"`
+	Status     string     `json:"status" gorm:"type:varchar(32);default:'initialized'"`
+	Error      string     `json:"error" gorm:"type:text"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+func (c *ChunkedExecution) IsPlanned() bool {
+	return c.PKColumn != ""
+}
+
+// ChunkedExecutionBatch is a completed batch of the chunked execution.
+type ChunkedExecutionBatch struct {
+	Model
+	ChunkedExecutionId uint      `json:"chunked_execution_id" gorm:"index"`
+	BatchNo            uint64    `json:"batch_no"`
+	StartPK            string    `json:"start_pk" gorm:"type:varchar(64)"`
+	EndPK              string    `json:"end_pk" gorm:"type:varchar(64)"`
+	RowAffects         int64     `json:"row_affects"`
+	DurationMs         int64     `json:"duration_ms"`
+	ExecutedAt         time.Time `json:"executed_at"`
+}
+
+func (s *Storage) GetChunkedExecutionByExecuteSQLId(executeSQLId uint) (*ChunkedExecution, bool, error) {
+	ce := &ChunkedExecution{}
+	err := s.db.Where("execute_sql_id = ?", executeSQLId).First(ce).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return ce, true, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetChunkedExecutionsByTaskId(taskId uint) ([]*ChunkedExecution, error) {
+	ces := []*ChunkedExecution{}
+	err := s.db.Where("task_id = ?", taskId).Find(&ces).Error
+	return ces, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetChunkedExecutionStatus(id uint) (string, error) {
+	ce := &ChunkedExecution{}
+	err := s.db.Select("status").Where("id = ?", id).First(ce).Error
+	return ce.Status, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetChunkedExecutionBatches(chunkedExecutionId uint) ([]*ChunkedExecutionBatch, error) {
+	batches := []*ChunkedExecutionBatch{}
+	err := s.db.Where("chunked_execution_id = ?", chunkedExecutionId).Order("batch_no ASC").Find(&batches).Error
+	return batches, errors.New(errors.ConnectStorageError, err)
+}
+
+// SetExecuteSQLChunked switches the execute mode of the SQL, the chunk settings are kept when it is switched off.
+func (s *Storage) SetExecuteSQLChunked(executeSQL *ExecuteSQL, chunked bool, chunkSize, sleepMillisecond uint64) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		mode := SQLExecModeNormal
+		if chunked {
+			mode = SQLExecModeChunked
+		}
+		if err := txDB.Model(&ExecuteSQL{}).Where("id = ?", executeSQL.ID).Update("exec_mode", mode).Error; err != nil {
+			return err
+		}
+		executeSQL.ExecMode = mode
+		if !chunked {
+			return nil
+		}
+		ce := &ChunkedExecution{}
+		err := txDB.Where("execute_sql_id = ?", executeSQL.ID).First(ce).Error
+		if err == gorm.ErrRecordNotFound {
+			return txDB.Create(&ChunkedExecution{
+				TaskId:           executeSQL.TaskId,
+				ExecuteSQLId:     executeSQL.ID,
+				ChunkSize:        chunkSize,
+				SleepMillisecond: sleepMillisecond,
+				Status:           ChunkedExecutionStatusInitialized,
+			}).Error
+		}
+		if err != nil {
+			return err
+		}
+		return txDB.Model(ce).Updates(map[string]interface{}{
+			"chunk_size":        chunkSize,
+			"sleep_millisecond": sleepMillisecond,
+		}).Error
+	})
+}
+
+// UpdateChunkedExecutionStatusIf updates the status only if the current status is one of fromStatus, it
+// reports whether the status is updated.
+func (s *Storage) UpdateChunkedExecutionStatusIf(id uint, fromStatus []string, status string) (bool, error) {
+	db := s.db.Model(&ChunkedExecution{}).Where("id = ? AND status IN (?)", id, fromStatus).Update("status", status)
+	return db.RowsAffected > 0, errors.New(errors.ConnectStorageError, db.Error)
+}
+
+// SaveChunkedExecutionBatch saves the completed batch and the progress in one transaction, so the progress
+// always matches the batches.
+func (s *Storage) SaveChunkedExecutionBatch(ce *ChunkedExecution, batch *ChunkedExecutionBatch) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if err := txDB.Create(batch).Error; err != nil {
+			return err
+		}
+		return txDB.Model(ce).Updates(map[string]interface{}{
+			"last_pk":     ce.LastPK,
+			"batch_count": ce.BatchCount,
+			"row_affects": ce.RowAffects,
+		}).Error
+	})
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	e "errors"
+	"strings"
+
+	dmsCommonAes "github.com/actiontech/dms/pkg/dms-common/pkg/aes"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &ExecutionThrottle{})
+	autoMigrateList = append(autoMigrateList, &TaskThrottleEvent{})
+}
+
+const (
+	DefaultThrottleCheckIntervalSecond uint64 = 5
+	DefaultThrottleTimeoutSecond       uint64 = 1800
+)
+
+// ExecutionThrottle is the throttle settings of an instance, the execution of a task pauses between statements
+// and batches while the replication lag or Threads_running of the instance exceeds the limits.
+type ExecutionThrottle struct {
+	Model
+	ProjectId  string `json:"project_id" gorm:"type:varchar(255);not null"`
+	InstanceId string `json:"instance_id" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Enabled    bool   `json:"enabled"`
+
+	// ReplicaDSNs are the DSNs of the replicas, e.g. "user:password@tcp(10.10.10.10:3306)/". They are saved
+	// encrypted since they contain passwords.
+	ReplicaDSNs          []string `json:"-" gorm:"-"`
+	SecretReplicaDSNs    string   `json:"-" gorm:"column:replica_dsns;type:text"`
+	DiscoverReplicas     bool     `json:"discover_replicas"`
+	MaxReplicationLagSec uint64   `json:"max_replication_lag_sec"`
+	MaxThreadsRunning    uint64   `json:"max_threads_running"`
+	CheckIntervalSecond  uint64   `json:"check_interval_second" gorm:"not null;default:5"`
+	TimeoutSecond        uint64   `json:"timeout_second" gorm:"not null;default:1800"`
+}
+
+// BeforeSave is a hook implement gorm model before exec create
+func (t *ExecutionThrottle) BeforeSave(tx *gorm.DB) error {
+	data, err := dmsCommonAes.AesEncrypt(strings.Join(t.ReplicaDSNs, "\n"))
+	if err != nil {
+		return err
+	}
+	tx.Statement.SetColumn("SecretReplicaDSNs", data)
+	return nil
+}
+
+// AfterFind is a hook implement gorm model after query, ignore err if query from db
+func (t *ExecutionThrottle) AfterFind(tx *gorm.DB) error {
+	if t.SecretReplicaDSNs == "" {
+		return nil
+	}
+	data, err := dmsCommonAes.AesDecrypt(t.SecretReplicaDSNs)
+	if err != nil {
+		log.NewEntry().Errorf("decrypt replica dsns for instance %s failed, error: %v", t.InstanceId, err)
+		return nil
+	}
+	t.ReplicaDSNs = nil
+	for _, dsn := range strings.Split(data, "\n") {
+		if dsn != "" {
+			t.ReplicaDSNs = append(t.ReplicaDSNs, dsn)
+		}
+	}
+	return nil
+}
+
+// HasLimit reports whether any limit is set, the throttle takes no effect without limits.
+func (t *ExecutionThrottle) HasLimit() bool {
+	return t.Enabled && (t.MaxThreadsRunning > 0 ||
+		(t.MaxReplicationLagSec > 0 && (len(t.ReplicaDSNs) > 0 || t.DiscoverReplicas)))
+}
+
+func (s *Storage) GetExecutionThrottleByInstanceId(instanceId string) (*ExecutionThrottle, bool, error) {
+	throttle := &ExecutionThrottle{}
+	err := s.db.Where("instance_id = ?", instanceId).First(throttle).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return throttle, true, errors.New(errors.ConnectStorageError, err)
+}
+
+const (
+	ThrottleEventThrottled = "throttled"
+	ThrottleEventResumed   = "resumed"
+	ThrottleEventAborted   = "aborted"
+)
+
+// TaskThrottleEvent records that the execution of the task is paused, resumed or aborted by the throttle.
+type TaskThrottleEvent struct {
+	Model
+	TaskId uint `json:"task_id" gorm:"not null;index"`
+	// ExecuteSQLId is the first SQL waiting for the throttle.
+	ExecuteSQLId      uint   `json:"execute_sql_id"`
+	Event             string `json:"event" gorm:"type:varchar(32);not null"`
+	Reason            string `json:"reason" gorm:"type:text"`
+	ThreadsRunning    int64  `json:"threads_running"`
+	ReplicationLagSec int64  `json:"replication_lag_sec"`
+	// WaitedSecond is how long the execution has been paused when the event happens.
+	WaitedSecond int64 `json:"waited_second"`
+}
+
+func (s *Storage) GetTaskThrottleEvents(taskId uint) ([]*TaskThrottleEvent, error) {
+	events := []*TaskThrottleEvent{}
+	err := s.db.Where("task_id = ?", taskId).Order("id ASC").Find(&events).Error
+	return events, errors.New(errors.ConnectStorageError, err)
+}
@@ -722,7 +722,10 @@ func (s *Storage) GetSqlManageMetricRecordsByTime(sqlId string, metricName strin
 		return db.Where("metric_name = ?", metricName)
 	}).
 		Preload("SqlManageMetricExecutePlanRecords").
-		Where("sql_id = ? AND record_begin_at >= ? AND record_end_at <= ?", sqlId, timeBegin, timeEnd).Find(&records).Error
+		Where("sql_id = ? AND record_begin_at >= ? AND record_end_at <= ?", sqlId, timeBegin, timeEnd).
+		// 同一条SQL会有不同指标的记录，只返回包含该指标的记录
+		Where("id IN (SELECT sql_manage_metric_record_id FROM sql_manage_metric_values WHERE metric_name = ?)", metricName).
+		Find(&records).Error
 	if err != nil {
 		return nil, err
 	}
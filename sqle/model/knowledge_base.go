@@ -1,13 +1,36 @@
 package model
 
+import (
+	e "errors"
+	"strings"
+	"unicode"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &KnowledgeVersion{})
+}
+
+// Knowledge 知识，标题、描述、内容和示例使用 ngram 分词的全文索引以支持中文检索
 type Knowledge struct {
 	Model
-	Title       string        `gorm:"type:varchar(255);not null" json:"title"`                       // 标题
-	Description string        `gorm:"type:text" json:"description"`                                  // 描述
-	Content     string        `gorm:"type:text" json:"content"`                // 内容
-	Tags        []*Tag        `gorm:"many2many:knowledge_tag_relations" json:"tags"`                 // 标签
-	Rules       []*Rule       `gorm:"many2many:rule_knowledge_relations" json:"rules"`               // 规则和知识的关系
-	CustomRules []*CustomRule `gorm:"many2many:custom_rule_knowledge_relations" json:"custom_rules"` // 自定义规则和知识的关系
+	Title       string        `gorm:"type:varchar(255);not null;index:idx_knowledge_full_text,class:FULLTEXT,option:WITH PARSER ngram" json:"title"` // 标题
+	Description string        `gorm:"type:text;index:idx_knowledge_full_text,class:FULLTEXT,option:WITH PARSER ngram" json:"description"`            // 描述
+	Content     string        `gorm:"type:text;index:idx_knowledge_full_text,class:FULLTEXT,option:WITH PARSER ngram" json:"content"`                // 内容
+	Examples    string        `gorm:"type:text;index:idx_knowledge_full_text,class:FULLTEXT,option:WITH PARSER ngram" json:"examples"`               // 示例
+	Language    string        `gorm:"type:varchar(16);not null;default:'';index" json:"language"`                                                    // 语言，为空时适用于所有语言
+	ProjectId   ProjectUID    `gorm:"type:varchar(255);not null;default:'';index" json:"project_id"`                                                 // 团队知识所属的项目，为空时为全局知识
+	Version     uint32        `gorm:"not null;default:1" json:"version"`                                                                             // 版本，每次编辑后递增
+	EditorId    string        `gorm:"type:varchar(255)" json:"editor_id"`                                                                            // 最后编辑人，为空时由系统生成
+	Tags        []*Tag        `gorm:"many2many:knowledge_tag_relations" json:"tags"`                                                                 // 标签
+	Rules       []*Rule       `gorm:"many2many:rule_knowledge_relations" json:"rules"`                                                               // 规则和知识的关系
+	CustomRules []*CustomRule `gorm:"many2many:custom_rule_knowledge_relations" json:"custom_rules"`                                                 // 自定义规则和知识的关系
 }
 
 type MultiLanguageKnowledge []*Knowledge
@@ -15,3 +38,330 @@ type MultiLanguageKnowledge []*Knowledge
 func (Knowledge) TableName() string {
 	return "knowledge"
 }
+
+// GetByLangTag 获取指定语言的知识，不存在时依次使用英文、默认语言和不区分语言的知识
+func (m MultiLanguageKnowledge) GetByLangTag(lang language.Tag) *Knowledge {
+	for _, tag := range driverV2.FallbackLangTags(lang) {
+		for _, k := range m {
+			if k.Language == tag.String() {
+				return k
+			}
+		}
+	}
+	for _, k := range m {
+		if k.Language == "" {
+			return k
+		}
+	}
+	return nil
+}
+
+func (k *Knowledge) TagNames() []string {
+	names := make([]string, 0, len(k.Tags))
+	for _, tag := range k.Tags {
+		names = append(names, string(tag.Name))
+	}
+	return names
+}
+
+// KnowledgeVersion 知识的历史版本，每次保存知识时记录
+type KnowledgeVersion struct {
+	Model
+	KnowledgeId uint   `gorm:"not null;index" json:"knowledge_id"`
+	Version     uint32 `gorm:"not null" json:"version"`
+	Title       string `gorm:"type:varchar(255);not null" json:"title"`
+	Description string `gorm:"type:text" json:"description"`
+	Content     string `gorm:"type:text" json:"content"`
+	Examples    string `gorm:"type:text" json:"examples"`
+	EditorId    string `gorm:"type:varchar(255)" json:"editor_id"`
+}
+
+func (KnowledgeVersion) TableName() string {
+	return "knowledge_versions"
+}
+
+func newKnowledgeVersion(k *Knowledge) *KnowledgeVersion {
+	return &KnowledgeVersion{
+		KnowledgeId: k.ID,
+		Version:     k.Version,
+		Title:       k.Title,
+		Description: k.Description,
+		Content:     k.Content,
+		Examples:    k.Examples,
+		EditorId:    k.EditorId,
+	}
+}
+
+// SaveKnowledge 保存知识并记录新的版本，tags 为 nil 时不修改知识的标签
+func (s *Storage) SaveKnowledge(k *Knowledge, tags []*Tag) error {
+	return errors.New(errors.ConnectStorageError, s.db.Transaction(func(tx *gorm.DB) error {
+		return saveKnowledge(tx, k, tags)
+	}))
+}
+
+func saveKnowledge(tx *gorm.DB, k *Knowledge, tags []*Tag) error {
+	if k.ID == 0 {
+		k.Version = 1
+	} else {
+		k.Version++
+	}
+	if err := tx.Omit(clause.Associations).Save(k).Error; err != nil {
+		return err
+	}
+	if tags != nil {
+		if err := tx.Model(k).Omit("Tags.*").Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+		k.Tags = tags
+	}
+	return tx.Create(newKnowledgeVersion(k)).Error
+}
+
+// CreateRuleKnowledge 创建规则的知识
+func (s *Storage) CreateRuleKnowledge(rule *Rule, k *Knowledge, tags []*Tag) error {
+	return errors.New(errors.ConnectStorageError, s.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveKnowledge(tx, k, tags); err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO rule_knowledge_relations (rule_name, rule_db_type, knowledge_id) VALUES (?, ?, ?)",
+			rule.Name, rule.DBType, k.ID).Error
+	}))
+}
+
+// CreateCustomRuleKnowledge 创建自定义规则的知识
+func (s *Storage) CreateCustomRuleKnowledge(rule *CustomRule, k *Knowledge, tags []*Tag) error {
+	return errors.New(errors.ConnectStorageError, s.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveKnowledge(tx, k, tags); err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO custom_rule_knowledge_relations (custom_rule_id, knowledge_id) VALUES (?, ?)",
+			rule.ID, k.ID).Error
+	}))
+}
+
+func (s *Storage) GetKnowledgeOfRule(ruleName, dbType string) (MultiLanguageKnowledge, error) {
+	knowledge := MultiLanguageKnowledge{}
+	err := s.db.Preload("Tags").
+		Joins("JOIN rule_knowledge_relations ON rule_knowledge_relations.knowledge_id = knowledge.id").
+		Where("rule_knowledge_relations.rule_name = ? AND rule_knowledge_relations.rule_db_type = ?", ruleName, dbType).
+		Find(&knowledge).Error
+	return knowledge, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetKnowledgeOfCustomRule(customRuleId uint) (MultiLanguageKnowledge, error) {
+	knowledge := MultiLanguageKnowledge{}
+	err := s.db.Preload("Tags").
+		Joins("JOIN custom_rule_knowledge_relations ON custom_rule_knowledge_relations.knowledge_id = knowledge.id").
+		Where("custom_rule_knowledge_relations.custom_rule_id = ?", customRuleId).
+		Find(&knowledge).Error
+	return knowledge, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetKnowledgeById 获取全局知识或指定项目的团队知识
+func (s *Storage) GetKnowledgeById(projectId ProjectUID, id uint) (*Knowledge, bool, error) {
+	k := &Knowledge{}
+	err := s.db.Preload("Tags").Preload("Rules").Preload("CustomRules").
+		Where("id = ? AND project_id IN (?)", id, []ProjectUID{"", projectId}).First(k).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return k, true, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) DeleteKnowledge(k *Knowledge) error {
+	return errors.New(errors.ConnectStorageError, s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(k).Association("Tags").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Where("knowledge_id = ?", k.ID).Delete(&KnowledgeVersion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(k).Error
+	}))
+}
+
+func (s *Storage) GetKnowledgeVersions(knowledgeId uint) ([]*KnowledgeVersion, error) {
+	versions := []*KnowledgeVersion{}
+	err := s.db.Where("knowledge_id = ?", knowledgeId).Order("version DESC").Find(&versions).Error
+	return versions, errors.New(errors.ConnectStorageError, err)
+}
+
+type KnowledgeFilter struct {
+	Keywords string
+	Tags     []string
+	// ProjectId 不为空时同时检索项目的团队知识
+	ProjectId ProjectUID
+	// Language 不为空时只检索该语言和不区分语言的知识
+	Language  string
+	PageIndex uint32
+	PageSize  uint32
+}
+
+// SearchKnowledge 通过全文索引检索知识，指定关键字时按相关度排序
+func (s *Storage) SearchKnowledge(filter *KnowledgeFilter) ([]*Knowledge, uint64, error) {
+	var count int64
+	knowledge := []*Knowledge{}
+	query := s.db.Model(&Knowledge{}).Where("knowledge.project_id IN (?)", []ProjectUID{"", filter.ProjectId})
+	if filter.Language != "" {
+		query = query.Where("knowledge.language IN (?)", []string{"", filter.Language})
+	}
+	if len(filter.Tags) > 0 {
+		query = query.Where("knowledge.id IN (?)", s.db.Table("knowledge_tag_relations").
+			Select("knowledge_tag_relations.knowledge_id").
+			Joins("JOIN tags ON tags.id = knowledge_tag_relations.tag_id").
+			Where("tags.name IN (?)", filter.Tags))
+	}
+	match := "MATCH (knowledge.title, knowledge.description, knowledge.content, knowledge.examples) AGAINST (? IN BOOLEAN MODE)"
+	keywords := FullTextBooleanQuery(filter.Keywords)
+	if keywords != "" {
+		query = query.Where(match, keywords)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return knowledge, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	if count == 0 {
+		return knowledge, 0, nil
+	}
+
+	if keywords != "" {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{SQL: match + " DESC", Vars: []interface{}{keywords}, WithoutParentheses: true}})
+	}
+	err := query.Preload("Tags").Preload("Rules").Preload("CustomRules").Order("knowledge.id DESC").
+		Offset(int((filter.PageIndex - 1) * filter.PageSize)).Limit(int(filter.PageSize)).Find(&knowledge).Error
+	return knowledge, uint64(count), errors.New(errors.ConnectStorageError, err)
+}
+
+// FullTextBooleanQuery 将用户输入的关键字转换为全文索引的布尔模式查询，结果需要包含所有关键字
+func FullTextBooleanQuery(keywords string) string {
+	terms := strings.FieldsFunc(keywords, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`+-<>()~*"@`, r)
+	})
+	for i, term := range terms {
+		terms[i] = `+"` + term + `"`
+	}
+	return strings.Join(terms, " ")
+}
+
+// RelatedRule 与知识有相同标签的规则
+type RelatedRule struct {
+	RuleName     string
+	DBType       string
+	IsCustomRule bool
+	SharedTags   int
+}
+
+// GetRelatedRulesOfKnowledge 获取和知识有相同标签的规则，不包含知识自身关联的规则，按相同标签数排序
+func (s *Storage) GetRelatedRulesOfKnowledge(knowledgeId uint, limit int) ([]*RelatedRule, error) {
+	rules := []*RelatedRule{}
+	err := s.db.Raw(`
+SELECT * FROM (
+	SELECT r.rule_name, r.rule_db_type AS db_type, false AS is_custom_rule, COUNT(DISTINCT kt.tag_id) AS shared_tags
+	FROM knowledge_tag_relations kt
+	JOIN rule_knowledge_relations r ON r.knowledge_id = kt.knowledge_id
+	WHERE kt.tag_id IN (SELECT tag_id FROM knowledge_tag_relations WHERE knowledge_id = ?)
+	AND NOT EXISTS (SELECT 1 FROM rule_knowledge_relations self
+		WHERE self.knowledge_id = ? AND self.rule_name = r.rule_name AND self.rule_db_type = r.rule_db_type)
+	GROUP BY r.rule_name, r.rule_db_type
+	UNION ALL
+	SELECT c.rule_id AS rule_name, c.db_type, true AS is_custom_rule, COUNT(DISTINCT kt.tag_id) AS shared_tags
+	FROM knowledge_tag_relations kt
+	JOIN custom_rule_knowledge_relations cr ON cr.knowledge_id = kt.knowledge_id
+	JOIN custom_rules c ON c.id = cr.custom_rule_id AND c.deleted_at IS NULL
+	WHERE kt.tag_id IN (SELECT tag_id FROM knowledge_tag_relations WHERE knowledge_id = ?)
+	AND NOT EXISTS (SELECT 1 FROM custom_rule_knowledge_relations self
+		WHERE self.knowledge_id = ? AND self.custom_rule_id = cr.custom_rule_id)
+	GROUP BY c.rule_id, c.db_type
+) related
+ORDER BY shared_tags DESC, rule_name ASC
+LIMIT ?`, knowledgeId, knowledgeId, knowledgeId, knowledgeId, limit).Scan(&rules).Error
+	return rules, errors.New(errors.ConnectStorageError, err)
+}
+
+// RuleKnowledgeRef 规则关联的知识
+type RuleKnowledgeRef struct {
+	RuleName    string
+	KnowledgeId uint
+	Language    string
+}
+
+// GetKnowledgeRefsOfRules 获取规则和自定义规则关联的知识，用于从审核结果跳转到知识
+func (s *Storage) GetKnowledgeRefsOfRules(dbType string, ruleNames []string) ([]*RuleKnowledgeRef, error) {
+	refs := []*RuleKnowledgeRef{}
+	if len(ruleNames) == 0 {
+		return refs, nil
+	}
+	err := s.db.Raw(`
+SELECT r.rule_name, k.id AS knowledge_id, k.language
+FROM rule_knowledge_relations r
+JOIN knowledge k ON k.id = r.knowledge_id AND k.deleted_at IS NULL
+WHERE r.rule_db_type = ? AND r.rule_name IN (?)
+UNION ALL
+SELECT c.rule_id AS rule_name, k.id AS knowledge_id, k.language
+FROM custom_rules c
+JOIN custom_rule_knowledge_relations cr ON cr.custom_rule_id = c.id
+JOIN knowledge k ON k.id = cr.knowledge_id AND k.deleted_at IS NULL
+WHERE c.db_type = ? AND c.rule_id IN (?) AND c.deleted_at IS NULL`, dbType, ruleNames, dbType, ruleNames).Scan(&refs).Error
+	return refs, errors.New(errors.ConnectStorageError, err)
+}
+
+// KnowledgeTagRef 知识的标签，用于生成知识图谱
+type KnowledgeTagRef struct {
+	KnowledgeId uint
+	TagId       uint
+	TagName     string
+}
+
+// GetKnowledgeTagRefs 获取全局知识的标签，ruleName 不为空时只获取该规则的知识的标签
+func (s *Storage) GetKnowledgeTagRefs(ruleName string) ([]*KnowledgeTagRef, error) {
+	refs := []*KnowledgeTagRef{}
+	query := s.db.Table("knowledge_tag_relations").
+		Select("knowledge_tag_relations.knowledge_id, tags.id AS tag_id, tags.name AS tag_name").
+		Joins("JOIN tags ON tags.id = knowledge_tag_relations.tag_id").
+		Joins("JOIN knowledge ON knowledge.id = knowledge_tag_relations.knowledge_id AND knowledge.deleted_at IS NULL").
+		Where("knowledge.project_id = ''")
+	if ruleName != "" {
+		query = query.Where("knowledge.id IN (?)", s.db.Table("rule_knowledge_relations").
+			Select("knowledge_id").Where("rule_name = ?", ruleName))
+	}
+	err := query.Scan(&refs).Error
+	return refs, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetAllTags() ([]*Tag, error) {
+	tags := []*Tag{}
+	err := s.db.Preload("SubTag").Order("id ASC").Find(&tags).Error
+	return tags, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetOrCreateTags 获取指定名称的标签，不存在的标签会被创建
+func (s *Storage) GetOrCreateTags(names []string) ([]*Tag, error) {
+	tags := []*Tag{}
+	if len(names) == 0 {
+		return tags, nil
+	}
+	if err := s.db.Where("name IN (?)", names).Find(&tags).Error; err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+	exist := make(map[TypeTag]struct{}, len(tags))
+	for _, tag := range tags {
+		exist[tag.Name] = struct{}{}
+	}
+	for _, name := range names {
+		if _, ok := exist[TypeTag(name)]; ok {
+			continue
+		}
+		tag := &Tag{Name: TypeTag(name)}
+		if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(tag).Error; err != nil {
+			return nil, errors.New(errors.ConnectStorageError, err)
+		}
+		if tag.ID == 0 {
+			// the tag is created concurrently
+			if err := s.db.Where("name = ?", name).First(tag).Error; err != nil {
+				return nil, errors.New(errors.ConnectStorageError, err)
+			}
+		}
+		exist[tag.Name] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
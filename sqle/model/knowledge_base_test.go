@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestFullTextBooleanQuery(t *testing.T) {
+	assert.Equal(t, "", FullTextBooleanQuery("   "))
+	assert.Equal(t, `+"select" +"index"`, FullTextBooleanQuery("select  index"))
+	assert.Equal(t, `+"a" +"b" +"c"`, FullTextBooleanQuery(`+a -b "c"*`))
+	assert.Equal(t, `+"索引"`, FullTextBooleanQuery("索引"))
+}
+
+func TestMultiLanguageKnowledge_GetByLangTag(t *testing.T) {
+	zh := &Knowledge{Language: language.Chinese.String()}
+	en := &Knowledge{Language: language.English.String()}
+	general := &Knowledge{Language: ""}
+
+	knowledge := MultiLanguageKnowledge{zh, en, general}
+	assert.Equal(t, zh, knowledge.GetByLangTag(language.Chinese))
+	assert.Equal(t, en, knowledge.GetByLangTag(language.English))
+	assert.Equal(t, en, knowledge.GetByLangTag(language.Japanese))
+
+	knowledge = MultiLanguageKnowledge{zh, general}
+	assert.Equal(t, zh, knowledge.GetByLangTag(language.Japanese))
+
+	knowledge = MultiLanguageKnowledge{general}
+	assert.Equal(t, general, knowledge.GetByLangTag(language.English))
+
+	assert.Nil(t, MultiLanguageKnowledge{}.GetByLangTag(language.English))
+}
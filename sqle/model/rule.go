@@ -44,6 +44,7 @@ func GenerateRuleByDriverRule(dr *driverV2.Rule, dbType string) *Rule {
 			Title:       v.Desc,
 			Content:     v.Knowledge.Content,
 			Description: v.Annotation,
+			Language:    lang.String(),
 		})
 		r.I18nRuleInfo[lang] = &driverV2.RuleInfo{
 			Desc:       v.Desc,
@@ -93,6 +94,11 @@ func (r *RuleKnowledge) GetContentByLangTag(lang language.Tag) string {
 	if r == nil {
 		return ""
 	}
+	for _, tag := range driverV2.FallbackLangTags(lang) {
+		if content, ok := r.I18nContent[tag]; ok {
+			return content
+		}
+	}
 	return r.I18nContent.GetStrInLang(lang)
 }
 
@@ -0,0 +1,226 @@
+package model
+
+import (
+	e "errors"
+	"fmt"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &SchemaBaseline{})
+	autoMigrateList = append(autoMigrateList, &SchemaBaselineObject{})
+	autoMigrateList = append(autoMigrateList, &SchemaDriftFinding{})
+}
+
+const (
+	SchemaBaselineSourceSnapshot = "snapshot"
+	SchemaBaselineSourceWorkflow = "workflow"
+)
+
+// SchemaBaseline is the approved schema pinned for an instance. Only the
+// latest baseline of an instance is effective, the older ones are soft deleted.
+type SchemaBaseline struct {
+	Model
+	ProjectId    string `json:"project_id" gorm:"type:varchar(255);not null"`
+	InstanceId   string `json:"instance_id" gorm:"type:varchar(255);not null;index"`
+	Source       string `json:"source" gorm:"type:varchar(255);not null"`
+	Desc         string `json:"desc" gorm:"type:varchar(512)"`
+	CreateUserId string `json:"create_user_id" gorm:"type:varchar(255)"`
+
+	Objects []*SchemaBaselineObject `gorm:"foreignkey:SchemaBaselineId"`
+}
+
+type SchemaBaselineObject struct {
+	Model
+	SchemaBaselineId uint   `json:"schema_baseline_id" gorm:"not null;index"`
+	SchemaName       string `json:"schema_name" gorm:"type:varchar(255);not null"`
+	TableName        string `json:"table_name" gorm:"type:varchar(255);not null"`
+	CreateTableSQL   string `json:"create_table_sql" gorm:"type:mediumtext;not null"`
+	// WorkflowId is the last workflow touching the table, it is empty if the table comes from a snapshot.
+	WorkflowId string `json:"workflow_id" gorm:"type:varchar(255)"`
+}
+
+func (s *Storage) GetSchemaBaselineByInstanceId(instanceId string) (*SchemaBaseline, bool, error) {
+	baseline := &SchemaBaseline{}
+	err := s.db.Preload("Objects").Where("instance_id = ?", instanceId).Order("id desc").First(baseline).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return baseline, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// SaveSchemaBaseline replaces the effective baseline of the instance, the open
+// drift findings of the previous baseline are resolved together.
+func (s *Storage) SaveSchemaBaseline(baseline *SchemaBaseline) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		var oldIds []uint
+		err := txDB.Model(&SchemaBaseline{}).Where("instance_id = ?", baseline.InstanceId).Pluck("id", &oldIds).Error
+		if err != nil {
+			return err
+		}
+		if len(oldIds) > 0 {
+			if err := txDB.Where("schema_baseline_id IN (?)", oldIds).Delete(&SchemaBaselineObject{}).Error; err != nil {
+				return err
+			}
+			if err := txDB.Where("id IN (?)", oldIds).Delete(&SchemaBaseline{}).Error; err != nil {
+				return err
+			}
+			err = txDB.Model(&SchemaDriftFinding{}).
+				Where("schema_baseline_id IN (?) AND status = ?", oldIds, SchemaDriftStatusOpen).
+				Updates(map[string]interface{}{"status": SchemaDriftStatusResolved, "resolved_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return txDB.Create(baseline).Error
+	})
+}
+
+// ExecutedDDL is a DDL statement which has been executed successfully by a finished workflow.
+type ExecutedDDL struct {
+	WorkflowId string     `json:"workflow_id"`
+	Schema     string     `json:"schema"`
+	Content    string     `json:"content"`
+	ExecEndAt  *time.Time `json:"exec_end_at"`
+}
+
+// GetExecutedDDLsOfFinishedWorkflows returns the DDL executed on the instance by finished workflows after `since`,
+// in execution order.
+func (s *Storage) GetExecutedDDLsOfFinishedWorkflows(instanceId string, since *time.Time) ([]*ExecutedDDL, error) {
+	ddls := []*ExecutedDDL{}
+	query := s.db.Table("execute_sql_detail AS esd").
+		Select("w.workflow_id, IF(esd.schema = '', t.instance_schema, esd.schema) AS `schema`, esd.content, t.exec_end_at").
+		Joins("JOIN tasks AS t ON t.id = esd.task_id").
+		Joins("JOIN workflow_instance_records AS wir ON wir.task_id = t.id").
+		Joins("JOIN workflows AS w ON w.workflow_record_id = wir.workflow_record_id").
+		Joins("JOIN workflow_records AS wr ON wr.id = w.workflow_record_id").
+		Where("t.instance_id = ? AND esd.sql_type = ? AND esd.exec_status = ?", instanceId, driverV2.SQLTypeDDL, SQLExecuteStatusSucceeded).
+		Where("wr.status = ?", WorkflowStatusFinish).
+		Where("esd.deleted_at IS NULL AND t.deleted_at IS NULL AND w.deleted_at IS NULL")
+	if since != nil {
+		query = query.Where("t.exec_end_at > ?", since)
+	}
+	err := query.Order("t.exec_end_at ASC, esd.number ASC").Scan(&ddls).Error
+	return ddls, errors.New(errors.ConnectStorageError, err)
+}
+
+const (
+	SchemaDriftTypeTableAdded    = "table_added"
+	SchemaDriftTypeTableRemoved  = "table_removed"
+	SchemaDriftTypeColumnAdded   = "column_added"
+	SchemaDriftTypeColumnRemoved = "column_removed"
+	SchemaDriftTypeColumnChanged = "column_changed"
+	SchemaDriftTypeIndexAdded    = "index_added"
+	SchemaDriftTypeIndexRemoved  = "index_removed"
+	SchemaDriftTypeIndexChanged  = "index_changed"
+)
+
+const (
+	SchemaDriftStatusOpen     = "open"
+	SchemaDriftStatusResolved = "resolved"
+)
+
+// SchemaDriftFinding records a difference between the instance and its baseline,
+// which is not made by any finished workflow.
+// WorkflowId is the workflow which last changed the baseline definition of the object,
+// it is empty for the objects added out of workflows.
+type SchemaDriftFinding struct {
+	Model
+	ProjectId          string     `json:"project_id" gorm:"type:varchar(255);not null;index:idx_project_instance"`
+	InstanceId         string     `json:"instance_id" gorm:"type:varchar(255);not null;index:idx_project_instance"`
+	SchemaBaselineId   uint       `json:"schema_baseline_id" gorm:"not null;index"`
+	AuditPlanId        uint       `json:"audit_plan_id"`
+	SchemaName         string     `json:"schema_name" gorm:"type:varchar(255);not null"`
+	TableName          string     `json:"table_name" gorm:"type:varchar(255);not null"`
+	DriftType          string     `json:"drift_type" gorm:"type:varchar(64);not null"`
+	ObjectName         string     `json:"object_name" gorm:"type:varchar(255)"`
+	BaselineDefinition string     `json:"baseline_definition" gorm:"type:mediumtext"`
+	CurrentDefinition  string     `json:"current_definition" gorm:"type:mediumtext"`
+	Diff               string     `json:"diff" gorm:"type:mediumtext"`
+	WorkflowId         string     `json:"workflow_id" gorm:"type:varchar(255)"`
+	Status             string     `json:"status" gorm:"type:varchar(64);not null;default:'open'"`
+	FirstDetectedAt    *time.Time `json:"first_detected_at"`
+	LastDetectedAt     *time.Time `json:"last_detected_at"`
+	ResolvedAt         *time.Time `json:"resolved_at"`
+}
+
+// Key identifies the drifted object, the same drift found by different collections has the same key.
+func (f *SchemaDriftFinding) Key() string {
+	return fmt.Sprintf("%s.%s:%s:%s", f.SchemaName, f.TableName, f.DriftType, f.ObjectName)
+}
+
+// SyncSchemaDriftFindings saves the drift found by the latest collection: the new drift is created,
+// the existing one is refreshed and the open drift which is not found anymore is resolved.
+func (s *Storage) SyncSchemaDriftFindings(baselineId uint, findings []*SchemaDriftFinding) error {
+	now := time.Now()
+	return s.Tx(func(txDB *gorm.DB) error {
+		existed := []*SchemaDriftFinding{}
+		err := txDB.Where("schema_baseline_id = ? AND status = ?", baselineId, SchemaDriftStatusOpen).Find(&existed).Error
+		if err != nil {
+			return err
+		}
+		existedMap := make(map[string]*SchemaDriftFinding, len(existed))
+		for _, f := range existed {
+			existedMap[f.Key()] = f
+		}
+		for _, f := range findings {
+			if old, ok := existedMap[f.Key()]; ok {
+				delete(existedMap, f.Key())
+				err = txDB.Model(old).Updates(map[string]interface{}{
+					"baseline_definition": f.BaselineDefinition,
+					"current_definition":  f.CurrentDefinition,
+					"diff":                f.Diff,
+					"workflow_id":         f.WorkflowId,
+					"audit_plan_id":       f.AuditPlanId,
+					"last_detected_at":    now,
+				}).Error
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			f.SchemaBaselineId = baselineId
+			f.Status = SchemaDriftStatusOpen
+			f.FirstDetectedAt = &now
+			f.LastDetectedAt = &now
+			if err = txDB.Create(f).Error; err != nil {
+				return err
+			}
+		}
+		for _, f := range existedMap {
+			err = txDB.Model(f).Updates(map[string]interface{}{"status": SchemaDriftStatusResolved, "resolved_at": now}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Storage) GetSchemaDriftFindingList(projectId, instanceId, status, driftType string, pageIndex, pageSize uint32) ([]*SchemaDriftFinding, uint64, error) {
+	var count int64
+	findings := []*SchemaDriftFinding{}
+	query := s.db.Model(&SchemaDriftFinding{}).Where("project_id = ?", projectId)
+	if instanceId != "" {
+		query = query.Where("instance_id = ?", instanceId)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if driftType != "" {
+		query = query.Where("drift_type = ?", driftType)
+	}
+	err := query.Count(&count).Error
+	if err != nil {
+		return findings, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	if count == 0 {
+		return findings, 0, nil
+	}
+	err = query.Offset(int((pageIndex - 1) * pageSize)).Limit(int(pageSize)).Order("id desc").Find(&findings).Error
+	return findings, uint64(count), errors.New(errors.ConnectStorageError, err)
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	e "errors"
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &ScoringPolicy{})
+}
+
+// ScoringPolicy replaces the default scoring of the audit tasks in the project. The score starts from
+// BaseScore, each audit result deducts the penalty of its rule, or the penalty of its level if the rule has
+// no penalty. The penalties of the rules in a category are limited by the cap of the category, and any
+// result of a hard-fail rule makes the score 0. While the policy is enabled, the submission of the workflow is
+// gated by the score and the allowable submission level of the workflow template.
+type ScoringPolicy struct {
+	Model
+	ProjectId string `json:"project_id" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Enabled   bool   `json:"enabled"`
+	BaseScore int32  `json:"base_score" gorm:"not null;default:100"`
+	// LevelPenalties is keyed by the rule level, e.g. {"error": 20, "warn": 5, "notice": 1}
+	LevelPenalties ScoreWeights `json:"level_penalties" gorm:"type:json"`
+	// RulePenalties is keyed by the rule name, it overrides the level penalty.
+	RulePenalties ScoreWeights `json:"rule_penalties" gorm:"type:json"`
+	// CategoryCaps is keyed by the rule category tag, e.g. {"performance": 30}
+	CategoryCaps  ScoreWeights `json:"category_caps" gorm:"type:json"`
+	HardFailRules Strings      `json:"hard_fail_rules" gorm:"type:json"`
+	// MinSubmitScore forbids submitting the workflow if the score of a task is lower, 0 means no limit.
+	MinSubmitScore int32 `json:"min_submit_score"`
+}
+
+type ScoreWeights map[string]float64
+
+// Scan impl sql.Scanner interface
+func (w *ScoreWeights) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal json value: %v", value)
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	result := ScoreWeights{}
+	err := json.Unmarshal(bytes, &result)
+	*w = result
+	return err
+}
+
+// Value impl sql.driver.Valuer interface
+func (w ScoreWeights) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return json.Marshal(w)
+}
+
+func (s *Storage) GetScoringPolicyByProjectId(projectId string) (*ScoringPolicy, bool, error) {
+	policy := &ScoringPolicy{}
+	err := s.db.Where("project_id = ?", projectId).First(policy).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return policy, true, errors.New(errors.ConnectStorageError, err)
+}
+
+const (
+	ScorePolicyDefault = "default"
+	ScorePolicyProject = "project"
+)
+
+// ScoreBreakdown explains how the score of the task is calculated.
+type ScoreBreakdown struct {
+	Policy string `json:"policy"`
+	// LevelCounts is the number of SQLs at each audit level
+	LevelCounts map[string]int `json:"level_counts"`
+
+	// the fields below are only set by the project policy
+	BaseScore         int32                   `json:"base_score,omitempty"`
+	LevelPenalties    map[string]float64      `json:"level_penalties,omitempty"`
+	RulePenalties     map[string]float64      `json:"rule_penalties,omitempty"`
+	CategoryPenalties []*CategoryScorePenalty `json:"category_penalties,omitempty"`
+	TotalPenalty      float64                 `json:"total_penalty,omitempty"`
+	HardFailRules     []string                `json:"hard_fail_rules,omitempty"`
+	Score             int32                   `json:"score"`
+}
+
+type CategoryScorePenalty struct {
+	Category string  `json:"category"`
+	Penalty  float64 `json:"penalty"`
+	Cap      float64 `json:"cap"`
+	// Capped is true if the penalty of the rules in the category exceeds the cap
+	Capped bool `json:"capped"`
+}
+
+// IsHardFailed reports whether a hard-fail rule is hit.
+func (b *ScoreBreakdown) IsHardFailed() bool {
+	return b != nil && len(b.HardFailRules) > 0
+}
+
+// Scan impl sql.Scanner interface
+func (b *ScoreBreakdown) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal json value: %v", value)
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, b)
+}
+
+// Value impl sql.driver.Valuer interface
+func (b ScoreBreakdown) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
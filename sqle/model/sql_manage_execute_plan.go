@@ -0,0 +1,137 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &SqlManageExecutePlan{})
+	autoMigrateList = append(autoMigrateList, &SqlManageExecutePlanChange{})
+}
+
+// SqlManageExecutePlan is a distinct execute plan of the SQL, the plans with the same access
+// types, chosen indexes and join order have the same hash.
+type SqlManageExecutePlan struct {
+	Model
+	SQLID       string `json:"sql_id" gorm:"type:varchar(255);not null;uniqueIndex:uniq_sql_id_plan_hash"`
+	PlanHash    string `json:"plan_hash" gorm:"type:varchar(64);not null;uniqueIndex:uniq_sql_id_plan_hash"`
+	PlanSummary string `json:"plan_summary" gorm:"type:text"`
+	// LastMetricRecordID is the latest metric record which has the plan, its execute plan records are the detail of the plan
+	LastMetricRecordID uint      `json:"last_metric_record_id"`
+	SeenCount          int       `json:"seen_count" gorm:"not null;default:1"`
+	FirstSeenAt        time.Time `json:"first_seen_at"`
+	LastSeenAt         time.Time `json:"last_seen_at" gorm:"index"`
+}
+
+const (
+	PlanChangeTypeIndexToFullScan   = "index_to_full_scan"
+	PlanChangeTypeFullScanToIndex   = "full_scan_to_index"
+	PlanChangeTypeIndexChanged      = "index_changed"
+	PlanChangeTypeAccessTypeChanged = "access_type_changed"
+	PlanChangeTypeJoinOrderChanged  = "join_order_changed"
+)
+
+// SqlManageExecutePlanChange records that the plan of the SQL flips from one plan to another.
+type SqlManageExecutePlanChange struct {
+	Model
+	SQLID        string    `json:"sql_id" gorm:"type:varchar(255);not null;index"`
+	ProjectId    string    `json:"project_id" gorm:"type:varchar(255)"`
+	FromPlanID   uint      `json:"from_plan_id"`
+	ToPlanID     uint      `json:"to_plan_id"`
+	ChangeTypes  Strings   `json:"change_types"`
+	IsRegression bool      `json:"is_regression"`
+	DetectedAt   time.Time `json:"detected_at"`
+}
+
+// GetLatestSqlManageExecutePlan returns the plan seen most recently.
+func (s *Storage) GetLatestSqlManageExecutePlan(sqlId string) (*SqlManageExecutePlan, bool, error) {
+	plan := &SqlManageExecutePlan{}
+	err := s.db.Where("sql_id = ?", sqlId).Order("last_seen_at DESC, id DESC").First(plan).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return plan, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// UpsertSqlManageExecutePlan creates the plan, or counts it as seen again if the SQL has the plan already.
+// The plan is reloaded, so its id and seen count are those of the saved row.
+func (s *Storage) UpsertSqlManageExecutePlan(plan *SqlManageExecutePlan) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		err := txDB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "sql_id"}, {Name: "plan_hash"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"seen_count":            gorm.Expr("seen_count + 1"),
+				"last_seen_at":          plan.LastSeenAt,
+				"last_metric_record_id": plan.LastMetricRecordID,
+			}),
+		}).Create(plan).Error
+		if err != nil {
+			return err
+		}
+		return txDB.Where("sql_id = ? AND plan_hash = ?", plan.SQLID, plan.PlanHash).First(plan).Error
+	})
+}
+
+func (s *Storage) GetSqlManageExecutePlanById(sqlId string, id uint) (*SqlManageExecutePlan, bool, error) {
+	plan := &SqlManageExecutePlan{}
+	err := s.db.Where("sql_id = ? AND id = ?", sqlId, id).First(plan).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return plan, true, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetSqlManageExecutePlans(sqlId string) ([]*SqlManageExecutePlan, error) {
+	plans := []*SqlManageExecutePlan{}
+	err := s.db.Where("sql_id = ?", sqlId).Order("first_seen_at ASC").Find(&plans).Error
+	return plans, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetSqlManageExecutePlanChanges(sqlId string) ([]*SqlManageExecutePlanChange, error) {
+	changes := []*SqlManageExecutePlanChange{}
+	err := s.db.Where("sql_id = ?", sqlId).Order("detected_at DESC").Find(&changes).Error
+	return changes, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetSqlManageMetricExecutePlanRecords(metricRecordId uint) ([]*SqlManageMetricExecutePlanRecord, error) {
+	records := []*SqlManageMetricExecutePlanRecord{}
+	err := s.db.Where("sql_manage_metric_record_id = ?", metricRecordId).Order("id ASC").Find(&records).Error
+	return records, errors.New(errors.ConnectStorageError, err)
+}
+
+// SaveSqlManageExecutePlanChange saves the plan flip, a regression flip raises the priority of the SQL in SQL management.
+func (s *Storage) SaveSqlManageExecutePlanChange(change *SqlManageExecutePlanChange) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if err := txDB.Create(change).Error; err != nil {
+			return err
+		}
+		if !change.IsRegression {
+			return nil
+		}
+		return txDB.Model(&SQLManageRecord{}).Where("sql_id = ?", change.SQLID).Update("priority", PriorityHigh).Error
+	})
+}
+
+// GetLatestSqlManageExecutePlanChangesBySQLIds returns the latest plan change keyed by the SQL id.
+func (s *Storage) GetLatestSqlManageExecutePlanChangesBySQLIds(sqlIds []string) (map[string]*SqlManageExecutePlanChange, error) {
+	result := make(map[string]*SqlManageExecutePlanChange, len(sqlIds))
+	if len(sqlIds) == 0 {
+		return result, nil
+	}
+	changes := []*SqlManageExecutePlanChange{}
+	// the changes are created in the order they are detected, so the latest change has the max id
+	err := s.db.Where("id IN (?)", s.db.Model(&SqlManageExecutePlanChange{}).
+		Select("MAX(id)").Where("sql_id IN (?)", sqlIds).Group("sql_id")).
+		Find(&changes).Error
+	if err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+	for _, change := range changes {
+		result[change.SQLID] = change
+	}
+	return result, nil
+}
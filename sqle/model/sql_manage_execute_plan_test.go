@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_UpsertSqlManageExecutePlan(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	plan := &SqlManageExecutePlan{SQLID: "sql1", PlanHash: "hash1", LastMetricRecordID: 3, SeenCount: 1, FirstSeenAt: now, LastSeenAt: now}
+	mock.ExpectBegin()
+	// the plan exists, it is counted as seen again instead of failing on the unique key
+	mock.ExpectExec("INSERT INTO `sql_manage_execute_plans` (`deleted_at`,`sql_id`,`plan_hash`,`plan_summary`,`last_metric_record_id`,`seen_count`,`first_seen_at`,`last_seen_at`) VALUES (?,?,?,?,?,?,?,?) ON DUPLICATE KEY UPDATE `last_metric_record_id`=?,`last_seen_at`=?,`seen_count`=seen_count + 1").
+		WithArgs(nil, "sql1", "hash1", "", 3, 1, now, now, 3, now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT * FROM `sql_manage_execute_plans` WHERE (sql_id = ? AND plan_hash = ?) AND `sql_manage_execute_plans`.`deleted_at` IS NULL ORDER BY `sql_manage_execute_plans`.`id` LIMIT 1").
+		WithArgs("sql1", "hash1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sql_id", "plan_hash", "seen_count"}).AddRow(7, "sql1", "hash1", 5))
+	mock.ExpectCommit()
+
+	assert.NoError(t, GetStorage().UpsertSqlManageExecutePlan(plan))
+	assert.Equal(t, uint(7), plan.ID)
+	assert.Equal(t, 5, plan.SeenCount)
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_GetLatestSqlManageExecutePlanChangesBySQLIds(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+
+	mock.ExpectQuery("SELECT * FROM `sql_manage_execute_plan_changes` WHERE id IN (SELECT MAX(id) FROM `sql_manage_execute_plan_changes` WHERE sql_id IN (?,?) AND `sql_manage_execute_plan_changes`.`deleted_at` IS NULL GROUP BY `sql_id`) AND `sql_manage_execute_plan_changes`.`deleted_at` IS NULL").
+		WithArgs("sql1", "sql2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sql_id", "is_regression"}).AddRow(9, "sql1", true))
+	changes, err := GetStorage().GetLatestSqlManageExecutePlanChangesBySQLIds([]string{"sql1", "sql2"})
+	assert.NoError(t, err)
+	assert.True(t, changes["sql1"].IsRegression)
+	assert.Nil(t, changes["sql2"])
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
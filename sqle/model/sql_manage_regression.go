@@ -0,0 +1,150 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &SqlManageRegression{})
+}
+
+const (
+	SqlRegressionTypeLatency      = "latency"
+	SqlRegressionTypeRowsExamined = "rows_examined"
+	SqlRegressionTypeFrequency    = "frequency"
+)
+
+const (
+	SqlRegressionStatusActive    = "active"
+	SqlRegressionStatusRecovered = "recovered"
+)
+
+// SqlManageRegression records a regression of the SQL found by comparing its latest metrics
+// with the baseline built from its metric history.
+type SqlManageRegression struct {
+	Model
+	SQLID          string     `json:"sql_id" gorm:"type:varchar(255);not null;index:idx_sql_id_status"`
+	ProjectId      string     `json:"project_id" gorm:"type:varchar(255)"`
+	InstanceID     string     `json:"instance_id" gorm:"type:varchar(255)"`
+	RegressionType string     `json:"regression_type" gorm:"type:varchar(64);not null"`
+	MetricName     string     `json:"metric_name" gorm:"type:varchar(255);not null"`
+	BaselineMedian float64    `json:"baseline_median" gorm:"type:decimal(20,4);not null;default:0"`
+	BaselineP95    float64    `json:"baseline_p95" gorm:"type:decimal(20,4);not null;default:0"`
+	CurrentValue   float64    `json:"current_value" gorm:"type:decimal(20,4);not null;default:0"`
+	Ratio          float64    `json:"ratio" gorm:"type:decimal(20,4);not null;default:0"`
+	SampleCount    int        `json:"sample_count"`
+	Status         string     `json:"status" gorm:"type:varchar(64);not null;index:idx_sql_id_status"`
+	DetectedAt     time.Time  `json:"detected_at"`
+	RecoveredAt    *time.Time `json:"recovered_at"`
+}
+
+// SqlManageMetricSample is a metric value of the SQL recorded in a time range.
+type SqlManageMetricSample struct {
+	SQLID          string
+	MetricName     string
+	MetricValue    float64
+	ExecutionCount int
+	RecordEndAt    time.Time
+}
+
+// GetSQLIDsWithMetricRecordSince returns the SQL which has metric records after `since`.
+func (s *Storage) GetSQLIDsWithMetricRecordSince(since time.Time, metricNames []string) ([]string, error) {
+	sqlIds := []string{}
+	err := s.db.Table("sql_manage_metric_records AS r").
+		Joins("JOIN sql_manage_metric_values AS v ON v.sql_manage_metric_record_id = r.id").
+		Where("r.record_end_at >= ? AND v.metric_name IN (?) AND r.deleted_at IS NULL", since, metricNames).
+		Distinct().Pluck("r.sql_id", &sqlIds).Error
+	return sqlIds, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetSqlManageMetricSamples(sqlId string, metricNames []string, since time.Time) ([]*SqlManageMetricSample, error) {
+	samples := []*SqlManageMetricSample{}
+	err := s.db.Table("sql_manage_metric_records AS r").
+		Select("r.sql_id, v.metric_name, v.metric_value, r.execution_count, r.record_end_at").
+		Joins("JOIN sql_manage_metric_values AS v ON v.sql_manage_metric_record_id = r.id").
+		Where("r.sql_id = ? AND r.record_end_at >= ? AND v.metric_name IN (?) AND r.deleted_at IS NULL", sqlId, since, metricNames).
+		Order("r.record_end_at ASC").
+		Scan(&samples).Error
+	return samples, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetActiveSqlManageRegressions(sqlId string) ([]*SqlManageRegression, error) {
+	regressions := []*SqlManageRegression{}
+	err := s.db.Where("sql_id = ? AND status = ?", sqlId, SqlRegressionStatusActive).Find(&regressions).Error
+	return regressions, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetActiveSqlManageRegressionsBySQLIds returns the active regressions keyed by the SQL id.
+func (s *Storage) GetActiveSqlManageRegressionsBySQLIds(sqlIds []string) (map[string][]*SqlManageRegression, error) {
+	result := make(map[string][]*SqlManageRegression, len(sqlIds))
+	if len(sqlIds) == 0 {
+		return result, nil
+	}
+	regressions := []*SqlManageRegression{}
+	err := s.db.Where("sql_id IN (?) AND status = ?", sqlIds, SqlRegressionStatusActive).Find(&regressions).Error
+	if err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+	for _, regression := range regressions {
+		result[regression.SQLID] = append(result[regression.SQLID], regression)
+	}
+	return result, nil
+}
+
+func (s *Storage) GetSqlManageRegressionList(sqlId string, limit int) ([]*SqlManageRegression, error) {
+	regressions := []*SqlManageRegression{}
+	err := s.db.Where("sql_id = ?", sqlId).Order("id DESC").Limit(limit).Find(&regressions).Error
+	return regressions, errors.New(errors.ConnectStorageError, err)
+}
+
+// SaveSqlManageRegression saves the regression and raises the priority of the SQL in SQL management.
+func (s *Storage) SaveSqlManageRegression(regression *SqlManageRegression, raisePriority bool) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if err := txDB.Save(regression).Error; err != nil {
+			return err
+		}
+		if !raisePriority {
+			return nil
+		}
+		return txDB.Model(&SQLManageRecord{}).Where("sql_id = ?", regression.SQLID).Update("priority", PriorityHigh).Error
+	})
+}
+
+// ClearSQLManagePriority clears the high priority of the SQL, the priority set by the triage rules is kept.
+// The updated_at is kept as it is the last time the SQL is seen.
+func (s *Storage) ClearSQLManagePriority(sqlId string) error {
+	err := s.db.Model(&SQLManageRecord{}).
+		Where("sql_id = ? AND priority = ?", sqlId, PriorityHigh).
+		Where("NOT EXISTS (SELECT 1 FROM sql_manage_triage_records AS t WHERE t.sql_manage_record_id = sql_manage_records.id AND t.field = ? AND t.deleted_at IS NULL)", SqlManageTriageFieldPriority).
+		UpdateColumns(map[string]interface{}{
+			"priority":   nil,
+			"updated_at": gorm.Expr("updated_at"),
+		}).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+// DeleteSqlManageMetricRecordsBefore hard deletes at most `limit` metric records which end before `before`
+// and have the metrics, together with their values. It returns the number of the records deleted.
+func (s *Storage) DeleteSqlManageMetricRecordsBefore(before time.Time, metricNames []string, limit int) (int64, error) {
+	var deleted int64
+	err := s.Tx(func(txDB *gorm.DB) error {
+		ids := []uint{}
+		err := txDB.Model(&SqlManageMetricRecord{}).Unscoped().
+			Where("record_end_at < ?", before).
+			Where("id IN (SELECT sql_manage_metric_record_id FROM sql_manage_metric_values WHERE metric_name IN (?))", metricNames).
+			Order("id ASC").Limit(limit).Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		if err := txDB.Where("sql_manage_metric_record_id IN (?)", ids).Delete(&SqlManageMetricValue{}).Error; err != nil {
+			return err
+		}
+		result := txDB.Unscoped().Where("id IN (?)", ids).Delete(&SqlManageMetricRecord{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, errors.New(errors.ConnectStorageError, err)
+}
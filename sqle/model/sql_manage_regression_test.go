@@ -0,0 +1,61 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_GetActiveSqlManageRegressionsBySQLIds(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+
+	// no query for the empty list
+	result, err := GetStorage().GetActiveSqlManageRegressionsBySQLIds(nil)
+	assert.NoError(t, err)
+	assert.Len(t, result, 0)
+
+	mock.ExpectQuery("SELECT * FROM `sql_manage_regressions` WHERE (sql_id IN (?,?) AND status = ?) AND `sql_manage_regressions`.`deleted_at` IS NULL").
+		WithArgs("sql1", "sql2", SqlRegressionStatusActive).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sql_id", "regression_type"}).
+			AddRow(1, "sql1", SqlRegressionTypeLatency).
+			AddRow(2, "sql1", SqlRegressionTypeFrequency))
+	result, err = GetStorage().GetActiveSqlManageRegressionsBySQLIds([]string{"sql1", "sql2"})
+	assert.NoError(t, err)
+	assert.Len(t, result["sql1"], 2)
+	assert.Len(t, result["sql2"], 0)
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_DeleteSqlManageMetricRecordsBefore(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `sql_manage_metric_records` WHERE record_end_at < ? AND id IN (SELECT sql_manage_metric_record_id FROM sql_manage_metric_values WHERE metric_name IN (?)) ORDER BY id ASC LIMIT 2").
+		WithArgs(before, "query_time_avg").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec("DELETE FROM `sql_manage_metric_values` WHERE sql_manage_metric_record_id IN (?,?)").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM `sql_manage_metric_records` WHERE id IN (?,?)").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	deleted, err := GetStorage().DeleteSqlManageMetricRecordsBefore(before, []string{"query_time_avg"}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,341 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	e "errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &SqlManageTriageRule{}, &SqlManageTriageRecord{})
+}
+
+const (
+	// SqlManageTriageRuleTypeMatch applies the actions to the SQL matching the conditions once, when it is audited
+	SqlManageTriageRuleTypeMatch = "match"
+	// SqlManageTriageRuleTypeAutoClose solves the open SQL matching the conditions which have not been seen for InactiveDays
+	SqlManageTriageRuleTypeAutoClose = "auto_close"
+	// SqlManageTriageRuleTypeAutoReopen reopens the closed SQL matching the conditions which reappear with a higher audit level
+	SqlManageTriageRuleTypeAutoReopen = "auto_reopen"
+)
+
+// the SQL in these status are closed, the others are open
+var SqlManageClosedStatus = []string{ProcessStatusSolved, ProcessStatusIgnored}
+
+func IsSqlManageClosedStatus(status string) bool {
+	for _, s := range SqlManageClosedStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	SqlManageMetricOperatorGreater      = ">"
+	SqlManageMetricOperatorGreaterEqual = ">="
+	SqlManageMetricOperatorLess         = "<"
+	SqlManageMetricOperatorLessEqual    = "<="
+	SqlManageMetricOperatorEqual        = "="
+)
+
+// SqlManageMetricCondition compares a numeric metric of the SQL, e.g. query_time_avg > 2
+type SqlManageMetricCondition struct {
+	Metric   string  `json:"metric"`
+	Operator string  `json:"operator"`
+	Value    float64 `json:"value"`
+}
+
+func (c *SqlManageMetricCondition) Compare(value float64) (bool, error) {
+	switch c.Operator {
+	case SqlManageMetricOperatorGreater:
+		return value > c.Value, nil
+	case SqlManageMetricOperatorGreaterEqual:
+		return value >= c.Value, nil
+	case SqlManageMetricOperatorLess:
+		return value < c.Value, nil
+	case SqlManageMetricOperatorLessEqual:
+		return value <= c.Value, nil
+	case SqlManageMetricOperatorEqual:
+		return value == c.Value, nil
+	default:
+		return false, fmt.Errorf("unknown operator %q", c.Operator)
+	}
+}
+
+type SqlManageMetricConditions []*SqlManageMetricCondition
+
+func (c *SqlManageMetricConditions) Scan(input interface{}) error {
+	if input == nil {
+		return nil
+	}
+	if data, ok := input.([]byte); !ok {
+		return fmt.Errorf("metric conditions Scan input is not bytes")
+	} else {
+		return json.Unmarshal(data, c)
+	}
+}
+
+func (c SqlManageMetricConditions) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// SqlManageTriageRule automates the triage of the SQL in the SQL management of the project, the SQL matching all
+// its conditions get its actions, an empty condition always matches. The enabled rules are evaluated by priority
+// in ascending order, so the later rules override the fields set by the former ones.
+type SqlManageTriageRule struct {
+	Model
+	ProjectId ProjectUID `json:"project_id" gorm:"index;not null;type:varchar(255)"`
+	Name      string     `json:"name" gorm:"type:varchar(255);not null"`
+	Type      string     `json:"type" gorm:"type:varchar(32);not null"`
+	Priority  int        `json:"priority" gorm:"not null;default:0"`
+	Enabled   bool       `json:"enabled"`
+
+	// Sources are the audit plan types collecting the SQL
+	Sources          Strings                   `json:"sources" gorm:"type:json"`
+	InstanceIds      Strings                   `json:"instance_ids" gorm:"type:json"`
+	SchemaNames      Strings                   `json:"schema_names" gorm:"type:json"`
+	MinAuditLevel    string                    `json:"min_audit_level" gorm:"type:varchar(32)"`
+	MetricConditions SqlManageMetricConditions `json:"metric_conditions" gorm:"type:json"`
+	// InactiveDays is the days the SQL has not been seen before it is closed by the auto_close rule
+	InactiveDays uint `json:"inactive_days"`
+
+	// Status is set by the match rule, the auto_close and auto_reopen rules set the status by their type
+	Status    string  `json:"status" gorm:"type:varchar(255)"`
+	Assignees Strings `json:"assignees" gorm:"type:json"`
+	// SQLPriority is not changed if it is nil
+	SQLPriority *string `json:"sql_priority" gorm:"type:varchar(255)"`
+	// Comment is appended to the remark of the SQL
+	Comment string `json:"comment" gorm:"type:varchar(1000)"`
+}
+
+// SqlManageTriageAttributes are the attributes of a SQL in the SQL management to match the triage rules.
+type SqlManageTriageAttributes struct {
+	Source     string
+	InstanceId string
+	SchemaName string
+	AuditLevel string
+	Metrics    map[string]interface{}
+}
+
+func NewSqlManageTriageAttributes(sql *SQLManageRecord) *SqlManageTriageAttributes {
+	attrs := &SqlManageTriageAttributes{
+		Source:     sql.Source,
+		InstanceId: sql.InstanceID,
+		SchemaName: sql.SchemaName,
+		AuditLevel: sql.AuditLevel,
+		Metrics:    map[string]interface{}{},
+	}
+	if len(sql.Info) > 0 {
+		if metrics, err := sql.Info.OriginValue(); err == nil {
+			attrs.Metrics = metrics
+		}
+	}
+	return attrs
+}
+
+// Match returns whether the attributes match all the conditions of the rule, and explains the matched conditions
+// or the first condition not matched.
+func (r *SqlManageTriageRule) Match(attrs *SqlManageTriageAttributes) (bool, []string) {
+	reasons := []string{}
+	if len(r.Sources) > 0 {
+		if !containsString(r.Sources, attrs.Source) {
+			return false, []string{fmt.Sprintf("source %q is not in %v", attrs.Source, []string(r.Sources))}
+		}
+		reasons = append(reasons, fmt.Sprintf("source %q is in %v", attrs.Source, []string(r.Sources)))
+	}
+	if len(r.InstanceIds) > 0 {
+		if !containsString(r.InstanceIds, attrs.InstanceId) {
+			return false, []string{fmt.Sprintf("instance %q is not in %v", attrs.InstanceId, []string(r.InstanceIds))}
+		}
+		reasons = append(reasons, fmt.Sprintf("instance %q is in %v", attrs.InstanceId, []string(r.InstanceIds)))
+	}
+	if len(r.SchemaNames) > 0 {
+		if !containsString(r.SchemaNames, attrs.SchemaName) {
+			return false, []string{fmt.Sprintf("schema %q is not in %v", attrs.SchemaName, []string(r.SchemaNames))}
+		}
+		reasons = append(reasons, fmt.Sprintf("schema %q is in %v", attrs.SchemaName, []string(r.SchemaNames)))
+	}
+	if r.MinAuditLevel != "" {
+		if !driverV2.RuleLevel(attrs.AuditLevel).MoreOrEqual(driverV2.RuleLevel(r.MinAuditLevel)) {
+			return false, []string{fmt.Sprintf("audit level %q is lower than %s", attrs.AuditLevel, r.MinAuditLevel)}
+		}
+		reasons = append(reasons, fmt.Sprintf("audit level %q is not lower than %s", attrs.AuditLevel, r.MinAuditLevel))
+	}
+	for _, condition := range r.MetricConditions {
+		raw, ok := attrs.Metrics[condition.Metric]
+		if !ok {
+			return false, []string{fmt.Sprintf("metric %s is not collected", condition.Metric)}
+		}
+		value, err := strconv.ParseFloat(fmt.Sprintf("%v", raw), 64)
+		if err != nil {
+			return false, []string{fmt.Sprintf("metric %s %v is not a number", condition.Metric, raw)}
+		}
+		matched, err := condition.Compare(value)
+		if err != nil {
+			return false, []string{fmt.Sprintf("metric %s: %v", condition.Metric, err)}
+		}
+		if !matched {
+			return false, []string{fmt.Sprintf("metric %s %v is not %s %v", condition.Metric, value, condition.Operator, condition.Value)}
+		}
+		reasons = append(reasons, fmt.Sprintf("metric %s %v is %s %v", condition.Metric, value, condition.Operator, condition.Value))
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "the rule has no condition")
+	}
+	return true, reasons
+}
+
+const (
+	SqlManageTriageFieldStatus    = "status"
+	SqlManageTriageFieldAssignees = "assignees"
+	SqlManageTriageFieldPriority  = "priority"
+	SqlManageTriageFieldRemark    = "remark"
+)
+
+// SqlManageTriageRecord records a field of the SQL changed by a triage rule.
+type SqlManageTriageRecord struct {
+	Model
+	ProjectId         ProjectUID `json:"project_id" gorm:"index;not null;type:varchar(255)"`
+	RuleId            uint       `json:"rule_id" gorm:"index:idx_record_rule"`
+	RuleName          string     `json:"rule_name" gorm:"type:varchar(255)"`
+	RuleType          string     `json:"rule_type" gorm:"type:varchar(32)"`
+	SQLManageRecordId uint       `json:"sql_manage_record_id" gorm:"index:idx_record_rule"`
+	SQLID             string     `json:"sql_id" gorm:"type:varchar(255)"`
+	Field             string     `json:"field" gorm:"type:varchar(32)"`
+	OldValue          string     `json:"old_value" gorm:"type:varchar(4000)"`
+	NewValue          string     `json:"new_value" gorm:"type:varchar(4000)"`
+	Reason            string     `json:"reason" gorm:"type:varchar(2000)"`
+}
+
+func (s *Storage) GetSqlManageTriageRules(projectId ProjectUID) ([]*SqlManageTriageRule, error) {
+	rules := []*SqlManageTriageRule{}
+	err := s.db.Where("project_id = ?", projectId).Order("priority ASC, id ASC").Find(&rules).Error
+	return rules, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetEnabledSqlManageTriageRulesByType returns the enabled rules of the type in all projects.
+func (s *Storage) GetEnabledSqlManageTriageRulesByType(ruleType string) ([]*SqlManageTriageRule, error) {
+	rules := []*SqlManageTriageRule{}
+	err := s.db.Where("type = ? AND enabled = ?", ruleType, true).Order("priority ASC, id ASC").Find(&rules).Error
+	return rules, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetSqlManageTriageRuleById(projectId ProjectUID, id uint) (*SqlManageTriageRule, bool, error) {
+	rule := &SqlManageTriageRule{}
+	err := s.db.Where("project_id = ? AND id = ?", projectId, id).First(rule).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return rule, true, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetSQLManageRecordProcessesByRecordIds(recordIds []uint) (map[uint]*SQLManageRecordProcess, error) {
+	processes := []*SQLManageRecordProcess{}
+	result := make(map[uint]*SQLManageRecordProcess, len(recordIds))
+	if len(recordIds) == 0 {
+		return result, nil
+	}
+	err := s.db.Where("sql_manage_record_id IN (?)", recordIds).Find(&processes).Error
+	if err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+	for _, process := range processes {
+		if process.SQLManageRecordID != nil {
+			result[*process.SQLManageRecordID] = process
+		}
+	}
+	return result, nil
+}
+
+// GetAppliedSqlManageTriageRuleIds returns the ids of the rules which have changed the SQL.
+func (s *Storage) GetAppliedSqlManageTriageRuleIds(recordIds []uint) (map[uint]map[uint]struct{}, error) {
+	type applied struct {
+		SQLManageRecordId uint
+		RuleId            uint
+	}
+	rows := []*applied{}
+	result := make(map[uint]map[uint]struct{}, len(recordIds))
+	if len(recordIds) == 0 {
+		return result, nil
+	}
+	err := s.db.Model(&SqlManageTriageRecord{}).Select("DISTINCT sql_manage_record_id, rule_id").
+		Where("sql_manage_record_id IN (?)", recordIds).Scan(&rows).Error
+	if err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+	for _, row := range rows {
+		if result[row.SQLManageRecordId] == nil {
+			result[row.SQLManageRecordId] = map[uint]struct{}{}
+		}
+		result[row.SQLManageRecordId][row.RuleId] = struct{}{}
+	}
+	return result, nil
+}
+
+// GetInactiveOpenSQLManageRecords returns the open SQL of the project which have not been seen since `before`,
+// the SQL are ordered by id and start after `afterId`.
+func (s *Storage) GetInactiveOpenSQLManageRecords(projectId ProjectUID, before time.Time, afterId uint, limit int) ([]*SQLManageRecord, error) {
+	records := []*SQLManageRecord{}
+	err := s.db.Model(&SQLManageRecord{}).
+		Joins("JOIN sql_manage_record_processes smrp ON sql_manage_records.id = smrp.sql_manage_record_id").
+		Where("sql_manage_records.project_id = ? AND sql_manage_records.updated_at < ? AND sql_manage_records.id > ?", projectId, before, afterId).
+		Where("smrp.status NOT IN (?)", SqlManageClosedStatus).
+		Order("sql_manage_records.id ASC").Limit(limit).
+		Find(&records).Error
+	return records, errors.New(errors.ConnectStorageError, err)
+}
+
+// SaveSqlManageTriage saves the fields of the SQL changed by the triage rules with the records of the changes,
+// the SQL changed is not regarded as seen again.
+func (s *Storage) SaveSqlManageTriage(recordId uint, processAttrs, recordAttrs map[string]interface{}, records []*SqlManageTriageRecord) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if len(processAttrs) > 0 {
+			if err := txDB.Model(&SQLManageRecordProcess{}).Where("sql_manage_record_id = ?", recordId).Updates(processAttrs).Error; err != nil {
+				return err
+			}
+		}
+		if len(recordAttrs) > 0 {
+			recordAttrs["updated_at"] = gorm.Expr("updated_at")
+			if err := txDB.Model(&SQLManageRecord{}).Where("id = ?", recordId).UpdateColumns(recordAttrs).Error; err != nil {
+				return err
+			}
+		}
+		if len(records) > 0 {
+			return txDB.Create(records).Error
+		}
+		return nil
+	})
+}
+
+type SqlManageTriageRecordFilter struct {
+	ProjectId         ProjectUID
+	SQLManageRecordId uint
+	RuleId            uint
+	Limit             uint32
+	Offset            uint32
+}
+
+func (s *Storage) GetSqlManageTriageRecords(filter *SqlManageTriageRecordFilter) ([]*SqlManageTriageRecord, int64, error) {
+	records := []*SqlManageTriageRecord{}
+	var count int64
+	query := s.db.Model(&SqlManageTriageRecord{}).Where("project_id = ?", filter.ProjectId)
+	if filter.SQLManageRecordId != 0 {
+		query = query.Where("sql_manage_record_id = ?", filter.SQLManageRecordId)
+	}
+	if filter.RuleId != 0 {
+		query = query.Where("rule_id = ?", filter.RuleId)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	err := query.Order("id DESC").Offset(int(filter.Offset)).Limit(int(filter.Limit)).Find(&records).Error
+	return records, count, errors.New(errors.ConnectStorageError, err)
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqlManageTriageRuleMatch(t *testing.T) {
+	attrs := NewSqlManageTriageAttributes(&SQLManageRecord{
+		Source:     "mysql_slow_log",
+		InstanceID: "1",
+		SchemaName: "orders",
+		AuditLevel: "warn",
+		Info:       JSON(`{"query_time_avg": 2.5, "counter": "10", "db_user": "root"}`),
+	})
+
+	cases := []struct {
+		rule    *SqlManageTriageRule
+		matched bool
+	}{
+		{&SqlManageTriageRule{}, true},
+		{&SqlManageTriageRule{Sources: Strings{"mysql_slow_log"}}, true},
+		{&SqlManageTriageRule{Sources: Strings{"mysql_processlist"}}, false},
+		{&SqlManageTriageRule{InstanceIds: Strings{"2"}}, false},
+		{&SqlManageTriageRule{SchemaNames: Strings{"users", "orders"}}, true},
+		{&SqlManageTriageRule{SchemaNames: Strings{"users"}}, false},
+		{&SqlManageTriageRule{MinAuditLevel: "warn"}, true},
+		{&SqlManageTriageRule{MinAuditLevel: "error"}, false},
+		{&SqlManageTriageRule{MetricConditions: SqlManageMetricConditions{{Metric: "query_time_avg", Operator: ">", Value: 2}}}, true},
+		{&SqlManageTriageRule{MetricConditions: SqlManageMetricConditions{{Metric: "query_time_avg", Operator: "<=", Value: 2}}}, false},
+		{&SqlManageTriageRule{MetricConditions: SqlManageMetricConditions{{Metric: "counter", Operator: ">=", Value: 10}}}, true},
+		{&SqlManageTriageRule{MetricConditions: SqlManageMetricConditions{{Metric: "row_examined_avg", Operator: ">", Value: 0}}}, false},
+		{&SqlManageTriageRule{MetricConditions: SqlManageMetricConditions{{Metric: "db_user", Operator: "=", Value: 0}}}, false},
+		{&SqlManageTriageRule{
+			Sources:          Strings{"mysql_slow_log"},
+			SchemaNames:      Strings{"orders"},
+			MetricConditions: SqlManageMetricConditions{{Metric: "query_time_avg", Operator: ">", Value: 2}},
+		}, true},
+	}
+	for i, c := range cases {
+		matched, reasons := c.rule.Match(attrs)
+		assert.Equal(t, c.matched, matched, "case %d: %v", i, reasons)
+		assert.NotEmpty(t, reasons)
+	}
+
+	// the SQL without metrics
+	matched, _ := (&SqlManageTriageRule{MetricConditions: SqlManageMetricConditions{{Metric: "query_time_avg", Operator: ">", Value: 2}}}).
+		Match(NewSqlManageTriageAttributes(&SQLManageRecord{}))
+	assert.False(t, matched)
+}
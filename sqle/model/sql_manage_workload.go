@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+)
+
+// SqlManageWorkloadMetric is the execution count and the average value of a metric of the SQL in a time range.
+type SqlManageWorkloadMetric struct {
+	SQLID          string
+	ExecutionCount int64
+	// MetricAvg is the average weighted by the execution counts of the records
+	MetricAvg float64
+}
+
+// GetSQLManageRecordsByInstanceSchema returns a page of the SQL in SQL management of the instance and the schema whose
+// id is greater than lastId, ordered by id. Only the columns of the workload are loaded.
+func (s *Storage) GetSQLManageRecordsByInstanceSchema(projectId, instanceId, schemaName string, lastId uint, limit int) ([]*SQLManageRecord, error) {
+	sqls := []*SQLManageRecord{}
+	err := s.db.Select("id, sql_id, sql_text, info").
+		Where("project_id = ? AND instance_id = ? AND schema_name = ? AND id > ?", projectId, instanceId, schemaName, lastId).
+		Order("id").Limit(limit).
+		Find(&sqls).Error
+	return sqls, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetSqlManageWorkloadMetrics returns the workload metrics of the SQL of the instance and the schema recorded after
+// `since`, the SQL without records of the metric are not returned.
+func (s *Storage) GetSqlManageWorkloadMetrics(projectId, instanceId, schemaName, metricName string, since time.Time) (map[string]*SqlManageWorkloadMetric, error) {
+	metrics := []*SqlManageWorkloadMetric{}
+	err := s.db.Table("sql_manage_metric_records AS r").
+		Select("r.sql_id, SUM(r.execution_count) AS execution_count, "+
+			"SUM(v.metric_value * r.execution_count) / SUM(r.execution_count) AS metric_avg").
+		Joins("JOIN sql_manage_metric_values AS v ON v.sql_manage_metric_record_id = r.id").
+		Joins("JOIN sql_manage_records AS s ON s.sql_id = r.sql_id").
+		Where("s.project_id = ? AND s.instance_id = ? AND s.schema_name = ? AND s.deleted_at IS NULL", projectId, instanceId, schemaName).
+		Where("r.record_end_at >= ? AND v.metric_name = ? AND r.deleted_at IS NULL", since, metricName).
+		Group("r.sql_id").
+		Scan(&metrics).Error
+	if err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+	res := make(map[string]*SqlManageWorkloadMetric, len(metrics))
+	for _, metric := range metrics {
+		res[metric.SQLID] = metric
+	}
+	return res, nil
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_GetSqlManageWorkloadMetrics(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+	since := time.Now()
+	mock.ExpectQuery("SELECT r.sql_id, SUM(r.execution_count) AS execution_count, SUM(v.metric_value * r.execution_count) / SUM(r.execution_count) AS metric_avg "+
+		"FROM sql_manage_metric_records AS r JOIN sql_manage_metric_values AS v ON v.sql_manage_metric_record_id = r.id JOIN sql_manage_records AS s ON s.sql_id = r.sql_id "+
+		"WHERE (s.project_id = ? AND s.instance_id = ? AND s.schema_name = ? AND s.deleted_at IS NULL) AND (r.record_end_at >= ? AND v.metric_name = ? AND r.deleted_at IS NULL) GROUP BY `r`.`sql_id`").
+		WithArgs("p1", "1", "db1", since, "query_time_avg").
+		WillReturnRows(sqlmock.NewRows([]string{"sql_id", "execution_count", "metric_avg"}).AddRow("s1", 10, 1.5))
+	mock.ExpectClose()
+	metrics, err := GetStorage().GetSqlManageWorkloadMetrics("p1", "1", "db1", "query_time_avg", since)
+	assert.NoError(t, err)
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, int64(10), metrics["s1"].ExecutionCount)
+	assert.Equal(t, 1.5, metrics["s1"].MetricAvg)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_GetSQLManageRecordsByInstanceSchema(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+	mock.ExpectQuery("SELECT id, sql_id, sql_text, info FROM `sql_manage_records` WHERE (project_id = ? AND instance_id = ? AND schema_name = ? AND id > ?) AND `sql_manage_records`.`deleted_at` IS NULL ORDER BY id LIMIT 2").
+		WithArgs("p1", "1", "db1", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sql_id"}).AddRow(4, "s4").AddRow(5, "s5"))
+	mock.ExpectClose()
+	records, err := GetStorage().GetSQLManageRecordsByInstanceSchema("p1", "1", "db1", 3, 2)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "s5", records[1].SQLID)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	e "errors"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &SQLQueryMaskingRule{}, &SQLQueryRecord{})
+}
+
+const (
+	MaskingTypePhone  = "phone"
+	MaskingTypeIDCard = "id_card"
+	MaskingTypeEmail  = "email"
+	MaskingTypeRegex  = "regex"
+)
+
+// SQLQueryMaskingRule masks the values of the columns matching ColumnPattern in the results of the query console.
+type SQLQueryMaskingRule struct {
+	Model
+	ProjectId string `json:"project_id" gorm:"type:varchar(255);not null;index"`
+	Name      string `json:"name" gorm:"type:varchar(255);not null"`
+	Enabled   bool   `json:"enabled"`
+	// ColumnPattern is a case-insensitive regular expression matching the column names
+	ColumnPattern string `json:"column_pattern" gorm:"type:varchar(512);not null"`
+	MaskingType   string `json:"masking_type" gorm:"type:varchar(32);not null"`
+	// ValuePattern and Replacement are only used by the regex type, the matched parts of the value are replaced.
+	ValuePattern string `json:"value_pattern" gorm:"type:varchar(512)"`
+	Replacement  string `json:"replacement" gorm:"type:varchar(255)"`
+}
+
+func (s *Storage) GetSQLQueryMaskingRules(projectId string) ([]*SQLQueryMaskingRule, error) {
+	rules := []*SQLQueryMaskingRule{}
+	err := s.db.Where("project_id = ?", projectId).Order("id ASC").Find(&rules).Error
+	return rules, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetSQLQueryMaskingRuleById(projectId string, id uint) (*SQLQueryMaskingRule, bool, error) {
+	rule := &SQLQueryMaskingRule{}
+	err := s.db.Where("project_id = ? AND id = ?", projectId, id).First(rule).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return rule, true, errors.New(errors.ConnectStorageError, err)
+}
+
+const (
+	SQLQueryStatusSucceeded = "succeeded"
+	SQLQueryStatusRejected  = "rejected"
+	SQLQueryStatusFailed    = "failed"
+)
+
+// SQLQueryRecord logs a statement run in the query console, including the statements rejected by the audit.
+type SQLQueryRecord struct {
+	Model
+	ProjectId  string `json:"project_id" gorm:"type:varchar(255);not null;index"`
+	InstanceId string `json:"instance_id" gorm:"type:varchar(255);not null;index"`
+	UserId     string `json:"user_id" gorm:"type:varchar(255);not null;index"`
+	Schema     string `json:"schema" gorm:"type:varchar(255)"`
+	SQL        string `json:"sql" gorm:"type:text;not null"`
+	AuditLevel string `json:"audit_level" gorm:"type:varchar(32)"`
+	Status     string `json:"status" gorm:"type:varchar(32);not null"`
+	Error      string `json:"error" gorm:"type:text"`
+	RowCount   int    `json:"row_count"`
+	// Truncated is true if the result has more rows than the limit of the instance
+	Truncated     bool       `json:"truncated"`
+	MaskedColumns Strings    `json:"masked_columns" gorm:"type:json"`
+	StartAt       *time.Time `json:"start_at"`
+	DurationMs    int64      `json:"duration_ms"`
+}
+
+func (s *Storage) GetSQLQueryRecordList(projectId, instanceId, userId, status string, pageIndex, pageSize uint32) ([]*SQLQueryRecord, uint64, error) {
+	var count int64
+	records := []*SQLQueryRecord{}
+	query := s.db.Model(&SQLQueryRecord{}).Where("project_id = ?", projectId)
+	if instanceId != "" {
+		query = query.Where("instance_id = ?", instanceId)
+	}
+	if userId != "" {
+		query = query.Where("user_id = ?", userId)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Count(&count).Error
+	if err != nil {
+		return records, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	if count == 0 {
+		return records, 0, nil
+	}
+	err = query.Offset(int((pageIndex - 1) * pageSize)).Limit(int(pageSize)).Order("id desc").Find(&records).Error
+	return records, uint64(count), errors.New(errors.ConnectStorageError, err)
+}
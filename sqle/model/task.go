@@ -41,6 +41,7 @@ const (
 )
 
 const TaskExecResultOK = "OK"
+
 // TaskExecResultRollback 同事务内非出错 SQL 的固定说明（AC-009 / overview §15.2）
 const TaskExecResultRollback = "同事务内其它 SQL 已随事务回滚"
 
@@ -49,35 +50,37 @@ const ExecModeSqls = "sqls"
 
 type Task struct {
 	Model
-	InstanceId           uint64  `json:"instance_id"`
-	Schema               string  `json:"instance_schema" gorm:"column:instance_schema;type:varchar(255)" example:"db1"`
-	PassRate             float64 `json:"pass_rate"`
-	Score                int32   `json:"score"`
-	AuditLevel           string  `json:"audit_level" gorm:"type:varchar(255)"`
-	SQLSource            string  `json:"sql_source" gorm:"column:sql_source;type:varchar(255)"`
-	DBType               string  `json:"db_type" gorm:"default:'mysql';type:varchar(255)" example:"mysql"`
-	Status               string  `json:"status" gorm:"default:\"initialized\";type:varchar(255)"`
-	GroupId              uint    `json:"group_id" gorm:"column:group_id"`
+	InstanceId uint64  `json:"instance_id"`
+	Schema     string  `json:"instance_schema" gorm:"column:instance_schema;type:varchar(255)" example:"db1"`
+	PassRate   float64 `json:"pass_rate"`
+	Score      int32   `json:"score"`
+	// ScoreBreakdown is how the score is calculated, it is nil for the tasks audited before it is introduced
+	ScoreBreakdown       *ScoreBreakdown `json:"score_breakdown" gorm:"type:json"`
+	AuditLevel           string          `json:"audit_level" gorm:"type:varchar(255)"`
+	SQLSource            string          `json:"sql_source" gorm:"column:sql_source;type:varchar(255)"`
+	DBType               string          `json:"db_type" gorm:"default:'mysql';type:varchar(255)" example:"mysql"`
+	Status               string          `json:"status" gorm:"default:\"initialized\";type:varchar(255)"`
+	GroupId              uint            `json:"group_id" gorm:"column:group_id"`
 	CreateUserId         uint64
 	RuleTemplateID       uint `json:"rule_template_id" gorm:"column:rule_template_id"`
 	ExecStartAt          *time.Time
 	ExecEndAt            *time.Time
-	ExecMode             string         `json:"exec_mode" gorm:"default:'sqls';type:varchar(255)" example:"sqls"`
-	EnableBackup         bool           `gorm:"column:enable_backup"`
-	BackupMaxRows        uint64         `json:"backup_max_rows" gorm:"column:backup_max_rows;not null;default:0"`
-	InstanceEnableBackup bool           `gorm:"column:instance_enable_backup"` // 用于记录创建task时，instance备份开关的状态
-	FileOrderMethod      string         `json:"file_order_method" gorm:"column:file_order_method;type:varchar(255)"`
+	ExecMode             string `json:"exec_mode" gorm:"default:'sqls';type:varchar(255)" example:"sqls"`
+	EnableBackup         bool   `gorm:"column:enable_backup"`
+	BackupMaxRows        uint64 `json:"backup_max_rows" gorm:"column:backup_max_rows;not null;default:0"`
+	InstanceEnableBackup bool   `gorm:"column:instance_enable_backup"` // 用于记录创建task时，instance备份开关的状态
+	FileOrderMethod      string `json:"file_order_method" gorm:"column:file_order_method;type:varchar(255)"`
 	// ExecFail* 上线失败摘要（Phase-1/3）；可空，不做历史回填
-	ExecFailStage     string `json:"exec_fail_stage" gorm:"column:exec_fail_stage;type:varchar(64);default:''"`
-	ExecFailReason    string `json:"exec_fail_reason" gorm:"column:exec_fail_reason;type:text"`
-	ExecFailSQLCount  int    `json:"exec_fail_sql_count" gorm:"column:exec_fail_sql_count;default:0"`
-	ExecFailSQLNumber uint   `json:"exec_fail_sql_number" gorm:"column:exec_fail_sql_number;default:0"` // 出错 SQL 工单内序号（AC-009）
-	ExecFailSQLID     uint   `json:"exec_fail_sql_id" gorm:"column:exec_fail_sql_id;default:0"`         // 出错 SQL ID（可选双写）
-	Instance         *Instance      `json:"-" gorm:"-"`
-	RuleTemplate     *RuleTemplate  `json:"-" gorm:"foreignkey:RuleTemplateID"`
-	ExecuteSQLs      []*ExecuteSQL  `json:"-" gorm:"foreignkey:TaskId"`
-	RollbackSQLs     []*RollbackSQL `json:"-" gorm:"foreignkey:TaskId"`
-	AuditFiles       []*AuditFile   `json:"-" gorm:"foreignkey:TaskId"`
+	ExecFailStage     string         `json:"exec_fail_stage" gorm:"column:exec_fail_stage;type:varchar(64);default:''"`
+	ExecFailReason    string         `json:"exec_fail_reason" gorm:"column:exec_fail_reason;type:text"`
+	ExecFailSQLCount  int            `json:"exec_fail_sql_count" gorm:"column:exec_fail_sql_count;default:0"`
+	ExecFailSQLNumber uint           `json:"exec_fail_sql_number" gorm:"column:exec_fail_sql_number;default:0"` // 出错 SQL 工单内序号（AC-009）
+	ExecFailSQLID     uint           `json:"exec_fail_sql_id" gorm:"column:exec_fail_sql_id;default:0"`         // 出错 SQL ID（可选双写）
+	Instance          *Instance      `json:"-" gorm:"-"`
+	RuleTemplate      *RuleTemplate  `json:"-" gorm:"foreignkey:RuleTemplateID"`
+	ExecuteSQLs       []*ExecuteSQL  `json:"-" gorm:"foreignkey:TaskId"`
+	RollbackSQLs      []*RollbackSQL `json:"-" gorm:"foreignkey:TaskId"`
+	AuditFiles        []*AuditFile   `json:"-" gorm:"foreignkey:TaskId"`
 }
 
 func (t *Task) RuleTemplateName() string {
@@ -131,16 +134,22 @@ const (
 	SQLExecuteStatusTerminateFailed  = "terminate_failed"
 	SQLExecuteStatusExecuteRollback  = "execute_rollback" // 执行回滚
 	SQLExecuteStatusNotExecuted      = "not_executed"     // 前序失败未执行到
+	SQLExecuteStatusPaused           = "paused"           // 分批执行被暂停，可从最后完成的主键范围继续
+)
+
+const (
+	SQLExecModeNormal  = ""
+	SQLExecModeChunked = "chunked" // 按主键范围分批执行 UPDATE/DELETE
 )
 
 // 上线失败阶段（后端权威值，对齐架构 overview §3.1）
 const (
-	OnlineFailStageSQLBackup          = "sql_backup"
-	OnlineFailStageSQLExecute         = "sql_execute"
-	OnlineFailStageDatasourceConnect  = "datasource_connect"
-	OnlineFailStagePreCheck           = "pre_check"
-	OnlineFailStageTerminate          = "terminate"
-	OnlineFailStageUnknown            = "unknown"
+	OnlineFailStageSQLBackup         = "sql_backup"
+	OnlineFailStageSQLExecute        = "sql_execute"
+	OnlineFailStageDatasourceConnect = "datasource_connect"
+	OnlineFailStagePreCheck          = "pre_check"
+	OnlineFailStageTerminate         = "terminate"
+	OnlineFailStageUnknown           = "unknown"
 )
 
 const (
@@ -201,6 +210,8 @@ func (s *BaseSQL) GetExecStatusDesc(ctx context.Context) string {
 		return locale.Bundle.LocalizeMsgByCtx(ctx, locale.SQLExecuteStatusManuallyExecuted)
 	case SQLExecuteStatusNotExecuted:
 		return locale.Bundle.LocalizeMsgByCtx(ctx, locale.SQLExecuteStatusNotExecuted)
+	case SQLExecuteStatusPaused:
+		return locale.Bundle.LocalizeMsgByCtx(ctx, locale.SQLExecuteStatusPaused)
 	default:
 		return locale.Bundle.LocalizeMsgByCtx(ctx, locale.SQLExecuteStatusUnknown)
 	}
@@ -233,8 +244,10 @@ func (i *I18nAuditResultInfo) GetAuditResultInfoByLangTag(lang language.Tag) *Au
 		return &AuditResultInfo{}
 	}
 
-	if info, ok := (*i)[lang]; ok {
-		return &info
+	for _, tag := range driverV2.FallbackLangTags(lang) {
+		if info, ok := (*i)[tag]; ok {
+			return &info
+		}
 	}
 
 	info := (*i)[i18nPkg.DefaultLang]
@@ -380,7 +393,9 @@ type ExecuteSQL struct {
 	// AuditLevel has four level: error, warn, notice, normal.
 	AuditLevel string `json:"audit_level" gorm:"type:varchar(255)"`
 	// FailStage 上线失败阶段；失败/未执行时写入，供详情刷新再读
-	FailStage  string      `json:"fail_stage" gorm:"column:fail_stage;type:varchar(64);default:''"`
+	FailStage string `json:"fail_stage" gorm:"column:fail_stage;type:varchar(64);default:''"`
+	// ExecMode 为 chunked 时按主键范围分批执行，进度见 ChunkedExecution
+	ExecMode   string      `json:"exec_mode" gorm:"column:exec_mode;type:varchar(32);default:''"`
 	BackupTask *BackupTask `json:"-" gorm:"foreignkey:execute_sql_id"`
 }
 
@@ -0,0 +1,130 @@
+package model
+
+import (
+	e "errors"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &UserDelegation{})
+}
+
+// UserDelegation lets the delegate operate the workflow steps assigned to the delegator between StartAt and EndAt,
+// e.g. when the delegator is out of office. The notifications to the delegator are redirected to the delegate
+// while the delegation is active.
+type UserDelegation struct {
+	Model
+	DelegatorId string `json:"delegator_id" gorm:"type:varchar(255);not null;index"`
+	DelegateId  string `json:"delegate_id" gorm:"type:varchar(255);not null;index"`
+	// ProjectId limits the delegation to a project, empty means all projects.
+	ProjectId string `json:"project_id" gorm:"type:varchar(255)"`
+	// InstanceId limits the delegation to the workflows only on the instance, empty means all instances.
+	InstanceId string    `json:"instance_id" gorm:"type:varchar(255)"`
+	StartAt    time.Time `json:"start_at" gorm:"not null"`
+	EndAt      time.Time `json:"end_at" gorm:"not null;index"`
+	Reason     string    `json:"reason" gorm:"type:varchar(255)"`
+}
+
+func (d *UserDelegation) IsActive(at time.Time) bool {
+	return !at.Before(d.StartAt) && at.Before(d.EndAt)
+}
+
+// Covers returns whether the delegation applies to a workflow of the project on the instances.
+func (d *UserDelegation) Covers(projectId string, instanceIds []uint64) bool {
+	if d.ProjectId != "" && d.ProjectId != projectId {
+		return false
+	}
+	if d.InstanceId == "" {
+		return true
+	}
+	if len(instanceIds) == 0 {
+		return false
+	}
+	for _, id := range instanceIds {
+		if strconv.FormatUint(id, 10) != d.InstanceId {
+			return false
+		}
+	}
+	return true
+}
+
+// GetActiveUserDelegations gets the delegations of the delegators active at the time.
+func (s *Storage) GetActiveUserDelegations(delegatorIds []string, at time.Time) ([]*UserDelegation, error) {
+	delegations := []*UserDelegation{}
+	if len(delegatorIds) == 0 {
+		return delegations, nil
+	}
+	err := s.db.Where("delegator_id IN (?) AND start_at <= ? AND end_at > ?", delegatorIds, at, at).
+		Order("id ASC").Find(&delegations).Error
+	return delegations, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetUserDelegationById(id uint) (*UserDelegation, bool, error) {
+	delegation := &UserDelegation{}
+	err := s.db.Where("id = ?", id).First(delegation).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return delegation, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetUserDelegationList gets the delegations the user delegates or is delegated, the expired delegations are
+// included if withExpired is true.
+func (s *Storage) GetUserDelegationList(userId string, withExpired bool, pageIndex, pageSize uint32) ([]*UserDelegation, uint64, error) {
+	var count int64
+	delegations := []*UserDelegation{}
+	query := s.db.Model(&UserDelegation{}).Where("delegator_id = ? OR delegate_id = ?", userId, userId)
+	if !withExpired {
+		query = query.Where("end_at > ?", time.Now())
+	}
+	err := query.Count(&count).Error
+	if err != nil {
+		return delegations, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	if count == 0 {
+		return delegations, 0, nil
+	}
+	err = query.Offset(int((pageIndex - 1) * pageSize)).Limit(int(pageSize)).Order("start_at desc").Find(&delegations).Error
+	return delegations, uint64(count), errors.New(errors.ConnectStorageError, err)
+}
+
+// IsUserDelegationOverlapped checks whether the delegator already delegates the same scope during the period.
+func (s *Storage) IsUserDelegationOverlapped(d *UserDelegation) (bool, error) {
+	var count int64
+	err := s.db.Model(&UserDelegation{}).
+		Where("delegator_id = ? AND project_id = ? AND instance_id = ?", d.DelegatorId, d.ProjectId, d.InstanceId).
+		Where("start_at < ? AND end_at > ?", d.EndAt, d.StartAt).
+		Where("id <> ?", d.ID).
+		Count(&count).Error
+	return count > 0, errors.New(errors.ConnectStorageError, err)
+}
+
+// RedirectToDelegates replaces the users delegating a workflow of the project on the instances with their
+// delegates, the other users are kept.
+func RedirectToDelegates(delegations []*UserDelegation, projectId string, instanceIds []uint64, userIds []string) []string {
+	delegates := map[string][]string{}
+	for _, d := range delegations {
+		if d.Covers(projectId, instanceIds) {
+			delegates[d.DelegatorId] = append(delegates[d.DelegatorId], d.DelegateId)
+		}
+	}
+	redirected := make([]string, 0, len(userIds))
+	seen := map[string]bool{}
+	for _, id := range userIds {
+		targets, ok := delegates[id]
+		if !ok {
+			targets = []string{id}
+		}
+		for _, target := range targets {
+			if !seen[target] {
+				seen[target] = true
+				redirected = append(redirected, target)
+			}
+		}
+	}
+	return redirected
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserDelegation_IsActive(t *testing.T) {
+	now := time.Now()
+	d := &UserDelegation{StartAt: now.Add(-time.Hour), EndAt: now.Add(time.Hour)}
+	assert.True(t, d.IsActive(now))
+	assert.True(t, d.IsActive(d.StartAt))
+	assert.False(t, d.IsActive(d.EndAt))
+	assert.False(t, d.IsActive(now.Add(-2*time.Hour)))
+}
+
+func TestUserDelegation_Covers(t *testing.T) {
+	all := &UserDelegation{}
+	assert.True(t, all.Covers("p1", nil))
+	assert.True(t, all.Covers("p2", []uint64{1, 2}))
+
+	project := &UserDelegation{ProjectId: "p1"}
+	assert.True(t, project.Covers("p1", []uint64{1}))
+	assert.False(t, project.Covers("p2", []uint64{1}))
+
+	instance := &UserDelegation{ProjectId: "p1", InstanceId: "1"}
+	assert.True(t, instance.Covers("p1", []uint64{1}))
+	assert.True(t, instance.Covers("p1", []uint64{1, 1}))
+	assert.False(t, instance.Covers("p1", []uint64{1, 2}))
+	assert.False(t, instance.Covers("p1", nil))
+	assert.False(t, instance.Covers("p2", []uint64{1}))
+}
+
+func TestRedirectToDelegates(t *testing.T) {
+	delegations := []*UserDelegation{
+		{DelegatorId: "1", DelegateId: "3"},
+		{DelegatorId: "1", DelegateId: "4", ProjectId: "p2"},
+		{DelegatorId: "2", DelegateId: "3", ProjectId: "p1", InstanceId: "10"},
+	}
+	assert.Equal(t, []string{"3", "2"}, RedirectToDelegates(delegations, "p1", []uint64{11}, []string{"1", "2"}))
+	assert.Equal(t, []string{"3"}, RedirectToDelegates(delegations, "p1", []uint64{10}, []string{"1", "2"}))
+	assert.Equal(t, []string{"3", "4", "2"}, RedirectToDelegates(delegations, "p2", nil, []string{"1", "2"}))
+	assert.Equal(t, []string{"5"}, RedirectToDelegates(delegations, "p1", nil, []string{"5"}))
+}
@@ -27,6 +27,15 @@ type WorkflowTemplate struct {
 	AllowSubmitWhenLessAuditLevel string     `gorm:"type:varchar(255)"`
 	IsDefault                     bool       `json:"is_default" gorm:"column:is_default; not null; default:false"`
 
+	// Separation of duties of the workflows, see server.CheckWorkflowApproveDuty and server.CheckWorkflowExecuteDuty.
+	ForbidCreatorApprove  bool `gorm:"column:forbid_creator_approve; not null; default:false"`
+	ForbidApproverExecute bool `gorm:"column:forbid_approver_execute; not null; default:false"`
+	// MinDistinctParticipants is the least number of the distinct creator, approvers and executor of the workflows
+	// whose audit level is not lower than MinParticipantsAuditLevel, 0 means disabled. Empty
+	// MinParticipantsAuditLevel applies to all workflows.
+	MinDistinctParticipants   uint   `gorm:"column:min_distinct_participants; not null; default:0"`
+	MinParticipantsAuditLevel string `gorm:"column:min_participants_audit_level; type:varchar(32)"`
+
 	Steps []*WorkflowStepTemplate `json:"-" gorm:"foreignkey:WorkflowTemplateId"`
 	// Instances []*Instance             `gorm:"foreignkey:WorkflowTemplateId"`
 }
@@ -63,6 +72,14 @@ type WorkflowStepTemplate struct {
 	ExecuteByAuthorized  sql.NullBool `gorm:"column:execute_by_authorized"`
 
 	Users string `gorm:"type:varchar(255)"` // `gorm:"many2many:workflow_step_template_user"` // dms-todo: 调整存储格式
+
+	// SLA of the step, see WorkflowSLAJob. 0 means disabled.
+	SLARemindIntervalMinute uint `gorm:"column:sla_remind_interval_minute; default:0"`
+	SLAEscalateAfterMinute  uint `gorm:"column:sla_escalate_after_minute; default:0"`
+	// SLAEscalateUsers are the user ids separated by ",", the users of SLAEscalateMemberGroupUid are also escalated to.
+	SLAEscalateUsers          string `gorm:"column:sla_escalate_users; type:varchar(255)"`
+	SLAEscalateMemberGroupUid string `gorm:"column:sla_escalate_member_group_uid; type:varchar(255)"`
+	SLAEscalateMode           string `gorm:"column:sla_escalate_mode; type:varchar(32)"`
 }
 
 func DefaultWorkflowTemplate(projectId string) *WorkflowTemplate {
@@ -196,6 +213,16 @@ func (s *Storage) GetWorkflowTemplateByProjectIdAndId(projectId ProjectUID, temp
 	return workflowTemplate, true, nil
 }
 
+func (s *Storage) UpdateWorkflowTemplateDutyPolicy(template *WorkflowTemplate) error {
+	err := s.db.Model(&WorkflowTemplate{}).Where("id = ?", template.ID).Updates(map[string]interface{}{
+		"forbid_creator_approve":       template.ForbidCreatorApprove,
+		"forbid_approver_execute":      template.ForbidApproverExecute,
+		"min_distinct_participants":    template.MinDistinctParticipants,
+		"min_participants_audit_level": template.MinParticipantsAuditLevel,
+	}).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) IsWorkflowTemplateNameExist(projectId ProjectUID, workflowType, name string, excludeID uint) (bool, error) {
 	var count int64
 	db := s.db.Model(&WorkflowTemplate{}).Where("project_id = ? AND workflow_type = ? AND name = ?", projectId, workflowType, name)
@@ -325,8 +352,10 @@ func (s *Storage) SaveWorkflowTemplate(template *WorkflowTemplate) error {
 }
 
 func saveWorkflowTemplate(template *WorkflowTemplate, tx *sql.Tx) (templateId int64, err error) {
-	result, err := tx.Exec("INSERT INTO workflow_templates (name, `desc`, `allow_submit_when_less_audit_level`, `project_id`, `workflow_type`, `is_default`) values (?, ?, ?, ?, ?, ?)",
-		template.Name, template.Desc, template.AllowSubmitWhenLessAuditLevel, template.ProjectId, template.WorkflowType, template.IsDefault)
+	result, err := tx.Exec("INSERT INTO workflow_templates (name, `desc`, `allow_submit_when_less_audit_level`, `project_id`, `workflow_type`, `is_default`, "+
+		"`forbid_creator_approve`, `forbid_approver_execute`, `min_distinct_participants`, `min_participants_audit_level`) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		template.Name, template.Desc, template.AllowSubmitWhenLessAuditLevel, template.ProjectId, template.WorkflowType, template.IsDefault,
+		template.ForbidCreatorApprove, template.ForbidApproverExecute, template.MinDistinctParticipants, template.MinParticipantsAuditLevel)
 	if err != nil {
 		return 0, err
 	}
@@ -336,8 +365,7 @@ func saveWorkflowTemplate(template *WorkflowTemplate, tx *sql.Tx) (templateId in
 	}
 	template.ID = uint(templateId)
 	for _, step := range template.Steps {
-		result, err = tx.Exec("INSERT INTO workflow_step_templates (step_number, workflow_template_id, type, users, `desc`, approved_by_authorized,execute_by_authorized) values (?,?,?,?,?,?,?)",
-			step.Number, templateId, step.Typ, step.Users, step.Desc, step.ApprovedByAuthorized, step.ExecuteByAuthorized)
+		result, err = insertWorkflowStepTemplate(tx, templateId, step)
 		if err != nil {
 			return 0, err
 		}
@@ -350,6 +378,13 @@ func saveWorkflowTemplate(template *WorkflowTemplate, tx *sql.Tx) (templateId in
 	return templateId, nil
 }
 
+func insertWorkflowStepTemplate(tx *sql.Tx, templateId int64, step *WorkflowStepTemplate) (sql.Result, error) {
+	return tx.Exec("INSERT INTO workflow_step_templates (step_number, workflow_template_id, type, users, `desc`, approved_by_authorized, execute_by_authorized, "+
+		"sla_remind_interval_minute, sla_escalate_after_minute, sla_escalate_users, sla_escalate_member_group_uid, sla_escalate_mode) values (?,?,?,?,?,?,?,?,?,?,?,?)",
+		step.Number, templateId, step.Typ, step.Users, step.Desc, step.ApprovedByAuthorized, step.ExecuteByAuthorized,
+		step.SLARemindIntervalMinute, step.SLAEscalateAfterMinute, step.SLAEscalateUsers, step.SLAEscalateMemberGroupUid, step.SLAEscalateMode)
+}
+
 func (s *Storage) UpdateWorkflowTemplateSteps(templateId uint, steps []*WorkflowStepTemplate) error {
 	return s.TxExec(func(tx *sql.Tx) error {
 		_, err := tx.Exec("UPDATE workflow_step_templates SET workflow_template_id = NULL WHERE workflow_template_id = ?",
@@ -358,8 +393,7 @@ func (s *Storage) UpdateWorkflowTemplateSteps(templateId uint, steps []*Workflow
 			return err
 		}
 		for _, step := range steps {
-			result, err := tx.Exec("INSERT INTO workflow_step_templates (step_number, workflow_template_id, type,users, `desc`, approved_by_authorized,execute_by_authorized) values (?,?,?,?,?,?,?)",
-				step.Number, templateId, step.Typ, step.Users, step.Desc, step.ApprovedByAuthorized, step.ExecuteByAuthorized)
+			result, err := insertWorkflowStepTemplate(tx, int64(templateId), step)
 			if err != nil {
 				return err
 			}
@@ -518,6 +552,8 @@ type WorkflowStep struct {
 	WorkflowStepTemplateId uint   `gorm:"index; not null"`
 	State                  string `gorm:"default:\"initialized\"; type:varchar(255)"`
 	Reason                 string `gorm:"type:varchar(255)"`
+	// OnBehalfOfUserId is the assignee delegating the operation user, empty if the step is operated by an assignee
+	OnBehalfOfUserId string `gorm:"type:varchar(255)"`
 
 	Assignees string                `gorm:"type:varchar(2000)"` // `gorm:"many2many:workflow_step_user"`
 	Template  *WorkflowStepTemplate `gorm:"foreignkey:WorkflowStepTemplateId"`
@@ -1056,8 +1092,8 @@ func updateWorkflowStatus(tx *gorm.DB, w *Workflow) error {
 
 func updateWorkflowStep(tx *gorm.DB, operateStep *WorkflowStep) error {
 	// 必须保证更新前的操作用户未填写，通过数据库的特性保证数据不会重复写
-	db := tx.Exec("UPDATE workflow_steps SET operation_user_id = ?, operate_at = ?, state = ?, reason = ? WHERE id = ? AND operation_user_id = ?",
-		operateStep.OperationUserId, operateStep.OperateAt, operateStep.State, operateStep.Reason, operateStep.ID, "")
+	db := tx.Exec("UPDATE workflow_steps SET operation_user_id = ?, on_behalf_of_user_id = ?, operate_at = ?, state = ?, reason = ? WHERE id = ? AND operation_user_id = ?",
+		operateStep.OperationUserId, operateStep.OnBehalfOfUserId, operateStep.OperateAt, operateStep.State, operateStep.Reason, operateStep.ID, "")
 	if db.Error != nil {
 		return db.Error
 	}
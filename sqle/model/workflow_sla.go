@@ -0,0 +1,165 @@
+package model
+
+import (
+	e "errors"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &WorkflowStepSLA{})
+}
+
+const (
+	// SLAEscalateModeAdd adds the escalation target to the assignees of the step
+	SLAEscalateModeAdd = "add"
+	// SLAEscalateModeReassign replaces the assignees of the step with the escalation target
+	SLAEscalateModeReassign = "reassign"
+)
+
+// WorkflowStepSLA tracks the reminders and the escalation of a workflow step with SLA, a step is breached once
+// it waits longer than the escalation duration of its template.
+type WorkflowStepSLA struct {
+	Model
+	ProjectId      ProjectUID `gorm:"index; not null; type:varchar(255)"`
+	WorkflowId     string     `gorm:"index; not null; type:varchar(255)"`
+	WorkflowStepId uint       `gorm:"uniqueIndex; not null"`
+	StepNumber     uint
+	// StepStartAt is when the step starts to wait for its assignees
+	StepStartAt  *time.Time
+	RemindCount  uint
+	LastRemindAt *time.Time
+	BreachedAt   *time.Time `gorm:"index"`
+	// OriginalAssignees and EscalatedTo are the user ids separated by ","
+	OriginalAssignees string `gorm:"type:varchar(2000)"`
+	EscalatedTo       string `gorm:"type:varchar(2000)"`
+	EscalateMode      string `gorm:"type:varchar(32)"`
+}
+
+func (s *Storage) GetWorkflowStepSLAByStepId(stepId uint) (*WorkflowStepSLA, bool, error) {
+	sla := &WorkflowStepSLA{}
+	err := s.db.Where("workflow_step_id = ?", stepId).First(sla).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return sla, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// PendingSLAStep is the current step of a pending workflow whose template has SLA.
+type PendingSLAStep struct {
+	ProjectId     string     `json:"project_id"`
+	WorkflowId    string     `json:"workflow_id"`
+	Subject       string     `json:"subject"`
+	StepId        uint       `json:"step_id"`
+	StepNumber    uint       `json:"step_number"`
+	StepType      string     `json:"step_type"`
+	Assignees     string     `json:"assignees"`
+	StepCreatedAt *time.Time `json:"step_created_at"`
+	// PrevOperateAt is when the previous step is operated, it is null for the first step
+	PrevOperateAt *time.Time `json:"prev_operate_at"`
+
+	SLARemindIntervalMinute   uint   `json:"sla_remind_interval_minute"`
+	SLAEscalateAfterMinute    uint   `json:"sla_escalate_after_minute"`
+	SLAEscalateUsers          string `json:"sla_escalate_users"`
+	SLAEscalateMemberGroupUid string `json:"sla_escalate_member_group_uid"`
+	SLAEscalateMode           string `json:"sla_escalate_mode"`
+}
+
+// StartAt is when the step starts to wait for its assignees.
+func (p *PendingSLAStep) StartAt() *time.Time {
+	if p.PrevOperateAt != nil {
+		return p.PrevOperateAt
+	}
+	return p.StepCreatedAt
+}
+
+func (s *Storage) GetPendingSLAWorkflowSteps() ([]*PendingSLAStep, error) {
+	steps := []*PendingSLAStep{}
+	err := s.db.Raw(`
+SELECT w.project_id, w.workflow_id, w.subject, ws.id AS step_id, wst.step_number, wst.type AS step_type,
+ws.assignees, ws.created_at AS step_created_at,
+(SELECT MAX(p.operate_at) FROM workflow_steps AS p WHERE p.workflow_record_id = ws.workflow_record_id AND p.id < ws.id) AS prev_operate_at,
+wst.sla_remind_interval_minute, wst.sla_escalate_after_minute, wst.sla_escalate_users,
+wst.sla_escalate_member_group_uid, wst.sla_escalate_mode
+FROM workflows AS w
+JOIN workflow_records AS wr ON w.workflow_record_id = wr.id
+JOIN workflow_steps AS ws ON wr.current_workflow_step_id = ws.id
+JOIN workflow_step_templates AS wst ON ws.workflow_step_template_id = wst.id
+WHERE w.deleted_at IS NULL
+AND wr.status IN (?, ?)
+AND ws.state = ?
+AND (wst.sla_remind_interval_minute > 0 OR wst.sla_escalate_after_minute > 0)
+`, WorkflowStatusWaitForAudit, WorkflowStatusWaitForExecution, WorkflowStepStateInit).Scan(&steps).Error
+	return steps, errors.New(errors.ConnectStorageError, err)
+}
+
+// EscalateWorkflowStep updates the assignees of the step and records the breach in one transaction.
+func (s *Storage) EscalateWorkflowStep(sla *WorkflowStepSLA, assignees string) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if err := txDB.Model(&WorkflowStep{}).Where("id = ?", sla.WorkflowStepId).
+			Update("assignees", assignees).Error; err != nil {
+			return err
+		}
+		return txDB.Save(sla).Error
+	})
+}
+
+func (s *Storage) UpdateWorkflowStepTemplateSLA(step *WorkflowStepTemplate) error {
+	err := s.db.Model(&WorkflowStepTemplate{}).Where("id = ?", step.ID).Updates(map[string]interface{}{
+		"sla_remind_interval_minute":    step.SLARemindIntervalMinute,
+		"sla_escalate_after_minute":     step.SLAEscalateAfterMinute,
+		"sla_escalate_users":            step.SLAEscalateUsers,
+		"sla_escalate_member_group_uid": step.SLAEscalateMemberGroupUid,
+		"sla_escalate_mode":             step.SLAEscalateMode,
+	}).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+type WorkflowSLABreach struct {
+	WorkflowId        string     `json:"workflow_id"`
+	Subject           string     `json:"subject"`
+	StepNumber        uint       `json:"step_number"`
+	StepStartAt       *time.Time `json:"step_start_at"`
+	BreachedAt        *time.Time `json:"breached_at"`
+	OriginalAssignees string     `json:"original_assignees"`
+	EscalatedTo       string     `json:"escalated_to"`
+	EscalateMode      string     `json:"escalate_mode"`
+	RemindCount       uint       `json:"remind_count"`
+	// OperateAt is when the step is finally operated, it is null if the step is still pending
+	OperateAt       *time.Time `json:"operate_at"`
+	OperationUserId string     `json:"operation_user_id"`
+}
+
+func (s *Storage) GetWorkflowSLABreachList(projectId, workflowId string, breachedFrom, breachedTo *time.Time, pageIndex, pageSize uint32) ([]*WorkflowSLABreach, uint64, error) {
+	var count int64
+	breaches := []*WorkflowSLABreach{}
+	query := s.db.Table("workflow_step_slas AS sla").
+		Joins("JOIN workflows AS w ON w.workflow_id = sla.workflow_id").
+		Joins("LEFT JOIN workflow_steps AS ws ON ws.id = sla.workflow_step_id").
+		Where("sla.deleted_at IS NULL AND w.deleted_at IS NULL").
+		Where("sla.breached_at IS NOT NULL").
+		Where("sla.project_id = ?", projectId)
+	if workflowId != "" {
+		query = query.Where("sla.workflow_id = ?", workflowId)
+	}
+	if breachedFrom != nil {
+		query = query.Where("sla.breached_at >= ?", breachedFrom)
+	}
+	if breachedTo != nil {
+		query = query.Where("sla.breached_at <= ?", breachedTo)
+	}
+	err := query.Count(&count).Error
+	if err != nil {
+		return breaches, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	if count == 0 {
+		return breaches, 0, nil
+	}
+	err = query.Select(`sla.workflow_id, w.subject, sla.step_number, sla.step_start_at, sla.breached_at,
+sla.original_assignees, sla.escalated_to, sla.escalate_mode, sla.remind_count, ws.operate_at, ws.operation_user_id`).
+		Offset(int((pageIndex - 1) * pageSize)).Limit(int(pageSize)).Order("sla.breached_at DESC").
+		Scan(&breaches).Error
+	return breaches, uint64(count), errors.New(errors.ConnectStorageError, err)
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	e "errors"
+	"fmt"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+func init() {
+	autoMigrateList = append(autoMigrateList, &WorkflowTemplateRouteRule{})
+}
+
+// WorkflowTemplateRouteRule selects the workflow template of the new workflows of the project whose attributes
+// match all its conditions, an empty condition always matches. The enabled rules are evaluated by priority in
+// ascending order, the default workflow template is used if none matches.
+type WorkflowTemplateRouteRule struct {
+	Model
+	ProjectId          ProjectUID `json:"project_id" gorm:"index;not null;type:varchar(255)"`
+	Name               string     `json:"name" gorm:"type:varchar(255);not null"`
+	Priority           int        `json:"priority" gorm:"not null;default:0"`
+	Enabled            bool       `json:"enabled"`
+	WorkflowTemplateId uint       `json:"workflow_template_id" gorm:"not null"`
+
+	// EnvironmentTags matches if the environment tags of all the instances are in it
+	EnvironmentTags Strings `json:"environment_tags" gorm:"type:json"`
+	// AnySQLTypes matches if the workflow has any statement of the types
+	AnySQLTypes Strings `json:"any_sql_types" gorm:"type:json"`
+	// OnlySQLTypes matches if the workflow only has the statements of the types
+	OnlySQLTypes  Strings `json:"only_sql_types" gorm:"type:json"`
+	MinAuditLevel string  `json:"min_audit_level" gorm:"type:varchar(32)"`
+	MaxAuditLevel string  `json:"max_audit_level" gorm:"type:varchar(32)"`
+	// MinAffectedRows and MaxAffectedRows limit the total estimated affected rows of the DML statements
+	MinAffectedRows *int64  `json:"min_affected_rows"`
+	MaxAffectedRows *int64  `json:"max_affected_rows"`
+	OpsTypeUIDs     Strings `json:"ops_type_uids" gorm:"type:json"`
+}
+
+// NeedAffectedRows returns whether the rule needs the estimated affected rows, which connects the instances.
+func (r *WorkflowTemplateRouteRule) NeedAffectedRows() bool {
+	return r.MinAffectedRows != nil || r.MaxAffectedRows != nil
+}
+
+// WorkflowRouteAttributes are the attributes of a new workflow to select its template.
+type WorkflowRouteAttributes struct {
+	EnvironmentTags []string
+	SQLTypes        []string
+	AuditLevel      string
+	OpsTypeUID      string
+	// AffectedRows is nil if it is not estimated, AffectedRowsErr explains why.
+	AffectedRows    *int64
+	AffectedRowsErr string
+}
+
+// Match returns whether the attributes match all the conditions of the rule, and explains the matched conditions
+// or the first condition not matched.
+func (r *WorkflowTemplateRouteRule) Match(attrs *WorkflowRouteAttributes) (bool, []string) {
+	reasons := []string{}
+	if len(r.EnvironmentTags) > 0 {
+		for _, tag := range attrs.EnvironmentTags {
+			if !containsString(r.EnvironmentTags, tag) {
+				return false, []string{fmt.Sprintf("environment tag %q is not in %v", tag, []string(r.EnvironmentTags))}
+			}
+		}
+		reasons = append(reasons, fmt.Sprintf("environment tags %v are in %v", attrs.EnvironmentTags, []string(r.EnvironmentTags)))
+	}
+	if len(r.AnySQLTypes) > 0 {
+		matched := ""
+		for _, typ := range attrs.SQLTypes {
+			if containsString(r.AnySQLTypes, typ) {
+				matched = typ
+				break
+			}
+		}
+		if matched == "" {
+			return false, []string{fmt.Sprintf("statement types %v have none of %v", attrs.SQLTypes, []string(r.AnySQLTypes))}
+		}
+		reasons = append(reasons, fmt.Sprintf("statement types %v have %s", attrs.SQLTypes, matched))
+	}
+	if len(r.OnlySQLTypes) > 0 {
+		for _, typ := range attrs.SQLTypes {
+			if !containsString(r.OnlySQLTypes, typ) {
+				return false, []string{fmt.Sprintf("statement type %s is not in %v", typ, []string(r.OnlySQLTypes))}
+			}
+		}
+		reasons = append(reasons, fmt.Sprintf("statement types %v are in %v", attrs.SQLTypes, []string(r.OnlySQLTypes)))
+	}
+	level := driverV2.RuleLevel(attrs.AuditLevel)
+	if r.MinAuditLevel != "" {
+		if !level.MoreOrEqual(driverV2.RuleLevel(r.MinAuditLevel)) {
+			return false, []string{fmt.Sprintf("audit level %q is lower than %s", attrs.AuditLevel, r.MinAuditLevel)}
+		}
+		reasons = append(reasons, fmt.Sprintf("audit level %q is not lower than %s", attrs.AuditLevel, r.MinAuditLevel))
+	}
+	if r.MaxAuditLevel != "" {
+		if level.More(driverV2.RuleLevel(r.MaxAuditLevel)) {
+			return false, []string{fmt.Sprintf("audit level %q is higher than %s", attrs.AuditLevel, r.MaxAuditLevel)}
+		}
+		reasons = append(reasons, fmt.Sprintf("audit level %q is not higher than %s", attrs.AuditLevel, r.MaxAuditLevel))
+	}
+	if r.NeedAffectedRows() {
+		if attrs.AffectedRows == nil {
+			return false, []string{fmt.Sprintf("affected rows are not estimated: %s", attrs.AffectedRowsErr)}
+		}
+		rows := *attrs.AffectedRows
+		if r.MinAffectedRows != nil && rows < *r.MinAffectedRows {
+			return false, []string{fmt.Sprintf("estimated affected rows %d are less than %d", rows, *r.MinAffectedRows)}
+		}
+		if r.MaxAffectedRows != nil && rows > *r.MaxAffectedRows {
+			return false, []string{fmt.Sprintf("estimated affected rows %d are more than %d", rows, *r.MaxAffectedRows)}
+		}
+		reasons = append(reasons, fmt.Sprintf("estimated affected rows %d are in range", rows))
+	}
+	if len(r.OpsTypeUIDs) > 0 {
+		if !containsString(r.OpsTypeUIDs, attrs.OpsTypeUID) {
+			return false, []string{fmt.Sprintf("ops type %q is not in %v", attrs.OpsTypeUID, []string(r.OpsTypeUIDs))}
+		}
+		reasons = append(reasons, fmt.Sprintf("ops type %q is in %v", attrs.OpsTypeUID, []string(r.OpsTypeUIDs)))
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "the rule has no condition")
+	}
+	return true, reasons
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Storage) GetWorkflowTemplateRouteRules(projectId ProjectUID) ([]*WorkflowTemplateRouteRule, error) {
+	rules := []*WorkflowTemplateRouteRule{}
+	err := s.db.Where("project_id = ?", projectId).Order("priority ASC, id ASC").Find(&rules).Error
+	return rules, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetWorkflowTemplateRouteRuleById(projectId ProjectUID, id uint) (*WorkflowTemplateRouteRule, bool, error) {
+	rule := &WorkflowTemplateRouteRule{}
+	err := s.db.Where("project_id = ? AND id = ?", projectId, id).First(rule).Error
+	if e.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	return rule, true, errors.New(errors.ConnectStorageError, err)
+}
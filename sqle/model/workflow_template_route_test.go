@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowTemplateRouteRuleMatch(t *testing.T) {
+	rows := func(n int64) *int64 { return &n }
+	attrs := &WorkflowRouteAttributes{
+		EnvironmentTags: []string{"prod"},
+		SQLTypes:        []string{"dml", "ddl"},
+		AuditLevel:      "warn",
+		OpsTypeUID:      "1",
+		AffectedRows:    rows(1000),
+	}
+
+	cases := []struct {
+		rule    *WorkflowTemplateRouteRule
+		matched bool
+	}{
+		{&WorkflowTemplateRouteRule{}, true},
+		{&WorkflowTemplateRouteRule{EnvironmentTags: Strings{"prod", "test"}}, true},
+		{&WorkflowTemplateRouteRule{EnvironmentTags: Strings{"test"}}, false},
+		{&WorkflowTemplateRouteRule{AnySQLTypes: Strings{"ddl"}}, true},
+		{&WorkflowTemplateRouteRule{AnySQLTypes: Strings{"dql"}}, false},
+		{&WorkflowTemplateRouteRule{OnlySQLTypes: Strings{"dml"}}, false},
+		{&WorkflowTemplateRouteRule{OnlySQLTypes: Strings{"dml", "ddl"}}, true},
+		{&WorkflowTemplateRouteRule{MinAuditLevel: "warn"}, true},
+		{&WorkflowTemplateRouteRule{MinAuditLevel: "error"}, false},
+		{&WorkflowTemplateRouteRule{MaxAuditLevel: "notice"}, false},
+		{&WorkflowTemplateRouteRule{MinAffectedRows: rows(1000)}, true},
+		{&WorkflowTemplateRouteRule{MinAffectedRows: rows(1001)}, false},
+		{&WorkflowTemplateRouteRule{MaxAffectedRows: rows(999)}, false},
+		{&WorkflowTemplateRouteRule{OpsTypeUIDs: Strings{"1"}}, true},
+		{&WorkflowTemplateRouteRule{OpsTypeUIDs: Strings{"2"}}, false},
+		{&WorkflowTemplateRouteRule{EnvironmentTags: Strings{"prod"}, AnySQLTypes: Strings{"ddl"}, MaxAffectedRows: rows(10)}, false},
+	}
+	for i, c := range cases {
+		matched, reasons := c.rule.Match(attrs)
+		assert.Equal(t, c.matched, matched, "case %d", i)
+		assert.NotEmpty(t, reasons, "case %d", i)
+	}
+
+	// the rules limiting affected rows do not match if the affected rows are not estimated
+	matched, reasons := (&WorkflowTemplateRouteRule{MaxAffectedRows: rows(10)}).Match(&WorkflowRouteAttributes{AffectedRowsErr: "not supported"})
+	assert.False(t, matched)
+	assert.Contains(t, reasons[0], "not supported")
+}
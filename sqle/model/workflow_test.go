@@ -1,8 +1,10 @@
 package model
 
 import (
+	"database/sql"
 	"testing"
 
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -137,3 +139,69 @@ func TestWorkflowRecordUpdateValues(t *testing.T) {
 		assert.Equal(t, "", updates["desc"])
 	})
 }
+
+func TestStorage_UpdateWorkflowTemplateStepsKeepsSLA(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+
+	step := &WorkflowStepTemplate{
+		Number:                    1,
+		Typ:                       WorkflowStepTypeSQLReview,
+		Users:                     "1,2",
+		Desc:                      "review",
+		ApprovedByAuthorized:      sql.NullBool{Bool: true, Valid: true},
+		SLARemindIntervalMinute:   30,
+		SLAEscalateAfterMinute:    120,
+		SLAEscalateUsers:          "3",
+		SLAEscalateMemberGroupUid: "group1",
+		SLAEscalateMode:           SLAEscalateModeAdd,
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE workflow_step_templates SET workflow_template_id = NULL WHERE workflow_template_id = ?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO workflow_step_templates (step_number, workflow_template_id, type, users, `desc`, approved_by_authorized, execute_by_authorized, "+
+		"sla_remind_interval_minute, sla_escalate_after_minute, sla_escalate_users, sla_escalate_member_group_uid, sla_escalate_mode) values (?,?,?,?,?,?,?,?,?,?,?,?)").
+		WithArgs(1, 1, WorkflowStepTypeSQLReview, "1,2", "review", true, nil, 30, 120, "3", "group1", SLAEscalateModeAdd).
+		WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, GetStorage().UpdateWorkflowTemplateSteps(1, []*WorkflowStepTemplate{step}))
+	assert.Equal(t, uint(10), step.ID)
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_SaveWorkflowTemplateKeepsDutyPolicy(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+
+	template := &WorkflowTemplate{
+		Name:                          "template",
+		Desc:                          "desc",
+		AllowSubmitWhenLessAuditLevel: "warn",
+		ProjectId:                     "1",
+		WorkflowType:                  "workflow",
+		ForbidCreatorApprove:          true,
+		ForbidApproverExecute:         true,
+		MinDistinctParticipants:       3,
+		MinParticipantsAuditLevel:     "error",
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO workflow_templates (name, `desc`, `allow_submit_when_less_audit_level`, `project_id`, `workflow_type`, `is_default`, "+
+		"`forbid_creator_approve`, `forbid_approver_execute`, `min_distinct_participants`, `min_participants_audit_level`) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+		WithArgs("template", "desc", "warn", "1", "workflow", false, true, true, 3, "error").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, GetStorage().SaveWorkflowTemplate(template))
+	assert.Equal(t, uint(5), template.ID)
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
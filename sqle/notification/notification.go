@@ -15,6 +15,7 @@ import (
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/metrics"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 )
@@ -34,13 +35,37 @@ type WorkflowNotifyConfig struct {
 }
 
 func Notify(notification Notification, userIds []string) error {
-	return dmsobject.Notify(context.TODO(), controller.GetDMSServerAddress(), v1.NotificationReq{
+	err := dmsobject.Notify(context.TODO(), controller.GetDMSServerAddress(), v1.NotificationReq{
 		Notification: &v1.Notification{
 			NotificationSubject: notification.NotificationSubject(),
 			NotificationBody:    notification.NotificationBody(),
 			UserUids:            userIds,
 		},
 	})
+	return recordNotificationResult(notificationChannelDMS, err)
+}
+
+// redirectToDelegates redirects the notifications of the users out of office to their active delegates.
+func redirectToDelegates(projectId string, instanceIds []uint64, userIds []string) []string {
+	delegations, err := model.GetStorage().GetActiveUserDelegations(userIds, time.Now())
+	if err != nil {
+		log.NewEntry().Errorf("get active user delegations error: %v", err)
+		return userIds
+	}
+	return model.RedirectToDelegates(delegations, projectId, instanceIds, userIds)
+}
+
+const (
+	notificationChannelDMS     = "dms"
+	notificationChannelWebHook = "webhook"
+)
+
+// recordNotificationResult counts the failed notifications of the channel.
+func recordNotificationResult(channel string, err error) error {
+	if err != nil {
+		metrics.NotificationFailures.Inc(channel)
+	}
+	return err
 }
 
 type WorkflowNotifyType int
@@ -373,6 +398,7 @@ func notifyWorkflow(sqleUrl string, workflow *model.Workflow, wt WorkflowNotifyT
 	if len(userIds) == 0 {
 		return
 	}
+	userIds = redirectToDelegates(string(workflow.ProjectId), workflow.GetInstanceIds(), userIds)
 
 	err = Notify(wn, userIds)
 	if err != nil {
@@ -583,13 +609,14 @@ func (n *AuditPlanNotifier) Send(notification Notification, auditPlan *model.Aud
 
 func (n *AuditPlanNotifier) sendEmail(notification Notification, user *model.User) error {
 	// dms-todo 只发送邮件告警
-	return dmsobject.Notify(context.TODO(), controller.GetDMSServerAddress(), v1.NotificationReq{
+	err := dmsobject.Notify(context.TODO(), controller.GetDMSServerAddress(), v1.NotificationReq{
 		Notification: &v1.Notification{
 			NotificationSubject: notification.NotificationSubject(),
 			NotificationBody:    notification.NotificationBody(),
 			UserUids:            []string{user.GetIDStr()},
 		},
 	})
+	return recordNotificationResult(notificationChannelDMS, err)
 }
 
 func (n *AuditPlanNotifier) updateRecord(auditPlanName string) {
@@ -0,0 +1,127 @@
+package notification
+
+import (
+	"strings"
+	"time"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/utils"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type SqlRegressionNotification struct {
+	sql        *model.SQLManageRecord
+	regression *model.SqlManageRegression
+}
+
+func NewSqlRegressionNotification(sql *model.SQLManageRecord, regression *model.SqlManageRegression) *SqlRegressionNotification {
+	return &SqlRegressionNotification{
+		sql:        sql,
+		regression: regression,
+	}
+}
+
+func GetSqlRegressionTypeDesc(typ string) *i18n.Message {
+	switch typ {
+	case model.SqlRegressionTypeLatency:
+		return locale.SqlRegressionTypeLatency
+	case model.SqlRegressionTypeRowsExamined:
+		return locale.SqlRegressionTypeRowsExamined
+	case model.SqlRegressionTypeFrequency:
+		return locale.SqlRegressionTypeFrequency
+	}
+	return locale.SqlRegressionTypeLatency
+}
+
+func (n *SqlRegressionNotification) NotificationSubject() i18nPkg.I18nStr {
+	return locale.Bundle.LocalizeAllWithArgs(locale.NotifySqlRegressionSubject, locale.Bundle.LocalizeAll(GetSqlRegressionTypeDesc(n.regression.RegressionType)))
+}
+
+func (n *SqlRegressionNotification) NotificationBody() i18nPkg.I18nStr {
+	return locale.Bundle.LocalizeAllWithArgs(locale.NotifySqlRegressionBody,
+		n.sql.SQLID,
+		locale.Bundle.LocalizeAll(GetSqlRegressionTypeDesc(n.regression.RegressionType)),
+		n.regression.MetricName,
+		n.regression.BaselineMedian,
+		n.regression.BaselineP95,
+		n.regression.CurrentValue,
+		n.regression.Ratio,
+		n.regression.DetectedAt.Format(time.RFC3339),
+		n.sql.SqlText,
+	)
+}
+
+// NotifySqlRegression notifies the assignees of the SQL and the creator of its scan task.
+func NotifySqlRegression(sql *model.SQLManageRecord, regression *model.SqlManageRegression) error {
+	userIds, err := getSqlManageNotifyUserIds(sql)
+	if err != nil {
+		return err
+	}
+	if len(userIds) == 0 {
+		return nil
+	}
+	return Notify(NewSqlRegressionNotification(sql, regression), userIds)
+}
+
+func getSqlManageNotifyUserIds(sql *model.SQLManageRecord) ([]string, error) {
+	s := model.GetStorage()
+	userIds := []string{}
+	process, err := s.GetSQLManageRecordProcess(sql.ID)
+	if err == nil && process.Assignees != "" {
+		userIds = append(userIds, strings.Split(process.Assignees, ",")...)
+	}
+	instanceAuditPlan, exist, err := s.GetInstanceAuditPlanDetail(sql.SourceId)
+	if err != nil {
+		return nil, err
+	}
+	if exist && instanceAuditPlan.CreateUserID != "" {
+		userIds = append(userIds, instanceAuditPlan.CreateUserID)
+	}
+	return utils.RemoveDuplicate(userIds), nil
+}
+
+type SqlPlanRegressionNotification struct {
+	sql      *model.SQLManageRecord
+	change   *model.SqlManageExecutePlanChange
+	fromPlan string
+	toPlan   string
+}
+
+func NewSqlPlanRegressionNotification(sql *model.SQLManageRecord, change *model.SqlManageExecutePlanChange, fromPlan, toPlan string) *SqlPlanRegressionNotification {
+	return &SqlPlanRegressionNotification{
+		sql:      sql,
+		change:   change,
+		fromPlan: fromPlan,
+		toPlan:   toPlan,
+	}
+}
+
+func (n *SqlPlanRegressionNotification) NotificationSubject() i18nPkg.I18nStr {
+	return locale.Bundle.LocalizeAll(locale.NotifySqlPlanRegressionSubject)
+}
+
+func (n *SqlPlanRegressionNotification) NotificationBody() i18nPkg.I18nStr {
+	return locale.Bundle.LocalizeAllWithArgs(locale.NotifySqlPlanRegressionBody,
+		n.sql.SQLID,
+		strings.Join(n.change.ChangeTypes, ", "),
+		n.fromPlan,
+		n.toPlan,
+		n.change.DetectedAt.Format(time.RFC3339),
+		n.sql.SqlText,
+	)
+}
+
+// NotifySqlPlanRegression notifies the assignees of the SQL and the creator of its scan task
+// when the execute plan of the SQL flips to a worse plan.
+func NotifySqlPlanRegression(sql *model.SQLManageRecord, change *model.SqlManageExecutePlanChange, fromPlan, toPlan string) error {
+	userIds, err := getSqlManageNotifyUserIds(sql)
+	if err != nil {
+		return err
+	}
+	if len(userIds) == 0 {
+		return nil
+	}
+	return Notify(NewSqlPlanRegressionNotification(sql, change, fromPlan, toPlan), userIds)
+}
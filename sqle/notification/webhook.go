@@ -132,12 +132,13 @@ func workflowSendRequest(action string, workflow *model.Workflow) (err error) {
 		return err
 	}
 
-	return dmsobject.WebHookSendMessage(context.TODO(), controller.GetDMSServerAddress(), &v1.WebHookSendMessageReq{
+	err = dmsobject.WebHookSendMessage(context.TODO(), controller.GetDMSServerAddress(), &v1.WebHookSendMessageReq{
 		WebHookMessage: &v1.WebHooksMessage{
 			Message:          string(b),
 			TriggerEventType: v1.TriggerEventTypeWorkflow,
 		},
 	})
+	return recordNotificationResult(notificationChannelWebHook, err)
 
 }
 
@@ -204,10 +205,11 @@ func auditPlanSendRequest(auditPlan *model.AuditPlan, report *model.AuditPlanRep
 	if err != nil {
 		return err
 	}
-	return dmsobject.WebHookSendMessage(context.TODO(), controller.GetDMSServerAddress(), &v1.WebHookSendMessageReq{
+	err = dmsobject.WebHookSendMessage(context.TODO(), controller.GetDMSServerAddress(), &v1.WebHookSendMessageReq{
 		WebHookMessage: &v1.WebHooksMessage{
 			Message:          string(b),
 			TriggerEventType: v1.TriggerEventAuditPlan,
 		},
 	})
+	return recordNotificationResult(notificationChannelWebHook, err)
 }
@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"strings"
+	"time"
+
+	"github.com/actiontech/dms/pkg/dms-common/i18nPkg"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+type WorkflowSLANotification struct {
+	step      *model.PendingSLAStep
+	assignees []string
+	waited    time.Duration
+	escalated bool
+}
+
+func NewWorkflowSLANotification(step *model.PendingSLAStep, assignees []string, waited time.Duration, escalated bool) *WorkflowSLANotification {
+	return &WorkflowSLANotification{
+		step:      step,
+		assignees: assignees,
+		waited:    waited,
+		escalated: escalated,
+	}
+}
+
+func (n *WorkflowSLANotification) NotificationSubject() i18nPkg.I18nStr {
+	stepType := GetWorkflowStepTypeDesc(n.step.StepType)
+	if n.escalated {
+		return locale.Bundle.LocalizeAllWithArgs(locale.NotifyWorkflowSLAEscalateSubject, n.step.Subject, stepType)
+	}
+	return locale.Bundle.LocalizeAllWithArgs(locale.NotifyWorkflowSLARemindSubject, n.step.Subject, stepType, int64(n.waited.Minutes()))
+}
+
+func (n *WorkflowSLANotification) NotificationBody() i18nPkg.I18nStr {
+	userNames := make([]string, 0, len(n.assignees))
+	for _, id := range n.assignees {
+		userNames = append(userNames, dms.GetUserNameWithDelTag(id))
+	}
+	startAt := ""
+	if t := n.step.StartAt(); t != nil {
+		startAt = t.Format("2006-01-02 15:04:05")
+	}
+	return locale.Bundle.LocalizeAllWithArgs(locale.NotifyWorkflowSLABody,
+		n.step.Subject,
+		n.step.WorkflowId,
+		n.step.StepNumber,
+		startAt,
+		int64(n.waited.Minutes()),
+		strings.Join(userNames, ","),
+	)
+}
+
+// NotifyWorkflowSLA reminds the assignees of the pending step, or notifies them that the step is escalated to them.
+func NotifyWorkflowSLA(step *model.PendingSLAStep, assignees []string, waited time.Duration, escalated bool) error {
+	if len(assignees) == 0 {
+		return nil
+	}
+	// the instances of the workflow are unknown here, so only the delegations not limited to an instance apply
+	return Notify(NewWorkflowSLANotification(step, assignees, waited, escalated), redirectToDelegates(step.ProjectId, nil, assignees))
+}
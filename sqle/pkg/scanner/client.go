@@ -49,6 +49,9 @@ const (
 	UploadSQL = "/sqle/v2/projects/%v/audit_plans/%s/sqls/upload"
 )
 
+// ErrAuditResult is returned if the audit level of any SQL is error, the SQL is audited successfully.
+var ErrAuditResult = errors.New("audit result error")
+
 type (
 	BaseRes                     = controller.BaseRes
 	GetAuditPlanReportSQLsRes   = v1.GetAuditPlanReportSQLsResV1
@@ -237,7 +240,6 @@ func (sc *Client) GetTaskSQLs(ctx context.Context, taskID uint, sqlAuditRecordID
 	pageIndex, pageSize = 1, 10
 	cursor = pageIndex * pageSize
 	var finalErr error
-	auditError := errors.New("audit result error")
 	var totalCount, errorCount, warningCount int
 
 	for {
@@ -266,7 +268,7 @@ func (sc *Client) GetTaskSQLs(ctx context.Context, taskID uint, sqlAuditRecordID
 
 			if sql.AuditLevel == "error" {
 				errorCount++
-				finalErr = auditError
+				finalErr = ErrAuditResult
 			}
 
 			if sql.AuditLevel == "warn" {
@@ -293,7 +295,6 @@ func (sc *Client) GetAuditReportReq(auditPlanName string, reportID string) error
 	pageIndex, pageSize = 1, 10
 	cursor = pageIndex * pageSize
 	var finalErr error
-	auditError := errors.New("audit result error")
 
 	for {
 		url := sc.baseURL + fmt.Sprintf(GetAuditReport, sc.project, auditPlanName, reportID, pageIndex, pageSize)
@@ -314,7 +315,7 @@ func (sc *Client) GetAuditReportReq(auditPlanName string, reportID string) error
 			fmt.Println(res.SQL)
 			fmt.Println(res.AuditResult)
 			if strings.Contains(res.AuditResult, "[error]") {
-				finalErr = auditError
+				finalErr = ErrAuditResult
 			}
 		}
 
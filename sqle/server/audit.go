@@ -15,6 +15,7 @@ import (
 	// "github.com/actiontech/sqle/sqle/driver/mysql/session"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/tracing"
 	"github.com/actiontech/sqle/sqle/utils"
 
 	"github.com/pkg/errors"
@@ -219,6 +220,17 @@ func hookAudit(l *logrus.Entry, task *model.Task, p driver.Plugin, hook AuditHoo
 		}
 	}()
 
+	ctx, span := tracing.Start(entryContext(l), "audit", tracing.WithAttributes(
+		tracing.Attr("task_id", task.ID),
+		tracing.Attr("db_type", task.DBType),
+		tracing.Attr("sql_count", len(task.ExecuteSQLs)),
+	))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+	l = l.WithContext(ctx)
+
 	st := model.GetStorage()
 	whitelist, err := st.GetSqlWhitelistByProjectId(projectId)
 	if err != nil {
@@ -284,13 +296,18 @@ func hookAudit(l *logrus.Entry, task *model.Task, p driver.Plugin, hook AuditHoo
 			hook.BeforeAudit(sql)
 		}
 
-		results, err := p.Audit(context.TODO(), sqls)
+		pluginCtx, pluginSpan := tracing.Start(ctx, "audit.plugin", tracing.WithAttributes(tracing.Attr("sql_count", len(sqls))))
+		results, err := p.Audit(pluginCtx, sqls)
+		pluginSpan.RecordError(err)
+		pluginSpan.End()
 		if err != nil {
 			results = auditSQLsOneByOne(l, p, sqls)
 		} else if len(results) != len(sqls) {
 			return fmt.Errorf("audit results [%d] does not match the number of SQL [%d]", len(results), len(sqls))
 		}
+		_, customRuleSpan := tracing.Start(ctx, "audit.custom_rules", tracing.WithAttributes(tracing.Attr("rule_count", len(customRules))))
 		CustomRuleAudit(l, task, sqls, results, customRules)
+		customRuleSpan.End()
 		for i, sql := range auditSqls {
 			hook.AfterAudit(sql)
 			sql.AuditStatus = model.SQLAuditStatusFinished
@@ -302,6 +319,7 @@ func hookAudit(l *logrus.Entry, task *model.Task, p driver.Plugin, hook AuditHoo
 	}
 
 	ReplenishTaskStatistics(task)
+	applyScoringPolicy(l, projectId, task)
 
 	// 审核完成后，记录代码规范规则触发统计（异步，不影响审核主流程）
 	if AfterAuditHook != nil {
@@ -316,6 +334,7 @@ func ReplenishTaskStatistics(task *model.Task) {
 		task.PassRate = 0
 		task.AuditLevel = string(driverV2.RuleLevelNull)
 		task.Score = 0
+		task.ScoreBreakdown = nil
 		task.Status = model.TaskStatusAudited
 		return
 	}
@@ -333,6 +352,11 @@ func ReplenishTaskStatistics(task *model.Task) {
 	task.PassRate = utils.Round(normalCount/float64(len(task.ExecuteSQLs)), 4)
 	task.AuditLevel = string(maxAuditLevel)
 	task.Score = scoreTask(task)
+	task.ScoreBreakdown = &model.ScoreBreakdown{
+		Policy:      model.ScorePolicyDefault,
+		LevelCounts: levelCounts(task),
+		Score:       task.Score,
+	}
 
 	task.Status = model.TaskStatusAudited
 }
@@ -340,7 +364,7 @@ func ReplenishTaskStatistics(task *model.Task) {
 func auditSQLsOneByOne(l *logrus.Entry, p driver.Plugin, sqls []string) []*driverV2.AuditResults {
 	results := make([]*driverV2.AuditResults, 0, len(sqls))
 	for _, sql := range sqls {
-		result, err := p.Audit(context.TODO(), []string{sql})
+		result, err := p.Audit(entryContext(l), []string{sql})
 		if err != nil || len(result) != 1 {
 			if err != nil {
 				l.Errorf("audit sql failed and fallback to warn: %v", err)
@@ -440,7 +464,7 @@ func scoreTask(task *model.Task) int32 {
 }
 
 func parse(l *logrus.Entry, p driver.Plugin, sql string) (node driverV2.Node, err error) {
-	nodes, err := p.Parse(context.TODO(), sql)
+	nodes, err := p.Parse(entryContext(l), sql)
 	if err != nil {
 		return node, errors.Wrapf(err, "parse sql: %s", sql)
 	}
@@ -458,3 +482,11 @@ func appendExecuteSqlResults(executeSQL *model.ExecuteSQL, result *driverV2.Audi
 		executeSQL.AuditResults.Append(result.Results[i])
 	}
 }
+
+// entryContext returns the trace context of the log entry, the spans of the audit are the children of the span in it.
+func entryContext(l *logrus.Entry) context.Context {
+	if l != nil && l.Context != nil {
+		return context.WithoutCancel(l.Context)
+	}
+	return context.Background()
+}
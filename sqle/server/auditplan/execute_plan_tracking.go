@@ -0,0 +1,261 @@
+package auditplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/notification"
+	"github.com/actiontech/sqle/sqle/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// ExecutePlanNode is the normalized row of the MySQL classic execute plan, only the parts which decide
+// how the SQL is executed are kept, so the plan hash is stable when the estimated rows change.
+type ExecutePlanNode struct {
+	SelectId int    `json:"select_id"`
+	Table    string `json:"table"`
+	Type     string `json:"type"`
+	Key      string `json:"key"`
+}
+
+func (n ExecutePlanNode) String() string {
+	return fmt.Sprintf("%d:%s:%s:%s", n.SelectId, n.Table, n.Type, n.Key)
+}
+
+// the access types which look up the table by index
+var indexAccessTypes = map[string]struct{}{
+	"system":          {},
+	"const":           {},
+	"eq_ref":          {},
+	"ref":             {},
+	"fulltext":        {},
+	"ref_or_null":     {},
+	"index_merge":     {},
+	"unique_subquery": {},
+	"index_subquery":  {},
+	"range":           {},
+}
+
+// the access types which scan the whole table or the whole index
+var fullScanAccessTypes = map[string]struct{}{
+	"ALL":   {},
+	"index": {},
+}
+
+func normalizeExecutePlan(records []model.SqlManageMetricExecutePlanRecord) []ExecutePlanNode {
+	nodes := make([]ExecutePlanNode, 0, len(records))
+	for _, record := range records {
+		nodes = append(nodes, ExecutePlanNode{
+			SelectId: record.SelectId,
+			Table:    strings.ToLower(record.Table),
+			Type:     record.Type,
+			Key:      strings.ToLower(record.Key),
+		})
+	}
+	return nodes
+}
+
+func executePlanHash(nodes []ExecutePlanNode) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		parts = append(parts, node.String())
+	}
+	return utils.Md5String(strings.Join(parts, ";"))
+}
+
+func ParseExecutePlanSummary(summary string) ([]ExecutePlanNode, error) {
+	nodes := []ExecutePlanNode{}
+	if summary == "" {
+		return nodes, nil
+	}
+	if err := json.Unmarshal([]byte(summary), &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// FormatExecutePlan formats the plan as `table(type, key) -> table(type, key)` in the execution order.
+func FormatExecutePlan(nodes []ExecutePlanNode) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Key == "" {
+			parts = append(parts, fmt.Sprintf("%s(%s)", node.Table, node.Type))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s(%s, %s)", node.Table, node.Type, node.Key))
+		}
+	}
+	return strings.Join(parts, " -> ")
+}
+
+func executePlanNodeKey(node ExecutePlanNode) string {
+	return fmt.Sprintf("%d:%s", node.SelectId, node.Table)
+}
+
+// CompareExecutePlans returns how the plan changes from `from` to `to`, the change is a regression
+// if any table which was accessed by index is accessed by full scan now.
+func CompareExecutePlans(from, to []ExecutePlanNode) (changeTypes []string, isRegression bool) {
+	changed := map[string]bool{}
+	fromNodes := make(map[string]ExecutePlanNode, len(from))
+	fromOrder := make([]string, 0, len(from))
+	for _, node := range from {
+		fromNodes[executePlanNodeKey(node)] = node
+		fromOrder = append(fromOrder, executePlanNodeKey(node))
+	}
+	toOrder := make([]string, 0, len(to))
+	for _, node := range to {
+		key := executePlanNodeKey(node)
+		toOrder = append(toOrder, key)
+		old, ok := fromNodes[key]
+		if !ok {
+			continue
+		}
+		_, oldIndex := indexAccessTypes[old.Type]
+		_, oldFullScan := fullScanAccessTypes[old.Type]
+		_, newIndex := indexAccessTypes[node.Type]
+		_, newFullScan := fullScanAccessTypes[node.Type]
+		switch {
+		case oldIndex && newFullScan:
+			changed[model.PlanChangeTypeIndexToFullScan] = true
+			isRegression = true
+		case oldFullScan && newIndex:
+			changed[model.PlanChangeTypeFullScanToIndex] = true
+		case old.Type != node.Type:
+			changed[model.PlanChangeTypeAccessTypeChanged] = true
+		}
+		if old.Key != "" && node.Key != "" && old.Key != node.Key {
+			changed[model.PlanChangeTypeIndexChanged] = true
+		}
+	}
+	if strings.Join(fromOrder, ",") != strings.Join(toOrder, ",") {
+		changed[model.PlanChangeTypeJoinOrderChanged] = true
+	}
+
+	// keep the change types in a fixed order
+	for _, typ := range []string{
+		model.PlanChangeTypeIndexToFullScan,
+		model.PlanChangeTypeFullScanToIndex,
+		model.PlanChangeTypeIndexChanged,
+		model.PlanChangeTypeAccessTypeChanged,
+		model.PlanChangeTypeJoinOrderChanged,
+	} {
+		if changed[typ] {
+			changeTypes = append(changeTypes, typ)
+		}
+	}
+	return changeTypes, isRegression
+}
+
+// trackSqlManageExecutePlan records the plan of the SQL in the plan history, and records a plan change
+// if the plan differs from the plan seen last time.
+func trackSqlManageExecutePlan(entry *logrus.Entry, sqlManageQueue *model.SQLManageQueue, metricRecordId uint, records []model.SqlManageMetricExecutePlanRecord, now time.Time) error {
+	nodes := normalizeExecutePlan(records)
+	if len(nodes) == 0 {
+		return nil
+	}
+	hash := executePlanHash(nodes)
+	s := model.GetStorage()
+
+	latest, hasLatest, err := s.GetLatestSqlManageExecutePlan(sqlManageQueue.SQLID)
+	if err != nil {
+		return err
+	}
+	summary, err := json.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+	plan := &model.SqlManageExecutePlan{
+		SQLID:              sqlManageQueue.SQLID,
+		PlanHash:           hash,
+		PlanSummary:        string(summary),
+		LastMetricRecordID: metricRecordId,
+		SeenCount:          1,
+		FirstSeenAt:        now,
+		LastSeenAt:         now,
+	}
+	if err := s.UpsertSqlManageExecutePlan(plan); err != nil {
+		return err
+	}
+	if !hasLatest || latest.PlanHash == hash {
+		return nil
+	}
+
+	fromNodes, err := ParseExecutePlanSummary(latest.PlanSummary)
+	if err != nil {
+		return err
+	}
+	changeTypes, isRegression := CompareExecutePlans(fromNodes, nodes)
+	change := &model.SqlManageExecutePlanChange{
+		SQLID:        sqlManageQueue.SQLID,
+		ProjectId:    sqlManageQueue.ProjectId,
+		FromPlanID:   latest.ID,
+		ToPlanID:     plan.ID,
+		ChangeTypes:  changeTypes,
+		IsRegression: isRegression,
+		DetectedAt:   now,
+	}
+	if err := s.SaveSqlManageExecutePlanChange(change); err != nil {
+		return err
+	}
+	entry.Infof("execute plan of sql %s changed from plan %d to plan %d, change types: %v", sqlManageQueue.SQLID, latest.ID, plan.ID, changeTypes)
+	if !isRegression {
+		return nil
+	}
+
+	sql, exist, err := s.GetManageSQLBySQLId(sqlManageQueue.SQLID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+	if err := notification.NotifySqlPlanRegression(sql, change, FormatExecutePlan(fromNodes), FormatExecutePlan(nodes)); err != nil {
+		entry.Warnf("notify plan regression of sql %s failed, error: %v", sqlManageQueue.SQLID, err)
+	}
+	return nil
+}
+
+// ExecutePlanRecordPair is a row of the side-by-side plan diff, `From` or `To` is nil
+// if the table is only accessed in one of the plans.
+type ExecutePlanRecordPair struct {
+	SelectId int
+	Table    string
+	From     *model.SqlManageMetricExecutePlanRecord
+	To       *model.SqlManageMetricExecutePlanRecord
+	Changed  bool
+}
+
+// AlignExecutePlanRecords aligns the rows of two plans by select id and table, the rows follow the order of the
+// `to` plan and the rows only in the `from` plan are appended.
+func AlignExecutePlanRecords(from, to []*model.SqlManageMetricExecutePlanRecord) []*ExecutePlanRecordPair {
+	pairs := make([]*ExecutePlanRecordPair, 0, len(to))
+	fromRecords := make(map[string]*model.SqlManageMetricExecutePlanRecord, len(from))
+	for _, record := range from {
+		key := executePlanNodeKey(ExecutePlanNode{SelectId: record.SelectId, Table: strings.ToLower(record.Table)})
+		if _, ok := fromRecords[key]; !ok {
+			fromRecords[key] = record
+		}
+	}
+	matched := map[string]bool{}
+	for _, record := range to {
+		key := executePlanNodeKey(ExecutePlanNode{SelectId: record.SelectId, Table: strings.ToLower(record.Table)})
+		pair := &ExecutePlanRecordPair{SelectId: record.SelectId, Table: record.Table, To: record, Changed: true}
+		if old, ok := fromRecords[key]; ok && !matched[key] {
+			matched[key] = true
+			pair.From = old
+			pair.Changed = old.Type != record.Type || !strings.EqualFold(old.Key, record.Key)
+		}
+		pairs = append(pairs, pair)
+	}
+	for _, record := range from {
+		key := executePlanNodeKey(ExecutePlanNode{SelectId: record.SelectId, Table: strings.ToLower(record.Table)})
+		if matched[key] {
+			continue
+		}
+		matched[key] = true
+		pairs = append(pairs, &ExecutePlanRecordPair{SelectId: record.SelectId, Table: record.Table, From: record, Changed: true})
+	}
+	return pairs
+}
@@ -0,0 +1,98 @@
+package auditplan
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutePlanHash(t *testing.T) {
+	plan1 := normalizeExecutePlan([]model.SqlManageMetricExecutePlanRecord{
+		{SelectId: 1, Table: "T1", Type: "ref", Key: "IDX_A", Rows: 10},
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "PRIMARY", Rows: 1},
+	})
+	// the estimated rows do not change the plan
+	plan2 := normalizeExecutePlan([]model.SqlManageMetricExecutePlanRecord{
+		{SelectId: 1, Table: "t1", Type: "ref", Key: "idx_a", Rows: 1000},
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "primary", Rows: 1},
+	})
+	assert.Equal(t, executePlanHash(plan1), executePlanHash(plan2))
+
+	plan3 := normalizeExecutePlan([]model.SqlManageMetricExecutePlanRecord{
+		{SelectId: 1, Table: "t1", Type: "ALL"},
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "PRIMARY"},
+	})
+	assert.NotEqual(t, executePlanHash(plan1), executePlanHash(plan3))
+	assert.Equal(t, "t1(ALL) -> t2(eq_ref, primary)", FormatExecutePlan(plan3))
+
+	nodes, err := ParseExecutePlanSummary(`[{"select_id":1,"table":"t1","type":"ALL","key":""}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []ExecutePlanNode{{SelectId: 1, Table: "t1", Type: "ALL"}}, nodes)
+}
+
+func TestCompareExecutePlans(t *testing.T) {
+	indexPlan := []ExecutePlanNode{
+		{SelectId: 1, Table: "t1", Type: "ref", Key: "idx_a"},
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "primary"},
+	}
+
+	changeTypes, isRegression := CompareExecutePlans(indexPlan, []ExecutePlanNode{
+		{SelectId: 1, Table: "t1", Type: "ALL"},
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "primary"},
+	})
+	assert.True(t, isRegression)
+	assert.Equal(t, []string{model.PlanChangeTypeIndexToFullScan}, changeTypes)
+
+	changeTypes, isRegression = CompareExecutePlans([]ExecutePlanNode{
+		{SelectId: 1, Table: "t1", Type: "ALL"},
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "primary"},
+	}, indexPlan)
+	assert.False(t, isRegression)
+	assert.Equal(t, []string{model.PlanChangeTypeFullScanToIndex}, changeTypes)
+
+	changeTypes, isRegression = CompareExecutePlans(indexPlan, []ExecutePlanNode{
+		{SelectId: 1, Table: "t1", Type: "range", Key: "idx_b"},
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "primary"},
+	})
+	assert.False(t, isRegression)
+	assert.Equal(t, []string{model.PlanChangeTypeIndexChanged, model.PlanChangeTypeAccessTypeChanged}, changeTypes)
+
+	changeTypes, isRegression = CompareExecutePlans(indexPlan, []ExecutePlanNode{
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "primary"},
+		{SelectId: 1, Table: "t1", Type: "ref", Key: "idx_a"},
+	})
+	assert.False(t, isRegression)
+	assert.Equal(t, []string{model.PlanChangeTypeJoinOrderChanged}, changeTypes)
+}
+
+func TestAlignExecutePlanRecords(t *testing.T) {
+	from := []*model.SqlManageMetricExecutePlanRecord{
+		{SelectId: 1, Table: "t1", Type: "ref", Key: "idx_a"},
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "PRIMARY"},
+		{SelectId: 2, Table: "t3", Type: "ref", Key: "idx_c"},
+	}
+	to := []*model.SqlManageMetricExecutePlanRecord{
+		{SelectId: 1, Table: "t2", Type: "eq_ref", Key: "PRIMARY"},
+		{SelectId: 1, Table: "t1", Type: "ALL"},
+		{SelectId: 1, Table: "t4", Type: "ALL"},
+	}
+	pairs := AlignExecutePlanRecords(from, to)
+	assert.Len(t, pairs, 4)
+
+	assert.Equal(t, "t2", pairs[0].Table)
+	assert.Equal(t, from[1], pairs[0].From)
+	assert.False(t, pairs[0].Changed)
+
+	assert.Equal(t, "t1", pairs[1].Table)
+	assert.Equal(t, from[0], pairs[1].From)
+	assert.True(t, pairs[1].Changed)
+
+	assert.Equal(t, "t4", pairs[2].Table)
+	assert.Nil(t, pairs[2].From)
+	assert.True(t, pairs[2].Changed)
+
+	assert.Equal(t, "t3", pairs[3].Table)
+	assert.Nil(t, pairs[3].To)
+	assert.True(t, pairs[3].Changed)
+}
@@ -0,0 +1,277 @@
+package auditplan
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/notification"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// the metric history in the baseline window builds the baseline of the SQL
+	sqlRegressionBaselineWindow = 7 * 24 * time.Hour
+	// the metric history in the recent window is compared with the baseline
+	sqlRegressionRecentWindow = time.Hour
+	// the baseline is unreliable if there are too few samples
+	sqlRegressionMinSamples = 5
+	// the runtime metric records older than the retention are deleted, they are out of the baseline window
+	sqlManageMetricRetention = 2 * sqlRegressionBaselineWindow
+	// the number of the runtime metric records deleted in a transaction
+	sqlManageMetricRetentionBatch = 1000
+	// the robust z-score the recent value should exceed, it is computed by the median absolute deviation
+	sqlRegressionMinZScore = 3.0
+)
+
+// sqlRegressionMetric is a metric checked for regression, the recent value is a regression if it is
+// higher than the p95 of the baseline and reaches `minRatio` times the median of the baseline.
+type sqlRegressionMetric struct {
+	regressionType string
+	metricName     string
+	minRatio       float64
+}
+
+var sqlRegressionMetrics = []sqlRegressionMetric{
+	{regressionType: model.SqlRegressionTypeLatency, metricName: MetricNameQueryTimeAvg, minRatio: 2},
+	{regressionType: model.SqlRegressionTypeRowsExamined, metricName: MetricNameRowExaminedAvg, minRatio: 3},
+	// the frequency is checked on the execution count of the records which have the query time
+	{regressionType: model.SqlRegressionTypeFrequency, metricName: MetricNameQueryTimeAvg, minRatio: 3},
+}
+
+type SqlRegressionJob struct {
+	server.BaseJob
+}
+
+func NewSqlRegressionJob(entry *logrus.Entry) server.ServerJob {
+	entry = entry.WithField("job", "sql_regression")
+	j := &SqlRegressionJob{}
+	j.BaseJob = *server.NewBaseJob(entry, 10*time.Minute, j.DetectRegression)
+	return j
+}
+
+func (j *SqlRegressionJob) DetectRegression(entry *logrus.Entry) {
+	s := model.GetStorage()
+	now := time.Now()
+	sqlIds, err := s.GetSQLIDsWithMetricRecordSince(now.Add(-sqlRegressionRecentWindow), SqlManageRuntimeMetrics)
+	if err != nil {
+		entry.Warnf("get sql with recent metrics failed, error: %v", err)
+		return
+	}
+	for _, sqlId := range sqlIds {
+		if err := detectSqlRegression(entry, s, sqlId, now); err != nil {
+			entry.Warnf("detect regression of sql %s failed, error: %v", sqlId, err)
+		}
+	}
+}
+
+type SqlManageMetricRetentionJob struct {
+	server.BaseJob
+}
+
+func NewSqlManageMetricRetentionJob(entry *logrus.Entry) server.ServerJob {
+	entry = entry.WithField("job", "sql_manage_metric_retention")
+	j := &SqlManageMetricRetentionJob{}
+	j.BaseJob = *server.NewBaseJob(entry, time.Hour, j.PruneMetricRecords)
+	return j
+}
+
+// PruneMetricRecords deletes the expired runtime metric records, the records of the other metrics such as
+// the explain cost are kept.
+func (j *SqlManageMetricRetentionJob) PruneMetricRecords(entry *logrus.Entry) {
+	s := model.GetStorage()
+	before := time.Now().Add(-sqlManageMetricRetention)
+	var total int64
+	for {
+		deleted, err := s.DeleteSqlManageMetricRecordsBefore(before, SqlManageRuntimeMetrics, sqlManageMetricRetentionBatch)
+		if err != nil {
+			entry.Warnf("delete expired runtime metric records failed, error: %v", err)
+			break
+		}
+		total += deleted
+		if deleted < sqlManageMetricRetentionBatch {
+			break
+		}
+	}
+	if total > 0 {
+		entry.Infof("%d expired runtime metric records are deleted", total)
+	}
+}
+
+func detectSqlRegression(entry *logrus.Entry, s *model.Storage, sqlId string, now time.Time) error {
+	samples, err := s.GetSqlManageMetricSamples(sqlId, SqlManageRuntimeMetrics, now.Add(-sqlRegressionBaselineWindow))
+	if err != nil {
+		return err
+	}
+	actives, err := s.GetActiveSqlManageRegressions(sqlId)
+	if err != nil {
+		return err
+	}
+	activeMap := make(map[string]*model.SqlManageRegression, len(actives))
+	for _, active := range actives {
+		activeMap[active.RegressionType] = active
+	}
+
+	var sql *model.SQLManageRecord
+	var recovered bool
+	recentBegin := now.Add(-sqlRegressionRecentWindow)
+	for _, metric := range sqlRegressionMetrics {
+		baseline, recent := splitRegressionSamples(samples, metric, recentBegin)
+		result, found := checkRegression(baseline, recent, metric.minRatio)
+		active, isActive := activeMap[metric.regressionType]
+		switch {
+		case found && isActive:
+			active.CurrentValue = result.CurrentValue
+			active.Ratio = result.Ratio
+			if err := s.SaveSqlManageRegression(active, false); err != nil {
+				return err
+			}
+		case found && !isActive:
+			if sql == nil {
+				var exist bool
+				sql, exist, err = s.GetManageSQLBySQLId(sqlId)
+				if err != nil {
+					return err
+				}
+				if !exist {
+					return nil
+				}
+			}
+			result.SQLID = sqlId
+			result.ProjectId = sql.ProjectId
+			result.InstanceID = sql.InstanceID
+			result.RegressionType = metric.regressionType
+			result.MetricName = metric.metricName
+			result.Status = model.SqlRegressionStatusActive
+			result.DetectedAt = now
+			if err := s.SaveSqlManageRegression(result, true); err != nil {
+				return err
+			}
+			entry.Infof("sql %s regressed on %s, current %v, baseline median %v", sqlId, metric.regressionType, result.CurrentValue, result.BaselineMedian)
+			if err := notification.NotifySqlRegression(sql, result); err != nil {
+				entry.Warnf("notify regression of sql %s failed, error: %v", sqlId, err)
+			}
+		case !found && isActive && len(recent) > 0:
+			active.Status = model.SqlRegressionStatusRecovered
+			active.RecoveredAt = &now
+			if err := s.SaveSqlManageRegression(active, false); err != nil {
+				return err
+			}
+			recovered = true
+		}
+	}
+	if !recovered {
+		return nil
+	}
+	for _, active := range activeMap {
+		if active.Status == model.SqlRegressionStatusActive {
+			return nil
+		}
+	}
+	return lowerSqlPriorityAfterRecovery(entry, s, sqlId, sql)
+}
+
+// lowerSqlPriorityAfterRecovery clears the high priority raised by the regressions if the SQL does not
+// meet the other high priority conditions any more.
+func lowerSqlPriorityAfterRecovery(entry *logrus.Entry, s *model.Storage, sqlId string, sql *model.SQLManageRecord) error {
+	if sql == nil {
+		var exist bool
+		var err error
+		sql, exist, err = s.GetManageSQLBySQLId(sqlId)
+		if err != nil || !exist {
+			return err
+		}
+	}
+	if sql.Priority.String != model.PriorityHigh {
+		return nil
+	}
+	auditPlan, exist, err := s.GetAuditPlanByInstanceIdAndType(sql.SourceId, sql.Source)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		auditPlan = nil
+	}
+	// the regressions are recovered, but the execute plan may still regress
+	priority, _, err := GetSingleSQLPriorityWithReasons(context.TODO(), auditPlan, sql)
+	if err != nil {
+		return err
+	}
+	if priority == model.PriorityHigh {
+		return nil
+	}
+	if err := s.ClearSQLManagePriority(sqlId); err != nil {
+		return err
+	}
+	entry.Infof("sql %s recovered from all regressions, the high priority is cleared", sqlId)
+	return nil
+}
+
+// splitRegressionSamples returns the values of the metric before and after `recentBegin`.
+func splitRegressionSamples(samples []*model.SqlManageMetricSample, metric sqlRegressionMetric, recentBegin time.Time) (baseline, recent []float64) {
+	for _, sample := range samples {
+		if sample.MetricName != metric.metricName {
+			continue
+		}
+		value := sample.MetricValue
+		if metric.regressionType == model.SqlRegressionTypeFrequency {
+			value = float64(sample.ExecutionCount)
+		}
+		if sample.RecordEndAt.Before(recentBegin) {
+			baseline = append(baseline, value)
+		} else {
+			recent = append(recent, value)
+		}
+	}
+	return baseline, recent
+}
+
+// checkRegression compares the median of the recent values with the baseline values.
+func checkRegression(baseline, recent []float64, minRatio float64) (*model.SqlManageRegression, bool) {
+	if len(baseline) < sqlRegressionMinSamples || len(recent) == 0 {
+		return nil, false
+	}
+	median := percentile(baseline, 50)
+	p95 := percentile(baseline, 95)
+	current := percentile(recent, 50)
+	if median <= 0 || current <= p95 || current < median*minRatio {
+		return nil, false
+	}
+
+	deviations := make([]float64, 0, len(baseline))
+	for _, v := range baseline {
+		deviations = append(deviations, math.Abs(v-median))
+	}
+	// 1.4826 scales the MAD to the standard deviation of the normal distribution, a zero MAD means
+	// the baseline is stable and any change beyond the thresholds is significant.
+	if mad := percentile(deviations, 50) * 1.4826; mad > 0 && (current-median)/mad < sqlRegressionMinZScore {
+		return nil, false
+	}
+	return &model.SqlManageRegression{
+		BaselineMedian: median,
+		BaselineP95:    p95,
+		CurrentValue:   current,
+		Ratio:          current / median,
+		SampleCount:    len(baseline),
+	}, true
+}
+
+// percentile returns the p-th percentile of values with linear interpolation.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
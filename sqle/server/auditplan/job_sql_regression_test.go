@@ -0,0 +1,71 @@
+package auditplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, float64(0), percentile(nil, 50))
+	assert.Equal(t, float64(3), percentile([]float64{5, 1, 3}, 50))
+	assert.Equal(t, 2.5, percentile([]float64{4, 1, 3, 2}, 50))
+	assert.InDelta(t, 9.55, percentile([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 95), 0.0001)
+}
+
+func TestCheckRegression(t *testing.T) {
+	baseline := []float64{1.0, 1.1, 0.9, 1.2, 1.0, 0.95, 1.05}
+
+	// too few samples to build a baseline
+	_, found := checkRegression(baseline[:3], []float64{10}, 2)
+	assert.False(t, found)
+
+	// no recent value
+	_, found = checkRegression(baseline, nil, 2)
+	assert.False(t, found)
+
+	// within the noise of the baseline
+	_, found = checkRegression(baseline, []float64{1.15, 1.1}, 2)
+	assert.False(t, found)
+
+	// higher than p95 but not reach the ratio
+	_, found = checkRegression(baseline, []float64{1.5}, 2)
+	assert.False(t, found)
+
+	// 10x slower
+	result, found := checkRegression(baseline, []float64{9, 10, 11}, 2)
+	assert.True(t, found)
+	assert.Equal(t, float64(10), result.CurrentValue)
+	assert.Equal(t, float64(1), result.BaselineMedian)
+	assert.Equal(t, float64(10), result.Ratio)
+	assert.Equal(t, len(baseline), result.SampleCount)
+
+	// a stable baseline has zero deviation
+	result, found = checkRegression([]float64{2, 2, 2, 2, 2}, []float64{6}, 3)
+	assert.True(t, found)
+	assert.Equal(t, float64(3), result.Ratio)
+}
+
+func TestSplitRegressionSamples(t *testing.T) {
+	now := time.Now()
+	samples := []*model.SqlManageMetricSample{
+		{MetricName: MetricNameQueryTimeAvg, MetricValue: 1, ExecutionCount: 10, RecordEndAt: now.Add(-2 * time.Hour)},
+		{MetricName: MetricNameRowExaminedAvg, MetricValue: 100, ExecutionCount: 10, RecordEndAt: now.Add(-2 * time.Hour)},
+		{MetricName: MetricNameQueryTimeAvg, MetricValue: 5, ExecutionCount: 50, RecordEndAt: now.Add(-10 * time.Minute)},
+	}
+	recentBegin := now.Add(-sqlRegressionRecentWindow)
+
+	baseline, recent := splitRegressionSamples(samples, sqlRegressionMetrics[0], recentBegin)
+	assert.Equal(t, []float64{1}, baseline)
+	assert.Equal(t, []float64{5}, recent)
+
+	baseline, recent = splitRegressionSamples(samples, sqlRegressionMetrics[1], recentBegin)
+	assert.Equal(t, []float64{100}, baseline)
+	assert.Len(t, recent, 0)
+
+	baseline, recent = splitRegressionSamples(samples, sqlRegressionMetrics[2], recentBegin)
+	assert.Equal(t, []float64{10}, baseline)
+	assert.Equal(t, []float64{50}, recent)
+}
@@ -5,12 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/notification"
 	"github.com/actiontech/sqle/sqle/server"
 	"github.com/sirupsen/logrus"
 )
@@ -36,6 +38,10 @@ func (j *AuditPlanHandlerJob) HandlerSQL(entry *logrus.Entry) {
 	if len(sqlList) == 0 {
 		return
 	}
+	previousLevels := make(map[string]string, len(sqlList))
+	for _, sql := range sqlList {
+		previousLevels[sql.SQLID] = sql.AuditLevel
+	}
 	sqlList, err = BatchAuditSQLs(entry, sqlList)
 	if err != nil {
 		entry.Warnf("batch audit manager sql failed, error: %v", err)
@@ -45,6 +51,10 @@ func (j *AuditPlanHandlerJob) HandlerSQL(entry *logrus.Entry) {
 	if err != nil {
 		entry.Warnf("set sql priority sql failed, error: %v", err)
 	}
+	// 按项目的分诊规则处理
+	if err = TriageSQLs(entry, sqlList, previousLevels); err != nil {
+		entry.Warnf("triage sqls failed, error: %v", err)
+	}
 	// 更新审核结果和优先级
 	recordIds := make([]uint, len(sqlList))
 	for i, sql := range sqlList {
@@ -127,6 +137,14 @@ func SetSQLPriority(sqlList []*model.SQLManageRecord) ([]*model.SQLManageRecord,
 	s := model.GetStorage()
 	// SQL聚合
 	auditPlanMap := make(map[string]*model.AuditPlanV2, 0)
+	sqlIds := make([]string, 0, len(sqlList))
+	for _, sql_ := range sqlList {
+		sqlIds = append(sqlIds, sql_.SQLID)
+	}
+	regressions, err := getSQLRegressions(s, sqlIds)
+	if err != nil {
+		return nil, err
+	}
 
 	for i, sql_ := range sqlList {
 		sourceId := sql_.SourceId
@@ -143,7 +161,7 @@ func SetSQLPriority(sqlList []*model.SQLManageRecord) ([]*model.SQLManageRecord,
 			}
 			auditPlanMap[sourceId] = auditPlan
 		}
-		priority, _, err := GetSingleSQLPriorityWithReasons(context.TODO(), auditPlan, sql_)
+		priority, _, err := getSQLPriorityWithReasons(context.TODO(), auditPlan, sql_, regressions[sql_.SQLID])
 		if err != nil {
 			return nil, err
 		}
@@ -159,6 +177,41 @@ func SetSQLPriority(sqlList []*model.SQLManageRecord) ([]*model.SQLManageRecord,
 
 // 获取SQL的优先级以及优先级触发的原因，只有高优先级或者无优先级，若是高优先级，则返回model.PriorityHigh=high,如果无优先级则返回空字符串
 func GetSingleSQLPriorityWithReasons(ctx context.Context, auditPlan *model.AuditPlanV2, sql *model.SQLManageRecord) (priority string, reasons []string, err error) {
+	if auditPlan == nil || sql == nil {
+		return "", reasons, nil
+	}
+	regressions, err := getSQLRegressions(model.GetStorage(), []string{sql.SQLID})
+	if err != nil {
+		return "", nil, err
+	}
+	return getSQLPriorityWithReasons(ctx, auditPlan, sql, regressions[sql.SQLID])
+}
+
+// sqlRegressions are the performance and execute plan regressions of the SQL which make it high priority.
+type sqlRegressions struct {
+	active     []*model.SqlManageRegression
+	planChange *model.SqlManageExecutePlanChange
+}
+
+// getSQLRegressions loads the regressions of the SQL in batch, keyed by the SQL id.
+func getSQLRegressions(s *model.Storage, sqlIds []string) (map[string]*sqlRegressions, error) {
+	actives, err := s.GetActiveSqlManageRegressionsBySQLIds(sqlIds)
+	if err != nil {
+		return nil, err
+	}
+	planChanges, err := s.GetLatestSqlManageExecutePlanChangesBySQLIds(sqlIds)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*sqlRegressions, len(sqlIds))
+	for _, sqlId := range sqlIds {
+		result[sqlId] = &sqlRegressions{active: actives[sqlId], planChange: planChanges[sqlId]}
+	}
+	return result, nil
+}
+
+// getSQLPriorityWithReasons returns the priority of the SQL, regressions may be nil if the SQL has no regression.
+func getSQLPriorityWithReasons(ctx context.Context, auditPlan *model.AuditPlanV2, sql *model.SQLManageRecord, regressions *sqlRegressions) (priority string, reasons []string, err error) {
 	if auditPlan == nil || sql == nil {
 		return "", reasons, nil
 	}
@@ -222,6 +275,23 @@ func GetSingleSQLPriorityWithReasons(ctx context.Context, auditPlan *model.Audit
 			)
 		}
 	}
+	// 存在性能回退的SQL为高优先级
+	if regressions == nil {
+		regressions = &sqlRegressions{}
+	}
+	for _, regression := range regressions.active {
+		reasons = append(reasons, fmt.Sprintf(locale.Bundle.LocalizeMsgByCtx(ctx, locale.SqlRegressionPriorityReason),
+			locale.Bundle.LocalizeMsgByCtx(ctx, notification.GetSqlRegressionTypeDesc(regression.RegressionType)),
+			regression.CurrentValue,
+			regression.Ratio),
+		)
+	}
+	// 执行计划回退的SQL为高优先级
+	if planChange := regressions.planChange; planChange != nil && planChange.IsRegression {
+		reasons = append(reasons, fmt.Sprintf(locale.Bundle.LocalizeMsgByCtx(ctx, locale.SqlPlanRegressionPriorityReason),
+			strings.Join(planChange.ChangeTypes, ", ")),
+		)
+	}
 	if len(reasons) > 0 {
 		return model.PriorityHigh, reasons, nil
 	}
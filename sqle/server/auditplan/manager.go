@@ -151,7 +151,7 @@ func GetSQLData(ctx context.Context, ap *AuditPlan, persist *model.Storage, filt
 }
 
 func init() {
-	server.OnlyRunOnLeaderJobs = append(server.OnlyRunOnLeaderJobs, NewManager, NewAuditPlanHandlerJob, NewAuditPlanAggregateSQLJob)
+	server.OnlyRunOnLeaderJobs = append(server.OnlyRunOnLeaderJobs, NewManager, NewAuditPlanHandlerJob, NewAuditPlanAggregateSQLJob, NewSqlRegressionJob, NewSqlManageMetricRetentionJob, NewSqlManageAutoCloseJob)
 }
 
 func NewManager(entry *logrus.Entry) server.ServerJob {
@@ -47,6 +47,7 @@ const (
 	TypeTDMySQLDistributedLock  = "tdsql_for_innodb_distributed_lock"
 	TypeSQLFile                 = scannerCmd.TypeSQLFile
 	TypeMSSQLTopSQL             = "mssql_top_sql"
+	TypeProxySQLQueryDigest     = "proxysql_query_digest"
 )
 
 const (
@@ -122,6 +123,11 @@ var MetaBuilderList = []MetaBuilder{
 		Desc:          locale.ApMetaAllAppExtract,
 		TaskHandlerFn: NewDefaultTaskV2Fn(),
 	},
+	{
+		Type:          TypeProxySQLQueryDigest,
+		Desc:          locale.ApMetaProxySQLQueryDigest,
+		TaskHandlerFn: NewProxySQLQueryDigestTaskV2Fn(),
+	},
 }
 
 var MetaMap = map[string]Meta{}
@@ -14,6 +14,7 @@ const MetricNameDBUser string = "db_user"
 const MetricNameHost string = "host"
 const MetricNameEndpoints string = "endpoints"
 const MetricNameStartTimeOfLastScrapedSQL string = "start_time_of_last_scraped_sql" // 抓取sql的开始时间
+const MetricNameRecordBeginAt string = "record_begin_at"                            // 采集器上报的统计区间的起始时间
 
 const MetricNameMetaName string = "schema_meta_name"    // 表或者视图的名字
 const MetricNameMetaType string = "schema_meta_type"    // 表或者视图等等
@@ -94,6 +95,7 @@ var ALLMetric = map[string]MetricType{
 	MetricNameDBUser:                    MetricTypeString, // MySQL slow log
 	MetricNameEndpoints:                 MetricTypeArray,  // MySQL slow log
 	MetricNameStartTimeOfLastScrapedSQL: MetricTypeString, // MySQL slow log
+	MetricNameRecordBeginAt:             MetricTypeString, // ProxySQL query digest
 	MetricNameMetaName:                  MetricTypeString, // MySQL schema meta
 	MetricNameMetaType:                  MetricTypeString, // MySQL schema meta
 	MetricNameRecordDeleted:             MetricTypeBool,   // MySQL schema meta
@@ -0,0 +1,577 @@
+package auditplan
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
+	"github.com/sirupsen/logrus"
+)
+
+// schemaTable is the table definition of the expected or the current schema.
+type schemaTable struct {
+	schema string
+	name   string
+	stmt   *ast.CreateTableStmt
+	// workflowId is the last finished workflow which changes the table.
+	workflowId string
+}
+
+// schemaTables is keyed by the lower case "schema.table".
+type schemaTables map[string]*schemaTable
+
+func schemaTableKey(schema, table string) string {
+	return fmt.Sprintf("%s.%s", strings.ToLower(schema), strings.ToLower(table))
+}
+
+func (s schemaTables) add(t *schemaTable) {
+	s[schemaTableKey(t.schema, t.name)] = t
+}
+
+func (s schemaTables) get(schema, table string) (*schemaTable, bool) {
+	t, ok := s[schemaTableKey(schema, table)]
+	return t, ok
+}
+
+func (s schemaTables) remove(schema, table string) {
+	delete(s, schemaTableKey(schema, table))
+}
+
+func (s schemaTables) sortedKeys() []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func tableSchemaOrDefault(table *ast.TableName, defaultSchema string) string {
+	if table.Schema.O != "" {
+		return table.Schema.O
+	}
+	return defaultSchema
+}
+
+// replaySchemaDDL applies the DDL executed by a workflow to the expected schema. Only the
+// statements which change table definitions are replayed, the others are ignored.
+func replaySchemaDDL(tables schemaTables, ddl *model.ExecutedDDL) error {
+	stmts, err := util.ParseSql(ddl.Content)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *ast.CreateTableStmt:
+			schema := tableSchemaOrDefault(stmt.Table, ddl.Schema)
+			if _, ok := tables.get(schema, stmt.Table.Name.O); ok && stmt.IfNotExists {
+				continue
+			}
+			if stmt.ReferTable != nil {
+				refer, ok := tables.get(tableSchemaOrDefault(stmt.ReferTable, ddl.Schema), stmt.ReferTable.Name.O)
+				if !ok {
+					continue
+				}
+				// copy the refer table by parsing its definition again, the replay modifies the AST in place
+				sql, err := restoreSchemaNode(refer.stmt)
+				if err != nil {
+					return err
+				}
+				copied, err := util.ParseCreateTableStmt(sql)
+				if err != nil {
+					return err
+				}
+				copied.Table = stmt.Table
+				stmt = copied
+			}
+			tables.add(&schemaTable{schema: schema, name: stmt.Table.Name.O, stmt: stmt, workflowId: ddl.WorkflowId})
+		case *ast.AlterTableStmt:
+			schema := tableSchemaOrDefault(stmt.Table, ddl.Schema)
+			table, ok := tables.get(schema, stmt.Table.Name.O)
+			if !ok {
+				continue
+			}
+			merged, err := util.MergeAlterToTable(table.stmt, stmt)
+			if err != nil {
+				return err
+			}
+			tables.remove(schema, stmt.Table.Name.O)
+			tables.add(&schemaTable{
+				schema:     tableSchemaOrDefault(merged.Table, schema),
+				name:       merged.Table.Name.O,
+				stmt:       merged,
+				workflowId: ddl.WorkflowId,
+			})
+		case *ast.RenameTableStmt:
+			for _, t2t := range stmt.TableToTables {
+				table, ok := tables.get(tableSchemaOrDefault(t2t.OldTable, ddl.Schema), t2t.OldTable.Name.O)
+				if !ok {
+					continue
+				}
+				tables.remove(table.schema, table.name)
+				table.schema = tableSchemaOrDefault(t2t.NewTable, ddl.Schema)
+				table.name = t2t.NewTable.Name.O
+				table.stmt.Table = t2t.NewTable
+				table.workflowId = ddl.WorkflowId
+				tables.add(table)
+			}
+		case *ast.DropTableStmt:
+			for _, t := range stmt.Tables {
+				tables.remove(tableSchemaOrDefault(t, ddl.Schema), t.Name.O)
+			}
+		case *ast.CreateIndexStmt:
+			table, ok := tables.get(tableSchemaOrDefault(stmt.Table, ddl.Schema), stmt.Table.Name.O)
+			if !ok {
+				continue
+			}
+			constraint := &ast.Constraint{
+				Name:   stmt.IndexName,
+				Keys:   stmt.IndexPartSpecifications,
+				Option: stmt.IndexOption,
+				Tp:     ast.ConstraintIndex,
+			}
+			switch stmt.KeyType {
+			case ast.IndexKeyTypeUnique:
+				constraint.Tp = ast.ConstraintUniqIndex
+			case ast.IndexKeyTypeFullText:
+				constraint.Tp = ast.ConstraintFulltext
+			}
+			table.stmt.Constraints = append(table.stmt.Constraints, constraint)
+			table.workflowId = ddl.WorkflowId
+		case *ast.DropIndexStmt:
+			table, ok := tables.get(tableSchemaOrDefault(stmt.Table, ddl.Schema), stmt.Table.Name.O)
+			if !ok {
+				continue
+			}
+			constraints := make([]*ast.Constraint, 0, len(table.stmt.Constraints))
+			for _, c := range table.stmt.Constraints {
+				if !strings.EqualFold(c.Name, stmt.IndexName) {
+					constraints = append(constraints, c)
+				}
+			}
+			table.stmt.Constraints = constraints
+			table.workflowId = ddl.WorkflowId
+		}
+	}
+	return nil
+}
+
+// buildExpectedSchema returns the schema which the instance should have: the baseline with the
+// DDL executed by finished workflows after the baseline is pinned. The baseline tables which can not
+// be parsed are returned as unparsed, their drift is unknown.
+func buildExpectedSchema(logger *logrus.Entry, objects []*model.SchemaBaselineObject, ddls []*model.ExecutedDDL) (tables schemaTables, unparsed schemaTables) {
+	tables, unparsed = schemaTables{}, schemaTables{}
+	for _, object := range objects {
+		stmt, err := util.ParseCreateTableSqlCompatibly(object.CreateTableSQL)
+		if err != nil {
+			logger.Warnf("parse baseline table %s.%s failed, error: %v", object.SchemaName, object.TableName, err)
+			unparsed.add(&schemaTable{schema: object.SchemaName, name: object.TableName})
+			continue
+		}
+		tables.add(&schemaTable{schema: object.SchemaName, name: object.TableName, stmt: stmt, workflowId: object.WorkflowId})
+	}
+	for _, ddl := range ddls {
+		if err := replaySchemaDDL(tables, ddl); err != nil {
+			logger.Warnf("replay ddl of workflow %s failed, sql: %s, error: %v", ddl.WorkflowId, ddl.Content, err)
+		}
+	}
+	return tables, unparsed
+}
+
+func buildCurrentSchema(logger *logrus.Entry, sqls []*SchemaMetaSQL) (tables schemaTables, unparsed schemaTables) {
+	tables, unparsed = schemaTables{}, schemaTables{}
+	for _, sql := range sqls {
+		if sql.MetaType != "table" {
+			continue
+		}
+		stmt, err := util.ParseCreateTableSqlCompatibly(sql.SQLContent)
+		if err != nil {
+			logger.Warnf("parse table %s.%s failed, error: %v", sql.SchemaName, sql.MetaName, err)
+			unparsed.add(&schemaTable{schema: sql.SchemaName, name: sql.MetaName})
+			continue
+		}
+		tables.add(&schemaTable{schema: sql.SchemaName, name: sql.MetaName, stmt: stmt})
+	}
+	return tables, unparsed
+}
+
+func restoreSchemaNode(node ast.Node) (string, error) {
+	buf := new(bytes.Buffer)
+	ctx := format.NewRestoreCtx(format.RestoreStringSingleQuotes|format.RestoreKeyWordUppercase|format.RestoreNameBackQuotes, buf)
+	if err := node.Restore(ctx); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func schemaNodeDefinition(node ast.Node) string {
+	sql, err := restoreSchemaNode(node)
+	if err != nil {
+		return ""
+	}
+	return sql
+}
+
+func schemaDriftDiff(baseline, current string) string {
+	lines := []string{}
+	if baseline != "" {
+		lines = append(lines, fmt.Sprintf("- %s", baseline))
+	}
+	if current != "" {
+		lines = append(lines, fmt.Sprintf("+ %s", current))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isIntegerType(tp byte) bool {
+	switch tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		return true
+	}
+	return false
+}
+
+// columnSignature normalizes the column definition so that the equivalent definitions written
+// in different forms (e.g. "int(11)" and "int", "DEFAULT 0" and "DEFAULT '0'") are the same.
+func columnSignature(col *ast.ColumnDef) string {
+	tp := col.Tp
+	length, decimal := tp.Flen, tp.Decimal
+	switch {
+	case isIntegerType(tp.Tp):
+		// display width is deprecated and omitted by MySQL 8.0
+		length, decimal = types.UnspecifiedLength, types.UnspecifiedLength
+	case tp.Tp == mysql.TypeNewDecimal:
+		if length == types.UnspecifiedLength {
+			length = 10
+		}
+		if decimal == types.UnspecifiedLength {
+			decimal = 0
+		}
+	case tp.Tp == mysql.TypeDatetime, tp.Tp == mysql.TypeTimestamp, tp.Tp == mysql.TypeDuration:
+		length = types.UnspecifiedLength
+		if decimal == types.UnspecifiedLength {
+			decimal = 0
+		}
+	case tp.Tp == mysql.TypeString && length == types.UnspecifiedLength:
+		length = 1
+	}
+
+	notNull, autoIncrement := false, false
+	defaultValue, comment, onUpdate := "", "", ""
+	for _, op := range col.Options {
+		switch op.Tp {
+		case ast.ColumnOptionNotNull, ast.ColumnOptionPrimaryKey:
+			notNull = true
+		case ast.ColumnOptionAutoIncrement:
+			autoIncrement = true
+		case ast.ColumnOptionDefaultValue:
+			defaultValue = normalizeSchemaExpr(op.Expr)
+		case ast.ColumnOptionComment:
+			comment = normalizeSchemaExpr(op.Expr)
+		case ast.ColumnOptionOnUpdate:
+			onUpdate = normalizeSchemaExpr(op.Expr)
+		}
+	}
+	if defaultValue == "" && !notNull {
+		defaultValue = "null"
+	}
+	return fmt.Sprintf("%d|%v|%d|%d|%s|%v|%s|%v|%s|%s",
+		tp.Tp, mysql.HasUnsignedFlag(tp.Flag), length, decimal, strings.Join(tp.Elems, ","),
+		notNull, defaultValue, autoIncrement, onUpdate, comment)
+}
+
+func normalizeSchemaExpr(expr ast.ExprNode) string {
+	if expr == nil {
+		return ""
+	}
+	v := strings.ToLower(util.ExprFormat(expr))
+	v = strings.Trim(v, "'\"")
+	return strings.TrimSuffix(v, "()")
+}
+
+type schemaIndex struct {
+	name       string
+	signature  string
+	definition string
+}
+
+// tableIndexes returns the indexes of the table keyed by the lower case index name, including
+// the primary key and unique key declared in column options.
+func tableIndexes(stmt *ast.CreateTableStmt) map[string]*schemaIndex {
+	indexes := map[string]*schemaIndex{}
+	for _, col := range stmt.Cols {
+		for _, op := range col.Options {
+			switch op.Tp {
+			case ast.ColumnOptionPrimaryKey:
+				indexes["primary"] = &schemaIndex{
+					name:       "PRIMARY",
+					signature:  fmt.Sprintf("primary|%s", col.Name.Name.L),
+					definition: fmt.Sprintf("PRIMARY KEY (`%s`)", col.Name.Name.O),
+				}
+			case ast.ColumnOptionUniqKey:
+				indexes[col.Name.Name.L] = &schemaIndex{
+					name:       col.Name.Name.O,
+					signature:  fmt.Sprintf("unique|%s", col.Name.Name.L),
+					definition: fmt.Sprintf("UNIQUE KEY `%s` (`%s`)", col.Name.Name.O, col.Name.Name.O),
+				}
+			}
+		}
+	}
+	for _, constraint := range stmt.Constraints {
+		var typ string
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey:
+			typ = "primary"
+		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+			typ = "unique"
+		case ast.ConstraintIndex, ast.ConstraintKey:
+			typ = "index"
+		case ast.ConstraintFulltext:
+			typ = "fulltext"
+		default:
+			continue
+		}
+		columns := make([]string, 0, len(constraint.Keys))
+		for _, key := range constraint.Keys {
+			if key.Column == nil {
+				columns = append(columns, strings.ToLower(util.ExprFormat(key.Expr)))
+				continue
+			}
+			column := key.Column.Name.L
+			if key.Length > 0 {
+				column = fmt.Sprintf("%s(%d)", column, key.Length)
+			}
+			columns = append(columns, column)
+		}
+		name := constraint.Name
+		if typ == "primary" {
+			name = "PRIMARY"
+		} else if name == "" && len(constraint.Keys) > 0 && constraint.Keys[0].Column != nil {
+			// MySQL names the anonymous index after its first column
+			name = constraint.Keys[0].Column.Name.O
+		}
+		indexes[strings.ToLower(name)] = &schemaIndex{
+			name:       name,
+			signature:  fmt.Sprintf("%s|%s", typ, strings.Join(columns, ",")),
+			definition: schemaNodeDefinition(constraint),
+		}
+	}
+	return indexes
+}
+
+func sortedIndexNames(indexes map[string]*schemaIndex) []string {
+	names := make([]string, 0, len(indexes))
+	for name := range indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diffSchemaTable compares the columns and indexes of the expected table and the current table.
+// Only the findings on the objects in the expected table are linked to the workflow which last
+// changed the table, the objects added out of workflows are linked to none, as the added tables.
+func diffSchemaTable(expected, current *schemaTable) []*model.SchemaDriftFinding {
+	findings := []*model.SchemaDriftFinding{}
+	newFinding := func(driftType, objectName, baselineDef, currentDef string) {
+		workflowId := expected.workflowId
+		if baselineDef == "" {
+			workflowId = ""
+		}
+		findings = append(findings, &model.SchemaDriftFinding{
+			SchemaName:         current.schema,
+			TableName:          current.name,
+			DriftType:          driftType,
+			ObjectName:         objectName,
+			BaselineDefinition: baselineDef,
+			CurrentDefinition:  currentDef,
+			Diff:               schemaDriftDiff(baselineDef, currentDef),
+			WorkflowId:         workflowId,
+		})
+	}
+
+	currentCols := make(map[string]*ast.ColumnDef, len(current.stmt.Cols))
+	for _, col := range current.stmt.Cols {
+		currentCols[col.Name.Name.L] = col
+	}
+	expectedCols := make(map[string]struct{}, len(expected.stmt.Cols))
+	for _, col := range expected.stmt.Cols {
+		expectedCols[col.Name.Name.L] = struct{}{}
+		currentCol, ok := currentCols[col.Name.Name.L]
+		if !ok {
+			newFinding(model.SchemaDriftTypeColumnRemoved, col.Name.Name.O, schemaNodeDefinition(col), "")
+			continue
+		}
+		if columnSignature(col) != columnSignature(currentCol) {
+			newFinding(model.SchemaDriftTypeColumnChanged, col.Name.Name.O, schemaNodeDefinition(col), schemaNodeDefinition(currentCol))
+		}
+	}
+	for _, col := range current.stmt.Cols {
+		if _, ok := expectedCols[col.Name.Name.L]; !ok {
+			newFinding(model.SchemaDriftTypeColumnAdded, col.Name.Name.O, "", schemaNodeDefinition(col))
+		}
+	}
+
+	expectedIndexes, currentIndexes := tableIndexes(expected.stmt), tableIndexes(current.stmt)
+	for _, name := range sortedIndexNames(expectedIndexes) {
+		index := expectedIndexes[name]
+		currentIndex, ok := currentIndexes[name]
+		if !ok {
+			newFinding(model.SchemaDriftTypeIndexRemoved, index.name, index.definition, "")
+			continue
+		}
+		if index.signature != currentIndex.signature {
+			newFinding(model.SchemaDriftTypeIndexChanged, index.name, index.definition, currentIndex.definition)
+		}
+	}
+	for _, name := range sortedIndexNames(currentIndexes) {
+		if _, ok := expectedIndexes[name]; !ok {
+			index := currentIndexes[name]
+			newFinding(model.SchemaDriftTypeIndexAdded, index.name, "", index.definition)
+		}
+	}
+	return findings
+}
+
+// diffSchema compares the expected schema with the current schema. The tables which can not be
+// parsed in the baseline or the instance are skipped, they are neither added nor removed.
+func diffSchema(expected, current, unparsed schemaTables) []*model.SchemaDriftFinding {
+	findings := []*model.SchemaDriftFinding{}
+	for _, key := range expected.sortedKeys() {
+		if _, ok := unparsed[key]; ok {
+			continue
+		}
+		expectedTable := expected[key]
+		currentTable, ok := current[key]
+		if !ok {
+			def := schemaNodeDefinition(expectedTable.stmt)
+			findings = append(findings, &model.SchemaDriftFinding{
+				SchemaName:         expectedTable.schema,
+				TableName:          expectedTable.name,
+				DriftType:          model.SchemaDriftTypeTableRemoved,
+				ObjectName:         expectedTable.name,
+				BaselineDefinition: def,
+				Diff:               schemaDriftDiff(def, ""),
+				WorkflowId:         expectedTable.workflowId,
+			})
+			continue
+		}
+		findings = append(findings, diffSchemaTable(expectedTable, currentTable)...)
+	}
+	for _, key := range current.sortedKeys() {
+		if _, ok := expected[key]; ok {
+			continue
+		}
+		if _, ok := unparsed[key]; ok {
+			continue
+		}
+		currentTable := current[key]
+		def := schemaNodeDefinition(currentTable.stmt)
+		findings = append(findings, &model.SchemaDriftFinding{
+			SchemaName:        currentTable.schema,
+			TableName:         currentTable.name,
+			DriftType:         model.SchemaDriftTypeTableAdded,
+			ObjectName:        currentTable.name,
+			CurrentDefinition: def,
+			Diff:              schemaDriftDiff("", def),
+		})
+	}
+	return findings
+}
+
+// checkSchemaDrift compares the collected schema with the baseline of the instance, it does
+// nothing if the instance has no baseline.
+func (at *BaseSchemaMetaTaskV2) checkSchemaDrift(logger *logrus.Entry, ap *AuditPlan, persist *model.Storage, sqls []*SchemaMetaSQL) error {
+	baseline, exist, err := persist.GetSchemaBaselineByInstanceId(ap.InstanceID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+	ddls, err := persist.GetExecutedDDLsOfFinishedWorkflows(ap.InstanceID, &baseline.CreatedAt)
+	if err != nil {
+		return err
+	}
+	expected, unparsedBaseline := buildExpectedSchema(logger, baseline.Objects, ddls)
+	current, unparsed := buildCurrentSchema(logger, sqls)
+	for key, table := range unparsedBaseline {
+		unparsed[key] = table
+	}
+	findings := diffSchema(expected, current, unparsed)
+	for _, finding := range findings {
+		finding.ProjectId = ap.ProjectId
+		finding.InstanceId = ap.InstanceID
+		finding.AuditPlanId = ap.ID
+	}
+	if len(findings) > 0 {
+		logger.Infof("found %d schema drift against baseline %d", len(findings), baseline.ID)
+	}
+	return persist.SyncSchemaDriftFindings(baseline.ID, findings)
+}
+
+// CreateSchemaBaselineBySnapshot pins the current schema of the instance as its baseline.
+func CreateSchemaBaselineBySnapshot(logger *logrus.Entry, projectId, instanceId, userId, desc string) (*model.SchemaBaseline, error) {
+	persist := model.GetStorage()
+	sqls, err := (&BaseSchemaMetaTaskV2{}).extractSQL(logger, &AuditPlan{ProjectId: projectId, InstanceID: instanceId}, persist)
+	if err != nil {
+		return nil, err
+	}
+	baseline := &model.SchemaBaseline{
+		ProjectId:    projectId,
+		InstanceId:   instanceId,
+		Source:       model.SchemaBaselineSourceSnapshot,
+		Desc:         desc,
+		CreateUserId: userId,
+	}
+	for _, sql := range sqls {
+		if sql.MetaType != "table" {
+			continue
+		}
+		baseline.Objects = append(baseline.Objects, &model.SchemaBaselineObject{
+			SchemaName:     sql.SchemaName,
+			TableName:      sql.MetaName,
+			CreateTableSQL: sql.SQLContent,
+		})
+	}
+	return baseline, persist.SaveSchemaBaseline(baseline)
+}
+
+// CreateSchemaBaselineByWorkflow pins the schema built by replaying the DDL executed by all
+// finished workflows of the instance as its baseline.
+func CreateSchemaBaselineByWorkflow(logger *logrus.Entry, projectId, instanceId, userId, desc string) (*model.SchemaBaseline, error) {
+	persist := model.GetStorage()
+	ddls, err := persist.GetExecutedDDLsOfFinishedWorkflows(instanceId, nil)
+	if err != nil {
+		return nil, err
+	}
+	tables, _ := buildExpectedSchema(logger, nil, ddls)
+	baseline := &model.SchemaBaseline{
+		ProjectId:    projectId,
+		InstanceId:   instanceId,
+		Source:       model.SchemaBaselineSourceWorkflow,
+		Desc:         desc,
+		CreateUserId: userId,
+	}
+	for _, key := range tables.sortedKeys() {
+		table := tables[key]
+		sql, err := restoreSchemaNode(table.stmt)
+		if err != nil {
+			return nil, err
+		}
+		baseline.Objects = append(baseline.Objects, &model.SchemaBaselineObject{
+			SchemaName:     table.schema,
+			TableName:      table.name,
+			CreateTableSQL: sql,
+			WorkflowId:     table.workflowId,
+		})
+	}
+	return baseline, persist.SaveSchemaBaseline(baseline)
+}
@@ -0,0 +1,119 @@
+package auditplan
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	baselineT1 = "CREATE TABLE `t1` (`id` int(11) NOT NULL AUTO_INCREMENT, `name` varchar(32) DEFAULT NULL, PRIMARY KEY (`id`), KEY `idx_name` (`name`)) ENGINE=InnoDB"
+	currentT1  = "CREATE TABLE `t1` (\n  `id` int NOT NULL AUTO_INCREMENT,\n  `name` varchar(32) DEFAULT NULL,\n  PRIMARY KEY (`id`),\n  KEY `idx_name` (`name`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+)
+
+func TestDiffSchema(t *testing.T) {
+	logger := log.NewEntry()
+	objects := []*model.SchemaBaselineObject{
+		{SchemaName: "db1", TableName: "t1", CreateTableSQL: baselineT1},
+		{SchemaName: "db1", TableName: "t2", CreateTableSQL: "CREATE TABLE `t2` (`id` int NOT NULL, `v` int DEFAULT 0)"},
+	}
+
+	// equivalent definitions are not drift
+	expected, _ := buildExpectedSchema(logger, objects, nil)
+	current, unparsed := buildCurrentSchema(logger, []*SchemaMetaSQL{
+		{SchemaName: "db1", MetaName: "t1", MetaType: "table", SQLContent: currentT1},
+		{SchemaName: "db1", MetaName: "t2", MetaType: "table", SQLContent: "CREATE TABLE `t2` (`id` int NOT NULL, `v` int DEFAULT '0')"},
+		{SchemaName: "db1", MetaName: "v1", MetaType: "view", SQLContent: "CREATE VIEW `v1` AS SELECT 1"},
+	})
+	assert.Len(t, diffSchema(expected, current, unparsed), 0)
+
+	// the changes made out of workflows are drift
+	expected, _ = buildExpectedSchema(logger, objects, nil)
+	current, unparsed = buildCurrentSchema(logger, []*SchemaMetaSQL{
+		{SchemaName: "db1", MetaName: "t1", MetaType: "table", SQLContent: "CREATE TABLE `t1` (`id` bigint NOT NULL AUTO_INCREMENT, `age` int DEFAULT NULL, PRIMARY KEY (`id`), KEY `idx_name` (`age`))"},
+		{SchemaName: "db1", MetaName: "t3", MetaType: "table", SQLContent: "CREATE TABLE `t3` (`id` int)"},
+	})
+	findings := diffSchema(expected, current, unparsed)
+	diffs := map[string]string{}
+	for _, f := range findings {
+		diffs[f.Key()] = f.Diff
+	}
+	assert.Len(t, findings, 6)
+	assert.Contains(t, diffs, "db1.t1:column_changed:id")
+	assert.Contains(t, diffs, "db1.t1:column_removed:name")
+	assert.Contains(t, diffs, "db1.t1:column_added:age")
+	assert.Contains(t, diffs, "db1.t1:index_changed:idx_name")
+	assert.Contains(t, diffs, "db1.t2:table_removed:t2")
+	assert.Contains(t, diffs, "db1.t3:table_added:t3")
+	assert.Equal(t, "- `id` INT(11) NOT NULL AUTO_INCREMENT\n+ `id` BIGINT NOT NULL AUTO_INCREMENT", diffs["db1.t1:column_changed:id"])
+}
+
+func TestDiffSchemaWithUnparsedBaseline(t *testing.T) {
+	logger := log.NewEntry()
+	expected, unparsedBaseline := buildExpectedSchema(logger, []*model.SchemaBaselineObject{
+		{SchemaName: "db1", TableName: "t1", CreateTableSQL: baselineT1},
+		{SchemaName: "db1", TableName: "t2", CreateTableSQL: "CREATE TABLE `t2` (not a valid definition"},
+	}, nil)
+	assert.Len(t, expected, 1)
+	assert.Len(t, unparsedBaseline, 1)
+
+	current, unparsed := buildCurrentSchema(logger, []*SchemaMetaSQL{
+		{SchemaName: "db1", MetaName: "t1", MetaType: "table", SQLContent: currentT1},
+		{SchemaName: "db1", MetaName: "t2", MetaType: "table", SQLContent: "CREATE TABLE `t2` (`id` int)"},
+	})
+	for key, table := range unparsedBaseline {
+		unparsed[key] = table
+	}
+	// the table unparsed in the baseline is not reported as added
+	assert.Len(t, diffSchema(expected, current, unparsed), 0)
+}
+
+func TestDiffSchemaWithWorkflowDDL(t *testing.T) {
+	logger := log.NewEntry()
+	objects := []*model.SchemaBaselineObject{
+		{SchemaName: "db1", TableName: "t1", CreateTableSQL: baselineT1},
+	}
+	ddls := []*model.ExecutedDDL{
+		{WorkflowId: "w1", Schema: "db1", Content: "ALTER TABLE t1 ADD COLUMN age int DEFAULT NULL; CREATE INDEX idx_age ON t1(age);"},
+		{WorkflowId: "w2", Schema: "db1", Content: "CREATE TABLE t2 LIKE t1; RENAME TABLE t2 TO t3"},
+		{WorkflowId: "w3", Schema: "db2", Content: "CREATE TABLE db1.t4 (id int); DROP TABLE db1.t4"},
+	}
+	expected, _ := buildExpectedSchema(logger, objects, ddls)
+	withAge := "CREATE TABLE `t1` (`id` int NOT NULL AUTO_INCREMENT, `name` varchar(32) DEFAULT NULL, `age` int DEFAULT NULL, PRIMARY KEY (`id`), KEY `idx_name` (`name`), KEY `idx_age` (`age`))"
+	current, unparsed := buildCurrentSchema(logger, []*SchemaMetaSQL{
+		{SchemaName: "db1", MetaName: "t1", MetaType: "table", SQLContent: withAge},
+		{SchemaName: "db1", MetaName: "t3", MetaType: "table", SQLContent: withAge},
+	})
+	assert.Len(t, diffSchema(expected, current, unparsed), 0)
+
+	// drift on the table changed by a workflow is linked to the workflow
+	current, unparsed = buildCurrentSchema(logger, []*SchemaMetaSQL{
+		{SchemaName: "db1", MetaName: "t1", MetaType: "table", SQLContent: currentT1},
+		{SchemaName: "db1", MetaName: "t3", MetaType: "table", SQLContent: withAge},
+	})
+	expected, _ = buildExpectedSchema(logger, []*model.SchemaBaselineObject{
+		{SchemaName: "db1", TableName: "t1", CreateTableSQL: baselineT1},
+	}, ddls)
+	findings := diffSchema(expected, current, unparsed)
+	assert.Len(t, findings, 2)
+	for _, f := range findings {
+		assert.Equal(t, "w1", f.WorkflowId)
+		assert.Contains(t, []string{model.SchemaDriftTypeColumnRemoved, model.SchemaDriftTypeIndexRemoved}, f.DriftType)
+	}
+
+	// the objects added out of workflows are linked to none, as the added tables
+	withEmail := "CREATE TABLE `t1` (`id` int NOT NULL AUTO_INCREMENT, `name` varchar(32) DEFAULT NULL, `age` int DEFAULT NULL, `email` varchar(64) DEFAULT NULL, PRIMARY KEY (`id`), KEY `idx_name` (`name`), KEY `idx_age` (`age`), KEY `idx_email` (`email`))"
+	current, unparsed = buildCurrentSchema(logger, []*SchemaMetaSQL{
+		{SchemaName: "db1", MetaName: "t1", MetaType: "table", SQLContent: withEmail},
+		{SchemaName: "db1", MetaName: "t3", MetaType: "table", SQLContent: withAge},
+		{SchemaName: "db1", MetaName: "t5", MetaType: "table", SQLContent: withAge},
+	})
+	findings = diffSchema(expected, current, unparsed)
+	assert.Len(t, findings, 3)
+	for _, f := range findings {
+		assert.Empty(t, f.WorkflowId)
+		assert.Contains(t, []string{model.SchemaDriftTypeColumnAdded, model.SchemaDriftTypeIndexAdded, model.SchemaDriftTypeTableAdded}, f.DriftType)
+	}
+}
@@ -0,0 +1,173 @@
+package auditplan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/common"
+	"github.com/actiontech/sqle/sqle/driver/mysql"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/sirupsen/logrus"
+)
+
+// SqlManageWorkloadQuery is a SQL of the workload, Weight is its estimated total execution time. The SQL without the
+// query time is assumed to cost the median query time of the workload.
+type SqlManageWorkloadQuery struct {
+	Record         *model.SQLManageRecord
+	ExecutionCount int64
+	QueryTimeAvg   float64
+	// QueryTimeKnown is false if the query time is assumed
+	QueryTimeKnown bool
+	Weight         float64
+}
+
+type SqlManageIndexAdvice struct {
+	Plan          *mysql.WorkloadIndexPlan
+	Queries       map[string] /*sql id*/ *SqlManageWorkloadQuery
+	MaxIndexCount int
+}
+
+// AdviseSqlManageIndexes advises the indexes for the workload of the schema in SQL management, the workload is
+// weighted by the metrics recorded after `since`.
+func AdviseSqlManageIndexes(l *logrus.Entry, instance *model.Instance, schemaName string, since time.Time) (*SqlManageIndexAdvice, error) {
+	if instance.DbType != driverV2.DriverTypeMySQL {
+		return nil, fmt.Errorf("index advice unsupported database type: %s, only MySQL is supported", instance.DbType)
+	}
+	s := model.GetStorage()
+	records, err := getSqlManageWorkloadRecords(instance, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := s.GetSqlManageWorkloadMetrics(instance.ProjectId, instance.GetIDStr(), schemaName, MetricNameQueryTimeAvg, since)
+	if err != nil {
+		return nil, err
+	}
+	queries := getSqlManageWorkloadQueries(records, metrics)
+
+	advisorParams, maxIndexCount, err := getWorkloadAdvisorParams(instance)
+	if err != nil {
+		return nil, err
+	}
+	dsn, err := common.NewDSN(instance, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := executor.NewExecutor(l, dsn, schemaName)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Db.Close()
+	ctx := session.NewContext(nil, session.WithExecutor(conn))
+	ctx.SetCurrentSchema(schemaName)
+
+	workload := make([]*mysql.WorkloadQuery, 0, len(queries))
+	for _, query := range queries {
+		workload = append(workload, &mysql.WorkloadQuery{
+			SQLID:  query.Record.SQLID,
+			SQL:    query.Record.SqlText,
+			Weight: query.Weight,
+		})
+	}
+	advice := &SqlManageIndexAdvice{
+		Plan:          mysql.AdviseWorkloadIndexes(l, ctx, workload, advisorParams, maxIndexCount),
+		Queries:       map[string]*SqlManageWorkloadQuery{},
+		MaxIndexCount: maxIndexCount,
+	}
+	for _, query := range queries {
+		advice.Queries[query.Record.SQLID] = query
+	}
+	return advice, nil
+}
+
+const sqlManageWorkloadPageSize = 1000
+
+// getSqlManageWorkloadRecords loads the SQL of the schema in SQL management page by page.
+func getSqlManageWorkloadRecords(instance *model.Instance, schemaName string) ([]*model.SQLManageRecord, error) {
+	s := model.GetStorage()
+	records := []*model.SQLManageRecord{}
+	var lastId uint
+	for {
+		page, err := s.GetSQLManageRecordsByInstanceSchema(instance.ProjectId, instance.GetIDStr(), schemaName, lastId, sqlManageWorkloadPageSize)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, page...)
+		if len(page) < sqlManageWorkloadPageSize {
+			return records, nil
+		}
+		lastId = page[len(page)-1].ID
+	}
+}
+
+// getSqlManageWorkloadQueries weights the SQL by the metrics, the execution count of the SQL without metric records
+// is the counter collected with it.
+func getSqlManageWorkloadQueries(records []*model.SQLManageRecord, metrics map[string]*model.SqlManageWorkloadMetric) []*SqlManageWorkloadQuery {
+	queries := make([]*SqlManageWorkloadQuery, 0, len(records))
+	knownQueryTimes := []float64{}
+	for _, record := range records {
+		query := &SqlManageWorkloadQuery{Record: record, ExecutionCount: 1}
+		if metric, ok := metrics[record.SQLID]; ok && metric.ExecutionCount > 0 {
+			query.ExecutionCount = metric.ExecutionCount
+			query.QueryTimeAvg = metric.MetricAvg
+			query.QueryTimeKnown = true
+			knownQueryTimes = append(knownQueryTimes, metric.MetricAvg)
+		} else if info, err := record.Info.OriginValue(); err == nil {
+			if counter := LoadMetrics(info, []string{MetricNameCounter}).Get(MetricNameCounter).Int(); counter > 0 {
+				query.ExecutionCount = counter
+			}
+		}
+		queries = append(queries, query)
+	}
+
+	assumedQueryTime := 1.0
+	if len(knownQueryTimes) > 0 {
+		assumedQueryTime = percentile(knownQueryTimes, 50)
+	}
+	for _, query := range queries {
+		if !query.QueryTimeKnown {
+			query.QueryTimeAvg = assumedQueryTime
+		}
+		query.Weight = float64(query.ExecutionCount) * query.QueryTimeAvg
+	}
+	sort.SliceStable(queries, func(i, j int) bool {
+		return queries[i].Weight > queries[j].Weight
+	})
+	return queries
+}
+
+// getWorkloadAdvisorParams returns the params of the index advisors and the max index count of a table, they are
+// the params of the rules in the rule template of the instance, or the default params of the rules.
+func getWorkloadAdvisorParams(instance *model.Instance) (params.Params, int, error) {
+	rules, _, err := model.GetStorage().GetAllRulesByInstance(instance)
+	if err != nil {
+		return nil, 0, err
+	}
+	optimizeParams := rulepkg.RuleHandlerMap[rulepkg.ConfigOptimizeIndexEnabled].Rule.Params
+	indexCountParams := rulepkg.RuleHandlerMap[rulepkg.DDLCheckIndexCount].Rule.Params
+	for _, rule := range rules {
+		switch rule.Name {
+		case rulepkg.ConfigOptimizeIndexEnabled:
+			optimizeParams = rule.Params
+		case rulepkg.DDLCheckIndexCount:
+			indexCountParams = rule.Params
+		}
+	}
+	advisorParams := params.Params{
+		{
+			Key:   mysql.MAX_INDEX_COLUMN,
+			Value: fmt.Sprint(optimizeParams.GetParam(rulepkg.DefaultMultiParamsSecondKeyName).Int()),
+			Type:  params.ParamTypeInt,
+		}, {
+			Key:   mysql.MIN_COLUMN_SELECTIVITY,
+			Value: fmt.Sprint(optimizeParams.GetParam(rulepkg.DefaultMultiParamsFirstKeyName).Float64()),
+			Type:  params.ParamTypeFloat64,
+		},
+	}
+	return advisorParams, indexCountParams.GetParam(rulepkg.DefaultSingleParamKeyName).Int(), nil
+}
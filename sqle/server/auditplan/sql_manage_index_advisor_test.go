@@ -0,0 +1,40 @@
+package auditplan
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSqlManageWorkloadQueries(t *testing.T) {
+	records := []*model.SQLManageRecord{
+		{SQLID: "q1"},
+		{SQLID: "q2"},
+		{SQLID: "q3", Info: model.JSON(`{"counter": 100}`)},
+		{SQLID: "q4"},
+	}
+	metrics := map[string]*model.SqlManageWorkloadMetric{
+		"q1": {SQLID: "q1", ExecutionCount: 10, MetricAvg: 2},
+		"q2": {SQLID: "q2", ExecutionCount: 1000, MetricAvg: 0.1},
+		"q4": {SQLID: "q4", ExecutionCount: 1, MetricAvg: 4},
+	}
+	queries := getSqlManageWorkloadQueries(records, metrics)
+	assert.Len(t, queries, 4)
+
+	weights := map[string]float64{}
+	for _, query := range queries {
+		weights[query.Record.SQLID] = query.Weight
+	}
+	assert.InDelta(t, 20, weights["q1"], 0.0001)
+	assert.InDelta(t, 100, weights["q2"], 0.0001)
+	// the query time of q3 is the median of the others
+	assert.InDelta(t, 200, weights["q3"], 0.0001)
+	assert.InDelta(t, 4, weights["q4"], 0.0001)
+	assert.Equal(t, "q3", queries[0].Record.SQLID)
+	assert.False(t, queries[0].QueryTimeKnown)
+
+	// no metric at all
+	queries = getSqlManageWorkloadQueries([]*model.SQLManageRecord{{SQLID: "q1"}}, nil)
+	assert.Equal(t, float64(1), queries[0].Weight)
+}
@@ -0,0 +1,296 @@
+package auditplan
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// the length of the remark column of the SQL manage record processes
+	sqlManageRemarkMaxLength = 4000
+	sqlManageAutoCloseBatch  = 500
+)
+
+// ValidateSqlManageTriageRule checks the conditions and the actions of a triage rule.
+func ValidateSqlManageTriageRule(rule *model.SqlManageTriageRule) error {
+	for _, source := range rule.Sources {
+		if _, err := GetMeta(source); err != nil {
+			return fmt.Errorf("source %s is invalid: %v", source, err)
+		}
+	}
+	for _, condition := range rule.MetricConditions {
+		if typ, ok := ALLMetric[condition.Metric]; !ok || (typ != MetricTypeInt && typ != MetricTypeFloat) {
+			return fmt.Errorf("metric %s is not a numeric metric", condition.Metric)
+		}
+		if _, err := condition.Compare(0); err != nil {
+			return fmt.Errorf("metric %s: %v", condition.Metric, err)
+		}
+	}
+	if rule.SQLPriority != nil && *rule.SQLPriority != "" && *rule.SQLPriority != model.PriorityHigh {
+		return fmt.Errorf("sql priority %s is invalid", *rule.SQLPriority)
+	}
+	switch rule.Type {
+	case model.SqlManageTriageRuleTypeMatch:
+		switch rule.Status {
+		case "", model.ProcessStatusUnhandled, model.ProcessStatusSolved, model.ProcessStatusIgnored, model.ProcessStatusManualAudited:
+		default:
+			return fmt.Errorf("status %s is invalid", rule.Status)
+		}
+		if rule.Status == "" && len(rule.Assignees) == 0 && rule.SQLPriority == nil && rule.Comment == "" {
+			return fmt.Errorf("the match rule has no action")
+		}
+	case model.SqlManageTriageRuleTypeAutoClose:
+		if rule.InactiveDays == 0 {
+			return fmt.Errorf("inactive days of the auto close rule should be greater than 0")
+		}
+	case model.SqlManageTriageRuleTypeAutoReopen:
+	default:
+		return fmt.Errorf("rule type %s is invalid", rule.Type)
+	}
+	if rule.Type != model.SqlManageTriageRuleTypeMatch && rule.Status != "" {
+		return fmt.Errorf("the status is set by the %s rule itself", rule.Type)
+	}
+	return nil
+}
+
+// sqlManageTriage collects the changes of a SQL made by the triage rules.
+type sqlManageTriage struct {
+	sql          *model.SQLManageRecord
+	process      *model.SQLManageRecordProcess
+	processAttrs map[string]interface{}
+	recordAttrs  map[string]interface{}
+	records      []*model.SqlManageTriageRecord
+}
+
+func newSqlManageTriage(sql *model.SQLManageRecord, process *model.SQLManageRecordProcess) *sqlManageTriage {
+	return &sqlManageTriage{
+		sql:          sql,
+		process:      process,
+		processAttrs: map[string]interface{}{},
+		recordAttrs:  map[string]interface{}{},
+	}
+}
+
+func (t *sqlManageTriage) record(rule *model.SqlManageTriageRule, field, oldValue, newValue, reason string) {
+	t.records = append(t.records, &model.SqlManageTriageRecord{
+		ProjectId:         rule.ProjectId,
+		RuleId:            rule.ID,
+		RuleName:          rule.Name,
+		RuleType:          rule.Type,
+		SQLManageRecordId: t.sql.ID,
+		SQLID:             t.sql.SQLID,
+		Field:             field,
+		OldValue:          oldValue,
+		NewValue:          newValue,
+		Reason:            reason,
+	})
+}
+
+// apply applies the actions of the rule to the SQL, the status is set if it is not empty. Only the fields
+// actually changed are recorded.
+func (t *sqlManageTriage) apply(rule *model.SqlManageTriageRule, status, reason string) {
+	if status != "" && status != string(t.process.Status) {
+		t.record(rule, model.SqlManageTriageFieldStatus, string(t.process.Status), status, reason)
+		t.process.Status = model.ProcessStatus(status)
+		t.processAttrs["status"] = status
+	}
+	if len(rule.Assignees) > 0 {
+		assignees := strings.Join(rule.Assignees, ",")
+		if assignees != t.process.Assignees {
+			t.record(rule, model.SqlManageTriageFieldAssignees, t.process.Assignees, assignees, reason)
+			t.process.Assignees = assignees
+			t.processAttrs["assignees"] = assignees
+		}
+	}
+	if rule.SQLPriority != nil && *rule.SQLPriority != t.sql.Priority.String {
+		t.record(rule, model.SqlManageTriageFieldPriority, t.sql.Priority.String, *rule.SQLPriority, reason)
+		t.sql.Priority = sql.NullString{String: *rule.SQLPriority, Valid: *rule.SQLPriority != ""}
+		t.recordAttrs["priority"] = t.sql.Priority
+	}
+	if rule.Comment != "" {
+		remark := appendSqlManageRemark(t.process.Remark, rule.Comment)
+		t.record(rule, model.SqlManageTriageFieldRemark, t.process.Remark, remark, reason)
+		t.process.Remark = remark
+		t.processAttrs["remark"] = remark
+	}
+}
+
+func (t *sqlManageTriage) changed() bool {
+	return len(t.records) > 0
+}
+
+func (t *sqlManageTriage) save(s *model.Storage) error {
+	return s.SaveSqlManageTriage(t.sql.ID, t.processAttrs, t.recordAttrs, t.records)
+}
+
+// appendSqlManageRemark appends the comment to the remark in a new line, the earliest content is dropped if the
+// remark is too long.
+func appendSqlManageRemark(remark, comment string) string {
+	if remark != "" {
+		remark += "\n"
+	}
+	remark += comment
+	for utf8.RuneCountInString(remark) > sqlManageRemarkMaxLength {
+		_, size := utf8.DecodeRuneInString(remark)
+		remark = remark[size:]
+	}
+	return remark
+}
+
+// evaluateSqlManageTriageRules evaluates the enabled match and auto_reopen rules on a SQL just audited,
+// `applied` are the ids of the rules which have changed the SQL before and `previousLevel` is the audit
+// level of the SQL before it is audited.
+func evaluateSqlManageTriageRules(t *sqlManageTriage, rules []*model.SqlManageTriageRule, applied map[uint]struct{}, previousLevel string) {
+	attrs := model.NewSqlManageTriageAttributes(t.sql)
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		switch rule.Type {
+		case model.SqlManageTriageRuleTypeMatch:
+			// the match rule changes a SQL only once, so the changes made by the users are kept
+			if _, ok := applied[rule.ID]; ok {
+				continue
+			}
+			if matched, reasons := rule.Match(attrs); matched {
+				t.apply(rule, rule.Status, strings.Join(reasons, "; "))
+			}
+		case model.SqlManageTriageRuleTypeAutoReopen:
+			if !model.IsSqlManageClosedStatus(string(t.process.Status)) {
+				continue
+			}
+			if previousLevel == "" || !driverV2.RuleLevel(t.sql.AuditLevel).More(driverV2.RuleLevel(previousLevel)) {
+				continue
+			}
+			if matched, reasons := rule.Match(attrs); matched {
+				reasons = append([]string{fmt.Sprintf("audit level rises from %s to %s", previousLevel, t.sql.AuditLevel)}, reasons...)
+				t.apply(rule, model.ProcessStatusUnhandled, strings.Join(reasons, "; "))
+			}
+		}
+	}
+}
+
+// TriageSQLs evaluates the triage rules of the projects on the SQL just audited, previousLevels are the audit
+// levels of the SQL before they are audited, keyed by the SQL id. The priority of the SQL is changed in place.
+func TriageSQLs(entry *logrus.Entry, sqlList []*model.SQLManageRecord, previousLevels map[string]string) error {
+	s := model.GetStorage()
+	recordIds := make([]uint, 0, len(sqlList))
+	for _, sql := range sqlList {
+		recordIds = append(recordIds, sql.ID)
+	}
+	processes, err := s.GetSQLManageRecordProcessesByRecordIds(recordIds)
+	if err != nil {
+		return err
+	}
+	applied, err := s.GetAppliedSqlManageTriageRuleIds(recordIds)
+	if err != nil {
+		return err
+	}
+
+	rulesOfProjects := map[string][]*model.SqlManageTriageRule{}
+	for _, sql := range sqlList {
+		rules, ok := rulesOfProjects[sql.ProjectId]
+		if !ok {
+			rules, err = s.GetSqlManageTriageRules(model.ProjectUID(sql.ProjectId))
+			if err != nil {
+				return err
+			}
+			rulesOfProjects[sql.ProjectId] = rules
+		}
+		process, ok := processes[sql.ID]
+		if !ok || len(rules) == 0 {
+			continue
+		}
+		t := newSqlManageTriage(sql, process)
+		evaluateSqlManageTriageRules(t, rules, applied[sql.ID], previousLevels[sql.SQLID])
+		if !t.changed() {
+			continue
+		}
+		if err := t.save(s); err != nil {
+			entry.Warnf("save triage of sql %s failed, error: %v", sql.SQLID, err)
+			continue
+		}
+		entry.Infof("sql %s is triaged, %d fields changed", sql.SQLID, len(t.records))
+	}
+	return nil
+}
+
+type SqlManageAutoCloseJob struct {
+	server.BaseJob
+}
+
+func NewSqlManageAutoCloseJob(entry *logrus.Entry) server.ServerJob {
+	entry = entry.WithField("job", "sql_manage_auto_close")
+	j := &SqlManageAutoCloseJob{}
+	j.BaseJob = *server.NewBaseJob(entry, time.Hour, j.AutoClose)
+	return j
+}
+
+func (j *SqlManageAutoCloseJob) AutoClose(entry *logrus.Entry) {
+	s := model.GetStorage()
+	rules, err := s.GetEnabledSqlManageTriageRulesByType(model.SqlManageTriageRuleTypeAutoClose)
+	if err != nil {
+		entry.Warnf("get auto close rules failed, error: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, rule := range rules {
+		if err := autoCloseSQLs(entry, s, rule, now); err != nil {
+			entry.Warnf("auto close sqls by rule %d failed, error: %v", rule.ID, err)
+		}
+	}
+}
+
+func autoCloseSQLs(entry *logrus.Entry, s *model.Storage, rule *model.SqlManageTriageRule, now time.Time) error {
+	before := now.Add(-time.Duration(rule.InactiveDays) * 24 * time.Hour)
+	var lastId uint
+	for {
+		sqlList, err := s.GetInactiveOpenSQLManageRecords(rule.ProjectId, before, lastId, sqlManageAutoCloseBatch)
+		if err != nil {
+			return err
+		}
+		if len(sqlList) == 0 {
+			return nil
+		}
+		lastId = sqlList[len(sqlList)-1].ID
+		recordIds := make([]uint, 0, len(sqlList))
+		for _, sql := range sqlList {
+			recordIds = append(recordIds, sql.ID)
+		}
+		processes, err := s.GetSQLManageRecordProcessesByRecordIds(recordIds)
+		if err != nil {
+			return err
+		}
+		for _, sql := range sqlList {
+			process, ok := processes[sql.ID]
+			if !ok {
+				continue
+			}
+			matched, reasons := rule.Match(model.NewSqlManageTriageAttributes(sql))
+			if !matched {
+				continue
+			}
+			reasons = append([]string{fmt.Sprintf("not seen since %s", sql.UpdatedAt.Format(time.RFC3339))}, reasons...)
+			t := newSqlManageTriage(sql, process)
+			t.apply(rule, model.ProcessStatusSolved, strings.Join(reasons, "; "))
+			if !t.changed() {
+				continue
+			}
+			if err := t.save(s); err != nil {
+				return err
+			}
+			entry.Infof("sql %s is closed by rule %d", sql.SQLID, rule.ID)
+		}
+		if len(sqlList) < sqlManageAutoCloseBatch {
+			return nil
+		}
+	}
+}
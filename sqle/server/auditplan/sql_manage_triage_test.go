@@ -0,0 +1,100 @@
+package auditplan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSqlManageTriageRule(t *testing.T) {
+	high := model.PriorityHigh
+	invalid := "low"
+	assert.NoError(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeMatch, SQLPriority: &high}))
+	assert.Error(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeMatch}))
+	assert.Error(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeMatch, SQLPriority: &invalid}))
+	assert.Error(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeMatch, Status: "unknown"}))
+	assert.Error(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: "unknown", Comment: "c"}))
+	assert.NoError(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeMatch, Comment: "c",
+		MetricConditions: model.SqlManageMetricConditions{{Metric: MetricNameQueryTimeAvg, Operator: ">", Value: 2}}}))
+	assert.Error(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeMatch, Comment: "c",
+		MetricConditions: model.SqlManageMetricConditions{{Metric: MetricNameDBUser, Operator: ">", Value: 2}}}))
+	assert.Error(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeMatch, Comment: "c",
+		MetricConditions: model.SqlManageMetricConditions{{Metric: MetricNameQueryTimeAvg, Operator: "!=", Value: 2}}}))
+	assert.Error(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeAutoClose}))
+	assert.NoError(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeAutoClose, InactiveDays: 30}))
+	assert.Error(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeAutoClose, InactiveDays: 30, Status: model.ProcessStatusIgnored}))
+	assert.NoError(t, ValidateSqlManageTriageRule(&model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeAutoReopen}))
+}
+
+func TestAppendSqlManageRemark(t *testing.T) {
+	assert.Equal(t, "comment", appendSqlManageRemark("", "comment"))
+	assert.Equal(t, "remark\ncomment", appendSqlManageRemark("remark", "comment"))
+	remark := appendSqlManageRemark(strings.Repeat("备", sqlManageRemarkMaxLength), "comment")
+	assert.Equal(t, sqlManageRemarkMaxLength, len([]rune(remark)))
+	assert.True(t, strings.HasSuffix(remark, "备\ncomment"))
+}
+
+func TestEvaluateSqlManageTriageRules(t *testing.T) {
+	high := model.PriorityHigh
+	newTriage := func(level string, status string) *sqlManageTriage {
+		sql := &model.SQLManageRecord{Source: "mysql_slow_log", SchemaName: "orders", AuditLevel: level,
+			Info: model.JSON(`{"query_time_avg": 3}`)}
+		sql.ID = 1
+		return newSqlManageTriage(sql, &model.SQLManageRecordProcess{Status: model.ProcessStatus(status), Remark: "checked"})
+	}
+	match := &model.SqlManageTriageRule{
+		Type:             model.SqlManageTriageRuleTypeMatch,
+		Enabled:          true,
+		Sources:          model.Strings{"mysql_slow_log"},
+		MetricConditions: model.SqlManageMetricConditions{{Metric: MetricNameQueryTimeAvg, Operator: ">", Value: 2}},
+		Assignees:        model.Strings{"100", "101"},
+		SQLPriority:      &high,
+		Comment:          "slow sql of orders",
+	}
+	match.ID = 1
+	reopen := &model.SqlManageTriageRule{Type: model.SqlManageTriageRuleTypeAutoReopen, Enabled: true}
+	reopen.ID = 2
+
+	// the match rule applies its actions
+	triage := newTriage("warn", model.ProcessStatusUnhandled)
+	evaluateSqlManageTriageRules(triage, []*model.SqlManageTriageRule{match, reopen}, nil, "warn")
+	assert.Len(t, triage.records, 3)
+	assert.Equal(t, "100,101", triage.processAttrs["assignees"])
+	assert.Equal(t, "checked\nslow sql of orders", triage.processAttrs["remark"])
+	assert.Equal(t, model.PriorityHigh, triage.sql.Priority.String)
+	assert.True(t, triage.sql.Priority.Valid)
+	assert.NotContains(t, triage.processAttrs, "status")
+
+	// the match rule applied before is skipped
+	triage = newTriage("warn", model.ProcessStatusUnhandled)
+	evaluateSqlManageTriageRules(triage, []*model.SqlManageTriageRule{match}, map[uint]struct{}{match.ID: {}}, "warn")
+	assert.False(t, triage.changed())
+
+	// the disabled rule is skipped
+	disabled := *match
+	disabled.Enabled = false
+	triage = newTriage("warn", model.ProcessStatusUnhandled)
+	evaluateSqlManageTriageRules(triage, []*model.SqlManageTriageRule{&disabled}, nil, "warn")
+	assert.False(t, triage.changed())
+
+	// the closed sql reappears with a higher audit level
+	triage = newTriage("error", model.ProcessStatusSolved)
+	evaluateSqlManageTriageRules(triage, []*model.SqlManageTriageRule{reopen}, nil, "warn")
+	assert.Len(t, triage.records, 1)
+	assert.Equal(t, model.SqlManageTriageFieldStatus, triage.records[0].Field)
+	assert.Equal(t, model.ProcessStatusSolved, triage.records[0].OldValue)
+	assert.Equal(t, model.ProcessStatusUnhandled, triage.processAttrs["status"])
+	assert.Contains(t, triage.records[0].Reason, "audit level rises from warn to error")
+
+	// the closed sql reappears with the same audit level
+	triage = newTriage("warn", model.ProcessStatusIgnored)
+	evaluateSqlManageTriageRules(triage, []*model.SqlManageTriageRule{reopen}, nil, "warn")
+	assert.False(t, triage.changed())
+
+	// the open sql is not reopened
+	triage = newTriage("error", model.ProcessStatusUnhandled)
+	evaluateSqlManageTriageRules(triage, []*model.SqlManageTriageRule{reopen}, nil, "notice")
+	assert.False(t, triage.changed())
+}
@@ -0,0 +1,445 @@
+package auditplan
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	paramKeyProxySQLHost       = "proxysql_host"
+	paramKeyProxySQLPort       = "proxysql_port"
+	paramKeyProxySQLUser       = "proxysql_user"
+	paramKeyProxySQLPassword   = "proxysql_password"
+	paramKeyProxySQLHostgroups = "proxysql_hostgroups"
+)
+
+// ProxySQLQueryDigestTaskV2 collects the SQL from the stats_mysql_query_digest table of the ProxySQL admin interface.
+// The counters of the table are accumulated since ProxySQL starts, so the task keeps the counters of the last
+// collection and reports the increment of them.
+type ProxySQLQueryDigestTaskV2 struct {
+	DefaultTaskV2
+	lastDigests     map[proxySQLDigestKey]*proxySQLDigest
+	lastCollectedAt time.Time
+}
+
+func NewProxySQLQueryDigestTaskV2Fn() func() interface{} {
+	return func() interface{} {
+		return &ProxySQLQueryDigestTaskV2{}
+	}
+}
+
+func (at *ProxySQLQueryDigestTaskV2) InstanceType() string {
+	return InstanceTypeMySQL
+}
+
+func (at *ProxySQLQueryDigestTaskV2) Params(instanceId ...string) params.Params {
+	return []*params.Param{
+		{
+			Key:      paramKeyCollectIntervalMinute,
+			Value:    "5",
+			Type:     params.ParamTypeInt,
+			I18nDesc: locale.Bundle.LocalizeAll(locale.ParamCollectIntervalMinute),
+		},
+		{
+			Key:      paramKeyProxySQLHost,
+			Value:    "",
+			Type:     params.ParamTypeString,
+			I18nDesc: locale.Bundle.LocalizeAll(locale.ParamProxySQLHost),
+		},
+		{
+			Key:      paramKeyProxySQLPort,
+			Value:    "6032",
+			Type:     params.ParamTypeString,
+			I18nDesc: locale.Bundle.LocalizeAll(locale.ParamProxySQLPort),
+		},
+		{
+			Key:      paramKeyProxySQLUser,
+			Value:    "",
+			Type:     params.ParamTypeString,
+			I18nDesc: locale.Bundle.LocalizeAll(locale.ParamProxySQLUser),
+		},
+		{
+			Key:      paramKeyProxySQLPassword,
+			Value:    "",
+			Type:     params.ParamTypePassword,
+			I18nDesc: locale.Bundle.LocalizeAll(locale.ParamProxySQLPassword),
+		},
+		{
+			Key:      paramKeyProxySQLHostgroups,
+			Value:    "",
+			Type:     params.ParamTypeString,
+			I18nDesc: locale.Bundle.LocalizeAll(locale.ParamProxySQLHostgroups),
+		},
+	}
+}
+
+func (at *ProxySQLQueryDigestTaskV2) Metrics() []string {
+	return []string{
+		MetricNameCounter,
+		MetricNameLastReceiveTimestamp,
+		MetricNameQueryTimeAvg,
+		MetricNameQueryTimeMax,
+		MetricNameDBUser,
+		MetricNameEndpoints,
+	}
+}
+
+type proxySQLDigestKey struct {
+	hostgroup     int64
+	schema        string
+	user          string
+	clientAddress string
+	digest        string
+}
+
+type proxySQLDigest struct {
+	proxySQLDigestKey
+	digestText string
+	countStar  int64
+	sumTime    int64 // μs
+	maxTime    int64 // μs
+}
+
+func (d *proxySQLDigest) endpoints() []string {
+	endpoints := []string{fmt.Sprintf("hostgroup=%d,schema=%s,user=%s", d.hostgroup, d.schema, d.user)}
+	// the client address is tracked only if mysql-query_digests_track_hostname is enabled
+	if d.clientAddress != "" {
+		host := d.clientAddress
+		if i := strings.LastIndex(host, ":"); i > 0 {
+			host = host[:i]
+		}
+		endpoints = append(endpoints, host)
+	}
+	return endpoints
+}
+
+// diffProxySQLDigests returns the increment of the digests since the last collection. A digest whose counter is
+// less than the last one is reset by ProxySQL, its current counters are the increment. The max time is only known
+// if it increases, otherwise it is zero.
+func diffProxySQLDigests(last map[proxySQLDigestKey]*proxySQLDigest, current []*proxySQLDigest) []*proxySQLDigest {
+	increments := make([]*proxySQLDigest, 0)
+	for _, d := range current {
+		lastDigest, ok := last[d.proxySQLDigestKey]
+		if !ok || d.countStar < lastDigest.countStar {
+			increments = append(increments, d)
+			continue
+		}
+		if d.countStar == lastDigest.countStar {
+			continue
+		}
+		increment := &proxySQLDigest{
+			proxySQLDigestKey: d.proxySQLDigestKey,
+			digestText:        d.digestText,
+			countStar:         d.countStar - lastDigest.countStar,
+			sumTime:           d.sumTime - lastDigest.sumTime,
+		}
+		if d.maxTime > lastDigest.maxTime {
+			increment.maxTime = d.maxTime
+		}
+		increments = append(increments, increment)
+	}
+	return increments
+}
+
+// parseProxySQLHostgroups parses the hostgroups separated by commas.
+func parseProxySQLHostgroups(hostgroups string) ([]int64, error) {
+	ids := []int64{}
+	for _, hg := range strings.Split(hostgroups, ",") {
+		hg = strings.TrimSpace(hg)
+		if hg == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(hg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hostgroup %s", hg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (at *ProxySQLQueryDigestTaskV2) ExtractSQL(logger *logrus.Entry, ap *AuditPlan, persist *model.Storage) ([]*SQLV2, error) {
+	if ap.InstanceID == "" {
+		return nil, fmt.Errorf("instance is not configured")
+	}
+	host := ap.Params.GetParam(paramKeyProxySQLHost).String()
+	if host == "" {
+		return nil, fmt.Errorf("ProxySQL admin host is not configured")
+	}
+	hostgroups, err := parseProxySQLHostgroups(ap.Params.GetParam(paramKeyProxySQLHostgroups).String())
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := executor.NewExecutor(logger, &driverV2.DSN{
+		Host:     host,
+		Port:     ap.Params.GetParam(paramKeyProxySQLPort).String(),
+		User:     ap.Params.GetParam(paramKeyProxySQLUser).String(),
+		Password: ap.Params.GetParam(paramKeyProxySQLPassword).String(),
+	}, "")
+	if err != nil {
+		return nil, fmt.Errorf("connect to ProxySQL admin interface fail, error: %v", err)
+	}
+	defer db.Db.Close()
+
+	query := `SELECT hostgroup, schemaname, username, client_address, digest, digest_text, count_star, sum_time, max_time FROM stats_mysql_query_digest`
+	if len(hostgroups) > 0 {
+		ids := make([]string, 0, len(hostgroups))
+		for _, id := range hostgroups {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		query += fmt.Sprintf(" WHERE hostgroup IN (%s)", strings.Join(ids, ","))
+	}
+	res, err := db.Db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("query stats_mysql_query_digest failed, error: %v", err)
+	}
+	current := make([]*proxySQLDigest, 0, len(res))
+	for _, row := range res {
+		current = append(current, convertProxySQLDigest(row))
+	}
+
+	now := time.Now()
+	last, lastCollectedAt := at.lastDigests, at.lastCollectedAt
+	at.lastDigests = make(map[proxySQLDigestKey]*proxySQLDigest, len(current))
+	for _, d := range current {
+		at.lastDigests[d.proxySQLDigestKey] = d
+	}
+	at.lastCollectedAt = now
+	// the counters before the task starts are unknown, the first collection is taken as the baseline
+	if last == nil {
+		logger.Infof("take %d digests of ProxySQL as the baseline", len(current))
+		return nil, nil
+	}
+	return at.convertToSQLs(logger, ap, diffProxySQLDigests(last, current), lastCollectedAt, now), nil
+}
+
+func convertProxySQLDigest(row map[string]sql.NullString) *proxySQLDigest {
+	parseInt := func(column string) int64 {
+		i, _ := strconv.ParseInt(row[column].String, 10, 64)
+		return i
+	}
+	return &proxySQLDigest{
+		proxySQLDigestKey: proxySQLDigestKey{
+			hostgroup:     parseInt("hostgroup"),
+			schema:        row["schemaname"].String,
+			user:          row["username"].String,
+			clientAddress: row["client_address"].String,
+			digest:        row["digest"].String,
+		},
+		digestText: row["digest_text"].String,
+		countStar:  parseInt("count_star"),
+		sumTime:    parseInt("sum_time"),
+		maxTime:    parseInt("max_time"),
+	}
+}
+
+func (at *ProxySQLQueryDigestTaskV2) convertToSQLs(logger *logrus.Entry, ap *AuditPlan, digests []*proxySQLDigest, beginAt, endAt time.Time) []*SQLV2 {
+	cache := NewSQLV2Cache()
+	for _, d := range digests {
+		if d.digestText == "" || d.countStar <= 0 {
+			continue
+		}
+		sqlV2 := &SQLV2{
+			Source:      ap.Type,
+			SourceId:    strconv.FormatUint(uint64(ap.InstanceAuditPlanId), 10),
+			AuditPlanId: strconv.FormatUint(uint64(ap.ID), 10),
+			ProjectId:   ap.ProjectId,
+			InstanceID:  ap.InstanceID,
+			SchemaName:  d.schema,
+			SQLContent:  d.digestText,
+		}
+		fp, err := util.Fingerprint(d.digestText, true)
+		if err != nil || fp == "" {
+			logger.Warnf("get sql finger print failed, err: %v, sql: %s", err, d.digestText)
+			fp = d.digestText
+		}
+		sqlV2.Fingerprint = fp
+
+		info := NewMetrics()
+		info.SetInt(MetricNameCounter, d.countStar)
+		info.SetFloat(MetricNameQueryTimeAvg, float64(d.sumTime)/float64(d.countStar)/1000/1000)
+		if d.maxTime > 0 {
+			info.SetFloat(MetricNameQueryTimeMax, float64(d.maxTime)/1000/1000)
+		}
+		info.SetString(MetricNameDBUser, d.user)
+		info.SetStringArray(MetricNameEndpoints, d.endpoints())
+		info.SetString(MetricNameRecordBeginAt, beginAt.Format(time.RFC3339))
+		info.SetString(MetricNameLastReceiveTimestamp, endAt.Format(time.RFC3339))
+		sqlV2.Info = info
+		sqlV2.GenSQLId()
+		if err := at.AggregateSQL(cache, sqlV2); err != nil {
+			logger.Warnf("aggregate sql failed error : %v", err)
+			continue
+		}
+	}
+	return cache.GetSQLs()
+}
+
+// mergeSQL merges the digests of the same SQL from the different hostgroups, users or clients.
+func (at *ProxySQLQueryDigestTaskV2) mergeSQL(originSQL, mergedSQL *SQLV2) {
+	if originSQL.SQLId != mergedSQL.SQLId {
+		return
+	}
+	originCounter := originSQL.Info.Get(MetricNameCounter).Int()
+	mergedCounter := mergedSQL.Info.Get(MetricNameCounter).Int()
+	counter := originCounter + mergedCounter
+	originSQL.Info.SetInt(MetricNameCounter, counter)
+
+	queryTimeAvg := (originSQL.Info.Get(MetricNameQueryTimeAvg).Float()*float64(originCounter) +
+		mergedSQL.Info.Get(MetricNameQueryTimeAvg).Float()*float64(mergedCounter)) / float64(counter)
+	originSQL.Info.SetFloat(MetricNameQueryTimeAvg, queryTimeAvg)
+
+	if mergedMax := mergedSQL.Info.Get(MetricNameQueryTimeMax).Float(); mergedMax > originSQL.Info.Get(MetricNameQueryTimeMax).Float() {
+		originSQL.Info.SetFloat(MetricNameQueryTimeMax, mergedMax)
+	}
+
+	endpoints := append(originSQL.Info.Get(MetricNameEndpoints).StringArray(), mergedSQL.Info.Get(MetricNameEndpoints).StringArray()...)
+	originSQL.Info.SetStringArray(MetricNameEndpoints, utils.RemoveDuplicate(endpoints))
+}
+
+func (at *ProxySQLQueryDigestTaskV2) AggregateSQL(cache SQLV2Cacher, sql *SQLV2) error {
+	originSQL, exist, err := cache.GetSQL(sql.SQLId)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		cache.CacheSQL(sql)
+		return nil
+	}
+	at.mergeSQL(originSQL, sql)
+	return nil
+}
+
+func (at *ProxySQLQueryDigestTaskV2) Audit(sqls []*model.SQLManageRecord) (*AuditResultResp, error) {
+	return auditSQLs(sqls)
+}
+
+func (at *ProxySQLQueryDigestTaskV2) Head(ap *AuditPlan) []Head {
+	return []Head{
+		{
+			Name: "fingerprint",
+			Desc: locale.ApSQLFingerprint,
+			Type: "sql",
+		},
+		{
+			Name: "priority",
+			Desc: locale.ApPriority,
+		},
+		{
+			Name: model.AuditResultName,
+			Desc: model.AuditResultDesc,
+		},
+		{
+			Name:     MetricNameCounter,
+			Desc:     locale.ApMetricNameCounter,
+			Sortable: true,
+		},
+		{
+			Name:     MetricNameQueryTimeAvg,
+			Desc:     locale.ApMetricNameQueryTimeAvg,
+			Sortable: true,
+		},
+		{
+			Name:     MetricNameQueryTimeMax,
+			Desc:     locale.ApMetricNameQueryTimeMax,
+			Sortable: true,
+		},
+		{
+			Name: MetricNameDBUser,
+			Desc: locale.ApMetricNameDBUser,
+		},
+		{
+			Name: MetricNameEndpoints,
+			Desc: locale.ApMetricNameEndpoints,
+		},
+		{
+			Name: "schema_name",
+			Desc: locale.ApSchema,
+		},
+		{
+			Name: MetricNameLastReceiveTimestamp,
+			Desc: locale.ApMetricNameLastReceiveTimestamp,
+			Type: "time",
+		},
+	}
+}
+
+func (at *ProxySQLQueryDigestTaskV2) Filters(ctx context.Context, logger *logrus.Entry, ap *AuditPlan, persist *model.Storage) []FilterMeta {
+	return []FilterMeta{
+		{
+			Name:            "sql",
+			Desc:            locale.ApSQLStatement,
+			FilterInputType: FilterInputTypeString,
+			FilterOpType:    FilterOpTypeEqual,
+		},
+		{
+			Name:            "rule_name",
+			Desc:            locale.ApRuleName,
+			FilterInputType: FilterInputTypeString,
+			FilterOpType:    FilterOpTypeEqual,
+			FilterTips:      GetSqlManagerRuleTips(ctx, logger, ap.ID, persist),
+		},
+		{
+			Name:            "priority",
+			Desc:            locale.ApPriority,
+			FilterInputType: FilterInputTypeString,
+			FilterOpType:    FilterOpTypeEqual,
+			FilterTips:      GetSqlManagerPriorityTips(ctx, logger),
+		},
+		{
+			Name:            MetricNameDBUser,
+			Desc:            locale.ApMetricNameDBUser,
+			FilterInputType: FilterInputTypeString,
+			FilterOpType:    FilterOpTypeEqual,
+			FilterTips:      GetSqlManagerMetricTips(logger, ap.ID, persist, MetricNameDBUser),
+		},
+	}
+}
+
+func (at *ProxySQLQueryDigestTaskV2) GetSQLData(ctx context.Context, ap *AuditPlan, persist *model.Storage, filters []Filter, orderBy string, isAsc bool, limit, offset int) ([]map[string] /* head name */ string, uint64, error) {
+	auditPlanSQLs, count, err := persist.GetInstanceAuditPlanSQLsByReqV2(ap.ID, ap.Type, limit, offset, checkAndGetOrderByName(at.Head(ap), orderBy), isAsc, genArgsByFilters(filters))
+	if err != nil {
+		return nil, count, err
+	}
+	rows := make([]map[string]string, 0, len(auditPlanSQLs))
+	for _, sql := range auditPlanSQLs {
+		data, err := sql.Info.OriginValue()
+		if err != nil {
+			return nil, 0, err
+		}
+		info := LoadMetrics(data, at.Metrics())
+		endpoints := info.Get(MetricNameEndpoints).StringArray()
+		sort.Strings(endpoints)
+		rows = append(rows, map[string]string{
+			"sql":                          sql.SQLContent,
+			"fingerprint":                  sql.Fingerprint,
+			"id":                           sql.AuditPlanSqlId,
+			"priority":                     sql.Priority.String,
+			"schema_name":                  sql.Schema,
+			MetricNameCounter:              strconv.Itoa(int(info.Get(MetricNameCounter).Int())),
+			MetricNameQueryTimeAvg:         fmt.Sprintf("%v", utils.Round(info.Get(MetricNameQueryTimeAvg).Float(), 6)),
+			MetricNameQueryTimeMax:         fmt.Sprintf("%v", utils.Round(info.Get(MetricNameQueryTimeMax).Float(), 6)),
+			MetricNameDBUser:               info.Get(MetricNameDBUser).String(),
+			MetricNameEndpoints:            strings.Join(endpoints, "; "),
+			MetricNameLastReceiveTimestamp: info.Get(MetricNameLastReceiveTimestamp).String(),
+			model.AuditResultName:          sql.AuditResult.GetAuditJsonStrByLangTag(locale.Bundle.GetLangTagFromCtx(ctx)),
+			model.AuditStatus:              sql.AuditStatus,
+		})
+	}
+	return rows, count, nil
+}
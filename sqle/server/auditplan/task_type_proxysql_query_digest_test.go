@@ -0,0 +1,90 @@
+package auditplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProxySQLDigest(hostgroup int64, user, digest, text string, count, sumTime, maxTime int64) *proxySQLDigest {
+	return &proxySQLDigest{
+		proxySQLDigestKey: proxySQLDigestKey{hostgroup: hostgroup, schema: "db1", user: user, digest: digest},
+		digestText:        text,
+		countStar:         count,
+		sumTime:           sumTime,
+		maxTime:           maxTime,
+	}
+}
+
+func TestDiffProxySQLDigests(t *testing.T) {
+	last := map[proxySQLDigestKey]*proxySQLDigest{}
+	for _, d := range []*proxySQLDigest{
+		newTestProxySQLDigest(10, "app", "0x1", "select * from t1 where id = ?", 10, 1000, 300),
+		newTestProxySQLDigest(10, "app", "0x2", "select * from t2 where id = ?", 10, 1000, 300),
+		newTestProxySQLDigest(10, "app", "0x3", "select * from t3 where id = ?", 10, 1000, 300),
+	} {
+		last[d.proxySQLDigestKey] = d
+	}
+
+	increments := diffProxySQLDigests(last, []*proxySQLDigest{
+		// increased without a new max time
+		newTestProxySQLDigest(10, "app", "0x1", "select * from t1 where id = ?", 15, 1500, 300),
+		// not executed since the last collection
+		newTestProxySQLDigest(10, "app", "0x2", "select * from t2 where id = ?", 10, 1000, 300),
+		// reset by ProxySQL
+		newTestProxySQLDigest(10, "app", "0x3", "select * from t3 where id = ?", 2, 800, 500),
+		// new digest
+		newTestProxySQLDigest(20, "report", "0x1", "select * from t1 where id = ?", 3, 600, 400),
+	})
+	assert.Len(t, increments, 3)
+	assert.Equal(t, int64(5), increments[0].countStar)
+	assert.Equal(t, int64(500), increments[0].sumTime)
+	assert.Equal(t, int64(0), increments[0].maxTime)
+	assert.Equal(t, "0x3", increments[1].digest)
+	assert.Equal(t, int64(2), increments[1].countStar)
+	assert.Equal(t, int64(500), increments[1].maxTime)
+	assert.Equal(t, int64(20), increments[2].hostgroup)
+	assert.Equal(t, int64(3), increments[2].countStar)
+}
+
+func TestParseProxySQLHostgroups(t *testing.T) {
+	hostgroups, err := parseProxySQLHostgroups(" 10, 20,,")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{10, 20}, hostgroups)
+
+	hostgroups, err = parseProxySQLHostgroups("")
+	assert.NoError(t, err)
+	assert.Empty(t, hostgroups)
+
+	_, err = parseProxySQLHostgroups("10;20")
+	assert.Error(t, err)
+}
+
+func TestProxySQLQueryDigestConvertToSQLs(t *testing.T) {
+	task := NewProxySQLQueryDigestTaskV2Fn()().(*ProxySQLQueryDigestTaskV2)
+	ap := &AuditPlan{ID: 1, InstanceAuditPlanId: 2, ProjectId: "1", InstanceID: "3", Type: TypeProxySQLQueryDigest}
+	writer := newTestProxySQLDigest(10, "app", "0x1", "select * from t1 where id = ?", 2, 2000000, 1500000)
+	writer.clientAddress = "10.0.0.1:52314"
+	reader := newTestProxySQLDigest(20, "report", "0x1", "select * from t1 where id = ?", 6, 6000000, 0)
+	beginAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sqls := task.convertToSQLs(logrus.WithField("test", "test"), ap, []*proxySQLDigest{
+		writer,
+		reader,
+		newTestProxySQLDigest(10, "app", "0x2", "", 1, 100, 100),
+	}, beginAt, beginAt.Add(5*time.Minute))
+	// the digests of the same sql from the different hostgroups are merged
+	assert.Len(t, sqls, 1)
+	sql := sqls[0]
+	assert.Equal(t, "db1", sql.SchemaName)
+	assert.Equal(t, "select * from t1 where id = ?", sql.SQLContent)
+	assert.Equal(t, int64(8), sql.Info.Get(MetricNameCounter).Int())
+	assert.InDelta(t, 1.0, sql.Info.Get(MetricNameQueryTimeAvg).Float(), 0.000001)
+	assert.InDelta(t, 1.5, sql.Info.Get(MetricNameQueryTimeMax).Float(), 0.000001)
+	assert.Equal(t, "app", sql.Info.Get(MetricNameDBUser).String())
+	assert.Equal(t, []string{"hostgroup=10,schema=db1,user=app", "10.0.0.1", "hostgroup=20,schema=db1,user=report"},
+		sql.Info.Get(MetricNameEndpoints).StringArray())
+	assert.Equal(t, beginAt.Format(time.RFC3339), sql.Info.Get(MetricNameRecordBeginAt).String())
+}
@@ -3,6 +3,7 @@ package auditplan
 import (
 	"context"
 	e "errors"
+	"fmt"
 	"net"
 	"regexp"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 	"github.com/actiontech/sqle/sqle/dms"
 	sqleErr "github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/metrics"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/sirupsen/logrus"
@@ -222,6 +224,7 @@ func (at *TaskWrapper) stopCollect() error {
 	at.cancel <- struct{}{}
 	at.isStarted = false
 	at.WaitGroup.Wait()
+	metrics.AuditPlanLastCollection.Delete(fmt.Sprint(at.ap.ID), at.ap.Type)
 	at.logger.Infof("stop task")
 	return nil
 }
@@ -233,10 +236,13 @@ func (at *TaskWrapper) extractSQL() {
 	}()
 	collectionTime := time.Now()
 	sqls, err := at.collect.ExtractSQL(at.logger, at.ap, at.persist)
+	metrics.ObserveSince(metrics.AuditPlanCollectionDuration, collectionTime, at.ap.Type)
 	if err != nil {
+		metrics.AuditPlanCollectionErrors.Inc(at.ap.Type)
 		at.logger.Errorf("extract sql failed, %v", err)
 		return
 	}
+	metrics.AuditPlanLastCollection.Set(float64(collectionTime.Unix()), fmt.Sprint(at.ap.ID), at.ap.Type)
 	// todo: 对于mysql慢日志类型，采集来源是scannerd的任务的时间不应该在此处更新
 	err = at.persist.UpdateAuditPlanLastCollectionTime(at.ap.ID, collectionTime)
 	if err != nil {
@@ -309,6 +315,12 @@ func (at *TaskWrapper) pushSQLToManagerSQLQueue(sqlList []*model.SQLManageQueue,
 		return err
 	}
 
+	for _, sqlQueue := range SqlQueueList {
+		err = createSqlManageRuntimeMetricRecord(at.persist, sqlQueue)
+		if err != nil {
+			log.Logger().Errorf("createSqlManageRuntimeMetricRecord: %v", err)
+		}
+	}
 	// 目前只支持MySQL
 	if ap.DBType == driverV2.DriverTypeMySQL {
 		for _, sqlQueue := range SqlQueueList {
@@ -674,9 +686,60 @@ func createSqlManageCostMetricRecord(sqlManageQueue *model.SQLManageQueue, insta
 		log.Logger().Errorf("createSqlManageCostMetricRecord: create sqlManageMetricExecutePlanRecord error sqlId: %v", sqlManageQueue.SQLID)
 		return err
 	}
+	if err = trackSqlManageExecutePlan(log.NewEntry(), sqlManageQueue, sqlManageMetricRecord.ID, sqlManageMetricExecutePlanRecords, nowTime); err != nil {
+		log.Logger().Errorf("createSqlManageCostMetricRecord: track execute plan error sqlId: %v, error: %v", sqlManageQueue.SQLID, err)
+	}
 	return nil
 }
 
+// SqlManageRuntimeMetrics are the metrics reported by collectors which are kept as history,
+// the history is used to detect the regression of SQL.
+var SqlManageRuntimeMetrics = []string{MetricNameQueryTimeAvg, MetricNameQueryTimeMax, MetricNameRowExaminedAvg}
+
+// createSqlManageRuntimeMetricRecord records the runtime metrics of the SQL collected in this round
+func createSqlManageRuntimeMetricRecord(persist *model.Storage, sqlManageQueue *model.SQLManageQueue) error {
+	info, err := sqlManageQueue.Info.OriginValue()
+	if err != nil {
+		return err
+	}
+	runtimeMetrics := LoadMetrics(info, append([]string{MetricNameCounter, MetricNameRecordBeginAt}, SqlManageRuntimeMetrics...))
+	values := make([]*model.SqlManageMetricValue, 0, len(SqlManageRuntimeMetrics))
+	for _, name := range SqlManageRuntimeMetrics {
+		if metric := runtimeMetrics.Get(name); metric != nil {
+			values = append(values, &model.SqlManageMetricValue{
+				MetricName:  name,
+				MetricValue: metric.Float(),
+			})
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	executionCount := runtimeMetrics.Get(MetricNameCounter).Int()
+	if executionCount <= 0 {
+		executionCount = 1
+	}
+	nowTime := time.Now()
+	// the collectors reporting the increment of the counters set the begin time of the increment
+	beginTime := nowTime
+	if t, err := time.Parse(time.RFC3339, runtimeMetrics.Get(MetricNameRecordBeginAt).String()); err == nil && t.Before(nowTime) {
+		beginTime = t
+	}
+	record := &model.SqlManageMetricRecord{
+		SQLID:          sqlManageQueue.SQLID,
+		ExecutionCount: int(executionCount),
+		RecordBeginAt:  beginTime,
+		RecordEndAt:    nowTime,
+	}
+	if err = persist.Create(record); err != nil {
+		return err
+	}
+	for _, value := range values {
+		value.SqlManageMetricRecordID = record.ID
+	}
+	return persist.Create(values)
+}
+
 // buildSqlManageMetricExecutePlanRecord 构建执行计划记录-批量
 func buildSqlManageMetricExecutePlanRecord(explainResultRows [][]string, sqlManageMetricRecordID uint) []model.SqlManageMetricExecutePlanRecord {
 	sqlManageMetricExecutePlanRecords := make([]model.SqlManageMetricExecutePlanRecord, 0)
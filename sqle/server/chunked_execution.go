@@ -0,0 +1,290 @@
+package server
+
+import (
+	_errors "errors"
+	"fmt"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/tracing"
+)
+
+// ErrChunkedExecutionPaused stops the execution of the task after a chunked SQL is paused, the SQLs after
+// it are kept initialized, so the task can be resumed by re-executing the paused SQL and the SQLs after it.
+var ErrChunkedExecutionPaused = fmt.Errorf("chunked execution is paused")
+
+// SetExecuteSQLChunked switches whether the SQL is executed in primary key range batches.
+func SetExecuteSQLChunked(task *model.Task, executeSQL *model.ExecuteSQL, chunked bool, chunkSize, sleepMillisecond uint64) error {
+	if chunked {
+		if task.ExecMode == model.ExecModeSqlFile {
+			return errors.New(errors.DataInvalid, fmt.Errorf("chunked execution is not supported in sql file execute mode"))
+		}
+		if !isChunkedExecutionSupported(task.DBType) {
+			return errors.New(errors.DataInvalid, fmt.Errorf("chunked execution is not supported by %s", task.DBType))
+		}
+		if executeSQL.SQLType != "" && executeSQL.SQLType != driverV2.SQLTypeDML {
+			return errors.New(errors.DataInvalid, fmt.Errorf("only UPDATE and DELETE can be executed in chunks"))
+		}
+		if chunkSize == 0 {
+			chunkSize = model.DefaultChunkSize
+		}
+	}
+	switch executeSQL.ExecStatus {
+	case model.SQLExecuteStatusInitialized, model.SQLExecuteStatusPaused, model.SQLExecuteStatusFailed:
+	default:
+		return errors.New(errors.DataInvalid, fmt.Errorf("the execute mode can not be changed when the SQL is %s", executeSQL.ExecStatus))
+	}
+	return model.GetStorage().SetExecuteSQLChunked(executeSQL, chunked, chunkSize, sleepMillisecond)
+}
+
+// PauseChunkedExecution asks the running chunked execution to pause after the current batch.
+func PauseChunkedExecution(executeSQL *model.ExecuteSQL) error {
+	st := model.GetStorage()
+	ce, exist, err := st.GetChunkedExecutionByExecuteSQLId(executeSQL.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.New(errors.DataNotExist, fmt.Errorf("the SQL is not executed in chunks"))
+	}
+	updated, err := st.UpdateChunkedExecutionStatusIf(ce.ID, []string{model.ChunkedExecutionStatusRunning}, model.ChunkedExecutionStatusPausing)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New(errors.DataInvalid, fmt.Errorf("the chunked execution is not running"))
+	}
+	return nil
+}
+
+// isChunkedExecutionSupported checks whether the plugin implements driver.ChunkedDMLExecutor.
+func isChunkedExecutionSupported(dbType string) bool {
+	return driver.IsChunkedDMLSupported(dbType)
+}
+
+// execChunkedSQL executes the UPDATE/DELETE in primary key range batches, each batch is committed and the
+// progress is saved after it, so the execution resumes from the last completed range. The SQL is executed
+// as a whole if it is not eligible. If backup is true, each batch is backed up before it is executed.
+func (a *action) execChunkedSQL(executeSQL *model.ExecuteSQL, backup bool) error {
+	st := model.GetStorage()
+	execWhole := func() error {
+		if backup {
+			if err := a.backupSQL(executeSQL); err != nil {
+				return err
+			}
+		}
+		return a.execSQL(executeSQL)
+	}
+	executor, ok := a.plugin.(driver.ChunkedDMLExecutor)
+	if !ok {
+		a.entry.Warnf("plugin %s does not support chunked execution, execute sql %d as a whole", a.task.DBType, executeSQL.ID)
+		return execWhole()
+	}
+	ce, exist, err := st.GetChunkedExecutionByExecuteSQLId(executeSQL.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		ce = &model.ChunkedExecution{
+			TaskId:           a.task.ID,
+			ExecuteSQLId:     executeSQL.ID,
+			ChunkSize:        model.DefaultChunkSize,
+			SleepMillisecond: model.DefaultChunkSleepMillisecond,
+		}
+	}
+	if ce.Status == model.ChunkedExecutionStatusFinished {
+		// all the batches are committed, the SQL must not be executed again when the task is resumed
+		a.entry.Infof("chunked execution of sql %d is finished, skip it", executeSQL.ID)
+		return a.finishChunkedSQL(executeSQL)
+	}
+	if ce.Status == model.ChunkedExecutionStatusNotEligible {
+		return execWhole()
+	}
+
+	if !ce.IsPlanned() {
+		plan, err := executor.PlanChunkedDML(a.ctx, executeSQL.Content)
+		if _errors.Is(err, driver.ErrNotChunkable) {
+			a.entry.Infof("sql %d is executed as a whole, %v", executeSQL.ID, err)
+			ce.Status = model.ChunkedExecutionStatusNotEligible
+			ce.Error = err.Error()
+			if err := st.Save(ce); err != nil {
+				return err
+			}
+			return execWhole()
+		}
+		if err != nil {
+			return a.failChunkedSQL(ce, executeSQL, err)
+		}
+		ce.Schema, ce.Table, ce.PKColumn = plan.Schema, plan.Table, plan.PKColumn
+		ce.MinPK, ce.MaxPK = plan.MinPK, plan.MaxPK
+	}
+	now := time.Now()
+	if ce.StartedAt == nil {
+		ce.StartedAt = &now
+	}
+	ce.Status = model.ChunkedExecutionStatusRunning
+	ce.Error = ""
+	if err := st.Save(ce); err != nil {
+		return err
+	}
+	if err := st.UpdateExecuteSqlStatus(&executeSQL.BaseSQL, model.SQLExecuteStatusDoing, ""); err != nil {
+		return err
+	}
+
+	plan := &driver.ChunkedDMLPlan{
+		Schema:   ce.Schema,
+		Table:    ce.Table,
+		PKColumn: ce.PKColumn,
+		MinPK:    ce.MinPK,
+		MaxPK:    ce.MaxPK,
+	}
+	entry := a.entry.WithField("execute_sql_id", executeSQL.ID)
+	for {
+		status, err := st.GetChunkedExecutionStatus(ce.ID)
+		if err != nil {
+			return err
+		}
+		if status == model.ChunkedExecutionStatusPausing {
+			return a.pauseChunkedSQL(ce, executeSQL)
+		}
+
+		if err := a.waitForThrottle(executeSQL); err != nil {
+			if a.hasTermination() {
+				return a.terminateChunkedSQL(ce, executeSQL, err)
+			}
+			return a.failChunkedSQL(ce, executeSQL, err)
+		}
+
+		chunk, err := executor.NextDMLChunk(a.ctx, executeSQL.Content, plan, ce.LastPK, ce.ChunkSize)
+		if err != nil {
+			return a.failChunkedSQL(ce, executeSQL, err)
+		}
+		if chunk.Done {
+			break
+		}
+		if chunk.MaxPK != "" {
+			entry.Infof("chunked execution continues with the rows inserted after the plan, primary key range [%s, %s]", chunk.MinPK, chunk.MaxPK)
+			ce.MinPK, ce.MaxPK = chunk.MinPK, chunk.MaxPK
+			plan.MinPK, plan.MaxPK = chunk.MinPK, chunk.MaxPK
+		}
+		if backup {
+			if err := a.backupChunk(executeSQL, chunk.SQL); err != nil {
+				return a.failChunkedSQL(ce, executeSQL, err)
+			}
+		}
+
+		begin := time.Now()
+		ctx, span := tracing.Start(a.ctx, "sqled.exec_chunk", tracing.WithAttributes(
+			tracing.Attr("execute_sql_id", executeSQL.ID),
+			tracing.Attr("batch_no", ce.BatchCount+1),
+		))
+		execResult, err := a.plugin.Exec(ctx, chunk.SQL)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			if a.hasTermination() && isConnectionTerminatedError(err, a.task.DBType) {
+				// the batch is rolled back, so the execution can resume from the last completed range
+				return a.terminateChunkedSQL(ce, executeSQL, err)
+			}
+			return a.failChunkedSQL(ce, executeSQL, err)
+		}
+		var rowsAffected int64
+		if execResult != nil {
+			rowsAffected, _ = execResult.RowsAffected()
+		}
+
+		ce.BatchCount++
+		ce.LastPK = chunk.EndPK
+		ce.RowAffects += rowsAffected
+		batch := &model.ChunkedExecutionBatch{
+			ChunkedExecutionId: ce.ID,
+			BatchNo:            ce.BatchCount,
+			StartPK:            chunk.StartPK,
+			EndPK:              chunk.EndPK,
+			RowAffects:         rowsAffected,
+			DurationMs:         time.Since(begin).Milliseconds(),
+			ExecutedAt:         begin,
+		}
+		if err := st.SaveChunkedExecutionBatch(ce, batch); err != nil {
+			return err
+		}
+		executeSQL.RowAffects = ce.RowAffects
+		if err := st.UpdateExecuteSQLById(fmt.Sprintf("%v", executeSQL.ID), map[string]interface{}{"row_affects": ce.RowAffects}); err != nil {
+			return err
+		}
+		entry.Infof("chunked execution batch %d done, primary key range (%s, %s], %d rows affected, %d rows affected in total",
+			batch.BatchNo, batch.StartPK, batch.EndPK, batch.RowAffects, ce.RowAffects)
+
+		if a.hasTermination() {
+			return a.terminateChunkedSQL(ce, executeSQL, fmt.Errorf("terminated by user"))
+		}
+		time.Sleep(time.Duration(ce.SleepMillisecond) * time.Millisecond)
+	}
+
+	finishedAt := time.Now()
+	ce.Status = model.ChunkedExecutionStatusFinished
+	ce.FinishedAt = &finishedAt
+	if err := st.Save(ce); err != nil {
+		return err
+	}
+	return a.finishChunkedSQL(executeSQL)
+}
+
+func (a *action) finishChunkedSQL(executeSQL *model.ExecuteSQL) error {
+	executeSQL.ExecStatus = model.SQLExecuteStatusSucceeded
+	executeSQL.ExecResult = model.TaskExecResultOK
+	return model.GetStorage().Save(executeSQL)
+}
+
+// backupChunk backs up the rows of the batch, the batch is backed up as the SQL restricted to its range.
+func (a *action) backupChunk(executeSQL *model.ExecuteSQL, chunkSQL string) error {
+	chunk := *executeSQL
+	chunk.Content = chunkSQL
+	return a.backupSQL(&chunk)
+}
+
+func (a *action) pauseChunkedSQL(ce *model.ChunkedExecution, executeSQL *model.ExecuteSQL) error {
+	st := model.GetStorage()
+	if _, err := st.UpdateChunkedExecutionStatusIf(ce.ID, []string{model.ChunkedExecutionStatusPausing}, model.ChunkedExecutionStatusPaused); err != nil {
+		return err
+	}
+	executeSQL.ExecStatus = model.SQLExecuteStatusPaused
+	executeSQL.ExecResult = fmt.Sprintf("paused after %d batches, %d rows affected, the last completed primary key is %s",
+		ce.BatchCount, ce.RowAffects, ce.LastPK)
+	if err := st.Save(executeSQL); err != nil {
+		return err
+	}
+	a.entry.WithField("execute_sql_id", executeSQL.ID).Info(executeSQL.ExecResult)
+	return ErrChunkedExecutionPaused
+}
+
+func (a *action) terminateChunkedSQL(ce *model.ChunkedExecution, executeSQL *model.ExecuteSQL, err error) error {
+	st := model.GetStorage()
+	ce.Status = model.ChunkedExecutionStatusPaused
+	ce.Error = err.Error()
+	if err := st.Save(ce); err != nil {
+		return err
+	}
+	executeSQL.ExecStatus = model.SQLExecuteStatusTerminateSucc
+	executeSQL.ExecResult = terminatedExecResult(err)
+	if err := st.Save(executeSQL); err != nil {
+		return err
+	}
+	return err
+}
+
+func (a *action) failChunkedSQL(ce *model.ChunkedExecution, executeSQL *model.ExecuteSQL, err error) error {
+	st := model.GetStorage()
+	ce.Status = model.ChunkedExecutionStatusFailed
+	ce.Error = err.Error()
+	if saveErr := st.Save(ce); saveErr != nil {
+		return saveErr
+	}
+	if persistErr := a.persistOnlineFailure(executeSQL, model.OnlineFailStageSQLExecute, err.Error()); persistErr != nil {
+		a.entry.Errorf("persist online failure after chunked execution failed, task=%v sql=%v err=%v", a.task.ID, executeSQL.ID, persistErr)
+	}
+	return err
+}
@@ -14,6 +14,7 @@ type DingTalkJob struct {
 }
 
 func NewDingTalkJob(entry *logrus.Entry) ServerJob {
+	entry = entry.WithField("job", "ding_talk")
 	d := new(DingTalkJob)
 	d.BaseJob = *NewBaseJob(entry, 60*time.Second, d.dingTalkRotation)
 	return d
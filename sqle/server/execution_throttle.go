@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+// ErrExecutionThrottleTimeout aborts the execution when the instance does not recover in the timeout of the throttle.
+var ErrExecutionThrottleTimeout = fmt.Errorf("execution is throttled until timeout")
+
+type executionThrottler struct {
+	cfg    *model.ExecutionThrottle
+	prober driver.ExecutionLoadProber
+}
+
+// initExecutionThrottler loads the throttle settings of the instance, the throttle is disabled if the settings
+// have no limit or the plugin can not report the load of the instance.
+func (a *action) initExecutionThrottler() error {
+	cfg, exist, err := model.GetStorage().GetExecutionThrottleByInstanceId(fmt.Sprintf("%d", a.task.InstanceId))
+	if err != nil {
+		return err
+	}
+	if !exist || !cfg.HasLimit() {
+		return nil
+	}
+	prober, ok := a.plugin.(driver.ExecutionLoadProber)
+	if !ok {
+		a.entry.Warnf("plugin %s does not support execution throttle, the throttle is ignored", a.task.DBType)
+		return nil
+	}
+	if cfg.CheckIntervalSecond == 0 {
+		cfg.CheckIntervalSecond = model.DefaultThrottleCheckIntervalSecond
+	}
+	if cfg.TimeoutSecond == 0 {
+		cfg.TimeoutSecond = model.DefaultThrottleTimeoutSecond
+	}
+	a.throttler = &executionThrottler{cfg: cfg, prober: prober}
+	return nil
+}
+
+// check returns the reasons to throttle, it is empty if the instance is healthy. The throttle fails closed:
+// the execution is also throttled if the load or the lag of a replica can not be probed, since the instance
+// may be overloaded, and it is aborted if the probe keeps failing until the timeout.
+func (t *executionThrottler) check(a *action) (reasons []string, load *driver.ExecutionLoad) {
+	lagLimited := t.cfg.MaxReplicationLagSec > 0
+	var replicaDSNs []string
+	if lagLimited {
+		replicaDSNs = t.cfg.ReplicaDSNs
+	}
+	load, err := t.prober.ProbeExecutionLoad(a.ctx, replicaDSNs, lagLimited && t.cfg.DiscoverReplicas)
+	if err != nil {
+		return []string{fmt.Sprintf("probe the load of the instance failed: %v", err)}, &driver.ExecutionLoad{}
+	}
+	if t.cfg.MaxThreadsRunning > 0 && load.ThreadsRunning > int64(t.cfg.MaxThreadsRunning) {
+		reasons = append(reasons, fmt.Sprintf("Threads_running %d exceeds %d", load.ThreadsRunning, t.cfg.MaxThreadsRunning))
+	}
+	if !lagLimited {
+		return reasons, load
+	}
+	for _, replica := range load.Replicas {
+		if replica.LagSecond < 0 {
+			reasons = append(reasons, fmt.Sprintf("replication lag of %s is unknown: %s", replica.Replica, replica.Error))
+		} else if replica.LagSecond > int64(t.cfg.MaxReplicationLagSec) {
+			reasons = append(reasons, fmt.Sprintf("replication lag of %s is %ds, exceeds %ds", replica.Replica, replica.LagSecond, t.cfg.MaxReplicationLagSec))
+		}
+	}
+	return reasons, load
+}
+
+func maxReplicationLag(load *driver.ExecutionLoad) int64 {
+	var lag int64
+	for _, replica := range load.Replicas {
+		if replica.LagSecond > lag {
+			lag = replica.LagSecond
+		}
+	}
+	return lag
+}
+
+// waitForThrottle is called before executing the SQLs, it pauses until the instance recovers. An error is
+// returned if the instance does not recover in the timeout or the task is terminated while waiting.
+func (a *action) waitForThrottle(executeSQL *model.ExecuteSQL) error {
+	if a.throttler == nil {
+		return nil
+	}
+	cfg := a.throttler.cfg
+	entry := a.entry.WithField("execute_sql_id", executeSQL.ID)
+	start := time.Now()
+	throttled := false
+	for {
+		reasons, load := a.throttler.check(a)
+		waited := time.Since(start)
+		if len(reasons) == 0 {
+			if throttled {
+				entry.Infof("execution is resumed after throttled for %v", waited)
+				a.recordThrottleEvent(executeSQL, model.ThrottleEventResumed, "the instance is recovered", load, waited)
+			}
+			return nil
+		}
+		reason := strings.Join(reasons, "; ")
+		if !throttled {
+			throttled = true
+			entry.Warnf("execution is throttled, %s", reason)
+			a.recordThrottleEvent(executeSQL, model.ThrottleEventThrottled, reason, load, waited)
+		}
+		if a.hasTermination() {
+			return fmt.Errorf("task is terminated while execution is throttled")
+		}
+		if waited >= time.Duration(cfg.TimeoutSecond)*time.Second {
+			entry.Errorf("execution is aborted after throttled for %v, %s", waited, reason)
+			a.recordThrottleEvent(executeSQL, model.ThrottleEventAborted, reason, load, waited)
+			return fmt.Errorf("%w %ds, %s", ErrExecutionThrottleTimeout, cfg.TimeoutSecond, reason)
+		}
+		time.Sleep(time.Duration(cfg.CheckIntervalSecond) * time.Second)
+	}
+}
+
+func (a *action) recordThrottleEvent(executeSQL *model.ExecuteSQL, event, reason string, load *driver.ExecutionLoad, waited time.Duration) {
+	err := model.GetStorage().Save(&model.TaskThrottleEvent{
+		TaskId:            a.task.ID,
+		ExecuteSQLId:      executeSQL.ID,
+		Event:             event,
+		Reason:            reason,
+		ThreadsRunning:    load.ThreadsRunning,
+		ReplicationLagSec: maxReplicationLag(load),
+		WaitedSecond:      int64(waited.Seconds()),
+	})
+	if err != nil {
+		a.entry.Errorf("save throttle event of task %d failed, err: %v", a.task.ID, err)
+	}
+}
+
+// abortThrottledSQLs updates the status of the SQLs which are not executed since the throttle is not released.
+func (a *action) abortThrottledSQLs(executeSQLs []*model.ExecuteSQL, err error) error {
+	if a.hasTermination() {
+		for _, executeSQL := range executeSQLs {
+			executeSQL.ExecStatus = model.SQLExecuteStatusTerminateSucc
+			executeSQL.ExecResult = terminatedExecResult(err)
+		}
+		if updateErr := model.GetStorage().UpdateExecuteSQLs(executeSQLs); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+	if persistErr := a.persistOnlineFailure(executeSQLs[0], model.OnlineFailStageSQLExecute, err.Error()); persistErr != nil {
+		a.entry.Errorf("persist online failure after execution throttled, task=%v err=%v", a.task.ID, persistErr)
+	}
+	return err
+}
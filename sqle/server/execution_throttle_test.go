@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLoadProber struct {
+	load *driver.ExecutionLoad
+	err  error
+
+	replicaDSNs      []string
+	discoverReplicas bool
+}
+
+func (p *fakeLoadProber) ProbeExecutionLoad(ctx context.Context, replicaDSNs []string, discoverReplicas bool) (*driver.ExecutionLoad, error) {
+	p.replicaDSNs, p.discoverReplicas = replicaDSNs, discoverReplicas
+	return p.load, p.err
+}
+
+func TestExecutionThrottlerCheck(t *testing.T) {
+	a := &action{ctx: context.TODO()}
+	prober := &fakeLoadProber{load: &driver.ExecutionLoad{
+		ThreadsRunning: 60,
+		Replicas: []*driver.ReplicaLag{
+			{Replica: "10.10.10.2:3306", LagSecond: 3},
+			{Replica: "10.10.10.3:3306", LagSecond: 30},
+			{Replica: "10.10.10.4:3306", LagSecond: -1, Error: "the replication is not running"},
+		},
+	}}
+	throttler := &executionThrottler{
+		cfg: &model.ExecutionThrottle{
+			Enabled:              true,
+			ReplicaDSNs:          []string{"repl:pass@tcp(10.10.10.2:3306)/"},
+			DiscoverReplicas:     true,
+			MaxReplicationLagSec: 10,
+			MaxThreadsRunning:    50,
+		},
+		prober: prober,
+	}
+	reasons, load := throttler.check(a)
+	assert.Equal(t, []string{
+		"Threads_running 60 exceeds 50",
+		"replication lag of 10.10.10.3:3306 is 30s, exceeds 10s",
+		"replication lag of 10.10.10.4:3306 is unknown: the replication is not running",
+	}, reasons)
+	assert.Equal(t, int64(30), maxReplicationLag(load))
+	assert.Equal(t, []string{"repl:pass@tcp(10.10.10.2:3306)/"}, prober.replicaDSNs)
+	assert.True(t, prober.discoverReplicas)
+
+	// the replicas are not probed without the lag limit
+	throttler.cfg.MaxReplicationLagSec = 0
+	throttler.cfg.MaxThreadsRunning = 100
+	reasons, _ = throttler.check(a)
+	assert.Empty(t, reasons)
+	assert.Nil(t, prober.replicaDSNs)
+	assert.False(t, prober.discoverReplicas)
+
+	prober.err = fmt.Errorf("connection refused")
+	reasons, _ = throttler.check(a)
+	assert.Equal(t, []string{"probe the load of the instance failed: connection refused"}, reasons)
+}
+
+// the throttle fails closed, the execution is aborted if the load can not be probed until the timeout
+func TestWaitForThrottleProbeFailed(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	model.InitMockStorage(mockDB)
+
+	for _, event := range []string{model.ThrottleEventThrottled, model.ThrottleEventAborted} {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `task_throttle_events`")).
+			WithArgs(nil, 1, 2, event, sqlmock.AnyArg(), 0, 0, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+
+	a := &action{
+		ctx:   context.TODO(),
+		task:  &model.Task{Model: model.Model{ID: 1}},
+		entry: log.NewEntry(),
+		throttler: &executionThrottler{
+			cfg:    &model.ExecutionThrottle{Enabled: true, MaxThreadsRunning: 50, CheckIntervalSecond: 1},
+			prober: &fakeLoadProber{err: fmt.Errorf("connection refused")},
+		},
+	}
+	err = a.waitForThrottle(&model.ExecuteSQL{BaseSQL: model.BaseSQL{Model: model.Model{ID: 2}}})
+	assert.True(t, errors.Is(err, ErrExecutionThrottleTimeout))
+	assert.Contains(t, err.Error(), "probe the load of the instance failed: connection refused")
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -14,6 +14,7 @@ type FeishuJob struct {
 }
 
 func NewFeishuJob(entry *logrus.Entry) ServerJob {
+	entry = entry.WithField("job", "feishu")
 	f := new(FeishuJob)
 	f.BaseJob = *NewBaseJob(entry, 60*time.Second, f.feishuRotation)
 	return f
@@ -0,0 +1,198 @@
+package knowledge_base
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/actiontech/sqle/sqle/model"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	// MaxTagNameLength 与 tags 表的 name 字段长度一致
+	MaxTagNameLength = 50
+	// MaxRelatedRules 知识详情中展示的相关规则数
+	MaxRelatedRules = 10
+)
+
+// CheckTagNames 检查用户输入的标签名称
+func CheckTagNames(names []string) error {
+	for _, name := range names {
+		if name == "" {
+			return fmt.Errorf("tag name is empty")
+		}
+		if utf8.RuneCountInString(name) > MaxTagNameLength {
+			return fmt.Errorf("tag name %s is longer than %d", name, MaxTagNameLength)
+		}
+	}
+	return nil
+}
+
+// GetOrCreateTags 获取标签，tags 用于缓存已获取的标签
+func GetOrCreateTags(s *model.Storage, tags map[string]*model.Tag, names []string) ([]*model.Tag, error) {
+	missing := []string{}
+	for _, name := range names {
+		if _, ok := tags[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		created, err := s.GetOrCreateTags(missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range created {
+			tags[string(tag.Name)] = tag
+		}
+	}
+	result := make([]*model.Tag, 0, len(names))
+	for _, name := range names {
+		if tag, ok := tags[name]; ok {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// ruleTagNames 规则的分类标签作为规则知识的标签，相同标签的知识互为相关知识
+func ruleTagNames(rule *model.Rule) []string {
+	names := []string{}
+	exist := map[string]struct{}{}
+	for _, category := range rule.Categories {
+		if _, ok := exist[category.Tag]; ok || category.Tag == "" || utf8.RuneCountInString(category.Tag) > MaxTagNameLength {
+			continue
+		}
+		exist[category.Tag] = struct{}{}
+		names = append(names, category.Tag)
+	}
+	return names
+}
+
+// newRuleKnowledge 返回规则在插件中定义了、但还未创建的各语言的知识
+func newRuleKnowledge(rule *model.Rule, exist model.MultiLanguageKnowledge) []*model.Knowledge {
+	created := map[string]struct{}{}
+	for _, k := range exist {
+		created[k.Language] = struct{}{}
+	}
+	knowledge := []*model.Knowledge{}
+	for _, k := range rule.Knowledge {
+		if _, ok := created[k.Language]; ok {
+			continue
+		}
+		created[k.Language] = struct{}{}
+		knowledge = append(knowledge, &model.Knowledge{
+			Title:       k.Title,
+			Description: k.Description,
+			Content:     k.Content,
+			Language:    k.Language,
+		})
+	}
+	return knowledge
+}
+
+// SelectKnowledgeOfRules 按语言为每个规则选择一篇知识，返回规则名称到知识ID的映射
+func SelectKnowledgeOfRules(refs []*model.RuleKnowledgeRef, lang language.Tag) map[string]uint {
+	knowledgeOfRules := map[string]model.MultiLanguageKnowledge{}
+	for _, ref := range refs {
+		k := &model.Knowledge{Language: ref.Language}
+		k.ID = ref.KnowledgeId
+		knowledgeOfRules[ref.RuleName] = append(knowledgeOfRules[ref.RuleName], k)
+	}
+	result := make(map[string]uint, len(knowledgeOfRules))
+	for ruleName, knowledge := range knowledgeOfRules {
+		if k := knowledge.GetByLangTag(lang); k != nil {
+			result[ruleName] = k.ID
+		}
+	}
+	return result
+}
+
+// GetKnowledgeIdsOfRules 获取审核结果中的规则对应的知识，用于从审核结果跳转到知识
+func GetKnowledgeIdsOfRules(dbType string, ruleNames []string, lang language.Tag) (map[string]uint, error) {
+	refs, err := model.GetStorage().GetKnowledgeRefsOfRules(dbType, ruleNames)
+	if err != nil {
+		return nil, err
+	}
+	return SelectKnowledgeOfRules(refs, lang), nil
+}
+
+type GraphNode struct {
+	ID     string
+	Name   string
+	Weight uint64
+}
+
+type GraphEdge struct {
+	FromID string
+	ToID   string
+	Weight uint64
+}
+
+type Graph struct {
+	Nodes []*GraphNode
+	Edges []*GraphEdge
+}
+
+// BuildTagGraph 生成知识的标签图谱，节点为标签，权重为标签的知识数；边连接同一知识的标签，权重为共同出现的次数
+func BuildTagGraph(refs []*model.KnowledgeTagRef) *Graph {
+	nodes := map[uint]*GraphNode{}
+	tagsOfKnowledge := map[uint][]uint{}
+	for _, ref := range refs {
+		node, ok := nodes[ref.TagId]
+		if !ok {
+			node = &GraphNode{ID: strconv.FormatUint(uint64(ref.TagId), 10), Name: ref.TagName}
+			nodes[ref.TagId] = node
+		}
+		node.Weight++
+		tagsOfKnowledge[ref.KnowledgeId] = append(tagsOfKnowledge[ref.KnowledgeId], ref.TagId)
+	}
+
+	type edgeKey struct{ from, to uint }
+	edges := map[edgeKey]*GraphEdge{}
+	for _, tagIds := range tagsOfKnowledge {
+		for i := range tagIds {
+			for j := i + 1; j < len(tagIds); j++ {
+				from, to := tagIds[i], tagIds[j]
+				if from == to {
+					continue
+				}
+				if from > to {
+					from, to = to, from
+				}
+				edge, ok := edges[edgeKey{from, to}]
+				if !ok {
+					edge = &GraphEdge{FromID: nodes[from].ID, ToID: nodes[to].ID}
+					edges[edgeKey{from, to}] = edge
+				}
+				edge.Weight++
+			}
+		}
+	}
+
+	graph := &Graph{Nodes: make([]*GraphNode, 0, len(nodes)), Edges: make([]*GraphEdge, 0, len(edges))}
+	tagIds := make([]uint, 0, len(nodes))
+	for id := range nodes {
+		tagIds = append(tagIds, id)
+	}
+	sort.Slice(tagIds, func(i, j int) bool { return tagIds[i] < tagIds[j] })
+	for _, id := range tagIds {
+		graph.Nodes = append(graph.Nodes, nodes[id])
+	}
+	keys := make([]edgeKey, 0, len(edges))
+	for key := range edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].from != keys[j].from {
+			return keys[i].from < keys[j].from
+		}
+		return keys[i].to < keys[j].to
+	})
+	for _, key := range keys {
+		graph.Edges = append(graph.Edges, edges[key])
+	}
+	return graph
+}
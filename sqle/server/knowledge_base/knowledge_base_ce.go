@@ -9,10 +9,39 @@ import (
 	"github.com/actiontech/sqle/sqle/model"
 )
 
+// LoadKnowledge 为规则创建插件中定义的知识，已创建的知识可能被用户编辑过，不会被覆盖
 func LoadKnowledge(rulesMap map[string][]*model.Rule) error {
+	s := model.GetStorage()
+	rulesInDB, err := s.GetAllRules()
+	if err != nil {
+		return err
+	}
+	existKnowledge := make(map[string]model.MultiLanguageKnowledge, len(rulesInDB))
+	for _, rule := range rulesInDB {
+		existKnowledge[fmt.Sprintf("%s:%s", rule.DBType, rule.Name)] = rule.Knowledge
+	}
+
+	tags := map[string]*model.Tag{}
+	for dbType, rules := range rulesMap {
+		for _, rule := range rules {
+			knowledge := newRuleKnowledge(rule, existKnowledge[fmt.Sprintf("%s:%s", dbType, rule.Name)])
+			if len(knowledge) == 0 {
+				continue
+			}
+			ruleTags, err := GetOrCreateTags(s, tags, ruleTagNames(rule))
+			if err != nil {
+				return err
+			}
+			for _, k := range knowledge {
+				if err := s.CreateRuleKnowledge(rule, k, ruleTags); err != nil {
+					return fmt.Errorf("create knowledge of rule %s failed: %v", rule.Name, err)
+				}
+			}
+		}
+	}
 	return nil
 }
 
 func CheckKnowledgeBaseLicense() error {
-	return fmt.Errorf("license not support knowledge base")
+	return nil
 }
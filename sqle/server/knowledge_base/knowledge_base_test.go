@@ -0,0 +1,88 @@
+package knowledge_base
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestCheckTagNames(t *testing.T) {
+	assert.NoError(t, CheckTagNames(nil))
+	assert.NoError(t, CheckTagNames([]string{"索引", "index"}))
+	assert.Error(t, CheckTagNames([]string{"index", ""}))
+	assert.NoError(t, CheckTagNames([]string{strings.Repeat("索", MaxTagNameLength)}))
+	assert.Error(t, CheckTagNames([]string{strings.Repeat("a", MaxTagNameLength+1)}))
+}
+
+func TestRuleTagNames(t *testing.T) {
+	rule := &model.Rule{Categories: []*model.AuditRuleCategory{
+		{Category: "audit_purpose", Tag: "performance"},
+		{Category: "sql", Tag: "dml"},
+		{Category: "audit_purpose", Tag: "performance"},
+		{Category: "sql", Tag: ""},
+	}}
+	assert.Equal(t, []string{"performance", "dml"}, ruleTagNames(rule))
+	assert.Equal(t, []string{}, ruleTagNames(&model.Rule{}))
+}
+
+func TestNewRuleKnowledge(t *testing.T) {
+	rule := &model.Rule{Knowledge: model.MultiLanguageKnowledge{
+		{Title: "标题", Content: "内容", Language: "zh"},
+		{Title: "title", Content: "content", Language: "en"},
+	}}
+
+	knowledge := newRuleKnowledge(rule, nil)
+	assert.Len(t, knowledge, 2)
+	assert.Equal(t, "标题", knowledge[0].Title)
+	assert.Equal(t, "en", knowledge[1].Language)
+
+	// the knowledge created before may have been edited by users, it should not be created again
+	knowledge = newRuleKnowledge(rule, model.MultiLanguageKnowledge{{Content: "edited", Language: "zh"}})
+	assert.Len(t, knowledge, 1)
+	assert.Equal(t, "content", knowledge[0].Content)
+
+	knowledge = newRuleKnowledge(rule, model.MultiLanguageKnowledge{{Language: "zh"}, {Language: "en"}})
+	assert.Len(t, knowledge, 0)
+}
+
+func TestSelectKnowledgeOfRules(t *testing.T) {
+	refs := []*model.RuleKnowledgeRef{
+		{RuleName: "rule1", KnowledgeId: 1, Language: "zh"},
+		{RuleName: "rule1", KnowledgeId: 2, Language: "en"},
+		{RuleName: "rule2", KnowledgeId: 3, Language: "zh"},
+		{RuleName: "rule3", KnowledgeId: 4, Language: ""},
+	}
+	assert.Equal(t, map[string]uint{"rule1": 2, "rule2": 3, "rule3": 4}, SelectKnowledgeOfRules(refs, language.English))
+	assert.Equal(t, map[string]uint{"rule1": 1, "rule2": 3, "rule3": 4}, SelectKnowledgeOfRules(refs, language.Chinese))
+	assert.Equal(t, map[string]uint{}, SelectKnowledgeOfRules(nil, language.Chinese))
+}
+
+func TestBuildTagGraph(t *testing.T) {
+	refs := []*model.KnowledgeTagRef{
+		{KnowledgeId: 1, TagId: 2, TagName: "index"},
+		{KnowledgeId: 1, TagId: 1, TagName: "performance"},
+		{KnowledgeId: 2, TagId: 1, TagName: "performance"},
+		{KnowledgeId: 2, TagId: 2, TagName: "index"},
+		{KnowledgeId: 2, TagId: 3, TagName: "ddl"},
+		{KnowledgeId: 3, TagId: 3, TagName: "ddl"},
+	}
+	graph := BuildTagGraph(refs)
+	assert.Equal(t, []*GraphNode{
+		{ID: "1", Name: "performance", Weight: 2},
+		{ID: "2", Name: "index", Weight: 2},
+		{ID: "3", Name: "ddl", Weight: 2},
+	}, graph.Nodes)
+	assert.Equal(t, []*GraphEdge{
+		{FromID: "1", ToID: "2", Weight: 2},
+		{FromID: "1", ToID: "3", Weight: 1},
+		{FromID: "2", ToID: "3", Weight: 1},
+	}, graph.Edges)
+
+	graph = BuildTagGraph(nil)
+	assert.Len(t, graph.Nodes, 0)
+	assert.Len(t, graph.Edges, 0)
+}
@@ -1,9 +1,11 @@
 package server
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/metrics"
 	"github.com/actiontech/sqle/sqle/server/cluster"
 	"github.com/sirupsen/logrus"
 )
@@ -19,6 +21,7 @@ var OnlyRunOnLeaderJobs = []func(entry *logrus.Entry) ServerJob{
 	NewFeishuJob,
 	NewWechatJob,
 	NewReportPushJob,
+	NewWorkflowSLAJob,
 }
 
 var RunOnAllJobs = []func(entry *logrus.Entry) ServerJob{
@@ -145,12 +148,22 @@ func (j *BaseJob) Start() {
 				j.doneCh <- struct{}{}
 				return
 			case <-tick.C:
+				begin := time.Now()
 				j.jobFn(j.entry)
+				metrics.ObserveSince(metrics.JobDuration, begin, j.name())
 			}
 		}
 	}()
 }
 
+// name returns the job name in the log fields, it is used as the label of the job metrics.
+func (j *BaseJob) name() string {
+	if name, ok := j.entry.Data["job"]; ok {
+		return fmt.Sprint(name)
+	}
+	return "unknown"
+}
+
 func (j *BaseJob) Stop() {
 	j.exitCh <- struct{}{}
 	<-j.doneCh
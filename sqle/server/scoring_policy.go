@@ -0,0 +1,139 @@
+package server
+
+import (
+	"math"
+	"sort"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/sirupsen/logrus"
+)
+
+func levelCounts(task *model.Task) map[string]int {
+	counts := map[string]int{}
+	for _, executeSQL := range task.ExecuteSQLs {
+		level := executeSQL.AuditLevel
+		if level == "" {
+			level = string(driverV2.RuleLevelNormal)
+		}
+		counts[level]++
+	}
+	return counts
+}
+
+// applyScoringPolicy scores the task by the scoring policy of the project, the default score is kept if the
+// project has no enabled policy.
+func applyScoringPolicy(l *logrus.Entry, projectId string, task *model.Task) {
+	if projectId == "" || len(task.ExecuteSQLs) == 0 {
+		return
+	}
+	st := model.GetStorage()
+	policy, exist, err := st.GetScoringPolicyByProjectId(projectId)
+	if err != nil {
+		l.Errorf("get scoring policy of project %s failed, the default score is kept, err: %v", projectId, err)
+		return
+	}
+	if !exist || !policy.Enabled {
+		return
+	}
+	ruleNames := []string{}
+	for _, executeSQL := range task.ExecuteSQLs {
+		for _, result := range executeSQL.AuditResults {
+			if result.RuleName != "" {
+				ruleNames = append(ruleNames, result.RuleName)
+			}
+		}
+	}
+	ruleTags := map[string][]string{}
+	if len(policy.CategoryCaps) > 0 && len(ruleNames) > 0 {
+		if ruleTags, err = st.GetRuleCategoryTags(ruleNames, task.DBType); err != nil {
+			l.Errorf("get rule categories failed, the category caps are ignored, err: %v", err)
+		}
+	}
+	task.Score, task.ScoreBreakdown = scoreTaskByPolicy(task, policy, ruleTags)
+}
+
+// scoreTaskByPolicy deducts the penalty of each audit result from the base score. ruleTags is the category
+// tags of the rules, the penalties of a rule are counted in its first capped category.
+func scoreTaskByPolicy(task *model.Task, policy *model.ScoringPolicy, ruleTags map[string][]string) (int32, *model.ScoreBreakdown) {
+	breakdown := &model.ScoreBreakdown{
+		Policy:         model.ScorePolicyProject,
+		LevelCounts:    levelCounts(task),
+		BaseScore:      policy.BaseScore,
+		LevelPenalties: map[string]float64{},
+		RulePenalties:  map[string]float64{},
+	}
+	hardFail := map[string]bool{}
+	for _, rule := range policy.HardFailRules {
+		hardFail[rule] = true
+	}
+	hit := map[string]bool{}
+
+	// the penalties of the rules without category cap are deducted directly
+	var uncapped float64
+	categoryPenalties := map[string]float64{}
+	for _, executeSQL := range task.ExecuteSQLs {
+		for _, result := range executeSQL.AuditResults {
+			level := driverV2.RuleLevel(result.Level)
+			if !level.More(driverV2.RuleLevelNormal) {
+				continue
+			}
+			if hardFail[result.RuleName] && !hit[result.RuleName] {
+				hit[result.RuleName] = true
+				breakdown.HardFailRules = append(breakdown.HardFailRules, result.RuleName)
+			}
+			penalty, ok := policy.RulePenalties[result.RuleName]
+			if !ok {
+				penalty = policy.LevelPenalties[result.Level]
+			}
+			if penalty == 0 {
+				continue
+			}
+			breakdown.LevelPenalties[result.Level] += penalty
+			if result.RuleName != "" {
+				breakdown.RulePenalties[result.RuleName] += penalty
+			}
+			if category, ok := cappedCategory(result.RuleName, ruleTags, policy.CategoryCaps); ok {
+				categoryPenalties[category] += penalty
+			} else {
+				uncapped += penalty
+			}
+		}
+	}
+
+	categories := make([]string, 0, len(categoryPenalties))
+	for category := range categoryPenalties {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	total := uncapped
+	for _, category := range categories {
+		p := &model.CategoryScorePenalty{
+			Category: category,
+			Penalty:  categoryPenalties[category],
+			Cap:      policy.CategoryCaps[category],
+		}
+		if p.Penalty > p.Cap {
+			p.Penalty, p.Capped = p.Cap, true
+		}
+		total += p.Penalty
+		breakdown.CategoryPenalties = append(breakdown.CategoryPenalties, p)
+	}
+	breakdown.TotalPenalty = total
+
+	score := math.Floor(float64(policy.BaseScore) - total)
+	if score < 0 || len(breakdown.HardFailRules) > 0 {
+		score = 0
+	}
+	breakdown.Score = int32(score)
+	return breakdown.Score, breakdown
+}
+
+func cappedCategory(ruleName string, ruleTags map[string][]string, caps model.ScoreWeights) (string, bool) {
+	for _, tag := range ruleTags[ruleName] {
+		if _, ok := caps[tag]; ok {
+			return tag, true
+		}
+	}
+	return "", false
+}
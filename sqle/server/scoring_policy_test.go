@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScoredSQL(level string, results ...model.AuditResult) *model.ExecuteSQL {
+	return &model.ExecuteSQL{AuditResults: results, AuditLevel: level}
+}
+
+func TestScoreTaskByPolicy(t *testing.T) {
+	task := &model.Task{
+		ExecuteSQLs: []*model.ExecuteSQL{
+			newScoredSQL("normal"),
+			newScoredSQL("warn",
+				model.AuditResult{Level: "warn", RuleName: "all_check_where_is_invalid"},
+				model.AuditResult{Level: "notice", RuleName: "dml_check_alias"},
+			),
+			newScoredSQL("warn",
+				model.AuditResult{Level: "warn", RuleName: "all_check_where_is_invalid"},
+				model.AuditResult{Level: "warn", RuleName: "dml_check_select_limit"},
+			),
+			newScoredSQL("error",
+				model.AuditResult{Level: "error", RuleName: "ddl_check_table_without_if_not_exists"},
+			),
+		},
+	}
+	policy := &model.ScoringPolicy{
+		BaseScore:      100,
+		LevelPenalties: model.ScoreWeights{"error": 20, "warn": 5, "notice": 1},
+		RulePenalties:  model.ScoreWeights{"all_check_where_is_invalid": 15},
+		CategoryCaps:   model.ScoreWeights{"performance": 20},
+	}
+	ruleTags := map[string][]string{
+		"all_check_where_is_invalid": {"dml", "performance"},
+		"dml_check_select_limit":     {"performance"},
+	}
+
+	score, breakdown := scoreTaskByPolicy(task, policy, ruleTags)
+	// performance: 15 + 15 + 5 = 35, capped to 20; the others: 1 + 20
+	assert.Equal(t, int32(59), score)
+	assert.Equal(t, model.ScorePolicyProject, breakdown.Policy)
+	assert.Equal(t, map[string]int{"normal": 1, "warn": 2, "error": 1}, breakdown.LevelCounts)
+	assert.Equal(t, map[string]float64{"error": 20, "warn": 35, "notice": 1}, breakdown.LevelPenalties)
+	assert.Equal(t, float64(30), breakdown.RulePenalties["all_check_where_is_invalid"])
+	assert.Equal(t, []*model.CategoryScorePenalty{{Category: "performance", Penalty: 20, Cap: 20, Capped: true}}, breakdown.CategoryPenalties)
+	assert.Equal(t, float64(41), breakdown.TotalPenalty)
+	assert.False(t, breakdown.IsHardFailed())
+
+	// a single result of the hard-fail rule fails the task
+	policy.HardFailRules = model.Strings{"ddl_check_table_without_if_not_exists"}
+	score, breakdown = scoreTaskByPolicy(task, policy, ruleTags)
+	assert.Equal(t, int32(0), score)
+	assert.True(t, breakdown.IsHardFailed())
+	assert.Equal(t, []string{"ddl_check_table_without_if_not_exists"}, breakdown.HardFailRules)
+
+	// the score is not negative
+	policy.HardFailRules = nil
+	policy.CategoryCaps = nil
+	policy.BaseScore = 30
+	score, _ = scoreTaskByPolicy(task, policy, nil)
+	assert.Equal(t, int32(0), score)
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSQLQueryMaxRows       = 100
+	defaultSQLQueryTimeoutSecond = 10
+)
+
+type SQLQueryResult struct {
+	// Task holds the audit results of the statement, it is not saved.
+	Task          *model.Task
+	Columns       []string
+	Rows          [][]string
+	Truncated     bool
+	MaskedColumns []string
+	Record        *model.SQLQueryRecord
+}
+
+// QueryReadOnly audits the statement with the rule template of the instance, and runs it if it is a read-only
+// query passing the audit. The result is limited by the SQL query config of the instance and masked by the
+// masking rules of the project. Every query is recorded, including the rejected ones.
+func QueryReadOnly(l *logrus.Entry, instance *model.Instance, schema, sql, userId string) (*SQLQueryResult, error) {
+	now := time.Now()
+	record := &model.SQLQueryRecord{
+		ProjectId:  instance.ProjectId,
+		InstanceId: instance.GetIDStr(),
+		UserId:     userId,
+		Schema:     schema,
+		SQL:        sql,
+		StartAt:    &now,
+	}
+	res, queryErr := queryReadOnly(l, instance, schema, sql, record)
+	if queryErr != nil {
+		record.Error = queryErr.Error()
+		if record.Status == "" {
+			record.Status = model.SQLQueryStatusFailed
+		}
+	}
+	if err := model.GetStorage().Save(record); err != nil {
+		return nil, err
+	}
+	if queryErr != nil {
+		return res, queryErr
+	}
+	res.Record = record
+	return res, nil
+}
+
+func queryReadOnly(l *logrus.Entry, instance *model.Instance, schema, sql string, record *model.SQLQueryRecord) (*SQLQueryResult, error) {
+	st := model.GetStorage()
+	maskingRules, err := st.GetSQLQueryMaskingRules(instance.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	maskers, err := newSQLQueryMaskers(maskingRules)
+	if err != nil {
+		return nil, err
+	}
+	rules, customRules, err := st.GetAllRulesByTmpNameAndProjectIdInstanceDBType("", instance.ProjectId, instance, instance.DbType)
+	if err != nil {
+		return nil, err
+	}
+	plugin, err := newDriverManagerWithAudit(l, instance, schema, instance.DbType, rules)
+	if err != nil {
+		return nil, err
+	}
+	defer plugin.Close(context.TODO())
+
+	task, err := convertSQLsToTask(sql, plugin)
+	if err != nil {
+		return nil, err
+	}
+	task.Instance = instance
+	if err := audit(instance.ProjectId, l, task, plugin, customRules); err != nil {
+		return nil, err
+	}
+	res := &SQLQueryResult{Task: task}
+	record.AuditLevel = task.AuditLevel
+	if err := checkSQLQueryAllowed(instance.SqlQueryConfig, task); err != nil {
+		record.Status = model.SQLQueryStatusRejected
+		return res, err
+	}
+	// the masking rules match the source columns of the result rather than its names, the query is rejected
+	// if the source columns can not be resolved
+	var selects []*sqlQuerySelect
+	if len(maskers) > 0 {
+		if instance.DbType != driverV2.DriverTypeMySQL {
+			record.Status = model.SQLQueryStatusRejected
+			return res, errors.New(errors.DataInvalid, fmt.Errorf("the masking rules only support %s, the query of %s is rejected", driverV2.DriverTypeMySQL, instance.DbType))
+		}
+		if selects, err = parseSQLQuerySources(task.ExecuteSQLs[0].Content); err != nil {
+			record.Status = model.SQLQueryStatusRejected
+			return res, errors.New(errors.DataInvalid, fmt.Errorf("resolve the columns of the query for masking failed: %v", err))
+		}
+	}
+
+	maxRows := instance.SqlQueryConfig.MaxPreQueryRows
+	if maxRows <= 0 {
+		maxRows = defaultSQLQueryMaxRows
+	}
+	timeout := instance.SqlQueryConfig.QueryTimeoutSecond
+	if timeout <= 0 {
+		timeout = defaultSQLQueryTimeoutSecond
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	start := time.Now()
+	// one more row is read to know whether the result is truncated
+	result, err := plugin.Query(ctx, task.ExecuteSQLs[0].Content, &driverV2.QueryConf{
+		TimeOutSecond: uint32(timeout),
+		MaxRows:       uint32(maxRows + 1),
+	})
+	record.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		return res, err
+	}
+
+	for _, column := range result.Column {
+		res.Columns = append(res.Columns, column.Key)
+	}
+	rows := result.Rows
+	if len(rows) > maxRows {
+		rows, res.Truncated = rows[:maxRows], true
+	}
+	res.Rows = make([][]string, 0, len(rows))
+	for _, row := range rows {
+		values := make([]string, 0, len(row.Values))
+		for _, value := range row.Values {
+			values = append(values, value.Value)
+		}
+		res.Rows = append(res.Rows, values)
+	}
+	res.MaskedColumns = maskSQLQueryResult(maskers, res.Columns, sqlQueryColumnSources(selects, res.Columns), res.Rows)
+
+	record.Status = model.SQLQueryStatusSucceeded
+	record.RowCount = len(res.Rows)
+	record.Truncated = res.Truncated
+	record.MaskedColumns = res.MaskedColumns
+	return res, nil
+}
+
+// checkSQLQueryAllowed only allows a single DQL statement, and rejects it if its audit level reaches the level
+// configured by the instance.
+func checkSQLQueryAllowed(config model.SqlQueryConfig, task *model.Task) error {
+	if len(task.ExecuteSQLs) != 1 {
+		return errors.New(errors.DataInvalid, fmt.Errorf("only one statement can be queried at a time"))
+	}
+	if task.ExecuteSQLs[0].SQLType != driverV2.SQLTypeDQL {
+		return errors.New(errors.DataInvalid, fmt.Errorf("only read-only queries are allowed"))
+	}
+	if !config.AuditEnabled {
+		return nil
+	}
+	allowLevel := driverV2.RuleLevel(config.AllowQueryWhenLessThanAuditLevel)
+	if allowLevel == driverV2.RuleLevelNull {
+		allowLevel = driverV2.RuleLevelError
+	}
+	if driverV2.RuleLevel(task.AuditLevel).MoreOrEqual(allowLevel) {
+		return errors.New(errors.DataInvalid, fmt.Errorf("the audit level of the query is %s, the query is only allowed below %s", task.AuditLevel, allowLevel))
+	}
+	return nil
+}
+
+type sqlQueryMasker struct {
+	rule   *model.SQLQueryMaskingRule
+	column *regexp.Regexp
+	value  *regexp.Regexp
+}
+
+func newSQLQueryMaskers(rules []*model.SQLQueryMaskingRule) ([]*sqlQueryMasker, error) {
+	maskers := []*sqlQueryMasker{}
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		m, err := newSQLQueryMasker(rule)
+		if err != nil {
+			return nil, err
+		}
+		maskers = append(maskers, m)
+	}
+	return maskers, nil
+}
+
+func newSQLQueryMasker(rule *model.SQLQueryMaskingRule) (*sqlQueryMasker, error) {
+	column, err := regexp.Compile("(?i)" + rule.ColumnPattern)
+	if err != nil {
+		return nil, fmt.Errorf("the column pattern of masking rule %s is invalid: %v", rule.Name, err)
+	}
+	m := &sqlQueryMasker{rule: rule, column: column}
+	switch rule.MaskingType {
+	case model.MaskingTypePhone, model.MaskingTypeIDCard, model.MaskingTypeEmail:
+	case model.MaskingTypeRegex:
+		if m.value, err = regexp.Compile(rule.ValuePattern); err != nil {
+			return nil, fmt.Errorf("the value pattern of masking rule %s is invalid: %v", rule.Name, err)
+		}
+	default:
+		return nil, fmt.Errorf("the masking type %s of masking rule %s is invalid", rule.MaskingType, rule.Name)
+	}
+	return m, nil
+}
+
+// ValidateSQLQueryMaskingRule checks the patterns and the type of the masking rule.
+func ValidateSQLQueryMaskingRule(rule *model.SQLQueryMaskingRule) error {
+	_, err := newSQLQueryMasker(rule)
+	return err
+}
+
+func (m *sqlQueryMasker) mask(value string) string {
+	if value == "" {
+		return value
+	}
+	switch m.rule.MaskingType {
+	case model.MaskingTypePhone:
+		return maskMiddle(value, 3, 4)
+	case model.MaskingTypeIDCard:
+		return maskMiddle(value, 6, 4)
+	case model.MaskingTypeEmail:
+		at := strings.LastIndex(value, "@")
+		if at <= 0 {
+			return maskMiddle(value, 0, 0)
+		}
+		return maskMiddle(value[:at], 1, 0) + value[at:]
+	case model.MaskingTypeRegex:
+		replacement := m.rule.Replacement
+		if replacement == "" {
+			replacement = "***"
+		}
+		return m.value.ReplaceAllString(value, replacement)
+	}
+	return value
+}
+
+// maskMiddle keeps the first head and the last tail characters of the value, the others are replaced by "*".
+// The whole value is replaced if it is not longer than head + tail.
+func maskMiddle(value string, head, tail int) string {
+	runes := []rune(value)
+	if len(runes) <= head+tail {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:head]) + strings.Repeat("*", len(runes)-head-tail) + string(runes[len(runes)-tail:])
+}
+
+// maskSQLQueryResult masks the rows in place by the first masker matching the name or a source column of each
+// column, and returns the masked columns.
+func maskSQLQueryResult(maskers []*sqlQueryMasker, columns []string, sources [][]string, rows [][]string) []string {
+	masked := []string{}
+	for idx, column := range columns {
+		names := []string{column}
+		if idx < len(sources) {
+			names = append(names, sources[idx]...)
+		}
+		var masker *sqlQueryMasker
+	match:
+		for _, m := range maskers {
+			for _, name := range names {
+				if m.column.MatchString(name) {
+					masker = m
+					break match
+				}
+			}
+		}
+		if masker == nil {
+			continue
+		}
+		masked = append(masked, column)
+		for _, row := range rows {
+			if idx < len(row) {
+				row[idx] = masker.mask(row[idx])
+			}
+		}
+	}
+	return masked
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskSQLQueryResult(t *testing.T) {
+	maskers, err := newSQLQueryMaskers([]*model.SQLQueryMaskingRule{
+		{Name: "phone", Enabled: true, ColumnPattern: "^(phone|mobile)$", MaskingType: model.MaskingTypePhone},
+		{Name: "id card", Enabled: true, ColumnPattern: "id_card", MaskingType: model.MaskingTypeIDCard},
+		{Name: "email", Enabled: true, ColumnPattern: "email", MaskingType: model.MaskingTypeEmail},
+		{Name: "card no", Enabled: true, ColumnPattern: "^remark$", MaskingType: model.MaskingTypeRegex, ValuePattern: `\d{16}`, Replacement: "<card>"},
+		{Name: "disabled", Enabled: false, ColumnPattern: "name", MaskingType: model.MaskingTypeRegex, ValuePattern: ".*"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, maskers, 4)
+
+	columns := []string{"name", "Phone", "user_id_card", "email", "remark"}
+	rows := [][]string{
+		{"Alice", "13812345678", "110101199003071234", "alice@example.com", "paid by 6222020202020202020 ok"},
+		{"Bob", "", "X1", "bob", "no card"},
+	}
+	masked := maskSQLQueryResult(maskers, columns, nil, rows)
+	assert.Equal(t, []string{"Phone", "user_id_card", "email", "remark"}, masked)
+	assert.Equal(t, []string{"Alice", "138****5678", "110101********1234", "a****@example.com", "paid by <card>020 ok"}, rows[0])
+	assert.Equal(t, []string{"Bob", "", "**", "***", "no card"}, rows[1])
+
+	// the columns are masked by their source columns
+	rows = [][]string{{"Alice", "13812345678"}}
+	masked = maskSQLQueryResult(maskers, []string{"name", "p"}, [][]string{{"name"}, {"mobile"}}, rows)
+	assert.Equal(t, []string{"p"}, masked)
+	assert.Equal(t, []string{"Alice", "138****5678"}, rows[0])
+
+	_, err = newSQLQueryMaskers([]*model.SQLQueryMaskingRule{
+		{Name: "invalid", Enabled: true, ColumnPattern: "(", MaskingType: model.MaskingTypePhone},
+	})
+	assert.Error(t, err)
+	assert.Error(t, ValidateSQLQueryMaskingRule(&model.SQLQueryMaskingRule{Name: "unknown", ColumnPattern: "a", MaskingType: "hash"}))
+}
+
+func TestCheckSQLQueryAllowed(t *testing.T) {
+	newTask := func(auditLevel string, sqlTypes ...string) *model.Task {
+		task := &model.Task{AuditLevel: auditLevel}
+		for _, sqlType := range sqlTypes {
+			task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{BaseSQL: model.BaseSQL{SQLType: sqlType}})
+		}
+		return task
+	}
+	config := model.SqlQueryConfig{AuditEnabled: true, AllowQueryWhenLessThanAuditLevel: "warn"}
+
+	assert.NoError(t, checkSQLQueryAllowed(config, newTask("notice", "dql")))
+	assert.Error(t, checkSQLQueryAllowed(config, newTask("warn", "dql")))
+	assert.Error(t, checkSQLQueryAllowed(config, newTask("normal", "dml")))
+	assert.Error(t, checkSQLQueryAllowed(config, newTask("normal", "dql", "dql")))
+
+	// the audit level is not checked if the audit is disabled
+	config.AuditEnabled = false
+	assert.NoError(t, checkSQLQueryAllowed(config, newTask("error", "dql")))
+	assert.Error(t, checkSQLQueryAllowed(config, newTask("normal", "ddl")))
+
+	// error level is rejected by default
+	config = model.SqlQueryConfig{AuditEnabled: true}
+	assert.NoError(t, checkSQLQueryAllowed(config, newTask("warn", "dql")))
+	assert.Error(t, checkSQLQueryAllowed(config, newTask("error", "dql")))
+}
+
+func TestSQLQueryColumnSources(t *testing.T) {
+	for _, c := range []struct {
+		sql     string
+		columns []string
+		expect  [][]string
+	}{
+		{
+			sql:     "SELECT id, phone AS p, CONCAT(name, '-', mobile) AS c FROM t1",
+			columns: []string{"id", "p", "c"},
+			expect:  [][]string{{"id"}, {"phone"}, {"name", "mobile"}},
+		},
+		{
+			sql:     "SELECT d.x, y FROM (SELECT phone AS x, UPPER(email) AS y FROM t1) d",
+			columns: []string{"x", "y"},
+			expect:  [][]string{{"x", "phone"}, {"y", "email"}},
+		},
+		{
+			sql:     "SELECT * FROM (SELECT id, phone AS x FROM t1) d",
+			columns: []string{"id", "x"},
+			expect:  [][]string{{"id"}, {"phone"}},
+		},
+		{
+			sql:     "SELECT id, t1.* FROM t1",
+			columns: []string{"id", "id", "phone"},
+			expect:  [][]string{{"id"}, nil, nil},
+		},
+		{
+			sql:     "SELECT id, name FROM t1 UNION SELECT id, phone FROM t2",
+			columns: []string{"id", "name"},
+			expect:  [][]string{{"id", "id"}, {"name", "phone"}},
+		},
+		{
+			sql:     "SELECT (SELECT MAX(phone) FROM t2) AS m FROM t1",
+			columns: []string{"m"},
+			expect:  [][]string{{"phone"}},
+		},
+		{
+			sql:     "SHOW TABLES",
+			columns: []string{"Tables_in_db1"},
+			expect:  [][]string{nil},
+		},
+	} {
+		selects, err := parseSQLQuerySources(c.sql)
+		assert.NoError(t, err, c.sql)
+		assert.Equal(t, c.expect, sqlQueryColumnSources(selects, c.columns), c.sql)
+	}
+
+	_, err := parseSQLQuerySources("SELECT (SELECT * FROM t2 LIMIT 1) AS m FROM t1")
+	assert.Error(t, err)
+	_, err = parseSQLQuerySources("SELECT FROM")
+	assert.Error(t, err)
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/pingcap/parser/ast"
+)
+
+// sqlQueryField is a field of the SELECT, the sources are the columns it is computed from.
+type sqlQueryField struct {
+	name     string
+	sources  []string
+	wildcard bool
+	// table is the qualifier of the wildcard, e.g. "t" of "t.*"
+	table string
+}
+
+// sqlQuerySelect resolves the source columns of the fields of a SELECT, the columns of the derived tables
+// are resolved recursively, so "SELECT p FROM (SELECT phone AS p FROM t) d" is known to read phone.
+type sqlQuerySelect struct {
+	fields  []*sqlQueryField
+	derived map[string]*sqlQuerySelect
+}
+
+// parseSQLQuerySources parses the MySQL query to resolve the source columns of its result. It returns nil
+// for SHOW and EXPLAIN, they return the metadata rather than the data of the tables.
+func parseSQLQuerySources(sql string) ([]*sqlQuerySelect, error) {
+	node, err := util.ParseOneSql(sql)
+	if err != nil {
+		return nil, err
+	}
+	visitor := &subqueryWildcardVisitor{}
+	node.Accept(visitor)
+	if visitor.found {
+		return nil, fmt.Errorf("the columns selected by * in the subquery of a field can not be resolved")
+	}
+	switch stmt := node.(type) {
+	case *ast.SelectStmt:
+		return []*sqlQuerySelect{newSQLQuerySelect(stmt)}, nil
+	case *ast.UnionStmt:
+		selects := []*sqlQuerySelect{}
+		if stmt.SelectList != nil {
+			for _, sel := range stmt.SelectList.Selects {
+				selects = append(selects, newSQLQuerySelect(sel))
+			}
+		}
+		return selects, nil
+	}
+	return nil, nil
+}
+
+func newSQLQuerySelect(stmt *ast.SelectStmt) *sqlQuerySelect {
+	s := &sqlQuerySelect{derived: map[string]*sqlQuerySelect{}}
+	if stmt.From != nil {
+		s.addDerived(stmt.From.TableRefs)
+	}
+	if stmt.Fields == nil {
+		return s
+	}
+	for _, field := range stmt.Fields.Fields {
+		if field.WildCard != nil {
+			s.fields = append(s.fields, &sqlQueryField{wildcard: true, table: field.WildCard.Table.L})
+			continue
+		}
+		f := &sqlQueryField{name: field.AsName.L, sources: s.exprSources(field.Expr)}
+		if col, ok := field.Expr.(*ast.ColumnNameExpr); ok && f.name == "" {
+			f.name = col.Name.Name.L
+		}
+		s.fields = append(s.fields, f)
+	}
+	return s
+}
+
+func (s *sqlQuerySelect) addDerived(node ast.ResultSetNode) {
+	switch n := node.(type) {
+	case *ast.Join:
+		s.addDerived(n.Left)
+		if n.Right != nil {
+			s.addDerived(n.Right)
+		}
+	case *ast.TableSource:
+		switch source := n.Source.(type) {
+		case *ast.SelectStmt:
+			s.derived[n.AsName.L] = newSQLQuerySelect(source)
+		case *ast.UnionStmt:
+			s.derived[n.AsName.L] = mergeSQLQuerySelects(source)
+		case *ast.Join:
+			s.addDerived(source)
+		}
+	}
+}
+
+// mergeSQLQuerySelects merges the SELECTs of the UNION by the position of the fields, the fields are named
+// by the first SELECT.
+func mergeSQLQuerySelects(stmt *ast.UnionStmt) *sqlQuerySelect {
+	merged := &sqlQuerySelect{derived: map[string]*sqlQuerySelect{}}
+	if stmt.SelectList == nil {
+		return merged
+	}
+	for i, sel := range stmt.SelectList.Selects {
+		s := newSQLQuerySelect(sel)
+		for alias, derived := range s.derived {
+			merged.derived[alias] = derived
+		}
+		for idx, f := range s.fields {
+			if i == 0 || idx >= len(merged.fields) {
+				merged.fields = append(merged.fields, f)
+				continue
+			}
+			merged.fields[idx].sources = append(merged.fields[idx].sources, f.sources...)
+			merged.fields[idx].wildcard = merged.fields[idx].wildcard || f.wildcard
+		}
+	}
+	return merged
+}
+
+// exprSources returns the columns referred by the expression, including the ones in its subqueries.
+func (s *sqlQuerySelect) exprSources(expr ast.ExprNode) []string {
+	visitor := util.ColumnNameVisitor{}
+	expr.Accept(&visitor)
+	sources := []string{}
+	for _, col := range visitor.ColumnNameList {
+		sources = append(sources, col.Name.Name.L)
+		sources = append(sources, s.lookupDerived(col.Name.Table.L, col.Name.Name.L)...)
+	}
+	return sources
+}
+
+// lookupDerived returns the sources of the column in the derived tables, all of them are looked up if the
+// table is not specified.
+func (s *sqlQuerySelect) lookupDerived(table, column string) []string {
+	if table != "" {
+		if derived, ok := s.derived[table]; ok {
+			return derived.lookup(column)
+		}
+		return nil
+	}
+	sources := []string{}
+	for _, derived := range s.derived {
+		sources = append(sources, derived.lookup(column)...)
+	}
+	return sources
+}
+
+// lookup returns the sources of the field of the SELECT named column.
+func (s *sqlQuerySelect) lookup(column string) []string {
+	sources := []string{}
+	found := false
+	for _, f := range s.fields {
+		if f.wildcard {
+			sources = append(sources, s.lookupDerived(f.table, column)...)
+		} else if f.name == column {
+			found = true
+			sources = append(sources, f.sources...)
+		}
+	}
+	if !found {
+		// the column is not named by the SELECT, it may be named by the wildcard or an expression without alias
+		for _, f := range s.fields {
+			if !f.wildcard && f.name == "" {
+				sources = append(sources, f.sources...)
+			}
+		}
+	}
+	return sources
+}
+
+// columnSources maps the fields to the columns of the result. The wildcard fields are expanded to the
+// columns not matched by the other fields, the sources of the columns are the union of all the fields if
+// they can not be mapped by position.
+func (s *sqlQuerySelect) columnSources(columns []string) [][]string {
+	wildcards := 0
+	for _, f := range s.fields {
+		if f.wildcard {
+			wildcards++
+		}
+	}
+	sources := make([][]string, len(columns))
+	expanded := len(columns) - (len(s.fields) - wildcards)
+	if (wildcards == 0 && expanded == 0) || (wildcards == 1 && expanded >= 0) {
+		idx := 0
+		for _, f := range s.fields {
+			if !f.wildcard {
+				sources[idx] = f.sources
+				idx++
+				continue
+			}
+			for end := idx + expanded; idx < end; idx++ {
+				sources[idx] = s.lookupDerived(f.table, columns[idx])
+			}
+		}
+		return sources
+	}
+	all := []string{}
+	for _, f := range s.fields {
+		all = append(all, f.sources...)
+	}
+	for idx, column := range columns {
+		sources[idx] = append(append([]string{}, all...), s.lookupDerived("", column)...)
+	}
+	return sources
+}
+
+// sqlQueryColumnSources returns the source columns of each column of the result by the SELECTs of the query.
+func sqlQueryColumnSources(selects []*sqlQuerySelect, columns []string) [][]string {
+	sources := make([][]string, len(columns))
+	for _, s := range selects {
+		for idx, columnSources := range s.columnSources(columns) {
+			sources[idx] = append(sources[idx], columnSources...)
+		}
+	}
+	return sources
+}
+
+// subqueryWildcardVisitor finds the wildcard in the subqueries of the fields, e.g. "SELECT (SELECT * FROM t)",
+// the columns read by the field are unknown.
+type subqueryWildcardVisitor struct {
+	found bool
+}
+
+func (v *subqueryWildcardVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	if field, ok := in.(*ast.SelectField); ok && field.Expr != nil {
+		field.Expr.Accept(&wildcardVisitor{found: &v.found})
+	}
+	return in, v.found
+}
+
+func (v *subqueryWildcardVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+type wildcardVisitor struct {
+	found *bool
+}
+
+func (v *wildcardVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	if field, ok := in.(*ast.SelectField); ok && field.WildCard != nil {
+		*v.found = true
+	}
+	return in, *v.found
+}
+
+func (v *wildcardVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/common"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/rewrite"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/sirupsen/logrus"
+)
+
+// RewriteTaskSQL rewrites the statement of the task by the audit rules it violates. The table definitions are loaded
+// from the instance of the task if it has one, the statements before it in the task are applied to the context so
+// the tables they create are known.
+func RewriteTaskSQL(l *logrus.Entry, task *model.Task, taskSql *model.ExecuteSQL) (*rewrite.Result, error) {
+	if task.DBType != driverV2.DriverTypeMySQL {
+		return nil, fmt.Errorf("sql rewriting unsupported database type: %s, only MySQL is supported", task.DBType)
+	}
+
+	ctx := session.NewContext(nil)
+	if task.Instance != nil {
+		dsn, err := common.NewDSN(task.Instance, task.Schema)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := executor.NewExecutor(l, dsn, task.Schema)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Db.Close()
+		ctx = session.NewContext(nil, session.WithExecutor(conn))
+	}
+	if task.Schema != "" {
+		ctx.SetCurrentSchema(task.Schema)
+	}
+
+	sqls, err := model.GetStorage().GetExecuteSQLsByTaskID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sql := range sqls {
+		if sql.Number >= taskSql.Number {
+			break
+		}
+		node, err := util.ParseOneSql(sql.Content)
+		if err != nil {
+			// the statements which can not be parsed do not change the context
+			continue
+		}
+		ctx.UpdateContext(node)
+	}
+
+	ruleNames := []string{}
+	for _, result := range taskSql.AuditResults {
+		ruleNames = append(ruleNames, result.RuleName)
+	}
+	return rewrite.Rewrite(ctx, taskSql.Content, ruleNames)
+}
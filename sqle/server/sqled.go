@@ -18,7 +18,9 @@ import (
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/metrics"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/tracing"
 	xerrors "github.com/pkg/errors"
 
 	"github.com/sirupsen/logrus"
@@ -44,6 +46,23 @@ type Sqled struct {
 	queue chan *action
 }
 
+func init() {
+	metrics.NewGaugeFunc("sqle_task_queue_depth", "Number of the tasks waiting in the sqled queue.", func() float64 {
+		if sqled == nil {
+			return 0
+		}
+		return float64(len(sqled.queue))
+	})
+	metrics.NewGaugeFunc("sqle_task_running", "Number of the tasks running by sqled.", func() float64 {
+		if sqled == nil {
+			return 0
+		}
+		sqled.Lock()
+		defer sqled.Unlock()
+		return float64(len(sqled.currentTask))
+	})
+}
+
 func InitSqled(exit chan struct{}) {
 	sqled = &Sqled{
 		exit:        exit,
@@ -62,7 +81,7 @@ func (s *Sqled) HasTask(taskId string) bool {
 
 // addTask receive taskId and action type, using taskId and typ to create an action;
 // action will be validated, and sent to Sqled.queue.
-func (s *Sqled) addTask(projectId string, taskId string, typ int, execSqlIds []uint) (*action, error) {
+func (s *Sqled) addTask(ctx context.Context, projectId string, taskId string, typ int, execSqlIds []uint) (*action, error) {
 	var err error
 	var p driver.Plugin
 	var rules []*model.Rule
@@ -72,6 +91,7 @@ func (s *Sqled) addTask(projectId string, taskId string, typ int, execSqlIds []u
 	// var drvMgr driver.DriverManager
 	entry := log.NewEntry().WithField("task_id", taskId)
 	action := &action{
+		ctx:   context.WithoutCancel(ctx),
 		typ:   typ,
 		entry: entry,
 		done:  make(chan struct{}),
@@ -140,12 +160,13 @@ Error:
 }
 
 func (s *Sqled) AddTask(projectId string, taskId string, typ int) error {
-	_, err := s.addTask(projectId, taskId, typ, nil)
+	_, err := s.addTask(context.Background(), projectId, taskId, typ, nil)
 	return err
 }
 
-func (s *Sqled) AddTaskWaitResult(projectId string, taskId string, typ int) (*model.Task, error) {
-	action, err := s.addTask(projectId, taskId, typ, nil)
+// AddTaskWaitResult runs the task and waits for the result, the spans of the task are the children of the span in ctx.
+func (s *Sqled) AddTaskWaitResult(ctx context.Context, projectId string, taskId string, typ int) (*model.Task, error) {
+	action, err := s.addTask(ctx, projectId, taskId, typ, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -154,7 +175,7 @@ func (s *Sqled) AddTaskWaitResult(projectId string, taskId string, typ int) (*mo
 }
 
 func (s *Sqled) AddTaskWaitResultWithSQLIds(projectId string, taskId string, execSqlIds []uint, typ int) (*model.Task, error) {
-	action, err := s.addTask(projectId, taskId, typ, execSqlIds)
+	action, err := s.addTask(context.Background(), projectId, taskId, typ, execSqlIds)
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +205,15 @@ func (s *Sqled) taskLoop() {
 
 func (s *Sqled) do(action *action) error {
 	var err error
+	begin := time.Now()
+	actionName := getActionTypeName(action.typ)
+	ctx, span := tracing.Start(action.ctx, "sqled."+actionName, tracing.WithAttributes(
+		tracing.Attr("task_id", action.task.ID),
+		tracing.Attr("db_type", action.task.DBType),
+		tracing.Attr("sql_count", len(action.task.ExecuteSQLs)),
+	))
+	action.ctx = ctx
+	action.entry = action.entry.WithContext(ctx)
 	switch action.typ {
 	case ActionTypeAudit:
 		err = action.audit()
@@ -192,8 +222,12 @@ func (s *Sqled) do(action *action) error {
 	case ActionTypeRollback:
 		err = action.rollback()
 	}
+	metrics.ObserveSince(metrics.TaskDuration, begin, actionName, action.task.DBType)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		action.err = err
+		metrics.TaskFailures.Inc(actionName, action.task.DBType)
 	}
 
 	action.plugin.Close(context.TODO())
@@ -214,10 +248,24 @@ const (
 	ActionTypeRollback
 )
 
+func getActionTypeName(typ int) string {
+	switch typ {
+	case ActionTypeAudit:
+		return "audit"
+	case ActionTypeExecute:
+		return "execute"
+	case ActionTypeRollback:
+		return "rollback"
+	}
+	return "unknown"
+}
+
 // Action is an action for the task;
 // when you want to execute a task, you can define an action whose type is rollback.
 type action struct {
 	sync.Mutex
+	// ctx holds the trace context of the task
+	ctx       context.Context
 	projectId string
 	plugin    driver.Plugin
 
@@ -233,6 +281,9 @@ type action struct {
 
 	customRules []*model.CustomRule
 	rules       []*model.Rule
+
+	// throttler is nil if the execution is not throttled
+	throttler *executionThrottler
 }
 
 const (
@@ -435,6 +486,8 @@ func (a *action) audit() (err error) {
 		"audit_level": a.task.AuditLevel,
 		"status":      a.task.Status,
 		"score":       a.task.Score,
+		// the breakdown is nil if the task has no SQL
+		"score_breakdown": a.task.ScoreBreakdown,
 	}); err != nil {
 		a.entry.Errorf("update task error:%v", err)
 		return err
@@ -607,6 +660,9 @@ func (a *action) GetTaskStatus(st *model.Storage) string {
 }
 
 func (a *action) execTask() (err error) {
+	if err = a.initExecutionThrottler(); err != nil {
+		return err
+	}
 	svc := BackupService{}
 	if svc.CheckCanTaskBackup(a.task) {
 		err = a.backupAndExecSql()
@@ -646,25 +702,39 @@ backupAndExecSql() 备份与执行SQL：
 */
 func (a *action) backupAndExecSql() error {
 	for _, executeSQL := range a.task.ExecuteSQLs {
-		backupMgr, err := getBackupManager(a.plugin, executeSQL, a.task.DBType, a.task.BackupMaxRows)
-		if err != nil {
-			_ = a.persistOnlineFailure(executeSQL, model.OnlineFailStageSQLBackup, err.Error())
-			return fmt.Errorf("in backupAndExecSql when getBackupManager, err %w , task: %v", err, a.task.ID)
-		}
-		if err = backupMgr.Backup(); err != nil {
-			// Backup() 已将业务原因放入 error；同时 defer 已写 backup_tasks
-			if persistErr := a.persistOnlineFailure(executeSQL, model.OnlineFailStageSQLBackup, err.Error()); persistErr != nil {
-				a.entry.Errorf("persist online failure after backup failed, task=%v sql=%v err=%v", a.task.ID, executeSQL.ID, persistErr)
+		if executeSQL.ExecMode == model.SQLExecModeChunked {
+			// 分批执行的SQL在执行每一批之前备份该批次的数据
+			if err := a.execChunkedSQL(executeSQL, true); err != nil {
+				return fmt.Errorf("in backupAndExecSql when execChunkedSQL %v, err %w, task: %v", executeSQL, err, a.task.ID)
 			}
-			return fmt.Errorf("in backupAndExecSql when backupMgr Backup, err %w, backup manager: %v, task: %v", err, backupMgr, a.task.ID)
+			continue
+		}
+		if err := a.backupSQL(executeSQL); err != nil {
+			return err
 		}
 		if err := a.execSQL(executeSQL); err != nil {
-			return fmt.Errorf("in backupAndExecSql when execSQL %v, err %w, backup manager: %v, task: %v", executeSQL, err, backupMgr, a.task.ID)
+			return fmt.Errorf("in backupAndExecSql when execSQL %v, err %w, task: %v", executeSQL, err, a.task.ID)
 		}
 	}
 	return nil
 }
 
+func (a *action) backupSQL(executeSQL *model.ExecuteSQL) error {
+	backupMgr, err := getBackupManager(a.plugin, executeSQL, a.task.DBType, a.task.BackupMaxRows)
+	if err != nil {
+		_ = a.persistOnlineFailure(executeSQL, model.OnlineFailStageSQLBackup, err.Error())
+		return fmt.Errorf("in backupAndExecSql when getBackupManager, err %w , task: %v", err, a.task.ID)
+	}
+	if err = backupMgr.Backup(); err != nil {
+		// Backup() 已将业务原因放入 error；同时 defer 已写 backup_tasks
+		if persistErr := a.persistOnlineFailure(executeSQL, model.OnlineFailStageSQLBackup, err.Error()); persistErr != nil {
+			a.entry.Errorf("persist online failure after backup failed, task=%v sql=%v err=%v", a.task.ID, executeSQL.ID, persistErr)
+		}
+		return fmt.Errorf("in backupAndExecSql when backupMgr Backup, err %w, backup manager: %v, task: %v", err, backupMgr, a.task.ID)
+	}
+	return nil
+}
+
 func (a *action) execSqlSqlMode() error {
 	// txSQLs keep adjacent DMLs, execute in one transaction.
 	task := a.task
@@ -672,8 +742,21 @@ func (a *action) execSqlSqlMode() error {
 	var err error
 	for i := range task.ExecuteSQLs {
 		executeSQL := task.ExecuteSQLs[i]
+		if executeSQL.ExecMode == model.SQLExecModeChunked {
+			// the batches of the chunked SQL are committed separately, so it can not join the transaction
+			if len(txSQLs) > 0 {
+				if err = a.execSQLs(txSQLs); err != nil {
+					return err
+				}
+				txSQLs = nil
+			}
+			if err = a.execChunkedSQL(executeSQL, false); err != nil {
+				return err
+			}
+			continue
+		}
 		var nodes []driverV2.Node
-		if nodes, err = a.plugin.Parse(context.TODO(), executeSQL.Content); err != nil {
+		if nodes, err = a.plugin.Parse(a.ctx, executeSQL.Content); err != nil {
 			return err
 		}
 
@@ -791,6 +874,9 @@ func checkBatchExecuteStatus(sqlBatch []*model.ExecuteSQL) error {
 // executeSQLBatch executes a batch of SQLs and updates their status.
 func (a *action) executeSQLBatch(executeSQLs []*model.ExecuteSQL) error {
 	st := model.GetStorage()
+	if err := a.waitForThrottle(executeSQLs[0]); err != nil {
+		return a.abortThrottledSQLs(executeSQLs, err)
+	}
 	// update status befor execute
 	for _, executeSQL := range executeSQLs {
 		executeSQL.ExecStatus = model.SQLExecuteStatusDoing
@@ -804,7 +890,10 @@ func (a *action) executeSQLBatch(executeSQLs []*model.ExecuteSQL) error {
 		sqls = append(sqls, sql.Content)
 	}
 
-	results, execErr := a.plugin.ExecBatch(context.TODO(), sqls...)
+	ctx, span := tracing.Start(a.ctx, "sqled.exec_batch", tracing.WithAttributes(tracing.Attr("sql_count", len(sqls))))
+	results, execErr := a.plugin.ExecBatch(ctx, sqls...)
+	span.RecordError(execErr)
+	span.End()
 	if execErr != nil {
 		for idx, executeSQL := range executeSQLs {
 			executeSQL.ExecStatus = model.SQLExecuteStatusFailed
@@ -851,12 +940,18 @@ func (a *action) executeSQLBatch(executeSQLs []*model.ExecuteSQL) error {
 // execSQL execute SQL and update SQL's executed status to storage.
 func (a *action) execSQL(executeSQL *model.ExecuteSQL) error {
 	st := model.GetStorage()
+	if err := a.waitForThrottle(executeSQL); err != nil {
+		return a.abortThrottledSQLs([]*model.ExecuteSQL{executeSQL}, err)
+	}
 
 	if err := st.UpdateExecuteSqlStatus(&executeSQL.BaseSQL, model.SQLExecuteStatusDoing, ""); err != nil {
 		return err
 	}
 
-	_, execErr := a.plugin.Exec(context.TODO(), executeSQL.Content)
+	ctx, span := tracing.Start(a.ctx, "sqled.exec_sql", tracing.WithAttributes(tracing.Attr("execute_sql_id", executeSQL.ID)))
+	_, execErr := a.plugin.Exec(ctx, executeSQL.Content)
+	span.RecordError(execErr)
+	span.End()
 	if execErr != nil {
 		executeSQL.ExecStatus = model.SQLExecuteStatusFailed
 		executeSQL.ExecResult = execErr.Error()
@@ -891,6 +986,9 @@ func (a *action) execSQL(executeSQL *model.ExecuteSQL) error {
 // execSQLs execute SQLs and update SQLs' executed status to storage.
 func (a *action) execSQLs(executeSQLs []*model.ExecuteSQL) error {
 	st := model.GetStorage()
+	if err := a.waitForThrottle(executeSQLs[0]); err != nil {
+		return a.abortThrottledSQLs(executeSQLs, err)
+	}
 
 	for _, executeSQL := range executeSQLs {
 		executeSQL.ExecStatus = model.SQLExecuteStatusDoing
@@ -904,7 +1002,10 @@ func (a *action) execSQLs(executeSQLs []*model.ExecuteSQL) error {
 		qs = append(qs, executeSQL.Content)
 	}
 
-	results, txErr := a.plugin.Tx(context.TODO(), qs...)
+	ctx, span := tracing.Start(a.ctx, "sqled.exec_tx", tracing.WithAttributes(tracing.Attr("sql_count", len(qs))))
+	results, txErr := a.plugin.Tx(ctx, qs...)
+	span.RecordError(txErr)
+	span.End()
 	for idx, executeSQL := range executeSQLs {
 		if results != nil && idx < len(results.ExecResult) {
 			rowAffects, _ := results.ExecResult[idx].RowsAffected()
@@ -998,7 +1099,7 @@ ExecSQLs:
 			return err
 		}
 
-		nodes, err := a.plugin.Parse(context.TODO(), rollbackSQL.Content)
+		nodes, err := a.plugin.Parse(a.ctx, rollbackSQL.Content)
 		if err != nil {
 			return err
 		}
@@ -1008,7 +1109,7 @@ ExecSQLs:
 				TaskId:  rollbackSQL.TaskId,
 				Content: node.Text,
 			}, ExecuteSQLId: rollbackSQL.ExecuteSQLId}
-			_, execErr = a.plugin.Exec(context.TODO(), node.Text)
+			_, execErr = a.plugin.Exec(a.ctx, node.Text)
 			if execErr != nil {
 				currentSQL.ExecStatus = model.SQLExecuteStatusFailed
 				currentSQL.ExecResult = execErr.Error()
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	dmsV1 "github.com/actiontech/dms/pkg/dms-common/api/dms/v1"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+// GetStepDelegator returns the assignee of the current step who delegates the user to operate the workflow,
+// it is empty if the user is not delegated. The delegate operates the step on behalf of the assignee but not
+// beyond its own permissions, so the delegation is ignored if the user can not operate the step itself.
+func GetStepDelegator(userId string, workflow *model.Workflow) (string, error) {
+	currentStep := workflow.CurrentStep()
+	if currentStep == nil || currentStep.Assignees == "" {
+		return "", nil
+	}
+	delegations, err := model.GetStorage().GetActiveUserDelegations(strings.Split(currentStep.Assignees, ","), time.Now())
+	if err != nil {
+		return "", err
+	}
+	delegator := findStepDelegator(delegations, userId, workflow)
+	if delegator == "" {
+		return "", nil
+	}
+	up, err := dms.NewUserPermission(userId, string(workflow.ProjectId))
+	if err != nil {
+		return "", err
+	}
+	if !canOperateStep(up, currentStep, workflow.GetInstanceIds()) {
+		log.NewEntry().Warnf("user %s is delegated by %s but has no permission to operate the step of workflow %s", userId, delegator, workflow.WorkflowId)
+		return "", nil
+	}
+	return delegator, nil
+}
+
+func findStepDelegator(delegations []*model.UserDelegation, userId string, workflow *model.Workflow) string {
+	for _, d := range delegations {
+		if d.DelegateId == userId && d.Covers(string(workflow.ProjectId), workflow.GetInstanceIds()) {
+			return d.DelegatorId
+		}
+	}
+	return ""
+}
+
+// delegatedOpPermissions are the permissions to operate the workflow steps, the delegate must hold the ones
+// held by the delegator.
+var delegatedOpPermissions = []dmsV1.OpPermissionType{
+	dmsV1.OpPermissionTypeAuditWorkflow,
+	dmsV1.OpPermissionTypeExecuteWorkflow,
+	dmsV1.OpPermissionTypeAuditExportWorkflow,
+}
+
+// opPermissionChecker is implemented by dms.UserPermission.
+type opPermissionChecker interface {
+	CanOpProjectForBusinessWrite() bool
+	CanOpInstanceNoAdmin(instanceId string, opTypes ...dmsV1.OpPermissionType) bool
+	GetInstancesByOP(opTypes ...dmsV1.OpPermissionType) []string
+}
+
+func stepOpPermission(step *model.WorkflowStep) dmsV1.OpPermissionType {
+	if step.Template == nil {
+		return dmsV1.OpPermissionTypeAuditWorkflow
+	}
+	switch step.Template.Typ {
+	case model.WorkflowStepTypeSQLExecute:
+		return dmsV1.OpPermissionTypeExecuteWorkflow
+	case model.WorkflowStepTypeExportReview:
+		return dmsV1.OpPermissionTypeAuditExportWorkflow
+	case model.WorkflowStepTypeExportExecute:
+		return dmsV1.OpPermissionTypeExportCreate
+	default:
+		return dmsV1.OpPermissionTypeAuditWorkflow
+	}
+}
+
+// canOperateStep checks whether the user has the permission of the step on all the instances of the workflow.
+func canOperateStep(up opPermissionChecker, step *model.WorkflowStep, instanceIds []uint64) bool {
+	if up.CanOpProjectForBusinessWrite() {
+		return true
+	}
+	if len(instanceIds) == 0 {
+		return false
+	}
+	op := stepOpPermission(step)
+	for _, id := range instanceIds {
+		if !up.CanOpInstanceNoAdmin(strconv.FormatUint(id, 10), op) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckDelegatePermissions checks the delegate is a member of the project and holds the permissions of the
+// delegator to operate the workflows, on the instance only if instanceId is not empty.
+func CheckDelegatePermissions(delegatorId, delegateId, projectId, instanceId string) error {
+	delegate, err := dms.NewUserPermission(delegateId, projectId)
+	if err != nil {
+		return err
+	}
+	if delegate.CanOpProjectForBusinessWrite() {
+		return nil
+	}
+	if !delegate.IsProjectMember() {
+		return errors.New(errors.DataInvalid, fmt.Errorf("the delegate is not a member of the project"))
+	}
+	delegator, err := dms.NewUserPermission(delegatorId, projectId)
+	if err != nil {
+		return err
+	}
+	return checkDelegatePermissions(delegator, delegate, instanceId)
+}
+
+func checkDelegatePermissions(delegator, delegate opPermissionChecker, instanceId string) error {
+	if delegate.CanOpProjectForBusinessWrite() {
+		return nil
+	}
+	for _, op := range delegatedOpPermissions {
+		for _, id := range delegator.GetInstancesByOP(op) {
+			if instanceId != "" && id != instanceId {
+				continue
+			}
+			if !delegate.CanOpInstanceNoAdmin(id, op) {
+				return errors.New(errors.DataInvalid, fmt.Errorf("the delegate has no permission %s on the instance %s", op, id))
+			}
+		}
+	}
+	return nil
+}
+
+// setStepOperationUser records the user operating the step, and the assignee on behalf of whom the user operates
+// if the user is a delegate.
+func setStepOperationUser(workflow *model.Workflow, step *model.WorkflowStep, user *model.User) error {
+	step.OperationUserId = user.GetIDStr()
+	step.OnBehalfOfUserId = ""
+	if workflow.IsOperationUser(user) {
+		return nil
+	}
+	delegator, err := GetStepDelegator(user.GetIDStr(), workflow)
+	if err != nil {
+		return err
+	}
+	step.OnBehalfOfUserId = delegator
+	return nil
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+
+	dmsV1 "github.com/actiontech/dms/pkg/dms-common/api/dms/v1"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockOpPermission struct {
+	projectAdmin bool
+	instances    map[dmsV1.OpPermissionType][]string
+}
+
+func (m *mockOpPermission) CanOpProjectForBusinessWrite() bool {
+	return m.projectAdmin
+}
+
+func (m *mockOpPermission) CanOpInstanceNoAdmin(instanceId string, opTypes ...dmsV1.OpPermissionType) bool {
+	for _, op := range opTypes {
+		for _, id := range m.instances[op] {
+			if id == instanceId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *mockOpPermission) GetInstancesByOP(opTypes ...dmsV1.OpPermissionType) []string {
+	ids := []string{}
+	for _, op := range opTypes {
+		ids = append(ids, m.instances[op]...)
+	}
+	return ids
+}
+
+func TestCheckDelegatePermissions(t *testing.T) {
+	delegator := &mockOpPermission{instances: map[dmsV1.OpPermissionType][]string{
+		dmsV1.OpPermissionTypeAuditWorkflow:   {"1", "2"},
+		dmsV1.OpPermissionTypeExecuteWorkflow: {"1"},
+	}}
+	delegate := &mockOpPermission{instances: map[dmsV1.OpPermissionType][]string{
+		dmsV1.OpPermissionTypeAuditWorkflow:   {"1", "2"},
+		dmsV1.OpPermissionTypeExecuteWorkflow: {"2"},
+	}}
+	assert.Error(t, checkDelegatePermissions(delegator, delegate, ""))
+	assert.Error(t, checkDelegatePermissions(delegator, delegate, "1"))
+	assert.NoError(t, checkDelegatePermissions(delegator, delegate, "2"))
+	assert.NoError(t, checkDelegatePermissions(delegator, &mockOpPermission{projectAdmin: true}, ""))
+}
+
+func TestCanOperateStep(t *testing.T) {
+	up := &mockOpPermission{instances: map[dmsV1.OpPermissionType][]string{
+		dmsV1.OpPermissionTypeAuditWorkflow:   {"1", "2"},
+		dmsV1.OpPermissionTypeExecuteWorkflow: {"1"},
+	}}
+	review := &model.WorkflowStep{Template: &model.WorkflowStepTemplate{Typ: model.WorkflowStepTypeSQLReview}}
+	execute := &model.WorkflowStep{Template: &model.WorkflowStepTemplate{Typ: model.WorkflowStepTypeSQLExecute}}
+	assert.True(t, canOperateStep(up, review, []uint64{1, 2}))
+	assert.False(t, canOperateStep(up, execute, []uint64{1, 2}))
+	assert.True(t, canOperateStep(up, execute, []uint64{1}))
+	assert.False(t, canOperateStep(up, review, nil))
+	assert.True(t, canOperateStep(&mockOpPermission{projectAdmin: true}, execute, []uint64{1, 2}))
+}
@@ -14,6 +14,7 @@ type WechatJob struct {
 }
 
 func NewWechatJob(entry *logrus.Entry) ServerJob {
+	entry = entry.WithField("job", "wechat")
 	w := new(WechatJob)
 	w.BaseJob = *NewBaseJob(entry, 60*time.Second, w.wechatRotation)
 	return w
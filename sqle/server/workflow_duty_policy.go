@@ -0,0 +1,160 @@
+package server
+
+import (
+	"fmt"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+// CheckWorkflowApproveDuty checks the separation of duties of the workflow template before the user approves the
+// current step of the workflow.
+func CheckWorkflowApproveDuty(workflow *model.Workflow, user *model.User) error {
+	return checkWorkflowDuty(workflow, user, model.WorkflowStepTypeSQLReview)
+}
+
+// CheckWorkflowExecuteDuty checks the separation of duties of the workflow template before the user executes the
+// workflow.
+func CheckWorkflowExecuteDuty(workflow *model.Workflow, user *model.User) error {
+	return checkWorkflowDuty(workflow, user, model.WorkflowStepTypeSQLExecute)
+}
+
+func checkWorkflowDuty(workflow *model.Workflow, user *model.User, stepType string) error {
+	// the workflows executed automatically are operated by sys user
+	if user.Name == model.DefaultSysUser {
+		return nil
+	}
+	policy, err := getWorkflowDutyPolicy(workflow)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+	auditLevel := ""
+	if policy.MinDistinctParticipants > 1 {
+		if auditLevel, err = getWorkflowAuditLevel(workflow); err != nil {
+			return err
+		}
+	}
+	onBehalfOfUserId := ""
+	if !workflow.IsOperationUser(user) {
+		if onBehalfOfUserId, err = GetStepDelegator(user.GetIDStr(), workflow); err != nil {
+			return err
+		}
+	}
+	if err := checkDutyPolicy(policy, workflow, user.GetIDStr(), onBehalfOfUserId, stepType, auditLevel); err != nil {
+		return errors.New(errors.UserNotPermission, err)
+	}
+	return nil
+}
+
+func getWorkflowDutyPolicy(workflow *model.Workflow) (*model.WorkflowTemplate, error) {
+	s := model.GetStorage()
+	var template *model.WorkflowTemplate
+	var exist bool
+	var err error
+	if workflow.WorkflowTemplateId != nil && *workflow.WorkflowTemplateId != 0 {
+		template, exist, err = s.GetWorkflowTemplateById(*workflow.WorkflowTemplateId)
+	} else {
+		template, exist, err = s.GetWorkflowTemplateByProjectId(workflow.ProjectId)
+	}
+	if err != nil || !exist {
+		return nil, err
+	}
+	return template, nil
+}
+
+// getWorkflowAuditLevel returns the highest audit level of the tasks of the workflow.
+func getWorkflowAuditLevel(workflow *model.Workflow) (string, error) {
+	taskIds := make([]uint, 0, len(workflow.Record.InstanceRecords))
+	for _, record := range workflow.Record.InstanceRecords {
+		taskIds = append(taskIds, record.TaskId)
+	}
+	if len(taskIds) == 0 {
+		return "", nil
+	}
+	tasks, _, err := model.GetStorage().GetTasksByIds(taskIds)
+	if err != nil {
+		return "", err
+	}
+	level := driverV2.RuleLevelNull
+	for _, task := range tasks {
+		if driverV2.RuleLevel(task.AuditLevel).More(level) {
+			level = driverV2.RuleLevel(task.AuditLevel)
+		}
+	}
+	return string(level), nil
+}
+
+// checkDutyPolicy explains which duty of the policy is violated if the user operates the step of the stepType.
+// The approvers of the workflow are the operation users of the approved review steps of its current record,
+// the assignees on behalf of whom the delegates approved the steps are regarded as approvers as well. The
+// onBehalfOfUserId is the assignee on behalf of whom the user operates the step if the user is a delegate.
+func checkDutyPolicy(policy *model.WorkflowTemplate, workflow *model.Workflow, userId, onBehalfOfUserId, stepType, auditLevel string) error {
+	approvers := []string{}
+	delegators := []string{}
+	for _, step := range workflow.Record.Steps {
+		if step.Template != nil && step.Template.Typ == model.WorkflowStepTypeSQLReview &&
+			step.State == model.WorkflowStepStateApprove && step.OperationUserId != "" {
+			approvers = append(approvers, step.OperationUserId)
+			if step.OnBehalfOfUserId != "" {
+				delegators = append(delegators, step.OnBehalfOfUserId)
+			}
+		}
+	}
+	operators := []string{userId}
+	if onBehalfOfUserId != "" {
+		operators = append(operators, onBehalfOfUserId)
+	}
+
+	switch stepType {
+	case model.WorkflowStepTypeSQLReview:
+		if policy.ForbidCreatorApprove && containsUserId(operators, workflow.CreateUserId) {
+			return fmt.Errorf("the workflow template %s does not allow the creator to approve the workflow", policy.Name)
+		}
+	case model.WorkflowStepTypeSQLExecute:
+		if policy.ForbidApproverExecute {
+			for _, approver := range append(approvers, delegators...) {
+				if containsUserId(operators, approver) {
+					return fmt.Errorf("the workflow template %s does not allow the approvers to execute the workflow", policy.Name)
+				}
+			}
+		}
+	}
+
+	if policy.MinDistinctParticipants <= 1 {
+		return nil
+	}
+	if policy.MinParticipantsAuditLevel != "" &&
+		!driverV2.RuleLevel(auditLevel).MoreOrEqual(driverV2.RuleLevel(policy.MinParticipantsAuditLevel)) {
+		return nil
+	}
+	participants := mergeUserIds([]string{workflow.CreateUserId}, append(approvers, userId))
+	required := int(policy.MinDistinctParticipants)
+	switch stepType {
+	case model.WorkflowStepTypeSQLReview:
+		// the approval of the last review step is rejected if even a new executor can not make up the participants
+		next := workflow.NextStep()
+		if next != nil && next.Template != nil && next.Template.Typ == model.WorkflowStepTypeSQLExecute && len(participants)+1 < required {
+			return fmt.Errorf("the workflow template %s requires at least %d distinct creator, approvers and executor for the workflows whose audit level is %s, the workflow would have at most %d",
+				policy.Name, required, auditLevel, len(participants)+1)
+		}
+	case model.WorkflowStepTypeSQLExecute:
+		if len(participants) < required {
+			return fmt.Errorf("the workflow template %s requires at least %d distinct creator, approvers and executor for the workflows whose audit level is %s, the workflow would have %d if you execute it",
+				policy.Name, required, auditLevel, len(participants))
+		}
+	}
+	return nil
+}
+
+func containsUserId(userIds []string, userId string) bool {
+	for _, id := range userIds {
+		if id == userId {
+			return true
+		}
+	}
+	return false
+}
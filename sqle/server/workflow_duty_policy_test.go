@@ -0,0 +1,83 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDutyWorkflow(currentStepIdx int, approvers ...string) *model.Workflow {
+	review := &model.WorkflowStepTemplate{Typ: model.WorkflowStepTypeSQLReview}
+	execute := &model.WorkflowStepTemplate{Typ: model.WorkflowStepTypeSQLExecute}
+	steps := []*model.WorkflowStep{}
+	for i := 0; i < 2; i++ {
+		step := &model.WorkflowStep{Template: review}
+		step.ID = uint(i + 1)
+		if i < len(approvers) {
+			step.State = model.WorkflowStepStateApprove
+			step.OperationUserId = approvers[i]
+		}
+		steps = append(steps, step)
+	}
+	step := &model.WorkflowStep{Template: execute}
+	step.ID = 3
+	steps = append(steps, step)
+	return &model.Workflow{
+		CreateUserId: "1",
+		Record: &model.WorkflowRecord{
+			Steps:                 steps,
+			CurrentWorkflowStepId: steps[currentStepIdx].ID,
+		},
+	}
+}
+
+func TestCheckDutyPolicy(t *testing.T) {
+	review, execute := model.WorkflowStepTypeSQLReview, model.WorkflowStepTypeSQLExecute
+
+	// no policy
+	policy := &model.WorkflowTemplate{Name: "t"}
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(0), "1", "", review, "error"))
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "1", "1"), "1", "", execute, "error"))
+
+	// the creator can not approve
+	policy = &model.WorkflowTemplate{Name: "t", ForbidCreatorApprove: true}
+	assert.Error(t, checkDutyPolicy(policy, mockDutyWorkflow(0), "1", "", review, ""))
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(0), "2", "", review, ""))
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "2", "3"), "1", "", execute, ""))
+
+	// the approvers can not execute
+	policy = &model.WorkflowTemplate{Name: "t", ForbidApproverExecute: true}
+	assert.Error(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "2", "3"), "3", "", execute, ""))
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "2", "3"), "1", "", execute, ""))
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(1, "2"), "2", "", review, ""))
+
+	// at least 3 distinct participants for the workflows at warn level or above
+	policy = &model.WorkflowTemplate{Name: "t", MinDistinctParticipants: 3, MinParticipantsAuditLevel: "warn"}
+	assert.Error(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "1", "1"), "1", "", execute, "warn"))
+	assert.Error(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "1", "2"), "2", "", execute, "error"))
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "1", "2"), "3", "", execute, "error"))
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "1", "1"), "1", "", execute, "notice"))
+	// the approval of the last review step needs a new executor at most
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(1, "1"), "2", "", review, "error"))
+	assert.Error(t, checkDutyPolicy(policy, mockDutyWorkflow(1, "1"), "1", "", review, "error"))
+	// the approval of the other review steps is not limited
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(0), "1", "", review, "error"))
+
+	// all workflows without audit level threshold
+	policy = &model.WorkflowTemplate{Name: "t", MinDistinctParticipants: 2}
+	assert.Error(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "1", "1"), "1", "", execute, ""))
+	assert.NoError(t, checkDutyPolicy(policy, mockDutyWorkflow(2, "1", "1"), "2", "", execute, ""))
+
+	// the delegators of the approvers are regarded as approvers
+	policy = &model.WorkflowTemplate{Name: "t", ForbidApproverExecute: true}
+	workflow := mockDutyWorkflow(2, "2", "3")
+	workflow.Record.Steps[0].OnBehalfOfUserId = "4"
+	assert.Error(t, checkDutyPolicy(policy, workflow, "4", "", execute, ""))
+	// the user executing on behalf of an approver is regarded as the approver
+	assert.Error(t, checkDutyPolicy(policy, workflow, "5", "3", execute, ""))
+	assert.NoError(t, checkDutyPolicy(policy, workflow, "5", "6", execute, ""))
+	// the user approving on behalf of the creator is regarded as the creator
+	policy = &model.WorkflowTemplate{Name: "t", ForbidCreatorApprove: true}
+	assert.Error(t, checkDutyPolicy(policy, mockDutyWorkflow(0), "2", "1", review, ""))
+}
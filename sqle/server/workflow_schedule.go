@@ -167,7 +167,7 @@ func ExecuteWorkflow(workflow *model.Workflow, needExecTaskIdToUserId map[uint]s
 		id := taskId
 		go func() {
 			sqledServer := GetSqled()
-			task, err := sqledServer.AddTaskWaitResult(string(workflow.ProjectId), strconv.Itoa(int(id)), ActionTypeExecute)
+			task, err := sqledServer.AddTaskWaitResult(context.Background(), string(workflow.ProjectId), strconv.Itoa(int(id)), ActionTypeExecute)
 
 			{ // NOTE: Update the workflow status before sending notifications to ensure that the notification content reflects the latest information.
 				lock.Lock()
@@ -267,12 +267,17 @@ func ApproveWorkflowProcess(workflow *model.Workflow, user *model.User, s *model
 		return errors.New(errors.DataInvalid,
 			fmt.Errorf("workflow has been approved, you should to execute it"))
 	}
+	if err := CheckWorkflowApproveDuty(workflow, user); err != nil {
+		return err
+	}
 
+	if err := setStepOperationUser(workflow, currentStep, user); err != nil {
+		return err
+	}
 	currentStep.State = model.WorkflowStepStateApprove
 	currentStep.Reason = reason
 	now := time.Now()
 	currentStep.OperateAt = &now
-	currentStep.OperationUserId = user.GetIDStr()
 	nextStep := workflow.NextStep()
 	workflow.Record.CurrentWorkflowStepId = nextStep.ID
 	if nextStep.Template.Typ == model.WorkflowStepTypeSQLExecute {
@@ -291,11 +296,13 @@ func ApproveWorkflowProcess(workflow *model.Workflow, user *model.User, s *model
 
 func RejectWorkflowProcess(workflow *model.Workflow, reason string, user *model.User, s *model.Storage) error {
 	currentStep := workflow.CurrentStep()
+	if err := setStepOperationUser(workflow, currentStep, user); err != nil {
+		return err
+	}
 	currentStep.State = model.WorkflowStepStateReject
 	currentStep.Reason = reason
 	now := time.Now()
 	currentStep.OperateAt = &now
-	currentStep.OperationUserId = user.GetIDStr()
 
 	workflow.Record.Status = model.WorkflowStatusReject
 	workflow.Record.CurrentWorkflowStepId = 0
@@ -418,6 +425,13 @@ func CheckCurrentUserCanOperateWorkflowByUser(user *model.User, projectUid strin
 	if access {
 		return nil
 	}
+	delegator, err := GetStepDelegator(user.GetIDStr(), workflow)
+	if err != nil {
+		return err
+	}
+	if delegator != "" {
+		return nil
+	}
 	if len(ops) > 0 {
 		for _, item := range workflow.Record.InstanceRecords {
 			if !up.CanOpInstanceNoAdmin(item.Instance.GetIDStr(), ops...) {
@@ -443,13 +457,21 @@ func CheckUserCanOperateStep(user *model.User, workflow *model.Workflow, stepId
 	}
 
 	// sys 用户和 admin 用户可以直接操作工单
-	if user.Name == model.DefaultSysUser || user.Name == model.DefaultAdminUser {
-		return nil
+	isSuperUser := user.Name == model.DefaultSysUser || user.Name == model.DefaultAdminUser
+	if !isSuperUser && !workflow.IsOperationUser(user) {
+		// the delegates of the assignees can operate the step while their delegations are active
+		delegator, err := GetStepDelegator(user.GetIDStr(), workflow)
+		if err != nil {
+			return err
+		}
+		if delegator == "" {
+			return fmt.Errorf("you are not allow to operate the workflow")
+		}
 	}
 
-	if !workflow.IsOperationUser(user) {
-		return fmt.Errorf("you are not allow to operate the workflow")
+	// the separation of duties also applies to admin
+	if currentStep.Template != nil && currentStep.Template.Typ == model.WorkflowStepTypeSQLExecute {
+		return CheckWorkflowExecuteDuty(workflow, user)
 	}
-
 	return nil
 }
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/notification"
+	"github.com/sirupsen/logrus"
+)
+
+// WorkflowSLAJob reminds the assignees of the pending workflow steps with SLA, and escalates the steps waiting
+// longer than the escalation duration of their templates.
+type WorkflowSLAJob struct {
+	BaseJob
+}
+
+func NewWorkflowSLAJob(entry *logrus.Entry) ServerJob {
+	entry = entry.WithField("job", "workflow_sla")
+	j := &WorkflowSLAJob{}
+	j.BaseJob = *NewBaseJob(entry, time.Minute, j.CheckWorkflowSLA)
+	return j
+}
+
+func (j *WorkflowSLAJob) CheckWorkflowSLA(entry *logrus.Entry) {
+	st := model.GetStorage()
+	steps, err := st.GetPendingSLAWorkflowSteps()
+	if err != nil {
+		entry.Errorf("get pending workflow steps with sla error: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, step := range steps {
+		if err := checkWorkflowStepSLA(entry, step, now); err != nil {
+			entry.Errorf("check sla of workflow %s step %d error: %v", step.WorkflowId, step.StepNumber, err)
+		}
+	}
+}
+
+type slaAction int
+
+const (
+	slaActionNone slaAction = iota
+	slaActionRemind
+	slaActionEscalate
+)
+
+// decideSLAAction escalates the step once when it waits longer than the escalation duration, and reminds its
+// assignees every reminder interval otherwise.
+func decideSLAAction(step *model.PendingSLAStep, sla *model.WorkflowStepSLA, now time.Time) slaAction {
+	startAt := step.StartAt()
+	if startAt == nil {
+		return slaActionNone
+	}
+	if step.SLAEscalateAfterMinute > 0 && sla.BreachedAt == nil &&
+		now.Sub(*startAt) >= time.Duration(step.SLAEscalateAfterMinute)*time.Minute {
+		return slaActionEscalate
+	}
+	if step.SLARemindIntervalMinute > 0 {
+		last := startAt
+		if sla.LastRemindAt != nil {
+			last = sla.LastRemindAt
+		}
+		if now.Sub(*last) >= time.Duration(step.SLARemindIntervalMinute)*time.Minute {
+			return slaActionRemind
+		}
+	}
+	return slaActionNone
+}
+
+func checkWorkflowStepSLA(entry *logrus.Entry, step *model.PendingSLAStep, now time.Time) error {
+	startAt := step.StartAt()
+	if startAt == nil {
+		return nil
+	}
+	st := model.GetStorage()
+	sla, exist, err := st.GetWorkflowStepSLAByStepId(step.StepId)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		sla = &model.WorkflowStepSLA{
+			ProjectId:      model.ProjectUID(step.ProjectId),
+			WorkflowId:     step.WorkflowId,
+			WorkflowStepId: step.StepId,
+			StepNumber:     step.StepNumber,
+			StepStartAt:    startAt,
+		}
+	}
+	waited := now.Sub(*startAt)
+	assignees := splitUserIds(step.Assignees)
+
+	switch decideSLAAction(step, sla, now) {
+	case slaActionRemind:
+		sla.RemindCount++
+		sla.LastRemindAt = &now
+		if err := st.Save(sla); err != nil {
+			return err
+		}
+		return notification.NotifyWorkflowSLA(step, assignees, waited, false)
+	case slaActionEscalate:
+		targets, err := getSLAEscalateTargets(step)
+		if err != nil {
+			// the breach is still recorded without escalation
+			entry.Errorf("get escalation target of workflow %s step %d error: %v", step.WorkflowId, step.StepNumber, err)
+		}
+		newAssignees := escalateAssignees(step.StepType, step.SLAEscalateMode, assignees, targets)
+		sla.BreachedAt = &now
+		sla.LastRemindAt = &now
+		sla.OriginalAssignees = step.Assignees
+		sla.EscalatedTo = strings.Join(targets, ",")
+		sla.EscalateMode = step.SLAEscalateMode
+		if err := st.EscalateWorkflowStep(sla, strings.Join(newAssignees, ",")); err != nil {
+			return err
+		}
+		entry.Infof("workflow %s step %d breaches its sla after %s, escalated to %v", step.WorkflowId, step.StepNumber, waited, targets)
+		return notification.NotifyWorkflowSLA(step, mergeUserIds(newAssignees, targets), waited, true)
+	}
+	return nil
+}
+
+func getSLAEscalateTargets(step *model.PendingSLAStep) ([]string, error) {
+	targets := splitUserIds(step.SLAEscalateUsers)
+	if step.SLAEscalateMemberGroupUid != "" {
+		userIds, err := dms.GetMemberGroupUserIds(context.TODO(), step.ProjectId, step.SLAEscalateMemberGroupUid)
+		if err != nil {
+			return targets, err
+		}
+		targets = mergeUserIds(targets, userIds)
+	}
+	return targets, nil
+}
+
+// escalateAssignees only changes the assignees of the review steps, the executors of a workflow are bound to
+// its instances, so the escalation targets of the other steps are only notified.
+func escalateAssignees(stepType, mode string, assignees, targets []string) []string {
+	if len(targets) == 0 || stepType != model.WorkflowStepTypeSQLReview {
+		return assignees
+	}
+	if mode == model.SLAEscalateModeReassign {
+		return targets
+	}
+	return mergeUserIds(assignees, targets)
+}
+
+func splitUserIds(ids string) []string {
+	userIds := []string{}
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			userIds = append(userIds, id)
+		}
+	}
+	return userIds
+}
+
+func mergeUserIds(a, b []string) []string {
+	merged := make([]string, 0, len(a)+len(b))
+	seen := map[string]bool{}
+	for _, id := range append(append([]string{}, a...), b...) {
+		if !seen[id] {
+			seen[id] = true
+			merged = append(merged, id)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecideSLAAction(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-50 * time.Minute)
+	step := &model.PendingSLAStep{
+		StepCreatedAt:           &created,
+		SLARemindIntervalMinute: 30,
+		SLAEscalateAfterMinute:  120,
+	}
+	sla := &model.WorkflowStepSLA{}
+	assert.Equal(t, slaActionRemind, decideSLAAction(step, sla, now))
+
+	remindAt := now.Add(-10 * time.Minute)
+	sla.LastRemindAt = &remindAt
+	assert.Equal(t, slaActionNone, decideSLAAction(step, sla, now))
+
+	// the step starts to wait after the previous step is operated
+	prevOperateAt := now.Add(-130 * time.Minute)
+	step.PrevOperateAt = &prevOperateAt
+	assert.Equal(t, slaActionEscalate, decideSLAAction(step, sla, now))
+
+	// a step is only escalated once
+	sla.BreachedAt = &now
+	assert.Equal(t, slaActionNone, decideSLAAction(step, sla, now))
+	assert.Equal(t, slaActionRemind, decideSLAAction(step, sla, now.Add(20*time.Minute)))
+}
+
+func TestEscalateAssignees(t *testing.T) {
+	assignees := []string{"1", "2"}
+	targets := []string{"2", "3"}
+	assert.Equal(t, []string{"1", "2", "3"}, escalateAssignees(model.WorkflowStepTypeSQLReview, model.SLAEscalateModeAdd, assignees, targets))
+	assert.Equal(t, []string{"2", "3"}, escalateAssignees(model.WorkflowStepTypeSQLReview, model.SLAEscalateModeReassign, assignees, targets))
+	assert.Equal(t, assignees, escalateAssignees(model.WorkflowStepTypeSQLReview, model.SLAEscalateModeReassign, assignees, nil))
+	assert.Equal(t, assignees, escalateAssignees(model.WorkflowStepTypeSQLExecute, model.SLAEscalateModeReassign, assignees, targets))
+	assert.Equal(t, []string{"1", "2"}, splitUserIds(" 1,,2 "))
+}
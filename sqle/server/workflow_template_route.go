@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/common"
+	"github.com/actiontech/sqle/sqle/driver"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/sirupsen/logrus"
+)
+
+type WorkflowTemplateRouteEvaluation struct {
+	Rule    *model.WorkflowTemplateRouteRule
+	Matched bool
+	Reasons []string
+}
+
+// WorkflowTemplateRoute explains how the workflow template of a new workflow is selected. Rule is nil if the
+// default workflow template is selected.
+type WorkflowTemplateRoute struct {
+	Template    *model.WorkflowTemplate
+	Rule        *model.WorkflowTemplateRouteRule
+	Attributes  *model.WorkflowRouteAttributes
+	Evaluations []*WorkflowTemplateRouteEvaluation
+}
+
+// RouteWorkflowTemplate selects the workflow template of a new workflow of the tasks by the route rules of the
+// project, the instances of the tasks should be loaded.
+func RouteWorkflowTemplate(l *logrus.Entry, projectId model.ProjectUID, tasks []*model.Task, opsTypeUID string) (*WorkflowTemplateRoute, error) {
+	s := model.GetStorage()
+	rules, err := s.GetWorkflowTemplateRouteRules(projectId)
+	if err != nil {
+		return nil, err
+	}
+	enabledRules := []*model.WorkflowTemplateRouteRule{}
+	needAffectedRows := false
+	for _, rule := range rules {
+		if rule.Enabled {
+			enabledRules = append(enabledRules, rule)
+			needAffectedRows = needAffectedRows || rule.NeedAffectedRows()
+		}
+	}
+	attrs, err := getWorkflowRouteAttributes(l, tasks, opsTypeUID, needAffectedRows)
+	if err != nil {
+		return nil, err
+	}
+
+	route := &WorkflowTemplateRoute{Attributes: attrs}
+	for _, rule := range enabledRules {
+		matched, reasons := rule.Match(attrs)
+		route.Evaluations = append(route.Evaluations, &WorkflowTemplateRouteEvaluation{Rule: rule, Matched: matched, Reasons: reasons})
+		if !matched {
+			continue
+		}
+		template, exist, err := s.GetWorkflowTemplateByProjectIdAndId(projectId, rule.WorkflowTemplateId)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			template = nil
+		}
+		if err := model.ValidateWorkflowTemplateForCreate(template, projectId); err != nil {
+			// the rule is skipped if its template is removed
+			last := route.Evaluations[len(route.Evaluations)-1]
+			last.Matched = false
+			last.Reasons = append(last.Reasons, fmt.Sprintf("workflow template %d is skipped: %v", rule.WorkflowTemplateId, err))
+			continue
+		}
+		route.Template, route.Rule = template, rule
+		return route, nil
+	}
+
+	template, err := s.ResolveWorkflowTemplateForCreate(projectId, nil)
+	if err != nil {
+		return nil, err
+	}
+	route.Template = template
+	return route, nil
+}
+
+func getWorkflowRouteAttributes(l *logrus.Entry, tasks []*model.Task, opsTypeUID string, needAffectedRows bool) (*model.WorkflowRouteAttributes, error) {
+	attrs := &model.WorkflowRouteAttributes{OpsTypeUID: opsTypeUID}
+	level := driverV2.RuleLevelNull
+	envTags, sqlTypes := map[string]bool{}, map[string]bool{}
+	var affectedRows int64
+	for _, task := range tasks {
+		if driverV2.RuleLevel(task.AuditLevel).More(level) {
+			level = driverV2.RuleLevel(task.AuditLevel)
+		}
+		if task.Instance != nil && !envTags[task.Instance.EnvironmentTagName] {
+			envTags[task.Instance.EnvironmentTagName] = true
+			attrs.EnvironmentTags = append(attrs.EnvironmentTags, task.Instance.EnvironmentTagName)
+		}
+		sqls, err := model.GetStorage().GetExecuteSQLsByTaskID(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, sql := range sqls {
+			typ := routeSQLType(sql)
+			if typ != "" && !sqlTypes[typ] {
+				sqlTypes[typ] = true
+				attrs.SQLTypes = append(attrs.SQLTypes, typ)
+			}
+		}
+		if needAffectedRows && attrs.AffectedRowsErr == "" {
+			rows, err := estimateTaskAffectedRows(l, task, sqls)
+			if err != nil {
+				attrs.AffectedRowsErr = err.Error()
+			}
+			affectedRows += rows
+		}
+	}
+	attrs.AuditLevel = string(level)
+	if needAffectedRows && attrs.AffectedRowsErr == "" {
+		attrs.AffectedRows = &affectedRows
+	}
+	return attrs, nil
+}
+
+// estimateTaskAffectedRows sums the estimated affected rows of the DML statements of the task.
+func estimateTaskAffectedRows(l *logrus.Entry, task *model.Task, sqls []*model.ExecuteSQL) (int64, error) {
+	var rows int64
+	dmls := []string{}
+	for _, sql := range sqls {
+		if sql.SQLType == driverV2.SQLTypeDML {
+			dmls = append(dmls, sql.Content)
+		}
+	}
+	if len(dmls) == 0 {
+		return 0, nil
+	}
+	if task.Instance == nil {
+		return 0, fmt.Errorf("instance of task %d is not exist", task.ID)
+	}
+	if !driver.GetPluginManager().IsOptionalModuleEnabled(task.Instance.DbType, driverV2.OptionalModuleEstimateSQLAffectRows) {
+		return 0, driver.NewErrPluginAPINotImplement(driverV2.OptionalModuleEstimateSQLAffectRows)
+	}
+	dsn, err := common.NewDSN(task.Instance, task.Schema)
+	if err != nil {
+		return 0, err
+	}
+	plugin, err := driver.GetPluginManager().OpenPlugin(l, task.Instance.DbType, &driverV2.Config{DSN: dsn})
+	if err != nil {
+		return 0, err
+	}
+	defer plugin.Close(context.TODO())
+	for _, sql := range dmls {
+		res, err := plugin.EstimateSQLAffectRows(context.TODO(), sql)
+		if err != nil {
+			return 0, err
+		}
+		if res.ErrMessage != "" {
+			return 0, fmt.Errorf("estimate affected rows of %q failed: %s", sql, res.ErrMessage)
+		}
+		rows += res.Count
+	}
+	return rows, nil
+}
+
+// SQLTypeDCL is the type of the statements controlling the privileges. The drivers classify them as DDL, they are
+// told apart from DDL by the route rules only.
+const SQLTypeDCL = "dcl"
+
+var dclPattern = regexp.MustCompile(`(?is)^(grant|revoke|(create|alter|drop|rename)\s+(user|role)|set\s+(password|role|default\s+role))\b`)
+var leadingCommentsPattern = regexp.MustCompile(`^(\s+|/\*.*?\*/|(--|#)[^\n]*)*`)
+
+// routeSQLType returns the type of the statement to match the route rules, DCL is told apart from DDL by its
+// leading keywords so that it works for all the drivers.
+func routeSQLType(sql *model.ExecuteSQL) string {
+	if sql.SQLType != driverV2.SQLTypeDDL {
+		return sql.SQLType
+	}
+	content := leadingCommentsPattern.ReplaceAllString(sql.Content, "")
+	if dclPattern.MatchString(strings.TrimSpace(content)) {
+		return SQLTypeDCL
+	}
+	return sql.SQLType
+}
+
+// ValidateWorkflowTemplateRouteRule checks the conditions of the rule, the statement types are the ones the drivers
+// classify and DCL.
+func ValidateWorkflowTemplateRouteRule(rule *model.WorkflowTemplateRouteRule) error {
+	sqlTypes := append(append([]string{}, rule.AnySQLTypes...), rule.OnlySQLTypes...)
+	for _, typ := range sqlTypes {
+		if typ != driverV2.SQLTypeDML && typ != driverV2.SQLTypeDDL && typ != driverV2.SQLTypeDQL && typ != SQLTypeDCL {
+			return fmt.Errorf("statement type %s is invalid, it should be one of %s, %s, %s and %s", typ, driverV2.SQLTypeDML, driverV2.SQLTypeDDL, driverV2.SQLTypeDQL, SQLTypeDCL)
+		}
+	}
+	if rule.MinAuditLevel != "" && rule.MaxAuditLevel != "" &&
+		driverV2.RuleLevel(rule.MinAuditLevel).More(driverV2.RuleLevel(rule.MaxAuditLevel)) {
+		return fmt.Errorf("min audit level %s is higher than max audit level %s", rule.MinAuditLevel, rule.MaxAuditLevel)
+	}
+	if rule.MinAffectedRows != nil && *rule.MinAffectedRows < 0 {
+		return fmt.Errorf("min affected rows should not be negative")
+	}
+	if rule.MinAffectedRows != nil && rule.MaxAffectedRows != nil && *rule.MinAffectedRows > *rule.MaxAffectedRows {
+		return fmt.Errorf("min affected rows %d is more than max affected rows %d", *rule.MinAffectedRows, *rule.MaxAffectedRows)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWorkflowTemplateRouteRule(t *testing.T) {
+	rows := func(n int64) *int64 { return &n }
+	assert.NoError(t, ValidateWorkflowTemplateRouteRule(&model.WorkflowTemplateRouteRule{}))
+	assert.NoError(t, ValidateWorkflowTemplateRouteRule(&model.WorkflowTemplateRouteRule{
+		AnySQLTypes:     model.Strings{"ddl"},
+		OnlySQLTypes:    model.Strings{"dml", "dql"},
+		MinAuditLevel:   "notice",
+		MaxAuditLevel:   "error",
+		MinAffectedRows: rows(0),
+		MaxAffectedRows: rows(100),
+	}))
+	assert.NoError(t, ValidateWorkflowTemplateRouteRule(&model.WorkflowTemplateRouteRule{AnySQLTypes: model.Strings{"dcl"}}))
+	assert.Error(t, ValidateWorkflowTemplateRouteRule(&model.WorkflowTemplateRouteRule{AnySQLTypes: model.Strings{"tcl"}}))
+	assert.Error(t, ValidateWorkflowTemplateRouteRule(&model.WorkflowTemplateRouteRule{MinAuditLevel: "error", MaxAuditLevel: "warn"}))
+	assert.Error(t, ValidateWorkflowTemplateRouteRule(&model.WorkflowTemplateRouteRule{MinAffectedRows: rows(-1)}))
+	assert.Error(t, ValidateWorkflowTemplateRouteRule(&model.WorkflowTemplateRouteRule{MinAffectedRows: rows(10), MaxAffectedRows: rows(1)}))
+}
+
+func TestRouteSQLType(t *testing.T) {
+	for content, expected := range map[string]string{
+		"GRANT SELECT ON db.* TO 'u'@'%'":        SQLTypeDCL,
+		"/* comment */ revoke all on *.* from u": SQLTypeDCL,
+		"-- comment\nCREATE USER u":              SQLTypeDCL,
+		"set password for u = 'p'":               SQLTypeDCL,
+		"CREATE TABLE user (id int)":             "ddl",
+		"CREATE USERS (id int)":                  "ddl",
+	} {
+		assert.Equal(t, expected, routeSQLType(&model.ExecuteSQL{BaseSQL: model.BaseSQL{Content: content, SQLType: "ddl"}}), content)
+	}
+	assert.Equal(t, "dml", routeSQLType(&model.ExecuteSQL{BaseSQL: model.BaseSQL{Content: "GRANT", SQLType: "dml"}}))
+}
@@ -2,6 +2,7 @@ package workflowexport
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	dmsV1 "github.com/actiontech/dms/pkg/dms-common/api/dms/v1"
@@ -467,13 +468,22 @@ func pruneEmptyColumns(header []string, rows [][]string) ([]string, [][]string)
 	return newHeader, newRows
 }
 
+// stepOperatorName also shows the assignee on behalf of whom the step is operated by a delegate.
+func stepOperatorName(ctx context.Context, step *model.WorkflowStep) string {
+	operator := dms.GetUserNameWithDelTag(step.OperationUserId)
+	if step.OnBehalfOfUserId == "" || operator == "" {
+		return operator
+	}
+	return fmt.Sprintf(locale.Bundle.LocalizeMsgByCtx(ctx, locale.WorkflowStepOnBehalfOf), operator, dms.GetUserNameWithDelTag(step.OnBehalfOfUserId))
+}
+
 func formatAuditRecordFromSQLSteps(ctx context.Context, steps []*model.WorkflowStep) string {
 	parts := make([]string, 0, len(steps))
 	for _, step := range steps {
 		if step == nil {
 			continue
 		}
-		auditor := dms.GetUserNameWithDelTag(step.OperationUserId)
+		auditor := stepOperatorName(ctx, step)
 		opTime := step.OperationTime()
 		resultMsg := workflowStepStateMap[step.State]
 		result := ""
@@ -607,7 +617,7 @@ func getAuditListFixed(ctx context.Context, workflow *model.Workflow) (workflowL
 			break
 		}
 		stepIndex := i * stepSize
-		auditNodeList[stepIndex] = dms.GetUserNameWithDelTag(step.OperationUserId)
+		auditNodeList[stepIndex] = stepOperatorName(ctx, step)
 		auditNodeList[stepIndex+1] = step.OperationTime()
 		auditNodeList[stepIndex+2] = locale.Bundle.LocalizeMsgByCtx(ctx, workflowStepStateMap[step.State])
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	dmsCommonAes "github.com/actiontech/dms/pkg/dms-common/pkg/aes"
@@ -17,10 +18,15 @@ import (
 
 	"github.com/actiontech/sqle/sqle/config"
 	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/metrics"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/server"
 	"github.com/actiontech/sqle/sqle/server/cluster"
+	"github.com/actiontech/sqle/sqle/tracing"
 
 	"github.com/facebookgo/grace/gracenet"
 )
@@ -61,6 +67,18 @@ func Run(options *config.SqleOptions) error {
 	net := &gracenet.Net{}
 	go NotifySignal(exitChan, net, sqleCnf.LogPath)
 
+	// init tracing before plugins, the plugin processes inherit the tracing config by environment variables
+	if err := initTracing(sqleCnf.Tracing); err != nil {
+		return fmt.Errorf("init tracing error: %v", err)
+	}
+	defer tracing.Shutdown()
+
+	// load the extra locale bundles before plugins, the rules of the built-in plugin are localized by them,
+	// and the plugin processes inherit the enabled languages by environment variables
+	if err := initLocale(sqleCnf.LocalePath); err != nil {
+		return fmt.Errorf("init locale error: %v", err)
+	}
+
 	// init plugins
 	{
 		defer driver.GetPluginManager().Stop()
@@ -125,6 +143,15 @@ func Run(options *config.SqleOptions) error {
 		{
 			go optimization.SyncOptimizeResult()
 		}
+		if sqleCnf.AutoMigrateTable {
+			driver.AfterPluginLoadedHook = func(meta *driverV2.DriverMetas) {
+				if err := createPluginRules(s, meta); err != nil {
+					log.Logger().Errorf("create rules of plugin %s failed: %v", meta.PluginName, err)
+				}
+			}
+		}
+		// supervise plugins after the rules of the plugins loaded at runtime can be created
+		driver.GetPluginManager().StartSupervisor()
 	}
 
 	err = dms.RegisterAsDMSTarget(options)
@@ -159,12 +186,67 @@ func Run(options *config.SqleOptions) error {
 	// start independent pprof server on separate port
 	pprof.StartServerAsync(sqleCnf.PprofPort)
 
+	// start independent metrics server on separate port
+	metrics.StartServerAsync(sqleCnf.MetricsPort)
+
 	// Wait for exit signal from NotifySignal goroutine
 	<-exitChan
 	log.Logger().Infoln("sqled server will exit")
 	return nil
 }
 
+// createPluginRules creates the rules and the default rule template of the plugin loaded at runtime.
+func createPluginRules(s *model.Storage, meta *driverV2.DriverMetas) error {
+	pluginRules := map[string][]*driverV2.Rule{meta.PluginName: meta.Rules}
+	rules := model.MergeOptimizationRules(pluginRules)
+	if err := s.CreateRulesIfNotExist(rules); err != nil {
+		return err
+	}
+	if err := s.CreateDefaultTemplateIfNotExist(model.ProjectIdForGlobalRuleTemplate, pluginRules); err != nil {
+		return err
+	}
+	return knowledge_base.LoadKnowledge(rules)
+}
+
+func initTracing(cnf config.Tracing) error {
+	if cnf.OtlpEndpoint != "" {
+		if err := os.Setenv(tracing.EnvOtlpEndpoint, cnf.OtlpEndpoint); err != nil {
+			return err
+		}
+	}
+	if cnf.SampleRatio != "" {
+		if err := os.Setenv(tracing.EnvSamplerArg, cnf.SampleRatio); err != nil {
+			return err
+		}
+	}
+	tracingCnf, ok := tracing.ConfigFromEnv("sqled")
+	if !ok {
+		return nil
+	}
+	tracingCnf.OnError = func(err error) {
+		log.Logger().Warnf("export spans failed, error: %v", err)
+	}
+	log.Logger().Infof("export spans to %s", tracingCnf.Endpoint)
+	return tracing.Init(tracingCnf)
+}
+
+func initLocale(localePath string) error {
+	if localePath != "" {
+		if err := locale.LoadLocaleDir(localePath); err != nil {
+			return err
+		}
+		if err := mysql.LoadLocaleDir(localePath); err != nil {
+			return err
+		}
+	}
+	langs := make([]string, 0, len(locale.Bundle.LanguageTags()))
+	for _, tag := range locale.Bundle.LanguageTags() {
+		langs = append(langs, tag.String())
+	}
+	log.Logger().Infof("enabled languages: %s", strings.Join(langs, ","))
+	return os.Setenv(driverV2.EnvEnabledLanguages, strings.Join(langs, ","))
+}
+
 func validateConfig(options *config.SqleOptions) error {
 	sqleCnf := options.Service
 	if sqleCnf.EnableClusterMode {
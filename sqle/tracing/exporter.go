@@ -0,0 +1,356 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the environment variables follow the OpenTelemetry SDK, the plugin processes inherit them from sqled.
+const (
+	EnvOtlpEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvOtlpTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	EnvOtlpHeaders        = "OTEL_EXPORTER_OTLP_HEADERS"
+	EnvServiceName        = "OTEL_SERVICE_NAME"
+	EnvSamplerArg         = "OTEL_TRACES_SAMPLER_ARG"
+)
+
+const (
+	exportQueueSize     = 2048
+	exportBatchSize     = 512
+	exportInterval      = 5 * time.Second
+	exportTimeout       = 10 * time.Second
+	instrumentationName = "github.com/actiontech/sqle/sqle/tracing"
+)
+
+type Config struct {
+	// Endpoint is the url of the OTLP/HTTP traces api, such as http://127.0.0.1:4318/v1/traces
+	Endpoint    string
+	Headers     map[string]string
+	ServiceName string
+	// SampleRatio is the ratio of the sampled traces started in this process, the traces started
+	// by the remote parent follow the decision of the parent.
+	SampleRatio float64
+	// OnError is called when the spans are failed to export
+	OnError func(err error)
+}
+
+// ConfigFromEnv returns the config from the OpenTelemetry environment variables, ok is false if no endpoint is set.
+func ConfigFromEnv(defaultServiceName string) (cfg Config, ok bool) {
+	cfg = Config{ServiceName: defaultServiceName, SampleRatio: 1, Headers: map[string]string{}}
+	if endpoint := os.Getenv(EnvOtlpTracesEndpoint); endpoint != "" {
+		cfg.Endpoint = endpoint
+	} else if endpoint := os.Getenv(EnvOtlpEndpoint); endpoint != "" {
+		cfg.Endpoint = strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	} else {
+		return cfg, false
+	}
+	if name := os.Getenv(EnvServiceName); name != "" {
+		cfg.ServiceName = name
+	}
+	if arg := os.Getenv(EnvSamplerArg); arg != "" {
+		if ratio, err := strconv.ParseFloat(arg, 64); err == nil {
+			cfg.SampleRatio = ratio
+		}
+	}
+	for _, pair := range strings.Split(os.Getenv(EnvOtlpHeaders), ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) != "" {
+			cfg.Headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return cfg, true
+}
+
+var (
+	globalMutex    sync.RWMutex
+	globalExporter *exporter
+)
+
+func getExporter() *exporter {
+	globalMutex.RLock()
+	defer globalMutex.RUnlock()
+	return globalExporter
+}
+
+// Init starts exporting the spans, the spans are dropped before Init is called.
+func Init(cfg Config) error {
+	if cfg.Endpoint == "" {
+		return fmt.Errorf("otlp endpoint is empty")
+	}
+	e := &exporter{
+		cfg:    cfg,
+		client: &http.Client{Timeout: exportTimeout},
+		queue:  make(chan *Span, exportQueueSize),
+		done:   make(chan struct{}),
+	}
+	globalMutex.Lock()
+	old := globalExporter
+	globalExporter = e
+	globalMutex.Unlock()
+	if old != nil {
+		old.shutdown()
+	}
+	go e.loop()
+	return nil
+}
+
+// InitFromEnv calls Init with the config from the environment variables, it does nothing if no endpoint is set.
+func InitFromEnv(defaultServiceName string) error {
+	cfg, ok := ConfigFromEnv(defaultServiceName)
+	if !ok {
+		return nil
+	}
+	return Init(cfg)
+}
+
+// Shutdown exports the buffered spans and stops exporting.
+func Shutdown() {
+	globalMutex.Lock()
+	e := globalExporter
+	globalExporter = nil
+	globalMutex.Unlock()
+	if e != nil {
+		e.shutdown()
+	}
+}
+
+type exporter struct {
+	cfg    Config
+	client *http.Client
+	queue  chan *Span
+	done   chan struct{}
+	// closed is protected by mutex, the spans ended after shutdown are dropped
+	mutex  sync.RWMutex
+	closed bool
+}
+
+func (e *exporter) shouldSample(traceId TraceID) bool {
+	if e.cfg.SampleRatio >= 1 {
+		return true
+	}
+	if e.cfg.SampleRatio <= 0 {
+		return false
+	}
+	// the decision only depends on the trace id, so it is the same in every process
+	return binary.BigEndian.Uint64(traceId[8:]) < uint64(e.cfg.SampleRatio*math.MaxUint64)
+}
+
+func (e *exporter) export(span *Span) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.queue <- span:
+	default:
+		// drop the span rather than block the traced operation
+	}
+}
+
+func (e *exporter) loop() {
+	defer close(e.done)
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	batch := make([]*Span, 0, exportBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil && e.cfg.OnError != nil {
+			e.cfg.OnError(err)
+		}
+		batch = make([]*Span, 0, exportBatchSize)
+	}
+	for {
+		select {
+		case span, ok := <-e.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (e *exporter) shutdown() {
+	e.mutex.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.queue)
+	}
+	e.mutex.Unlock()
+	select {
+	case <-e.done:
+	case <-time.After(exportTimeout):
+	}
+}
+
+func (e *exporter) send(spans []*Span) error {
+	body, err := json.Marshal(e.buildRequest(spans))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("export spans to %s failed, status: %s", e.cfg.Endpoint, resp.Status)
+	}
+	return nil
+}
+
+// the structs below are the OTLP/HTTP JSON encoding of ExportTraceServiceRequest
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceId           string         `json:"traceId"`
+	SpanId            string         `json:"spanId"`
+	ParentSpanId      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+const (
+	otlpStatusUnset = 0
+	otlpStatusError = 2
+)
+
+func (e *exporter) buildRequest(spans []*Span) *otlpRequest {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		span.mutex.Lock()
+		s := otlpSpan{
+			TraceId:           span.spanContext.TraceID.String(),
+			SpanId:            span.spanContext.SpanID.String(),
+			Name:              span.name,
+			Kind:              int(span.kind),
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+			Attributes:        convertAttributes(span.attributes),
+			Status:            otlpStatus{Code: otlpStatusUnset},
+		}
+		if span.parentSpanID.IsValid() {
+			s.ParentSpanId = span.parentSpanID.String()
+		}
+		if span.statusError {
+			s.Status = otlpStatus{Code: otlpStatusError, Message: span.statusMessage}
+		}
+		span.mutex.Unlock()
+		otlpSpans = append(otlpSpans, s)
+	}
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: convertAttributes([]Attribute{
+				Attr("service.name", e.cfg.ServiceName),
+				Attr("process.pid", os.Getpid()),
+			})},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: instrumentationName},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+func convertAttributes(attributes []Attribute) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attributes))
+	for _, attr := range attributes {
+		value := otlpAnyValue{}
+		switch v := attr.Value.(type) {
+		case string:
+			value.StringValue = &v
+		case bool:
+			value.BoolValue = &v
+		case int:
+			s := strconv.FormatInt(int64(v), 10)
+			value.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(v, 10)
+			value.IntValue = &s
+		case uint:
+			s := strconv.FormatUint(uint64(v), 10)
+			value.IntValue = &s
+		case uint64:
+			s := strconv.FormatUint(v, 10)
+			value.IntValue = &s
+		case float64:
+			value.DoubleValue = &v
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+		kvs = append(kvs, otlpKeyValue{Key: attr.Key, Value: value})
+	}
+	return kvs
+}
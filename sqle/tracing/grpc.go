@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor starts a client span for each RPC and sends the trace context in the gRPC metadata.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := Start(ctx, method, WithSpanKind(SpanKindClient), WithAttributes(
+			Attr("rpc.system", "grpc"),
+			Attr("rpc.method", method),
+		))
+		defer span.End()
+		Inject(ctx, func(key, value string) {
+			ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+		})
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		span.RecordError(err)
+		return err
+	}
+}
+
+// UnaryServerInterceptor starts a server span for each RPC as the child of the trace context in the gRPC metadata.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = Extract(ctx, func(key string) string {
+				if values := md.Get(key); len(values) > 0 {
+					return values[0]
+				}
+				return ""
+			})
+		}
+		ctx, span := Start(ctx, info.FullMethod, WithSpanKind(SpanKindServer), WithAttributes(
+			Attr("rpc.system", "grpc"),
+			Attr("rpc.method", info.FullMethod),
+		))
+		defer span.End()
+		resp, err := handler(ctx, req)
+		span.RecordError(err)
+		return resp, err
+	}
+}
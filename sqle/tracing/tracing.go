@@ -0,0 +1,284 @@
+// Package tracing records the spans of sqled and its plugins and exports them to an OTLP collector
+// with the OTLP/HTTP JSON protocol. The trace context is propagated by the W3C `traceparent` header
+// over HTTP and gRPC metadata, so an audit can be followed from the API into the plugin process.
+//
+// It stands in for the OpenTelemetry SDK (go.opentelemetry.io/otel with the otlptracehttp exporter and
+// the otelgrpc interceptors), which can not be vendored while google.golang.org/grpc is pinned to v1.29.0.
+// Start, Span.End, Span.SetAttributes and the OTEL_* environment variables follow the SDK, so moving to
+// the SDK only replaces this package, the callers are kept.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const TraceParentHeader = "traceparent"
+
+type TraceID [16]byte
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+type SpanID [8]byte
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext identifies a span across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// TraceParent formats the span context as the W3C traceparent header value.
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceParent parses the W3C traceparent header value.
+func ParseTraceParent(value string) (SpanContext, bool) {
+	sc := SpanContext{}
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return sc, false
+	}
+	// version 00 has exactly 4 parts, the later versions may append parts
+	if parts[0] == "00" && len(parts) != 4 {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return sc, false
+	}
+	sc.Sampled = flags[0]&0x01 == 0x01
+	return sc, sc.IsValid()
+}
+
+type SpanKind int
+
+// the values are the span kinds of OTLP
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is an operation in a trace. A nil span is valid and records nothing, it is returned when
+// tracing is disabled or the trace is not sampled, so the callers need not check it.
+type Span struct {
+	mutex sync.Mutex
+
+	name          string
+	kind          SpanKind
+	spanContext   SpanContext
+	parentSpanID  SpanID
+	start         time.Time
+	end           time.Time
+	attributes    []Attribute
+	statusError   bool
+	statusMessage string
+	ended         bool
+	exporter      *exporter
+}
+
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.spanContext
+}
+
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.attributes = append(s.attributes, attributes...)
+	s.mutex.Unlock()
+}
+
+// RecordError marks the span as failed if err is not nil.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.statusError = true
+	s.statusMessage = err.Error()
+	s.mutex.Unlock()
+}
+
+// End finishes the span and sends it to the exporter, the span is sent only once.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mutex.Unlock()
+	s.exporter.export(s)
+}
+
+type spanContextKey struct{}
+
+type remoteSpanContextKey struct{}
+
+// SpanFromContext returns the current span in ctx, it is nil if there is no recording span.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteSpanContext returns a context whose spans are the children of the span in another process.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context of the current span, or the remote parent if there is no local span.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	if span := SpanFromContext(ctx); span != nil {
+		return span.spanContext
+	}
+	sc, _ := ctx.Value(remoteSpanContextKey{}).(SpanContext)
+	return sc
+}
+
+type startConfig struct {
+	kind       SpanKind
+	attributes []Attribute
+}
+
+type StartOption func(*startConfig)
+
+func WithSpanKind(kind SpanKind) StartOption {
+	return func(c *startConfig) {
+		c.kind = kind
+	}
+}
+
+func WithAttributes(attributes ...Attribute) StartOption {
+	return func(c *startConfig) {
+		c.attributes = append(c.attributes, attributes...)
+	}
+}
+
+// Start starts a span as the child of the current span in ctx and returns the context holding the new span.
+func Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e := getExporter()
+	if e == nil {
+		return ctx, nil
+	}
+	cfg := &startConfig{kind: SpanKindInternal}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{SpanID: newSpanID()}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+	} else {
+		sc.TraceID = newTraceID()
+		sc.Sampled = e.shouldSample(sc.TraceID)
+	}
+	if !sc.Sampled {
+		// keep the decision for the children and the remote processes
+		return ContextWithRemoteSpanContext(ctx, sc), nil
+	}
+
+	span := &Span{
+		name:         name,
+		kind:         cfg.kind,
+		spanContext:  sc,
+		parentSpanID: parent.SpanID,
+		start:        time.Now(),
+		attributes:   cfg.attributes,
+		exporter:     e,
+	}
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// Inject writes the trace context of ctx by `set`, such as setting a HTTP header or gRPC metadata.
+func Inject(ctx context.Context, set func(key, value string)) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	set(TraceParentHeader, sc.TraceParent())
+}
+
+// Extract reads the trace context written by Inject and returns the context holding it as the remote parent.
+func Extract(ctx context.Context, get func(key string) string) context.Context {
+	sc, ok := ParseTraceParent(get(TraceParentHeader))
+	if !ok {
+		return ctx
+	}
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
+
+func newTraceID() TraceID {
+	id := TraceID{}
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	id := SpanID{}
+	_, _ = rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,203 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	sc, ok := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.True(t, sc.Sampled)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.TraceParent())
+
+	sc, ok = ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	assert.True(t, ok)
+	assert.False(t, sc.Sampled)
+
+	// the later versions may append fields
+	_, ok = ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra")
+	assert.True(t, ok)
+
+	for _, value := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+	} {
+		_, ok := ParseTraceParent(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestStartWithoutExporter(t *testing.T) {
+	Shutdown()
+	ctx, span := Start(context.Background(), "noop")
+	assert.Nil(t, span)
+	assert.Nil(t, SpanFromContext(ctx))
+	// the nil span is safe to use
+	span.SetAttributes(Attr("k", "v"))
+	span.RecordError(errors.New("failed"))
+	span.End()
+}
+
+type collector struct {
+	sync.Mutex
+	requests []otlpRequest
+	headers  []http.Header
+}
+
+func (c *collector) spans() []otlpSpan {
+	c.Lock()
+	defer c.Unlock()
+	spans := []otlpSpan{}
+	for _, req := range c.requests {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}
+	return spans
+}
+
+func newCollector(t *testing.T) (*collector, *httptest.Server) {
+	c := &collector{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		req := otlpRequest{}
+		assert.NoError(t, json.Unmarshal(body, &req))
+		c.Lock()
+		c.requests = append(c.requests, req)
+		c.headers = append(c.headers, r.Header.Clone())
+		c.Unlock()
+	}))
+	return c, server
+}
+
+func TestExportSpans(t *testing.T) {
+	c, server := newCollector(t)
+	defer server.Close()
+	assert.NoError(t, Init(Config{
+		Endpoint:    server.URL + "/v1/traces",
+		Headers:     map[string]string{"Authorization": "token"},
+		ServiceName: "sqled",
+		SampleRatio: 1,
+	}))
+
+	ctx, parent := Start(context.Background(), "parent", WithSpanKind(SpanKindServer))
+	_, child := Start(ctx, "child", WithAttributes(Attr("task_id", uint(1)), Attr("rule", "ddl_check_pk_not_exist")))
+	child.RecordError(errors.New("rule failed"))
+	child.End()
+	parent.End()
+	Shutdown()
+
+	spans := c.spans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, parent.SpanContext().TraceID.String(), spans[0].TraceId)
+	assert.Equal(t, parent.SpanContext().SpanID.String(), spans[0].ParentSpanId)
+	assert.Equal(t, otlpStatusError, spans[0].Status.Code)
+	assert.Equal(t, "rule failed", spans[0].Status.Message)
+	assert.Equal(t, "1", *spans[0].Attributes[0].Value.IntValue)
+	assert.Equal(t, "parent", spans[1].Name)
+	assert.Equal(t, int(SpanKindServer), spans[1].Kind)
+	assert.Empty(t, spans[1].ParentSpanId)
+	assert.Equal(t, "token", c.headers[0].Get("Authorization"))
+	assert.Equal(t, "sqled", *c.requests[0].ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+
+	// the spans ended after shutdown are dropped
+	_, span := Start(context.Background(), "after shutdown")
+	assert.Nil(t, span)
+}
+
+func TestSampling(t *testing.T) {
+	c, server := newCollector(t)
+	defer server.Close()
+	assert.NoError(t, Init(Config{Endpoint: server.URL, SampleRatio: 0}))
+
+	ctx, span := Start(context.Background(), "unsampled")
+	assert.Nil(t, span)
+	// the unsampled decision is kept for the children and the remote processes
+	sc := SpanContextFromContext(ctx)
+	assert.True(t, sc.IsValid())
+	assert.False(t, sc.Sampled)
+	_, span = Start(ctx, "child")
+	assert.Nil(t, span)
+
+	// the sampled remote parent is followed even if the ratio is 0
+	remote, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, span = Start(ContextWithRemoteSpanContext(context.Background(), remote), "remote child")
+	assert.NotNil(t, span)
+	span.End()
+	Shutdown()
+
+	spans := c.spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceId)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanId)
+}
+
+func TestPropagation(t *testing.T) {
+	_, server := newCollector(t)
+	defer server.Close()
+	assert.NoError(t, Init(Config{Endpoint: server.URL, SampleRatio: 1}))
+	defer Shutdown()
+
+	ctx, span := Start(context.Background(), "client")
+	defer span.End()
+	header := http.Header{}
+	Inject(ctx, header.Set)
+	assert.Equal(t, span.SpanContext().TraceParent(), header.Get(TraceParentHeader))
+
+	remote := Extract(context.Background(), header.Get)
+	assert.Equal(t, span.SpanContext(), SpanContextFromContext(remote))
+
+	// the context without trace context is not changed
+	empty := Extract(context.Background(), http.Header{}.Get)
+	assert.False(t, SpanContextFromContext(empty).IsValid())
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	detached := context.WithoutCancel(cancelCtx)
+	assert.NoError(t, detached.Err())
+	assert.Equal(t, span, SpanFromContext(detached))
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvOtlpEndpoint, "http://127.0.0.1:4318/")
+	t.Setenv(EnvOtlpHeaders, "a=1, b = 2,invalid")
+	t.Setenv(EnvSamplerArg, "0.25")
+	cfg, ok := ConfigFromEnv("sqled")
+	assert.True(t, ok)
+	assert.Equal(t, "http://127.0.0.1:4318/v1/traces", cfg.Endpoint)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, cfg.Headers)
+	assert.Equal(t, 0.25, cfg.SampleRatio)
+	assert.Equal(t, "sqled", cfg.ServiceName)
+
+	t.Setenv(EnvOtlpTracesEndpoint, "http://collector/traces")
+	t.Setenv(EnvServiceName, "mysql-plugin")
+	cfg, ok = ConfigFromEnv("sqled")
+	assert.True(t, ok)
+	assert.Equal(t, "http://collector/traces", cfg.Endpoint)
+	assert.Equal(t, "mysql-plugin", cfg.ServiceName)
+
+	t.Setenv(EnvOtlpEndpoint, "")
+	t.Setenv(EnvOtlpTracesEndpoint, "")
+	_, ok = ConfigFromEnv("sqled")
+	assert.False(t, ok)
+}